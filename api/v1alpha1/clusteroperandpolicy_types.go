@@ -0,0 +1,142 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1alpha1
+
+import (
+	"path"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// ClusterOperandPolicySpec defines the restrictions of the OperandRequests in the cluster.
+type ClusterOperandPolicySpec struct {
+	// OperatorRules restrict the namespaces which may request the operators.
+	// An operator without a rule can be requested from any namespace.
+	// When several rules match an operator, a namespace allowed by any of them may request it.
+	// +optional
+	OperatorRules []OperatorRule `json:"operatorRules,omitempty"`
+	// MaxOperandsPerNamespace is the maximum number of distinct operands requested
+	// by all the OperandRequests of a namespace.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxOperandsPerNamespace *int32 `json:"maxOperandsPerNamespace,omitempty"`
+	// DenyChannelDowngrade keeps the Subscriptions of the operators
+	// in their channel when the OperandRegistry sets a lower one.
+	// +optional
+	DenyChannelDowngrade bool `json:"denyChannelDowngrade,omitempty"`
+	// MaxMinorVersionJump keeps the Subscriptions of the operators in their channel when the OperandRegistry
+	// sets a channel more than this number of minor versions higher, like 1 allows v3.20 to v3.21 but not v3.22.
+	// The next major version is a jump of its minor version plus one, like v4.0 is a jump of 1 from v3.20,
+	// and the major versions after it are denied.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxMinorVersionJump *int32 `json:"maxMinorVersionJump,omitempty"`
+}
+
+// OperatorRule is the namespaces allowed to request a list of operators.
+type OperatorRule struct {
+	// Operators are the names of the operators in the OperandRegistries, `*` matches all the operators.
+	Operators []string `json:"operators"`
+	// Namespaces are the names of the namespaces allowed to request the operators,
+	// they can be shell patterns like `team-*`.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// NamespaceSelector selects the namespaces allowed to request the operators by their labels.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterOperandPolicy is the Schema for the clusteroperandpolicies API.
+// It restricts the operators which can be requested from the namespaces, the number of operands per namespace,
+// the channel downgrades and the channel jumps. All the ClusterOperandPolicies in the cluster apply.
+// +kubebuilder:resource:path=clusteroperandpolicies,shortName=opolicy,scope=Cluster
+// +kubebuilder:printcolumn:name="Max Operands",type=integer,JSONPath=.spec.maxOperandsPerNamespace,description="Maximum number of operands per namespace"
+// +kubebuilder:printcolumn:name="Deny Downgrade",type=boolean,JSONPath=.spec.denyChannelDowngrade,description="Channel downgrades are denied"
+// +kubebuilder:printcolumn:name="Created At",type=string,JSONPath=.metadata.creationTimestamp
+// +operator-sdk:csv:customresourcedefinitions:displayName="ClusterOperandPolicy"
+type ClusterOperandPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterOperandPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterOperandPolicyList contains a list of ClusterOperandPolicy.
+type ClusterOperandPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterOperandPolicy `json:"items"`
+}
+
+// Matches returns true if the rule applies to the operator.
+func (r *OperatorRule) Matches(operator string) bool {
+	for _, name := range r.Operators {
+		if name == "*" || name == operator {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows returns true if the namespace with the labels may request the operators of the rule.
+func (r *OperatorRule) Allows(namespace string, namespaceLabels map[string]string) (bool, error) {
+	for _, pattern := range r.Namespaces {
+		matched, err := path.Match(pattern, namespace)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	if r.NamespaceSelector == nil {
+		return false, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(r.NamespaceSelector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(namespaceLabels)), nil
+}
+
+// AllowsOperator returns true if the namespace with the labels may request the operator.
+func (p *ClusterOperandPolicy) AllowsOperator(operator, namespace string, namespaceLabels map[string]string) (bool, error) {
+	restricted := false
+	for i := range p.Spec.OperatorRules {
+		rule := &p.Spec.OperatorRules[i]
+		if !rule.Matches(operator) {
+			continue
+		}
+		restricted = true
+		allowed, err := rule.Allows(namespace, namespaceLabels)
+		if err != nil || allowed {
+			return allowed, err
+		}
+	}
+	return !restricted, nil
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterOperandPolicy{}, &ClusterOperandPolicyList{})
+}
@@ -19,13 +19,16 @@ package v1alpha1
 import (
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 )
 
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // BindInfoPhase defines the BindInfo status.
+// +kubebuilder:validation:Enum=Completed;Failed;Initialized;Updating;"Waiting for Secret and/or Configmap from provider"
 type BindInfoPhase string
 
 // BindInfo status
@@ -57,6 +60,11 @@ type OperandBindInfoSpec struct {
 	// The bindings section is used to specify information about the access/configuration data that is to be shared.
 	// +optional
 	Bindings map[string]SecretConfigmap `json:"bindings,omitempty"`
+	// AllowedNamespaces selects the namespaces the Secrets and ConfigMaps may be copied to by their labels.
+	// The OperandRequests in the other namespaces don't get the bindings.
+	// The default is all the namespaces.
+	// +optional
+	AllowedNamespaces *metav1.LabelSelector `json:"allowedNamespaces,omitempty"`
 }
 
 // SecretConfigmap is a pair of Secret and/or Configmap.
@@ -78,6 +86,10 @@ type OperandBindInfoStatus struct {
 	// RequestNamespaces defines the namespaces of OperandRequest.
 	// +optional
 	RequestNamespaces []string `json:"requestNamespaces,omitempty"`
+	// Conditions represents the current state of the OperandBindInfo.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions",xDescriptors="urn:alm:descriptor:io.kubernetes.conditions"
+	Conditions []Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -125,6 +137,19 @@ func (r *OperandBindInfo) GetRegistryKey() types.NamespacedName {
 	return types.NamespacedName{Namespace: r.Namespace, Name: r.Spec.Registry}
 }
 
+// AllowsNamespace returns true if the Secrets and ConfigMaps may be copied to the namespace with the labels.
+// The namespace of the OperandBindInfo is always allowed.
+func (r *OperandBindInfo) AllowsNamespace(namespace string, namespaceLabels map[string]string) (bool, error) {
+	if r.Spec.AllowedNamespaces == nil || namespace == r.Namespace {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(r.Spec.AllowedNamespaces)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(namespaceLabels)), nil
+}
+
 // GenerateLabels generates the labels for the OperandBindInfo to include information about the OperandRegistry it uses.
 func (r *OperandBindInfo) GenerateLabels() map[string]string {
 	labels := make(map[string]string)
@@ -161,6 +186,29 @@ func (r *OperandBindInfo) UpdateLabels() bool {
 	return isUpdated
 }
 
+// SetPropagateConflictCondition creates a PropagateConflict condition for the copy of a Secret or a ConfigMap
+// which can't be updated because of another writer.
+func (r *OperandBindInfo) SetPropagateConflictCondition(name, manager string, cs corev1.ConditionStatus) {
+	c := newCondition(ConditionPropagateConflict, cs, name+" conflicts", name+" can't be updated, it conflicts with the field manager "+manager)
+	// Keep the condition unchanged while the conflict is the same
+	if _, cp := getCondition(&r.Status.Conditions, c.Type, c.Message); cp != nil && cp.Status == cs {
+		return
+	}
+	r.RemovePropagateConflictCondition(name)
+	r.Status.Conditions = append(r.Status.Conditions, *c)
+}
+
+// RemovePropagateConflictCondition removes the PropagateConflict condition of the copy of a Secret or a ConfigMap.
+func (r *OperandBindInfo) RemovePropagateConflictCondition(name string) {
+	var conds []Condition
+	for _, c := range r.Status.Conditions {
+		if c.Type != ConditionPropagateConflict || c.Reason != name+" conflicts" {
+			conds = append(conds, c)
+		}
+	}
+	r.Status.Conditions = conds
+}
+
 // RemoveFinalizer removes the operator source finalizer from the
 // OperatorSource ObjectMeta.
 func (r *OperandBindInfo) RemoveFinalizer() bool {
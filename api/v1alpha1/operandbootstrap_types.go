@@ -0,0 +1,111 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// OperandBootstrapSpec defines the install order of the operators of the OperandRegistry
+// with the same name and namespace.
+type OperandBootstrapSpec struct {
+	// Steps are the operators which wait for other operators of the OperandRegistry before they are installed.
+	// The operators without a step are installed as soon as they are requested.
+	// +listType=map
+	// +listMapKey=operator
+	// +optional
+	Steps []BootstrapStep `json:"steps,omitempty"`
+}
+
+// BootstrapStep is an operator and the operators it is installed after.
+type BootstrapStep struct {
+	// Operator is the name of the operator in the OperandRegistry.
+	Operator string `json:"operator"`
+	// After are the operators which must be ready before the operator is installed.
+	After []BootstrapDependency `json:"after"`
+}
+
+// BootstrapDependency is an operator which must be ready before another one is installed.
+type BootstrapDependency struct {
+	// Operator is the name of the operator in the OperandRegistry.
+	Operator string `json:"operator"`
+	// Condition is when the operator is ready, OperatorReady once its operator is running,
+	// OperandReady once its operand is running as well. The default is OperandReady.
+	// +optional
+	Condition BootstrapCondition `json:"condition,omitempty"`
+	// Optional skips the dependency when no OperandRequest of the OperandRegistry requests the operator.
+	// +optional
+	Optional bool `json:"optional,omitempty"`
+}
+
+// BootstrapCondition is when a dependency of a BootstrapStep is ready.
+// +kubebuilder:validation:Enum=OperatorReady;OperandReady
+type BootstrapCondition string
+
+// The conditions of the BootstrapDependencies.
+const (
+	BootstrapOperatorReady BootstrapCondition = "OperatorReady"
+	BootstrapOperandReady  BootstrapCondition = "OperandReady"
+)
+
+// +kubebuilder:object:root=true
+
+// OperandBootstrap is the Schema for the operandbootstraps API.
+// It orders the first install of the operators of the OperandRegistry with the same name and namespace,
+// an operator is installed once the operators it depends on are ready.
+// +kubebuilder:resource:path=operandbootstraps,shortName=opboot,scope=Namespaced
+// +kubebuilder:printcolumn:name="Created At",type=string,JSONPath=.metadata.creationTimestamp
+// +operator-sdk:csv:customresourcedefinitions:displayName="OperandBootstrap"
+type OperandBootstrap struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec OperandBootstrapSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperandBootstrapList contains a list of OperandBootstrap.
+type OperandBootstrapList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperandBootstrap `json:"items"`
+}
+
+// GetStep returns the step of the operator, nil if it has none.
+func (b *OperandBootstrap) GetStep(operator string) *BootstrapStep {
+	for i := range b.Spec.Steps {
+		if b.Spec.Steps[i].Operator == operator {
+			return &b.Spec.Steps[i]
+		}
+	}
+	return nil
+}
+
+// GetCondition returns the condition of the dependency with its default.
+func (d *BootstrapDependency) GetCondition() BootstrapCondition {
+	if d.Condition == "" {
+		return BootstrapOperandReady
+	}
+	return d.Condition
+}
+
+func init() {
+	SchemeBuilder.Register(&OperandBootstrap{}, &OperandBootstrapList{})
+}
@@ -17,6 +17,9 @@
 package v1alpha1
 
 import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -42,6 +45,360 @@ type ConfigService struct {
 	// Resources is used to specify the kubernetes resources that are needed for the service.
 	// +optional
 	Resources []ConfigResource `json:"resources,omitempty"`
+	// Prune is used to determine whether the fields removed from the spec are also removed from the custom resources.
+	// The default is false, the removed fields are kept in the custom resources.
+	// +optional
+	Prune *bool `json:"prune,omitempty"`
+	// Remediation is the policy for the changes made to the custom resources which conflict with the configuration.
+	// Enforce reverts them, Detect keeps them and reports them with a Drifted condition and an event,
+	// and Ignore keeps them. The default is Enforce.
+	// +kubebuilder:validation:Enum=Enforce;Detect;Ignore
+	// +optional
+	Remediation string `json:"remediation,omitempty"`
+	// Values are the values of the Helm release, for the operator installed by Helm.
+	// They are merged into the default values in the OperandRegistry.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Values *runtime.RawExtension `json:"values,omitempty"`
+	// ZoneAware renders the zone-aware settings of the service from the zones detected in the cluster,
+	// so that the service survives a single zone failure.
+	// +optional
+	ZoneAware *ZoneAwareness `json:"zoneAware,omitempty"`
+	// HealthCheck decides when the service is healthy, for the operators whose custom resources
+	// don't report a reliable status.
+	// +optional
+	HealthCheck *HealthCheck `json:"healthCheck,omitempty"`
+	// Backup declares how the backup tooling like Velero and OADP includes the service.
+	// +optional
+	Backup *BackupPolicy `json:"backup,omitempty"`
+	// Proxy renders the proxy and the CA bundle of the cluster into the custom resources of the service,
+	// for the operands which need them in their own spec.
+	// +optional
+	Proxy *ProxyInjection `json:"proxy,omitempty"`
+	// Placement schedules the pods of the service with a placement profile of the OperatorConfig.
+	// +optional
+	Placement *PlacementInjection `json:"placement,omitempty"`
+	// SizeProfiles are the named sizes of the service, like small, medium and large, declared from the smallest
+	// to the largest. The OperandRequests select one with the SizeProfile of the operand.
+	// +optional
+	SizeProfiles []SizeProfile `json:"sizeProfiles,omitempty"`
+	// DefaultSizeProfile is the size profile of the service when no OperandRequest selects one.
+	// The spec of the service is used unchanged when it is not set.
+	// +optional
+	DefaultSizeProfile string `json:"defaultSizeProfile,omitempty"`
+	// Scaling declares where the replicas requested by the OperandRequests are rendered in the custom resources
+	// of the service, so the consumers scale the service without knowing the schemas of its custom resources.
+	// +optional
+	Scaling *ScalingHints `json:"scaling,omitempty"`
+	// PreDelete declares the hooks ODLM runs and waits for before it deletes the custom resources of the service,
+	// so the services holding data, like databases and message queues, drain or deprovision it first.
+	// +optional
+	PreDelete *PreDeleteHooks `json:"preDelete,omitempty"`
+	// PostInstall declares the hooks ODLM runs after the custom resources of the service are ready,
+	// the operand only turns Running after they succeed.
+	// +optional
+	PostInstall *PostInstallHooks `json:"postInstall,omitempty"`
+}
+
+// PostInstallHooks defines the hooks verifying a service after its custom resources are ready.
+type PostInstallHooks struct {
+	// Job is the spec of a Job ODLM creates in the namespace of the service, like a smoke test.
+	// The Job is created again when its spec changes.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Job *runtime.RawExtension `json:"job,omitempty"`
+	// HTTP is an HTTP endpoint of the service ODLM calls, it succeeds when the endpoint answers with the expected status.
+	// +optional
+	HTTP *HTTPCheck `json:"http,omitempty"`
+}
+
+// HTTPCheck defines an HTTP GET call verifying a service.
+type HTTPCheck struct {
+	// URL is the URL ODLM calls, like https://my-service.my-namespace.svc:8443/healthz.
+	URL string `json:"url"`
+	// ExpectedStatus is the status code of a successful call. Any 2xx status succeeds when it is not set.
+	// +optional
+	ExpectedStatus int32 `json:"expectedStatus,omitempty"`
+	// Timeout is the timeout of the call. The default is 10s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// InsecureSkipTLSVerify skips the verification of the serving certificate of the endpoint.
+	// +optional
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+}
+
+// PreDeleteHooks defines the hooks run before the custom resources of a service are deleted.
+type PreDeleteHooks struct {
+	// Job is the spec of a Job ODLM creates in the namespace of the service, it waits for the Job to succeed.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Job *runtime.RawExtension `json:"job,omitempty"`
+	// CustomResources are the fields ODLM sets in the custom resources of the service,
+	// to tell their operator to drain them.
+	// +optional
+	CustomResources []PreDeleteResource `json:"customResources,omitempty"`
+	// Done is a CEL expression which returns true when the custom resources of the service are drained.
+	// The custom resources are in the list crs, like crs.all(cr, has(cr.status.drained) && cr.status.drained).
+	// +optional
+	Done string `json:"done,omitempty"`
+	// Timeout is how long ODLM waits for the hooks. The default is 10m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// OnError is what ODLM does when a hook fails or times out, Fail keeps the custom resources
+	// until the hook succeeds and Continue deletes them anyway. The default is Fail.
+	// +kubebuilder:validation:Enum=Continue;Fail
+	// +optional
+	OnError string `json:"onError,omitempty"`
+}
+
+// PreDeleteResource defines the fields set in a custom resource before it is deleted.
+type PreDeleteResource struct {
+	// Kind is the kind of the custom resource.
+	Kind string `json:"kind"`
+	// Spec is merged into the spec of the custom resource, like {"drain": true}.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Spec *runtime.RawExtension `json:"spec"`
+}
+
+// ScalingHints defines the paths of the replicas in the custom resources of a service and their range.
+type ScalingHints struct {
+	// MinReplicas is the lowest number of replicas the OperandRequests can request.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// MaxReplicas is the highest number of replicas the OperandRequests can request. The default is no limit.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+	// CustomResources are the custom resources of the service with the paths of their replicas.
+	CustomResources []ScalingResource `json:"customResources"`
+}
+
+// ScalingResource defines where the replicas are rendered in a custom resource.
+type ScalingResource struct {
+	// Kind is the kind of the custom resource.
+	Kind string `json:"kind"`
+	// ReplicasPath is the dot-separated path of the replicas in the spec of the custom resource, like replicas or server.size.
+	ReplicasPath string `json:"replicasPath"`
+}
+
+// SizeProfile defines a named size of a service.
+type SizeProfile struct {
+	// Name is the name of the size profile, like small, medium or large.
+	Name string `json:"name"`
+	// Spec is the map of the sizing settings of the custom resources, like their resources and replicas,
+	// keyed by the kind of the custom resources. They are merged into the spec of the service and take precedence.
+	// +optional
+	Spec map[string]runtime.RawExtension `json:"spec,omitempty"`
+}
+
+// PlacementInjection defines the placement profile of a service and where it is rendered in the custom resources.
+type PlacementInjection struct {
+	// Profile is the name of the placement profile of the OperatorConfig.
+	Profile string `json:"profile"`
+	// CustomResources are the custom resources of the service to render the placement profile into.
+	// The pod templates of the Deployments, StatefulSets, ReplicaSets, DaemonSets and Jobs in the resources
+	// of the service are always rendered.
+	// +optional
+	CustomResources []PlacementResource `json:"customResources,omitempty"`
+}
+
+// PlacementResource defines where the placement profile is rendered in a custom resource.
+// The paths are dot-separated paths in the spec of the custom resource.
+type PlacementResource struct {
+	// Kind is the kind of the custom resource.
+	Kind string `json:"kind"`
+	// NodeSelectorPath is the path of the node selector of the pods.
+	// +optional
+	NodeSelectorPath string `json:"nodeSelectorPath,omitempty"`
+	// TolerationsPath is the path of the tolerations of the pods.
+	// +optional
+	TolerationsPath string `json:"tolerationsPath,omitempty"`
+	// AffinityPath is the path of the affinity of the pods.
+	// +optional
+	AffinityPath string `json:"affinityPath,omitempty"`
+}
+
+// BackupPolicy declares the labels and the hooks of the backup tooling like Velero and OADP for a service.
+type BackupPolicy struct {
+	// Labels are added to the custom resources of the service and to their namespace,
+	// so the Backups selecting the resources by labels include the service.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// PreHook is the command Velero runs in the pods before they are backed up.
+	// +optional
+	PreHook *BackupHook `json:"preHook,omitempty"`
+	// PostHook is the command Velero runs in the pods after they are backed up.
+	// +optional
+	PostHook *BackupHook `json:"postHook,omitempty"`
+}
+
+// BackupHook defines a command Velero runs in a container of the pods of a service during a backup.
+// It is added to the custom resources of the service as the Velero hook annotations.
+type BackupHook struct {
+	// Container is the container the command runs in. The default is the first container of the pod.
+	// +optional
+	Container string `json:"container,omitempty"`
+	// Command is the command and its arguments.
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command"`
+	// OnError is what Velero does when the command fails, Fail stops the backup of the pod.
+	// The default is Fail.
+	// +kubebuilder:validation:Enum=Continue;Fail
+	// +optional
+	OnError string `json:"onError,omitempty"`
+	// Timeout is how long Velero waits for the command. The default is 30s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// ProxyInjection defines where the proxy and the CA bundle of the cluster are rendered in the custom resources of a service.
+type ProxyInjection struct {
+	// CABundleConfigMap is the ConfigMap, in the namespace of the service, holding the PEM CA bundle in its ca-bundle.crt key.
+	// The default is the ConfigMap odlm-trusted-ca-bundle, created by ODLM for OpenShift to inject the trusted CA bundle of the cluster.
+	// +optional
+	CABundleConfigMap string `json:"caBundleConfigMap,omitempty"`
+	// CustomResources are the custom resources of the service to render the proxy and the CA bundle into.
+	CustomResources []ProxyResource `json:"customResources"`
+}
+
+// ProxyResource defines where the proxy and the CA bundle are rendered in a custom resource.
+// The paths are dot-separated paths in the spec of the custom resource.
+type ProxyResource struct {
+	// Kind is the kind of the custom resource.
+	Kind string `json:"kind"`
+	// HTTPProxyPath is the path of the HTTP proxy.
+	// +optional
+	HTTPProxyPath string `json:"httpProxyPath,omitempty"`
+	// HTTPSProxyPath is the path of the HTTPS proxy.
+	// +optional
+	HTTPSProxyPath string `json:"httpsProxyPath,omitempty"`
+	// NoProxyPath is the path of the comma-separated hosts bypassing the proxy.
+	// +optional
+	NoProxyPath string `json:"noProxyPath,omitempty"`
+	// CABundlePath is the path of the PEM CA bundle.
+	// +optional
+	CABundlePath string `json:"caBundlePath,omitempty"`
+	// CABundleConfigMapPath is the path of the name of the ConfigMap holding the CA bundle.
+	// +optional
+	CABundleConfigMapPath string `json:"caBundleConfigMapPath,omitempty"`
+}
+
+// HealthCheck defines the health of a service with a CEL expression.
+type HealthCheck struct {
+	// Expression is a CEL expression which returns true when the service is healthy.
+	// The custom resources of the service are in the list crs, and the related objects
+	// are in the map objects by their name, like objects.deployments.
+	Expression string `json:"expression"`
+	// Objects are the related objects of the service, like its Deployments and StatefulSets.
+	// +optional
+	Objects []HealthCheckObjects `json:"objects,omitempty"`
+}
+
+// HealthCheckObjects selects the related objects of a service by their labels.
+type HealthCheckObjects struct {
+	// Name is the key of the objects in the map objects of the expression.
+	Name string `json:"name"`
+	// APIVersion is the apiVersion of the objects.
+	APIVersion string `json:"apiVersion"`
+	// Kind is the kind of the objects.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the objects. The default is the namespace of the custom resources.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Selector is the label selector of the objects. All the objects of the kind in the namespace are selected when it is empty.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// ZoneAwareness defines how the service is spread across the zones of the cluster.
+type ZoneAwareness struct {
+	// MaxSkew is the maxSkew of the topologySpreadConstraints. The default is 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxSkew int32 `json:"maxSkew,omitempty"`
+	// WhenUnsatisfiable is how the scheduler deals with a pod that doesn't satisfy the spread constraint.
+	// The default is ScheduleAnyway.
+	// +kubebuilder:validation:Enum=DoNotSchedule;ScheduleAnyway
+	// +optional
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty"`
+	// StorageClass is the name of a StorageClass. ODLM creates a copy of it pinned to each zone, named <storageClass>-<zone>.
+	// +optional
+	StorageClass string `json:"storageClass,omitempty"`
+	// CustomResources are the custom resources of the service to render the zone-aware settings into.
+	// The Deployments, StatefulSets and ReplicaSets in the resources of the service are always rendered.
+	// +optional
+	CustomResources []ZoneAwareResource `json:"customResources,omitempty"`
+}
+
+// ZoneAwareResource defines where the zone-aware settings are rendered in a custom resource.
+type ZoneAwareResource struct {
+	// Kind is the kind of the custom resource.
+	Kind string `json:"kind"`
+	// TopologySpreadConstraintsPath is the dot-separated path, in the spec of the custom resource,
+	// of the topologySpreadConstraints of its pods.
+	// +optional
+	TopologySpreadConstraintsPath string `json:"topologySpreadConstraintsPath,omitempty"`
+	// PodLabels are the labels of the pods of the custom resource, they are used as the label selector of the spread constraint.
+	// +optional
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+	// StorageClassesPath is the dot-separated path, in the spec of the custom resource,
+	// of the list of the zone-pinned storage classes, in the order of the zones.
+	// +optional
+	StorageClassesPath string `json:"storageClassesPath,omitempty"`
+}
+
+// IsPruneEnabled returns true if the fields removed from the service spec should be pruned from the custom resources.
+// The prune is opt-in, so the OperandConfigs created before it keep the fields they removed in the custom resources.
+func (s *ConfigService) IsPruneEnabled() bool {
+	return s.Prune != nil && *s.Prune
+}
+
+// GetSizeProfile returns the size profile with the name, it is nil if the service has no such profile
+func (s *ConfigService) GetSizeProfile(name string) *SizeProfile {
+	for i := range s.SizeProfiles {
+		if s.SizeProfiles[i].Name == name {
+			return &s.SizeProfiles[i]
+		}
+	}
+	return nil
+}
+
+// GetRemediation returns the remediation policy of the drifted custom resources of the service
+func (s *ConfigService) GetRemediation() string {
+	if s.Remediation == "" {
+		return RemediationEnforce
+	}
+	return s.Remediation
+}
+
+// GetTimeout returns how long ODLM waits for the pre-delete hooks
+func (h *PreDeleteHooks) GetTimeout() time.Duration {
+	if h.Timeout == nil || h.Timeout.Duration <= 0 {
+		return DefaultPreDeleteTimeout
+	}
+	return h.Timeout.Duration
+}
+
+// ContinuesOnError returns true if the custom resources are deleted when a pre-delete hook fails
+func (h *PreDeleteHooks) ContinuesOnError() bool {
+	return h.OnError == PreDeleteContinue
+}
+
+// GetMaxSkew returns the maxSkew of the topologySpreadConstraints
+func (z *ZoneAwareness) GetMaxSkew() int32 {
+	if z.MaxSkew < 1 {
+		return 1
+	}
+	return z.MaxSkew
+}
+
+// GetWhenUnsatisfiable returns the action of the topologySpreadConstraints for a pod that doesn't satisfy it
+func (z *ZoneAwareness) GetWhenUnsatisfiable() corev1.UnsatisfiableConstraintAction {
+	if z.WhenUnsatisfiable == "" {
+		return corev1.ScheduleAnyway
+	}
+	return z.WhenUnsatisfiable
 }
 
 // ConfigResource defines the resource needed for the service
@@ -81,6 +438,66 @@ type OperandConfigStatus struct {
 	// ServiceStatus defines all the status of a operator.
 	// +optional
 	ServiceStatus map[string]CrStatus `json:"serviceStatus,omitempty"`
+	// Scorecard is the result of the best practice checks of the services, for the platform governance.
+	// +optional
+	Scorecard *Scorecard `json:"scorecard,omitempty"`
+	// Deployments summarize where the custom resources of the services are rendered, by the service name.
+	// +optional
+	Deployments map[string]ServiceDeployment `json:"deployments,omitempty"`
+}
+
+// ServiceDeployment summarizes the custom resources rendered from the template of a service.
+type ServiceDeployment struct {
+	// Namespaces are the namespaces the custom resources and the k8s resources of the service are rendered into.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// SpecHash is the short hash of the custom resource specs rendered from the template of the service.
+	// +optional
+	SpecHash string `json:"specHash,omitempty"`
+	// InSync is true when the spec of every live custom resource of the service matches its rendered spec.
+	InSync bool `json:"inSync"`
+	// Drifted are the custom resources whose spec doesn't match the rendered spec, as <kind> <namespace>/<name>.
+	// +optional
+	Drifted []string `json:"drifted,omitempty"`
+}
+
+// Scorecard is the result of the best practice checks of the services of an OperandConfig.
+type Scorecard struct {
+	// Score is the percentage of the checks passed by all the services, from 0 to 100.
+	Score int `json:"score"`
+	// Services are the scores and the findings of the services.
+	// +optional
+	Services []ServiceScorecard `json:"services,omitempty"`
+}
+
+// ServiceScorecard is the result of the best practice checks of a service.
+type ServiceScorecard struct {
+	// Name of the service.
+	Name string `json:"name"`
+	// Score is the percentage of the checks passed by the service, from 0 to 100.
+	Score int `json:"score"`
+	// Passed are the checks the service passed.
+	// +optional
+	Passed []string `json:"passed,omitempty"`
+	// Failed are the checks the service failed.
+	// +optional
+	Failed []string `json:"failed,omitempty"`
+	// Findings are the violations of the checks the service failed.
+	// +optional
+	Findings []ScorecardFinding `json:"findings,omitempty"`
+	// OmittedFindings is the number of the findings left out of the status, to keep it small.
+	// +optional
+	OmittedFindings int `json:"omittedFindings,omitempty"`
+}
+
+// ScorecardFinding is a violation of a best practice check.
+type ScorecardFinding struct {
+	// Check is the failed check, one of ResourceLimits, Probes, PlaintextSecrets and Schema.
+	Check string `json:"check"`
+	// Path is the path of the field in the OperandConfig.
+	Path string `json:"path"`
+	// Message describes the violation.
+	Message string `json:"message"`
 }
 
 // CrStatus defines the status of the custom resource.
@@ -92,9 +509,11 @@ type CrStatus struct {
 // OperandConfig is the Schema for the operandconfigs API.
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:resource:path=operandconfigs,shortName=opcon,scope=Namespaced
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp
 // +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=.status.phase,description="Current Phase"
+// +kubebuilder:printcolumn:name="Score",type=integer,JSONPath=.status.scorecard.score,description="Best Practice Score"
 // +kubebuilder:printcolumn:name="Created At",type=string,JSONPath=.metadata.creationTimestamp
 // +operator-sdk:csv:customresourcedefinitions:displayName="OperandConfig"
 type OperandConfig struct {
@@ -115,6 +534,7 @@ type OperandConfigList struct {
 }
 
 // ServicePhase defines the service status.
+// +kubebuilder:validation:Enum=Running;Failed;Initialized;Creating;"Not Found"
 type ServicePhase string
 
 // Service status.
@@ -129,6 +549,16 @@ const (
 	ServiceCreating ServicePhase = "Creating"
 	ServiceNotFound ServicePhase = "Not Found"
 	ServiceNone     ServicePhase = ""
+
+	RemediationEnforce = "Enforce"
+	RemediationDetect  = "Detect"
+	RemediationIgnore  = "Ignore"
+
+	PreDeleteContinue = "Continue"
+	PreDeleteFail     = "Fail"
+
+	// DefaultPreDeleteTimeout is how long ODLM waits for the pre-delete hooks of a service by default
+	DefaultPreDeleteTimeout = 10 * time.Minute
 )
 
 // GetService obtains the service definition with the operand name.
@@ -0,0 +1,167 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+const (
+	// FleetStatusName is the name of the OperandFleetStatus kept updated by ODLM.
+	FleetStatusName = "odlm-fleet"
+
+	// DefaultMaxFleetItems is the default maximum number of failing members and pending upgrades listed in the status.
+	DefaultMaxFleetItems = 100
+
+	// FleetPhaseUnknown counts the resources without a phase.
+	FleetPhaseUnknown = "Unknown"
+)
+
+// OperandFleetStatusSpec defines the desired state of OperandFleetStatus.
+type OperandFleetStatusSpec struct {
+	// MaxItems is the maximum number of failing members and pending upgrades listed in the status.
+	// The counts are always complete. The default is 100.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxItems *int32 `json:"maxItems,omitempty"`
+}
+
+// FleetResourceSummary is the rollup of a kind of ODLM resources.
+type FleetResourceSummary struct {
+	// Total is the number of resources.
+	Total int32 `json:"total"`
+	// Phases is the number of resources by phase.
+	// +optional
+	Phases map[string]int32 `json:"phases,omitempty"`
+}
+
+// FleetFailingMember is a failed operator or operand of an OperandRequest.
+type FleetFailingMember struct {
+	// Request is the namespaced name of the OperandRequest.
+	Request string `json:"request"`
+	// Name is the name of the operand.
+	Name string `json:"name"`
+	// OperatorPhase is the phase of the operator.
+	// +optional
+	OperatorPhase OperatorPhase `json:"operatorPhase,omitempty"`
+	// OperandPhase is the phase of the operand.
+	// +optional
+	OperandPhase ServicePhase `json:"operandPhase,omitempty"`
+}
+
+// FleetPendingUpgrade is an operator installed by ODLM with an upgrade available or waiting for approval.
+type FleetPendingUpgrade struct {
+	// Name is the name of the Subscription.
+	Name string `json:"name"`
+	// Namespace is the namespace of the Subscription.
+	Namespace string `json:"namespace"`
+	// State is the state of the Subscription.
+	// +optional
+	State string `json:"state,omitempty"`
+	// InstalledCSV is the ClusterServiceVersion currently installed.
+	// +optional
+	InstalledCSV string `json:"installedCSV,omitempty"`
+	// CurrentCSV is the ClusterServiceVersion the operator is upgraded to.
+	// +optional
+	CurrentCSV string `json:"currentCSV,omitempty"`
+}
+
+// OperandFleetStatusStatus defines the observed state of OperandFleetStatus.
+type OperandFleetStatusStatus struct {
+	// LastUpdateTime is the time the rollup was last changed.
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+	// OperandRequests is the rollup of the OperandRequests.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="OperandRequests"
+	// +optional
+	OperandRequests FleetResourceSummary `json:"operandRequests,omitempty"`
+	// OperandRegistries is the rollup of the OperandRegistries.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="OperandRegistries"
+	// +optional
+	OperandRegistries FleetResourceSummary `json:"operandRegistries,omitempty"`
+	// OperandConfigs is the rollup of the OperandConfigs.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="OperandConfigs"
+	// +optional
+	OperandConfigs FleetResourceSummary `json:"operandConfigs,omitempty"`
+	// FailingMemberCount is the number of failed operators and operands of all the OperandRequests.
+	// +optional
+	FailingMemberCount int32 `json:"failingMemberCount,omitempty"`
+	// FailingMembers are the failed operators and operands, up to MaxItems.
+	// +optional
+	FailingMembers []FleetFailingMember `json:"failingMembers,omitempty"`
+	// PendingUpgradeCount is the number of operators installed by ODLM with a pending upgrade.
+	// +optional
+	PendingUpgradeCount int32 `json:"pendingUpgradeCount,omitempty"`
+	// PendingUpgrades are the operators with a pending upgrade, up to MaxItems.
+	// +optional
+	PendingUpgrades []FleetPendingUpgrade `json:"pendingUpgrades,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// OperandFleetStatus is the Schema for the operandfleetstatuses API.
+// It is kept updated by ODLM with a rollup of all the OperandRequests, OperandRegistries and OperandConfigs in the cluster.
+// +kubebuilder:resource:path=operandfleetstatuses,shortName=opfleet,scope=Cluster
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp
+// +kubebuilder:printcolumn:name="Requests",type=integer,JSONPath=.status.operandRequests.total,description="Number of OperandRequests"
+// +kubebuilder:printcolumn:name="Failing",type=integer,JSONPath=.status.failingMemberCount,description="Number of failing members"
+// +kubebuilder:printcolumn:name="Pending Upgrades",type=integer,JSONPath=.status.pendingUpgradeCount,description="Number of pending upgrades"
+// +kubebuilder:printcolumn:name="Created At",type=string,JSONPath=.metadata.creationTimestamp
+// +operator-sdk:csv:customresourcedefinitions:displayName="OperandFleetStatus"
+type OperandFleetStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperandFleetStatusSpec   `json:"spec,omitempty"`
+	Status OperandFleetStatusStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperandFleetStatusList contains a list of OperandFleetStatus.
+type OperandFleetStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperandFleetStatus `json:"items"`
+}
+
+// GetMaxItems returns the maximum number of failing members and pending upgrades listed in the status.
+func (r *OperandFleetStatus) GetMaxItems() int {
+	if r.Spec.MaxItems == nil {
+		return DefaultMaxFleetItems
+	}
+	return int(*r.Spec.MaxItems)
+}
+
+// Add counts a resource in the phase.
+func (s *FleetResourceSummary) Add(phase string) {
+	if phase == "" {
+		phase = FleetPhaseUnknown
+	}
+	if s.Phases == nil {
+		s.Phases = make(map[string]int32)
+	}
+	s.Total++
+	s.Phases[phase]++
+}
+
+func init() {
+	SchemeBuilder.Register(&OperandFleetStatus{}, &OperandFleetStatusList{})
+}
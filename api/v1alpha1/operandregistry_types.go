@@ -17,9 +17,14 @@
 package v1alpha1
 
 import (
+	"sort"
+	"strings"
+	"time"
+
 	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -68,9 +73,180 @@ type Operator struct {
 	// StartingCSV of the installation.
 	// +optional
 	StartingCSV string `json:"startingCSV,omitempty"`
-	// SubscriptionConfig is used to override operator configuration.
+	// PinnedVersion pins the operator to an exact version, either the name of its CSV, like ibm-etcd-operator.v0.9.4,
+	// or a semver, like 0.9.4. ODLM sets the Manual approval on the Subscription and only approves the InstallPlans
+	// installing the pinned version, until the pin is lifted. The Subscription starts at the pinned CSV unless
+	// StartingCSV is set. It is only supported when the type is "olm".
+	// +optional
+	PinnedVersion string `json:"pinnedVersion,omitempty"`
+	// SubscriptionConfig is used to override operator configuration, like the environment variables, resources,
+	// node selector and tolerations of the operator pods. ODLM merges it into the config of the Subscription,
+	// the fields removed from it are removed from the Subscription, and the fields set by others are kept.
+	// It is injected into the Deployments of the operator installed from the manifests.
 	// +optional
 	SubscriptionConfig *olmv1alpha1.SubscriptionConfig `json:"subscriptionConfig,omitempty"`
+	// PriorityClassName is the priority class of the operator pods. The config of the Subscription doesn't support it,
+	// ODLM sets it in the Deployments of the ClusterServiceVersion once they are created.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// PlacementProfile is the name of a placement profile of the OperatorConfig the operator pods are scheduled with.
+	// Its node selector and tolerations are merged into the SubscriptionConfig, and its affinity is set in the
+	// Deployments of the operator like the priority class.
+	// +optional
+	PlacementProfile string `json:"placementProfile,omitempty"`
+	// The type of the operator installation.
+	// Valid values are:
+	// - "olm" (default): operator is installed by an OLM Subscription;
+	// - "manifests": operator is installed by ODLM from the manifests, for the clusters without OLM;
+	// - "helm": operator or operand is installed by ODLM as a Helm release;
+	// - "byo": operator is brought by the user, ODLM only verifies it is present;
+	// +kubebuilder:validation:Enum=olm;manifests;helm;byo
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Manifests is the source of the operator manifests, it is required when the type is "manifests".
+	// +optional
+	Manifests *ManifestsSource `json:"manifests,omitempty"`
+	// Chart is the Helm chart to be installed, it is required when the type is "helm".
+	// +optional
+	Chart *HelmChart `json:"chart,omitempty"`
+	// BYO defines how ODLM verifies the operator brought by the user, when the type is "byo".
+	// +optional
+	BYO *BYOOperator `json:"byo,omitempty"`
+	// Requires is the list of the operators, in the same OperandRegistry, this operator depends on.
+	// An OperandRequest asking for this operator installs and tracks them too, as implicit operands.
+	// +optional
+	Requires []string `json:"requires,omitempty"`
+	// Coexist keeps the previous version of the operator running side by side with the new one, for a side-by-side upgrade.
+	// The OperandRequests already using the previous version keep it until they are migrated, the other OperandRequests
+	// use the new version. It is only supported when the type is "olm".
+	// +optional
+	Coexist bool `json:"coexist,omitempty"`
+	// PreviousVersion is the previous version of the operator, with its own Subscription, when Coexist is true.
+	// +optional
+	PreviousVersion *OperatorVersion `json:"previousVersion,omitempty"`
+	// AutoStepChannels switches the Subscription through the intermediate channels of the package, one at a time,
+	// when the replacement chain of the channel skips the installed CSV. Otherwise ODLM only reports the
+	// intermediate channels in a ReplacesChainBroken condition. It is only supported when the type is "olm".
+	// +optional
+	AutoStepChannels bool `json:"autoStepChannels,omitempty"`
+	// AllowSkipRange controls the upgrades skipping versions through the olm.skipRange of the CSVs.
+	// When false, ODLM approves the InstallPlans of the Subscription itself, and only the upgrades to the CSV
+	// replacing the installed one. When unset, OLM takes the olm.skipRange as usual.
+	// It is only supported when the type is "olm".
+	// +optional
+	AllowSkipRange *bool `json:"allowSkipRange,omitempty"`
+	// ChannelScheme reads the versions of the channels of the operator when they don't follow the vX.Y scheme,
+	// to pick the largest channel of the singleton services and to check the channel downgrades.
+	// +optional
+	ChannelScheme *ChannelScheme `json:"channelScheme,omitempty"`
+	// Entitlement is the license the operator requires. ODLM doesn't subscribe to the operator until it is entitled,
+	// the Subscriptions already created aren't affected. It is only supported when the type is "olm".
+	// +optional
+	Entitlement *Entitlement `json:"entitlement,omitempty"`
+}
+
+// Entitlement defines how ODLM checks the license of an operator, with a Secret, an entitlement API or both.
+type Entitlement struct {
+	// SecretRef is the Secret holding the license, the operator is entitled once it exists.
+	// +optional
+	SecretRef *EntitlementSecretRef `json:"secretRef,omitempty"`
+	// API is the entitlement API ODLM calls, the operator is entitled when it answers with the expected status.
+	// +optional
+	API *HTTPCheck `json:"api,omitempty"`
+}
+
+// EntitlementSecretRef references the Secret holding the license of an operator.
+type EntitlementSecretRef struct {
+	// Name is the name of the Secret.
+	Name string `json:"name"`
+	// Namespace is the namespace of the Secret. The default is the namespace of the OperandRegistry.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Key is the key of the license in the Secret, it must not be empty. Any Secret is accepted when it is not set.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// ChannelScheme defines how the versions of the channels of an operator are read.
+type ChannelScheme struct {
+	// Name is the scheme of the channel names.
+	// Valid values are:
+	// - "semver" (default): the version ends the channel name, like v3.20, stable-v3.23.1, candidate-4.14 or 2023.4;
+	// - "openshift": the channels are a risk level and a version, like candidate-4.14, fast-4.14, stable-4.14 or eus-4.14,
+	// ordered by version, then from candidate to eus;
+	// +kubebuilder:validation:Enum=semver;openshift
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Pattern is a regular expression matching the channel names, its first capture group is the version of the channel,
+	// like ^release-([0-9.]+)-lts$. It overrides the version of the named scheme, the channels not matching it have no version.
+	// +optional
+	Pattern string `json:"pattern,omitempty"`
+	// Order lists the channels from the lowest to the highest, like alpha, beta and stable.
+	// It orders the channels without a version, and the channels with the same version.
+	// +optional
+	Order []string `json:"order,omitempty"`
+}
+
+// OperatorVersion defines the previous version of a coexisting operator.
+// The unset fields are the same as the new version.
+type OperatorVersion struct {
+	// Namespace is the namespace of the previous version, it must be different from the namespace of the new version.
+	Namespace string `json:"namespace"`
+	// Channel is the channel tracked by the previous version.
+	Channel string `json:"channel"`
+	// Name of a CatalogSource that defines where and how to find the channel.
+	// +optional
+	SourceName string `json:"sourceName,omitempty"`
+	// The Kubernetes namespace where the CatalogSource used is located.
+	// +optional
+	SourceNamespace string `json:"sourceNamespace,omitempty"`
+	// StartingCSV of the installation.
+	// +optional
+	StartingCSV string `json:"startingCSV,omitempty"`
+	// PinnedVersion pins the previous version, like the PinnedVersion of the operator.
+	// +optional
+	PinnedVersion string `json:"pinnedVersion,omitempty"`
+}
+
+// ManifestsSource defines where the operator manifests are fetched from.
+// Only one of BundleImage and URL should be set.
+type ManifestsSource struct {
+	// BundleImage is an operator bundle image. The manifests directory of the image
+	// is unpacked by a Job in the operator namespace.
+	// +optional
+	BundleImage string `json:"bundleImage,omitempty"`
+	// URL is the http(s) URL of a plain YAML or JSON manifest file.
+	// +optional
+	URL string `json:"url,omitempty"`
+}
+
+// HelmChart defines the Helm chart and the release installed from it.
+type HelmChart struct {
+	// Repository is the URL of the chart repository. An "oci://" URL refers to an OCI registry.
+	Repository string `json:"repository"`
+	// Name is the name of the chart in the repository.
+	Name string `json:"name"`
+	// Version is the version of the chart. The default is the latest version.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// ReleaseName is the name of the Helm release. The default is the operator name.
+	// +optional
+	ReleaseName string `json:"releaseName,omitempty"`
+	// Values are the default values of the release. The values in the OperandConfig are merged into them.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Values *runtime.RawExtension `json:"values,omitempty"`
+	// ServiceAccountName is the ServiceAccount, in the operator namespace, the Helm Jobs run as.
+	// It must be granted the permissions the chart needs, ODLM doesn't grant any.
+	ServiceAccountName string `json:"serviceAccountName"`
+}
+
+// BYOOperator defines the operator installed by the user.
+type BYOOperator struct {
+	// DeploymentName is the name of the operator Deployment in the operator namespace.
+	// The default is the operator name.
+	// +optional
+	DeploymentName string `json:"deploymentName,omitempty"`
 }
 
 // +kubebuilder:validation:Enum=public;private
@@ -92,12 +268,41 @@ const (
 	InstallModeNamespace string = "namespace"
 )
 
+const (
+	// OperatorTypeOLM means the operator is installed by an OLM Subscription.
+	OperatorTypeOLM string = "olm"
+	// OperatorTypeManifests means the operator is installed by ODLM from the manifests.
+	OperatorTypeManifests string = "manifests"
+	// OperatorTypeHelm means the operator or operand is installed by ODLM as a Helm release.
+	OperatorTypeHelm string = "helm"
+	// OperatorTypeBYO means the operator is installed by the user, ODLM only verifies it is present.
+	OperatorTypeBYO string = "byo"
+)
+
 // OperandRegistrySpec defines the desired state of OperandRegistry.
 type OperandRegistrySpec struct {
 	// Operators is a list of operator OLM definition.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Operators Registry List"
 	// +optional
 	Operators []Operator `json:"operators,omitempty"`
+	// NamespaceProvisioning creates the namespaces of the operators which don't exist, with its labels and annotations.
+	// Without it, ODLM only tries to create the namespace an operator is installed in.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Namespace Provisioning"
+	// +optional
+	NamespaceProvisioning *NamespaceProvisioning `json:"namespaceProvisioning,omitempty"`
+}
+
+// NamespaceProvisioning defines the namespaces ODLM creates for the operators of the OperandRegistry.
+type NamespaceProvisioning struct {
+	// Labels are set on the created namespaces, like the pod security labels.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are set on the created namespaces.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// TargetNamespaces creates the target namespaces of the operators as well, not only the namespaces they are installed in.
+	// +optional
+	TargetNamespaces bool `json:"targetNamespaces,omitempty"`
 }
 
 // OperandRegistryStatus defines the observed state of OperandRegistry.
@@ -123,8 +328,47 @@ type OperatorStatus struct {
 	// ReconcileRequests stores the namespace/name of all the requests.
 	// +optional
 	ReconcileRequests []ReconcileRequest `json:"reconcileRequests,omitempty"`
+	// RequestCount is the number of the OperandRequests using the operator.
+	// +optional
+	RequestCount int `json:"requestCount,omitempty"`
+	// Namespaces are the namespaces of the OperandRequests using the operator, sorted.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// InstalledCSV is the ClusterServiceVersion installed by the Subscription of the operator.
+	// +optional
+	InstalledCSV string `json:"installedCSV,omitempty"`
+	// Version is the version of the installed ClusterServiceVersion.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// SubscriptionHealth is the health of the Subscription of the operator, it is empty for the operators not installed by OLM.
+	// +optional
+	SubscriptionHealth SubscriptionHealth `json:"subscriptionHealth,omitempty"`
+	// Message explains the health of the Subscription when it isn't healthy.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// LastTransitionTime is the last time the phase, the installed CSV or the health of the Subscription changed,
+	// in RFC 3339 format.
+	// +kubebuilder:validation:Format=date-time
+	// +optional
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
 }
 
+// SubscriptionHealth is the health of the Subscription of an operator.
+// +kubebuilder:validation:Enum=Healthy;Progressing;Unhealthy;NotFound
+type SubscriptionHealth string
+
+// The health of the Subscriptions.
+const (
+	// SubscriptionHealthy is a Subscription at the latest known CSV of its channel.
+	SubscriptionHealthy SubscriptionHealth = "Healthy"
+	// SubscriptionProgressing is a Subscription installing or upgrading its CSV.
+	SubscriptionProgressing SubscriptionHealth = "Progressing"
+	// SubscriptionUnhealthy is a Subscription OLM can't resolve, or whose CatalogSources are unhealthy.
+	SubscriptionUnhealthy SubscriptionHealth = "Unhealthy"
+	// SubscriptionNotFound is an operator requested without a Subscription.
+	SubscriptionNotFound SubscriptionHealth = "NotFound"
+)
+
 // ReconcileRequest records the information of the operandRequest.
 type ReconcileRequest struct {
 	// Name defines the name of request.
@@ -151,6 +395,7 @@ type OperandRegistry struct {
 }
 
 // RegistryPhase defines the operator status.
+// +kubebuilder:validation:Enum="Ready for Deployment";Running;Pending;Updating;Failed;"Waiting for CatalogSource being ready";Initialized
 type RegistryPhase string
 
 // Registry phase
@@ -159,6 +404,10 @@ const (
 	// when an OperandRegistry is deleted.
 	RegistryFinalizer = "finalizer.registry.ibm.com"
 
+	// ForceUpgradeAnnotation lists the comma-separated names of the operators whose channel switches aren't checked
+	// against the channel constraints of the ClusterOperandPolicies. The value "*" forces all the operators of the OperandRegistry.
+	ForceUpgradeAnnotation = "operator.ibm.com/force-upgrade"
+
 	RegistryReady    RegistryPhase = "Ready for Deployment"
 	RegistryRunning  RegistryPhase = "Running"
 	RegistryPending  RegistryPhase = "Pending"
@@ -202,6 +451,30 @@ func (r *OperandRegistry) SetOperatorStatus(name string, phase OperatorPhase, re
 	r.Status.OperatorsStatus[name] = s
 }
 
+// SummarizeRequests sets the number of the OperandRequests using the operator and their namespaces.
+func (s *OperatorStatus) SummarizeRequests() {
+	s.RequestCount = len(s.ReconcileRequests)
+	seen := make(map[string]bool)
+	s.Namespaces = nil
+	for _, req := range s.ReconcileRequests {
+		if !seen[req.Namespace] {
+			seen[req.Namespace] = true
+			s.Namespaces = append(s.Namespaces, req.Namespace)
+		}
+	}
+	sort.Strings(s.Namespaces)
+}
+
+// KeepTransitionTime keeps the LastTransitionTime of the previous status when the phase, the installed CSV
+// and the health of the Subscription are unchanged, otherwise it is set to now.
+func (s *OperatorStatus) KeepTransitionTime(previous *OperatorStatus, now time.Time) {
+	if previous != nil && previous.LastTransitionTime != "" && previous.Phase == s.Phase && previous.InstalledCSV == s.InstalledCSV && previous.SubscriptionHealth == s.SubscriptionHealth {
+		s.LastTransitionTime = previous.LastTransitionTime
+		return
+	}
+	s.LastTransitionTime = now.UTC().Format(time.RFC3339)
+}
+
 // GetOperator obtains the operator definition with the operand name.
 func (r *OperandRegistry) GetOperator(operandName string) *Operator {
 	for _, o := range r.Spec.Operators {
@@ -212,6 +485,125 @@ func (r *OperandRegistry) GetOperator(operandName string) *Operator {
 	return nil
 }
 
+// IsUpgradeForced returns true if the channel switches of the operator are forced by the ForceUpgradeAnnotation.
+func (r *OperandRegistry) IsUpgradeForced(operatorName string) bool {
+	for _, name := range strings.Split(r.GetAnnotations()[ForceUpgradeAnnotation], ",") {
+		name = strings.TrimSpace(name)
+		if name == "*" || (name != "" && name == operatorName) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRequiredOperands returns the operators required by the operands, directly or through another required operator,
+// which aren't in the operands themselves. The value is the sorted names of the operands requiring the operator.
+func (r *OperandRegistry) GetRequiredOperands(operands []Operand) map[string][]string {
+	requested := make(map[string]bool)
+	for _, operand := range operands {
+		requested[operand.Name] = true
+	}
+	requiredBy := make(map[string][]string)
+	for _, operand := range operands {
+		visited := map[string]bool{operand.Name: true}
+		queue := []string{operand.Name}
+		for len(queue) > 0 {
+			name := queue[0]
+			queue = queue[1:]
+			opt := r.GetOperator(name)
+			if opt == nil {
+				continue
+			}
+			for _, required := range opt.Requires {
+				if visited[required] {
+					continue
+				}
+				visited[required] = true
+				queue = append(queue, required)
+				if !requested[required] {
+					requiredBy[required] = append(requiredBy[required], operand.Name)
+				}
+			}
+		}
+	}
+	for name := range requiredBy {
+		sort.Strings(requiredBy[name])
+	}
+	return requiredBy
+}
+
+// WithRequiredOperands returns the operands followed by the operators they require, sorted by name.
+func (r *OperandRegistry) WithRequiredOperands(operands []Operand) []Operand {
+	requiredBy := r.GetRequiredOperands(operands)
+	if len(requiredBy) == 0 {
+		return operands
+	}
+	names := make([]string, 0, len(requiredBy))
+	for name := range requiredBy {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := make([]Operand, 0, len(operands)+len(names))
+	result = append(result, operands...)
+	for _, name := range names {
+		result = append(result, Operand{Name: name})
+	}
+	return result
+}
+
+// GetType returns the type of the operator installation with default value.
+func (o *Operator) GetType() string {
+	if o.Type == "" {
+		return OperatorTypeOLM
+	}
+	return o.Type
+}
+
+// DeniesSkipRange returns true if the upgrades of the operator must follow the replaces of the CSVs.
+func (o *Operator) DeniesSkipRange() bool {
+	return o.AllowSkipRange != nil && !*o.AllowSkipRange
+}
+
+// GetReleaseName returns the name of the Helm release with default value.
+func (o *Operator) GetReleaseName() string {
+	if o.Chart == nil || o.Chart.ReleaseName == "" {
+		return o.Name
+	}
+	return o.Chart.ReleaseName
+}
+
+// GetDeploymentName returns the name of the Deployment of the operator brought by the user with default value.
+func (o *Operator) GetDeploymentName() string {
+	if o.BYO == nil || o.BYO.DeploymentName == "" {
+		return o.Name
+	}
+	return o.BYO.DeploymentName
+}
+
+// GetPreviousVersion returns the operator definition of the previous version, or nil if the operator doesn't coexist
+// with its previous version.
+func (o *Operator) GetPreviousVersion() *Operator {
+	if !o.Coexist || o.PreviousVersion == nil || o.GetType() != OperatorTypeOLM || o.PreviousVersion.Namespace == o.Namespace {
+		return nil
+	}
+	previous := o.DeepCopy()
+	previous.Coexist = false
+	previous.PreviousVersion = nil
+	previous.InstallMode = InstallModeNamespace
+	previous.Namespace = o.PreviousVersion.Namespace
+	previous.TargetNamespaces = nil
+	previous.Channel = o.PreviousVersion.Channel
+	previous.StartingCSV = o.PreviousVersion.StartingCSV
+	previous.PinnedVersion = o.PreviousVersion.PinnedVersion
+	if o.PreviousVersion.SourceName != "" {
+		previous.SourceName = o.PreviousVersion.SourceName
+	}
+	if o.PreviousVersion.SourceNamespace != "" {
+		previous.SourceNamespace = o.PreviousVersion.SourceNamespace
+	}
+	return previous
+}
+
 // GetAllReconcileRequest gets all the ReconcileRequest from OperandRegistry status.
 func (r *OperandRegistry) GetAllReconcileRequest() []reconcile.Request {
 	maprrs := make(map[string]reconcile.Request)
@@ -244,6 +636,37 @@ func (r *OperandRegistry) SetNotFoundCondition(name string, rt ResourceType, cs
 	r.setCondition(*c)
 }
 
+// SetCatalogUnhealthyConditions sets a CatalogUnhealthy condition for each unhealthy CatalogSource with the reason
+// it can't serve the operators, and removes the conditions of the other CatalogSources.
+// The condition of a CatalogSource is kept as it is while its reason doesn't change.
+func (r *OperandRegistry) SetCatalogUnhealthyConditions(unhealthy map[string]string) {
+	reasons := make(map[string]string)
+	for catalog, message := range unhealthy {
+		reasons[string(ResourceTypeCatalogSource)+" "+catalog+" is unhealthy"] = message
+	}
+	var conds []Condition
+	for _, c := range r.Status.Conditions {
+		if c.Type != ConditionCatalogUnhealthy {
+			conds = append(conds, c)
+			continue
+		}
+		if message, ok := reasons[c.Reason]; ok && message == c.Message {
+			conds = append(conds, c)
+			delete(reasons, c.Reason)
+		}
+	}
+	r.Status.Conditions = conds
+	// Append the new conditions in a stable order
+	keys := make([]string, 0, len(reasons))
+	for reason := range reasons {
+		keys = append(keys, reason)
+	}
+	sort.Strings(keys)
+	for _, reason := range keys {
+		r.setCondition(*newCondition(ConditionCatalogUnhealthy, corev1.ConditionTrue, reason, reasons[reason]))
+	}
+}
+
 func (r *OperandRegistry) setCondition(c Condition) {
 	pos, cp := getCondition(&r.Status.Conditions, c.Type, c.Message)
 	if cp != nil {
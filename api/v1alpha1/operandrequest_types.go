@@ -17,11 +17,15 @@
 package v1alpha1
 
 import (
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -35,6 +39,49 @@ type OperandRequestSpec struct {
 	// Requests defines a list of operands installation.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Operators Request List"
 	Requests []Request `json:"requests"`
+	// NotBefore is the time before which ODLM doesn't start installing or updating the operands of the request.
+	// The request is held in the Scheduled phase until then.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Not Before"
+	// +optional
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+	// Placement distributes the operators and operands of the request to the managed clusters,
+	// instead of installing them in the current cluster.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Placement"
+	// +optional
+	Placement *OperandPlacement `json:"placement,omitempty"`
+	// TeardownGracePeriod is how long ODLM waits for the custom resources of the operands to be removed,
+	// after the OperandRequest is deleted, before it uninstalls the operators anyway. The default is 5m.
+	// +optional
+	TeardownGracePeriod *metav1.Duration `json:"teardownGracePeriod,omitempty"`
+	// PrunePolicy is what ODLM does with the operands removed from the requests.
+	// Delete uninstalls them if no other OperandRequest requests them, Orphan leaves them running
+	// and only releases the references of the OperandRequest to their operators. The default is Delete.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Prune Policy"
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	// +optional
+	PrunePolicy string `json:"prunePolicy,omitempty"`
+}
+
+// PlacementType is the way the operators and operands are distributed to the managed clusters.
+type PlacementType string
+
+const (
+	// PlacementTypeManifestWork distributes the operators and operands with the Open Cluster Management ManifestWork
+	PlacementTypeManifestWork PlacementType = "ManifestWork"
+)
+
+// OperandPlacement defines the managed clusters the request is distributed to.
+type OperandPlacement struct {
+	// Type is the way the request is distributed. The default is ManifestWork.
+	// +kubebuilder:validation:Enum=ManifestWork
+	// +optional
+	Type PlacementType `json:"type,omitempty"`
+	// Clusters are the names of the managed clusters.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+	// ClusterSelector selects the managed clusters by their labels.
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
 }
 
 // Request identifies a operand detail.
@@ -76,18 +123,80 @@ type Operand struct {
 	// +nullable
 	// +optional
 	Spec *runtime.RawExtension `json:"spec,omitempty"`
+	// NotBefore is the time before which ODLM doesn't start installing or updating the operand.
+	// It is combined with the NotBefore of the request, the later one applies.
+	// +optional
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+	// Uses declares the APIs of the operand the request depends on.
+	// ODLM doesn't switch the channel of the operator to a version removing any of them.
+	// The API of the custom resource set by Kind and APIVersion is always used.
+	// +optional
+	Uses []OperandUse `json:"uses,omitempty"`
+	// DeletionPolicy is what ODLM does with the operand when the OperandRequest is deleted.
+	// Delete uninstalls the operand if no other OperandRequest requests it, Retain keeps it
+	// with the operators it requires. The default is Delete.
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +optional
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+	// PinnedVersion pins the operator of the operand to an exact version, either the name of its CSV or a semver.
+	// It overrides the PinnedVersion of the operator in the OperandRegistry. When the OperandRequests
+	// of the operator pin different versions, ODLM approves no InstallPlan until they agree.
+	// +optional
+	PinnedVersion string `json:"pinnedVersion,omitempty"`
+	// SizeProfile is the name of the size profile of the service in the OperandConfig, like small, medium or large.
+	// When the OperandRequests of the service select different profiles, the largest one applies.
+	// +optional
+	SizeProfile string `json:"sizeProfile,omitempty"`
+	// Replicas is the number of replicas of the service, rendered into the replica paths declared by the scaling
+	// of the service in the OperandConfig. It overrides the replicas of the size profile. When the OperandRequests
+	// of the service request different replicas, the highest number applies.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Patches are the JSON6902 operations applied to the custom resource of the operand with a kind, after its Spec
+	// is merged into it. They express the edits the merge can't, like removing a field or inserting a list item
+	// at an index. Their paths start with /spec.
+	// +optional
+	Patches []PatchOperation `json:"patches,omitempty"`
+}
+
+// PatchOperation is a JSON6902 operation of a custom resource.
+type PatchOperation struct {
+	// Op is the operation.
+	// +kubebuilder:validation:Enum=add;remove;replace;move;copy;test
+	Op string `json:"op"`
+	// Path is the JSON pointer of the field, like /spec/containers/0.
+	Path string `json:"path"`
+	// From is the JSON pointer of the field moved or copied by the move and copy operations.
+	// +optional
+	From string `json:"from,omitempty"`
+	// Value is the value of the add, replace and test operations.
+	// +optional
+	Value *apiextensionsv1.JSON `json:"value,omitempty"`
+}
+
+// OperandUse declares an API of an operand.
+type OperandUse struct {
+	// APIVersion is the group and version of the API, like etcd.database.coreos.com/v1beta2.
+	APIVersion string `json:"apiVersion"`
+	// Kind is the kind of the API. The default is all the kinds of the APIVersion.
+	// +optional
+	Kind string `json:"kind,omitempty"`
 }
 
 // ConditionType is the condition of a service.
+// +kubebuilder:validation:Enum=Creating;Updating;Deleting;NotFound;OutofScope;Ready;Truncated;Scheduled;Throttled;Excluded;IncompatibleConsumers;Drifted;Paused;Unhealthy;Conflict;PropagateConflict;ReplacesChainBroken;Blocked;SkipRangeDenied;PermissionDenied;OperatorGroupConflict;CatalogUnhealthy;VersionPinned;UpgradeBlocked;WaitingForDependencies;VerificationFailed;SchemaValidationFailed;LicenseRequired;Reconciling;Stalled;WaitingForCRD;ManifestsTooLarge
 type ConditionType string
 
 // ClusterPhase is the phase of the installation.
+// +kubebuilder:validation:Enum=Pending;Creating;Installing;Updating;Running;Failed;Scheduled
 type ClusterPhase string
 
 // ResourceType is the type of condition use.
 type ResourceType string
 
 // OperatorPhase defines the operator status.
+// +kubebuilder:validation:Enum="Ready for Deployment";Running;Installing;Updating;Failed;Initialized;"Not Found";Scheduled
 type OperatorPhase string
 
 // Constants are used for state.
@@ -96,12 +205,85 @@ const (
 	// when an OperandRequest is deleted.
 	RequestFinalizer = "finalizer.request.ibm.com"
 
+	// PausedOperandsAnnotation lists the comma-separated names of the operands whose reconcile is paused.
+	// The value "*" pauses all the operands of the OperandRequest.
+	PausedOperandsAnnotation = "operator.ibm.com/paused-operands"
+
+	// MigratedOperandsAnnotation lists the comma-separated names of the coexisting operands migrated
+	// from their previous version to the new one. The value "*" migrates all the operands of the OperandRequest.
+	MigratedOperandsAnnotation = "operator.ibm.com/migrated-operands"
+
+	// DeletionPreviewAnnotation generates the deletion preview in the status of the OperandRequest when it is "true".
+	DeletionPreviewAnnotation = "operator.ibm.com/deletion-preview"
+	// EffectiveConfigAnnotation shows the effective configuration of the operands in the status of the OperandRequest when it is "true".
+	EffectiveConfigAnnotation = "operator.ibm.com/effective-config"
+	// PriorityAnnotation is the priority class of the OperandRequest, the requests of a higher class
+	// are reconciled first when the controller has a backlog.
+	PriorityAnnotation = "operator.ibm.com/priority"
+
+	// The priority classes of the OperandRequests, from the highest to the lowest.
+	PriorityCritical = "critical"
+	PriorityHigh     = "high"
+	PriorityNormal   = "normal"
+	PriorityLow      = "low"
+
+	// The deletion policies of the operands.
+	DeletionPolicyDelete = "Delete"
+	DeletionPolicyRetain = "Retain"
+
+	// The prune policies of the operands removed from the requests.
+	PrunePolicyDelete = "Delete"
+	PrunePolicyOrphan = "Orphan"
+
+	// DefaultTeardownGracePeriod is how long ODLM waits for the custom resources of the deleted OperandRequest by default.
+	DefaultTeardownGracePeriod = 5 * time.Minute
+
+	// The reasons why a resource is retained when the OperandRequest is deleted.
+	PreviewReasonRequestedByOthers = "RequestedByOtherOperandRequests"
+	PreviewReasonOtherRegistries   = "RequestedWithOtherOperandRegistries"
+	PreviewReasonNotCreatedByODLM  = "NotCreatedByODLM"
+	PreviewReasonDoNotUninstall    = "DoNotUninstall"
+	PreviewReasonCRD               = "CustomResourceDefinition"
+	PreviewReasonAdopted           = "Adopted"
+
 	ConditionCreating   ConditionType = "Creating"
 	ConditionUpdating   ConditionType = "Updating"
 	ConditionDeleting   ConditionType = "Deleting"
 	ConditionNotFound   ConditionType = "NotFound"
 	ConditionOutofScope ConditionType = "OutofScope"
 	ConditionReady      ConditionType = "Ready"
+	ConditionTruncated  ConditionType = "Truncated"
+	ConditionScheduled  ConditionType = "Scheduled"
+	ConditionThrottled  ConditionType = "Throttled"
+	ConditionExcluded   ConditionType = "Excluded"
+
+	ConditionIncompatibleConsumers  ConditionType = "IncompatibleConsumers"
+	ConditionDrifted                ConditionType = "Drifted"
+	ConditionPaused                 ConditionType = "Paused"
+	ConditionUnhealthy              ConditionType = "Unhealthy"
+	ConditionConflict               ConditionType = "Conflict"
+	ConditionPropagateConflict      ConditionType = "PropagateConflict"
+	ConditionReplacesChainBroken    ConditionType = "ReplacesChainBroken"
+	ConditionBlocked                ConditionType = "Blocked"
+	ConditionSkipRangeDenied        ConditionType = "SkipRangeDenied"
+	ConditionPermissionDenied       ConditionType = "PermissionDenied"
+	ConditionOperatorGroupConflict  ConditionType = "OperatorGroupConflict"
+	ConditionCatalogUnhealthy       ConditionType = "CatalogUnhealthy"
+	ConditionVersionPinned          ConditionType = "VersionPinned"
+	ConditionUpgradeBlocked         ConditionType = "UpgradeBlocked"
+	ConditionVerificationFailed     ConditionType = "VerificationFailed"
+	ConditionSchemaValidationFailed ConditionType = "SchemaValidationFailed"
+	ConditionLicenseRequired        ConditionType = "LicenseRequired"
+
+	ConditionWaitingForDependencies ConditionType = "WaitingForDependencies"
+
+	// ConditionReconciling and ConditionStalled summarize the OperandRequest for the GitOps controllers
+	ConditionReconciling ConditionType = "Reconciling"
+	ConditionStalled     ConditionType = "Stalled"
+
+	ConditionWaitingForCRD ConditionType = "WaitingForCRD"
+
+	ConditionManifestsTooLarge ConditionType = "ManifestsTooLarge"
 
 	OperatorReady      OperatorPhase = "Ready for Deployment"
 	OperatorRunning    OperatorPhase = "Running"
@@ -110,6 +292,7 @@ const (
 	OperatorFailed     OperatorPhase = "Failed"
 	OperatorInit       OperatorPhase = "Initialized"
 	OperatorNotFound   OperatorPhase = "Not Found"
+	OperatorScheduled  OperatorPhase = "Scheduled"
 	OperatorNone       OperatorPhase = ""
 
 	ClusterPhaseNone       ClusterPhase = "Pending"
@@ -118,13 +301,23 @@ const (
 	ClusterPhaseUpdating   ClusterPhase = "Updating"
 	ClusterPhaseRunning    ClusterPhase = "Running"
 	ClusterPhaseFailed     ClusterPhase = "Failed"
+	ClusterPhaseScheduled  ClusterPhase = "Scheduled"
 
 	ResourceTypeOperandRegistry ResourceType = "operandregistry"
 	ResourceTypeCatalogSource   ResourceType = "catalogsource"
 	ResourceTypeSub             ResourceType = "subscription"
 	ResourceTypeCsv             ResourceType = "csv"
+	ResourceTypeManifests       ResourceType = "manifests"
+	ResourceTypeHelmRelease     ResourceType = "helmrelease"
 	ResourceTypeOperator        ResourceType = "operator"
 	ResourceTypeOperand         ResourceType = "operands"
+
+	// MaxConditionsAfterTruncation is the number of the latest conditions kept when the status is truncated.
+	MaxConditionsAfterTruncation = 10
+	// MaxHistoryAfterTruncation is the number of the latest history entries kept when the status is truncated.
+	MaxHistoryAfterTruncation = 5
+	// MaxMessageLengthAfterTruncation is the length the messages of the status are cut to when the status is truncated.
+	MaxMessageLengthAfterTruncation = 256
 )
 
 // Condition represents the current state of the Request Service.
@@ -133,11 +326,14 @@ type Condition struct {
 	// Type of condition.
 	Type ConditionType `json:"type"`
 	// Status of the condition, one of True, False, Unknown.
+	// +kubebuilder:validation:Enum=True;False;Unknown
 	Status corev1.ConditionStatus `json:"status"`
-	// The last time this condition was updated.
+	// The last time this condition was updated, in RFC 3339 format.
+	// +kubebuilder:validation:Format=date-time
 	// +optional
 	LastUpdateTime string `json:"lastUpdateTime,omitempty"`
-	// Last time the condition transitioned from one status to another.
+	// Last time the condition transitioned from one status to another, in RFC 3339 format.
+	// +kubebuilder:validation:Format=date-time
 	// +optional
 	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
 	// The reason for the condition's last transition.
@@ -161,6 +357,275 @@ type OperandRequestStatus struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Phase",xDescriptors="urn:alm:descriptor:io.kubernetes.phase"
 	// +optional
 	Phase ClusterPhase `json:"phase,omitempty"`
+	// Placement shows the status of the request in each managed cluster.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Placement"
+	// +optional
+	Placement []ClusterPlacementStatus `json:"placement,omitempty"`
+	// DeletionPreview lists the resources deleted and retained when the OperandRequest is deleted.
+	// It is generated when the annotation operator.ibm.com/deletion-preview is "true".
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Deletion Preview"
+	// +optional
+	DeletionPreview *DeletionPreview `json:"deletionPreview,omitempty"`
+	// EffectiveConfig shows the spec ODLM applies to each custom resource of the operands.
+	// It is generated when the annotation operator.ibm.com/effective-config is "true".
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Effective Config"
+	// +optional
+	EffectiveConfig *EffectiveConfig `json:"effectiveConfig,omitempty"`
+	// DriftDiff shows the differences between the configuration and the custom resources drifted from it,
+	// under the Detect remediation policy. It is refreshed at each reconcile.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Drift Diff"
+	// +optional
+	DriftDiff []DriftedResource `json:"driftDiff,omitempty"`
+	// History lists the last actions ODLM took for the OperandRequest, the oldest first.
+	// It holds up to 20 entries.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="History"
+	// +optional
+	History []HistoryEntry `json:"history,omitempty"`
+	// ObservedGeneration is the generation of the OperandRequest the last successful reconcile ran for.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Observed Generation"
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// HistoryAction is an action recorded in the history of an OperandRequest.
+// +kubebuilder:validation:Enum=SubscriptionCreated;ChannelChanged;SpecChanged;MemberFailed;OperandRemoved;OperandOrphaned
+type HistoryAction string
+
+// The actions recorded in the history of an OperandRequest.
+const (
+	// HistorySubscriptionCreated is recorded when the Subscription of an operator is created
+	HistorySubscriptionCreated HistoryAction = "SubscriptionCreated"
+	// HistoryChannelChanged is recorded when the Subscription of an operator is switched to another channel
+	HistoryChannelChanged HistoryAction = "ChannelChanged"
+	// HistorySpecChanged is recorded when the spec of a custom resource is changed by ODLM
+	HistorySpecChanged HistoryAction = "SpecChanged"
+	// HistoryMemberFailed is recorded when the operator or the operand of a member fails
+	HistoryMemberFailed HistoryAction = "MemberFailed"
+	// HistoryOperandRemoved is recorded when an operand removed from the OperandRequest is uninstalled
+	HistoryOperandRemoved HistoryAction = "OperandRemoved"
+	// HistoryOperandOrphaned is recorded when an operand removed from the OperandRequest is left running
+	HistoryOperandOrphaned HistoryAction = "OperandOrphaned"
+
+	// MaxHistoryEntries is the number of entries kept in the history of an OperandRequest.
+	MaxHistoryEntries = 20
+)
+
+// HistoryEntry is an action ODLM took for an OperandRequest.
+type HistoryEntry struct {
+	// Time is when the action was taken, in RFC 3339 format.
+	// +kubebuilder:validation:Format=date-time
+	Time string `json:"time"`
+	// Action is the action taken.
+	Action HistoryAction `json:"action"`
+	// Operator is the name of the operator of the action.
+	// +optional
+	Operator string `json:"operator,omitempty"`
+	// Resource is the kind, namespace and name of the resource changed by the action, like EtcdCluster ns/example.
+	// +optional
+	Resource string `json:"resource,omitempty"`
+	// From is the value before the action, like the previous channel or the hash of the previous spec.
+	// +optional
+	From string `json:"from,omitempty"`
+	// To is the value after the action, like the new channel or the hash of the new spec.
+	// +optional
+	To string `json:"to,omitempty"`
+	// Message is a human readable description of the action.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// DeletionPreview lists the resources deleted and retained when the OperandRequest is deleted,
+// under the current cleanup policies.
+type DeletionPreview struct {
+	// GeneratedTime is when the preview was generated, in RFC 3339 format.
+	// +kubebuilder:validation:Format=date-time
+	// +optional
+	GeneratedTime string `json:"generatedTime,omitempty"`
+	// Deleted are the resources deleted with the OperandRequest.
+	// +optional
+	Deleted []PreviewResource `json:"deleted,omitempty"`
+	// Retained are the resources kept after the OperandRequest is deleted.
+	// +optional
+	Retained []PreviewResource `json:"retained,omitempty"`
+}
+
+// PreviewResource is a resource of the deletion preview.
+type PreviewResource struct {
+	// APIVersion is the apiVersion of the resource.
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+	// Kind is the kind of the resource.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the resource.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the resource.
+	Name string `json:"name"`
+	// Operand is the operand the resource belongs to.
+	// +optional
+	Operand string `json:"operand,omitempty"`
+	// Reason is why the resource is retained.
+	// +kubebuilder:validation:Enum=RequestedByOtherOperandRequests;RequestedWithOtherOperandRegistries;NotCreatedByODLM;DoNotUninstall;CustomResourceDefinition;Adopted
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// AddDeleted adds a resource deleted with the OperandRequest to the preview.
+func (p *DeletionPreview) AddDeleted(res PreviewResource) {
+	p.Deleted = append(p.Deleted, res)
+}
+
+// AddRetained adds a resource kept after the OperandRequest is deleted to the preview, with the reason.
+func (p *DeletionPreview) AddRetained(res PreviewResource, reason string) {
+	res.Reason = reason
+	p.Retained = append(p.Retained, res)
+}
+
+// Merge adds the resources of the other preview.
+// Its deleted resources are retained with the reason, unless the reason is empty.
+func (p *DeletionPreview) Merge(other *DeletionPreview, reason string) {
+	if reason == "" {
+		p.Deleted = append(p.Deleted, other.Deleted...)
+	} else {
+		for _, res := range other.Deleted {
+			p.AddRetained(res, reason)
+		}
+	}
+	p.Retained = append(p.Retained, other.Retained...)
+}
+
+// IsDeletionPreviewRequested returns true if the annotation asks for the deletion preview.
+func (r *OperandRequest) IsDeletionPreviewRequested() bool {
+	return r.GetAnnotations()[DeletionPreviewAnnotation] == "true"
+}
+
+// EffectiveConfig shows the spec ODLM applies to the custom resources of the operands, after merging
+// the ALM examples, the OperandConfig and the OperandRequest, and rendering the zones and the proxy.
+type EffectiveConfig struct {
+	// GeneratedTime is when the effective configuration was generated, in RFC 3339 format.
+	// +kubebuilder:validation:Format=date-time
+	// +optional
+	GeneratedTime string `json:"generatedTime,omitempty"`
+	// Resources are the custom resources of the operands.
+	// +optional
+	Resources []EffectiveResource `json:"resources,omitempty"`
+}
+
+// EffectiveResource is the spec ODLM applies to a custom resource.
+type EffectiveResource struct {
+	// Operand is the operand the custom resource belongs to.
+	Operand string `json:"operand"`
+	// APIVersion is the apiVersion of the custom resource.
+	APIVersion string `json:"apiVersion"`
+	// Kind is the kind of the custom resource.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the custom resource.
+	Namespace string `json:"namespace"`
+	// Name is the name of the custom resource.
+	Name string `json:"name"`
+	// Spec is the spec applied to the custom resource, the sensitive fields are redacted.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Spec *runtime.RawExtension `json:"spec,omitempty"`
+}
+
+// priorities are the orders of the priority classes, the higher first
+var priorities = map[string]int{
+	PriorityCritical: 3,
+	PriorityHigh:     2,
+	PriorityNormal:   1,
+	PriorityLow:      0,
+}
+
+// IsPriorityClass returns true if the name is one of the priority classes
+func IsPriorityClass(name string) bool {
+	_, ok := priorities[name]
+	return ok
+}
+
+// GetPriority returns the order of the priority class of the annotation, the higher first.
+// The OperandRequests without the annotation, or with an unknown class, are normal.
+func (r *OperandRequest) GetPriority() int {
+	if priority, ok := priorities[r.GetAnnotations()[PriorityAnnotation]]; ok {
+		return priority
+	}
+	return priorities[PriorityNormal]
+}
+
+// IsEffectiveConfigRequested returns true if the annotation asks for the effective configuration.
+func (r *OperandRequest) IsEffectiveConfigRequested() bool {
+	return r.GetAnnotations()[EffectiveConfigAnnotation] == "true"
+}
+
+// SetEffectiveResource records the spec applied to a custom resource, when the effective configuration is collected.
+// It replaces the spec recorded for the same custom resource.
+func (r *OperandRequest) SetEffectiveResource(res EffectiveResource, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	if r.Status.EffectiveConfig == nil {
+		return
+	}
+	for i, existing := range r.Status.EffectiveConfig.Resources {
+		if existing.Kind == res.Kind && existing.Namespace == res.Namespace && existing.Name == res.Name {
+			r.Status.EffectiveConfig.Resources[i] = res
+			return
+		}
+	}
+	r.Status.EffectiveConfig.Resources = append(r.Status.EffectiveConfig.Resources, res)
+}
+
+// DriftedResource shows the fields of a custom resource drifted from the configuration.
+type DriftedResource struct {
+	// Operand is the operand the custom resource belongs to.
+	Operand string `json:"operand"`
+	// APIVersion is the apiVersion of the custom resource.
+	APIVersion string `json:"apiVersion"`
+	// Kind is the kind of the custom resource.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the custom resource.
+	Namespace string `json:"namespace"`
+	// Name is the name of the custom resource.
+	Name string `json:"name"`
+	// Fields are the drifted fields, sorted by path.
+	Fields []DriftedField `json:"fields"`
+}
+
+// DriftedField shows the desired and the live values of a drifted field, the sensitive values are redacted.
+type DriftedField struct {
+	// Path is the dot-separated path of the field in the spec.
+	Path string `json:"path"`
+	// Desired is the JSON value of the field in the configuration.
+	// +optional
+	Desired string `json:"desired,omitempty"`
+	// Live is the JSON value of the field in the custom resource, it is empty when the field was removed.
+	// +optional
+	Live string `json:"live,omitempty"`
+}
+
+// SetDriftedResource records the drifted fields of a custom resource.
+// It replaces the fields recorded for the same custom resource.
+func (r *OperandRequest) SetDriftedResource(res DriftedResource, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	for i, existing := range r.Status.DriftDiff {
+		if existing.Kind == res.Kind && existing.Namespace == res.Namespace && existing.Name == res.Name {
+			r.Status.DriftDiff[i] = res
+			return
+		}
+	}
+	r.Status.DriftDiff = append(r.Status.DriftDiff, res)
+}
+
+// ClusterPlacementStatus shows the status of the request in a managed cluster.
+type ClusterPlacementStatus struct {
+	// Cluster is the name of the managed cluster.
+	Cluster string `json:"cluster"`
+	// Phase is the phase of the operators and operands in the managed cluster.
+	// +optional
+	Phase ClusterPhase `json:"phase,omitempty"`
+	// Message is a human readable message about the phase.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // MemberPhase shows the phase of the operator and operator instance.
@@ -196,6 +661,67 @@ type MemberStatus struct {
 	// OperandCRList shows the list of custom resource created by OperandRequest.
 	// +optional
 	OperandCRList []OperandCRMember `json:"operandCRList,omitempty"`
+	// HelmRelease shows the status of the Helm release, for the operator installed by Helm.
+	// +optional
+	HelmRelease *HelmReleaseStatus `json:"helmRelease,omitempty"`
+	// RequiredBy lists the requested operands requiring this member, when it is an implicit operand
+	// included from the requires of the OperandRegistry rather than requested explicitly.
+	// +optional
+	RequiredBy []string `json:"requiredBy,omitempty"`
+	// Upgrade is the last upgrade of the operator installed by OLM.
+	// +optional
+	Upgrade *UpgradeStatus `json:"upgrade,omitempty"`
+	// WaitingForCRD is the group/version, Kind=kind of the custom resource whose CRD isn't established yet,
+	// the custom resource is created as soon as the CRD is established.
+	// +optional
+	WaitingForCRD string `json:"waitingForCRD,omitempty"`
+	// Retry shows the retries of the member while its reconcile keeps failing.
+	// +optional
+	Retry *MemberRetry `json:"retry,omitempty"`
+}
+
+// MemberRetry defines the retries of a member whose reconcile keeps failing.
+// The member is reconciled again with an exponential backoff, the other members are reconciled in the meantime.
+type MemberRetry struct {
+	// Retries is the number of the reconciles of the member which failed in a row.
+	Retries int `json:"retries"`
+	// LastError is the error of the last failed reconcile of the member.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+	// NextRetryTime is when the member is reconciled again.
+	// +optional
+	NextRetryTime string `json:"nextRetryTime,omitempty"`
+}
+
+// UpgradeStatus defines the last upgrade of an operator installed by OLM.
+type UpgradeStatus struct {
+	// From is the ClusterServiceVersion before the upgrade.
+	From string `json:"from"`
+	// To is the ClusterServiceVersion after the upgrade.
+	To string `json:"to"`
+	// Path is how OLM upgraded the operator, one of replaces, skips and skipRange.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// HelmReleaseStatus defines the status of a Helm release.
+type HelmReleaseStatus struct {
+	// Name is the name of the release.
+	Name string `json:"name"`
+	// Namespace is the namespace of the release.
+	Namespace string `json:"namespace"`
+	// Revision is the latest revision of the release.
+	// +optional
+	Revision int `json:"revision,omitempty"`
+	// Status is the Helm status of the latest revision, like deployed, failed or pending-upgrade.
+	// +optional
+	Status string `json:"status,omitempty"`
+	// ChartVersion is the version of the chart of the latest revision.
+	// +optional
+	ChartVersion string `json:"chartVersion,omitempty"`
+	// Description is the Helm description of the latest revision.
+	// +optional
+	Description string `json:"description,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -280,94 +806,804 @@ func (r *OperandRequest) SetNotFoundOperandRegistryCondition(name string, rt Res
 	r.setCondition(*c)
 }
 
-// setReadyCondition creates a Condition to claim Ready.
-func (r *OperandRequest) setReadyCondition(name string, rt ResourceType, cs corev1.ConditionStatus) {
-	c := &Condition{}
-	if rt == ResourceTypeOperator {
-		c = newCondition(ConditionReady, cs, string(rt)+" is ready", string(rt)+" "+name+" is ready")
-	} else if rt == ResourceTypeOperand {
-		c = newCondition(ConditionReady, cs, string(rt)+" are created", string(rt)+" from "+name+" are created")
-	}
+// SetScheduledCondition creates a Scheduled condition status.
+func (r *OperandRequest) SetScheduledCondition(name string, notBefore time.Time, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	c := newCondition(ConditionScheduled, cs, string(rt)+" "+name+" is scheduled", string(rt)+" "+name+" is scheduled to start at "+notBefore.UTC().Format(time.RFC3339))
 	r.setCondition(*c)
 }
 
-func (r *OperandRequest) setCondition(c Condition) {
-	pos, cp := getCondition(&r.Status.Conditions, c.Type, c.Message)
-	if cp != nil {
-		r.Status.Conditions[pos] = c
-	} else {
-		r.Status.Conditions = append(r.Status.Conditions, c)
-	}
+// SetIncompatibleConsumersCondition creates an IncompatibleConsumers condition status.
+// It replaces the previous IncompatibleConsumers condition of the same resource.
+func (r *OperandRequest) SetIncompatibleConsumersCondition(name, channel string, namespaces, apis []string, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	reason := string(rt) + " " + name + " upgrade is blocked"
+	r.removeCondition(ConditionIncompatibleConsumers, reason)
+	c := newCondition(ConditionIncompatibleConsumers, cs, reason, "The channel "+channel+" of "+string(rt)+" "+name+" removes the APIs "+strings.Join(apis, ", ")+" used by the OperandRequests in the namespaces "+strings.Join(namespaces, ", "))
+	r.setCondition(*c)
 }
 
-func getCondition(conds *[]Condition, t ConditionType, msg string) (int, *Condition) {
-	for i, c := range *conds {
-		if t == c.Type && msg == c.Message {
-			return i, &c
+// RemoveIncompatibleConsumersCondition removes the IncompatibleConsumers condition of the resource.
+func (r *OperandRequest) RemoveIncompatibleConsumersCondition(name string, rt ResourceType, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	r.removeCondition(ConditionIncompatibleConsumers, string(rt)+" "+name+" upgrade is blocked")
+}
+
+// SetReplacesChainBrokenCondition creates a ReplacesChainBroken condition status.
+// It replaces the previous ReplacesChainBroken condition of the same resource, but keeps its LastTransitionTime,
+// which is when the break was detected. It returns the time the break was detected, and true if it is detected now.
+func (r *OperandRequest) SetReplacesChainBrokenCondition(name, channel, installedCSV string, intermediates []string, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) (time.Time, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	reason := string(rt) + " " + name + " replaces chain is broken"
+	message := "The channel " + channel + " of " + string(rt) + " " + name + " doesn't replace the installed CSV " + installedCSV
+	if len(intermediates) != 0 {
+		message += ", upgrade through the channels " + strings.Join(intermediates, ", ") + " first"
+	} else {
+		message += ", no channel of the package has a version in between, upgrade the operator manually"
+	}
+	c := newCondition(ConditionReplacesChainBroken, cs, reason, message)
+	isNew := true
+	for _, previous := range r.Status.Conditions {
+		if previous.Type == ConditionReplacesChainBroken && previous.Reason == reason {
+			c.LastTransitionTime = previous.LastTransitionTime
+			isNew = false
 		}
 	}
-	return -1, nil
-}
+	r.removeCondition(ConditionReplacesChainBroken, reason)
+	r.setCondition(*c)
 
-func newCondition(condType ConditionType, status corev1.ConditionStatus, reason, message string) *Condition {
-	now := time.Now().Format(time.RFC3339)
-	return &Condition{
-		Type:               condType,
-		Status:             status,
-		LastUpdateTime:     now,
-		LastTransitionTime: now,
-		Reason:             reason,
-		Message:            message,
+	detected, err := time.Parse(time.RFC3339, c.LastTransitionTime)
+	if err != nil {
+		return time.Now(), isNew
 	}
+	return detected, isNew
 }
 
-// SetMemberStatus appends a Member status in the Member status list.
-func (r *OperandRequest) SetMemberStatus(name string, operatorPhase OperatorPhase, operandPhase ServicePhase, mu sync.Locker) {
+// RemoveReplacesChainBrokenCondition removes the ReplacesChainBroken condition of the resource.
+func (r *OperandRequest) RemoveReplacesChainBrokenCondition(name string, rt ResourceType, mu sync.Locker) {
 	mu.Lock()
 	defer mu.Unlock()
-	pos, m := getMemberStatus(&r.Status, name)
-	if m != nil {
-		if operatorPhase != "" && operatorPhase != m.Phase.OperatorPhase {
-			r.Status.Members[pos].Phase.OperatorPhase = operatorPhase
-			r.setOperatorReadyCondition(operatorPhase, name)
-		}
-		if operandPhase != "" && operandPhase != m.Phase.OperandPhase {
-			r.Status.Members[pos].Phase.OperandPhase = operandPhase
-			r.setOperandReadyCondition(operandPhase, name)
+	r.removeCondition(ConditionReplacesChainBroken, string(rt)+" "+name+" replaces chain is broken")
+}
+
+// SetThrottledCondition creates a Throttled condition status.
+// It replaces the previous Throttled condition of the same resource.
+func (r *OperandRequest) SetThrottledCondition(name, catalog string, limit int, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	reason := string(rt) + " " + name + " is throttled"
+	r.removeCondition(ConditionThrottled, reason)
+	c := newCondition(ConditionThrottled, cs, reason, "The CatalogSource "+catalog+" is installing "+strconv.Itoa(limit)+" Subscriptions, "+string(rt)+" "+name+" waits for one of them to complete")
+	r.setCondition(*c)
+}
+
+// RemoveThrottledCondition removes the Throttled condition of the resource.
+func (r *OperandRequest) RemoveThrottledCondition(name string, rt ResourceType, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	r.removeCondition(ConditionThrottled, string(rt)+" "+name+" is throttled")
+}
+
+// SetWaitingForDependenciesCondition creates a WaitingForDependencies condition status.
+// It replaces the previous WaitingForDependencies condition of the same resource.
+func (r *OperandRequest) SetWaitingForDependenciesCondition(name, message string, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	reason := string(rt) + " " + name + " is waiting for dependencies"
+	r.removeCondition(ConditionWaitingForDependencies, reason)
+	c := newCondition(ConditionWaitingForDependencies, cs, reason, message)
+	r.setCondition(*c)
+}
+
+// RemoveWaitingForDependenciesCondition removes the WaitingForDependencies condition of the resource.
+func (r *OperandRequest) RemoveWaitingForDependenciesCondition(name string, rt ResourceType, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	r.removeCondition(ConditionWaitingForDependencies, string(rt)+" "+name+" is waiting for dependencies")
+}
+
+// IsWaitingForDependencies returns true if the resource has a WaitingForDependencies condition.
+func (r *OperandRequest) IsWaitingForDependencies(name string, rt ResourceType, mu sync.Locker) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	reason := string(rt) + " " + name + " is waiting for dependencies"
+	for _, c := range r.Status.Conditions {
+		if c.Type == ConditionWaitingForDependencies && c.Reason == reason {
+			return true
 		}
-	} else {
-		newM := newMemberStatus(name, operatorPhase, operandPhase)
-		r.Status.Members = append(r.Status.Members, newM)
-		r.setOperatorReadyCondition(operatorPhase, name)
 	}
+	return false
 }
 
-// SetMemberCRStatus appends a Member CR in the Member status list.
-func (r *OperandRequest) SetMemberCRStatus(name, CRName, CRKind, CRAPIVersion string, mu sync.Locker) {
+// SetWaitingForCRDCondition creates a WaitingForCRD condition status and records the missing kind in the Member status.
+// It replaces the previous WaitingForCRD condition of the same operand.
+func (r *OperandRequest) SetWaitingForCRDCondition(name, gvk string, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) {
 	mu.Lock()
 	defer mu.Unlock()
+	reason := string(rt) + " " + name + " is waiting for the CRD"
+	r.removeCondition(ConditionWaitingForCRD, reason)
+	c := newCondition(ConditionWaitingForCRD, cs, reason, "The CRD of "+gvk+" is not established")
+	r.setCondition(*c)
 	pos, m := getMemberStatus(&r.Status, name)
-	if m != nil {
-		for _, OperandCR := range r.Status.Members[pos].OperandCRList {
-			if OperandCR.Kind == CRKind && OperandCR.Name == CRName {
-				return
-			}
-		}
-		r.Status.Members[pos].OperandCRList = append(r.Status.Members[pos].OperandCRList, OperandCRMember{APIVersion: CRAPIVersion, Kind: CRKind, Name: CRName})
+	if m == nil {
+		r.Status.Members = append(r.Status.Members, newMemberStatus(name, "", ""))
+		pos = len(r.Status.Members) - 1
 	}
+	r.Status.Members[pos].WaitingForCRD = gvk
 }
 
-// RemoveMemberCRStatus removes a Member CR in the Member status list.
-func (r *OperandRequest) RemoveMemberCRStatus(name, CRName, CRKind string, mu sync.Locker) {
+// RemoveWaitingForCRDCondition removes the WaitingForCRD condition of the operand and the missing kind of its Member status.
+func (r *OperandRequest) RemoveWaitingForCRDCondition(name string, rt ResourceType, mu sync.Locker) {
 	mu.Lock()
 	defer mu.Unlock()
-	pos, m := getMemberStatus(&r.Status, name)
-	if m != nil {
-		for index, OperandCR := range r.Status.Members[pos].OperandCRList {
-			if OperandCR.Kind == CRKind && OperandCR.Name == CRName {
-				r.Status.Members[pos].OperandCRList = append(r.Status.Members[pos].OperandCRList[:index], r.Status.Members[pos].OperandCRList[index+1:]...)
-			}
-		}
+	r.removeCondition(ConditionWaitingForCRD, string(rt)+" "+name+" is waiting for the CRD")
+	if pos, m := getMemberStatus(&r.Status, name); m != nil {
+		r.Status.Members[pos].WaitingForCRD = ""
+	}
+}
+
+// IsWaitingForCRD returns true if a custom resource of the operand is waiting for its CRD.
+func (r *OperandRequest) IsWaitingForCRD(name string, mu sync.Locker) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	_, m := getMemberStatus(&r.Status, name)
+	return m != nil && m.WaitingForCRD != ""
+}
+
+// SetExcludedCondition creates an Excluded condition status.
+// It replaces the previous Excluded condition of the same resource.
+func (r *OperandRequest) SetExcludedCondition(name, namespace string, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	reason := string(rt) + " " + name + " is excluded"
+	r.removeCondition(ConditionExcluded, reason)
+	c := newCondition(ConditionExcluded, cs, reason, "The namespace "+namespace+" opts out of ODLM, "+string(rt)+" "+name+" is not installed there")
+	r.setCondition(*c)
+}
+
+// RemoveExcludedCondition removes the Excluded condition of the resource.
+func (r *OperandRequest) RemoveExcludedCondition(name string, rt ResourceType, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	r.removeCondition(ConditionExcluded, string(rt)+" "+name+" is excluded")
+}
+
+// SetBlockedCondition creates a Blocked condition status with the violated ClusterOperandPolicy.
+// It replaces the previous Blocked condition of the same resource, and returns true if the resource wasn't blocked.
+func (r *OperandRequest) SetBlockedCondition(name, message string, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	reason := string(rt) + " " + name + " is blocked"
+	isNew := true
+	for _, previous := range r.Status.Conditions {
+		if previous.Type == ConditionBlocked && previous.Reason == reason {
+			isNew = false
+		}
+	}
+	r.removeCondition(ConditionBlocked, reason)
+	c := newCondition(ConditionBlocked, cs, reason, message)
+	r.setCondition(*c)
+	return isNew
+}
+
+// RemoveBlockedCondition removes the Blocked condition of the resource.
+func (r *OperandRequest) RemoveBlockedCondition(name string, rt ResourceType, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	r.removeCondition(ConditionBlocked, string(rt)+" "+name+" is blocked")
+}
+
+// SetSkipRangeDeniedCondition creates a SkipRangeDenied condition status.
+// It replaces the previous SkipRangeDenied condition of the same resource.
+func (r *OperandRequest) SetSkipRangeDeniedCondition(name, installPlan, from, to string, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	reason := string(rt) + " " + name + " skip range is denied"
+	r.removeCondition(ConditionSkipRangeDenied, reason)
+	c := newCondition(ConditionSkipRangeDenied, cs, reason, "The InstallPlan "+installPlan+" upgrades "+string(rt)+" "+name+" from "+from+" to "+to+" skipping versions, the OperandRegistry doesn't allow the skip range, approve the InstallPlan or set allowSkipRange to upgrade")
+	r.setCondition(*c)
+}
+
+// RemoveSkipRangeDeniedCondition removes the SkipRangeDenied condition of the resource.
+func (r *OperandRequest) RemoveSkipRangeDeniedCondition(name string, rt ResourceType, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	r.removeCondition(ConditionSkipRangeDenied, string(rt)+" "+name+" skip range is denied")
+}
+
+// SetPermissionDeniedCondition creates a PermissionDenied condition status for the OperandBindInfo
+// which doesn't allow the namespace of the request.
+// It replaces the previous PermissionDenied condition of the same resource.
+func (r *OperandRequest) SetPermissionDeniedCondition(name, bindInfo string, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	reason := string(rt) + " " + name + " permission is denied"
+	r.removeCondition(ConditionPermissionDenied, reason)
+	c := newCondition(ConditionPermissionDenied, cs, reason, "The OperandBindInfo "+bindInfo+" doesn't allow the namespace "+r.Namespace+", the Secrets and ConfigMaps of "+string(rt)+" "+name+" are not copied there")
+	r.setCondition(*c)
+}
+
+// RemovePermissionDeniedCondition removes the PermissionDenied condition of the resource.
+func (r *OperandRequest) RemovePermissionDeniedCondition(name string, rt ResourceType, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	r.removeCondition(ConditionPermissionDenied, string(rt)+" "+name+" permission is denied")
+}
+
+// SetOperatorGroupConflictCondition creates an OperatorGroupConflict condition status with the reason
+// OLM can't install the operator in the namespace.
+// It replaces the previous OperatorGroupConflict condition of the same resource.
+func (r *OperandRequest) SetOperatorGroupConflictCondition(name, message string, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	reason := string(rt) + " " + name + " OperatorGroup conflicts"
+	r.removeCondition(ConditionOperatorGroupConflict, reason)
+	c := newCondition(ConditionOperatorGroupConflict, cs, reason, message)
+	r.setCondition(*c)
+}
+
+// RemoveOperatorGroupConflictCondition removes the OperatorGroupConflict condition of the resource.
+func (r *OperandRequest) RemoveOperatorGroupConflictCondition(name string, rt ResourceType, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	r.removeCondition(ConditionOperatorGroupConflict, string(rt)+" "+name+" OperatorGroup conflicts")
+}
+
+// SetCatalogUnhealthyCondition creates a CatalogUnhealthy condition status with the reason
+// OLM can't install the operator from its CatalogSource.
+// It replaces the previous CatalogUnhealthy condition of the same resource, and returns true if the CatalogSource was healthy.
+func (r *OperandRequest) SetCatalogUnhealthyCondition(name, message string, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	reason := string(rt) + " " + name + " catalog is unhealthy"
+	isNew := true
+	for _, previous := range r.Status.Conditions {
+		if previous.Type == ConditionCatalogUnhealthy && previous.Reason == reason {
+			isNew = false
+		}
+	}
+	r.removeCondition(ConditionCatalogUnhealthy, reason)
+	c := newCondition(ConditionCatalogUnhealthy, cs, reason, message)
+	r.setCondition(*c)
+	return isNew
+}
+
+// RemoveCatalogUnhealthyCondition removes the CatalogUnhealthy condition of the resource.
+func (r *OperandRequest) RemoveCatalogUnhealthyCondition(name string, rt ResourceType, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	r.removeCondition(ConditionCatalogUnhealthy, string(rt)+" "+name+" catalog is unhealthy")
+}
+
+// SetVersionPinnedCondition creates a VersionPinned condition status for the InstallPlan installing another version
+// than the pinned ones. It replaces the previous VersionPinned condition of the same resource, and returns true if
+// the condition is new or the InstallPlan changed.
+func (r *OperandRequest) SetVersionPinnedCondition(name, installPlan, csv, pinned string, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	reason := string(rt) + " " + name + " version is pinned"
+	message := "The InstallPlan " + installPlan + " installs " + csv + " of " + string(rt) + " " + name + " past the pinned version " + pinned + ", lift the pin to upgrade"
+	isNew := true
+	for _, previous := range r.Status.Conditions {
+		if previous.Type == ConditionVersionPinned && previous.Reason == reason && previous.Message == message {
+			isNew = false
+		}
+	}
+	r.removeCondition(ConditionVersionPinned, reason)
+	c := newCondition(ConditionVersionPinned, cs, reason, message)
+	r.setCondition(*c)
+	return isNew
+}
+
+// RemoveVersionPinnedCondition removes the VersionPinned condition of the resource.
+func (r *OperandRequest) RemoveVersionPinnedCondition(name string, rt ResourceType, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	r.removeCondition(ConditionVersionPinned, string(rt)+" "+name+" version is pinned")
+}
+
+// SetUpgradeBlockedCondition creates an UpgradeBlocked condition status for the channel which has no upgrade path
+// from the installed CSV, with the missing edge and the intermediate channels to upgrade through.
+// It replaces the previous UpgradeBlocked condition of the same resource, and returns true if the condition is new.
+func (r *OperandRequest) SetUpgradeBlockedCondition(name, channel, installedCSV, oldestCSV string, intermediates []string, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	reason := string(rt) + " " + name + " upgrade is blocked"
+	message := "The channel " + channel + " of " + string(rt) + " " + name + " has no upgrade path from the installed CSV " + installedCSV + ", the edge " + installedCSV + " -> " + oldestCSV + " is missing"
+	if len(intermediates) != 0 {
+		message += ", upgrade through the channels " + strings.Join(intermediates, ", ") + " first or set autoStepChannels"
+	} else {
+		message += ", no channel of the package has a version in between, upgrade the operator manually"
+	}
+	isNew := true
+	for _, previous := range r.Status.Conditions {
+		if previous.Type == ConditionUpgradeBlocked && previous.Reason == reason && previous.Message == message {
+			isNew = false
+		}
+	}
+	r.removeCondition(ConditionUpgradeBlocked, reason)
+	c := newCondition(ConditionUpgradeBlocked, cs, reason, message)
+	r.setCondition(*c)
+	return isNew
+}
+
+// RemoveUpgradeBlockedCondition removes the UpgradeBlocked condition of the resource.
+func (r *OperandRequest) RemoveUpgradeBlockedCondition(name string, rt ResourceType, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	r.removeCondition(ConditionUpgradeBlocked, string(rt)+" "+name+" upgrade is blocked")
+}
+
+// IsBlocked returns true if an operator of the request is blocked by a ClusterOperandPolicy.
+func (r *OperandRequest) IsBlocked() bool {
+	for _, c := range r.Status.Conditions {
+		if c.Type == ConditionBlocked {
+			return true
+		}
+	}
+	return false
+}
+
+// SetDriftedCondition creates a Drifted condition status.
+// It replaces the previous Drifted condition of the same resource.
+func (r *OperandRequest) SetDriftedCondition(name string, fields []string, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	reason := string(rt) + " " + name + " is drifted"
+	r.removeCondition(ConditionDrifted, reason)
+	c := newCondition(ConditionDrifted, cs, reason, "The fields "+strings.Join(fields, ", ")+" of "+string(rt)+" "+name+" were changed and differ from the configuration")
+	r.setCondition(*c)
+}
+
+// RemoveDriftedCondition removes the Drifted condition of the resource.
+func (r *OperandRequest) RemoveDriftedCondition(name string, rt ResourceType, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	r.removeCondition(ConditionDrifted, string(rt)+" "+name+" is drifted")
+}
+
+// SetConflictCondition creates a Conflict condition status.
+func (r *OperandRequest) SetConflictCondition(name string, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	c := newCondition(ConditionConflict, cs, string(rt)+" "+name+" already exists", string(rt)+" "+name+" already exists and isn't created by ODLM, annotate it with odlm.ibm.com/adopt: \"true\" to adopt it")
+	r.setCondition(*c)
+}
+
+// RemoveConflictCondition removes the Conflict condition of the resource.
+func (r *OperandRequest) RemoveConflictCondition(name string, rt ResourceType, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	r.removeCondition(ConditionConflict, string(rt)+" "+name+" already exists")
+}
+
+// SetPausedCondition creates a Paused condition status.
+func (r *OperandRequest) SetPausedCondition(name string, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	c := newCondition(ConditionPaused, cs, string(rt)+" "+name+" is paused", "The reconcile of "+string(rt)+" "+name+" is paused by the annotation "+PausedOperandsAnnotation)
+	r.setCondition(*c)
+}
+
+// RemovePausedCondition removes the Paused condition of the resource.
+func (r *OperandRequest) RemovePausedCondition(name string, rt ResourceType, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	r.removeCondition(ConditionPaused, string(rt)+" "+name+" is paused")
+}
+
+// SetUnhealthyCondition creates an Unhealthy condition status.
+// It replaces the previous Unhealthy condition of the same resource.
+func (r *OperandRequest) SetUnhealthyCondition(name, message string, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	reason := string(rt) + " " + name + " is unhealthy"
+	r.removeCondition(ConditionUnhealthy, reason)
+	c := newCondition(ConditionUnhealthy, cs, reason, message)
+	r.setCondition(*c)
+}
+
+// RemoveUnhealthyCondition removes the Unhealthy condition of the resource.
+func (r *OperandRequest) RemoveUnhealthyCondition(name string, rt ResourceType, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	r.removeCondition(ConditionUnhealthy, string(rt)+" "+name+" is unhealthy")
+}
+
+// SetVerificationFailedCondition creates a VerificationFailed condition status.
+// It replaces the previous VerificationFailed condition of the same resource.
+func (r *OperandRequest) SetVerificationFailedCondition(name, message string, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	reason := string(rt) + " " + name + " verification failed"
+	r.removeCondition(ConditionVerificationFailed, reason)
+	c := newCondition(ConditionVerificationFailed, cs, reason, message)
+	r.setCondition(*c)
+}
+
+// RemoveVerificationFailedCondition removes the VerificationFailed condition of the resource.
+func (r *OperandRequest) RemoveVerificationFailedCondition(name string, rt ResourceType, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	r.removeCondition(ConditionVerificationFailed, string(rt)+" "+name+" verification failed")
+}
+
+// SetSchemaValidationFailedCondition creates a SchemaValidationFailed condition status with the invalid fields.
+// It replaces the previous SchemaValidationFailed condition of the same resource.
+func (r *OperandRequest) SetSchemaValidationFailedCondition(name, message string, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	reason := string(rt) + " " + name + " doesn't match the schema"
+	r.removeCondition(ConditionSchemaValidationFailed, reason)
+	c := newCondition(ConditionSchemaValidationFailed, cs, reason, message)
+	r.setCondition(*c)
+}
+
+// RemoveSchemaValidationFailedCondition removes the SchemaValidationFailed condition of the resource.
+func (r *OperandRequest) RemoveSchemaValidationFailedCondition(name string, rt ResourceType, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	r.removeCondition(ConditionSchemaValidationFailed, string(rt)+" "+name+" doesn't match the schema")
+}
+
+// SetManifestsTooLargeCondition creates a ManifestsTooLarge condition status, the manifests of the operator
+// don't fit in the ConfigMap caching them. It replaces the previous ManifestsTooLarge condition of the same resource.
+func (r *OperandRequest) SetManifestsTooLargeCondition(name, message string, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	reason := string(rt) + " " + name + " has too large manifests"
+	r.removeCondition(ConditionManifestsTooLarge, reason)
+	c := newCondition(ConditionManifestsTooLarge, cs, reason, message)
+	r.setCondition(*c)
+}
+
+// RemoveManifestsTooLargeCondition removes the ManifestsTooLarge condition of the resource.
+func (r *OperandRequest) RemoveManifestsTooLargeCondition(name string, rt ResourceType, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	r.removeCondition(ConditionManifestsTooLarge, string(rt)+" "+name+" has too large manifests")
+}
+
+// SetLicenseRequiredCondition creates a LicenseRequired condition status with the missing entitlement.
+// It replaces the previous LicenseRequired condition of the same resource, and returns true if the resource wasn't waiting for its license.
+func (r *OperandRequest) SetLicenseRequiredCondition(name, message string, rt ResourceType, cs corev1.ConditionStatus, mu sync.Locker) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	reason := string(rt) + " " + name + " requires a license"
+	isNew := true
+	for _, previous := range r.Status.Conditions {
+		if previous.Type == ConditionLicenseRequired && previous.Reason == reason {
+			isNew = false
+		}
+	}
+	r.removeCondition(ConditionLicenseRequired, reason)
+	c := newCondition(ConditionLicenseRequired, cs, reason, message)
+	r.setCondition(*c)
+	return isNew
+}
+
+// RemoveLicenseRequiredCondition removes the LicenseRequired condition of the resource.
+func (r *OperandRequest) RemoveLicenseRequiredCondition(name string, rt ResourceType, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	r.removeCondition(ConditionLicenseRequired, string(rt)+" "+name+" requires a license")
+}
+
+// removeCondition removes the conditions of the type with the reason.
+
+func (r *OperandRequest) removeCondition(t ConditionType, reason string) {
+	var conds []Condition
+	for _, c := range r.Status.Conditions {
+		if c.Type != t || c.Reason != reason {
+			conds = append(conds, c)
+		}
+	}
+	r.Status.Conditions = conds
+}
+
+// setReadyCondition creates a Condition to claim Ready.
+func (r *OperandRequest) setReadyCondition(name string, rt ResourceType, cs corev1.ConditionStatus) {
+	c := &Condition{}
+	if rt == ResourceTypeOperator {
+		c = newCondition(ConditionReady, cs, string(rt)+" is ready", string(rt)+" "+name+" is ready")
+	} else if rt == ResourceTypeOperand {
+		c = newCondition(ConditionReady, cs, string(rt)+" are created", string(rt)+" from "+name+" are created")
+	}
+	r.setCondition(*c)
+}
+
+// TruncateStatus trims the conditions and the history of the OperandRequest to the latest ones, drops the generated
+// reports and cuts the long messages. All the members are kept with their phases, the reconcile relies on them
+// to tell the operands removed from the spec and the ones already installed, and only the custom resources
+// of the running members are left out.
+// It adds a Truncated condition pointing to the ConfigMap holding the full status.
+func (r *OperandRequest) TruncateStatus(configMapName string) {
+	conds := r.Status.Conditions
+	sort.SliceStable(conds, func(i, j int) bool {
+		return conds[i].LastUpdateTime > conds[j].LastUpdateTime
+	})
+	if len(conds) > MaxConditionsAfterTruncation {
+		conds = conds[:MaxConditionsAfterTruncation]
+	}
+	for i := range conds {
+		conds[i].Message = truncateMessage(conds[i].Message)
+	}
+	r.Status.Conditions = conds
+
+	omitted := 0
+	for i := range r.Status.Members {
+		m := &r.Status.Members[i]
+		if m.Phase.OperandPhase == ServiceRunning && len(m.OperandCRList) != 0 {
+			m.OperandCRList = nil
+			omitted++
+		}
+		m.Upgrade = nil
+		if m.Retry != nil {
+			m.Retry.LastError = truncateMessage(m.Retry.LastError)
+		}
+		if m.HelmRelease != nil {
+			m.HelmRelease.Description = truncateMessage(m.HelmRelease.Description)
+		}
+	}
+
+	if len(r.Status.History) > MaxHistoryAfterTruncation {
+		r.Status.History = append([]HistoryEntry(nil), r.Status.History[len(r.Status.History)-MaxHistoryAfterTruncation:]...)
+	}
+	r.Status.EffectiveConfig = nil
+	r.Status.DriftDiff = nil
+	r.Status.DeletionPreview = nil
+
+	message := "The status exceeds the size limit, the full status is saved in ConfigMap " + configMapName
+	if omitted > 0 {
+		message += ", the custom resources of " + strconv.Itoa(omitted) + " running members are left out"
+	}
+	c := newCondition(ConditionTruncated, corev1.ConditionTrue, "Status is truncated", message)
+	r.setCondition(*c)
+}
+
+// truncateMessage cuts the message to MaxMessageLengthAfterTruncation bytes, without splitting a character
+func truncateMessage(message string) string {
+	if len(message) <= MaxMessageLengthAfterTruncation {
+		return message
+	}
+	end := MaxMessageLengthAfterTruncation
+	for end > 0 && !utf8.RuneStart(message[end]) {
+		end--
+	}
+	return message[:end] + "..."
+}
+
+// UpdateSummaryConditions sets the Reconciling and the Stalled conditions summarizing the OperandRequest,
+// and its ObservedGeneration when the reconcile succeeded. They follow the conventions of kstatus, which
+// the GitOps controllers, like Argo CD and Flux, use to tell the health of a resource: the OperandRequest
+// is Reconciling until all its members are running, and Stalled when one of them failed.
+func (r *OperandRequest) UpdateSummaryConditions(succeeded bool) {
+	if succeeded {
+		r.Status.ObservedGeneration = r.Generation
+	}
+
+	var failed []string
+	for _, m := range r.Status.Members {
+		if m.Phase.OperatorPhase == OperatorFailed || m.Phase.OperandPhase == ServiceFailed {
+			failed = append(failed, m.Name)
+		}
+	}
+	sort.Strings(failed)
+
+	stalled := newCondition(ConditionStalled, corev1.ConditionFalse, "NoFailure", "No member of the OperandRequest failed")
+	if len(failed) != 0 {
+		stalled = newCondition(ConditionStalled, corev1.ConditionTrue, "MemberFailed", "The members "+strings.Join(failed, ", ")+" failed")
+	}
+
+	reconciling := newCondition(ConditionReconciling, corev1.ConditionFalse, "Reconciled", "The OperandRequest is reconciled")
+	switch {
+	case !succeeded:
+		reconciling = newCondition(ConditionReconciling, corev1.ConditionTrue, "ReconcileFailed", "The reconcile of the OperandRequest failed, it is retried")
+	case r.Status.ObservedGeneration != r.Generation:
+		reconciling = newCondition(ConditionReconciling, corev1.ConditionTrue, "NewGeneration", "The generation "+strconv.FormatInt(r.Generation, 10)+" of the OperandRequest isn't reconciled yet")
+	case r.Status.Phase != ClusterPhaseRunning && len(r.Status.Members) != 0 && len(failed) == 0:
+		reconciling = newCondition(ConditionReconciling, corev1.ConditionTrue, "Progressing", "The OperandRequest is "+string(r.Status.Phase))
+	}
+
+	for _, c := range []*Condition{reconciling, stalled} {
+		r.removeConditionType(c.Type)
+		r.setCondition(*c)
+	}
+}
+
+// StabilizeConditions keeps the times and the order of the conditions unchanged since the previous status,
+// so a reconcile which changes nothing doesn't update the status. The status-only updates would otherwise
+// refresh the OperandRequest at each reconcile, and the GitOps controllers watching it with it.
+func (r *OperandRequest) StabilizeConditions(previous []Condition) {
+	index := make(map[Condition]int, len(previous))
+	for i, p := range previous {
+		index[conditionKey(p)] = i
+	}
+	for i, c := range r.Status.Conditions {
+		pos, ok := index[conditionKey(c)]
+		if !ok {
+			// The transition time is kept when only the reason of the condition changed
+			for _, p := range previous {
+				if p.Type == c.Type && p.Message == c.Message && p.Status == c.Status {
+					r.Status.Conditions[i].LastTransitionTime = p.LastTransitionTime
+				}
+			}
+			continue
+		}
+		r.Status.Conditions[i].LastUpdateTime = previous[pos].LastUpdateTime
+		r.Status.Conditions[i].LastTransitionTime = previous[pos].LastTransitionTime
+	}
+
+	// The conditions set again keep their previous position, the new ones follow them
+	position := func(c Condition) int {
+		if pos, ok := index[conditionKey(c)]; ok {
+			return pos
+		}
+		return len(previous)
+	}
+	sort.SliceStable(r.Status.Conditions, func(i, j int) bool {
+		return position(r.Status.Conditions[i]) < position(r.Status.Conditions[j])
+	})
+}
+
+// conditionKey is the condition without its times
+func conditionKey(c Condition) Condition {
+	c.LastUpdateTime = ""
+	c.LastTransitionTime = ""
+	return c
+}
+
+// removeConditionType removes the conditions of the type.
+func (r *OperandRequest) removeConditionType(t ConditionType) {
+	var conds []Condition
+	for _, c := range r.Status.Conditions {
+		if c.Type != t {
+			conds = append(conds, c)
+		}
+	}
+	r.Status.Conditions = conds
+}
+
+func (r *OperandRequest) setCondition(c Condition) {
+	pos, cp := getCondition(&r.Status.Conditions, c.Type, c.Message)
+	if cp != nil {
+		r.Status.Conditions[pos] = c
+	} else {
+		r.Status.Conditions = append(r.Status.Conditions, c)
+	}
+}
+
+func getCondition(conds *[]Condition, t ConditionType, msg string) (int, *Condition) {
+	for i, c := range *conds {
+		if t == c.Type && msg == c.Message {
+			return i, &c
+		}
+	}
+	return -1, nil
+}
+
+func newCondition(condType ConditionType, status corev1.ConditionStatus, reason, message string) *Condition {
+	now := time.Now().Format(time.RFC3339)
+	return &Condition{
+		Type:               condType,
+		Status:             status,
+		LastUpdateTime:     now,
+		LastTransitionTime: now,
+		Reason:             reason,
+		Message:            message,
+	}
+}
+
+// SetMemberStatus appends a Member status in the Member status list.
+func (r *OperandRequest) SetMemberStatus(name string, operatorPhase OperatorPhase, operandPhase ServicePhase, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	pos, m := getMemberStatus(&r.Status, name)
+	if m != nil {
+		if operatorPhase != "" && operatorPhase != m.Phase.OperatorPhase {
+			r.Status.Members[pos].Phase.OperatorPhase = operatorPhase
+			r.setOperatorReadyCondition(operatorPhase, name)
+			if operatorPhase == OperatorFailed {
+				r.addHistory(HistoryEntry{Action: HistoryMemberFailed, Operator: name, From: string(m.Phase.OperatorPhase), To: string(operatorPhase), Message: "The operator failed"})
+			}
+		}
+		if operandPhase != "" && operandPhase != m.Phase.OperandPhase {
+			r.Status.Members[pos].Phase.OperandPhase = operandPhase
+			r.setOperandReadyCondition(operandPhase, name)
+			if operandPhase == ServiceFailed {
+				r.addHistory(HistoryEntry{Action: HistoryMemberFailed, Operator: name, From: string(m.Phase.OperandPhase), To: string(operandPhase), Message: "The operand failed"})
+			}
+		}
+	} else {
+		newM := newMemberStatus(name, operatorPhase, operandPhase)
+		r.Status.Members = append(r.Status.Members, newM)
+		r.setOperatorReadyCondition(operatorPhase, name)
+		if operatorPhase == OperatorFailed {
+			r.addHistory(HistoryEntry{Action: HistoryMemberFailed, Operator: name, To: string(operatorPhase), Message: "The operator failed"})
+		}
+	}
+}
+
+// AddHistory records an action in the history of the OperandRequest, at the current time.
+// The oldest entries are dropped beyond MaxHistoryEntries.
+func (r *OperandRequest) AddHistory(entry HistoryEntry, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	r.addHistory(entry)
+}
+
+func (r *OperandRequest) addHistory(entry HistoryEntry) {
+	if entry.Time == "" {
+		entry.Time = time.Now().Format(time.RFC3339)
+	}
+	r.Status.History = append(r.Status.History, entry)
+	if len(r.Status.History) > MaxHistoryEntries {
+		r.Status.History = append([]HistoryEntry(nil), r.Status.History[len(r.Status.History)-MaxHistoryEntries:]...)
+	}
+}
+
+// SetMemberCRStatus appends a Member CR in the Member status list.
+func (r *OperandRequest) SetMemberCRStatus(name, CRName, CRKind, CRAPIVersion string, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	pos, m := getMemberStatus(&r.Status, name)
+	if m != nil {
+		for _, OperandCR := range r.Status.Members[pos].OperandCRList {
+			if OperandCR.Kind == CRKind && OperandCR.Name == CRName {
+				return
+			}
+		}
+		r.Status.Members[pos].OperandCRList = append(r.Status.Members[pos].OperandCRList, OperandCRMember{APIVersion: CRAPIVersion, Kind: CRKind, Name: CRName})
+	}
+}
+
+// SetMemberHelmReleaseStatus sets the Helm release status of a Member in the Member status list.
+func (r *OperandRequest) SetMemberHelmReleaseStatus(name string, release *HelmReleaseStatus, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	pos, m := getMemberStatus(&r.Status, name)
+	if m != nil {
+		r.Status.Members[pos].HelmRelease = release
+	}
+}
+
+// SetImplicitMembers records the operands each implicit member is required by, and clears it from the other members.
+func (r *OperandRequest) SetImplicitMembers(requiredBy map[string][]string, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	for i := range r.Status.Members {
+		r.Status.Members[i].RequiredBy = nil
+	}
+	names := make([]string, 0, len(requiredBy))
+	for name := range requiredBy {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		pos, m := getMemberStatus(&r.Status, name)
+		if m == nil {
+			r.Status.Members = append(r.Status.Members, newMemberStatus(name, "", ""))
+			pos = len(r.Status.Members) - 1
+		}
+		r.Status.Members[pos].RequiredBy = requiredBy[name]
+	}
+}
+
+// RemoveMemberCRStatus removes a Member CR in the Member status list.
+func (r *OperandRequest) RemoveMemberCRStatus(name, CRName, CRKind string, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	pos, m := getMemberStatus(&r.Status, name)
+	if m != nil {
+		for index, OperandCR := range r.Status.Members[pos].OperandCRList {
+			if OperandCR.Kind == CRKind && OperandCR.Name == CRName {
+				r.Status.Members[pos].OperandCRList = append(r.Status.Members[pos].OperandCRList[:index], r.Status.Members[pos].OperandCRList[index+1:]...)
+			}
+		}
 	}
 }
 
@@ -387,11 +1623,101 @@ func (r *OperandRequest) setOperandReadyCondition(operandPhase ServicePhase, nam
 	}
 }
 
+// SetMemberUpgrade records the last upgrade of the operator in the Member status.
+func (r *OperandRequest) SetMemberUpgrade(name, from, to, path string, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	pos, m := getMemberStatus(&r.Status, name)
+	if m == nil {
+		r.Status.Members = append(r.Status.Members, newMemberStatus(name, "", ""))
+		pos = len(r.Status.Members) - 1
+	}
+	r.Status.Members[pos].Upgrade = &UpgradeStatus{From: from, To: to, Path: path}
+}
+
+// GetMemberRetries returns the number of the reconciles of the member which failed in a row.
+func (r *OperandRequest) GetMemberRetries(name string, mu sync.Locker) int {
+	mu.Lock()
+	defer mu.Unlock()
+	_, m := getMemberStatus(&r.Status, name)
+	if m == nil || m.Retry == nil {
+		return 0
+	}
+	return m.Retry.Retries
+}
+
+// SetMemberRetry records the failed reconcile of the member and when it is reconciled again in the Member status.
+func (r *OperandRequest) SetMemberRetry(name string, retries int, lastError string, nextRetry time.Time, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	pos, m := getMemberStatus(&r.Status, name)
+	if m == nil {
+		r.Status.Members = append(r.Status.Members, newMemberStatus(name, "", ""))
+		pos = len(r.Status.Members) - 1
+	}
+	r.Status.Members[pos].Retry = &MemberRetry{
+		Retries:       retries,
+		LastError:     lastError,
+		NextRetryTime: nextRetry.UTC().Format(time.RFC3339),
+	}
+}
+
+// ResetMemberRetry removes the retries of the member from the Member status, once its reconcile succeeds.
+func (r *OperandRequest) ResetMemberRetry(name string, mu sync.Locker) {
+	mu.Lock()
+	defer mu.Unlock()
+	if pos, m := getMemberStatus(&r.Status, name); m != nil {
+		r.Status.Members[pos].Retry = nil
+	}
+}
+
+// ResetMemberRetries removes the retries of all the members, to reconcile them right away.
+func (r *OperandRequest) ResetMemberRetries() {
+	for i := range r.Status.Members {
+		r.Status.Members[i].Retry = nil
+	}
+}
+
+// GetMemberRetryWait returns how long the member waits until it is reconciled again.
+// It returns 0 if the member isn't backing off.
+func (r *OperandRequest) GetMemberRetryWait(name string, now time.Time, mu sync.Locker) time.Duration {
+	mu.Lock()
+	defer mu.Unlock()
+	_, m := getMemberStatus(&r.Status, name)
+	if m == nil {
+		return 0
+	}
+	return m.retryWait(now)
+}
+
+// GetRetryWait returns how long to wait until the next member backing off is reconciled again.
+// It returns 0 if no member is backing off.
+func (r *OperandRequest) GetRetryWait(now time.Time) time.Duration {
+	var wait time.Duration
+	for i := range r.Status.Members {
+		if w := r.Status.Members[i].retryWait(now); w > 0 && (wait == 0 || w < wait) {
+			wait = w
+		}
+	}
+	return wait
+}
+
+func (m *MemberStatus) retryWait(now time.Time) time.Duration {
+	if m.Retry == nil || m.Retry.NextRetryTime == "" {
+		return 0
+	}
+	next, err := time.Parse(time.RFC3339, m.Retry.NextRetryTime)
+	if err != nil || !now.Before(next) {
+		return 0
+	}
+	return next.Sub(now)
+}
+
 // FreshMemberStatus cleanup Member status from the Member status list.
 func (r *OperandRequest) FreshMemberStatus() {
 	newMembers := []MemberStatus{}
 	for index, m := range r.Status.Members {
-		if foundOperand(r.Spec.Requests, m.Name) {
+		if foundOperand(r.Spec.Requests, m.Name) || len(m.RequiredBy) != 0 {
 			newMembers = append(newMembers, r.Status.Members[index])
 		}
 	}
@@ -441,11 +1767,13 @@ func (r *OperandRequest) UpdateClusterPhase() {
 		runningNum    int
 		installingNum int
 		failedNum     int
+		scheduledNum  int
 	}{
 		creatingNum:   0,
 		runningNum:    0,
 		installingNum: 0,
 		failedNum:     0,
+		scheduledNum:  0,
 	}
 
 	for _, m := range r.Status.Members {
@@ -460,12 +1788,16 @@ func (r *OperandRequest) UpdateClusterPhase() {
 			clusterStatusStat.installingNum++
 		case OperatorUpdating:
 			clusterStatusStat.installingNum++
+		case OperatorScheduled:
+			clusterStatusStat.scheduledNum++
 		default:
 		}
 
 		switch m.Phase.OperandPhase {
 		case ServiceRunning:
 			clusterStatusStat.runningNum++
+		case ServiceCreating:
+			clusterStatusStat.creatingNum++
 		case ServiceFailed:
 			clusterStatusStat.failedNum++
 		default:
@@ -479,6 +1811,8 @@ func (r *OperandRequest) UpdateClusterPhase() {
 		clusterPhase = ClusterPhaseInstalling
 	} else if clusterStatusStat.creatingNum > 0 {
 		clusterPhase = ClusterPhaseCreating
+	} else if clusterStatusStat.scheduledNum > 0 {
+		clusterPhase = ClusterPhaseScheduled
 	} else if clusterStatusStat.runningNum > 0 {
 		clusterPhase = ClusterPhaseRunning
 	} else {
@@ -487,6 +1821,141 @@ func (r *OperandRequest) UpdateClusterPhase() {
 	r.SetClusterPhase(clusterPhase)
 }
 
+// SetPlacementStatus sets the status of the request in the managed clusters.
+// Then summarize the cluster phase of the OperandRequest from them.
+func (r *OperandRequest) SetPlacementStatus(statuses []ClusterPlacementStatus) {
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Cluster < statuses[j].Cluster
+	})
+	r.Status.Placement = statuses
+
+	var failedNum, runningNum int
+	for _, s := range statuses {
+		switch s.Phase {
+		case ClusterPhaseFailed:
+			failedNum++
+		case ClusterPhaseRunning:
+			runningNum++
+		default:
+		}
+	}
+
+	var clusterPhase ClusterPhase
+	if failedNum > 0 {
+		clusterPhase = ClusterPhaseFailed
+	} else if len(statuses) == 0 {
+		clusterPhase = ClusterPhaseNone
+	} else if runningNum == len(statuses) {
+		clusterPhase = ClusterPhaseRunning
+	} else {
+		clusterPhase = ClusterPhaseInstalling
+	}
+	r.SetClusterPhase(clusterPhase)
+}
+
+// GetType returns the way the request is distributed to the managed clusters
+func (p *OperandPlacement) GetType() PlacementType {
+	if p.Type == "" {
+		return PlacementTypeManifestWork
+	}
+	return p.Type
+}
+
+// GetUses returns the APIs of the operand the request depends on.
+// They include the API of the custom resource created by the operand.
+func (o *Operand) GetUses() []OperandUse {
+	uses := o.Uses
+	if o.APIVersion != "" && o.Kind != "" {
+		uses = append(append([]OperandUse{}, uses...), OperandUse{APIVersion: o.APIVersion, Kind: o.Kind})
+	}
+	return uses
+}
+
+// GetNotBefore returns the time before which the operand must not be installed or updated.
+// It returns the zero time if the operand is not scheduled.
+func (r *OperandRequest) GetNotBefore(operand Operand) time.Time {
+	var notBefore time.Time
+	if r.Spec.NotBefore != nil {
+		notBefore = r.Spec.NotBefore.Time
+	}
+	if operand.NotBefore != nil && operand.NotBefore.After(notBefore) {
+		notBefore = operand.NotBefore.Time
+	}
+	return notBefore
+}
+
+// IsPaused returns true if the reconcile of the operand is paused by the PausedOperandsAnnotation.
+func (r *OperandRequest) IsPaused(operandName string) bool {
+	return r.isAnnotatedOperand(PausedOperandsAnnotation, operandName)
+}
+
+// IsMigrated returns true if the coexisting operand is migrated to its new version by the MigratedOperandsAnnotation.
+func (r *OperandRequest) IsMigrated(operandName string) bool {
+	return r.isAnnotatedOperand(MigratedOperandsAnnotation, operandName)
+}
+
+// isAnnotatedOperand returns true if the operand is in the comma-separated list of the annotation, or the list is "*".
+func (r *OperandRequest) isAnnotatedOperand(annotation, operandName string) bool {
+	for _, name := range strings.Split(r.GetAnnotations()[annotation], ",") {
+		name = strings.TrimSpace(name)
+		if name == "*" || (name != "" && name == operandName) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetDeletionPolicy returns the deletion policy of the operand with default value.
+func (o *Operand) GetDeletionPolicy() string {
+	if o.DeletionPolicy == "" {
+		return DeletionPolicyDelete
+	}
+	return o.DeletionPolicy
+}
+
+// GetPrunePolicy returns the prune policy of the operands removed from the requests with default value.
+func (r *OperandRequest) GetPrunePolicy() string {
+	if r.Spec.PrunePolicy == "" {
+		return PrunePolicyDelete
+	}
+	return r.Spec.PrunePolicy
+}
+
+// GetTeardownDeadline returns the time until which ODLM waits for the custom resources of the deleted OperandRequest
+// to be removed. It returns the zero time if the OperandRequest isn't deleted.
+func (r *OperandRequest) GetTeardownDeadline() time.Time {
+	if r.DeletionTimestamp == nil {
+		return time.Time{}
+	}
+	gracePeriod := DefaultTeardownGracePeriod
+	if r.Spec.TeardownGracePeriod != nil {
+		gracePeriod = r.Spec.TeardownGracePeriod.Duration
+	}
+	return r.DeletionTimestamp.Add(gracePeriod)
+}
+
+// IsScheduled returns true if the operand must wait until its NotBefore time.
+func (r *OperandRequest) IsScheduled(operand Operand, now time.Time) bool {
+	return now.Before(r.GetNotBefore(operand))
+}
+
+// GetScheduledWait returns how long to wait until the next scheduled operand of the request can start.
+// It returns 0 if no operand is scheduled.
+func (r *OperandRequest) GetScheduledWait(now time.Time) time.Duration {
+	var wait time.Duration
+	for _, req := range r.Spec.Requests {
+		for _, operand := range req.Operands {
+			if !r.IsScheduled(operand, now) {
+				continue
+			}
+			if w := r.GetNotBefore(operand).Sub(now); wait == 0 || w < wait {
+				wait = w
+			}
+		}
+	}
+	return wait
+}
+
 // GetRegistryKey Set the default value for Request spec.
 func (r *OperandRequest) GetRegistryKey(req Request) types.NamespacedName {
 	regName := req.Registry
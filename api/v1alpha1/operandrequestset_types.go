@@ -0,0 +1,147 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// OperandRequestSetSpec defines the desired state of OperandRequestSet.
+type OperandRequestSetSpec struct {
+	// NamespaceSelector selects the namespaces ODLM creates the OperandRequest in.
+	// The namespaces excluded from ODLM are skipped.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector"`
+	// Template is the OperandRequest created in each selected namespace, with the name of the OperandRequestSet.
+	Template OperandRequestTemplate `json:"template"`
+}
+
+// OperandRequestTemplate defines the OperandRequests created by an OperandRequestSet.
+type OperandRequestTemplate struct {
+	// Labels are added to the OperandRequests.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are added to the OperandRequests.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Spec is the spec of the OperandRequests. The registryNamespace of the requests must be set,
+	// otherwise it is the namespace of each OperandRequest.
+	Spec OperandRequestSpec `json:"spec"`
+}
+
+// OperandRequestSetMember is the OperandRequest of an OperandRequestSet in a namespace.
+type OperandRequestSetMember struct {
+	// Namespace is the namespace of the OperandRequest.
+	Namespace string `json:"namespace"`
+	// Phase is the phase of the OperandRequest.
+	// +optional
+	Phase ClusterPhase `json:"phase,omitempty"`
+	// Message tells why the OperandRequest can't be created.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// OperandRequestSetStatus defines the observed state of OperandRequestSet.
+type OperandRequestSetStatus struct {
+	// ObservedGeneration is the generation of the OperandRequestSet applied to the OperandRequests.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Phase is the aggregated phase of the OperandRequests. It is Running when they are all running.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Phase",xDescriptors="urn:alm:descriptor:io.kubernetes.phase"
+	// +optional
+	Phase ClusterPhase `json:"phase,omitempty"`
+	// Namespaces is the number of selected namespaces.
+	// +optional
+	Namespaces int32 `json:"namespaces,omitempty"`
+	// Ready is the number of running OperandRequests.
+	// +optional
+	Ready int32 `json:"ready,omitempty"`
+	// Members are the OperandRequests of the selected namespaces, sorted by namespace.
+	// +optional
+	Members []OperandRequestSetMember `json:"members,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// OperandRequestSet is the Schema for the operandrequestsets API.
+// It creates the same OperandRequest in every namespace matching its namespace selector.
+// +kubebuilder:resource:path=operandrequestsets,shortName=opreqset,scope=Cluster
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=.status.phase,description="Aggregated phase of the OperandRequests"
+// +kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=.status.ready,description="Number of running OperandRequests"
+// +kubebuilder:printcolumn:name="Namespaces",type=integer,JSONPath=.status.namespaces,description="Number of selected namespaces"
+// +kubebuilder:printcolumn:name="Created At",type=string,JSONPath=.metadata.creationTimestamp
+// +operator-sdk:csv:customresourcedefinitions:displayName="OperandRequestSet"
+type OperandRequestSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperandRequestSetSpec   `json:"spec,omitempty"`
+	Status OperandRequestSetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperandRequestSetList contains a list of OperandRequestSet.
+type OperandRequestSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperandRequestSet `json:"items"`
+}
+
+// requestSetPhaseOrder is the order of the phases aggregated in the OperandRequestSet, the first one found wins
+var requestSetPhaseOrder = []ClusterPhase{
+	ClusterPhaseFailed,
+	ClusterPhaseInstalling,
+	ClusterPhaseUpdating,
+	ClusterPhaseCreating,
+	ClusterPhaseScheduled,
+	ClusterPhaseNone,
+}
+
+// UpdatePhase aggregates the phases of the members into the phase of the OperandRequestSet and counts the running members.
+// The phase is Running when all the members are running, and Pending when no namespace is selected.
+func (r *OperandRequestSet) UpdatePhase() {
+	phases := make(map[ClusterPhase]int32)
+	for _, m := range r.Status.Members {
+		phase := m.Phase
+		if phase == "" {
+			phase = ClusterPhaseNone
+		}
+		phases[phase]++
+	}
+	r.Status.Namespaces = int32(len(r.Status.Members))
+	r.Status.Ready = phases[ClusterPhaseRunning]
+
+	r.Status.Phase = ClusterPhaseNone
+	if len(r.Status.Members) != 0 && r.Status.Ready == r.Status.Namespaces {
+		r.Status.Phase = ClusterPhaseRunning
+		return
+	}
+	for _, phase := range requestSetPhaseOrder {
+		if phases[phase] > 0 {
+			r.Status.Phase = phase
+			return
+		}
+	}
+}
+
+func init() {
+	SchemeBuilder.Register(&OperandRequestSet{}, &OperandRequestSetList{})
+}
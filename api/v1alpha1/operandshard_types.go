@@ -0,0 +1,98 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+const (
+	// ShardLabel moves a namespace to the OperandShard of its value, instead of the shard of the hash of its name.
+	ShardLabel = "operator.ibm.com/odlm-shard"
+
+	// ShardClaimed means an ODLM instance holds the OperandShard and renews its claim.
+	ShardClaimed = "Claimed"
+	// ShardExpired means the ODLM instance holding the OperandShard stopped renewing its claim.
+	ShardExpired = "Expired"
+)
+
+// OperandShardSpec defines the desired state of OperandShard.
+type OperandShardSpec struct {
+	// Shards is the number of the shards the namespaces are spread across by the hash of their name.
+	// It must be the same in all the OperandShards.
+	// +kubebuilder:validation:Minimum=1
+	Shards int `json:"shards"`
+	// Index is the index of the shard, from 0 to shards-1. The shard with the index 0 also runs
+	// the controllers of the cluster scoped resources.
+	// +kubebuilder:validation:Minimum=0
+	Index int `json:"index"`
+}
+
+// OperandShardStatus defines the observed state of OperandShard.
+type OperandShardStatus struct {
+	// Phase is Claimed while an ODLM instance holds the shard, Expired when it stopped renewing its claim.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Phase",xDescriptors="urn:alm:descriptor:io.kubernetes.phase"
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// Holder is the namespace of the ODLM instance holding the shard.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Holder"
+	// +optional
+	Holder string `json:"holder,omitempty"`
+	// RenewTime is the time the holder last renewed its claim.
+	// +optional
+	RenewTime *metav1.Time `json:"renewTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// OperandShard is the Schema for the operandshards API.
+// An ODLM instance claims an OperandShard to reconcile the ODLM resources of the namespaces of the shard.
+// +kubebuilder:resource:path=operandshards,shortName=opshard,scope=Cluster
+// +kubebuilder:printcolumn:name="Index",type=integer,JSONPath=.spec.index,description="Index of the shard"
+// +kubebuilder:printcolumn:name="Shards",type=integer,JSONPath=.spec.shards,description="Number of the shards"
+// +kubebuilder:printcolumn:name="Holder",type=string,JSONPath=.status.holder,description="Namespace of the ODLM instance holding the shard"
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=.status.phase,description="Current Phase"
+// +kubebuilder:printcolumn:name="Created At",type=string,JSONPath=.metadata.creationTimestamp
+// +operator-sdk:csv:customresourcedefinitions:displayName="OperandShard"
+type OperandShard struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperandShardSpec   `json:"spec,omitempty"`
+	Status OperandShardStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperandShardList contains a list of OperandShard.
+type OperandShardList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperandShard `json:"items"`
+}
+
+// IsPrimary returns true for the shard running the controllers of the cluster scoped resources.
+func (r *OperandShard) IsPrimary() bool {
+	return r.Spec.Index == 0
+}
+
+func init() {
+	SchemeBuilder.Register(&OperandShard{}, &OperandShardList{})
+}
@@ -0,0 +1,216 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// SnapshotOperation defines the operation of an OperandSnapshot.
+type SnapshotOperation string
+
+// SnapshotPhase defines the OperandSnapshot status.
+// +kubebuilder:validation:Enum=Capturing;Captured;Restoring;Restored;Failed
+type SnapshotPhase string
+
+// OperandSnapshot operations and status
+const (
+	// SnapshotFormatVersion is the version of the snapshot content format.
+	SnapshotFormatVersion = "v1"
+
+	SnapshotOperationCapture SnapshotOperation = "Capture"
+	SnapshotOperationRestore SnapshotOperation = "Restore"
+
+	SnapshotCapturing SnapshotPhase = "Capturing"
+	SnapshotCaptured  SnapshotPhase = "Captured"
+	SnapshotRestoring SnapshotPhase = "Restoring"
+	SnapshotRestored  SnapshotPhase = "Restored"
+	SnapshotFailed    SnapshotPhase = "Failed"
+)
+
+// OperandSnapshotSpec defines the desired state of OperandSnapshot.
+type OperandSnapshotSpec struct {
+	// Operation is the operation of the snapshot, one of Capture and Restore.
+	// The default is Capture.
+	// +kubebuilder:validation:Enum=Capture;Restore
+	// +optional
+	Operation SnapshotOperation `json:"operation,omitempty"`
+	// Namespaces are the namespaces of the OperandRequests to be captured.
+	// The default is all the namespaces watched by ODLM.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Export specifies where the captured snapshot is exported to, in addition to the OperandSnapshot status.
+	// +optional
+	Export *SnapshotExport `json:"export,omitempty"`
+	// Source specifies where the snapshot is restored from.
+	// The default is the snapshot captured in the status of this OperandSnapshot.
+	// +optional
+	Source *SnapshotSource `json:"source,omitempty"`
+}
+
+// SnapshotExport defines the export target of a snapshot.
+type SnapshotExport struct {
+	// ConfigMap is the name of the ConfigMap, in the OperandSnapshot namespace, the snapshot is exported to.
+	// +optional
+	ConfigMap string `json:"configMap,omitempty"`
+}
+
+// SnapshotSource defines the source of a snapshot to be restored.
+type SnapshotSource struct {
+	// ConfigMap is the name of a ConfigMap, in the OperandSnapshot namespace, holding an exported snapshot.
+	// +optional
+	ConfigMap string `json:"configMap,omitempty"`
+	// Snapshot is the name of another OperandSnapshot, in the same namespace, holding a captured snapshot.
+	// +optional
+	Snapshot string `json:"snapshot,omitempty"`
+}
+
+// SnapshotObject is a captured object.
+type SnapshotObject struct {
+	// APIVersion is the APIVersion of the object.
+	APIVersion string `json:"apiVersion"`
+	// Kind is the kind of the object.
+	Kind string `json:"kind"`
+	// Name is the name of the object.
+	Name string `json:"name"`
+	// Namespace is the namespace of the object.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Labels are the labels of the object.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Spec is the spec of the object.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Spec *runtime.RawExtension `json:"spec,omitempty"`
+}
+
+// SnapshotContent is the content of a snapshot.
+type SnapshotContent struct {
+	// Version is the version of the snapshot content format.
+	Version string `json:"version"`
+	// OperandRegistries are the OperandRegistries resolved from the captured OperandRequests.
+	// +optional
+	OperandRegistries []SnapshotObject `json:"operandRegistries,omitempty"`
+	// OperandConfigs are the OperandConfigs resolved from the captured OperandRequests.
+	// +optional
+	OperandConfigs []SnapshotObject `json:"operandConfigs,omitempty"`
+	// OperandRequests are the captured OperandRequests.
+	// +optional
+	OperandRequests []SnapshotObject `json:"operandRequests,omitempty"`
+	// Operands are the custom resources created by the captured OperandRequests.
+	// +optional
+	Operands []SnapshotObject `json:"operands,omitempty"`
+}
+
+// OperandSnapshotStatus defines the observed state of OperandSnapshot.
+type OperandSnapshotStatus struct {
+	// Phase describes the overall phase of OperandSnapshot.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Phase",xDescriptors="urn:alm:descriptor:io.kubernetes.phase"
+	// +optional
+	Phase SnapshotPhase `json:"phase,omitempty"`
+	// Message is a human readable message about the last operation.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// ObservedGeneration is the generation of the OperandSnapshot the last operation ran for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// CapturedTime is the time the snapshot was captured.
+	// +optional
+	CapturedTime string `json:"capturedTime,omitempty"`
+	// RestoredTime is the time the snapshot was restored.
+	// +optional
+	RestoredTime string `json:"restoredTime,omitempty"`
+	// Content is the captured snapshot.
+	// +optional
+	Content *SnapshotContent `json:"content,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// OperandSnapshot is the Schema for the operandsnapshots API.
+// +kubebuilder:resource:path=operandsnapshots,shortName=opsnap,scope=Namespaced
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp
+// +kubebuilder:printcolumn:name="Operation",type=string,JSONPath=.spec.operation,description="Snapshot Operation"
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=.status.phase,description="Current Phase"
+// +kubebuilder:printcolumn:name="Created At",type=string,JSONPath=.metadata.creationTimestamp
+// +operator-sdk:csv:customresourcedefinitions:displayName="OperandSnapshot"
+type OperandSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperandSnapshotSpec   `json:"spec,omitempty"`
+	Status OperandSnapshotStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperandSnapshotList contains a list of OperandSnapshot.
+type OperandSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperandSnapshot `json:"items"`
+}
+
+// GetOperation returns the operation of the OperandSnapshot with default value.
+func (r *OperandSnapshot) GetOperation() SnapshotOperation {
+	if r.Spec.Operation == "" {
+		return SnapshotOperationCapture
+	}
+	return r.Spec.Operation
+}
+
+// IsDone returns true if the operation has finished for the current generation.
+func (r *OperandSnapshot) IsDone() bool {
+	if r.Status.ObservedGeneration != r.Generation {
+		return false
+	}
+	switch r.Status.Phase {
+	case SnapshotCaptured, SnapshotRestored, SnapshotFailed:
+		return true
+	}
+	return false
+}
+
+// SetPhase sets the phase and the message of the OperandSnapshot for the current generation.
+func (r *OperandSnapshot) SetPhase(phase SnapshotPhase, message string) {
+	r.Status.Phase = phase
+	r.Status.Message = message
+	r.Status.ObservedGeneration = r.Generation
+	switch phase {
+	case SnapshotCaptured:
+		r.Status.CapturedTime = time.Now().Format(time.RFC3339)
+	case SnapshotRestored:
+		r.Status.RestoredTime = time.Now().Format(time.RFC3339)
+	}
+}
+
+// TruncateStatus drops the captured content from the status and points to the ConfigMap holding the full status.
+func (r *OperandSnapshot) TruncateStatus(configMapName string) {
+	r.Status.Content = nil
+	r.Status.Message = "The snapshot exceeds the size limit of the status, the full status is saved in ConfigMap " + configMapName
+}
+
+func init() {
+	SchemeBuilder.Register(&OperandSnapshot{}, &OperandSnapshotList{})
+}
@@ -0,0 +1,737 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// OperatorConfigPhase defines the OperatorConfig status.
+// +kubebuilder:validation:Enum=Applied;RestartRequired;Invalid;Ignored
+type OperatorConfigPhase string
+
+// OperatorConfig name, install scopes and phases
+const (
+	// OperatorConfigName is the name of the OperatorConfig read by ODLM, in the namespace of the operator.
+	OperatorConfigName = "odlm-config"
+
+	InstallScopeCluster    = "cluster"
+	InstallScopeNamespaced = "namespaced"
+
+	RecreatePolicyImmediate = "Immediate"
+	RecreatePolicyResync    = "Resync"
+
+	GarbageCollectionDisabled = "Disabled"
+	GarbageCollectionDryRun   = "DryRun"
+	GarbageCollectionDelete   = "Delete"
+
+	CallbackPreInstall  CallbackEvent = "PreInstall"
+	CallbackPostInstall CallbackEvent = "PostInstall"
+	CallbackPreDelete   CallbackEvent = "PreDelete"
+
+	CallbackFailurePolicyFail   = "Fail"
+	CallbackFailurePolicyIgnore = "Ignore"
+
+	OperatorConfigApplied         OperatorConfigPhase = "Applied"
+	OperatorConfigRestartRequired OperatorConfigPhase = "RestartRequired"
+	OperatorConfigInvalid         OperatorConfigPhase = "Invalid"
+	OperatorConfigIgnored         OperatorConfigPhase = "Ignored"
+)
+
+// OperatorConfigSpec defines the desired state of OperatorConfig.
+// The unset settings fall back to the environment variables of the operator.
+type OperatorConfigSpec struct {
+	// IsolatedMode limits ODLM to the watched namespaces, it replaces the ISOLATED_MODE environment variable.
+	// The change takes effect when ODLM restarts, because it changes the resources cached by ODLM.
+	// +optional
+	IsolatedMode *bool `json:"isolatedMode,omitempty"`
+	// WatchNamespace are the namespaces ODLM watches, separated by commas, all the namespaces when it is empty.
+	// It replaces the WATCH_NAMESPACE environment variable. The change takes effect when ODLM restarts,
+	// because it changes the resources cached by ODLM.
+	// +optional
+	WatchNamespace *string `json:"watchNamespace,omitempty"`
+	// WatchNamespaceSelector is the label selector of the namespaces ODLM watches in addition to the watched
+	// namespaces, they are watched as soon as they are labeled, without restarting ODLM. It replaces the
+	// WATCH_NAMESPACE_SELECTOR environment variable, it is ignored when all the namespaces are watched.
+	// The change takes effect when ODLM restarts.
+	// +optional
+	WatchNamespaceSelector *string `json:"watchNamespaceSelector,omitempty"`
+	// Shard is the name of the OperandShard ODLM claims, it only reconciles the namespaces of the shard.
+	// ODLM reconciles all the namespaces when it is empty. It overrides the --shard flag.
+	// The change takes effect when ODLM restarts.
+	// +optional
+	Shard string `json:"shard,omitempty"`
+	// InstallScope is the scope of the installation, one of cluster and namespaced.
+	// It replaces the INSTALL_SCOPE environment variable.
+	// +kubebuilder:validation:Enum=cluster;namespaced
+	// +optional
+	InstallScope string `json:"installScope,omitempty"`
+	// OperatorChecker enables the operator checker, which recovers the Subscriptions stuck in OLM.
+	// It replaces the OPERATORCHECKER_MODE environment variable.
+	// +optional
+	OperatorChecker *bool `json:"operatorChecker,omitempty"`
+	// OperatorCheckerOptions sets how often the operator checker checks the Subscriptions, and which ones it checks.
+	// The unset values fall back to the flags of the operator. The change takes effect without restarting ODLM.
+	// +optional
+	OperatorCheckerOptions *OperatorCheckerOptions `json:"operatorCheckerOptions,omitempty"`
+	// ForensicBundle collects the diagnostics of the operands failing terminally.
+	// It replaces the FORENSIC_BUNDLE and FORENSIC_BUNDLE_DIR environment variables.
+	// +optional
+	ForensicBundle *ForensicBundle `json:"forensicBundle,omitempty"`
+	// InstallThrottle limits the Subscriptions being installed at the same time from a CatalogSource.
+	// It replaces the MAX_PARALLEL_INSTALLS_PER_CATALOG environment variable.
+	// +optional
+	InstallThrottle *InstallThrottle `json:"installThrottle,omitempty"`
+	// Logging sets the verbosity of the logs of the controllers, it overrides the -v and --log-verbosity flags.
+	// The change takes effect without restarting ODLM.
+	// +optional
+	Logging *Logging `json:"logging,omitempty"`
+	// Throughput tunes the client to the API server and the work queues of the controllers for large clusters.
+	// The unset values fall back to the flags of the operator. The change takes effect when ODLM restarts.
+	// +optional
+	Throughput *Throughput `json:"throughput,omitempty"`
+	// RecreatePolicy is when the Subscriptions, OperatorGroups, custom resources and copied Secrets created by ODLM
+	// are recreated after someone else deletes them. Immediate recreates them as soon as they are deleted,
+	// Resync waits for the next reconcile of their OperandRequest or OperandBindInfo.
+	// It replaces the RECREATE_POLICY environment variable.
+	// +kubebuilder:validation:Enum=Immediate;Resync
+	// +optional
+	RecreatePolicy string `json:"recreatePolicy,omitempty"`
+	// RepairOperatorGroups merges the OperatorGroups of a namespace having several of them into one,
+	// OLM doesn't install the operators in such a namespace. ODLM only reports the conflict when it is false.
+	// It replaces the REPAIR_OPERATOR_GROUPS environment variable.
+	// +optional
+	RepairOperatorGroups *bool `json:"repairOperatorGroups,omitempty"`
+	// Callbacks are the HTTP endpoints notified of the lifecycle of the operands, like a CMDB or an ITSM system.
+	// The change takes effect without restarting ODLM.
+	// +optional
+	Callbacks []Callback `json:"callbacks,omitempty"`
+	// Mirrors replace the CatalogSources and the image registries of the OperandRegistries when ODLM installs
+	// the operators, so the same OperandRegistries work in the disconnected clusters.
+	// The change takes effect without restarting ODLM.
+	// +optional
+	Mirrors *Mirrors `json:"mirrors,omitempty"`
+	// PlacementProfiles are the named node selectors, tolerations and affinities the operators of the OperandRegistries
+	// and the services of the OperandConfigs reference, like the infra, control-plane or tenant nodes.
+	// The change takes effect without restarting ODLM.
+	// +optional
+	PlacementProfiles []PlacementProfile `json:"placementProfiles,omitempty"`
+	// Metrics bounds the cardinality of the metrics of ODLM, so the clusters with many tenants don't overload Prometheus.
+	// The change takes effect without restarting ODLM.
+	// +optional
+	Metrics *Metrics `json:"metrics,omitempty"`
+	// GarbageCollection periodically finds the Subscriptions created by ODLM none of their OperandRequests exists anymore,
+	// like after the OperandRequests are deleted by hand or their CRD is removed, and deletes them with their ClusterServiceVersions.
+	// The change takes effect without restarting ODLM.
+	// +optional
+	GarbageCollection *GarbageCollection `json:"garbageCollection,omitempty"`
+}
+
+// OperatorCheckerOptions defines the interval and the scope of the operator checker.
+type OperatorCheckerOptions struct {
+	// Interval is the time between two checks of a Subscription, at least 10 seconds. The default is 20 seconds.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// Jitter is the percentage of the interval added at random to each check, so the Subscriptions
+	// created together aren't all checked at the same time. The default is 10.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Jitter *int `json:"jitter,omitempty"`
+	// Namespaces are the namespaces of the Subscriptions checked, all the namespaces when empty.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Packages are the packages of the Subscriptions checked, all the packages when empty.
+	// +optional
+	Packages []string `json:"packages,omitempty"`
+}
+
+// GarbageCollection defines the collection of the orphaned Subscriptions and ClusterServiceVersions.
+type GarbageCollection struct {
+	// Mode is Disabled, DryRun to only report the orphaned objects in the status, or Delete to delete them.
+	// The default is Disabled.
+	// +kubebuilder:validation:Enum=Disabled;DryRun;Delete
+	// +optional
+	Mode string `json:"mode,omitempty"`
+	// Interval is the time between two collections, at least one minute. The default is one hour.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// Metrics defines the cardinality guard of the metrics.
+type Metrics struct {
+	// MaxLabelValues is the number of the values of each namespace, name, request and operator label of a metric.
+	// The series beyond it are recorded with the _overflow_ value of the label. 0 disables the guard. The default is 500.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxLabelValues *int `json:"maxLabelValues,omitempty"`
+}
+
+// PlacementProfile defines the nodes the pods referencing the profile are scheduled on.
+// The node selector and the tolerations are added to the ones of the pods, the affinity is only set
+// when the pods don't have one.
+type PlacementProfile struct {
+	// Name of the profile, like infra.
+	Name string `json:"name"`
+	// NodeSelector is added to the node selector of the pods, the keys already set in the pods are kept.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations are added to the tolerations of the pods.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity is the affinity of the pods without one.
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+}
+
+// Mirrors defines the CatalogSources and the image registries replacing the ones of the OperandRegistries.
+type Mirrors struct {
+	// Catalogs replace the CatalogSources of the operators installed by OLM.
+	// +optional
+	Catalogs []CatalogMirror `json:"catalogs,omitempty"`
+	// Registries replace the registries of the bundle images and the OCI Helm chart repositories.
+	// +optional
+	Registries []RegistryMirror `json:"registries,omitempty"`
+}
+
+// CatalogMirror defines the CatalogSource replacing a CatalogSource of the OperandRegistries.
+type CatalogMirror struct {
+	// SourceName is the name of the replaced CatalogSource. All the CatalogSources of the namespace are replaced when it is empty.
+	// +optional
+	SourceName string `json:"sourceName,omitempty"`
+	// SourceNamespace is the namespace of the replaced CatalogSource.
+	SourceNamespace string `json:"sourceNamespace"`
+	// MirrorName is the name of the CatalogSource replacing it. The name is kept when it is empty.
+	// +optional
+	MirrorName string `json:"mirrorName,omitempty"`
+	// MirrorNamespace is the namespace of the CatalogSource replacing it. The namespace is kept when it is empty.
+	// +optional
+	MirrorNamespace string `json:"mirrorNamespace,omitempty"`
+}
+
+// RegistryMirror defines the registry replacing the prefix of the images, like icr.io/cpopen.
+type RegistryMirror struct {
+	// Source is the prefix of the replaced images, a registry host or a repository.
+	Source string `json:"source"`
+	// Mirror is the prefix replacing it, like mirror.example.com:5000/cpopen.
+	Mirror string `json:"mirror"`
+}
+
+// Callback defines an HTTP endpoint ODLM posts the lifecycle events of the operands to.
+// The JSON body is signed with HMAC-SHA256 in the X-ODLM-Signature header when a secret is set.
+type Callback struct {
+	// Name of the callback.
+	Name string `json:"name"`
+	// URL the events are posted to.
+	URL string `json:"url"`
+	// Events are the events the callback is invoked for. All the events are sent when it is empty.
+	// PreInstall is sent before the operator of an operand is installed, PostInstall when the operand is ready
+	// and PreDelete before the operand is deleted.
+	// +optional
+	Events []CallbackEvent `json:"events,omitempty"`
+	// SecretKeyRef selects the key of a Secret in the namespace of ODLM holding the HMAC key.
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+	// Timeout of a call, the default is 10s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// FailurePolicy is what ODLM does when the callback fails or times out. Fail holds the operand and calls
+	// the callback again at the next reconcile, Ignore records a warning Event and goes on. The default is Ignore.
+	// +kubebuilder:validation:Enum=Fail;Ignore
+	// +optional
+	FailurePolicy string `json:"failurePolicy,omitempty"`
+}
+
+// CallbackEvent is a lifecycle event of an operand.
+// +kubebuilder:validation:Enum=PreInstall;PostInstall;PreDelete
+type CallbackEvent string
+
+// ForensicBundle defines the collection of the forensic bundles. A bundle holds the Subscription,
+// ClusterServiceVersion, InstallPlan and custom resources of the failed operand, the recent Events and an ODLM log excerpt.
+type ForensicBundle struct {
+	// Enabled collects a forensic bundle when an operand of an OperandRequest fails.
+	Enabled bool `json:"enabled"`
+	// Directory is the path the bundles are written into, like the mount path of a PersistentVolumeClaim.
+	// The bundles are stored in a ConfigMap in the namespace of the OperandRequest when it is empty.
+	// +optional
+	Directory string `json:"directory,omitempty"`
+}
+
+// InstallThrottle defines how many Subscriptions ODLM installs in parallel from a CatalogSource.
+// A Subscription is being installed until OLM reports its installed ClusterServiceVersion.
+type InstallThrottle struct {
+	// MaxParallelInstalls is the limit of every CatalogSource, 0 means unlimited.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxParallelInstalls int `json:"maxParallelInstalls,omitempty"`
+	// Catalogs overrides the limit of the listed CatalogSources.
+	// +optional
+	Catalogs []CatalogInstallLimit `json:"catalogs,omitempty"`
+}
+
+// CatalogInstallLimit is the limit of the parallel installs from a CatalogSource.
+type CatalogInstallLimit struct {
+	// SourceName is the name of the CatalogSource.
+	SourceName string `json:"sourceName"`
+	// SourceNamespace is the namespace of the CatalogSource.
+	SourceNamespace string `json:"sourceNamespace"`
+	// MaxParallelInstalls is the limit of the CatalogSource, 0 means unlimited.
+	// +kubebuilder:validation:Minimum=0
+	MaxParallelInstalls int `json:"maxParallelInstalls"`
+}
+
+// Logging defines the verbosity of the logs. A line is written when its level is not above the verbosity of its controller.
+type Logging struct {
+	// Verbosity is the verbosity of all the controllers.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Verbosity int `json:"verbosity,omitempty"`
+	// Controllers overrides the verbosity of the listed controllers.
+	// +optional
+	Controllers []ControllerVerbosity `json:"controllers,omitempty"`
+}
+
+// Throughput defines how fast ODLM talks to the API server and reconciles the resources.
+type Throughput struct {
+	// QPS is the queries per second of the client to the API server.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	QPS int `json:"qps,omitempty"`
+	// Burst is the burst of the queries of the client to the API server.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Burst int `json:"burst,omitempty"`
+	// OperandWorkers is the number of the members of an OperandRequest reconciled in parallel, after the members they require.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	OperandWorkers int `json:"operandWorkers,omitempty"`
+	// ControllerThroughput is the throughput of all the controllers.
+	ControllerThroughput `json:",inline"`
+	// Controllers overrides the throughput of the listed controllers.
+	// +optional
+	Controllers []NamedControllerThroughput `json:"controllers,omitempty"`
+}
+
+// ControllerThroughput defines how many reconciles a controller runs in parallel and how fast it requeues the resources.
+type ControllerThroughput struct {
+	// MaxConcurrentReconciles is the number of the reconciles the controller runs in parallel.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxConcurrentReconciles int `json:"maxConcurrentReconciles,omitempty"`
+	// RateLimiter limits how fast the resources are requeued.
+	// +optional
+	RateLimiter *RateLimiter `json:"rateLimiter,omitempty"`
+	// FairQueue reconciles the resources of the namespaces in turn, so a namespace with a burst of changes
+	// doesn't delay the reconciles of the other namespaces. It is enabled by default.
+	// +optional
+	FairQueue *bool `json:"fairQueue,omitempty"`
+}
+
+// NamedControllerThroughput is the throughput of a controller.
+type NamedControllerThroughput struct {
+	// Name is the name of the controller, like operandrequest or operandbindinfo.
+	Name string `json:"name"`
+	// ControllerThroughput is the throughput of the controller, the unset values are the ones of all the controllers.
+	ControllerThroughput `json:",inline"`
+}
+
+// RateLimiter is the rate limiter of a work queue. A failed resource is requeued with an exponential delay,
+// from the base delay to the max delay, and all the resources are requeued within the QPS and burst.
+type RateLimiter struct {
+	// BaseDelay is the delay of the first retry of a failed resource.
+	// +optional
+	BaseDelay *metav1.Duration `json:"baseDelay,omitempty"`
+	// MaxDelay is the longest delay of the retries of a failed resource.
+	// +optional
+	MaxDelay *metav1.Duration `json:"maxDelay,omitempty"`
+	// QPS is the number of the resources requeued per second.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	QPS int `json:"qps,omitempty"`
+	// Burst is the burst of the resources requeued.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Burst int `json:"burst,omitempty"`
+}
+
+// ControllerVerbosity is the verbosity of the logs of a controller.
+type ControllerVerbosity struct {
+	// Name is the name of the controller, like operandrequest or operandregistry.
+	Name string `json:"name"`
+	// Verbosity is the verbosity of the controller.
+	// +kubebuilder:validation:Minimum=0
+	Verbosity int `json:"verbosity"`
+}
+
+// OperatorSettings are the settings ODLM runs with.
+type OperatorSettings struct {
+	// IsolatedMode shows if ODLM is limited to the watched namespaces.
+	IsolatedMode bool `json:"isolatedMode"`
+	// WatchNamespace are the namespaces ODLM watches, separated by commas, all the namespaces when it is empty.
+	WatchNamespace string `json:"watchNamespace"`
+	// WatchNamespaceSelector is the label selector of the namespaces ODLM watches in addition to the watched namespaces.
+	WatchNamespaceSelector string `json:"watchNamespaceSelector,omitempty"`
+	// Shard is the name of the OperandShard claimed by ODLM.
+	Shard string `json:"shard,omitempty"`
+	// InstallScope is the scope of the installation.
+	InstallScope string `json:"installScope"`
+	// OperatorChecker shows if the operator checker is enabled.
+	OperatorChecker bool `json:"operatorChecker"`
+	// OperatorCheckerInterval is the time between two checks of a Subscription.
+	OperatorCheckerInterval metav1.Duration `json:"operatorCheckerInterval"`
+	// OperatorCheckerJitter is the percentage of the interval added at random to each check.
+	OperatorCheckerJitter int `json:"operatorCheckerJitter"`
+	// OperatorCheckerNamespaces are the namespaces of the Subscriptions checked, all the namespaces when empty.
+	// +optional
+	OperatorCheckerNamespaces []string `json:"operatorCheckerNamespaces,omitempty"`
+	// OperatorCheckerPackages are the packages of the Subscriptions checked, all the packages when empty.
+	// +optional
+	OperatorCheckerPackages []string `json:"operatorCheckerPackages,omitempty"`
+	// ForensicBundle shows if the forensic bundles are collected.
+	ForensicBundle bool `json:"forensicBundle"`
+	// ForensicBundleDirectory is the path the forensic bundles are written into.
+	// +optional
+	ForensicBundleDirectory string `json:"forensicBundleDirectory,omitempty"`
+	// MaxParallelInstalls is the limit of the parallel installs from every CatalogSource.
+	MaxParallelInstalls int `json:"maxParallelInstalls"`
+	// CatalogInstallLimits are the limits of the parallel installs from the listed CatalogSources.
+	// +optional
+	CatalogInstallLimits []CatalogInstallLimit `json:"catalogInstallLimits,omitempty"`
+	// Verbosity is the verbosity of the logs of all the controllers.
+	Verbosity int `json:"verbosity"`
+	// ControllerVerbosities are the verbosities of the logs of the listed controllers.
+	// +optional
+	ControllerVerbosities []ControllerVerbosity `json:"controllerVerbosities,omitempty"`
+	// Throughput is the throughput of the client to the API server and of the controllers.
+	Throughput Throughput `json:"throughput"`
+	// RecreatePolicy is when the deleted objects created by ODLM are recreated.
+	RecreatePolicy string `json:"recreatePolicy"`
+	// RepairOperatorGroups shows if the conflicting OperatorGroups are merged.
+	RepairOperatorGroups bool `json:"repairOperatorGroups"`
+	// Callbacks are the HTTP endpoints notified of the lifecycle of the operands.
+	// +optional
+	Callbacks []Callback `json:"callbacks,omitempty"`
+	// CatalogMirrors are the CatalogSources replacing the ones of the OperandRegistries.
+	// +optional
+	CatalogMirrors []CatalogMirror `json:"catalogMirrors,omitempty"`
+	// RegistryMirrors are the registries replacing the ones of the images of the OperandRegistries.
+	// +optional
+	RegistryMirrors []RegistryMirror `json:"registryMirrors,omitempty"`
+	// PlacementProfiles are the placement profiles the operators and the services reference.
+	// +optional
+	PlacementProfiles []PlacementProfile `json:"placementProfiles,omitempty"`
+	// MaxMetricLabelValues is the number of the values of each guarded label of a metric, 0 when the guard is disabled.
+	MaxMetricLabelValues int `json:"maxMetricLabelValues"`
+	// GarbageCollectionMode is whether the orphaned objects are collected, reported or deleted.
+	GarbageCollectionMode string `json:"garbageCollectionMode"`
+	// GarbageCollectionInterval is the time between two collections of the orphaned objects.
+	GarbageCollectionInterval metav1.Duration `json:"garbageCollectionInterval"`
+}
+
+// OperatorConfigStatus defines the observed state of OperatorConfig.
+type OperatorConfigStatus struct {
+	// Phase describes the overall phase of OperatorConfig.
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Phase",xDescriptors="urn:alm:descriptor:io.kubernetes.phase"
+	// +optional
+	Phase OperatorConfigPhase `json:"phase,omitempty"`
+	// Message is a human readable message about the phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// ObservedGeneration is the generation of the OperatorConfig the settings are applied for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Applied are the settings ODLM is running with.
+	// +optional
+	Applied *OperatorSettings `json:"applied,omitempty"`
+	// GarbageCollection is the report of the last collection of the orphaned objects.
+	// +optional
+	GarbageCollection *GarbageCollectionReport `json:"garbageCollection,omitempty"`
+}
+
+// GarbageCollectionReport is the result of a collection of the orphaned objects.
+type GarbageCollectionReport struct {
+	// Mode is the mode of the collection, DryRun or Delete.
+	Mode string `json:"mode"`
+	// LastCollectionTime is when the orphaned objects were last collected.
+	LastCollectionTime metav1.Time `json:"lastCollectionTime"`
+	// OrphanCount is the number of the orphaned Subscriptions found by the collection.
+	OrphanCount int `json:"orphanCount"`
+	// Orphans are the first orphaned Subscriptions found by the collection.
+	// +optional
+	Orphans []OrphanedSubscription `json:"orphans,omitempty"`
+}
+
+// OrphanedSubscription is a Subscription created by ODLM none of its OperandRequests exists anymore.
+type OrphanedSubscription struct {
+	// Name is the name of the Subscription.
+	Name string `json:"name"`
+	// Namespace is the namespace of the Subscription.
+	Namespace string `json:"namespace"`
+	// ClusterServiceVersion is the ClusterServiceVersion installed by the Subscription.
+	// +optional
+	ClusterServiceVersion string `json:"clusterServiceVersion,omitempty"`
+	// Requests are the OperandRequests the Subscription was created or updated for, as namespace/name.
+	// +optional
+	Requests []string `json:"requests,omitempty"`
+	// Deleted shows if the Subscription and its ClusterServiceVersion are deleted.
+	// +optional
+	Deleted bool `json:"deleted,omitempty"`
+	// Error is why the Subscription or its ClusterServiceVersion can't be deleted.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// OperatorConfig is the Schema for the operatorconfigs API. ODLM reads the OperatorConfig named odlm-config in its namespace.
+// +kubebuilder:resource:path=operatorconfigs,scope=Namespaced
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=.status.phase,description="Current Phase"
+// +kubebuilder:printcolumn:name="Created At",type=string,JSONPath=.metadata.creationTimestamp
+// +operator-sdk:csv:customresourcedefinitions:displayName="OperatorConfig"
+type OperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperatorConfigSpec   `json:"spec,omitempty"`
+	Status OperatorConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperatorConfigList contains a list of OperatorConfig.
+type OperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperatorConfig `json:"items"`
+}
+
+// GetSettings returns the settings of the OperatorConfig, the unset ones are taken from the defaults.
+func (r *OperatorConfig) GetSettings(defaults OperatorSettings) OperatorSettings {
+	settings := defaults
+	if r.Spec.IsolatedMode != nil {
+		settings.IsolatedMode = *r.Spec.IsolatedMode
+	}
+	if r.Spec.WatchNamespace != nil {
+		settings.WatchNamespace = *r.Spec.WatchNamespace
+	}
+	if r.Spec.WatchNamespaceSelector != nil {
+		settings.WatchNamespaceSelector = *r.Spec.WatchNamespaceSelector
+	}
+	if r.Spec.Shard != "" {
+		settings.Shard = r.Spec.Shard
+	}
+	if r.Spec.InstallScope != "" {
+		settings.InstallScope = r.Spec.InstallScope
+	}
+	if r.Spec.OperatorChecker != nil {
+		settings.OperatorChecker = *r.Spec.OperatorChecker
+	}
+	if r.Spec.OperatorCheckerOptions != nil {
+		if r.Spec.OperatorCheckerOptions.Interval != nil {
+			settings.OperatorCheckerInterval = *r.Spec.OperatorCheckerOptions.Interval
+		}
+		if r.Spec.OperatorCheckerOptions.Jitter != nil {
+			settings.OperatorCheckerJitter = *r.Spec.OperatorCheckerOptions.Jitter
+		}
+		if r.Spec.OperatorCheckerOptions.Namespaces != nil {
+			settings.OperatorCheckerNamespaces = r.Spec.OperatorCheckerOptions.Namespaces
+		}
+		if r.Spec.OperatorCheckerOptions.Packages != nil {
+			settings.OperatorCheckerPackages = r.Spec.OperatorCheckerOptions.Packages
+		}
+	}
+	if r.Spec.ForensicBundle != nil {
+		settings.ForensicBundle = r.Spec.ForensicBundle.Enabled
+		settings.ForensicBundleDirectory = r.Spec.ForensicBundle.Directory
+	}
+	if r.Spec.InstallThrottle != nil {
+		settings.MaxParallelInstalls = r.Spec.InstallThrottle.MaxParallelInstalls
+		settings.CatalogInstallLimits = r.Spec.InstallThrottle.Catalogs
+	}
+	if r.Spec.Logging != nil {
+		settings.Verbosity = r.Spec.Logging.Verbosity
+		settings.ControllerVerbosities = r.Spec.Logging.Controllers
+	}
+	if r.Spec.Throughput != nil {
+		settings.Throughput = r.Spec.Throughput.Merge(settings.Throughput)
+	}
+	if r.Spec.RecreatePolicy != "" {
+		settings.RecreatePolicy = r.Spec.RecreatePolicy
+	}
+	if r.Spec.RepairOperatorGroups != nil {
+		settings.RepairOperatorGroups = *r.Spec.RepairOperatorGroups
+	}
+	if r.Spec.Callbacks != nil {
+		settings.Callbacks = r.Spec.Callbacks
+	}
+	if r.Spec.Mirrors != nil {
+		settings.CatalogMirrors = r.Spec.Mirrors.Catalogs
+		settings.RegistryMirrors = r.Spec.Mirrors.Registries
+	}
+	if r.Spec.PlacementProfiles != nil {
+		settings.PlacementProfiles = r.Spec.PlacementProfiles
+	}
+	if r.Spec.Metrics != nil && r.Spec.Metrics.MaxLabelValues != nil {
+		settings.MaxMetricLabelValues = *r.Spec.Metrics.MaxLabelValues
+	}
+	if r.Spec.GarbageCollection != nil {
+		if r.Spec.GarbageCollection.Mode != "" {
+			settings.GarbageCollectionMode = r.Spec.GarbageCollection.Mode
+		}
+		if r.Spec.GarbageCollection.Interval != nil {
+			settings.GarbageCollectionInterval = *r.Spec.GarbageCollection.Interval
+		}
+	}
+	return settings
+}
+
+// SetPhase sets the phase, the message and the applied settings of the OperatorConfig for the current generation.
+func (r *OperatorConfig) SetPhase(phase OperatorConfigPhase, message string, applied *OperatorSettings) {
+	r.Status.Phase = phase
+	r.Status.Message = message
+	r.Status.ObservedGeneration = r.Generation
+	r.Status.Applied = applied
+}
+
+// NewOperatorConfig returns an OperatorConfig holding all the settings.
+func NewOperatorConfig(namespace string, settings OperatorSettings) *OperatorConfig {
+	isolatedMode := settings.IsolatedMode
+	watchNamespace := settings.WatchNamespace
+	watchNamespaceSelector := settings.WatchNamespaceSelector
+	operatorChecker := settings.OperatorChecker
+	operatorCheckerInterval := settings.OperatorCheckerInterval
+	operatorCheckerJitter := settings.OperatorCheckerJitter
+	repairOperatorGroups := settings.RepairOperatorGroups
+	maxMetricLabelValues := settings.MaxMetricLabelValues
+	garbageCollectionInterval := settings.GarbageCollectionInterval
+	return &OperatorConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      OperatorConfigName,
+			Namespace: namespace,
+		},
+		Spec: OperatorConfigSpec{
+			IsolatedMode:           &isolatedMode,
+			WatchNamespace:         &watchNamespace,
+			WatchNamespaceSelector: &watchNamespaceSelector,
+			Shard:                  settings.Shard,
+			InstallScope:           settings.InstallScope,
+			OperatorChecker:        &operatorChecker,
+			OperatorCheckerOptions: &OperatorCheckerOptions{
+				Interval:   &operatorCheckerInterval,
+				Jitter:     &operatorCheckerJitter,
+				Namespaces: settings.OperatorCheckerNamespaces,
+				Packages:   settings.OperatorCheckerPackages,
+			},
+			ForensicBundle: &ForensicBundle{
+				Enabled:   settings.ForensicBundle,
+				Directory: settings.ForensicBundleDirectory,
+			},
+			InstallThrottle: &InstallThrottle{
+				MaxParallelInstalls: settings.MaxParallelInstalls,
+				Catalogs:            settings.CatalogInstallLimits,
+			},
+			Logging: &Logging{
+				Verbosity:   settings.Verbosity,
+				Controllers: settings.ControllerVerbosities,
+			},
+			Throughput:           settings.Throughput.DeepCopy(),
+			RecreatePolicy:       settings.RecreatePolicy,
+			RepairOperatorGroups: &repairOperatorGroups,
+			Callbacks:            settings.Callbacks,
+			Mirrors: &Mirrors{
+				Catalogs:   settings.CatalogMirrors,
+				Registries: settings.RegistryMirrors,
+			},
+			PlacementProfiles: settings.PlacementProfiles,
+			Metrics:           &Metrics{MaxLabelValues: &maxMetricLabelValues},
+			GarbageCollection: &GarbageCollection{
+				Mode:     settings.GarbageCollectionMode,
+				Interval: &garbageCollectionInterval,
+			},
+		},
+	}
+}
+
+// Merge returns the throughput with the unset values taken from the defaults.
+func (t *Throughput) Merge(defaults Throughput) Throughput {
+	merged := *t.DeepCopy()
+	if merged.QPS == 0 {
+		merged.QPS = defaults.QPS
+	}
+	if merged.Burst == 0 {
+		merged.Burst = defaults.Burst
+	}
+	if merged.OperandWorkers == 0 {
+		merged.OperandWorkers = defaults.OperandWorkers
+	}
+	merged.ControllerThroughput = merged.ControllerThroughput.merge(defaults.ControllerThroughput)
+	if merged.Controllers == nil {
+		merged.Controllers = defaults.DeepCopy().Controllers
+	}
+	return merged
+}
+
+// ForController returns the throughput of the named controller, the unset values are the ones of all the controllers.
+func (t *Throughput) ForController(name string) ControllerThroughput {
+	for _, c := range t.Controllers {
+		if c.Name == name {
+			return c.ControllerThroughput.merge(t.ControllerThroughput)
+		}
+	}
+	return *t.ControllerThroughput.DeepCopy()
+}
+
+func (c ControllerThroughput) merge(defaults ControllerThroughput) ControllerThroughput {
+	merged := *c.DeepCopy()
+	if merged.MaxConcurrentReconciles == 0 {
+		merged.MaxConcurrentReconciles = defaults.MaxConcurrentReconciles
+	}
+	if merged.FairQueue == nil && defaults.FairQueue != nil {
+		fair := *defaults.FairQueue
+		merged.FairQueue = &fair
+	}
+	if defaults.RateLimiter == nil {
+		return merged
+	}
+	if merged.RateLimiter == nil {
+		merged.RateLimiter = defaults.RateLimiter.DeepCopy()
+		return merged
+	}
+	if merged.RateLimiter.BaseDelay == nil {
+		merged.RateLimiter.BaseDelay = defaults.RateLimiter.BaseDelay.DeepCopy()
+	}
+	if merged.RateLimiter.MaxDelay == nil {
+		merged.RateLimiter.MaxDelay = defaults.RateLimiter.MaxDelay.DeepCopy()
+	}
+	if merged.RateLimiter.QPS == 0 {
+		merged.RateLimiter.QPS = defaults.RateLimiter.QPS
+	}
+	if merged.RateLimiter.Burst == 0 {
+		merged.RateLimiter.Burst = defaults.RateLimiter.Burst
+	}
+	return merged
+}
+
+func init() {
+	SchemeBuilder.Register(&OperatorConfig{}, &OperatorConfigList{})
+
+}
@@ -23,26 +23,54 @@ package v1alpha1
 
 import (
 	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Condition) DeepCopyInto(out *Condition) {
+func (in *BYOOperator) DeepCopyInto(out *BYOOperator) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
-func (in *Condition) DeepCopy() *Condition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BYOOperator.
+func (in *BYOOperator) DeepCopy() *BYOOperator {
 	if in == nil {
 		return nil
 	}
-	out := new(Condition)
+	out := new(BYOOperator)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ConfigResource) DeepCopyInto(out *ConfigResource) {
+func (in *BackupHook) DeepCopyInto(out *BackupHook) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupHook.
+func (in *BackupHook) DeepCopy() *BackupHook {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupPolicy) DeepCopyInto(out *BackupPolicy) {
 	*out = *in
 	if in.Labels != nil {
 		in, out := &in.Labels, &out.Labels
@@ -51,165 +79,163 @@ func (in *ConfigResource) DeepCopyInto(out *ConfigResource) {
 			(*out)[key] = val
 		}
 	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.PreHook != nil {
+		in, out := &in.PreHook, &out.PreHook
+		*out = new(BackupHook)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.Data != nil {
-		in, out := &in.Data, &out.Data
-		*out = new(runtime.RawExtension)
+	if in.PostHook != nil {
+		in, out := &in.PostHook, &out.PostHook
+		*out = new(BackupHook)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigResource.
-func (in *ConfigResource) DeepCopy() *ConfigResource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupPolicy.
+func (in *BackupPolicy) DeepCopy() *BackupPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(ConfigResource)
+	out := new(BackupPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ConfigService) DeepCopyInto(out *ConfigService) {
+func (in *BootstrapDependency) DeepCopyInto(out *BootstrapDependency) {
 	*out = *in
-	if in.Spec != nil {
-		in, out := &in.Spec, &out.Spec
-		*out = make(map[string]runtime.RawExtension, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapDependency.
+func (in *BootstrapDependency) DeepCopy() *BootstrapDependency {
+	if in == nil {
+		return nil
 	}
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = make([]ConfigResource, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	out := new(BootstrapDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapStep) DeepCopyInto(out *BootstrapStep) {
+	*out = *in
+	if in.After != nil {
+		in, out := &in.After, &out.After
+		*out = make([]BootstrapDependency, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigService.
-func (in *ConfigService) DeepCopy() *ConfigService {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapStep.
+func (in *BootstrapStep) DeepCopy() *BootstrapStep {
 	if in == nil {
 		return nil
 	}
-	out := new(ConfigService)
+	out := new(BootstrapStep)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CrStatus) DeepCopyInto(out *CrStatus) {
+func (in *Callback) DeepCopyInto(out *Callback) {
 	*out = *in
-	if in.CrStatus != nil {
-		in, out := &in.CrStatus, &out.CrStatus
-		*out = make(map[string]ServicePhase, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]CallbackEvent, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrStatus.
-func (in *CrStatus) DeepCopy() *CrStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Callback.
+func (in *Callback) DeepCopy() *Callback {
 	if in == nil {
 		return nil
 	}
-	out := new(CrStatus)
+	out := new(Callback)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MemberPhase) DeepCopyInto(out *MemberPhase) {
+func (in *CatalogInstallLimit) DeepCopyInto(out *CatalogInstallLimit) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberPhase.
-func (in *MemberPhase) DeepCopy() *MemberPhase {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogInstallLimit.
+func (in *CatalogInstallLimit) DeepCopy() *CatalogInstallLimit {
 	if in == nil {
 		return nil
 	}
-	out := new(MemberPhase)
+	out := new(CatalogInstallLimit)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MemberStatus) DeepCopyInto(out *MemberStatus) {
+func (in *CatalogMirror) DeepCopyInto(out *CatalogMirror) {
 	*out = *in
-	out.Phase = in.Phase
-	if in.OperandCRList != nil {
-		in, out := &in.OperandCRList, &out.OperandCRList
-		*out = make([]OperandCRMember, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberStatus.
-func (in *MemberStatus) DeepCopy() *MemberStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogMirror.
+func (in *CatalogMirror) DeepCopy() *CatalogMirror {
 	if in == nil {
 		return nil
 	}
-	out := new(MemberStatus)
+	out := new(CatalogMirror)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Operand) DeepCopyInto(out *Operand) {
+func (in *ChannelScheme) DeepCopyInto(out *ChannelScheme) {
 	*out = *in
-	if in.Bindings != nil {
-		in, out := &in.Bindings, &out.Bindings
-		*out = make(map[string]SecretConfigmap, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Spec != nil {
-		in, out := &in.Spec, &out.Spec
-		*out = new(runtime.RawExtension)
-		(*in).DeepCopyInto(*out)
+	if in.Order != nil {
+		in, out := &in.Order, &out.Order
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Operand.
-func (in *Operand) DeepCopy() *Operand {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChannelScheme.
+func (in *ChannelScheme) DeepCopy() *ChannelScheme {
 	if in == nil {
 		return nil
 	}
-	out := new(Operand)
+	out := new(ChannelScheme)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperandBindInfo) DeepCopyInto(out *OperandBindInfo) {
+func (in *ClusterOperandPolicy) DeepCopyInto(out *ClusterOperandPolicy) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandBindInfo.
-func (in *OperandBindInfo) DeepCopy() *OperandBindInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterOperandPolicy.
+func (in *ClusterOperandPolicy) DeepCopy() *ClusterOperandPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(OperandBindInfo)
+	out := new(ClusterOperandPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OperandBindInfo) DeepCopyObject() runtime.Object {
+func (in *ClusterOperandPolicy) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -217,31 +243,31 @@ func (in *OperandBindInfo) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperandBindInfoList) DeepCopyInto(out *OperandBindInfoList) {
+func (in *ClusterOperandPolicyList) DeepCopyInto(out *ClusterOperandPolicyList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]OperandBindInfo, len(*in))
+		*out = make([]ClusterOperandPolicy, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandBindInfoList.
-func (in *OperandBindInfoList) DeepCopy() *OperandBindInfoList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterOperandPolicyList.
+func (in *ClusterOperandPolicyList) DeepCopy() *ClusterOperandPolicyList {
 	if in == nil {
 		return nil
 	}
-	out := new(OperandBindInfoList)
+	out := new(ClusterOperandPolicyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OperandBindInfoList) DeepCopyObject() runtime.Object {
+func (in *ClusterOperandPolicyList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -249,474 +275,3033 @@ func (in *OperandBindInfoList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperandBindInfoSpec) DeepCopyInto(out *OperandBindInfoSpec) {
+func (in *ClusterOperandPolicySpec) DeepCopyInto(out *ClusterOperandPolicySpec) {
 	*out = *in
-	if in.Bindings != nil {
-		in, out := &in.Bindings, &out.Bindings
-		*out = make(map[string]SecretConfigmap, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	if in.OperatorRules != nil {
+		in, out := &in.OperatorRules, &out.OperatorRules
+		*out = make([]OperatorRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MaxOperandsPerNamespace != nil {
+		in, out := &in.MaxOperandsPerNamespace, &out.MaxOperandsPerNamespace
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxMinorVersionJump != nil {
+		in, out := &in.MaxMinorVersionJump, &out.MaxMinorVersionJump
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandBindInfoSpec.
-func (in *OperandBindInfoSpec) DeepCopy() *OperandBindInfoSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterOperandPolicySpec.
+func (in *ClusterOperandPolicySpec) DeepCopy() *ClusterOperandPolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(OperandBindInfoSpec)
+	out := new(ClusterOperandPolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperandBindInfoStatus) DeepCopyInto(out *OperandBindInfoStatus) {
+func (in *ClusterPlacementStatus) DeepCopyInto(out *ClusterPlacementStatus) {
 	*out = *in
-	if in.RequestNamespaces != nil {
-		in, out := &in.RequestNamespaces, &out.RequestNamespaces
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandBindInfoStatus.
-func (in *OperandBindInfoStatus) DeepCopy() *OperandBindInfoStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPlacementStatus.
+func (in *ClusterPlacementStatus) DeepCopy() *ClusterPlacementStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(OperandBindInfoStatus)
+	out := new(ClusterPlacementStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperandCRMember) DeepCopyInto(out *OperandCRMember) {
+func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandCRMember.
-func (in *OperandCRMember) DeepCopy() *OperandCRMember {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
 	if in == nil {
 		return nil
 	}
-	out := new(OperandCRMember)
+	out := new(Condition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperandConfig) DeepCopyInto(out *OperandConfig) {
+func (in *ConfigResource) DeepCopyInto(out *ConfigResource) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandConfig.
-func (in *OperandConfig) DeepCopy() *OperandConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigResource.
+func (in *ConfigResource) DeepCopy() *ConfigResource {
 	if in == nil {
 		return nil
 	}
-	out := new(OperandConfig)
+	out := new(ConfigResource)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OperandConfig) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperandConfigList) DeepCopyInto(out *OperandConfigList) {
+func (in *ConfigService) DeepCopyInto(out *ConfigService) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]OperandConfig, len(*in))
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = make(map[string]runtime.RawExtension, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ConfigResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Prune != nil {
+		in, out := &in.Prune, &out.Prune
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ZoneAware != nil {
+		in, out := &in.ZoneAware, &out.ZoneAware
+		*out = new(ZoneAwareness)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(BackupPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ProxyInjection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PlacementInjection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SizeProfiles != nil {
+		in, out := &in.SizeProfiles, &out.SizeProfiles
+		*out = make([]SizeProfile, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Scaling != nil {
+		in, out := &in.Scaling, &out.Scaling
+		*out = new(ScalingHints)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreDelete != nil {
+		in, out := &in.PreDelete, &out.PreDelete
+		*out = new(PreDeleteHooks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostInstall != nil {
+		in, out := &in.PostInstall, &out.PostInstall
+		*out = new(PostInstallHooks)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandConfigList.
-func (in *OperandConfigList) DeepCopy() *OperandConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigService.
+func (in *ConfigService) DeepCopy() *ConfigService {
 	if in == nil {
 		return nil
 	}
-	out := new(OperandConfigList)
+	out := new(ConfigService)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OperandConfigList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperandConfigSpec) DeepCopyInto(out *OperandConfigSpec) {
+func (in *ControllerThroughput) DeepCopyInto(out *ControllerThroughput) {
 	*out = *in
-	if in.Services != nil {
-		in, out := &in.Services, &out.Services
-		*out = make([]ConfigService, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.RateLimiter != nil {
+		in, out := &in.RateLimiter, &out.RateLimiter
+		*out = new(RateLimiter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FairQueue != nil {
+		in, out := &in.FairQueue, &out.FairQueue
+		*out = new(bool)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandConfigSpec.
-func (in *OperandConfigSpec) DeepCopy() *OperandConfigSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerThroughput.
+func (in *ControllerThroughput) DeepCopy() *ControllerThroughput {
 	if in == nil {
 		return nil
 	}
-	out := new(OperandConfigSpec)
+	out := new(ControllerThroughput)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperandConfigStatus) DeepCopyInto(out *OperandConfigStatus) {
+func (in *ControllerVerbosity) DeepCopyInto(out *ControllerVerbosity) {
 	*out = *in
-	if in.ServiceStatus != nil {
-		in, out := &in.ServiceStatus, &out.ServiceStatus
-		*out = make(map[string]CrStatus, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerVerbosity.
+func (in *ControllerVerbosity) DeepCopy() *ControllerVerbosity {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllerVerbosity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrStatus) DeepCopyInto(out *CrStatus) {
+	*out = *in
+	if in.CrStatus != nil {
+		in, out := &in.CrStatus, &out.CrStatus
+		*out = make(map[string]ServicePhase, len(*in))
 		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
+			(*out)[key] = val
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandConfigStatus.
-func (in *OperandConfigStatus) DeepCopy() *OperandConfigStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrStatus.
+func (in *CrStatus) DeepCopy() *CrStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(OperandConfigStatus)
+	out := new(CrStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperandRegistry) DeepCopyInto(out *OperandRegistry) {
+func (in *DeletionPreview) DeepCopyInto(out *DeletionPreview) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Deleted != nil {
+		in, out := &in.Deleted, &out.Deleted
+		*out = make([]PreviewResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.Retained != nil {
+		in, out := &in.Retained, &out.Retained
+		*out = make([]PreviewResource, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRegistry.
-func (in *OperandRegistry) DeepCopy() *OperandRegistry {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionPreview.
+func (in *DeletionPreview) DeepCopy() *DeletionPreview {
 	if in == nil {
 		return nil
 	}
-	out := new(OperandRegistry)
+	out := new(DeletionPreview)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OperandRegistry) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftedField) DeepCopyInto(out *DriftedField) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftedField.
+func (in *DriftedField) DeepCopy() *DriftedField {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(DriftedField)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperandRegistryList) DeepCopyInto(out *OperandRegistryList) {
+func (in *DriftedResource) DeepCopyInto(out *DriftedResource) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]OperandRegistry, len(*in))
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]DriftedField, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftedResource.
+func (in *DriftedResource) DeepCopy() *DriftedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EffectiveConfig) DeepCopyInto(out *EffectiveConfig) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]EffectiveResource, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRegistryList.
-func (in *OperandRegistryList) DeepCopy() *OperandRegistryList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectiveConfig.
+func (in *EffectiveConfig) DeepCopy() *EffectiveConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(OperandRegistryList)
+	out := new(EffectiveConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OperandRegistryList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EffectiveResource) DeepCopyInto(out *EffectiveResource) {
+	*out = *in
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
 	}
-	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectiveResource.
+func (in *EffectiveResource) DeepCopy() *EffectiveResource {
+	if in == nil {
+		return nil
+	}
+	out := new(EffectiveResource)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperandRegistrySpec) DeepCopyInto(out *OperandRegistrySpec) {
+func (in *Entitlement) DeepCopyInto(out *Entitlement) {
 	*out = *in
-	if in.Operators != nil {
-		in, out := &in.Operators, &out.Operators
-		*out = make([]Operator, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(EntitlementSecretRef)
+		**out = **in
+	}
+	if in.API != nil {
+		in, out := &in.API, &out.API
+		*out = new(HTTPCheck)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRegistrySpec.
-func (in *OperandRegistrySpec) DeepCopy() *OperandRegistrySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Entitlement.
+func (in *Entitlement) DeepCopy() *Entitlement {
 	if in == nil {
 		return nil
 	}
-	out := new(OperandRegistrySpec)
+	out := new(Entitlement)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperandRegistryStatus) DeepCopyInto(out *OperandRegistryStatus) {
+func (in *EntitlementSecretRef) DeepCopyInto(out *EntitlementSecretRef) {
 	*out = *in
-	if in.OperatorsStatus != nil {
-		in, out := &in.OperatorsStatus, &out.OperatorsStatus
-		*out = make(map[string]OperatorStatus, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]Condition, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRegistryStatus.
-func (in *OperandRegistryStatus) DeepCopy() *OperandRegistryStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EntitlementSecretRef.
+func (in *EntitlementSecretRef) DeepCopy() *EntitlementSecretRef {
 	if in == nil {
 		return nil
 	}
-	out := new(OperandRegistryStatus)
+	out := new(EntitlementSecretRef)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperandRequest) DeepCopyInto(out *OperandRequest) {
+func (in *FleetFailingMember) DeepCopyInto(out *FleetFailingMember) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRequest.
-func (in *OperandRequest) DeepCopy() *OperandRequest {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetFailingMember.
+func (in *FleetFailingMember) DeepCopy() *FleetFailingMember {
 	if in == nil {
 		return nil
 	}
-	out := new(OperandRequest)
+	out := new(FleetFailingMember)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OperandRequest) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetPendingUpgrade) DeepCopyInto(out *FleetPendingUpgrade) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetPendingUpgrade.
+func (in *FleetPendingUpgrade) DeepCopy() *FleetPendingUpgrade {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(FleetPendingUpgrade)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperandRequestList) DeepCopyInto(out *OperandRequestList) {
+func (in *FleetResourceSummary) DeepCopyInto(out *FleetResourceSummary) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]OperandRequest, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRequestList.
-func (in *OperandRequestList) DeepCopy() *OperandRequestList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetResourceSummary.
+func (in *FleetResourceSummary) DeepCopy() *FleetResourceSummary {
 	if in == nil {
 		return nil
 	}
-	out := new(OperandRequestList)
+	out := new(FleetResourceSummary)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OperandRequestList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ForensicBundle) DeepCopyInto(out *ForensicBundle) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ForensicBundle.
+func (in *ForensicBundle) DeepCopy() *ForensicBundle {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(ForensicBundle)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperandRequestSpec) DeepCopyInto(out *OperandRequestSpec) {
+func (in *GarbageCollection) DeepCopyInto(out *GarbageCollection) {
 	*out = *in
-	if in.Requests != nil {
-		in, out := &in.Requests, &out.Requests
-		*out = make([]Request, len(*in))
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GarbageCollection.
+func (in *GarbageCollection) DeepCopy() *GarbageCollection {
+	if in == nil {
+		return nil
+	}
+	out := new(GarbageCollection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GarbageCollectionReport) DeepCopyInto(out *GarbageCollectionReport) {
+	*out = *in
+	in.LastCollectionTime.DeepCopyInto(&out.LastCollectionTime)
+	if in.Orphans != nil {
+		in, out := &in.Orphans, &out.Orphans
+		*out = make([]OrphanedSubscription, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRequestSpec.
-func (in *OperandRequestSpec) DeepCopy() *OperandRequestSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GarbageCollectionReport.
+func (in *GarbageCollectionReport) DeepCopy() *GarbageCollectionReport {
 	if in == nil {
 		return nil
 	}
-	out := new(OperandRequestSpec)
+	out := new(GarbageCollectionReport)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperandRequestStatus) DeepCopyInto(out *OperandRequestStatus) {
+func (in *HTTPCheck) DeepCopyInto(out *HTTPCheck) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]Condition, len(*in))
-		copy(*out, *in)
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
 	}
-	if in.Members != nil {
-		in, out := &in.Members, &out.Members
-		*out = make([]MemberStatus, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPCheck.
+func (in *HTTPCheck) DeepCopy() *HTTPCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheck) DeepCopyInto(out *HealthCheck) {
+	*out = *in
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]HealthCheckObjects, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRequestStatus.
-func (in *OperandRequestStatus) DeepCopy() *OperandRequestStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheck.
+func (in *HealthCheck) DeepCopy() *HealthCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(OperandRequestStatus)
+	out := new(HealthCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Operator) DeepCopyInto(out *Operator) {
+func (in *HealthCheckObjects) DeepCopyInto(out *HealthCheckObjects) {
 	*out = *in
-	if in.TargetNamespaces != nil {
-		in, out := &in.TargetNamespaces, &out.TargetNamespaces
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.SubscriptionConfig != nil {
-		in, out := &in.SubscriptionConfig, &out.SubscriptionConfig
-		*out = new(operatorsv1alpha1.SubscriptionConfig)
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Operator.
-func (in *Operator) DeepCopy() *Operator {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheckObjects.
+func (in *HealthCheckObjects) DeepCopy() *HealthCheckObjects {
 	if in == nil {
 		return nil
 	}
-	out := new(Operator)
+	out := new(HealthCheckObjects)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperatorStatus) DeepCopyInto(out *OperatorStatus) {
+func (in *HelmChart) DeepCopyInto(out *HelmChart) {
 	*out = *in
-	if in.ReconcileRequests != nil {
-		in, out := &in.ReconcileRequests, &out.ReconcileRequests
-		*out = make([]ReconcileRequest, len(*in))
-		copy(*out, *in)
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorStatus.
-func (in *OperatorStatus) DeepCopy() *OperatorStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmChart.
+func (in *HelmChart) DeepCopy() *HelmChart {
 	if in == nil {
 		return nil
 	}
-	out := new(OperatorStatus)
+	out := new(HelmChart)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReconcileRequest) DeepCopyInto(out *ReconcileRequest) {
+func (in *HelmReleaseStatus) DeepCopyInto(out *HelmReleaseStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcileRequest.
-func (in *ReconcileRequest) DeepCopy() *ReconcileRequest {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmReleaseStatus.
+func (in *HelmReleaseStatus) DeepCopy() *HelmReleaseStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ReconcileRequest)
+	out := new(HelmReleaseStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Request) DeepCopyInto(out *Request) {
+func (in *HistoryEntry) DeepCopyInto(out *HistoryEntry) {
 	*out = *in
-	if in.Operands != nil {
-		in, out := &in.Operands, &out.Operands
-		*out = make([]Operand, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Request.
-func (in *Request) DeepCopy() *Request {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HistoryEntry.
+func (in *HistoryEntry) DeepCopy() *HistoryEntry {
 	if in == nil {
 		return nil
 	}
-	out := new(Request)
+	out := new(HistoryEntry)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretConfigmap) DeepCopyInto(out *SecretConfigmap) {
+func (in *InstallThrottle) DeepCopyInto(out *InstallThrottle) {
 	*out = *in
+	if in.Catalogs != nil {
+		in, out := &in.Catalogs, &out.Catalogs
+		*out = make([]CatalogInstallLimit, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretConfigmap.
-func (in *SecretConfigmap) DeepCopy() *SecretConfigmap {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallThrottle.
+func (in *InstallThrottle) DeepCopy() *InstallThrottle {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretConfigmap)
+	out := new(InstallThrottle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Logging) DeepCopyInto(out *Logging) {
+	*out = *in
+	if in.Controllers != nil {
+		in, out := &in.Controllers, &out.Controllers
+		*out = make([]ControllerVerbosity, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Logging.
+func (in *Logging) DeepCopy() *Logging {
+	if in == nil {
+		return nil
+	}
+	out := new(Logging)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestsSource) DeepCopyInto(out *ManifestsSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestsSource.
+func (in *ManifestsSource) DeepCopy() *ManifestsSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestsSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemberPhase) DeepCopyInto(out *MemberPhase) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberPhase.
+func (in *MemberPhase) DeepCopy() *MemberPhase {
+	if in == nil {
+		return nil
+	}
+	out := new(MemberPhase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemberRetry) DeepCopyInto(out *MemberRetry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberRetry.
+func (in *MemberRetry) DeepCopy() *MemberRetry {
+	if in == nil {
+		return nil
+	}
+	out := new(MemberRetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemberStatus) DeepCopyInto(out *MemberStatus) {
+	*out = *in
+	out.Phase = in.Phase
+	if in.OperandCRList != nil {
+		in, out := &in.OperandCRList, &out.OperandCRList
+		*out = make([]OperandCRMember, len(*in))
+		copy(*out, *in)
+	}
+	if in.HelmRelease != nil {
+		in, out := &in.HelmRelease, &out.HelmRelease
+		*out = new(HelmReleaseStatus)
+		**out = **in
+	}
+	if in.RequiredBy != nil {
+		in, out := &in.RequiredBy, &out.RequiredBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(UpgradeStatus)
+		**out = **in
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(MemberRetry)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberStatus.
+func (in *MemberStatus) DeepCopy() *MemberStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MemberStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Metrics) DeepCopyInto(out *Metrics) {
+	*out = *in
+	if in.MaxLabelValues != nil {
+		in, out := &in.MaxLabelValues, &out.MaxLabelValues
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Metrics.
+func (in *Metrics) DeepCopy() *Metrics {
+	if in == nil {
+		return nil
+	}
+	out := new(Metrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Mirrors) DeepCopyInto(out *Mirrors) {
+	*out = *in
+	if in.Catalogs != nil {
+		in, out := &in.Catalogs, &out.Catalogs
+		*out = make([]CatalogMirror, len(*in))
+		copy(*out, *in)
+	}
+	if in.Registries != nil {
+		in, out := &in.Registries, &out.Registries
+		*out = make([]RegistryMirror, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Mirrors.
+func (in *Mirrors) DeepCopy() *Mirrors {
+	if in == nil {
+		return nil
+	}
+	out := new(Mirrors)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamedControllerThroughput) DeepCopyInto(out *NamedControllerThroughput) {
+	*out = *in
+	in.ControllerThroughput.DeepCopyInto(&out.ControllerThroughput)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamedControllerThroughput.
+func (in *NamedControllerThroughput) DeepCopy() *NamedControllerThroughput {
+	if in == nil {
+		return nil
+	}
+	out := new(NamedControllerThroughput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceProvisioning) DeepCopyInto(out *NamespaceProvisioning) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceProvisioning.
+func (in *NamespaceProvisioning) DeepCopy() *NamespaceProvisioning {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceProvisioning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Operand) DeepCopyInto(out *Operand) {
+	*out = *in
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make(map[string]SecretConfigmap, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NotBefore != nil {
+		in, out := &in.NotBefore, &out.NotBefore
+		*out = (*in).DeepCopy()
+	}
+	if in.Uses != nil {
+		in, out := &in.Uses, &out.Uses
+		*out = make([]OperandUse, len(*in))
+		copy(*out, *in)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]PatchOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Operand.
+func (in *Operand) DeepCopy() *Operand {
+	if in == nil {
+		return nil
+	}
+	out := new(Operand)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandBindInfo) DeepCopyInto(out *OperandBindInfo) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandBindInfo.
+func (in *OperandBindInfo) DeepCopy() *OperandBindInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandBindInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperandBindInfo) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandBindInfoList) DeepCopyInto(out *OperandBindInfoList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperandBindInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandBindInfoList.
+func (in *OperandBindInfoList) DeepCopy() *OperandBindInfoList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandBindInfoList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperandBindInfoList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandBindInfoSpec) DeepCopyInto(out *OperandBindInfoSpec) {
+	*out = *in
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make(map[string]SecretConfigmap, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandBindInfoSpec.
+func (in *OperandBindInfoSpec) DeepCopy() *OperandBindInfoSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandBindInfoSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandBindInfoStatus) DeepCopyInto(out *OperandBindInfoStatus) {
+	*out = *in
+	if in.RequestNamespaces != nil {
+		in, out := &in.RequestNamespaces, &out.RequestNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandBindInfoStatus.
+func (in *OperandBindInfoStatus) DeepCopy() *OperandBindInfoStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandBindInfoStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandBootstrap) DeepCopyInto(out *OperandBootstrap) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandBootstrap.
+func (in *OperandBootstrap) DeepCopy() *OperandBootstrap {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandBootstrap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperandBootstrap) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandBootstrapList) DeepCopyInto(out *OperandBootstrapList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperandBootstrap, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandBootstrapList.
+func (in *OperandBootstrapList) DeepCopy() *OperandBootstrapList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandBootstrapList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperandBootstrapList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandBootstrapSpec) DeepCopyInto(out *OperandBootstrapSpec) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]BootstrapStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandBootstrapSpec.
+func (in *OperandBootstrapSpec) DeepCopy() *OperandBootstrapSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandBootstrapSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandCRMember) DeepCopyInto(out *OperandCRMember) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandCRMember.
+func (in *OperandCRMember) DeepCopy() *OperandCRMember {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandCRMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandConfig) DeepCopyInto(out *OperandConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandConfig.
+func (in *OperandConfig) DeepCopy() *OperandConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperandConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandConfigList) DeepCopyInto(out *OperandConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperandConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandConfigList.
+func (in *OperandConfigList) DeepCopy() *OperandConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperandConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandConfigSpec) DeepCopyInto(out *OperandConfigSpec) {
+	*out = *in
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]ConfigService, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandConfigSpec.
+func (in *OperandConfigSpec) DeepCopy() *OperandConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandConfigStatus) DeepCopyInto(out *OperandConfigStatus) {
+	*out = *in
+	if in.ServiceStatus != nil {
+		in, out := &in.ServiceStatus, &out.ServiceStatus
+		*out = make(map[string]CrStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Scorecard != nil {
+		in, out := &in.Scorecard, &out.Scorecard
+		*out = new(Scorecard)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Deployments != nil {
+		in, out := &in.Deployments, &out.Deployments
+		*out = make(map[string]ServiceDeployment, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandConfigStatus.
+func (in *OperandConfigStatus) DeepCopy() *OperandConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandFleetStatus) DeepCopyInto(out *OperandFleetStatus) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandFleetStatus.
+func (in *OperandFleetStatus) DeepCopy() *OperandFleetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandFleetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperandFleetStatus) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandFleetStatusList) DeepCopyInto(out *OperandFleetStatusList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperandFleetStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandFleetStatusList.
+func (in *OperandFleetStatusList) DeepCopy() *OperandFleetStatusList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandFleetStatusList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperandFleetStatusList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandFleetStatusSpec) DeepCopyInto(out *OperandFleetStatusSpec) {
+	*out = *in
+	if in.MaxItems != nil {
+		in, out := &in.MaxItems, &out.MaxItems
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandFleetStatusSpec.
+func (in *OperandFleetStatusSpec) DeepCopy() *OperandFleetStatusSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandFleetStatusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandFleetStatusStatus) DeepCopyInto(out *OperandFleetStatusStatus) {
+	*out = *in
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	in.OperandRequests.DeepCopyInto(&out.OperandRequests)
+	in.OperandRegistries.DeepCopyInto(&out.OperandRegistries)
+	in.OperandConfigs.DeepCopyInto(&out.OperandConfigs)
+	if in.FailingMembers != nil {
+		in, out := &in.FailingMembers, &out.FailingMembers
+		*out = make([]FleetFailingMember, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingUpgrades != nil {
+		in, out := &in.PendingUpgrades, &out.PendingUpgrades
+		*out = make([]FleetPendingUpgrade, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandFleetStatusStatus.
+func (in *OperandFleetStatusStatus) DeepCopy() *OperandFleetStatusStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandFleetStatusStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandPlacement) DeepCopyInto(out *OperandPlacement) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandPlacement.
+func (in *OperandPlacement) DeepCopy() *OperandPlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandPlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandRegistry) DeepCopyInto(out *OperandRegistry) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRegistry.
+func (in *OperandRegistry) DeepCopy() *OperandRegistry {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandRegistry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperandRegistry) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandRegistryList) DeepCopyInto(out *OperandRegistryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperandRegistry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRegistryList.
+func (in *OperandRegistryList) DeepCopy() *OperandRegistryList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandRegistryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperandRegistryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandRegistrySpec) DeepCopyInto(out *OperandRegistrySpec) {
+	*out = *in
+	if in.Operators != nil {
+		in, out := &in.Operators, &out.Operators
+		*out = make([]Operator, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NamespaceProvisioning != nil {
+		in, out := &in.NamespaceProvisioning, &out.NamespaceProvisioning
+		*out = new(NamespaceProvisioning)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRegistrySpec.
+func (in *OperandRegistrySpec) DeepCopy() *OperandRegistrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandRegistrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandRegistryStatus) DeepCopyInto(out *OperandRegistryStatus) {
+	*out = *in
+	if in.OperatorsStatus != nil {
+		in, out := &in.OperatorsStatus, &out.OperatorsStatus
+		*out = make(map[string]OperatorStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRegistryStatus.
+func (in *OperandRegistryStatus) DeepCopy() *OperandRegistryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandRegistryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandRequest) DeepCopyInto(out *OperandRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRequest.
+func (in *OperandRequest) DeepCopy() *OperandRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperandRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandRequestList) DeepCopyInto(out *OperandRequestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperandRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRequestList.
+func (in *OperandRequestList) DeepCopy() *OperandRequestList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandRequestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperandRequestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandRequestSet) DeepCopyInto(out *OperandRequestSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRequestSet.
+func (in *OperandRequestSet) DeepCopy() *OperandRequestSet {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandRequestSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperandRequestSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandRequestSetList) DeepCopyInto(out *OperandRequestSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperandRequestSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRequestSetList.
+func (in *OperandRequestSetList) DeepCopy() *OperandRequestSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandRequestSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperandRequestSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandRequestSetMember) DeepCopyInto(out *OperandRequestSetMember) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRequestSetMember.
+func (in *OperandRequestSetMember) DeepCopy() *OperandRequestSetMember {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandRequestSetMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandRequestSetSpec) DeepCopyInto(out *OperandRequestSetSpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRequestSetSpec.
+func (in *OperandRequestSetSpec) DeepCopy() *OperandRequestSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandRequestSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandRequestSetStatus) DeepCopyInto(out *OperandRequestSetStatus) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]OperandRequestSetMember, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRequestSetStatus.
+func (in *OperandRequestSetStatus) DeepCopy() *OperandRequestSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandRequestSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandRequestSpec) DeepCopyInto(out *OperandRequestSpec) {
+	*out = *in
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make([]Request, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NotBefore != nil {
+		in, out := &in.NotBefore, &out.NotBefore
+		*out = (*in).DeepCopy()
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(OperandPlacement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TeardownGracePeriod != nil {
+		in, out := &in.TeardownGracePeriod, &out.TeardownGracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRequestSpec.
+func (in *OperandRequestSpec) DeepCopy() *OperandRequestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandRequestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandRequestStatus) DeepCopyInto(out *OperandRequestStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		copy(*out, *in)
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]MemberStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = make([]ClusterPlacementStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeletionPreview != nil {
+		in, out := &in.DeletionPreview, &out.DeletionPreview
+		*out = new(DeletionPreview)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EffectiveConfig != nil {
+		in, out := &in.EffectiveConfig, &out.EffectiveConfig
+		*out = new(EffectiveConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DriftDiff != nil {
+		in, out := &in.DriftDiff, &out.DriftDiff
+		*out = make([]DriftedResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]HistoryEntry, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRequestStatus.
+func (in *OperandRequestStatus) DeepCopy() *OperandRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandRequestTemplate) DeepCopyInto(out *OperandRequestTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandRequestTemplate.
+func (in *OperandRequestTemplate) DeepCopy() *OperandRequestTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandRequestTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandShard) DeepCopyInto(out *OperandShard) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandShard.
+func (in *OperandShard) DeepCopy() *OperandShard {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandShard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperandShard) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandShardList) DeepCopyInto(out *OperandShardList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperandShard, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandShardList.
+func (in *OperandShardList) DeepCopy() *OperandShardList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandShardList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperandShardList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandShardSpec) DeepCopyInto(out *OperandShardSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandShardSpec.
+func (in *OperandShardSpec) DeepCopy() *OperandShardSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandShardSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandShardStatus) DeepCopyInto(out *OperandShardStatus) {
+	*out = *in
+	if in.RenewTime != nil {
+		in, out := &in.RenewTime, &out.RenewTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandShardStatus.
+func (in *OperandShardStatus) DeepCopy() *OperandShardStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandShardStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandSnapshot) DeepCopyInto(out *OperandSnapshot) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandSnapshot.
+func (in *OperandSnapshot) DeepCopy() *OperandSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperandSnapshot) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandSnapshotList) DeepCopyInto(out *OperandSnapshotList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperandSnapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandSnapshotList.
+func (in *OperandSnapshotList) DeepCopy() *OperandSnapshotList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandSnapshotList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperandSnapshotList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandSnapshotSpec) DeepCopyInto(out *OperandSnapshotSpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(SnapshotExport)
+		**out = **in
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(SnapshotSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandSnapshotSpec.
+func (in *OperandSnapshotSpec) DeepCopy() *OperandSnapshotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandSnapshotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandSnapshotStatus) DeepCopyInto(out *OperandSnapshotStatus) {
+	*out = *in
+	if in.Content != nil {
+		in, out := &in.Content, &out.Content
+		*out = new(SnapshotContent)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandSnapshotStatus.
+func (in *OperandSnapshotStatus) DeepCopy() *OperandSnapshotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandSnapshotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandUse) DeepCopyInto(out *OperandUse) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandUse.
+func (in *OperandUse) DeepCopy() *OperandUse {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandUse)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Operator) DeepCopyInto(out *Operator) {
+	*out = *in
+	if in.TargetNamespaces != nil {
+		in, out := &in.TargetNamespaces, &out.TargetNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SubscriptionConfig != nil {
+		in, out := &in.SubscriptionConfig, &out.SubscriptionConfig
+		*out = new(operatorsv1alpha1.SubscriptionConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Manifests != nil {
+		in, out := &in.Manifests, &out.Manifests
+		*out = new(ManifestsSource)
+		**out = **in
+	}
+	if in.Chart != nil {
+		in, out := &in.Chart, &out.Chart
+		*out = new(HelmChart)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BYO != nil {
+		in, out := &in.BYO, &out.BYO
+		*out = new(BYOOperator)
+		**out = **in
+	}
+	if in.Requires != nil {
+		in, out := &in.Requires, &out.Requires
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PreviousVersion != nil {
+		in, out := &in.PreviousVersion, &out.PreviousVersion
+		*out = new(OperatorVersion)
+		**out = **in
+	}
+	if in.AllowSkipRange != nil {
+		in, out := &in.AllowSkipRange, &out.AllowSkipRange
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ChannelScheme != nil {
+		in, out := &in.ChannelScheme, &out.ChannelScheme
+		*out = new(ChannelScheme)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Entitlement != nil {
+		in, out := &in.Entitlement, &out.Entitlement
+		*out = new(Entitlement)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Operator.
+func (in *Operator) DeepCopy() *Operator {
+	if in == nil {
+		return nil
+	}
+	out := new(Operator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorCheckerOptions) DeepCopyInto(out *OperatorCheckerOptions) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Jitter != nil {
+		in, out := &in.Jitter, &out.Jitter
+		*out = new(int)
+		**out = **in
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Packages != nil {
+		in, out := &in.Packages, &out.Packages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorCheckerOptions.
+func (in *OperatorCheckerOptions) DeepCopy() *OperatorCheckerOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorCheckerOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfig) DeepCopyInto(out *OperatorConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfig.
+func (in *OperatorConfig) DeepCopy() *OperatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigList) DeepCopyInto(out *OperatorConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperatorConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigList.
+func (in *OperatorConfigList) DeepCopy() *OperatorConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigSpec) DeepCopyInto(out *OperatorConfigSpec) {
+	*out = *in
+	if in.IsolatedMode != nil {
+		in, out := &in.IsolatedMode, &out.IsolatedMode
+		*out = new(bool)
+		**out = **in
+	}
+	if in.WatchNamespace != nil {
+		in, out := &in.WatchNamespace, &out.WatchNamespace
+		*out = new(string)
+		**out = **in
+	}
+	if in.WatchNamespaceSelector != nil {
+		in, out := &in.WatchNamespaceSelector, &out.WatchNamespaceSelector
+		*out = new(string)
+		**out = **in
+	}
+	if in.OperatorChecker != nil {
+		in, out := &in.OperatorChecker, &out.OperatorChecker
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OperatorCheckerOptions != nil {
+		in, out := &in.OperatorCheckerOptions, &out.OperatorCheckerOptions
+		*out = new(OperatorCheckerOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ForensicBundle != nil {
+		in, out := &in.ForensicBundle, &out.ForensicBundle
+		*out = new(ForensicBundle)
+		**out = **in
+	}
+	if in.InstallThrottle != nil {
+		in, out := &in.InstallThrottle, &out.InstallThrottle
+		*out = new(InstallThrottle)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(Logging)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Throughput != nil {
+		in, out := &in.Throughput, &out.Throughput
+		*out = new(Throughput)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RepairOperatorGroups != nil {
+		in, out := &in.RepairOperatorGroups, &out.RepairOperatorGroups
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Callbacks != nil {
+		in, out := &in.Callbacks, &out.Callbacks
+		*out = make([]Callback, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Mirrors != nil {
+		in, out := &in.Mirrors, &out.Mirrors
+		*out = new(Mirrors)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PlacementProfiles != nil {
+		in, out := &in.PlacementProfiles, &out.PlacementProfiles
+		*out = make([]PlacementProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(Metrics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GarbageCollection != nil {
+		in, out := &in.GarbageCollection, &out.GarbageCollection
+		*out = new(GarbageCollection)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigSpec.
+func (in *OperatorConfigSpec) DeepCopy() *OperatorConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigStatus) DeepCopyInto(out *OperatorConfigStatus) {
+	*out = *in
+	if in.Applied != nil {
+		in, out := &in.Applied, &out.Applied
+		*out = new(OperatorSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GarbageCollection != nil {
+		in, out := &in.GarbageCollection, &out.GarbageCollection
+		*out = new(GarbageCollectionReport)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigStatus.
+func (in *OperatorConfigStatus) DeepCopy() *OperatorConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorRule) DeepCopyInto(out *OperatorRule) {
+	*out = *in
+	if in.Operators != nil {
+		in, out := &in.Operators, &out.Operators
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorRule.
+func (in *OperatorRule) DeepCopy() *OperatorRule {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorSettings) DeepCopyInto(out *OperatorSettings) {
+	*out = *in
+	out.OperatorCheckerInterval = in.OperatorCheckerInterval
+	if in.OperatorCheckerNamespaces != nil {
+		in, out := &in.OperatorCheckerNamespaces, &out.OperatorCheckerNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OperatorCheckerPackages != nil {
+		in, out := &in.OperatorCheckerPackages, &out.OperatorCheckerPackages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CatalogInstallLimits != nil {
+		in, out := &in.CatalogInstallLimits, &out.CatalogInstallLimits
+		*out = make([]CatalogInstallLimit, len(*in))
+		copy(*out, *in)
+	}
+	if in.ControllerVerbosities != nil {
+		in, out := &in.ControllerVerbosities, &out.ControllerVerbosities
+		*out = make([]ControllerVerbosity, len(*in))
+		copy(*out, *in)
+	}
+	in.Throughput.DeepCopyInto(&out.Throughput)
+	if in.Callbacks != nil {
+		in, out := &in.Callbacks, &out.Callbacks
+		*out = make([]Callback, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CatalogMirrors != nil {
+		in, out := &in.CatalogMirrors, &out.CatalogMirrors
+		*out = make([]CatalogMirror, len(*in))
+		copy(*out, *in)
+	}
+	if in.RegistryMirrors != nil {
+		in, out := &in.RegistryMirrors, &out.RegistryMirrors
+		*out = make([]RegistryMirror, len(*in))
+		copy(*out, *in)
+	}
+	if in.PlacementProfiles != nil {
+		in, out := &in.PlacementProfiles, &out.PlacementProfiles
+		*out = make([]PlacementProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.GarbageCollectionInterval = in.GarbageCollectionInterval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorSettings.
+func (in *OperatorSettings) DeepCopy() *OperatorSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorStatus) DeepCopyInto(out *OperatorStatus) {
+	*out = *in
+	if in.ReconcileRequests != nil {
+		in, out := &in.ReconcileRequests, &out.ReconcileRequests
+		*out = make([]ReconcileRequest, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorStatus.
+func (in *OperatorStatus) DeepCopy() *OperatorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorVersion) DeepCopyInto(out *OperatorVersion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorVersion.
+func (in *OperatorVersion) DeepCopy() *OperatorVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrphanedSubscription) DeepCopyInto(out *OrphanedSubscription) {
+	*out = *in
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanedSubscription.
+func (in *OrphanedSubscription) DeepCopy() *OrphanedSubscription {
+	if in == nil {
+		return nil
+	}
+	out := new(OrphanedSubscription)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatchOperation) DeepCopyInto(out *PatchOperation) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchOperation.
+func (in *PatchOperation) DeepCopy() *PatchOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(PatchOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementInjection) DeepCopyInto(out *PlacementInjection) {
+	*out = *in
+	if in.CustomResources != nil {
+		in, out := &in.CustomResources, &out.CustomResources
+		*out = make([]PlacementResource, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementInjection.
+func (in *PlacementInjection) DeepCopy() *PlacementInjection {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementInjection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementProfile) DeepCopyInto(out *PlacementProfile) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementProfile.
+func (in *PlacementProfile) DeepCopy() *PlacementProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementResource) DeepCopyInto(out *PlacementResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementResource.
+func (in *PlacementResource) DeepCopy() *PlacementResource {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostInstallHooks) DeepCopyInto(out *PostInstallHooks) {
+	*out = *in
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPCheck)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostInstallHooks.
+func (in *PostInstallHooks) DeepCopy() *PostInstallHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(PostInstallHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreDeleteHooks) DeepCopyInto(out *PreDeleteHooks) {
+	*out = *in
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CustomResources != nil {
+		in, out := &in.CustomResources, &out.CustomResources
+		*out = make([]PreDeleteResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreDeleteHooks.
+func (in *PreDeleteHooks) DeepCopy() *PreDeleteHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(PreDeleteHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreDeleteResource) DeepCopyInto(out *PreDeleteResource) {
+	*out = *in
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreDeleteResource.
+func (in *PreDeleteResource) DeepCopy() *PreDeleteResource {
+	if in == nil {
+		return nil
+	}
+	out := new(PreDeleteResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreviewResource) DeepCopyInto(out *PreviewResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreviewResource.
+func (in *PreviewResource) DeepCopy() *PreviewResource {
+	if in == nil {
+		return nil
+	}
+	out := new(PreviewResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyInjection) DeepCopyInto(out *ProxyInjection) {
+	*out = *in
+	if in.CustomResources != nil {
+		in, out := &in.CustomResources, &out.CustomResources
+		*out = make([]ProxyResource, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyInjection.
+func (in *ProxyInjection) DeepCopy() *ProxyInjection {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyInjection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyResource) DeepCopyInto(out *ProxyResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyResource.
+func (in *ProxyResource) DeepCopy() *ProxyResource {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimiter) DeepCopyInto(out *RateLimiter) {
+	*out = *in
+	if in.BaseDelay != nil {
+		in, out := &in.BaseDelay, &out.BaseDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxDelay != nil {
+		in, out := &in.MaxDelay, &out.MaxDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimiter.
+func (in *RateLimiter) DeepCopy() *RateLimiter {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimiter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconcileRequest) DeepCopyInto(out *ReconcileRequest) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcileRequest.
+func (in *ReconcileRequest) DeepCopy() *ReconcileRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconcileRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryMirror) DeepCopyInto(out *RegistryMirror) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryMirror.
+func (in *RegistryMirror) DeepCopy() *RegistryMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Request) DeepCopyInto(out *Request) {
+	*out = *in
+	if in.Operands != nil {
+		in, out := &in.Operands, &out.Operands
+		*out = make([]Operand, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Request.
+func (in *Request) DeepCopy() *Request {
+	if in == nil {
+		return nil
+	}
+	out := new(Request)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingHints) DeepCopyInto(out *ScalingHints) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxReplicas != nil {
+		in, out := &in.MaxReplicas, &out.MaxReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CustomResources != nil {
+		in, out := &in.CustomResources, &out.CustomResources
+		*out = make([]ScalingResource, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingHints.
+func (in *ScalingHints) DeepCopy() *ScalingHints {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingHints)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingResource) DeepCopyInto(out *ScalingResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingResource.
+func (in *ScalingResource) DeepCopy() *ScalingResource {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Scorecard) DeepCopyInto(out *Scorecard) {
+	*out = *in
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]ServiceScorecard, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Scorecard.
+func (in *Scorecard) DeepCopy() *Scorecard {
+	if in == nil {
+		return nil
+	}
+	out := new(Scorecard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScorecardFinding) DeepCopyInto(out *ScorecardFinding) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScorecardFinding.
+func (in *ScorecardFinding) DeepCopy() *ScorecardFinding {
+	if in == nil {
+		return nil
+	}
+	out := new(ScorecardFinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretConfigmap) DeepCopyInto(out *SecretConfigmap) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretConfigmap.
+func (in *SecretConfigmap) DeepCopy() *SecretConfigmap {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretConfigmap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceDeployment) DeepCopyInto(out *ServiceDeployment) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drifted != nil {
+		in, out := &in.Drifted, &out.Drifted
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceDeployment.
+func (in *ServiceDeployment) DeepCopy() *ServiceDeployment {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceDeployment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceScorecard) DeepCopyInto(out *ServiceScorecard) {
+	*out = *in
+	if in.Passed != nil {
+		in, out := &in.Passed, &out.Passed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Failed != nil {
+		in, out := &in.Failed, &out.Failed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Findings != nil {
+		in, out := &in.Findings, &out.Findings
+		*out = make([]ScorecardFinding, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceScorecard.
+func (in *ServiceScorecard) DeepCopy() *ServiceScorecard {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceScorecard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SizeProfile) DeepCopyInto(out *SizeProfile) {
+	*out = *in
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = make(map[string]runtime.RawExtension, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SizeProfile.
+func (in *SizeProfile) DeepCopy() *SizeProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(SizeProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotContent) DeepCopyInto(out *SnapshotContent) {
+	*out = *in
+	if in.OperandRegistries != nil {
+		in, out := &in.OperandRegistries, &out.OperandRegistries
+		*out = make([]SnapshotObject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.OperandConfigs != nil {
+		in, out := &in.OperandConfigs, &out.OperandConfigs
+		*out = make([]SnapshotObject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.OperandRequests != nil {
+		in, out := &in.OperandRequests, &out.OperandRequests
+		*out = make([]SnapshotObject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Operands != nil {
+		in, out := &in.Operands, &out.Operands
+		*out = make([]SnapshotObject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotContent.
+func (in *SnapshotContent) DeepCopy() *SnapshotContent {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotContent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotExport) DeepCopyInto(out *SnapshotExport) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotExport.
+func (in *SnapshotExport) DeepCopy() *SnapshotExport {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotExport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotObject) DeepCopyInto(out *SnapshotObject) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotObject.
+func (in *SnapshotObject) DeepCopy() *SnapshotObject {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotSource) DeepCopyInto(out *SnapshotSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotSource.
+func (in *SnapshotSource) DeepCopy() *SnapshotSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Throughput) DeepCopyInto(out *Throughput) {
+	*out = *in
+	in.ControllerThroughput.DeepCopyInto(&out.ControllerThroughput)
+	if in.Controllers != nil {
+		in, out := &in.Controllers, &out.Controllers
+		*out = make([]NamedControllerThroughput, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Throughput.
+func (in *Throughput) DeepCopy() *Throughput {
+	if in == nil {
+		return nil
+	}
+	out := new(Throughput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeStatus) DeepCopyInto(out *UpgradeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeStatus.
+func (in *UpgradeStatus) DeepCopy() *UpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneAwareResource) DeepCopyInto(out *ZoneAwareResource) {
+	*out = *in
+	if in.PodLabels != nil {
+		in, out := &in.PodLabels, &out.PodLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneAwareResource.
+func (in *ZoneAwareResource) DeepCopy() *ZoneAwareResource {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneAwareResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneAwareness) DeepCopyInto(out *ZoneAwareness) {
+	*out = *in
+	if in.CustomResources != nil {
+		in, out := &in.CustomResources, &out.CustomResources
+		*out = make([]ZoneAwareResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneAwareness.
+func (in *ZoneAwareness) DeepCopy() *ZoneAwareness {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneAwareness)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -0,0 +1,129 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1beta2
+
+import (
+	"sort"
+
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+// ConvertTo converts the OperandConfig to the v1alpha1 version, which is the storage version.
+func (src *OperandConfig) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.OperandConfig)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Status = *src.Status.DeepCopy()
+
+	dst.Spec.Services = nil
+	for _, service := range src.Spec.Services {
+		service := service.DeepCopy()
+		converted := v1alpha1.ConfigService{
+			Name:               service.Name,
+			State:              service.State,
+			Resources:          service.Resources,
+			Prune:              service.Prune,
+			Remediation:        service.Remediation,
+			Values:             service.Values,
+			ZoneAware:          service.ZoneAware,
+			HealthCheck:        service.HealthCheck,
+			Backup:             service.Backup,
+			Proxy:              service.Proxy,
+			Placement:          service.Placement,
+			DefaultSizeProfile: service.DefaultSizeProfile,
+			Scaling:            service.Scaling,
+			PreDelete:          service.PreDelete,
+			PostInstall:        service.PostInstall,
+		}
+		converted.Spec = toSpecMap(service.CustomResources)
+		for _, p := range service.SizeProfiles {
+			converted.SizeProfiles = append(converted.SizeProfiles, v1alpha1.SizeProfile{Name: p.Name, Spec: toSpecMap(p.CustomResources)})
+		}
+		dst.Spec.Services = append(dst.Spec.Services, converted)
+	}
+	return nil
+}
+
+// ConvertFrom converts the OperandConfig from the v1alpha1 version, which is the storage version.
+// The custom resources are sorted by their kind, the v1alpha1 spec map has no order.
+func (dst *OperandConfig) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.OperandConfig)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Status = *src.Status.DeepCopy()
+
+	dst.Spec.Services = nil
+	for _, service := range src.Spec.Services {
+		service := service.DeepCopy()
+		converted := ConfigService{
+			Name:               service.Name,
+			State:              service.State,
+			Resources:          service.Resources,
+			Prune:              service.Prune,
+			Remediation:        service.Remediation,
+			Values:             service.Values,
+			ZoneAware:          service.ZoneAware,
+			HealthCheck:        service.HealthCheck,
+			Backup:             service.Backup,
+			Proxy:              service.Proxy,
+			Placement:          service.Placement,
+			DefaultSizeProfile: service.DefaultSizeProfile,
+			Scaling:            service.Scaling,
+			PreDelete:          service.PreDelete,
+			PostInstall:        service.PostInstall,
+		}
+		converted.CustomResources = fromSpecMap(service.Spec)
+		for _, p := range service.SizeProfiles {
+			converted.SizeProfiles = append(converted.SizeProfiles, SizeProfile{Name: p.Name, CustomResources: fromSpecMap(p.Spec)})
+		}
+		dst.Spec.Services = append(dst.Spec.Services, converted)
+	}
+	return nil
+}
+
+// toSpecMap converts the custom resources into the v1alpha1 spec map keyed by their kind
+func toSpecMap(crs []ConfigCustomResource) map[string]runtime.RawExtension {
+	var specs map[string]runtime.RawExtension
+	for _, cr := range crs {
+		if specs == nil {
+			specs = make(map[string]runtime.RawExtension)
+		}
+		spec := runtime.RawExtension{}
+		if cr.Spec != nil {
+			spec = *cr.Spec
+		}
+		specs[cr.Kind] = spec
+	}
+	return specs
+}
+
+// fromSpecMap converts the v1alpha1 spec map into the custom resources sorted by their kind
+func fromSpecMap(specs map[string]runtime.RawExtension) []ConfigCustomResource {
+	var crs []ConfigCustomResource
+	for kind, spec := range specs {
+		cr := ConfigCustomResource{Kind: kind}
+		if spec.Raw != nil {
+			cr.Spec = &runtime.RawExtension{Raw: spec.Raw}
+		}
+		crs = append(crs, cr)
+	}
+	sort.Slice(crs, func(i, j int) bool {
+		return crs[i].Kind < crs[j].Kind
+	})
+	return crs
+}
@@ -0,0 +1,174 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1beta2
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// OperandConfigSpec defines the desired state of OperandConfig.
+type OperandConfigSpec struct {
+	// Services is a list of configuration of service.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Operand Services Config List"
+	// +optional
+	Services []ConfigService `json:"services,omitempty"`
+}
+
+// ConfigService defines the configuration of the service.
+type ConfigService struct {
+	// Name is the subscription name.
+	Name string `json:"name"`
+	// CustomResources are the templates of the custom resources of the service.
+	// They replace the spec map of v1alpha1, keyed by the kind of the custom resources.
+	// +listType=map
+	// +listMapKey=kind
+	// +optional
+	CustomResources []ConfigCustomResource `json:"customResources,omitempty"`
+	// State is a flag to enable or disable service.
+	// +optional
+	State string `json:"state,omitempty"`
+	// Resources is used to specify the kubernetes resources that are needed for the service.
+	// +optional
+	Resources []v1alpha1.ConfigResource `json:"resources,omitempty"`
+	// Prune is used to determine whether the fields removed from the spec are also removed from the custom resources.
+	// The default is false, the removed fields are kept in the custom resources.
+	// +optional
+	Prune *bool `json:"prune,omitempty"`
+	// Remediation is the policy for the changes made to the custom resources which conflict with the configuration.
+	// Enforce reverts them, Detect keeps them and reports them with a Drifted condition and an event,
+	// and Ignore keeps them. The default is Enforce.
+	// +kubebuilder:validation:Enum=Enforce;Detect;Ignore
+	// +optional
+	Remediation string `json:"remediation,omitempty"`
+	// Values are the values of the Helm release, for the operator installed by Helm.
+	// They are merged into the default values in the OperandRegistry.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Values *runtime.RawExtension `json:"values,omitempty"`
+	// ZoneAware renders the zone-aware settings of the service from the zones detected in the cluster,
+	// so that the service survives a single zone failure.
+	// +optional
+	ZoneAware *v1alpha1.ZoneAwareness `json:"zoneAware,omitempty"`
+	// HealthCheck decides when the service is healthy, for the operators whose custom resources
+	// don't report a reliable status.
+	// +optional
+	HealthCheck *v1alpha1.HealthCheck `json:"healthCheck,omitempty"`
+	// Backup declares how the backup tooling like Velero and OADP includes the service.
+	// +optional
+	Backup *v1alpha1.BackupPolicy `json:"backup,omitempty"`
+	// Proxy renders the proxy and the CA bundle of the cluster into the custom resources of the service,
+	// for the operands which need them in their own spec.
+	// +optional
+	Proxy *v1alpha1.ProxyInjection `json:"proxy,omitempty"`
+	// Placement schedules the pods of the service with a placement profile of the OperatorConfig.
+	// +optional
+	Placement *v1alpha1.PlacementInjection `json:"placement,omitempty"`
+	// SizeProfiles are the named sizes of the service, like small, medium and large, declared from the smallest
+	// to the largest. The OperandRequests select one with the SizeProfile of the operand.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	SizeProfiles []SizeProfile `json:"sizeProfiles,omitempty"`
+	// DefaultSizeProfile is the size profile of the service when no OperandRequest selects one.
+	// The custom resources of the service are used unchanged when it is not set.
+	// +optional
+	DefaultSizeProfile string `json:"defaultSizeProfile,omitempty"`
+	// Scaling declares where the replicas requested by the OperandRequests are rendered in the custom resources
+	// of the service, so the consumers scale the service without knowing the schemas of its custom resources.
+	// +optional
+	Scaling *v1alpha1.ScalingHints `json:"scaling,omitempty"`
+	// PreDelete declares the hooks ODLM runs and waits for before it deletes the custom resources of the service,
+	// so the services holding data, like databases and message queues, drain or deprovision it first.
+	// +optional
+	PreDelete *v1alpha1.PreDeleteHooks `json:"preDelete,omitempty"`
+	// PostInstall declares the hooks ODLM runs after the custom resources of the service are ready,
+	// the operand only turns Running after they succeed.
+	// +optional
+	PostInstall *v1alpha1.PostInstallHooks `json:"postInstall,omitempty"`
+}
+
+// SizeProfile defines a named size of a service.
+type SizeProfile struct {
+	// Name is the name of the size profile, like small, medium or large.
+	Name string `json:"name"`
+	// CustomResources are the sizing settings of the custom resources, like their resources and replicas.
+	// They are merged into the custom resources of the service and take precedence.
+	// +listType=map
+	// +listMapKey=kind
+	// +optional
+	CustomResources []ConfigCustomResource `json:"customResources,omitempty"`
+}
+
+// ConfigCustomResource defines the template of a custom resource of the service.
+type ConfigCustomResource struct {
+	// Kind is the kind of the custom resource, like etcdCluster.
+	// It is matched case-insensitively with the kinds in the alm-examples of the operator.
+	Kind string `json:"kind"`
+	// Spec is the template merged into the spec of the custom resource.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +nullable
+	// +optional
+	Spec *runtime.RawExtension `json:"spec,omitempty"`
+}
+
+// OperandConfig is the Schema for the operandconfigs API.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:unservedversion
+// +kubebuilder:resource:path=operandconfigs,shortName=opcon,scope=Namespaced
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=.metadata.creationTimestamp
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=.status.phase,description="Current Phase"
+// +kubebuilder:printcolumn:name="Score",type=integer,JSONPath=.status.scorecard.score,description="Best Practice Score"
+// +kubebuilder:printcolumn:name="Created At",type=string,JSONPath=.metadata.creationTimestamp
+// +operator-sdk:csv:customresourcedefinitions:displayName="OperandConfig"
+type OperandConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperandConfigSpec            `json:"spec,omitempty"`
+	Status v1alpha1.OperandConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperandConfigList contains a list of OperandConfig.
+type OperandConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperandConfig `json:"items"`
+}
+
+// GetCustomResource obtains the template of the custom resource with the kind, it is matched case-insensitively.
+func (s *ConfigService) GetCustomResource(kind string) *ConfigCustomResource {
+	for i := range s.CustomResources {
+		if strings.EqualFold(s.CustomResources[i].Kind, kind) {
+			return &s.CustomResources[i]
+		}
+	}
+	return nil
+}
+
+func init() {
+	SchemeBuilder.Register(&OperandConfig{}, &OperandConfigList{})
+}
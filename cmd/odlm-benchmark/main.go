@@ -0,0 +1,201 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Command odlm-benchmark runs the ODLM controllers against envtest or a cluster under a synthetic load,
+// and reports their throughput, the reconcile latency and the API calls. When a baseline is set,
+// it fails on the regressions from the baseline.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	olmv1 "github.com/operator-framework/api/pkg/operators/v1"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	operatorsv1 "github.com/operator-framework/operator-lifecycle-manager/pkg/package-server/apis/operators/v1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	nssv1 "github.com/IBM/ibm-namespace-scope-operator/api/v1"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/operandconfig"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/operandregistry"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/operandrequest"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/benchmark"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/throughput"
+)
+
+var (
+	scheme = runtime.NewScheme()
+	log    = logging.Logger("setup")
+)
+
+func init() {
+	utilruntime.Must(olmv1.AddToScheme(scheme))
+	utilruntime.Must(olmv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(nssv1.AddToScheme(scheme))
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(operatorv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(operatorsv1.AddToScheme(scheme))
+}
+
+func main() {
+	var logOptions logging.Options
+	logOptions.BindFlags(flag.CommandLine)
+	var throughputOptions throughput.Options
+	throughputOptions.BindFlags(flag.CommandLine)
+	var benchmarkOptions benchmark.Options
+	benchmarkOptions.BindFlags(flag.CommandLine)
+	useEnvtest := flag.Bool("envtest", false, "Start an envtest API server instead of using the cluster of the kubeconfig.")
+	crdDirs := flag.String("crd-dirs", "config/crd/bases,testcrds", "The comma separated directories of the CRDs installed in envtest.")
+	stepSize := flag.Int("batch-chunk-size", 1, "The number of the Subscriptions created concurrently.")
+	output := flag.String("output", "", "The file the result is written to as JSON.")
+	baseline := flag.String("baseline", "", "The JSON result of a previous run, the run fails on the regressions from it.")
+	tolerance := flag.Float64("tolerance", 0.2, "The tolerated regression from the baseline, 0.2 for 20%.")
+	flag.Parse()
+
+	if err := logging.Setup(logOptions, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to set up the logger: %v\n", err)
+		os.Exit(1)
+	}
+	throughput.Setup(throughputOptions)
+
+	if err := run(benchmarkOptions, *useEnvtest, strings.Split(*crdDirs, ","), *stepSize, *output, *baseline, *tolerance); err != nil {
+		log.Error(err, "benchmark failed")
+		os.Exit(1)
+	}
+}
+
+func run(opts benchmark.Options, useEnvtest bool, crdDirs []string, stepSize int, output, baseline string, tolerance float64) error {
+	var cfg *rest.Config
+	if useEnvtest {
+		testEnv := &envtest.Environment{CRDDirectoryPaths: crdDirs}
+		var err error
+		if cfg, err = testEnv.Start(); err != nil {
+			return errors.Wrap(err, "failed to start envtest")
+		}
+		defer func() {
+			if err := testEnv.Stop(); err != nil {
+				log.Error(err, "unable to stop envtest")
+			}
+		}()
+	} else {
+		var err error
+		if cfg, err = ctrl.GetConfig(); err != nil {
+			return err
+		}
+	}
+	if os.Getenv("OPERATOR_NAMESPACE") == "" {
+		os.Setenv("OPERATOR_NAMESPACE", opts.Namespace)
+	}
+
+	// The controllers run with the throughput of the flags, their API calls are counted
+	counter := benchmark.NewAPICounter()
+	controllerConfig := rest.CopyConfig(cfg)
+	throughput.ConfigureClient(controllerConfig)
+	controllerConfig.Wrap(counter.Wrap)
+
+	ctx, cancel := context.WithCancel(ctrl.SetupSignalHandler())
+	defer cancel()
+	if err := startControllers(ctx, controllerConfig, stepSize); err != nil {
+		return err
+	}
+
+	result, err := benchmark.Run(ctx, cfg, scheme, opts, counter)
+	if err != nil {
+		return err
+	}
+	result.Print(os.Stdout)
+
+	if output != "" {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(output, data, 0644); err != nil {
+			return errors.Wrapf(err, "failed to write the result to %s", output)
+		}
+	}
+
+	if baseline == "" {
+		if result.Reconciled < result.Requests {
+			return errors.Errorf("only %d of the %d OperandRequests were reconciled", result.Reconciled, result.Requests)
+		}
+		return nil
+	}
+	data, err := ioutil.ReadFile(baseline)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the baseline %s", baseline)
+	}
+	base := &benchmark.Result{}
+	if err := json.Unmarshal(data, base); err != nil {
+		return errors.Wrapf(err, "failed to parse the baseline %s", baseline)
+	}
+	if regressions := result.Compare(base, tolerance); len(regressions) != 0 {
+		return errors.Errorf("regressions from the baseline: %s", strings.Join(regressions, "; "))
+	}
+	return nil
+}
+
+// startControllers starts the controllers reconciling the synthetic resources in a manager
+func startControllers(ctx context.Context, cfg *rest.Config, stepSize int) error {
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:                 scheme,
+		MetricsBindAddress:     "0",
+		HealthProbeBindAddress: "0",
+	})
+	if err != nil {
+		return errors.Wrap(err, "unable to create the manager")
+	}
+	if err := (&operandrequest.Reconciler{
+		ODLMOperator: deploy.NewODLMOperator(mgr, "OperandRequest"),
+		StepSize:     stepSize,
+	}).SetupWithManager(mgr); err != nil {
+		return errors.Wrap(err, "unable to create controller OperandRequest")
+	}
+	if err := (&operandconfig.Reconciler{
+		ODLMOperator: deploy.NewODLMOperator(mgr, "OperandConfig"),
+	}).SetupWithManager(mgr); err != nil {
+		return errors.Wrap(err, "unable to create controller OperandConfig")
+	}
+	if err := (&operandregistry.Reconciler{
+		ODLMOperator: deploy.NewODLMOperator(mgr, "OperandRegistry"),
+	}).SetupWithManager(mgr); err != nil {
+		return errors.Wrap(err, "unable to create controller OperandRegistry")
+	}
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			log.Error(err, "problem running manager")
+		}
+	}()
+	if !mgr.GetCache().WaitForCacheSync(ctx) {
+		return errors.New("failed to sync the cache of the controllers")
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Command odlm inspects the OperandRequests and the resources ODLM created for them. Built as kubectl-odlm
+// in the PATH, it runs as a kubectl plugin:
+//
+//	kubectl odlm status <request> [-n namespace]
+//	kubectl odlm tree <request> [-n namespace]
+//	kubectl odlm render <request> [-n namespace]
+//	kubectl odlm render -f <file> [-f <file>...] [<request>] [-n namespace]
+//	kubectl odlm prune --dry-run <request> [-n namespace]
+//
+// It only reads the cluster, nothing is changed. With the -f files, render reads no cluster at all: it prints
+// the Subscriptions and the custom resources ODLM creates for the OperandRequests of the files.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	olmv1 "github.com/operator-framework/api/pkg/operators/v1"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/inspect"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/render"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(olmv1.AddToScheme(scheme))
+	utilruntime.Must(olmv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(operatorv1alpha1.AddToScheme(scheme))
+}
+
+const usage = `Usage: kubectl odlm [flags] <command> <request> [-n namespace]
+
+Commands:
+  status   Show the phase, the members, the conditions, the drifted fields and the history of the OperandRequest
+  tree     Show the OperandRegistries, the operators, the Subscriptions, the ClusterServiceVersions,
+           the custom resources and the binding copies of the OperandRequest
+  render   Print the custom resources rendered for the operands of the OperandRequest. With -f, print offline
+           the Subscriptions and the custom resources of the OperandRequests of the files
+  prune    Show the operands removed from the OperandRequest on its next reconcile, with --dry-run
+
+Flags:
+`
+
+func main() {
+	var logOptions logging.Options
+	logOptions.BindFlags(flag.CommandLine)
+	utilruntime.Must(flag.Set("log-format", logging.FormatText))
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := logging.Setup(logOptions, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to set up the logger: %v\n", err)
+		os.Exit(1)
+	}
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(context.Background(), flag.Arg(0), flag.Args()[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, command string, args []string) error {
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	namespace := fs.String("namespace", "default", "The namespace of the OperandRequest.")
+	fs.StringVar(namespace, "n", "default", "The namespace of the OperandRequest (shorthand).")
+	dryRun := fs.Bool("dry-run", false, "Only show what would be pruned, it is required by the prune command.")
+	var files fileList
+	fs.Var(&files, "f", "The YAML or JSON file of the OperandRegistries, the OperandConfigs, the OperandRequests and the ClusterServiceVersions rendered offline, repeated for each file.")
+	names, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if command == "render" && len(files) != 0 {
+		return renderFiles(files, *namespace, names)
+	}
+	if len(names) != 1 {
+		return errors.Errorf("the %s command needs the name of one OperandRequest", command)
+	}
+	key := types.NamespacedName{Namespace: *namespace, Name: names[0]}
+
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return err
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return errors.Wrap(err, "failed to create the client")
+	}
+	inspector := inspect.New(c, scheme, os.Stdout)
+
+	switch command {
+	case "status":
+		return inspector.Status(ctx, key)
+	case "tree":
+		return inspector.Tree(ctx, key)
+	case "render":
+		return inspector.Render(ctx, key)
+	case "prune":
+		// ODLM prunes the operands when it reconciles the OperandRequest, the plugin only shows them
+		if !*dryRun {
+			return errors.New("the prune command only supports --dry-run, ODLM prunes the operands when it reconciles the OperandRequest")
+		}
+		return inspector.Prune(ctx, key)
+	default:
+		return errors.Errorf("unknown command %q, see kubectl odlm -h", command)
+	}
+}
+
+// renderFiles renders the OperandRequests of the files without a cluster, only the named ones when names are given
+func renderFiles(files []string, namespace string, names []string) error {
+	if len(names) > 1 {
+		return errors.New("the render command renders one OperandRequest, or all the OperandRequests of the files")
+	}
+	data := make([][]byte, 0, len(files))
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read the file %s", file)
+		}
+		data = append(data, content)
+	}
+	in, err := render.Load(namespace, data...)
+	if err != nil {
+		return err
+	}
+	if len(names) == 1 {
+		var requests []operatorv1alpha1.OperandRequest
+		for _, req := range in.Requests {
+			if req.Name == names[0] && req.Namespace == namespace {
+				requests = append(requests, req)
+			}
+		}
+		if len(requests) == 0 {
+			return errors.Errorf("the OperandRequest %s/%s is not found in the files", namespace, names[0])
+		}
+		in.Requests = requests
+	}
+	out, err := render.Render(in)
+	if err != nil {
+		return err
+	}
+	return render.Write(os.Stdout, out)
+}
+
+// fileList is the value of a flag repeated for each file
+type fileList []string
+
+func (f *fileList) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *fileList) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// parseArgs parses the flags of the command before and after its arguments, and returns the arguments
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var names []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return names, nil
+		}
+		names = append(names, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
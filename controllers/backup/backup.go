@@ -0,0 +1,108 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package backup renders the labels and the Velero hook annotations of the services backed up by Velero and OADP.
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+const (
+	// PreHookPrefix is the prefix of the Velero annotations of the hook run before the pod is backed up
+	PreHookPrefix = "pre.hook.backup.velero.io/"
+	// PostHookPrefix is the prefix of the Velero annotations of the hook run after the pod is backed up
+	PostHookPrefix = "post.hook.backup.velero.io/"
+)
+
+// Validate returns an error if the labels of the backup policy aren't valid k8s labels
+func Validate(policy *operatorv1alpha1.BackupPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	var invalid []string
+	for k, v := range policy.Labels {
+		for _, msg := range validation.IsQualifiedName(k) {
+			invalid = append(invalid, fmt.Sprintf("key %q: %s", k, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(v) {
+			invalid = append(invalid, fmt.Sprintf("value %q of %q: %s", v, k, msg))
+		}
+	}
+	if len(invalid) != 0 {
+		return errors.Errorf("invalid backup labels: %s", strings.Join(invalid, "; "))
+	}
+	return nil
+}
+
+// Labels returns the labels added to the custom resources of the service and to their namespace
+func Labels(policy *operatorv1alpha1.BackupPolicy) map[string]string {
+	labels := make(map[string]string)
+	if policy == nil {
+		return labels
+	}
+	for k, v := range policy.Labels {
+		labels[k] = v
+	}
+	return labels
+}
+
+// Annotations returns the Velero hook annotations added to the custom resources of the service
+func Annotations(policy *operatorv1alpha1.BackupPolicy) map[string]string {
+	annotations := make(map[string]string)
+	if policy == nil {
+		return annotations
+	}
+	addHook(annotations, PreHookPrefix, policy.PreHook)
+	addHook(annotations, PostHookPrefix, policy.PostHook)
+	return annotations
+}
+
+// addHook adds the annotations of a hook, the command is a JSON array like Velero expects
+func addHook(annotations map[string]string, prefix string, hook *operatorv1alpha1.BackupHook) {
+	if hook == nil || len(hook.Command) == 0 {
+		return
+	}
+	command, _ := json.Marshal(hook.Command)
+	annotations[prefix+"command"] = string(command)
+	if hook.Container != "" {
+		annotations[prefix+"container"] = hook.Container
+	}
+	if hook.OnError != "" {
+		annotations[prefix+"on-error"] = hook.OnError
+	}
+	if hook.Timeout != nil {
+		annotations[prefix+"timeout"] = hook.Timeout.Duration.String()
+	}
+}
+
+// MissingLabels returns the labels which aren't set with the same value in the existing labels
+func MissingLabels(existing, labels map[string]string) map[string]string {
+	missing := make(map[string]string)
+	for k, v := range labels {
+		if current, ok := existing[k]; !ok || current != v {
+			missing[k] = v
+		}
+	}
+	return missing
+}
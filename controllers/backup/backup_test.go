@@ -0,0 +1,74 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package backup
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Backup", func() {
+
+	policy := &operatorv1alpha1.BackupPolicy{
+		Labels: map[string]string{"velero.io/backup-set": "common-services"},
+		PreHook: &operatorv1alpha1.BackupHook{
+			Container: "mongodb",
+			Command:   []string{"/bin/sh", "-c", "mongo --eval 'db.fsyncLock()'"},
+			OnError:   "Fail",
+			Timeout:   &metav1.Duration{Duration: 2 * time.Minute},
+		},
+		PostHook: &operatorv1alpha1.BackupHook{
+			Command: []string{"/bin/sh", "-c", "mongo --eval 'db.fsyncUnlock()'"},
+		},
+	}
+
+	It("Should render the labels and the Velero hook annotations", func() {
+		Expect(Labels(policy)).To(Equal(map[string]string{"velero.io/backup-set": "common-services"}))
+		Expect(Annotations(policy)).To(Equal(map[string]string{
+			"pre.hook.backup.velero.io/command":   `["/bin/sh","-c","mongo --eval 'db.fsyncLock()'"]`,
+			"pre.hook.backup.velero.io/container": "mongodb",
+			"pre.hook.backup.velero.io/on-error":  "Fail",
+			"pre.hook.backup.velero.io/timeout":   "2m0s",
+			"post.hook.backup.velero.io/command":  `["/bin/sh","-c","mongo --eval 'db.fsyncUnlock()'"]`,
+		}))
+	})
+
+	It("Should render nothing without a backup policy", func() {
+		Expect(Labels(nil)).To(BeEmpty())
+		Expect(Annotations(nil)).To(BeEmpty())
+		Expect(Validate(nil)).To(Succeed())
+	})
+
+	It("Should reject the invalid labels", func() {
+		Expect(Validate(policy)).To(Succeed())
+		err := Validate(&operatorv1alpha1.BackupPolicy{Labels: map[string]string{"velero.io/backup set": "common services"}})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`key "velero.io/backup set"`))
+		Expect(err.Error()).To(ContainSubstring(`value "common services"`))
+	})
+
+	It("Should return the labels missing in the namespace", func() {
+		existing := map[string]string{"velero.io/backup-set": "other", "team": "a"}
+		Expect(MissingLabels(existing, map[string]string{"velero.io/backup-set": "common-services", "team": "a"})).To(Equal(map[string]string{"velero.io/backup-set": "common-services"}))
+		Expect(MissingLabels(existing, map[string]string{"team": "a"})).To(BeEmpty())
+	})
+})
@@ -0,0 +1,185 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package bootstrap orders the first install of the operators of an OperandRegistry with its OperandBootstrap.
+package bootstrap
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+// Get returns the OperandBootstrap of the OperandRegistry, nil when there is none or its CRD isn't installed
+func Get(ctx context.Context, reader client.Reader, registryKey types.NamespacedName) (*operatorv1alpha1.OperandBootstrap, error) {
+	b := &operatorv1alpha1.OperandBootstrap{}
+	if err := reader.Get(ctx, registryKey, b); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the OperandBootstrap %s", registryKey.String())
+	}
+	return b, nil
+}
+
+// Validate returns an error if the OperandBootstrap refers to an operator missing from the OperandRegistry,
+// or if its steps depend on each other in a cycle
+func Validate(b *operatorv1alpha1.OperandBootstrap, registry *operatorv1alpha1.OperandRegistry) error {
+	known := make(map[string]bool)
+	for _, o := range registry.Spec.Operators {
+		known[o.Name] = true
+	}
+	seen := make(map[string]bool)
+	for _, step := range b.Spec.Steps {
+		if seen[step.Operator] {
+			return errors.Errorf("the operator %s has several steps", step.Operator)
+		}
+		seen[step.Operator] = true
+		if !known[step.Operator] {
+			return errors.Errorf("the operator %s of the steps is not in the OperandRegistry %s", step.Operator, registry.Name)
+		}
+		for _, dep := range step.After {
+			if dep.Operator == step.Operator {
+				return errors.Errorf("the operator %s can't be installed after itself", step.Operator)
+			}
+			if !known[dep.Operator] {
+				return errors.Errorf("the operator %s installed before %s is not in the OperandRegistry %s", dep.Operator, step.Operator, registry.Name)
+			}
+		}
+	}
+	_, err := Waves(b)
+	return err
+}
+
+// Waves groups the operators of the OperandBootstrap by install order,
+// the operators of a wave only depend on the operators of the previous waves.
+// It returns an error naming the operators of a cycle.
+func Waves(b *operatorv1alpha1.OperandBootstrap) ([][]string, error) {
+	pending := make(map[string]int)
+	dependents := make(map[string][]string)
+	for _, step := range b.Spec.Steps {
+		if _, ok := pending[step.Operator]; !ok {
+			pending[step.Operator] = 0
+		}
+		for _, dep := range step.After {
+			if _, ok := pending[dep.Operator]; !ok {
+				pending[dep.Operator] = 0
+			}
+			pending[step.Operator]++
+			dependents[dep.Operator] = append(dependents[dep.Operator], step.Operator)
+		}
+	}
+
+	var waves [][]string
+	for len(pending) != 0 {
+		var wave []string
+		for name, count := range pending {
+			if count == 0 {
+				wave = append(wave, name)
+			}
+		}
+		if len(wave) == 0 {
+			var cycle []string
+			for name := range pending {
+				cycle = append(cycle, name)
+			}
+			sort.Strings(cycle)
+			return nil, errors.Errorf("the operators %s depend on each other in a cycle", strings.Join(cycle, ", "))
+		}
+		sort.Strings(wave)
+		for _, name := range wave {
+			delete(pending, name)
+			for _, dependent := range dependents[name] {
+				pending[dependent]--
+			}
+		}
+		waves = append(waves, wave)
+	}
+	return waves, nil
+}
+
+// Waiting returns the dependencies of the operator which aren't ready in the OperandRequests of the OperandRegistry.
+// The optional dependencies no OperandRequest asks for are skipped.
+func Waiting(b *operatorv1alpha1.OperandBootstrap, operator string, registryKey types.NamespacedName, requests []operatorv1alpha1.OperandRequest) []operatorv1alpha1.BootstrapDependency {
+	step := b.GetStep(operator)
+	if step == nil {
+		return nil
+	}
+	var waiting []operatorv1alpha1.BootstrapDependency
+	for _, dep := range step.After {
+		requested, ready := lookup(dep, registryKey, requests)
+		if ready || (!requested && dep.Optional) {
+			continue
+		}
+		waiting = append(waiting, dep)
+	}
+	return waiting
+}
+
+// lookup returns whether an OperandRequest asks for the operator of the dependency, and whether it is ready
+func lookup(dep operatorv1alpha1.BootstrapDependency, registryKey types.NamespacedName, requests []operatorv1alpha1.OperandRequest) (requested, ready bool) {
+	for _, request := range requests {
+		if request.DeletionTimestamp != nil {
+			continue
+		}
+		onRegistry := false
+		for _, req := range request.Spec.Requests {
+			if request.GetRegistryKey(req) != registryKey {
+				continue
+			}
+			onRegistry = true
+			for _, o := range req.Operands {
+				if o.Name == dep.Operator {
+					requested = true
+				}
+			}
+		}
+		if !onRegistry {
+			continue
+		}
+		// The status members include the operators required by the requested ones
+		for _, m := range request.Status.Members {
+			if m.Name != dep.Operator {
+				continue
+			}
+			requested = true
+			switch dep.GetCondition() {
+			case operatorv1alpha1.BootstrapOperatorReady:
+				ready = ready || m.Phase.OperatorPhase == operatorv1alpha1.OperatorRunning
+			default:
+				ready = ready || (m.Phase.OperatorPhase == operatorv1alpha1.OperatorRunning && m.Phase.OperandPhase == operatorv1alpha1.ServiceRunning)
+			}
+		}
+	}
+	return requested, ready
+}
+
+// Message explains what the operator is waiting for
+func Message(operator string, waiting []operatorv1alpha1.BootstrapDependency) string {
+	var deps []string
+	for _, dep := range waiting {
+		deps = append(deps, dep.Operator+" ("+string(dep.GetCondition())+")")
+	}
+	return "The operator " + operator + " is installed after " + strings.Join(deps, ", ")
+}
@@ -0,0 +1,137 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package bootstrap
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+func newRequest(name string, operands []string, members ...operatorv1alpha1.MemberStatus) operatorv1alpha1.OperandRequest {
+	request := operatorv1alpha1.OperandRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ibm-common-services"},
+	}
+	req := operatorv1alpha1.Request{Registry: "common-service", RegistryNamespace: "ibm-common-services"}
+	for _, o := range operands {
+		req.Operands = append(req.Operands, operatorv1alpha1.Operand{Name: o})
+	}
+	request.Spec.Requests = []operatorv1alpha1.Request{req}
+	request.Status.Members = members
+	return request
+}
+
+func newMember(name string, operatorPhase operatorv1alpha1.OperatorPhase, operandPhase operatorv1alpha1.ServicePhase) operatorv1alpha1.MemberStatus {
+	return operatorv1alpha1.MemberStatus{Name: name, Phase: operatorv1alpha1.MemberPhase{OperatorPhase: operatorPhase, OperandPhase: operandPhase}}
+}
+
+var _ = Describe("OperandBootstrap", func() {
+
+	registryKey := types.NamespacedName{Name: "common-service", Namespace: "ibm-common-services"}
+	registry := &operatorv1alpha1.OperandRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "common-service", Namespace: "ibm-common-services"},
+		Spec: operatorv1alpha1.OperandRegistrySpec{
+			Operators: []operatorv1alpha1.Operator{{Name: "ibm-cert-manager-operator"}, {Name: "ibm-licensing-operator"}, {Name: "ibm-metering-operator"}, {Name: "ibm-iam-operator"}},
+		},
+	}
+	b := &operatorv1alpha1.OperandBootstrap{
+		Spec: operatorv1alpha1.OperandBootstrapSpec{
+			Steps: []operatorv1alpha1.BootstrapStep{
+				{Operator: "ibm-metering-operator", After: []operatorv1alpha1.BootstrapDependency{
+					{Operator: "ibm-licensing-operator"},
+					{Operator: "ibm-cert-manager-operator", Condition: operatorv1alpha1.BootstrapOperatorReady},
+				}},
+				{Operator: "ibm-iam-operator", After: []operatorv1alpha1.BootstrapDependency{
+					{Operator: "ibm-cert-manager-operator", Condition: operatorv1alpha1.BootstrapOperatorReady},
+					{Operator: "ibm-metering-operator", Optional: true},
+				}},
+			},
+		},
+	}
+
+	It("Should group the operators by install order", func() {
+		Expect(Validate(b, registry)).Should(Succeed())
+		waves, err := Waves(b)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(waves).Should(Equal([][]string{
+			{"ibm-cert-manager-operator", "ibm-licensing-operator"},
+			{"ibm-metering-operator"},
+			{"ibm-iam-operator"},
+		}))
+	})
+
+	It("Should reject the invalid steps", func() {
+		cycle := b.DeepCopy()
+		cycle.Spec.Steps = append(cycle.Spec.Steps, operatorv1alpha1.BootstrapStep{
+			Operator: "ibm-cert-manager-operator", After: []operatorv1alpha1.BootstrapDependency{{Operator: "ibm-iam-operator"}},
+		})
+		Expect(Validate(cycle, registry)).Should(MatchError("the operators ibm-cert-manager-operator, ibm-iam-operator, ibm-metering-operator depend on each other in a cycle"))
+
+		unknown := b.DeepCopy()
+		unknown.Spec.Steps[0].After[0].Operator = "ibm-zen-operator"
+		Expect(Validate(unknown, registry)).Should(MatchError("the operator ibm-zen-operator installed before ibm-metering-operator is not in the OperandRegistry common-service"))
+
+		self := b.DeepCopy()
+		self.Spec.Steps[0].After[0].Operator = "ibm-metering-operator"
+		Expect(Validate(self, registry)).Should(MatchError("the operator ibm-metering-operator can't be installed after itself"))
+
+		duplicated := b.DeepCopy()
+		duplicated.Spec.Steps[1].Operator = "ibm-metering-operator"
+		Expect(Validate(duplicated, registry)).Should(MatchError("the operator ibm-metering-operator has several steps"))
+	})
+
+	It("Should wait for the dependencies which aren't ready", func() {
+		Expect(Waiting(b, "ibm-cert-manager-operator", registryKey, nil)).Should(BeEmpty())
+
+		requests := []operatorv1alpha1.OperandRequest{
+			newRequest("common-service", []string{"ibm-cert-manager-operator", "ibm-metering-operator", "ibm-iam-operator"},
+				newMember("ibm-cert-manager-operator", operatorv1alpha1.OperatorRunning, operatorv1alpha1.ServiceCreating),
+				// The licensing operator is required by the metering operator
+				newMember("ibm-licensing-operator", operatorv1alpha1.OperatorRunning, operatorv1alpha1.ServiceCreating)),
+		}
+		waiting := Waiting(b, "ibm-metering-operator", registryKey, requests)
+		Expect(waiting).Should(Equal([]operatorv1alpha1.BootstrapDependency{{Operator: "ibm-licensing-operator"}}))
+		Expect(Message("ibm-metering-operator", waiting)).Should(Equal("The operator ibm-metering-operator is installed after ibm-licensing-operator (OperandReady)"))
+		Expect(Waiting(b, "ibm-iam-operator", registryKey, requests)).Should(Equal([]operatorv1alpha1.BootstrapDependency{{Operator: "ibm-metering-operator", Optional: true}}))
+
+		requests = append(requests, newRequest("licensing", []string{"ibm-licensing-operator"},
+			newMember("ibm-licensing-operator", operatorv1alpha1.OperatorRunning, operatorv1alpha1.ServiceRunning)))
+		Expect(Waiting(b, "ibm-metering-operator", registryKey, requests)).Should(BeEmpty())
+	})
+
+	It("Should skip the optional dependencies nobody requests", func() {
+		requests := []operatorv1alpha1.OperandRequest{
+			newRequest("iam", []string{"ibm-iam-operator"}),
+		}
+		Expect(Waiting(b, "ibm-iam-operator", registryKey, requests)).Should(Equal([]operatorv1alpha1.BootstrapDependency{
+			{Operator: "ibm-cert-manager-operator", Condition: operatorv1alpha1.BootstrapOperatorReady},
+		}))
+
+		other := newRequest("other", []string{"ibm-cert-manager-operator", "ibm-metering-operator"},
+			newMember("ibm-cert-manager-operator", operatorv1alpha1.OperatorRunning, operatorv1alpha1.ServiceRunning))
+		other.Spec.Requests[0].Registry = "other-registry"
+		requests = append(requests, other)
+		Expect(Waiting(b, "ibm-iam-operator", registryKey, requests)).Should(HaveLen(1))
+
+		requests = append(requests, newRequest("cert-manager", []string{"ibm-cert-manager-operator"},
+			newMember("ibm-cert-manager-operator", operatorv1alpha1.OperatorRunning, operatorv1alpha1.ServiceCreating)))
+		Expect(Waiting(b, "ibm-iam-operator", registryKey, requests)).Should(BeEmpty())
+	})
+})
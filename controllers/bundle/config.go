@@ -0,0 +1,138 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package bundle
+
+import (
+	"reflect"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// InjectConfig injects the SubscriptionConfig, the priority class and the affinity into a rendered Deployment, like OLM does
+// for the Deployments of a Subscription. The environment variables, volumes and volume mounts replace the ones
+// with the same name, the envFrom sources and tolerations are added, and the resources, node selector and
+// priority class replace the ones of the Deployment. The affinity is only set when the Deployment has none.
+// The other objects are left unchanged.
+func InjectConfig(obj *unstructured.Unstructured, config *olmv1alpha1.SubscriptionConfig, priorityClassName string, affinity *corev1.Affinity) error {
+	if obj.GetKind() != "Deployment" || (config == nil && priorityClassName == "" && affinity == nil) {
+		return nil
+	}
+	deploy := &appsv1.Deployment{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, deploy); err != nil {
+		return errors.Wrapf(err, "failed to convert the Deployment %s", obj.GetName())
+	}
+	podSpec := &deploy.Spec.Template.Spec
+	if priorityClassName != "" {
+		podSpec.PriorityClassName = priorityClassName
+	}
+	if affinity != nil && podSpec.Affinity == nil {
+		podSpec.Affinity = affinity.DeepCopy()
+	}
+	if config != nil {
+		for i := range podSpec.Containers {
+			c := &podSpec.Containers[i]
+			for _, env := range config.Env {
+				c.Env = upsertEnv(c.Env, env)
+			}
+			for _, from := range config.EnvFrom {
+				if !containsEnvFrom(c.EnvFrom, from) {
+					c.EnvFrom = append(c.EnvFrom, from)
+				}
+			}
+			for _, mount := range config.VolumeMounts {
+				c.VolumeMounts = upsertVolumeMount(c.VolumeMounts, mount)
+			}
+			if config.Resources != nil {
+				c.Resources = *config.Resources.DeepCopy()
+			}
+		}
+		for _, volume := range config.Volumes {
+			podSpec.Volumes = upsertVolume(podSpec.Volumes, volume)
+		}
+		for _, toleration := range config.Tolerations {
+			if !containsToleration(podSpec.Tolerations, toleration) {
+				podSpec.Tolerations = append(podSpec.Tolerations, toleration)
+			}
+		}
+		if config.NodeSelector != nil {
+			podSpec.NodeSelector = config.NodeSelector
+		}
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(deploy)
+	if err != nil {
+		return errors.Wrapf(err, "failed to convert the Deployment %s", obj.GetName())
+	}
+	unstructured.RemoveNestedField(content, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(content, "spec", "template", "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(content, "status")
+	obj.Object = content
+	return nil
+}
+
+func upsertEnv(envs []corev1.EnvVar, env corev1.EnvVar) []corev1.EnvVar {
+	for i := range envs {
+		if envs[i].Name == env.Name {
+			envs[i] = env
+			return envs
+		}
+	}
+	return append(envs, env)
+}
+
+func upsertVolumeMount(mounts []corev1.VolumeMount, mount corev1.VolumeMount) []corev1.VolumeMount {
+	for i := range mounts {
+		if mounts[i].Name == mount.Name {
+			mounts[i] = mount
+			return mounts
+		}
+	}
+	return append(mounts, mount)
+}
+
+func upsertVolume(volumes []corev1.Volume, volume corev1.Volume) []corev1.Volume {
+	for i := range volumes {
+		if volumes[i].Name == volume.Name {
+			volumes[i] = volume
+			return volumes
+		}
+	}
+	return append(volumes, volume)
+}
+
+func containsEnvFrom(froms []corev1.EnvFromSource, from corev1.EnvFromSource) bool {
+	for _, f := range froms {
+		if reflect.DeepEqual(f, from) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsToleration(tolerations []corev1.Toleration, toleration corev1.Toleration) bool {
+	for _, t := range tolerations {
+		if t.MatchToleration(&toleration) {
+			return true
+		}
+	}
+	return false
+}
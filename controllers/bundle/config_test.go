@@ -0,0 +1,86 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package bundle
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("Inject the SubscriptionConfig", func() {
+
+	It("Should inject the config and the priority class into the Deployments", func() {
+		deploy := &appsv1.Deployment{}
+		deploy.SetName("etcd-operator")
+		deploy.Spec.Template.Spec.Containers = []corev1.Container{{
+			Name: "etcd-operator",
+			Env:  []corev1.EnvVar{{Name: "WATCH_NAMESPACE", Value: ""}, {Name: "HTTP_PROXY", Value: "http://old:3128"}},
+		}}
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(deploy)
+		Expect(err).ShouldNot(HaveOccurred())
+		obj := &unstructured.Unstructured{Object: content}
+		obj.SetAPIVersion("apps/v1")
+		obj.SetKind("Deployment")
+
+		config := &olmv1alpha1.SubscriptionConfig{
+			Env:          []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "http://proxy:3128"}},
+			NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+			Tolerations:  []corev1.Toleration{{Key: "infra", Operator: corev1.TolerationOpExists}},
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			},
+		}
+		affinity := &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "node-role.kubernetes.io/infra", Operator: corev1.NodeSelectorOpExists}},
+			}}},
+		}}
+		Expect(InjectConfig(obj, config, "system-cluster-critical", affinity)).Should(Succeed())
+
+		injected := &appsv1.Deployment{}
+		Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, injected)).Should(Succeed())
+		podSpec := injected.Spec.Template.Spec
+		Expect(podSpec.PriorityClassName).Should(Equal("system-cluster-critical"))
+		Expect(podSpec.Affinity).Should(Equal(affinity))
+		Expect(podSpec.NodeSelector).Should(HaveKey("node-role.kubernetes.io/infra"))
+		Expect(podSpec.Tolerations).Should(HaveLen(1))
+		Expect(podSpec.Containers[0].Env).Should(Equal([]corev1.EnvVar{{Name: "WATCH_NAMESPACE", Value: ""}, {Name: "HTTP_PROXY", Value: "http://proxy:3128"}}))
+		Expect(podSpec.Containers[0].Resources.Limits.Memory().String()).Should(Equal("512Mi"))
+		Expect(obj.GetKind()).Should(Equal("Deployment"))
+
+		// The tolerations already in the Deployment aren't added again
+		Expect(InjectConfig(obj, config, "", nil)).Should(Succeed())
+		Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, injected)).Should(Succeed())
+		Expect(injected.Spec.Template.Spec.Tolerations).Should(HaveLen(1))
+	})
+
+	It("Should leave the other objects unchanged", func() {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ServiceAccount")
+		obj.SetName("etcd-operator")
+		original := obj.DeepCopy()
+		Expect(InjectConfig(obj, &olmv1alpha1.SubscriptionConfig{NodeSelector: map[string]string{"a": "b"}}, "high", nil)).Should(Succeed())
+		Expect(obj).Should(Equal(original))
+	})
+})
@@ -0,0 +1,230 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+)
+
+// Manifests is the set of objects ODLM applies to install an operator without OLM.
+type Manifests struct {
+	// Objects are the objects to be applied, sorted in the install order
+	Objects []unstructured.Unstructured
+	// CSV is the ClusterServiceVersion of the bundle. It is not applied, its install strategy
+	// is rendered into Objects and its alm-examples are used to create the operands.
+	CSV *olmv1alpha1.ClusterServiceVersion
+}
+
+// installOrder is the order the kinds are applied in, the other kinds are applied at the end
+var installOrder = []string{
+	"Namespace",
+	"CustomResourceDefinition",
+	"ServiceAccount",
+	"Secret",
+	"ConfigMap",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"Role",
+	"RoleBinding",
+	"Service",
+	"Deployment",
+}
+
+// Parse decodes the multi-document YAML or JSON manifests into objects.
+// Empty documents are skipped and the items of a List are flattened.
+func Parse(data []byte) ([]unstructured.Unstructured, error) {
+	var objs []unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		raw := make(map[string]interface{})
+		if err := decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, errors.Wrap(err, "failed to decode the manifests")
+		}
+		if len(raw) == 0 {
+			continue
+		}
+		obj := unstructured.Unstructured{Object: raw}
+		if obj.IsList() {
+			list, err := obj.ToList()
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to decode the list %s", obj.GetName())
+			}
+			objs = append(objs, list.Items...)
+			continue
+		}
+		if obj.GetKind() == "" || obj.GetAPIVersion() == "" {
+			return nil, fmt.Errorf("the object %s in the manifests has no apiVersion or kind", obj.GetName())
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// Load parses the manifest files and renders the ClusterServiceVersion they contain, if any,
+// for the operator to be installed in the namespace and watch the target namespaces.
+// When there is no ClusterServiceVersion, an empty one named after the operator is returned.
+func Load(files map[string]string, name, namespace string, targetNamespaces []string) (*Manifests, error) {
+	fileNames := make([]string, 0, len(files))
+	for fileName := range files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	m := &Manifests{}
+	for _, fileName := range fileNames {
+		objs, err := Parse([]byte(files[fileName]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the manifest file %s", fileName)
+		}
+		for _, obj := range objs {
+			if obj.GetKind() != "ClusterServiceVersion" {
+				m.Objects = append(m.Objects, obj)
+				continue
+			}
+			if m.CSV != nil {
+				return nil, fmt.Errorf("found more than one ClusterServiceVersion in the manifests: %s and %s", m.CSV.Name, obj.GetName())
+			}
+			csv := &olmv1alpha1.ClusterServiceVersion{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, csv); err != nil {
+				return nil, errors.Wrapf(err, "failed to convert the ClusterServiceVersion %s", obj.GetName())
+			}
+			m.CSV = csv
+		}
+	}
+
+	if m.CSV != nil {
+		rendered, err := RenderCSV(m.CSV, namespace, targetNamespaces)
+		if err != nil {
+			return nil, err
+		}
+		m.Objects = append(m.Objects, rendered...)
+		m.CSV.Namespace = namespace
+	} else {
+		m.CSV = EmptyCSV(name, namespace)
+	}
+
+	SortByInstallOrder(m.Objects)
+	return m, nil
+}
+
+// EmptyCSV returns a ClusterServiceVersion without alm-examples, for the operators which have no ClusterServiceVersion
+func EmptyCSV(name, namespace string) *olmv1alpha1.ClusterServiceVersion {
+	return &olmv1alpha1.ClusterServiceVersion{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: map[string]string{"alm-examples": "[]"},
+		},
+	}
+}
+
+// SortByInstallOrder sorts the objects so that the objects they depend on are applied first
+func SortByInstallOrder(objs []unstructured.Unstructured) {
+	rank := func(kind string) int {
+		for i, k := range installOrder {
+			if k == kind {
+				return i
+			}
+		}
+		return len(installOrder)
+	}
+	sort.SliceStable(objs, func(i, j int) bool {
+		return rank(objs[i].GetKind()) < rank(objs[j].GetKind())
+	})
+}
+
+// Hash returns a short hash of the object content, it is used to detect the objects changed by an upgrade
+func Hash(obj unstructured.Unstructured) (string, error) {
+	raw, err := json.Marshal(obj.Object)
+	if err != nil {
+		return "", err
+	}
+	hashed := sha256.Sum256(raw)
+	return hex.EncodeToString(hashed[:7]), nil
+}
+
+// TooLargeError is returned when the manifests exceed the size limit of the ConfigMap caching them
+type TooLargeError struct {
+	Source string
+	Limit  int
+}
+
+// Error is the error message
+func (e *TooLargeError) Error() string {
+	return fmt.Sprintf("the manifests of %s exceed the size limit of %d bytes of the ConfigMap caching them", e.Source, e.Limit)
+}
+
+// CheckSize returns a TooLargeError when the manifest files of the source exceed the limit
+func CheckSize(files map[string]string, source string, limit int) error {
+	size := 0
+	for name, content := range files {
+		size += len(name) + len(content)
+	}
+	if size > limit {
+		return &TooLargeError{Source: source, Limit: limit}
+	}
+	return nil
+}
+
+// fetchClient is the HTTP client downloading the manifests, a stalled server doesn't hold the reconcile
+var fetchClient = &http.Client{Timeout: constant.DefaultManifestsFetchTimeout}
+
+// Fetch downloads the manifest file from the URL, it fails with a TooLargeError when the file exceeds the limit
+func Fetch(ctx context.Context, url string, limit int) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to build the request for %s", url)
+	}
+	resp, err := fetchClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch the manifests from %s", url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch the manifests from %s: %s", url, resp.Status)
+	}
+	// One more byte than the limit is read to tell a file of the limit size from a larger one
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, int64(limit)+1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the manifests from %s", url)
+	}
+	if len(data) > limit {
+		return nil, &TooLargeError{Source: url, Limit: limit}
+	}
+	return data, nil
+}
@@ -0,0 +1,202 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const testCSV = `apiVersion: operators.coreos.com/v1alpha1
+kind: ClusterServiceVersion
+metadata:
+  name: etcd-operator.v0.9.4
+  annotations:
+    alm-examples: '[{"apiVersion":"etcd.database.coreos.com/v1beta2","kind":"EtcdCluster","metadata":{"name":"example"},"spec":{"size":3}}]'
+spec:
+  install:
+    strategy: deployment
+    spec:
+      permissions:
+      - serviceAccountName: etcd-operator
+        rules:
+        - apiGroups: ["etcd.database.coreos.com"]
+          resources: ["etcdclusters"]
+          verbs: ["*"]
+      clusterPermissions:
+      - serviceAccountName: etcd-operator
+        rules:
+        - apiGroups: [""]
+          resources: ["nodes"]
+          verbs: ["get"]
+      deployments:
+      - name: etcd-operator
+        spec:
+          selector:
+            matchLabels:
+              name: etcd-operator
+          template:
+            metadata:
+              labels:
+                name: etcd-operator
+            spec:
+              serviceAccountName: etcd-operator
+              containers:
+              - name: etcd-operator
+                image: quay.io/coreos/etcd-operator:v0.9.4
+`
+
+const testCRD = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: etcdclusters.etcd.database.coreos.com
+spec:
+  group: etcd.database.coreos.com
+`
+
+var _ = Describe("Manifests", func() {
+
+	Context("Parse the manifests", func() {
+		It("Should parse the multi-document YAML and skip the empty documents", func() {
+			objs, err := Parse([]byte(testCRD + "---\n---\napiVersion: v1\nkind: ServiceAccount\nmetadata:\n  name: sa\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(objs).Should(HaveLen(2))
+			Expect(objs[0].GetKind()).Should(Equal("CustomResourceDefinition"))
+			Expect(objs[1].GetKind()).Should(Equal("ServiceAccount"))
+		})
+
+		It("Should flatten the items of a List", func() {
+			objs, err := Parse([]byte(`{"apiVersion":"v1","kind":"List","items":[{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"a"}},{"apiVersion":"v1","kind":"Secret","metadata":{"name":"b"}}]}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(objs).Should(HaveLen(2))
+			Expect(objs[1].GetName()).Should(Equal("b"))
+		})
+
+		It("Should fail when an object has no kind", func() {
+			_, err := Parse([]byte("apiVersion: v1\nmetadata:\n  name: a\n"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Load the manifests", func() {
+		It("Should render the ClusterServiceVersion in the install order", func() {
+			m, err := Load(map[string]string{"csv.yaml": testCSV, "crd.yaml": testCRD}, "etcd", "etcd-ns", []string{"ns-a", "ns-b"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.CSV.Name).Should(Equal("etcd-operator.v0.9.4"))
+			Expect(m.CSV.Namespace).Should(Equal("etcd-ns"))
+
+			var kinds []string
+			for _, obj := range m.Objects {
+				kinds = append(kinds, obj.GetKind())
+			}
+			Expect(kinds).Should(Equal([]string{"CustomResourceDefinition", "ServiceAccount", "ClusterRole", "ClusterRoleBinding", "Role", "RoleBinding", "Deployment"}))
+
+			deploy := m.Objects[len(m.Objects)-1]
+			Expect(deploy.GetNamespace()).Should(Equal("etcd-ns"))
+			annotations, _, _ := unstructured.NestedStringMap(deploy.Object, "spec", "template", "metadata", "annotations")
+			Expect(annotations).Should(HaveKeyWithValue(TargetNamespacesAnnotation, "ns-a,ns-b"))
+			_, found, _ := unstructured.NestedFieldNoCopy(deploy.Object, "metadata", "creationTimestamp")
+			Expect(found).Should(BeFalse())
+
+			Expect(m.Objects[2].GetName()).Should(Equal("etcd-ns-etcd-operator.v0.9.4-etcd-operator"))
+		})
+
+		It("Should return an empty ClusterServiceVersion for the plain manifests", func() {
+			m, err := Load(map[string]string{"manifests.yaml": testCRD}, "etcd", "etcd-ns", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.Objects).Should(HaveLen(1))
+			Expect(m.CSV.Name).Should(Equal("etcd"))
+			Expect(m.CSV.GetAnnotations()["alm-examples"]).Should(Equal("[]"))
+		})
+
+		It("Should fail when there is more than one ClusterServiceVersion", func() {
+			_, err := Load(map[string]string{"a.yaml": testCSV, "b.yaml": testCSV}, "etcd", "etcd-ns", nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Hash the objects", func() {
+		It("Should change the hash when the object changes", func() {
+			objs, err := Parse([]byte(testCRD))
+			Expect(err).NotTo(HaveOccurred())
+			before, err := Hash(objs[0])
+			Expect(err).NotTo(HaveOccurred())
+			objs[0].SetLabels(map[string]string{"key": "value"})
+			after, err := Hash(objs[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(after).ShouldNot(Equal(before))
+		})
+	})
+
+	Context("Fetch the manifests", func() {
+		It("Should fetch the manifests up to the size limit", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(testCRD))
+			}))
+			defer server.Close()
+
+			data, err := Fetch(context.TODO(), server.URL, len(testCRD))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).Should(Equal(testCRD))
+
+			_, err = Fetch(context.TODO(), server.URL, len(testCRD)-1)
+			Expect(err).Should(BeAssignableToTypeOf(&TooLargeError{}))
+		})
+
+		It("Should fail when the manifest files exceed the size limit", func() {
+			files := map[string]string{"csv.yaml": testCSV, "crd.yaml": strings.Repeat("#", 1024)}
+			Expect(CheckSize(files, "quay.io/example/bundle:v1", 4096)).Should(Succeed())
+			Expect(CheckSize(files, "quay.io/example/bundle:v1", 1024)).Should(BeAssignableToTypeOf(&TooLargeError{}))
+		})
+	})
+
+	Context("Unpack the bundle", func() {
+		It("Should unpack the manifest files and decode them from the log", func() {
+			dir, err := ioutil.TempDir("", "bundle")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+			Expect(ioutil.WriteFile(filepath.Join(dir, "csv.yaml"), []byte(testCSV), 0600)).Should(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("readme"), 0600)).Should(Succeed())
+
+			out := &bytes.Buffer{}
+			Expect(Unpack(dir, out)).Should(Succeed())
+
+			log := append([]byte("I1016 10:00:00.000000       1 main.go:1] starting\n"), out.Bytes()...)
+			files, err := DecodeUnpacked(log)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).Should(HaveLen(1))
+			Expect(files).Should(HaveKeyWithValue("csv.yaml", testCSV))
+		})
+
+		It("Should fail when there is no manifest file", func() {
+			dir, err := ioutil.TempDir("", "bundle")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+			Expect(Unpack(dir, &bytes.Buffer{})).ShouldNot(Succeed())
+		})
+	})
+})
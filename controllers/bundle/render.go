@@ -0,0 +1,129 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package bundle
+
+import (
+	"fmt"
+	"strings"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// TargetNamespacesAnnotation is the pod annotation OLM injects for the operators to discover the namespaces they watch
+const TargetNamespacesAnnotation = "olm.targetNamespaces"
+
+// RenderCSV renders the deployment install strategy of a ClusterServiceVersion into
+// the ServiceAccounts, RBAC and Deployments OLM would create for it.
+func RenderCSV(csv *olmv1alpha1.ClusterServiceVersion, namespace string, targetNamespaces []string) ([]unstructured.Unstructured, error) {
+	strategy := csv.Spec.InstallStrategy
+	if strategy.StrategyName != "" && strategy.StrategyName != olmv1alpha1.InstallStrategyNameDeployment {
+		return nil, fmt.Errorf("unsupported install strategy %s in the ClusterServiceVersion %s", strategy.StrategyName, csv.Name)
+	}
+	if len(targetNamespaces) == 0 {
+		targetNamespaces = []string{namespace}
+	}
+
+	var objs []runtime.Object
+	serviceAccounts := make(map[string]bool)
+	addServiceAccount := func(name string) {
+		if serviceAccounts[name] {
+			return
+		}
+		serviceAccounts[name] = true
+		objs = append(objs, &corev1.ServiceAccount{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		})
+	}
+
+	for _, perm := range strategy.StrategySpec.Permissions {
+		addServiceAccount(perm.ServiceAccountName)
+		name := csv.Name + "-" + perm.ServiceAccountName
+		objs = append(objs,
+			&rbacv1.Role{
+				TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "Role"},
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Rules:      perm.Rules,
+			},
+			&rbacv1.RoleBinding{
+				TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "RoleBinding"},
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: name},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: perm.ServiceAccountName, Namespace: namespace}},
+			})
+	}
+
+	for _, perm := range strategy.StrategySpec.ClusterPermissions {
+		addServiceAccount(perm.ServiceAccountName)
+		// ClusterRoles are shared by all the namespaces, include the namespace in the name
+		name := namespace + "-" + csv.Name + "-" + perm.ServiceAccountName
+		objs = append(objs,
+			&rbacv1.ClusterRole{
+				TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Rules:      perm.Rules,
+			},
+			&rbacv1.ClusterRoleBinding{
+				TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRoleBinding"},
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: name},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: perm.ServiceAccountName, Namespace: namespace}},
+			})
+	}
+
+	for _, spec := range strategy.StrategySpec.DeploymentSpecs {
+		deploy := &appsv1.Deployment{
+			TypeMeta: metav1.TypeMeta{APIVersion: appsv1.SchemeGroupVersion.String(), Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      spec.Name,
+				Namespace: namespace,
+				Labels:    spec.Label,
+			},
+			Spec: *spec.Spec.DeepCopy(),
+		}
+		if deploy.Spec.Template.Annotations == nil {
+			deploy.Spec.Template.Annotations = make(map[string]string)
+		}
+		deploy.Spec.Template.Annotations[TargetNamespacesAnnotation] = strings.Join(targetNamespaces, ",")
+		if deploy.Spec.Template.Spec.ServiceAccountName != "" {
+			addServiceAccount(deploy.Spec.Template.Spec.ServiceAccountName)
+		}
+		objs = append(objs, deploy)
+	}
+
+	rendered := make([]unstructured.Unstructured, 0, len(objs))
+	for _, obj := range objs {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render the ClusterServiceVersion %s", csv.Name)
+		}
+		u := unstructured.Unstructured{Object: content}
+		// Drop the empty creationTimestamp and status set by the typed objects
+		unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+		unstructured.RemoveNestedField(u.Object, "spec", "template", "metadata", "creationTimestamp")
+		unstructured.RemoveNestedField(u.Object, "status")
+		rendered = append(rendered, u)
+	}
+	return rendered, nil
+}
@@ -0,0 +1,148 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package bundle
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+)
+
+const (
+	// ManifestsDir is the directory of the manifests in an operator bundle image
+	ManifestsDir = "/manifests"
+
+	utilVolume = "util"
+	utilDir    = "/util"
+)
+
+// UnpackJob builds the Job unpacking the manifests of a bundle image.
+// Bundle images have no shell, so the ODLM binary is copied from the util image
+// into a shared volume and run in the bundle container to print the manifests into the pod log.
+func UnpackJob(name, namespace, bundleImage, utilImage string) *batchv1.Job {
+	backoffLimit := int32(3)
+	activeDeadlineSeconds := int64(600)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				constant.OpreqLabel: "true",
+			},
+			Annotations: map[string]string{
+				constant.ManifestsSourceAnnotation: bundleImage,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          &backoffLimit,
+			ActiveDeadlineSeconds: &activeDeadlineSeconds,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Volumes: []corev1.Volume{
+						{
+							Name:         utilVolume,
+							VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+						},
+					},
+					InitContainers: []corev1.Container{
+						{
+							Name:         "util",
+							Image:        utilImage,
+							Command:      []string{"cp", "/manager", utilDir + "/manager"},
+							VolumeMounts: []corev1.VolumeMount{{Name: utilVolume, MountPath: utilDir}},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:         "bundle",
+							Image:        bundleImage,
+							Command:      []string{utilDir + "/manager", "--unpack-bundle-dir=" + ManifestsDir},
+							VolumeMounts: []corev1.VolumeMount{{Name: utilVolume, MountPath: utilDir}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Unpack reads the manifest files in the directory and writes them to w
+// as a single line of JSON, mapping the file names to their content.
+func Unpack(dir string, w io.Writer) error {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the manifests directory %s", dir)
+	}
+	files := make(map[string]string)
+	for _, entry := range entries {
+		if !entry.Mode().IsRegular() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		content, err := ioutil.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return errors.Wrapf(err, "failed to read the manifest file %s", entry.Name())
+		}
+		files[entry.Name()] = string(content)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no manifest file found in the directory %s", dir)
+	}
+	raw, err := json.Marshal(files)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(raw))
+	return err
+}
+
+// DecodeUnpacked finds the manifest files written by Unpack in the pod log
+func DecodeUnpacked(log []byte) (map[string]string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(log))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(log)+1)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if !bytes.HasPrefix(line, []byte("{")) {
+			continue
+		}
+		files := make(map[string]string)
+		if err := json.Unmarshal(line, &files); err == nil && len(files) != 0 {
+			return files, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read the unpack log")
+	}
+	return nil, fmt.Errorf("no manifests found in the unpack log")
+}
@@ -0,0 +1,200 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package callback posts the lifecycle events of the operands to the HTTP endpoints
+// of the OperatorConfig, so the external systems like a CMDB or an ITSM stay in sync.
+package callback
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+const (
+	// DefaultTimeout is the timeout of a call when the callback has none
+	DefaultTimeout = 10 * time.Second
+
+	// SignatureHeader holds the HMAC-SHA256 of the body, as sha256=<hex>
+	SignatureHeader = "X-ODLM-Signature"
+	// EventHeader holds the event of the call
+	EventHeader = "X-ODLM-Event"
+)
+
+// configured are the callbacks of the running OperatorConfig
+var configured struct {
+	sync.RWMutex
+	callbacks []operatorv1alpha1.Callback
+}
+
+// Set sets the callbacks at runtime
+func Set(callbacks []operatorv1alpha1.Callback) {
+	configured.Lock()
+	defer configured.Unlock()
+	configured.callbacks = callbacks
+}
+
+// Get returns the callbacks ODLM is running with
+func Get() []operatorv1alpha1.Callback {
+	configured.RLock()
+	defer configured.RUnlock()
+	return configured.callbacks
+}
+
+// Payload is the JSON body posted to the callbacks
+type Payload struct {
+	Event          operatorv1alpha1.CallbackEvent `json:"event"`
+	Operand        string                         `json:"operand"`
+	OperandRequest Reference                      `json:"operandRequest"`
+	Registry       Reference                      `json:"registry"`
+	PackageName    string                         `json:"packageName,omitempty"`
+	Channel        string                         `json:"channel,omitempty"`
+	Namespace      string                         `json:"namespace,omitempty"`
+	// Timestamp lets the receivers reject the replayed calls
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Reference is the namespace and the name of an ODLM resource
+type Reference struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// Failure is a failed call of a callback
+type Failure struct {
+	Name string
+	Err  error
+	// Ignored is true if the FailurePolicy of the callback is Ignore
+	Ignored bool
+}
+
+// Notifier posts the events to the callbacks
+type Notifier struct {
+	// Reader reads the Secrets of the HMAC keys, they aren't in the cache of ODLM
+	Reader client.Reader
+	// Namespace is the namespace of the Secrets
+	Namespace string
+	// HTTPClient posts the events, the default client is used when it is nil
+	HTTPClient *http.Client
+}
+
+// Notify posts the payload to the callbacks of its event and returns the failed calls.
+// The callbacks are called in order, a failed call doesn't stop the next ones.
+func (n *Notifier) Notify(ctx context.Context, callbacks []operatorv1alpha1.Callback, payload Payload) []Failure {
+	var failures []Failure
+	for _, c := range callbacks {
+		if !Subscribed(c, payload.Event) {
+			continue
+		}
+		if err := n.call(ctx, c, payload); err != nil {
+			failures = append(failures, Failure{Name: c.Name, Err: err, Ignored: c.FailurePolicy != operatorv1alpha1.CallbackFailurePolicyFail})
+		}
+	}
+	return failures
+}
+
+// call posts the payload to the callback and fails if it doesn't answer with a 2xx status in time
+func (n *Notifier) call(ctx context.Context, c operatorv1alpha1.Callback, payload Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	timeout := DefaultTimeout
+	if c.Timeout != nil && c.Timeout.Duration > 0 {
+		timeout = c.Timeout.Duration
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the request of callback %s", c.Name)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(payload.Event))
+	if c.SecretKeyRef != nil {
+		key, err := n.getKey(ctx, c.SecretKeyRef)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the HMAC key of callback %s", c.Name)
+		}
+		req.Header.Set(SignatureHeader, Sign(key, body))
+	}
+
+	httpClient := n.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to call callback %s", c.Name)
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection is reused
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("callback %s answered with status %s", c.Name, resp.Status)
+	}
+	return nil
+}
+
+// getKey reads the HMAC key from the Secret
+func (n *Notifier) getKey(ctx context.Context, ref *corev1.SecretKeySelector) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := n.Reader.Get(ctx, types.NamespacedName{Namespace: n.Namespace, Name: ref.Name}, secret); err != nil {
+		return nil, err
+	}
+	key, ok := secret.Data[ref.Key]
+	if !ok || len(key) == 0 {
+		return nil, fmt.Errorf("the Secret %s/%s has no key %s", n.Namespace, ref.Name, ref.Key)
+	}
+	return key, nil
+}
+
+// Sign returns the signature of the body for the SignatureHeader
+func Sign(key, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Subscribed returns true if the callback is called for the event
+func Subscribed(c operatorv1alpha1.Callback, event operatorv1alpha1.CallbackEvent) bool {
+	if len(c.Events) == 0 {
+		return true
+	}
+	for _, e := range c.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
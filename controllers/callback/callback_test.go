@@ -0,0 +1,140 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Callback", func() {
+
+	var (
+		server   *httptest.Server
+		status   int
+		received []*http.Request
+		bodies   [][]byte
+		notifier *Notifier
+		payload  Payload
+	)
+
+	BeforeEach(func() {
+		status = http.StatusOK
+		received, bodies = nil, nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			body, _ := ioutil.ReadAll(req.Body)
+			received = append(received, req)
+			bodies = append(bodies, body)
+			w.WriteHeader(status)
+		}))
+
+		scheme := runtime.NewScheme()
+		utilruntime.Must(corev1.AddToScheme(scheme))
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "cmdb-hmac", Namespace: "ibm-operators"},
+			Data:       map[string][]byte{"key": []byte("s3cr3t")},
+		}
+		notifier = &Notifier{
+			Reader:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build(),
+			Namespace: "ibm-operators",
+		}
+		payload = Payload{
+			Event:          operatorv1alpha1.CallbackPreInstall,
+			Operand:        "ibm-etcd-operator",
+			OperandRequest: Reference{Namespace: "ibm-cloudpak", Name: "common-service"},
+			Registry:       Reference{Namespace: "ibm-common-services", Name: "common-service"},
+			Timestamp:      time.Now().UTC(),
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Should post the signed payload", func() {
+		callbacks := []operatorv1alpha1.Callback{{
+			Name:         "cmdb",
+			URL:          server.URL,
+			SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "cmdb-hmac"}, Key: "key"},
+		}}
+		Expect(notifier.Notify(context.TODO(), callbacks, payload)).Should(BeEmpty())
+		Expect(received).Should(HaveLen(1))
+		Expect(received[0].Header.Get(EventHeader)).Should(Equal("PreInstall"))
+		Expect(received[0].Header.Get(SignatureHeader)).Should(Equal(Sign([]byte("s3cr3t"), bodies[0])))
+
+		sent := Payload{}
+		Expect(json.Unmarshal(bodies[0], &sent)).Should(Succeed())
+		Expect(sent.Operand).Should(Equal("ibm-etcd-operator"))
+		Expect(sent.OperandRequest.Namespace).Should(Equal("ibm-cloudpak"))
+	})
+
+	It("Should only call the callbacks of the event", func() {
+		callbacks := []operatorv1alpha1.Callback{
+			{Name: "itsm", URL: server.URL, Events: []operatorv1alpha1.CallbackEvent{operatorv1alpha1.CallbackPreDelete}},
+			{Name: "cmdb", URL: server.URL, Events: []operatorv1alpha1.CallbackEvent{operatorv1alpha1.CallbackPreInstall, operatorv1alpha1.CallbackPostInstall}},
+		}
+		Expect(notifier.Notify(context.TODO(), callbacks, payload)).Should(BeEmpty())
+		Expect(received).Should(HaveLen(1))
+		Expect(received[0].Header.Get(SignatureHeader)).Should(BeEmpty())
+	})
+
+	It("Should report the failures with their policy", func() {
+		status = http.StatusServiceUnavailable
+		callbacks := []operatorv1alpha1.Callback{
+			{Name: "cmdb", URL: server.URL, FailurePolicy: operatorv1alpha1.CallbackFailurePolicyFail},
+			{Name: "itsm", URL: server.URL},
+		}
+		failures := notifier.Notify(context.TODO(), callbacks, payload)
+		Expect(failures).Should(HaveLen(2))
+		Expect(failures[0].Name).Should(Equal("cmdb"))
+		Expect(failures[0].Ignored).Should(BeFalse())
+		Expect(failures[1].Ignored).Should(BeTrue())
+	})
+
+	It("Should fail when the callback times out", func() {
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			time.Sleep(500 * time.Millisecond)
+		}))
+		defer slow.Close()
+		callbacks := []operatorv1alpha1.Callback{{Name: "slow", URL: slow.URL, Timeout: &metav1.Duration{Duration: 50 * time.Millisecond}}}
+		Expect(notifier.Notify(context.TODO(), callbacks, payload)).Should(HaveLen(1))
+	})
+
+	It("Should fail when the HMAC key is missing", func() {
+		callbacks := []operatorv1alpha1.Callback{{
+			Name:         "cmdb",
+			URL:          server.URL,
+			SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "cmdb-hmac"}, Key: "missing"},
+		}}
+		Expect(notifier.Notify(context.TODO(), callbacks, payload)).Should(HaveLen(1))
+		Expect(received).Should(BeEmpty())
+	})
+})
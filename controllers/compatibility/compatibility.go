@@ -0,0 +1,102 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package compatibility finds the OperandRequests depending on the APIs
+// removed by an operator upgrade.
+package compatibility
+
+import (
+	"sort"
+	"strings"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+// Consumer is an OperandRequest using the APIs of an operand
+type Consumer struct {
+	Namespace string
+	Name      string
+	Uses      []operatorv1alpha1.OperandUse
+}
+
+// ServedAPIs returns the APIs served by the owned CRDs as apiVersion/kind.
+// The CRD name is plural.group, like etcdclusters.etcd.database.coreos.com.
+func ServedAPIs(owned []olmv1alpha1.CRDDescription) map[string]bool {
+	apis := make(map[string]bool)
+	for _, crd := range owned {
+		group := ""
+		if i := strings.Index(crd.Name, "."); i >= 0 {
+			group = crd.Name[i+1:]
+		}
+		apiVersion := crd.Version
+		if group != "" {
+			apiVersion = group + "/" + crd.Version
+		}
+		apis[apiVersion+"/"+crd.Kind] = true
+	}
+	return apis
+}
+
+// RemovedAPIs returns the sorted APIs served by the CRDs owned in from,
+// but not by the CRDs owned in to.
+func RemovedAPIs(from, to []olmv1alpha1.CRDDescription) []string {
+	served := ServedAPIs(to)
+	var removed []string
+	for api := range ServedAPIs(from) {
+		if !served[api] {
+			removed = append(removed, api)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+// Incompatible returns the sorted namespaces of the consumers using any of
+// the removed APIs, and the removed APIs they use.
+// A use without Kind matches all the kinds of its APIVersion.
+func Incompatible(consumers []Consumer, removed []string) (namespaces []string, apis []string) {
+	nsSet := make(map[string]bool)
+	apiSet := make(map[string]bool)
+	for _, c := range consumers {
+		for _, use := range c.Uses {
+			for _, api := range removed {
+				if !matches(use, api) {
+					continue
+				}
+				nsSet[c.Namespace] = true
+				apiSet[api] = true
+			}
+		}
+	}
+	for ns := range nsSet {
+		namespaces = append(namespaces, ns)
+	}
+	for api := range apiSet {
+		apis = append(apis, api)
+	}
+	sort.Strings(namespaces)
+	sort.Strings(apis)
+	return
+}
+
+func matches(use operatorv1alpha1.OperandUse, api string) bool {
+	if use.Kind != "" {
+		return api == use.APIVersion+"/"+use.Kind
+	}
+	return strings.HasPrefix(api, use.APIVersion+"/") && !strings.Contains(strings.TrimPrefix(api, use.APIVersion+"/"), "/")
+}
@@ -0,0 +1,60 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package compatibility
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Compatibility", func() {
+	v1 := []olmv1alpha1.CRDDescription{
+		{Name: "etcdclusters.etcd.database.coreos.com", Version: "v1beta2", Kind: "EtcdCluster"},
+		{Name: "etcdbackups.etcd.database.coreos.com", Version: "v1beta2", Kind: "EtcdBackup"},
+	}
+	v2 := []olmv1alpha1.CRDDescription{
+		{Name: "etcdclusters.etcd.database.coreos.com", Version: "v1", Kind: "EtcdCluster"},
+		{Name: "etcdbackups.etcd.database.coreos.com", Version: "v1beta2", Kind: "EtcdBackup"},
+	}
+
+	It("Should find the APIs removed by the upgrade", func() {
+		Expect(ServedAPIs(v1)).Should(HaveKey("etcd.database.coreos.com/v1beta2/EtcdCluster"))
+		Expect(RemovedAPIs(v1, v2)).Should(Equal([]string{"etcd.database.coreos.com/v1beta2/EtcdCluster"}))
+		Expect(RemovedAPIs(v1, v1)).Should(BeEmpty())
+	})
+
+	It("Should find the consumers of the removed APIs", func() {
+		removed := []string{"etcd.database.coreos.com/v1beta2/EtcdCluster"}
+		consumers := []Consumer{
+			{Namespace: "team-b", Name: "etcd", Uses: []operatorv1alpha1.OperandUse{{APIVersion: "etcd.database.coreos.com/v1beta2"}}},
+			{Namespace: "team-a", Name: "etcd", Uses: []operatorv1alpha1.OperandUse{{APIVersion: "etcd.database.coreos.com/v1beta2", Kind: "EtcdCluster"}}},
+			{Namespace: "team-c", Name: "etcd", Uses: []operatorv1alpha1.OperandUse{{APIVersion: "etcd.database.coreos.com/v1beta2", Kind: "EtcdBackup"}}},
+			{Namespace: "team-d", Name: "etcd", Uses: []operatorv1alpha1.OperandUse{{APIVersion: "etcd.database.coreos.com/v1"}}},
+		}
+		namespaces, apis := Incompatible(consumers, removed)
+		Expect(namespaces).Should(Equal([]string{"team-a", "team-b"}))
+		Expect(apis).Should(Equal(removed))
+
+		namespaces, apis = Incompatible(consumers, nil)
+		Expect(namespaces).Should(BeEmpty())
+		Expect(apis).Should(BeEmpty())
+	})
+})
@@ -31,6 +31,9 @@ const (
 	//OpreqLabel is the label used to label the subscription/CR managed by ODLM
 	OpreqLabel string = "operator.ibm.com/opreq-control"
 
+	//ExcludeNamespaceLabel is the label used to opt a namespace out of ODLM, its value must be "true"
+	ExcludeNamespaceLabel string = "com.ibm.operand/exclude"
+
 	//OpbiNsLabel is the label used to add OperandBindInfo namespace to the secrets/configmaps watched by ODLM
 	OpbiNsLabel string = "operator.ibm.com/watched-by-opbi-with-namespace"
 
@@ -40,6 +43,9 @@ const (
 	//OpbiTypeLabel is the label used to label if secrets/configmaps are "original" or "copy"
 	OpbiTypeLabel string = "operator.ibm.com/managedBy-opbi"
 
+	//OpbiBindingAnnotation is the annotation used to record the binding key a secret/configmap is copied for
+	OpbiBindingAnnotation string = "operator.ibm.com/opbi-binding"
+
 	//BindInfoRefreshLabel is the label used to label if secrets/configmaps are "original" or "copy"
 	BindInfoRefreshLabel string = "operator.ibm.com/bindinfoRefresh"
 
@@ -64,6 +70,12 @@ const (
 	//DefaultSyncPeriod is the frequency at which watched resources are reconciled
 	DefaultSyncPeriod = 3 * time.Hour
 
+	//MemberRetryBaseDelay is the delay of the first retry of a failed member of an OperandRequest
+	MemberRetryBaseDelay = 10 * time.Second
+
+	//MemberRetryMaxDelay is the longest delay of the retries of a failed member, the delay doubles on each retry
+	MemberRetryMaxDelay = 5 * time.Minute
+
 	//DefaultCRFetchTimeout is the default timeout for getting a custom resource
 	DefaultCRFetchTimeout = 250 * time.Millisecond
 
@@ -81,4 +93,310 @@ const (
 
 	//DefaultCSVWaitPeriod is the default period for wait CSV ready
 	DefaultCSVWaitPeriod = 1 * time.Minute
+
+	//DefaultDiscoveryCacheTTL is the default time the discovered API resources are cached for
+	DefaultDiscoveryCacheTTL = 10 * time.Minute
+
+	//DefaultListPageSize is the number of objects listed per page from the API server
+	DefaultListPageSize int64 = 500
+
+	//RegistryIndexField is the field index of the OperandRequests by the namespace/name of the OperandRegistries they request
+	RegistryIndexField string = "spec.requests.registryKey"
+
+	//SubscriptionPackageIndexField is the field index of the Subscriptions by the name of their package
+	SubscriptionPackageIndexField string = "spec.name"
+
+	//ManagedIndexField is the field index of the Subscriptions by the value of their OpreqLabel
+	ManagedIndexField string = "metadata.labels.opreq-control"
+
+	//CSVPackageIndexField is the field index of the ClusterServiceVersions by the package of their properties
+	CSVPackageIndexField string = "metadata.annotations.packageName"
+
+	//CSVPropertiesAnnotation is the annotation OLM uses to record the properties of a ClusterServiceVersion
+	CSVPropertiesAnnotation string = "operatorframework.io/properties"
+
+	//LastAppliedConfigAnnotation is the annotation used to record the configuration last applied to a custom resource
+	LastAppliedConfigAnnotation string = "operator.ibm.com/odlm-last-applied-config"
+
+	//PatchesAnnotation is the annotation used to record the hash of the JSON6902 patches last applied to a custom resource
+	PatchesAnnotation string = "operator.ibm.com/odlm-applied-patches"
+
+	//ProvenanceAnnotation is the annotation used to record where the top-level fields of the spec of a custom resource come from
+	ProvenanceAnnotation string = "operator.ibm.com/odlm-field-provenance"
+
+	//AdoptAnnotation is the annotation allowing ODLM to adopt an existing custom resource it didn't create
+	AdoptAnnotation string = "odlm.ibm.com/adopt"
+
+	//ManageAnnotation is the annotation setting how ODLM manages an operand custom resource
+	ManageAnnotation string = "odlm.ibm.com/manage"
+
+	//ManagePartial is the value of the ManageAnnotation keeping ODLM away from the protected paths of a custom resource
+	ManagePartial string = "partial"
+
+	//ProtectedPathsAnnotation is the annotation listing the comma-separated paths of the fields ODLM never touches
+	//in a custom resource partially managed by ODLM, such as spec.replicas
+	ProtectedPathsAnnotation string = "odlm.ibm.com/protected-paths"
+
+	//FieldManager is the field manager ODLM server-side applies the custom resources with
+	FieldManager string = "operand-deployment-lifecycle-manager"
+
+	//StatusOverflowLabel is the label used to mark the ConfigMaps holding the full status of a truncated ODLM resource
+	StatusOverflowLabel string = "operator.ibm.com/status-overflow"
+
+	//StatusOverflowSuffix is the name suffix of the ConfigMap holding the full status of a truncated ODLM resource
+	StatusOverflowSuffix string = "-status-overflow"
+
+	//StatusOverflowKey is the key of the full status in the status overflow ConfigMap
+	StatusOverflowKey string = "status.json"
+
+	//SnapshotConfigMapKey is the key of the snapshot content in the ConfigMap an OperandSnapshot is exported to
+	SnapshotConfigMapKey string = "snapshot.json"
+
+	//StatusSizeWarningThreshold is the status size in bytes above which ODLM starts warning
+	StatusSizeWarningThreshold = 256 * 1024
+
+	//StatusSizeLimit is the status size in bytes above which ODLM truncates the status
+	StatusSizeLimit = 512 * 1024
+
+	//RenderedSpecSizeWarningThreshold is the rendered custom resource size in bytes above which ODLM starts warning
+	RenderedSpecSizeWarningThreshold = 512 * 1024
+
+	//RenderedSpecSizeLimit is the rendered custom resource size in bytes above which ODLM refuses to write it,
+	//it keeps a safe distance from the default etcd request size limit of 1.5 MiB
+	RenderedSpecSizeLimit = 1024 * 1024
+
+	//ZoneStorageClassLabel is the label used to record the StorageClass a zone-pinned StorageClass is copied from
+	ZoneStorageClassLabel string = "operator.ibm.com/zone-storage-class"
+
+	//ManifestsOwnerLabel is the label used to record the operator owning the objects applied from its manifests
+	ManifestsOwnerLabel string = "operator.ibm.com/opreq-manifests-owner"
+
+	//ManifestsSourceAnnotation is the annotation used to record the bundle image or URL the manifests are fetched from
+	ManifestsSourceAnnotation string = "operator.ibm.com/odlm-manifests-source"
+
+	//ManifestsAppliedAnnotation is the annotation used to record the objects applied from the manifests
+	ManifestsAppliedAnnotation string = "operator.ibm.com/odlm-manifests-applied"
+
+	//ManifestsConfigMapSuffix is the name suffix of the ConfigMap holding the manifests of an operator
+	ManifestsConfigMapSuffix string = "-manifests"
+
+	//BundleUnpackJobSuffix is the name suffix of the Job unpacking the bundle image of an operator
+	BundleUnpackJobSuffix string = "-bundle-unpack"
+
+	//DefaultManifestsFetchTimeout is the default timeout for fetching the manifests from a URL
+	DefaultManifestsFetchTimeout = 30 * time.Second
+
+	//ManifestsSizeLimit is the size in bytes of the manifests above which ODLM refuses to cache them,
+	//it leaves room for the metadata of the ConfigMap under its size limit of 1 MiB
+	ManifestsSizeLimit = 1000 * 1024
+
+	//HelmReleaseLabel is the label used to record the Helm release of the objects created for it
+	HelmReleaseLabel string = "operator.ibm.com/opreq-helm-release"
+
+	//HelmAppliedAnnotation is the annotation used to record the hash of the chart and values last installed
+	HelmAppliedAnnotation string = "operator.ibm.com/odlm-helm-applied"
+
+	//HelmValuesSecretSuffix is the name suffix of the Secret holding the values of a Helm release
+	HelmValuesSecretSuffix string = "-helm-values"
+
+	//HelmJobSuffix is the name suffix of the Jobs installing a Helm release, followed by the hash of the chart and values
+	HelmJobSuffix string = "-helm-"
+
+	//HelmUninstallJobSuffix is the name suffix of the Job uninstalling a Helm release
+	HelmUninstallJobSuffix string = "-helm-uninstall"
+
+	//PreDeleteHookLabel is the label used to record the service of a pre-delete Job
+	PreDeleteHookLabel string = "operator.ibm.com/opreq-pre-delete"
+
+	//PreDeleteJobSuffix is the name suffix of the Job running the pre-delete hook of a service
+	PreDeleteJobSuffix string = "-pre-delete"
+
+	//PreDeleteStartedAnnotation is the annotation used to record when the pre-delete hooks started on a custom resource
+	PreDeleteStartedAnnotation string = "operator.ibm.com/opreq-pre-delete-started"
+
+	//PostInstallHookLabel is the label used to record the service of a post-install Job
+	PostInstallHookLabel string = "operator.ibm.com/opreq-post-install"
+
+	//PostInstallJobSuffix is the name suffix of the Jobs running the post-install hook of a service, before the hash of their spec
+	PostInstallJobSuffix string = "-post-install-"
+
+	//PlacementRequestAnnotation is the annotation used to record the OperandRequest a ManifestWork is created for
+	PlacementRequestAnnotation string = "operator.ibm.com/odlm-placement-request"
+
+	//SteppingChannelAnnotation is the annotation used to record the intermediate channel a Subscription steps through
+	SteppingChannelAnnotation string = "operator.ibm.com/opreq-stepping-channel"
+
+	//InstalledCSVAnnotation is the annotation used to record the installed CSV of a Subscription, to detect its upgrades
+	InstalledCSVAnnotation string = "operator.ibm.com/opreq-installed-csv"
+
+	//UpgradedFromAnnotation is the annotation used to record the CSV a Subscription was last upgraded from
+	UpgradedFromAnnotation string = "operator.ibm.com/opreq-upgraded-from"
+
+	//UpgradePathAnnotation is the annotation used to record how OLM last upgraded a Subscription
+	UpgradePathAnnotation string = "operator.ibm.com/opreq-upgrade-path"
+
+	//SubscriptionConfigAnnotation is the annotation used to record the SubscriptionConfig last applied to a Subscription
+	SubscriptionConfigAnnotation string = "operator.ibm.com/opreq-subscription-config"
+
+	//SyncWaveAnnotation is the Argo CD annotation ordering the k8s resources and the custom resources ODLM creates for a service
+	SyncWaveAnnotation string = "argocd.argoproj.io/sync-wave"
+
+	//ForensicBundleLabel is the label used to record the failed operand of a forensic bundle ConfigMap
+	ForensicBundleLabel string = "operator.ibm.com/opreq-forensic-bundle"
+
+	//ForensicBundleSuffix is the name suffix of the ConfigMap holding the forensic bundle of a failed operand
+	ForensicBundleSuffix string = "-forensics"
+
+	//ReferenceLedgerSuffix is the name suffix of the ConfigMap counting the OperandRequests referencing the operators of an OperandRegistry
+	ReferenceLedgerSuffix string = "-references"
+
+	//TenantRequestLabel is the label used to mark the ConfigMaps converted into an OperandRequest, its value must be "true"
+	TenantRequestLabel string = "operator.ibm.com/tenant-request"
+
+	//TenantRequestDataKey is the key of the requests in the data of a tenant ConfigMap
+	TenantRequestDataKey string = "requests"
+
+	//TenantRequestPhaseAnnotation is the annotation used to report the phase of the OperandRequest of a tenant ConfigMap
+	TenantRequestPhaseAnnotation string = "operator.ibm.com/tenant-request-phase"
+
+	//TenantRequestMessageAnnotation is the annotation used to report why a tenant ConfigMap isn't converted
+	TenantRequestMessageAnnotation string = "operator.ibm.com/tenant-request-message"
+
+	//OperandRequestSetLabel is the label used to record the OperandRequestSet of the OperandRequests it creates
+	OperandRequestSetLabel string = "operator.ibm.com/opreq-set"
+)
+
+// The reasons of the Events recorded for the lifecycle transitions
+const (
+	//EventReasonSubscribed is recorded when the Subscription of an operator is created
+	EventReasonSubscribed string = "Subscribed"
+
+	//EventReasonInstalled is recorded when an operator is installed
+	EventReasonInstalled string = "Installed"
+
+	//EventReasonUpgrading is recorded when the Subscription of an operator is switched to another channel
+	EventReasonUpgrading string = "Upgrading"
+
+	//EventReasonUpgraded is recorded when an operator is upgraded
+	EventReasonUpgraded string = "Upgraded"
+
+	//EventReasonReplacesChainBroken is recorded when the channel of a Subscription doesn't replace its installed CSV
+	EventReasonReplacesChainBroken string = "ReplacesChainBroken"
+
+	//EventReasonUpgradeBlocked is recorded when the channel of an operator has no upgrade path from its installed CSV
+	EventReasonUpgradeBlocked string = "UpgradeBlocked"
+
+	//EventReasonBlocked is recorded when a ClusterOperandPolicy blocks an operator of an OperandRequest
+	EventReasonBlocked string = "Blocked"
+
+	//EventReasonWaitingForDependencies is recorded when the OperandBootstrap holds the first install of an operator
+	EventReasonWaitingForDependencies string = "WaitingForDependencies"
+
+	//EventReasonPermissionDenied is recorded when an OperandBindInfo doesn't allow the namespace of an OperandRequest
+	EventReasonPermissionDenied string = "PermissionDenied"
+
+	//EventReasonOperatorGroupConflict is recorded when the namespace of an operator has OperatorGroups OLM can't install into
+	EventReasonOperatorGroupConflict string = "OperatorGroupConflict"
+
+	//EventReasonOperatorGroupRepaired is recorded when the conflicting OperatorGroups of a namespace are merged
+	EventReasonOperatorGroupRepaired string = "OperatorGroupRepaired"
+
+	//EventReasonCatalogUnhealthy is recorded when an operator waits for its CatalogSource to be ready before it is subscribed
+	EventReasonCatalogUnhealthy string = "CatalogUnhealthy"
+
+	//EventReasonUpgradePath is recorded when an operator is upgraded, with the path OLM took
+	EventReasonUpgradePath string = "UpgradePath"
+
+	//EventReasonSkipRange is recorded when the olm.skipRange of a channel doesn't match the allowSkipRange of an operator
+	EventReasonSkipRange string = "SkipRange"
+
+	//EventReasonSkipRangeDenied is recorded when ODLM doesn't approve an upgrade skipping versions
+	EventReasonSkipRangeDenied string = "SkipRangeDenied"
+
+	//EventReasonVersionPinned is recorded when ODLM doesn't approve an InstallPlan installing another version than the pinned one
+	EventReasonVersionPinned string = "VersionPinned"
+
+	//EventReasonInstallFailed is recorded when an operator fails to be installed or upgraded
+	EventReasonInstallFailed string = "InstallFailed"
+
+	//EventReasonOperandCreated is recorded when a custom resource or a k8s resource of an operand is created
+	EventReasonOperandCreated string = "OperandCreated"
+
+	//EventReasonOperandMerged is recorded when the configuration is merged into an existing custom resource
+	EventReasonOperandMerged string = "OperandMerged"
+
+	//EventReasonOperandUpdated is recorded when a k8s resource of an operand is updated
+	EventReasonOperandUpdated string = "OperandUpdated"
+
+	//EventReasonOperandDrifted is recorded when the changes made to a custom resource conflict with the configuration
+	EventReasonOperandDrifted string = "OperandDrifted"
+
+	//EventReasonOperandReverted is recorded when the changes conflicting with the configuration are reverted in a custom resource
+	EventReasonOperandReverted string = "OperandReverted"
+
+	//EventReasonOperandPruned is recorded when the fields removed from the configuration are removed from a custom resource
+	EventReasonOperandPruned string = "OperandPruned"
+
+	//EventReasonOperandRemoved is recorded when an operator and its operands removed from an OperandRequest are pruned
+	EventReasonOperandRemoved string = "OperandRemoved"
+
+	//EventReasonOperandOrphaned is recorded when an operator and its operands removed from an OperandRequest are left running
+	EventReasonOperandOrphaned string = "OperandOrphaned"
+
+	//EventReasonOperandAdopted is recorded when an existing custom resource is adopted by ODLM
+	EventReasonOperandAdopted string = "OperandAdopted"
+
+	//EventReasonOperandReleased is recorded when an adopted custom resource is released instead of being deleted
+	EventReasonOperandReleased string = "OperandReleased"
+
+	//EventReasonTeardownTimeout is recorded when a custom resource isn't removed within the teardown grace period of the deleted OperandRequest
+	EventReasonTeardownTimeout string = "OperandTeardownTimeout"
+
+	//EventReasonPreDeleteHookStarted is recorded when the pre-delete hooks of a service start before its custom resources are deleted
+	EventReasonPreDeleteHookStarted string = "PreDeleteHookStarted"
+
+	//EventReasonPreDeleteHookFailed is recorded when a pre-delete hook of a service fails or times out
+	EventReasonPreDeleteHookFailed string = "PreDeleteHookFailed"
+
+	//EventReasonVerified is recorded when the post-install hooks of a service succeed
+	EventReasonVerified string = "Verified"
+
+	//EventReasonVerificationFailed is recorded when a post-install hook of a service fails
+	EventReasonVerificationFailed string = "VerificationFailed"
+
+	//EventReasonSchemaValidationFailed is recorded when a rendered custom resource doesn't match the schema of its CRD
+	EventReasonSchemaValidationFailed string = "SchemaValidationFailed"
+
+	//EventReasonLicenseRequired is recorded when ODLM doesn't subscribe to an operator until it is entitled
+	EventReasonLicenseRequired string = "LicenseRequired"
+
+	//EventReasonBindInfoPropagated is recorded when a Secret or a ConfigMap is copied to the namespace of an OperandRequest
+	EventReasonBindInfoPropagated string = "BindInfoPropagated"
+
+	//EventReasonBindInfoCopyDeleted is recorded when a copied Secret or ConfigMap isn't requested anymore and is deleted
+	EventReasonBindInfoCopyDeleted string = "BindInfoCopyDeleted"
+
+	//EventReasonForensicBundleCollected is recorded when the forensic bundle of a failed operand is collected
+	EventReasonForensicBundleCollected string = "ForensicBundleCollected"
+
+	//EventReasonCallbackFailed is recorded when a callback of the OperatorConfig fails for a lifecycle event of an operand
+	EventReasonCallbackFailed string = "CallbackFailed"
+
+	//EventReasonTenantRequestInvalid is recorded when a tenant ConfigMap can't be converted into an OperandRequest
+	EventReasonTenantRequestInvalid string = "TenantRequestInvalid"
+
+	//EventReasonGarbageCollected is recorded when an orphaned Subscription is deleted with its ClusterServiceVersion
+	EventReasonGarbageCollected string = "GarbageCollected"
+
+	//EventReasonOperatorStuck is recorded when the operator checker finds a Subscription stuck in OLM it can't recover
+	EventReasonOperatorStuck string = "OperatorStuck"
+
+	//EventReasonOperatorRecovered is recorded when the operator checker deletes the ClusterServiceVersion a Subscription is stuck on
+	EventReasonOperatorRecovered string = "OperatorRecovered"
+
+	//EventReasonRequestSetInvalid is recorded when an OperandRequestSet can't create the OperandRequest of a namespace
+	EventReasonRequestSetInvalid string = "RequestSetInvalid"
+
+	//EventReasonHelmReleaseAbandoned is recorded when a Helm release is left installed, its ServiceAccount is not found
+	EventReasonHelmReleaseAbandoned string = "HelmReleaseAbandoned"
 )
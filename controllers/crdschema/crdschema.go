@@ -0,0 +1,159 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package crdschema validates the specs of the custom resources against the OpenAPI schemas of their CRDs.
+package crdschema
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	odlmutil "github.com/IBM/operand-deployment-lifecycle-manager/pkg/util/v1"
+)
+
+// GetSpecSchema returns the OpenAPI schema of the spec of the given kind from its CRD.
+// It returns nil if the kind isn't served yet, or its CRD has no schema for the spec.
+func GetSpecSchema(ctx context.Context, reader client.Reader, dc discovery.DiscoveryInterface, apiVersion, kind string) (*apiextensionsv1.JSONSchemaProps, error) {
+	plural, err := odlmutil.ResourcePlural(dc, apiVersion, kind)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to discover the kind %s of %s", kind, apiVersion)
+	}
+	if plural == "" {
+		return nil, nil
+	}
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: plural + "." + gv.Group}, crd); err != nil {
+		// The built-in kinds have no CRD
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the CustomResourceDefinition %s.%s", plural, gv.Group)
+	}
+	for _, v := range crd.Spec.Versions {
+		if v.Name != gv.Version || v.Schema == nil || v.Schema.OpenAPIV3Schema == nil {
+			continue
+		}
+		if spec, ok := v.Schema.OpenAPIV3Schema.Properties["spec"]; ok {
+			return &spec, nil
+		}
+	}
+	return nil, nil
+}
+
+// ValidateTemplates validates the templates of the custom resources of a service, keyed by their kinds in any case,
+// against the CRDs of the custom resources with the same kinds in the alm-examples of the operator
+func ValidateTemplates(ctx context.Context, reader client.Reader, dc discovery.DiscoveryInterface, examples []unstructured.Unstructured, templates map[string]runtime.RawExtension, fldPath *field.Path) (field.ErrorList, error) {
+	crNames := make([]string, 0, len(templates))
+	for crName := range templates {
+		crNames = append(crNames, crName)
+	}
+	sort.Strings(crNames)
+
+	var allErrs field.ErrorList
+	for _, crName := range crNames {
+		for _, example := range examples {
+			if !strings.EqualFold(example.GetKind(), crName) {
+				continue
+			}
+			specSchema, err := GetSpecSchema(ctx, reader, dc, example.GetAPIVersion(), example.GetKind())
+			if err != nil {
+				return nil, err
+			}
+			template := templates[crName]
+			allErrs = append(allErrs, ValidateSpec(specSchema, &template, fldPath.Key(crName))...)
+			break
+		}
+	}
+	return allErrs, nil
+}
+
+// ValidateSpec validates the spec against the OpenAPI schema, the errors are reported under the field path
+func ValidateSpec(specSchema *apiextensionsv1.JSONSchemaProps, spec *runtime.RawExtension, fldPath *field.Path) field.ErrorList {
+	if specSchema == nil || spec == nil || len(spec.Raw) == 0 {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(spec.Raw, &value); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, string(spec.Raw), err.Error())}
+	}
+
+	internal := &apiextensions.JSONSchemaProps{}
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(specSchema, internal, nil); err != nil {
+		return field.ErrorList{field.InternalError(fldPath, err)}
+	}
+	validator, _, err := validation.NewSchemaValidator(&apiextensions.CustomResourceValidation{OpenAPIV3Schema: internal})
+	if err != nil {
+		return field.ErrorList{field.InternalError(fldPath, err)}
+	}
+	allErrs := validation.ValidateCustomResource(fldPath, value, validator)
+	return append(allErrs, unknownFields(specSchema, value, fldPath)...)
+}
+
+// unknownFields returns the fields of the value which aren't in the structural schema, the API server would prune them
+func unknownFields(s *apiextensionsv1.JSONSchemaProps, value interface{}, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if prop, ok := s.Properties[key]; ok {
+				allErrs = append(allErrs, unknownFields(&prop, item, fldPath.Child(key))...)
+				continue
+			}
+			if s.AdditionalProperties != nil {
+				if s.AdditionalProperties.Schema != nil {
+					allErrs = append(allErrs, unknownFields(s.AdditionalProperties.Schema, item, fldPath.Key(key))...)
+				}
+				continue
+			}
+			// The embedded resources have the implicit apiVersion, kind and metadata
+			if s.XEmbeddedResource && (key == "apiVersion" || key == "kind" || key == "metadata") {
+				continue
+			}
+			if s.XPreserveUnknownFields != nil && *s.XPreserveUnknownFields {
+				continue
+			}
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child(key), "unknown field, it isn't in the schema of the CRD"))
+		}
+	case []interface{}:
+		if s.Items == nil || s.Items.Schema == nil {
+			return nil
+		}
+		for i, item := range v {
+			allErrs = append(allErrs, unknownFields(s.Items.Schema, item, fldPath.Index(i))...)
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,55 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package crdschema
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var _ = Describe("CRD schema", func() {
+
+	specSchema := &apiextensionsv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"replicas": {Type: "integer"},
+			"labels": {
+				Type:                 "object",
+				AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{Allows: true, Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}},
+			},
+		},
+	}
+	fldPath := field.NewPath("spec")
+
+	It("Should accept the valid specs", func() {
+		Expect(ValidateSpec(specSchema, &runtime.RawExtension{Raw: []byte(`{"replicas":2,"labels":{"app":"jenkins"}}`)}, fldPath)).Should(BeEmpty())
+		Expect(ValidateSpec(nil, &runtime.RawExtension{Raw: []byte(`{"unknown":true}`)}, fldPath)).Should(BeEmpty())
+		Expect(ValidateSpec(specSchema, nil, fldPath)).Should(BeEmpty())
+	})
+
+	It("Should report the invalid and the unknown fields", func() {
+		errs := ValidateSpec(specSchema, &runtime.RawExtension{Raw: []byte(`{"replicas":"two","unknown":true}`)}, fldPath)
+		var fields []string
+		for _, err := range errs {
+			fields = append(fields, err.Field)
+		}
+		Expect(fields).Should(ConsistOf("spec.replicas", "spec.unknown"))
+	})
+})
@@ -0,0 +1,62 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package entitlement checks the licenses of the operators, ODLM doesn't subscribe to an operator until it is entitled.
+package entitlement
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/postinstall"
+)
+
+// Check returns why the operator isn't entitled, or an empty string when it is.
+// The Secret is looked up in the namespace when it has none, the error is only returned when the Secret can't be read.
+func Check(ctx context.Context, reader client.Reader, httpClient *http.Client, ent *operatorv1alpha1.Entitlement, namespace string) (string, error) {
+	if ent == nil {
+		return "", nil
+	}
+	if ref := ent.SecretRef; ref != nil {
+		if ref.Namespace != "" {
+			namespace = ref.Namespace
+		}
+		secret := &corev1.Secret{}
+		if err := reader.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				return fmt.Sprintf("the license Secret %s/%s is not found", namespace, ref.Name), nil
+			}
+			return "", errors.Wrapf(err, "failed to get the license Secret %s/%s", namespace, ref.Name)
+		}
+		if ref.Key != "" && len(secret.Data[ref.Key]) == 0 {
+			return fmt.Sprintf("the license Secret %s/%s has no %s key", namespace, ref.Name, ref.Key), nil
+		}
+	}
+	if ent.API != nil {
+		if err := postinstall.CheckHTTP(ctx, httpClient, ent.API); err != nil {
+			return "the entitlement API denied the license: " + err.Error(), nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,107 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package entitlement
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Entitlement", func() {
+
+	var (
+		ctx = context.Background()
+		c   client.Client
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+		c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "ibm-entitlement-key", Namespace: "ibm-common-services"},
+				Data:       map[string][]byte{"license": []byte("accepted")},
+			},
+		).Build()
+	})
+
+	It("Should entitle the operator without an entitlement", func() {
+		reason, err := Check(ctx, c, nil, nil, "ibm-common-services")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(BeEmpty())
+	})
+
+	It("Should check the license Secret", func() {
+		ent := &operatorv1alpha1.Entitlement{SecretRef: &operatorv1alpha1.EntitlementSecretRef{Name: "ibm-entitlement-key"}}
+		reason, err := Check(ctx, c, nil, ent, "ibm-common-services")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(BeEmpty())
+
+		ent.SecretRef.Key = "license"
+		reason, err = Check(ctx, c, nil, ent, "ibm-common-services")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(BeEmpty())
+
+		ent.SecretRef.Key = "token"
+		reason, err = Check(ctx, c, nil, ent, "ibm-common-services")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(Equal("the license Secret ibm-common-services/ibm-entitlement-key has no token key"))
+
+		ent.SecretRef = &operatorv1alpha1.EntitlementSecretRef{Name: "ibm-entitlement-key", Namespace: "team-a"}
+		reason, err = Check(ctx, c, nil, ent, "ibm-common-services")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(Equal("the license Secret team-a/ibm-entitlement-key is not found"))
+	})
+
+	It("Should call the entitlement API", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.URL.Path == "/entitled" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer server.Close()
+
+		ent := &operatorv1alpha1.Entitlement{
+			SecretRef: &operatorv1alpha1.EntitlementSecretRef{Name: "ibm-entitlement-key"},
+			API:       &operatorv1alpha1.HTTPCheck{URL: server.URL + "/entitled"},
+		}
+		reason, err := Check(ctx, c, server.Client(), ent, "ibm-common-services")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(BeEmpty())
+
+		ent.API.URL = server.URL + "/denied"
+		reason, err = Check(ctx, c, server.Client(), ent, "ibm-common-services")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(ContainSubstring("the entitlement API denied the license"))
+		Expect(reason).To(ContainSubstring("403"))
+	})
+})
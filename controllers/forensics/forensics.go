@@ -0,0 +1,189 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package forensics builds the diagnostic bundle collected when an operand
+// fails, so the support cases include the same diagnostics every time.
+package forensics
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// MaxBundleSize keeps the bundle under the 1MiB limit of a ConfigMap, with room for its metadata
+	MaxBundleSize = 900 * 1024
+	// MaxEvents is the number of the most recent Events in the bundle
+	MaxEvents = 50
+	// MaxLogLines is the number of the most recent ODLM log lines in the bundle
+	MaxLogLines = 200
+
+	truncatedSuffix = "\n... truncated\n"
+)
+
+// Bundle is the forensic bundle of a failed operand, its files are keyed by name
+type Bundle struct {
+	Files map[string]string
+}
+
+// New returns an empty Bundle
+func New() *Bundle {
+	return &Bundle{Files: make(map[string]string)}
+}
+
+// AddObjects adds the objects to the file as YAML documents, without their managed fields
+func (b *Bundle) AddObjects(name string, objs ...runtime.Object) error {
+	var docs []string
+	for _, obj := range objs {
+		if obj == nil {
+			continue
+		}
+		obj = obj.DeepCopyObject()
+		if accessor, ok := obj.(metav1.Object); ok {
+			accessor.SetManagedFields(nil)
+		}
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("failed to marshal the object for %s: %v", name, err)
+		}
+		docs = append(docs, string(data))
+	}
+	if len(docs) != 0 {
+		b.Files[name] = strings.Join(docs, "---\n")
+	}
+	return nil
+}
+
+// AddText adds a text file
+func (b *Bundle) AddText(name, text string) {
+	if text != "" {
+		b.Files[name] = text
+	}
+}
+
+// Size returns the total size of the files
+func (b *Bundle) Size() int {
+	size := 0
+	for name, data := range b.Files {
+		size += len(name) + len(data)
+	}
+	return size
+}
+
+// Truncate cuts the largest files until the bundle fits in the limit
+func (b *Bundle) Truncate(limit int) {
+	for b.Size() > limit {
+		largest := ""
+		for name, data := range b.Files {
+			if largest == "" || len(data) > len(b.Files[largest]) {
+				largest = name
+			}
+		}
+		data := b.Files[largest]
+		keep := len(data) - (b.Size() - limit) - len(truncatedSuffix)
+		if keep <= 0 {
+			delete(b.Files, largest)
+			continue
+		}
+		b.Files[largest] = data[:keep] + truncatedSuffix
+	}
+}
+
+// ConfigMap returns the ConfigMap holding the bundle
+func (b *Bundle) ConfigMap(name, namespace string, labels map[string]string) *corev1.ConfigMap {
+	data := make(map[string]string, len(b.Files))
+	for file, content := range b.Files {
+		data[file] = content
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Data: data,
+	}
+}
+
+// WriteTo writes the files of the bundle into the directory, which is created if it doesn't exist
+func (b *Bundle) WriteTo(dir string) error {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("failed to create the directory %s: %v", dir, err)
+	}
+	for name, data := range b.Files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0640); err != nil {
+			return fmt.Errorf("failed to write the file %s: %v", filepath.Join(dir, name), err)
+		}
+	}
+	return nil
+}
+
+// RecentEvents returns the most recent Events, oldest first, about the objects with the names
+func RecentEvents(events []corev1.Event, names map[string]bool, max int) []corev1.Event {
+	var found []corev1.Event
+	for _, e := range events {
+		if names[e.InvolvedObject.Name] {
+			found = append(found, e)
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return eventTime(found[i]).Before(eventTime(found[j]))
+	})
+	if len(found) > max {
+		found = found[len(found)-max:]
+	}
+	return found
+}
+
+func eventTime(e corev1.Event) time.Time {
+	if !e.LastTimestamp.IsZero() {
+		return e.LastTimestamp.Time
+	}
+	if !e.EventTime.IsZero() {
+		return e.EventTime.Time
+	}
+	return e.CreationTimestamp.Time
+}
+
+// LogExcerpt returns the last lines of the log mentioning any of the keywords
+func LogExcerpt(log string, keywords []string, max int) string {
+	var lines []string
+	for _, line := range strings.Split(log, "\n") {
+		for _, keyword := range keywords {
+			if keyword != "" && strings.Contains(line, keyword) {
+				lines = append(lines, line)
+				break
+			}
+		}
+	}
+	if len(lines) > max {
+		lines = lines[len(lines)-max:]
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
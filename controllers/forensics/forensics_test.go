@@ -0,0 +1,110 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package forensics
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Forensic bundle", func() {
+
+	It("Should add the objects without their managed fields", func() {
+		cm := &corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:          "etcd",
+				Namespace:     "etcd-ns",
+				ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "odlm"}},
+			},
+		}
+		b := New()
+		Expect(b.AddObjects("objects.yaml", cm, cm)).To(Succeed())
+		Expect(b.Files["objects.yaml"]).Should(ContainSubstring("name: etcd"))
+		Expect(b.Files["objects.yaml"]).ShouldNot(ContainSubstring("managedFields"))
+		Expect(strings.Count(b.Files["objects.yaml"], "---\n")).Should(Equal(1))
+		Expect(cm.ManagedFields).Should(HaveLen(1))
+
+		Expect(b.AddObjects("empty.yaml")).To(Succeed())
+		Expect(b.Files).ShouldNot(HaveKey("empty.yaml"))
+	})
+
+	It("Should truncate the largest files to the limit", func() {
+		b := New()
+		b.AddText("small.txt", "small")
+		b.AddText("large.txt", strings.Repeat("x", 1000))
+		b.Truncate(500)
+		Expect(b.Size()).Should(BeNumerically("<=", 500))
+		Expect(b.Files["small.txt"]).Should(Equal("small"))
+		Expect(b.Files["large.txt"]).Should(HaveSuffix(truncatedSuffix))
+
+		b.Truncate(10)
+		Expect(b.Size()).Should(BeNumerically("<=", 10))
+	})
+
+	It("Should keep the recent Events of the objects", func() {
+		now := time.Now()
+		event := func(name, object string, age time.Duration) corev1.Event {
+			return corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: name},
+				InvolvedObject: corev1.ObjectReference{Name: object},
+				LastTimestamp:  metav1.NewTime(now.Add(-age)),
+			}
+		}
+		events := []corev1.Event{
+			event("latest", "etcd", 0),
+			event("other", "jenkins", time.Minute),
+			event("oldest", "etcd", 2*time.Minute),
+			event("older", "etcd-csv", time.Minute),
+		}
+		recent := RecentEvents(events, map[string]bool{"etcd": true, "etcd-csv": true}, 2)
+		Expect(recent).Should(HaveLen(2))
+		Expect(recent[0].Name).Should(Equal("older"))
+		Expect(recent[1].Name).Should(Equal("latest"))
+	})
+
+	It("Should keep the last log lines about the operand", func() {
+		log := "I0101 etcd is installing\nI0101 jenkins is installing\nE0101 etcd is failed\nI0101 etcd is retried\n"
+		Expect(LogExcerpt(log, []string{"etcd"}, 2)).Should(Equal("E0101 etcd is failed\nI0101 etcd is retried\n"))
+		Expect(LogExcerpt(log, []string{"mongodb"}, 2)).Should(BeEmpty())
+	})
+
+	It("Should write the files into the directory", func() {
+		dir, err := ioutil.TempDir("", "forensics")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		b := New()
+		b.AddText("summary.yaml", "operand: etcd\n")
+		Expect(b.WriteTo(filepath.Join(dir, "ns", "request"))).To(Succeed())
+		data, err := ioutil.ReadFile(filepath.Join(dir, "ns", "request", "summary.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).Should(Equal("operand: etcd\n"))
+
+		cm := b.ConfigMap("request-etcd-forensics", "ns", map[string]string{"app": "odlm"})
+		Expect(cm.Data).Should(Equal(b.Files))
+		Expect(cm.Labels).Should(HaveKeyWithValue("app", "odlm"))
+	})
+})
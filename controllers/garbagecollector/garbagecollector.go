@@ -0,0 +1,269 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package garbagecollector periodically collects the Subscriptions created by ODLM none of their OperandRequests
+// exists anymore, with their ClusterServiceVersions.
+package garbagecollector
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/ownership"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/metrics"
+)
+
+const (
+	// DefaultInterval is the default time between two collections
+	DefaultInterval = time.Hour
+	// MinInterval is the shortest time between two collections
+	MinInterval = time.Minute
+
+	// minAge keeps the Subscriptions created recently, their OperandRequest may not be in the cache yet
+	minAge = 10 * time.Minute
+	// maxReportedOrphans is the number of the orphaned Subscriptions listed in the report
+	maxReportedOrphans = 50
+
+	kindSubscription          = "Subscription"
+	kindClusterServiceVersion = "ClusterServiceVersion"
+)
+
+var log = logging.Logger("garbagecollector")
+
+// configured is the garbage collection of the running OperatorConfig
+var configured = struct {
+	sync.RWMutex
+	mode     string
+	interval time.Duration
+}{mode: operatorv1alpha1.GarbageCollectionDisabled, interval: DefaultInterval}
+
+// Set sets the mode and the interval of the garbage collection at runtime
+func Set(mode string, interval time.Duration) {
+	configured.Lock()
+	defer configured.Unlock()
+	configured.mode = mode
+	configured.interval = interval
+}
+
+// Get returns the mode and the interval of the garbage collection ODLM is running with
+func Get() (string, time.Duration) {
+	configured.RLock()
+	defer configured.RUnlock()
+	return configured.mode, configured.interval
+}
+
+// Orphan is a Subscription created by ODLM none of its OperandRequests exists anymore
+type Orphan struct {
+	Subscription *olmv1alpha1.Subscription
+	// Requests are the OperandRequests in the annotations of the Subscription, as namespace/name
+	Requests []string
+}
+
+// FindOrphans returns the Subscriptions labeled by ODLM whose OperandRequests in their annotations all don't exist.
+// The Subscriptions without an OperandRequest in their annotations, created less than ten minutes ago,
+// or labeled not to be uninstalled are kept.
+func FindOrphans(subs []olmv1alpha1.Subscription, requests []operatorv1alpha1.OperandRequest, now time.Time) []Orphan {
+	existing := make(map[string]bool)
+	for _, request := range requests {
+		existing[request.Namespace+"/"+request.Name] = true
+	}
+
+	var orphans []Orphan
+	for i := range subs {
+		sub := &subs[i]
+		if sub.Labels[constant.OpreqLabel] != "true" || sub.Labels[constant.NotUninstallLabel] == "true" {
+			continue
+		}
+		if sub.DeletionTimestamp != nil || now.Sub(sub.CreationTimestamp.Time) < minAge {
+			continue
+		}
+		keys := ownership.IndexValues(sub)
+		if len(keys) == 0 {
+			continue
+		}
+		orphaned := true
+		for _, key := range keys {
+			if existing[key] {
+				orphaned = false
+				break
+			}
+		}
+		if orphaned {
+			orphans = append(orphans, Orphan{Subscription: sub, Requests: keys})
+		}
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		return orphans[i].Subscription.Namespace+"/"+orphans[i].Subscription.Name < orphans[j].Subscription.Namespace+"/"+orphans[j].Subscription.Name
+	})
+	return orphans
+}
+
+// Collector collects the orphaned Subscriptions at the interval of the OperatorConfig
+type Collector struct {
+	*deploy.ODLMOperator
+	// period is how often the interval and the mode of the OperatorConfig are checked
+	period  time.Duration
+	lastRun time.Time
+}
+
+// NewCollector returns a Collector checking every minute if a collection is due
+func NewCollector(operator *deploy.ODLMOperator) *Collector {
+	return &Collector{ODLMOperator: operator, period: MinInterval}
+}
+
+// NeedLeaderElection returns true, only the leader deletes the orphaned objects
+func (c *Collector) NeedLeaderElection() bool {
+	return true
+}
+
+// Start runs the collections until the context is done
+func (c *Collector) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			mode, interval := Get()
+			if mode == operatorv1alpha1.GarbageCollectionDisabled || now.Sub(c.lastRun) < interval {
+				continue
+			}
+			c.lastRun = now
+			if err := c.Collect(ctx, mode, now); err != nil {
+				log.Error(err, "failed to collect the orphaned Subscriptions")
+			}
+		}
+	}
+}
+
+// Collect finds the orphaned Subscriptions, deletes them with their ClusterServiceVersions in the Delete mode,
+// and reports them in the status of the OperatorConfig
+func (c *Collector) Collect(ctx context.Context, mode string, now time.Time) error {
+	subList, err := c.ListManagedSubscriptions(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the Subscriptions")
+	}
+	requestList := &operatorv1alpha1.OperandRequestList{}
+	if err := c.Client.List(ctx, requestList); err != nil {
+		return errors.Wrap(err, "failed to list the OperandRequests")
+	}
+	orphans := FindOrphans(subList.Items, requestList.Items, now)
+
+	report := &operatorv1alpha1.GarbageCollectionReport{
+		Mode:               mode,
+		LastCollectionTime: metav1.NewTime(now),
+		OrphanCount:        len(orphans),
+	}
+	csvCount := 0
+	for _, orphan := range orphans {
+		sub := orphan.Subscription
+		orphaned := operatorv1alpha1.OrphanedSubscription{
+			Name:                  sub.Name,
+			Namespace:             sub.Namespace,
+			ClusterServiceVersion: sub.Status.InstalledCSV,
+			Requests:              orphan.Requests,
+		}
+		if sub.Status.InstalledCSV != "" {
+			csvCount++
+		}
+		log.Info("Found an orphaned Subscription", "subscription", sub.Namespace+"/"+sub.Name, "csv", sub.Status.InstalledCSV, "requests", strings.Join(orphan.Requests, ","), "mode", mode)
+		if mode == operatorv1alpha1.GarbageCollectionDelete {
+			if err := c.delete(ctx, sub); err != nil {
+				log.Error(err, "failed to delete the orphaned Subscription", "subscription", sub.Namespace+"/"+sub.Name)
+				orphaned.Error = err.Error()
+			} else {
+				orphaned.Deleted = true
+			}
+		}
+		if len(report.Orphans) < maxReportedOrphans {
+			report.Orphans = append(report.Orphans, orphaned)
+		}
+	}
+	metrics.SetOrphanedObjects(kindSubscription, len(orphans))
+	metrics.SetOrphanedObjects(kindClusterServiceVersion, csvCount)
+
+	return c.report(ctx, report)
+}
+
+// delete deletes the ClusterServiceVersion of the Subscription and the Subscription,
+// OLM doesn't delete the ClusterServiceVersion of a deleted Subscription
+func (c *Collector) delete(ctx context.Context, sub *olmv1alpha1.Subscription) error {
+	if sub.Status.InstalledCSV != "" {
+		csv := &olmv1alpha1.ClusterServiceVersion{}
+		csv.Name = sub.Status.InstalledCSV
+		csv.Namespace = sub.Namespace
+		if err := c.Client.Delete(ctx, csv); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to delete the ClusterServiceVersion %s/%s", csv.Namespace, csv.Name)
+			}
+		} else {
+			metrics.IncGarbageCollected(kindClusterServiceVersion)
+		}
+	}
+	if err := c.Client.Delete(ctx, sub); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to delete the Subscription %s/%s", sub.Namespace, sub.Name)
+	}
+	metrics.IncGarbageCollected(kindSubscription)
+	c.recordEvent(ctx, sub)
+	return nil
+}
+
+// recordEvent records the deleted Subscription on the OperatorConfig
+func (c *Collector) recordEvent(ctx context.Context, sub *olmv1alpha1.Subscription) {
+	config := &operatorv1alpha1.OperatorConfig{}
+	if err := c.Client.Get(ctx, types.NamespacedName{Namespace: util.GetOperatorNamespace(), Name: operatorv1alpha1.OperatorConfigName}, config); err != nil {
+		return
+	}
+	message := "Deleted the orphaned Subscription " + sub.Namespace + "/" + sub.Name
+	if sub.Status.InstalledCSV != "" {
+		message += " and its ClusterServiceVersion " + sub.Status.InstalledCSV
+	}
+	c.Recorder.Event(config, corev1.EventTypeNormal, constant.EventReasonGarbageCollected, message)
+}
+
+// report writes the report into the status of the OperatorConfig, if it exists
+func (c *Collector) report(ctx context.Context, report *operatorv1alpha1.GarbageCollectionReport) error {
+	config := &operatorv1alpha1.OperatorConfig{}
+	if err := c.Client.Get(ctx, types.NamespacedName{Namespace: util.GetOperatorNamespace(), Name: operatorv1alpha1.OperatorConfigName}, config); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	original := config.DeepCopy()
+	config.Status.GarbageCollection = report
+	return c.PatchStatus(ctx, config, original, func(latest client.Object) error {
+		latest.(*operatorv1alpha1.OperatorConfig).Status.GarbageCollection = report
+		return nil
+	})
+}
@@ -0,0 +1,146 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package garbagecollector
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+)
+
+func newSubscription(name, csv string, created time.Time, requests ...string) *olmv1alpha1.Subscription {
+	sub := &olmv1alpha1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "ibm-common-services",
+			Labels:            map[string]string{constant.OpreqLabel: "true"},
+			Annotations:       map[string]string{"ibm-common-services.common-service/registry": "true"},
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec:   &olmv1alpha1.SubscriptionSpec{Package: name},
+		Status: olmv1alpha1.SubscriptionStatus{InstalledCSV: csv},
+	}
+	for _, r := range requests {
+		sub.Annotations[r+"/request"] = "true"
+	}
+	return sub
+}
+
+func newRequest(namespace, name string) *operatorv1alpha1.OperandRequest {
+	return &operatorv1alpha1.OperandRequest{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+}
+
+var _ = Describe("Garbage collector", func() {
+
+	now := time.Now()
+	old := now.Add(-time.Hour)
+
+	It("Should find the Subscriptions none of their OperandRequests exists", func() {
+		kept := newSubscription("ibm-iam-operator", "", old, "ibm-common-services.common-service", "team-a.iam")
+		orphaned := newSubscription("ibm-licensing-operator", "", old, "team-b.licensing.v2")
+		recent := newSubscription("ibm-zen-operator", "", now.Add(-time.Minute), "team-c.zen")
+		retained := newSubscription("ibm-cert-manager-operator", "", old, "team-d.cert-manager")
+		retained.Labels[constant.NotUninstallLabel] = "true"
+		unannotated := newSubscription("ibm-metering-operator", "", old)
+		unlabeled := newSubscription("etcd", "", old, "team-e.etcd")
+		unlabeled.Labels = nil
+
+		subs := []olmv1alpha1.Subscription{*kept, *orphaned, *recent, *retained, *unannotated, *unlabeled}
+		requests := []operatorv1alpha1.OperandRequest{*newRequest("team-a", "iam")}
+		orphans := FindOrphans(subs, requests, now)
+		Expect(orphans).Should(HaveLen(1))
+		Expect(orphans[0].Subscription.Name).Should(Equal("ibm-licensing-operator"))
+		Expect(orphans[0].Requests).Should(Equal([]string{"team-b/licensing.v2"}))
+
+		Expect(FindOrphans(subs, nil, now)).Should(HaveLen(2))
+	})
+
+	Context("Collecting the orphaned Subscriptions", func() {
+		var (
+			ctx       = context.Background()
+			c         client.Client
+			collector *Collector
+			recorder  *record.FakeRecorder
+		)
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			utilruntime.Must(operatorv1alpha1.AddToScheme(scheme))
+			utilruntime.Must(olmv1alpha1.AddToScheme(scheme))
+			c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				newSubscription("ibm-iam-operator", "ibm-iam-operator.v3.20.0", old, "team-a.iam"),
+				newSubscription("ibm-licensing-operator", "ibm-licensing-operator.v1.16.0", old, "team-b.licensing"),
+				&olmv1alpha1.ClusterServiceVersion{ObjectMeta: metav1.ObjectMeta{Name: "ibm-iam-operator.v3.20.0", Namespace: "ibm-common-services"}},
+				&olmv1alpha1.ClusterServiceVersion{ObjectMeta: metav1.ObjectMeta{Name: "ibm-licensing-operator.v1.16.0", Namespace: "ibm-common-services"}},
+				newRequest("team-a", "iam"),
+				operatorv1alpha1.NewOperatorConfig("", operatorv1alpha1.OperatorSettings{}),
+			).Build()
+			recorder = record.NewFakeRecorder(10)
+			collector = NewCollector(&deploy.ODLMOperator{Client: c, Reader: c, Scheme: scheme, Recorder: recorder})
+		})
+
+		It("Should only report the orphaned Subscriptions in the DryRun mode", func() {
+			Expect(collector.Collect(ctx, operatorv1alpha1.GarbageCollectionDryRun, now)).Should(Succeed())
+
+			Expect(c.Get(ctx, types.NamespacedName{Namespace: "ibm-common-services", Name: "ibm-licensing-operator"}, &olmv1alpha1.Subscription{})).Should(Succeed())
+			config := &operatorv1alpha1.OperatorConfig{}
+			Expect(c.Get(ctx, types.NamespacedName{Name: operatorv1alpha1.OperatorConfigName}, config)).Should(Succeed())
+			Expect(config.Status.GarbageCollection).ShouldNot(BeNil())
+			Expect(config.Status.GarbageCollection.Mode).Should(Equal(operatorv1alpha1.GarbageCollectionDryRun))
+			Expect(config.Status.GarbageCollection.OrphanCount).Should(Equal(1))
+			Expect(config.Status.GarbageCollection.Orphans).Should(Equal([]operatorv1alpha1.OrphanedSubscription{{
+				Name:                  "ibm-licensing-operator",
+				Namespace:             "ibm-common-services",
+				ClusterServiceVersion: "ibm-licensing-operator.v1.16.0",
+				Requests:              []string{"team-b/licensing"},
+			}}))
+			Expect(recorder.Events).Should(BeEmpty())
+		})
+
+		It("Should delete the orphaned Subscriptions and their ClusterServiceVersions in the Delete mode", func() {
+			Expect(collector.Collect(ctx, operatorv1alpha1.GarbageCollectionDelete, now)).Should(Succeed())
+
+			err := c.Get(ctx, types.NamespacedName{Namespace: "ibm-common-services", Name: "ibm-licensing-operator"}, &olmv1alpha1.Subscription{})
+			Expect(apierrors.IsNotFound(err)).Should(BeTrue())
+			err = c.Get(ctx, types.NamespacedName{Namespace: "ibm-common-services", Name: "ibm-licensing-operator.v1.16.0"}, &olmv1alpha1.ClusterServiceVersion{})
+			Expect(apierrors.IsNotFound(err)).Should(BeTrue())
+			Expect(c.Get(ctx, types.NamespacedName{Namespace: "ibm-common-services", Name: "ibm-iam-operator"}, &olmv1alpha1.Subscription{})).Should(Succeed())
+			Expect(c.Get(ctx, types.NamespacedName{Namespace: "ibm-common-services", Name: "ibm-iam-operator.v3.20.0"}, &olmv1alpha1.ClusterServiceVersion{})).Should(Succeed())
+
+			config := &operatorv1alpha1.OperatorConfig{}
+			Expect(c.Get(ctx, types.NamespacedName{Name: operatorv1alpha1.OperatorConfigName}, config)).Should(Succeed())
+			Expect(config.Status.GarbageCollection.Orphans).Should(HaveLen(1))
+			Expect(config.Status.GarbageCollection.Orphans[0].Deleted).Should(BeTrue())
+			Expect(recorder.Events).Should(Receive(ContainSubstring("Deleted the orphaned Subscription ibm-common-services/ibm-licensing-operator")))
+		})
+	})
+})
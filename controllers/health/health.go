@@ -0,0 +1,124 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package health evaluates the health checks of the services, written as CEL
+// expressions over the custom resources of a service and its related objects.
+package health
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"github.com/pkg/errors"
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// CustomResourcesVariable is the list of the custom resources of the service in the expression.
+	CustomResourcesVariable = "crs"
+	// ObjectsVariable is the map of the related objects of the service, by their name, in the expression.
+	ObjectsVariable = "objects"
+)
+
+var (
+	env     *cel.Env
+	envErr  error
+	envOnce sync.Once
+
+	programs   = make(map[string]cel.Program)
+	programsMu sync.Mutex
+)
+
+// Check is a compiled health check expression
+type Check struct {
+	expression string
+	program    cel.Program
+}
+
+func newEnv() (*cel.Env, error) {
+	envOnce.Do(func() {
+		env, envErr = cel.NewEnv(cel.Declarations(
+			decls.NewVar(CustomResourcesVariable, decls.NewListType(decls.Dyn)),
+			decls.NewVar(ObjectsVariable, decls.NewMapType(decls.String, decls.NewListType(decls.Dyn))),
+		))
+	})
+	return env, envErr
+}
+
+// Compile compiles the health check expression, it must return a bool.
+// The programs are cached by expression, the same expression is only compiled once.
+func Compile(expression string) (*Check, error) {
+	programsMu.Lock()
+	defer programsMu.Unlock()
+	if program, ok := programs[expression]; ok {
+		return &Check{expression: expression, program: program}, nil
+	}
+
+	e, err := newEnv()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the CEL environment")
+	}
+	ast, issues := e.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, errors.Wrapf(issues.Err(), "failed to compile the health check %q", expression)
+	}
+	if t := ast.ResultType(); t.GetPrimitive() != exprpb.Type_BOOL && t.GetDyn() == nil {
+		return nil, fmt.Errorf("the health check %q returns %v, not a bool", expression, t)
+	}
+	program, err := e.Program(ast)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the program of the health check %q", expression)
+	}
+	programs[expression] = program
+	return &Check{expression: expression, program: program}, nil
+}
+
+// Healthy evaluates the health check with the custom resources of the service and its related objects by name.
+// An error is returned when the expression can't be evaluated, for example when it reads a missing field.
+func (c *Check) Healthy(crs []unstructured.Unstructured, objects map[string][]unstructured.Unstructured) (bool, error) {
+	vars := map[string]interface{}{
+		CustomResourcesVariable: toList(crs),
+		ObjectsVariable:         toMap(objects),
+	}
+	val, _, err := c.program.Eval(vars)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to evaluate the health check %q", c.expression)
+	}
+	healthy, ok := val.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("the health check %q returns %v, not a bool", c.expression, val.Type())
+	}
+	return healthy, nil
+}
+
+func toList(objs []unstructured.Unstructured) []interface{} {
+	list := make([]interface{}, 0, len(objs))
+	for _, obj := range objs {
+		list = append(list, obj.Object)
+	}
+	return list
+}
+
+func toMap(objects map[string][]unstructured.Unstructured) map[string]interface{} {
+	m := make(map[string]interface{}, len(objects))
+	for name, objs := range objects {
+		m[name] = toList(objs)
+	}
+	return m
+}
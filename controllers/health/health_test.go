@@ -0,0 +1,73 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package health
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Health check", func() {
+
+	newObject := func(kind string, spec, status map[string]interface{}) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       kind,
+			"metadata":   map[string]interface{}{"name": "example"},
+			"spec":       spec,
+			"status":     status,
+		}}
+	}
+
+	crs := []unstructured.Unstructured{
+		newObject("EtcdCluster", map[string]interface{}{"size": int64(3)}, map[string]interface{}{"phase": "Running"}),
+	}
+
+	It("Should evaluate the expression over the custom resources and the related objects", func() {
+		check, err := Compile(`crs.all(cr, cr.status.phase == "Running") && objects.deployments.all(d, d.status.readyReplicas == d.spec.replicas)`)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		ready := newObject("Deployment", map[string]interface{}{"replicas": int64(2)}, map[string]interface{}{"readyReplicas": int64(2)})
+		healthy, err := check.Healthy(crs, map[string][]unstructured.Unstructured{"deployments": {ready}})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(healthy).Should(BeTrue())
+
+		notReady := newObject("Deployment", map[string]interface{}{"replicas": int64(2)}, map[string]interface{}{"readyReplicas": int64(1)})
+		healthy, err = check.Healthy(crs, map[string][]unstructured.Unstructured{"deployments": {ready, notReady}})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(healthy).Should(BeFalse())
+	})
+
+	It("Should fail to evaluate the expression reading a missing field", func() {
+		check, err := Compile(`objects.statefulsets.all(s, s.status.readyReplicas == 3)`)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = check.Healthy(crs, map[string][]unstructured.Unstructured{
+			"statefulsets": {newObject("StatefulSet", map[string]interface{}{}, map[string]interface{}{})},
+		})
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("Should reject the invalid expressions", func() {
+		_, err := Compile(`crs.all(cr, `)
+		Expect(err).Should(HaveOccurred())
+
+		_, err = Compile(`size(crs)`)
+		Expect(err).Should(MatchError(ContainSubstring("not a bool")))
+	})
+})
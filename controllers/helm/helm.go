@@ -0,0 +1,228 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package helm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+)
+
+const (
+	// ValuesKey is the key of the values in the values Secret
+	ValuesKey = "values.yaml"
+
+	// StatusDeployed is the Helm status of a release deployed successfully
+	StatusDeployed = "deployed"
+	// StatusFailed is the Helm status of a release failed to deploy
+	StatusFailed = "failed"
+
+	valuesVolume = "values"
+	valuesDir    = "/values"
+	homeVolume   = "helm-home"
+	homeDir      = "/tmp/helm"
+
+	// uninstallTTLSeconds is how long the uninstall Job is kept after it finishes
+	uninstallTTLSeconds = int32(600)
+)
+
+var gzipMagic = []byte{0x1f, 0x8b, 0x08}
+
+// ChartArgs returns the chart reference and the flags Helm needs to locate the chart
+func ChartArgs(chart *operatorv1alpha1.HelmChart) []string {
+	var args []string
+	if strings.HasPrefix(chart.Repository, "oci://") {
+		args = append(args, strings.TrimSuffix(chart.Repository, "/")+"/"+chart.Name)
+	} else {
+		args = append(args, chart.Name, "--repo", chart.Repository)
+	}
+	if chart.Version != "" {
+		args = append(args, "--version", chart.Version)
+	}
+	return args
+}
+
+// Hash returns a short hash of the chart and the values, it is used to detect a release to be upgraded
+func Hash(chart *operatorv1alpha1.HelmChart, values []byte) string {
+	h := sha256.New()
+	h.Write([]byte(strings.Join(ChartArgs(chart), " ")))
+	h.Write([]byte{0})
+	h.Write(values)
+	return hex.EncodeToString(h.Sum(nil)[:7])
+}
+
+// InstallJob builds the Job running "helm upgrade --install" for the release with the values in the Secret
+func InstallJob(name, namespace, release, serviceAccount, image, valuesSecret string, chart *operatorv1alpha1.HelmChart) *batchv1.Job {
+	args := append([]string{"upgrade", "--install", release}, ChartArgs(chart)...)
+	args = append(args, "--namespace", namespace, "--values", valuesDir+"/"+ValuesKey)
+	job := newJob(name, namespace, release, serviceAccount, image, args)
+	job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name: valuesVolume,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: valuesSecret},
+		},
+	})
+	job.Spec.Template.Spec.Containers[0].VolumeMounts = append(job.Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      valuesVolume,
+		MountPath: valuesDir,
+		ReadOnly:  true,
+	})
+	return job
+}
+
+// UninstallJob builds the Job running "helm uninstall" for the release
+func UninstallJob(name, namespace, release, serviceAccount, image string) *batchv1.Job {
+	job := newJob(name, namespace, release, serviceAccount, image, []string{"uninstall", release, "--namespace", namespace})
+	ttl := uninstallTTLSeconds
+	job.Spec.TTLSecondsAfterFinished = &ttl
+	return job
+}
+
+func newJob(name, namespace, release, serviceAccount, image string, args []string) *batchv1.Job {
+	backoffLimit := int32(3)
+	activeDeadlineSeconds := int64(1800)
+	env := []corev1.EnvVar{
+		{Name: "HELM_CACHE_HOME", Value: homeDir + "/cache"},
+		{Name: "HELM_CONFIG_HOME", Value: homeDir + "/config"},
+		{Name: "HELM_DATA_HOME", Value: homeDir + "/data"},
+	}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				constant.OpreqLabel:       "true",
+				constant.HelmReleaseLabel: release,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          &backoffLimit,
+			ActiveDeadlineSeconds: &activeDeadlineSeconds,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: serviceAccount,
+					Volumes: []corev1.Volume{
+						{
+							Name:         homeVolume,
+							VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:         "helm",
+							Image:        image,
+							Command:      []string{"helm"},
+							Args:         args,
+							Env:          env,
+							VolumeMounts: []corev1.VolumeMount{{Name: homeVolume, MountPath: homeDir}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// release is the part of the Helm release record ODLM reads
+type release struct {
+	Info struct {
+		Status      string `json:"status"`
+		Description string `json:"description"`
+	} `json:"info"`
+	Chart struct {
+		Metadata struct {
+			Version string `json:"version"`
+		} `json:"metadata"`
+	} `json:"chart"`
+}
+
+// LatestRelease returns the status of the latest revision among the Helm storage Secrets of a release.
+// It returns nil if there is no revision.
+func LatestRelease(name, namespace string, secrets []corev1.Secret) (*operatorv1alpha1.HelmReleaseStatus, error) {
+	var latest *corev1.Secret
+	latestRevision := 0
+	for i := range secrets {
+		revision, err := strconv.Atoi(secrets[i].Labels["version"])
+		if err != nil {
+			continue
+		}
+		if revision > latestRevision {
+			latest = &secrets[i]
+			latestRevision = revision
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+
+	status := &operatorv1alpha1.HelmReleaseStatus{
+		Name:      name,
+		Namespace: namespace,
+		Revision:  latestRevision,
+		Status:    latest.Labels["status"],
+	}
+	if data, ok := latest.Data["release"]; ok {
+		rls, err := decodeRelease(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode the Helm release %s/%s revision %d", namespace, name, latestRevision)
+		}
+		if rls.Info.Status != "" {
+			status.Status = rls.Info.Status
+		}
+		status.Description = rls.Info.Description
+		status.ChartVersion = rls.Chart.Metadata.Version
+	}
+	return status, nil
+}
+
+// decodeRelease decodes the release record Helm stores as base64 encoded, gzipped JSON
+func decodeRelease(data []byte) (*release, error) {
+	raw, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(raw, gzipMagic) {
+		r, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		if raw, err = ioutil.ReadAll(r); err != nil {
+			return nil, err
+		}
+	}
+	rls := &release{}
+	if err := json.Unmarshal(raw, rls); err != nil {
+		return nil, err
+	}
+	return rls, nil
+}
@@ -0,0 +1,118 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package helm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+)
+
+func storageSecret(revision, status, record string) corev1.Secret {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(record))
+	Expect(err).NotTo(HaveOccurred())
+	Expect(w.Close()).Should(Succeed())
+	return corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "sh.helm.release.v1.etcd.v" + revision,
+			Labels: map[string]string{"owner": "helm", "name": "etcd", "version": revision, "status": status},
+		},
+		Data: map[string][]byte{"release": []byte(base64.StdEncoding.EncodeToString(buf.Bytes()))},
+	}
+}
+
+var _ = Describe("Helm", func() {
+
+	Context("Locate the chart", func() {
+		It("Should use the repo flag for a chart repository", func() {
+			chart := &operatorv1alpha1.HelmChart{Repository: "https://charts.example.com", Name: "etcd", Version: "0.9.4"}
+			Expect(ChartArgs(chart)).Should(Equal([]string{"etcd", "--repo", "https://charts.example.com", "--version", "0.9.4"}))
+		})
+
+		It("Should use the chart reference for an OCI registry", func() {
+			chart := &operatorv1alpha1.HelmChart{Repository: "oci://registry.example.com/charts/", Name: "etcd"}
+			Expect(ChartArgs(chart)).Should(Equal([]string{"oci://registry.example.com/charts/etcd"}))
+		})
+	})
+
+	Context("Hash the chart and values", func() {
+		It("Should change the hash when the version or the values change", func() {
+			chart := &operatorv1alpha1.HelmChart{Repository: "https://charts.example.com", Name: "etcd", Version: "0.9.4"}
+			before := Hash(chart, []byte(`{"replicaCount":1}`))
+			Expect(Hash(chart, []byte(`{"replicaCount":1}`))).Should(Equal(before))
+			Expect(Hash(chart, []byte(`{"replicaCount":2}`))).ShouldNot(Equal(before))
+			chart.Version = "0.9.5"
+			Expect(Hash(chart, []byte(`{"replicaCount":1}`))).ShouldNot(Equal(before))
+		})
+	})
+
+	Context("Build the Jobs", func() {
+		It("Should mount the values Secret in the install Job", func() {
+			chart := &operatorv1alpha1.HelmChart{Repository: "https://charts.example.com", Name: "etcd"}
+			job := InstallJob("etcd-helm-abc", "etcd-ns", "etcd", "etcd-installer", "helm:latest", "etcd-helm-values", chart)
+			Expect(job.Labels).Should(HaveKeyWithValue(constant.HelmReleaseLabel, "etcd"))
+			container := job.Spec.Template.Spec.Containers[0]
+			Expect(container.Args).Should(Equal([]string{"upgrade", "--install", "etcd", "etcd", "--repo", "https://charts.example.com", "--namespace", "etcd-ns", "--values", "/values/values.yaml"}))
+			volumes := job.Spec.Template.Spec.Volumes
+			Expect(volumes[len(volumes)-1].Secret.SecretName).Should(Equal("etcd-helm-values"))
+		})
+
+		It("Should keep the uninstall Job for a while", func() {
+			job := UninstallJob("etcd-helm-uninstall", "etcd-ns", "etcd", "etcd-installer", "helm:latest")
+			Expect(job.Spec.Template.Spec.Containers[0].Args).Should(Equal([]string{"uninstall", "etcd", "--namespace", "etcd-ns"}))
+			Expect(job.Spec.TTLSecondsAfterFinished).ShouldNot(BeNil())
+		})
+	})
+
+	Context("Read the release status", func() {
+		It("Should return the latest revision", func() {
+			secrets := []corev1.Secret{
+				storageSecret("1", "superseded", `{"info":{"status":"superseded"},"chart":{"metadata":{"version":"0.9.3"}}}`),
+				storageSecret("2", "deployed", `{"info":{"status":"deployed","description":"Upgrade complete"},"chart":{"metadata":{"version":"0.9.4"}}}`),
+			}
+			release, err := LatestRelease("etcd", "etcd-ns", secrets)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(release.Revision).Should(Equal(2))
+			Expect(release.Status).Should(Equal(StatusDeployed))
+			Expect(release.Description).Should(Equal("Upgrade complete"))
+			Expect(release.ChartVersion).Should(Equal("0.9.4"))
+		})
+
+		It("Should return nil when there is no revision", func() {
+			release, err := LatestRelease("etcd", "etcd-ns", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(release).Should(BeNil())
+		})
+
+		It("Should fail when the release record is corrupted", func() {
+			secret := storageSecret("1", "deployed", "")
+			secret.Data["release"] = []byte("not base64!")
+			_, err := LatestRelease("etcd", "etcd-ns", []corev1.Secret{secret})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
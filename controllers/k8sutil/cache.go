@@ -34,16 +34,20 @@ import (
 	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/client-go/rest"
 	toolscache "k8s.io/client-go/tools/cache"
-	"k8s.io/klog"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
 	filteredcache "github.com/IBM/controller-filtered-cache/filteredcache"
+
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
 )
 
-// NewODLMCache implements a customized cache with a for ODLM
-func NewODLMCache(isolatedModeEnable bool, namespaces []string, gvkLabelMap map[schema.GroupVersionKind]filteredcache.Selector) cache.NewCacheFunc {
+var log = logging.Logger("cache")
+
+// NewODLMCache implements a customized cache with a for ODLM.
+// The namespaces labeled with the namespaceSelector are watched in addition to the namespaces, when it is not nil.
+func NewODLMCache(isolatedModeEnable bool, namespaces []string, namespaceSelector labels.Selector, gvkLabelMap map[schema.GroupVersionKind]filteredcache.Selector) cache.NewCacheFunc {
 	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
 
 		// Get the frequency that informers are resynced
@@ -59,6 +63,12 @@ func NewODLMCache(isolatedModeEnable bool, namespaces []string, gvkLabelMap map[
 				{Group: "operator.ibm.com", Kind: "OperandRegistry", Version: "v1alpha1"},
 				{Group: "operator.ibm.com", Kind: "OperandConfig", Version: "v1alpha1"},
 				{Group: "operator.ibm.com", Kind: "OperandBindInfo", Version: "v1alpha1"},
+				{Group: "operator.ibm.com", Kind: "OperandSnapshot", Version: "v1alpha1"},
+				{Group: "operator.ibm.com", Kind: "OperandFleetStatus", Version: "v1alpha1"},
+				{Group: "operator.ibm.com", Kind: "OperatorConfig", Version: "v1alpha1"},
+				{Group: "operator.ibm.com", Kind: "ClusterOperandPolicy", Version: "v1alpha1"},
+				{Group: "operator.ibm.com", Kind: "OperandBootstrap", Version: "v1alpha1"},
+				{Group: "operator.ibm.com", Kind: "OperandRequestSet", Version: "v1alpha1"},
 			}
 			clusterGVKList = append(clusterGVKList, GVKList...)
 		}
@@ -72,6 +82,8 @@ func NewODLMCache(isolatedModeEnable bool, namespaces []string, gvkLabelMap map[
 		var NewCache cache.NewCacheFunc
 		if len(namespaces) == 1 && namespaces[0] == "" {
 			NewCache = filteredcache.NewFilteredCacheBuilder(gvkLabelMap)
+		} else if namespaceSelector != nil {
+			NewCache = DynamicNamespacedCacheBuilder(filteredcache.NewFilteredCacheBuilder(gvkLabelMap), namespaces, namespaceSelector)
 		} else {
 			NewCache = filteredcache.MultiNamespacedFilteredCacheBuilder(gvkLabelMap, namespaces)
 		}
@@ -172,7 +184,7 @@ func (c ODLMCache) getFromStore(informer toolscache.SharedIndexInformer, key cli
 
 	item, exists, err := informer.GetStore().GetByKey(keyString)
 	if err != nil {
-		klog.Info("Failed to get item from cache", "error", err)
+		log.Error(err, "Failed to get item from cache", "key", keyString)
 		return err
 	}
 	if !exists {
@@ -220,7 +232,7 @@ func (c ODLMCache) getFromClient(ctx context.Context, key client.ObjectKey, obj
 	if apierrors.IsNotFound(err) {
 		return err
 	} else if err != nil {
-		klog.Info("Failed to retrieve resource list", "error", err)
+		logging.FromContext(ctx).Error(err, "Failed to retrieve resource", "resource", resource, "key", key.String())
 		return err
 	}
 
@@ -342,7 +354,7 @@ func (c ODLMCache) GetInformerForKind(ctx context.Context, gvk schema.GroupVersi
 // Start runs all the informers known to this cache until the given channel is closed.
 // It blocks.
 func (c ODLMCache) Start(ctx context.Context) error {
-	klog.Info("Start filtered cache")
+	log.Info("Start filtered cache")
 	for _, informer := range c.informerMap {
 		informer := informer
 		go informer.Run(ctx.Done())
@@ -431,10 +443,16 @@ func indexByField(indexer cache.Informer, field string, extractor client.Indexer
 // kindToResource converts kind to resource
 func kindToResource(kind string) string {
 	kindToResourceMap := map[string]string{
-		"OperandRequest":  "operandrequests",
-		"OperandRegistry": "operandregistries",
-		"OperandConfig":   "operandconfigs",
-		"OperandBindInfo": "operandbindinfos",
+		"OperandRequest":       "operandrequests",
+		"OperandRegistry":      "operandregistries",
+		"OperandConfig":        "operandconfigs",
+		"OperandBindInfo":      "operandbindinfos",
+		"OperandSnapshot":      "operandsnapshots",
+		"OperandFleetStatus":   "operandfleetstatuses",
+		"OperatorConfig":       "operatorconfigs",
+		"ClusterOperandPolicy": "clusteroperandpolicies",
+		"OperandBootstrap":     "operandbootstraps",
+		"OperandRequestSet":    "operandrequestsets",
 	}
 	return kindToResourceMap[kind]
 }
@@ -0,0 +1,447 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package k8sutil
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// DynamicNamespacedCacheBuilder returns a cache of the namespaces and of the namespaces labeled with the selector.
+// Each namespace has a cache of its own built by newCache, the caches of the labeled namespaces are started
+// when they gain the labels and stopped when they lose them, so the tenant namespaces are watched as they are
+// onboarded without restarting ODLM. The informers and the indexes of the cache are added to the new namespaces.
+func DynamicNamespacedCacheBuilder(newCache cache.NewCacheFunc, namespaces []string, selector labels.Selector) cache.NewCacheFunc {
+	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		c := newDynamicNamespacedCache(newCache, config, opts, selector)
+		for _, namespace := range namespaces {
+			c.static[namespace] = true
+			if err := c.addNamespace(namespace); err != nil {
+				return nil, err
+			}
+		}
+		return c, nil
+	}
+}
+
+// NamespaceSelector returns the label selector of the watched namespaces, nil when it is empty
+func NamespaceSelector(selector string) (labels.Selector, error) {
+	if selector == "" {
+		return nil, nil
+	}
+	return labels.Parse(selector)
+}
+
+func newDynamicNamespacedCache(newCache cache.NewCacheFunc, config *rest.Config, opts cache.Options, selector labels.Selector) *dynamicNamespacedCache {
+	return &dynamicNamespacedCache{
+		newCache:  newCache,
+		config:    config,
+		opts:      opts,
+		selector:  selector,
+		static:    map[string]bool{},
+		caches:    map[string]*namespaceCache{},
+		informers: map[schema.GroupVersionKind]*dynamicInformer{},
+		synced:    make(chan struct{}),
+	}
+}
+
+// dynamicNamespacedCache is a cache of a set of namespaces changing at runtime
+type dynamicNamespacedCache struct {
+	newCache cache.NewCacheFunc
+	config   *rest.Config
+	opts     cache.Options
+	selector labels.Selector
+	// static are the namespaces always watched
+	static map[string]bool
+
+	mu sync.RWMutex
+	// ctx is the context of the started cache, nil until it starts
+	ctx       context.Context
+	caches    map[string]*namespaceCache
+	informers map[schema.GroupVersionKind]*dynamicInformer
+	indexes   []fieldIndex
+	// synced is closed once the labeled namespaces are added
+	synced chan struct{}
+}
+
+// namespaceCache is the cache of a namespace
+type namespaceCache struct {
+	cache  cache.Cache
+	cancel context.CancelFunc
+}
+
+// fieldIndex is an index added to the cache, it is added to the caches of the new namespaces
+type fieldIndex struct {
+	obj          client.Object
+	field        string
+	extractValue client.IndexerFunc
+}
+
+// addNamespace creates the cache of the namespace with the informers and the indexes of the cache,
+// and starts it when the cache is started
+func (c *dynamicNamespacedCache) addNamespace(namespace string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.caches[namespace]; ok {
+		return nil
+	}
+	opts := c.opts
+	opts.Namespace = namespace
+	nsCache, err := c.newCache(c.config, opts)
+	if err != nil {
+		return fmt.Errorf("failed to create the cache of the namespace %s: %v", namespace, err)
+	}
+	ctx := context.Background()
+	for _, index := range c.indexes {
+		if err := nsCache.IndexField(ctx, index.obj, index.field, index.extractValue); err != nil {
+			return fmt.Errorf("failed to index the field %s in the namespace %s: %v", index.field, namespace, err)
+		}
+	}
+	for _, informer := range c.informers {
+		nsInformer, err := informer.get(ctx, nsCache)
+		if err != nil {
+			return fmt.Errorf("failed to get the informer of %s in the namespace %s: %v", informer.gvk.Kind, namespace, err)
+		}
+		if err := informer.add(namespace, nsInformer); err != nil {
+			return fmt.Errorf("failed to add the informer of %s in the namespace %s: %v", informer.gvk.Kind, namespace, err)
+		}
+	}
+	entry := &namespaceCache{cache: nsCache}
+	c.caches[namespace] = entry
+	if c.ctx != nil {
+		c.start(namespace, entry)
+	}
+	log.Info("Watching the namespace", "namespace", namespace)
+	return nil
+}
+
+// removeNamespace stops the cache of the namespace, the namespaces always watched are kept
+func (c *dynamicNamespacedCache) removeNamespace(namespace string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.caches[namespace]
+	if !ok || c.static[namespace] {
+		return
+	}
+	if entry.cancel != nil {
+		entry.cancel()
+	}
+	delete(c.caches, namespace)
+	for _, informer := range c.informers {
+		informer.remove(namespace)
+	}
+	log.Info("Stopped watching the namespace", "namespace", namespace)
+}
+
+// start runs the cache of the namespace until the cache or the namespace is stopped
+func (c *dynamicNamespacedCache) start(namespace string, entry *namespaceCache) {
+	ctx, cancel := context.WithCancel(c.ctx)
+	entry.cancel = cancel
+	go func() {
+		if err := entry.cache.Start(ctx); err != nil {
+			log.Error(err, "failed to start the cache of the namespace", "namespace", namespace)
+		}
+	}()
+}
+
+// watchNamespaces adds and removes the namespaces as they gain and lose the labels of the selector
+func (c *dynamicNamespacedCache) watchNamespaces(ctx context.Context) error {
+	clientset, err := kubernetes.NewForConfig(c.config)
+	if err != nil {
+		return err
+	}
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0, informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+		options.LabelSelector = c.selector.String()
+	}))
+	informer := factory.Core().V1().Namespaces().Informer()
+	informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if ns, ok := obj.(*corev1.Namespace); ok {
+				if err := c.addNamespace(ns.Name); err != nil {
+					log.Error(err, "failed to watch the labeled namespace", "namespace", ns.Name)
+				}
+			}
+		},
+		// The watch of a label selector deletes the namespaces losing the labels
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if ns, ok := obj.(*corev1.Namespace); ok {
+				c.removeNamespace(ns.Name)
+			}
+		},
+	})
+	go informer.Run(ctx.Done())
+	if !toolscache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return fmt.Errorf("failed to sync the namespaces labeled with %s", c.selector.String())
+	}
+	return nil
+}
+
+// namespaceCaches returns the caches of the namespaces
+func (c *dynamicNamespacedCache) namespaceCaches() map[string]cache.Cache {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	caches := make(map[string]cache.Cache, len(c.caches))
+	for namespace, entry := range c.caches {
+		caches[namespace] = entry.cache
+	}
+	return caches
+}
+
+// namespaceCache returns the cache of the namespace
+func (c *dynamicNamespacedCache) namespaceCache(namespace string) (cache.Cache, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.caches[namespace]
+	if !ok {
+		return nil, fmt.Errorf("unable to get: %v because of unknown namespace for the cache", namespace)
+	}
+	return entry.cache, nil
+}
+
+// Get gets the object from the cache of its namespace
+func (c *dynamicNamespacedCache) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	nsCache, err := c.namespaceCache(key.Namespace)
+	if err != nil {
+		return err
+	}
+	return nsCache.Get(ctx, key, obj)
+}
+
+// List lists the objects from the cache of the namespace, or from the caches of all the namespaces
+func (c *dynamicNamespacedCache) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	if listOpts.Namespace != corev1.NamespaceAll {
+		nsCache, err := c.namespaceCache(listOpts.Namespace)
+		if err != nil {
+			return err
+		}
+		return nsCache.List(ctx, list, opts...)
+	}
+
+	var allItems []runtime.Object
+	for _, nsCache := range c.namespaceCaches() {
+		listObj := list.DeepCopyObject().(client.ObjectList)
+		if err := nsCache.List(ctx, listObj, opts...); err != nil {
+			return err
+		}
+		items, err := apimeta.ExtractList(listObj)
+		if err != nil {
+			return err
+		}
+		allItems = append(allItems, items...)
+	}
+	return apimeta.SetList(list, allItems)
+}
+
+// GetInformer returns the informer of the object in all the namespaces
+func (c *dynamicNamespacedCache) GetInformer(ctx context.Context, obj client.Object) (cache.Informer, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.opts.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	return c.getInformer(ctx, &dynamicInformer{gvk: gvk, obj: obj})
+}
+
+// GetInformerForKind returns the informer of the kind in all the namespaces
+func (c *dynamicNamespacedCache) GetInformerForKind(ctx context.Context, gvk schema.GroupVersionKind) (cache.Informer, error) {
+	return c.getInformer(ctx, &dynamicInformer{gvk: gvk})
+}
+
+func (c *dynamicNamespacedCache) getInformer(ctx context.Context, informer *dynamicInformer) (cache.Informer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if existing, ok := c.informers[informer.gvk]; ok {
+		return existing, nil
+	}
+	informer.informers = map[string]cache.Informer{}
+	for namespace, entry := range c.caches {
+		nsInformer, err := informer.get(ctx, entry.cache)
+		if err != nil {
+			return nil, err
+		}
+		informer.informers[namespace] = nsInformer
+	}
+	c.informers[informer.gvk] = informer
+	return informer, nil
+}
+
+// Start starts the caches of the namespaces, and watches the labeled namespaces until the context is done
+func (c *dynamicNamespacedCache) Start(ctx context.Context) error {
+	c.mu.Lock()
+	c.ctx = ctx
+	for namespace, entry := range c.caches {
+		c.start(namespace, entry)
+	}
+	c.mu.Unlock()
+
+	err := c.watchNamespaces(ctx)
+	close(c.synced)
+	if err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return nil
+}
+
+// WaitForCacheSync waits for the labeled namespaces to be added and for the caches of all the namespaces to sync
+func (c *dynamicNamespacedCache) WaitForCacheSync(ctx context.Context) bool {
+	select {
+	case <-c.synced:
+	case <-ctx.Done():
+		return false
+	}
+	synced := true
+	for _, nsCache := range c.namespaceCaches() {
+		if !nsCache.WaitForCacheSync(ctx) {
+			synced = false
+		}
+	}
+	return synced
+}
+
+// IndexField adds the index to the caches of the namespaces, and of the namespaces added later
+func (c *dynamicNamespacedCache) IndexField(ctx context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, entry := range c.caches {
+		if err := entry.cache.IndexField(ctx, obj, field, extractValue); err != nil {
+			return err
+		}
+	}
+	c.indexes = append(c.indexes, fieldIndex{obj: obj, field: field, extractValue: extractValue})
+	return nil
+}
+
+// eventHandler is an event handler added to an informer of the cache
+type eventHandler struct {
+	handler toolscache.ResourceEventHandler
+	// resyncPeriod is the resync period of the handler, nil for the one of the informer
+	resyncPeriod *time.Duration
+}
+
+// dynamicInformer is the informer of a kind in the namespaces of the cache, the event handlers and the indexers
+// added to it are added to the informers of the namespaces added later
+type dynamicInformer struct {
+	gvk schema.GroupVersionKind
+	// obj is the object the informer is got for, nil when it is got for the kind
+	obj client.Object
+
+	mu        sync.Mutex
+	informers map[string]cache.Informer
+	handlers  []eventHandler
+	indexers  []toolscache.Indexers
+}
+
+// get returns the informer of the kind in the cache of a namespace
+func (i *dynamicInformer) get(ctx context.Context, nsCache cache.Cache) (cache.Informer, error) {
+	if i.obj != nil {
+		return nsCache.GetInformer(ctx, i.obj)
+	}
+	return nsCache.GetInformerForKind(ctx, i.gvk)
+}
+
+// add adds the informer of a new namespace with the event handlers and the indexers
+func (i *dynamicInformer) add(namespace string, informer cache.Informer) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, indexers := range i.indexers {
+		if err := informer.AddIndexers(indexers); err != nil {
+			return err
+		}
+	}
+	for _, h := range i.handlers {
+		if h.resyncPeriod != nil {
+			informer.AddEventHandlerWithResyncPeriod(h.handler, *h.resyncPeriod)
+		} else {
+			informer.AddEventHandler(h.handler)
+		}
+	}
+	i.informers[namespace] = informer
+	return nil
+}
+
+// remove removes the informer of a namespace no longer watched
+func (i *dynamicInformer) remove(namespace string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.informers, namespace)
+}
+
+// AddEventHandler adds the handler to the informers of all the namespaces
+func (i *dynamicInformer) AddEventHandler(handler toolscache.ResourceEventHandler) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.handlers = append(i.handlers, eventHandler{handler: handler})
+	for _, informer := range i.informers {
+		informer.AddEventHandler(handler)
+	}
+}
+
+// AddEventHandlerWithResyncPeriod adds the handler with a resync period to the informers of all the namespaces
+func (i *dynamicInformer) AddEventHandlerWithResyncPeriod(handler toolscache.ResourceEventHandler, resyncPeriod time.Duration) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.handlers = append(i.handlers, eventHandler{handler: handler, resyncPeriod: &resyncPeriod})
+	for _, informer := range i.informers {
+		informer.AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
+	}
+}
+
+// AddIndexers adds the indexers to the informers of all the namespaces
+func (i *dynamicInformer) AddIndexers(indexers toolscache.Indexers) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.indexers = append(i.indexers, indexers)
+	for _, informer := range i.informers {
+		if err := informer.AddIndexers(indexers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasSynced returns true when the informers of all the namespaces have synced
+func (i *dynamicInformer) HasSynced() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, informer := range i.informers {
+		if !informer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package k8sutil
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
+)
+
+var _ = Describe("Dynamic namespaced cache", func() {
+
+	var (
+		ctx        context.Context
+		nsCaches   map[string]*informertest.FakeInformers
+		dynamic    *dynamicNamespacedCache
+		configMaps int
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		nsCaches = map[string]*informertest.FakeInformers{}
+		configMaps = 0
+		newCache := func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+			nsCaches[opts.Namespace] = &informertest.FakeInformers{Scheme: opts.Scheme}
+			return nsCaches[opts.Namespace], nil
+		}
+		newDynamicCache := DynamicNamespacedCacheBuilder(newCache, []string{"ibm-common-services"}, labels.SelectorFromSet(labels.Set{"tenant": "true"}))
+		c, err := newDynamicCache(&rest.Config{}, cache.Options{Scheme: scheme.Scheme})
+		Expect(err).ShouldNot(HaveOccurred())
+		dynamic = c.(*dynamicNamespacedCache)
+
+		informer, err := dynamic.GetInformer(ctx, &corev1.ConfigMap{})
+		Expect(err).ShouldNot(HaveOccurred())
+		informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) { configMaps++ },
+		})
+	})
+
+	addConfigMap := func(namespace string) {
+		informer, err := nsCaches[namespace].FakeInformerFor(&corev1.ConfigMap{})
+		Expect(err).ShouldNot(HaveOccurred())
+		informer.Add(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: namespace}})
+	}
+
+	It("Should add the event handlers to the informers of the labeled namespaces", func() {
+		addConfigMap("ibm-common-services")
+		Expect(configMaps).Should(Equal(1))
+
+		Expect(dynamic.addNamespace("cloudpak1")).Should(Succeed())
+		addConfigMap("cloudpak1")
+		Expect(configMaps).Should(Equal(2))
+
+		informer, err := dynamic.GetInformer(ctx, &corev1.ConfigMap{})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(informer.(*dynamicInformer).informers).Should(HaveLen(2))
+		Expect(informer.(*dynamicInformer).informers["cloudpak1"]).Should(BeAssignableToTypeOf(&controllertest.FakeInformer{}))
+	})
+
+	It("Should stop watching the namespaces losing the labels", func() {
+		Expect(dynamic.addNamespace("cloudpak1")).Should(Succeed())
+		Expect(dynamic.Get(ctx, client.ObjectKey{Namespace: "cloudpak1", Name: "tenant"}, &corev1.ConfigMap{})).Should(Succeed())
+
+		dynamic.removeNamespace("cloudpak1")
+		Expect(dynamic.Get(ctx, client.ObjectKey{Namespace: "cloudpak1", Name: "tenant"}, &corev1.ConfigMap{})).ShouldNot(Succeed())
+		Expect(dynamic.informers).Should(HaveLen(1))
+		for _, informer := range dynamic.informers {
+			Expect(informer.informers).Should(HaveKey("ibm-common-services"))
+			Expect(informer.informers).ShouldNot(HaveKey("cloudpak1"))
+		}
+
+		// The namespaces always watched are kept
+		dynamic.removeNamespace("ibm-common-services")
+		Expect(dynamic.Get(ctx, client.ObjectKey{Namespace: "ibm-common-services", Name: "tenant"}, &corev1.ConfigMap{})).Should(Succeed())
+	})
+
+	It("Should list the objects of all the namespaces", func() {
+		Expect(dynamic.addNamespace("cloudpak1")).Should(Succeed())
+		Expect(dynamic.List(ctx, &corev1.ConfigMapList{})).Should(Succeed())
+		Expect(dynamic.List(ctx, &corev1.ConfigMapList{}, client.InNamespace("cloudpak1"))).Should(Succeed())
+		Expect(dynamic.List(ctx, &corev1.ConfigMapList{}, client.InNamespace("cloudpak2"))).ShouldNot(Succeed())
+	})
+
+	It("Should parse the label selector of the watched namespaces", func() {
+		selector, err := NamespaceSelector("")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(selector).Should(BeNil())
+
+		selector, err = NamespaceSelector("tenant in (gold,silver)")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(selector.Matches(labels.Set{"tenant": "gold"})).Should(BeTrue())
+
+		_, err = NamespaceSelector("tenant in gold")
+		Expect(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,124 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package ledger counts the OperandRequests referencing the operators of an OperandRegistry in a ConfigMap,
+// so that an operator is only uninstalled once the last OperandRequest releases it.
+package ledger
+
+import (
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+)
+
+// Name returns the name of the ConfigMap holding the ledger of the OperandRegistry
+func Name(registry string) string {
+	return registry + constant.ReferenceLedgerSuffix
+}
+
+// New returns an empty ledger for the OperandRegistry
+func New(registryKey types.NamespacedName) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name(registryKey.Name),
+			Namespace: registryKey.Namespace,
+			Labels: map[string]string{
+				constant.OpreqLabel: "true",
+			},
+		},
+		Data: make(map[string]string),
+	}
+}
+
+// References returns the OperandRequests referencing the operator, sorted by namespace and name
+func References(cm *corev1.ConfigMap, operator string) []types.NamespacedName {
+	var refs []types.NamespacedName
+	for _, line := range strings.Split(cm.Data[operator], "\n") {
+		parts := strings.SplitN(line, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			continue
+		}
+		refs = append(refs, types.NamespacedName{Namespace: parts[0], Name: parts[1]})
+	}
+	return refs
+}
+
+// Bind adds the OperandRequest to the references of the operator, it returns true if the ledger is changed
+func Bind(cm *corev1.ConfigMap, operator string, request types.NamespacedName) bool {
+	refs := References(cm, operator)
+	for _, ref := range refs {
+		if ref == request {
+			return false
+		}
+	}
+	setReferences(cm, operator, append(refs, request))
+	return true
+}
+
+// Unbind removes the OperandRequest from the references of the operator, it returns true if the ledger is changed
+func Unbind(cm *corev1.ConfigMap, operator string, request types.NamespacedName) bool {
+	refs := References(cm, operator)
+	kept := refs[:0]
+	for _, ref := range refs {
+		if ref != request {
+			kept = append(kept, ref)
+		}
+	}
+	if len(kept) == len(refs) {
+		return false
+	}
+	setReferences(cm, operator, kept)
+	return true
+}
+
+// Requests returns true if the OperandRequest requests the operator of the OperandRegistry,
+// directly or through an operand requiring it
+func Requests(request *operatorv1alpha1.OperandRequest, registry *operatorv1alpha1.OperandRegistry, operator string) bool {
+	registryKey := types.NamespacedName{Namespace: registry.Namespace, Name: registry.Name}
+	for _, req := range request.Spec.Requests {
+		if request.GetRegistryKey(req) != registryKey {
+			continue
+		}
+		for _, operand := range registry.WithRequiredOperands(req.Operands) {
+			if operand.Name == operator {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func setReferences(cm *corev1.ConfigMap, operator string, refs []types.NamespacedName) {
+	if len(refs) == 0 {
+		delete(cm.Data, operator)
+		return
+	}
+	lines := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		lines = append(lines, ref.String())
+	}
+	sort.Strings(lines)
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	cm.Data[operator] = strings.Join(lines, "\n")
+}
@@ -0,0 +1,75 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ledger
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Ledger", func() {
+
+	registryKey := types.NamespacedName{Name: "common-service", Namespace: "ibm-common-services"}
+	requestA := types.NamespacedName{Name: "a", Namespace: "team-a"}
+	requestB := types.NamespacedName{Name: "b", Namespace: "team-b"}
+
+	It("Should bind and unbind the OperandRequests", func() {
+		cm := New(registryKey)
+		Expect(cm.Name).Should(Equal("common-service-references"))
+		Expect(Bind(cm, "ibm-licensing-operator", requestB)).Should(BeTrue())
+		Expect(Bind(cm, "ibm-licensing-operator", requestA)).Should(BeTrue())
+		Expect(Bind(cm, "ibm-licensing-operator", requestA)).Should(BeFalse())
+		Expect(cm.Data["ibm-licensing-operator"]).Should(Equal("team-a/a\nteam-b/b"))
+		Expect(References(cm, "ibm-licensing-operator")).Should(Equal([]types.NamespacedName{requestA, requestB}))
+
+		Expect(Unbind(cm, "ibm-licensing-operator", requestA)).Should(BeTrue())
+		Expect(Unbind(cm, "ibm-licensing-operator", requestA)).Should(BeFalse())
+		Expect(References(cm, "ibm-licensing-operator")).Should(Equal([]types.NamespacedName{requestB}))
+		Expect(Unbind(cm, "ibm-licensing-operator", requestB)).Should(BeTrue())
+		Expect(cm.Data).ShouldNot(HaveKey("ibm-licensing-operator"))
+		Expect(References(cm, "ibm-licensing-operator")).Should(BeEmpty())
+	})
+
+	It("Should find the operators requested by the OperandRequest", func() {
+		registry := &operatorv1alpha1.OperandRegistry{
+			ObjectMeta: metav1.ObjectMeta{Name: registryKey.Name, Namespace: registryKey.Namespace},
+			Spec: operatorv1alpha1.OperandRegistrySpec{
+				Operators: []operatorv1alpha1.Operator{
+					{Name: "ibm-iam-operator", Requires: []string{"ibm-cert-manager-operator"}},
+					{Name: "ibm-cert-manager-operator"},
+					{Name: "ibm-licensing-operator"},
+				},
+			},
+		}
+		request := &operatorv1alpha1.OperandRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ibm-common-services"},
+			Spec: operatorv1alpha1.OperandRequestSpec{
+				Requests: []operatorv1alpha1.Request{
+					{Registry: "common-service", Operands: []operatorv1alpha1.Operand{{Name: "ibm-iam-operator"}}},
+					{Registry: "other-service", Operands: []operatorv1alpha1.Operand{{Name: "ibm-licensing-operator"}}},
+				},
+			},
+		}
+		Expect(Requests(request, registry, "ibm-iam-operator")).Should(BeTrue())
+		Expect(Requests(request, registry, "ibm-cert-manager-operator")).Should(BeTrue())
+		Expect(Requests(request, registry, "ibm-licensing-operator")).Should(BeFalse())
+	})
+})
@@ -0,0 +1,128 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package mirror replaces the CatalogSources and the image registries of the OperandRegistries with the mirrors
+// of the OperatorConfig, so the same OperandRegistries work in the disconnected clusters.
+package mirror
+
+import (
+	"strings"
+	"sync"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+// ociScheme is the scheme of the Helm chart repositories in an OCI registry
+const ociScheme = "oci://"
+
+// configured are the mirrors of the running OperatorConfig
+var configured struct {
+	sync.RWMutex
+	catalogs   []operatorv1alpha1.CatalogMirror
+	registries []operatorv1alpha1.RegistryMirror
+}
+
+// Set sets the mirrors at runtime
+func Set(catalogs []operatorv1alpha1.CatalogMirror, registries []operatorv1alpha1.RegistryMirror) {
+	configured.Lock()
+	defer configured.Unlock()
+	configured.catalogs = catalogs
+	configured.registries = registries
+}
+
+// Get returns the mirrors ODLM is running with
+func Get() ([]operatorv1alpha1.CatalogMirror, []operatorv1alpha1.RegistryMirror) {
+	configured.RLock()
+	defer configured.RUnlock()
+	return configured.catalogs, configured.registries
+}
+
+// Rewrite replaces the CatalogSource, the bundle image and the OCI chart repository of the operator
+// with the mirrors ODLM is running with
+func Rewrite(o *operatorv1alpha1.Operator) {
+	catalogs, registries := Get()
+	RewriteOperator(o, catalogs, registries)
+}
+
+// RewriteOperator replaces the CatalogSource, the bundle image and the OCI chart repository of the operator with the mirrors
+func RewriteOperator(o *operatorv1alpha1.Operator, catalogs []operatorv1alpha1.CatalogMirror, registries []operatorv1alpha1.RegistryMirror) {
+	if o.SourceName != "" || o.SourceNamespace != "" {
+		o.SourceName, o.SourceNamespace = Catalog(catalogs, o.SourceName, o.SourceNamespace)
+	}
+	if o.Manifests != nil && o.Manifests.BundleImage != "" {
+		o.Manifests.BundleImage = Image(registries, o.Manifests.BundleImage)
+	}
+	if o.Chart != nil && strings.HasPrefix(o.Chart.Repository, ociScheme) {
+		o.Chart.Repository = ociScheme + Image(registries, strings.TrimPrefix(o.Chart.Repository, ociScheme))
+	}
+}
+
+// Catalog returns the CatalogSource replacing the CatalogSource, the mirror of the CatalogSource
+// takes precedence over the mirror of its namespace
+func Catalog(catalogs []operatorv1alpha1.CatalogMirror, name, namespace string) (string, string) {
+	var found *operatorv1alpha1.CatalogMirror
+	for i, c := range catalogs {
+		if c.SourceNamespace != namespace {
+			continue
+		}
+		if c.SourceName == name {
+			found = &catalogs[i]
+			break
+		}
+		if c.SourceName == "" && found == nil {
+			found = &catalogs[i]
+		}
+	}
+	if found == nil {
+		return name, namespace
+	}
+	if found.MirrorName != "" {
+		name = found.MirrorName
+	}
+	if found.MirrorNamespace != "" {
+		namespace = found.MirrorNamespace
+	}
+	return name, namespace
+}
+
+// Image returns the image with the longest matching prefix replaced by its mirror.
+// A registry host only matches the whole host, a repository also matches its tags and digests
+func Image(registries []operatorv1alpha1.RegistryMirror, image string) string {
+	var found *operatorv1alpha1.RegistryMirror
+	for i, r := range registries {
+		if matches(r.Source, image) && (found == nil || len(r.Source) > len(found.Source)) {
+			found = &registries[i]
+		}
+	}
+	if found == nil {
+		return image
+	}
+	return strings.TrimSuffix(found.Mirror, "/") + image[len(strings.TrimSuffix(found.Source, "/")):]
+}
+
+// matches returns true if the image starts with the prefix at a boundary of its reference
+func matches(prefix, image string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix == "" || !strings.HasPrefix(image, prefix) {
+		return false
+	}
+	rest := image[len(prefix):]
+	if rest == "" || strings.HasPrefix(rest, "/") {
+		return true
+	}
+	// The tag or digest of a repository, the port of a registry host isn't a boundary
+	return strings.Contains(prefix, "/") && (strings.HasPrefix(rest, ":") || strings.HasPrefix(rest, "@"))
+}
@@ -0,0 +1,78 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package mirror
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Mirror", func() {
+
+	catalogs := []operatorv1alpha1.CatalogMirror{
+		{SourceNamespace: "openshift-marketplace", MirrorNamespace: "mirrored-catalogs"},
+		{SourceName: "opencloud-operators", SourceNamespace: "openshift-marketplace", MirrorName: "opencloud-mirror", MirrorNamespace: "mirrored-catalogs"},
+		{SourceName: "ibm-operator-catalog", SourceNamespace: "openshift-marketplace", MirrorName: "ibm-mirror"},
+	}
+	registries := []operatorv1alpha1.RegistryMirror{
+		{Source: "icr.io", Mirror: "mirror.example.com:5000"},
+		{Source: "icr.io/cpopen/", Mirror: "mirror.example.com:5000/ibm/"},
+		{Source: "quay.io/opencloudio/ibm-iam-operator-bundle", Mirror: "mirror.example.com:5000/iam-bundle"},
+	}
+
+	It("Should replace the CatalogSources", func() {
+		name, namespace := Catalog(catalogs, "opencloud-operators", "openshift-marketplace")
+		Expect(name + "/" + namespace).To(Equal("opencloud-mirror/mirrored-catalogs"))
+		name, namespace = Catalog(catalogs, "ibm-operator-catalog", "openshift-marketplace")
+		Expect(name + "/" + namespace).To(Equal("ibm-mirror/openshift-marketplace"))
+		name, namespace = Catalog(catalogs, "redhat-operators", "openshift-marketplace")
+		Expect(name + "/" + namespace).To(Equal("redhat-operators/mirrored-catalogs"))
+		name, namespace = Catalog(catalogs, "opencloud-operators", "ibm-common-services")
+		Expect(name + "/" + namespace).To(Equal("opencloud-operators/ibm-common-services"))
+	})
+
+	It("Should replace the longest matching prefix of the images", func() {
+		Expect(Image(registries, "icr.io/cpopen/ibm-iam-operator-bundle:4.0.0")).To(Equal("mirror.example.com:5000/ibm/ibm-iam-operator-bundle:4.0.0"))
+		Expect(Image(registries, "icr.io/other/bundle@sha256:abc")).To(Equal("mirror.example.com:5000/other/bundle@sha256:abc"))
+		Expect(Image(registries, "quay.io/opencloudio/ibm-iam-operator-bundle:3.23")).To(Equal("mirror.example.com:5000/iam-bundle:3.23"))
+		Expect(Image(registries, "quay.io/opencloudio/ibm-iam-operator-bundle-v2:3.23")).To(Equal("quay.io/opencloudio/ibm-iam-operator-bundle-v2:3.23"))
+		Expect(Image(registries, "icr.io:443/cpopen/bundle:1")).To(Equal("icr.io:443/cpopen/bundle:1"))
+		Expect(Image(nil, "icr.io/cpopen/bundle:1")).To(Equal("icr.io/cpopen/bundle:1"))
+	})
+
+	It("Should rewrite the operators", func() {
+		olm := &operatorv1alpha1.Operator{Name: "ibm-iam-operator", SourceName: "opencloud-operators", SourceNamespace: "openshift-marketplace"}
+		RewriteOperator(olm, catalogs, registries)
+		Expect(olm.SourceName).To(Equal("opencloud-mirror"))
+		Expect(olm.SourceNamespace).To(Equal("mirrored-catalogs"))
+
+		manifests := &operatorv1alpha1.Operator{Name: "ibm-iam-operator", Manifests: &operatorv1alpha1.ManifestsSource{BundleImage: "icr.io/cpopen/ibm-iam-operator-bundle:4.0.0"}}
+		RewriteOperator(manifests, catalogs, registries)
+		Expect(manifests.SourceName).To(BeEmpty())
+		Expect(manifests.Manifests.BundleImage).To(Equal("mirror.example.com:5000/ibm/ibm-iam-operator-bundle:4.0.0"))
+
+		helm := &operatorv1alpha1.Operator{Name: "ibm-iam-operator", Chart: &operatorv1alpha1.HelmChart{Repository: "oci://icr.io/cpopen/charts"}}
+		RewriteOperator(helm, catalogs, registries)
+		Expect(helm.Chart.Repository).To(Equal("oci://mirror.example.com:5000/ibm/charts"))
+
+		https := &operatorv1alpha1.Operator{Name: "ibm-iam-operator", Chart: &operatorv1alpha1.HelmChart{Repository: "https://icr.io/charts"}}
+		RewriteOperator(https, catalogs, registries)
+		Expect(https.Chart.Repository).To(Equal("https://icr.io/charts"))
+	})
+})
@@ -25,10 +25,9 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
-	"k8s.io/client-go/discovery"
-	"k8s.io/klog"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	nssv1 "github.com/IBM/ibm-namespace-scope-operator/api/v1"
@@ -36,7 +35,11 @@ import (
 	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
 	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/shard"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/throughput"
+	odlmutil "github.com/IBM/operand-deployment-lifecycle-manager/pkg/util/v1"
 )
 
 var (
@@ -50,7 +53,9 @@ type Reconciler struct {
 
 // ReconcileOperandRequest reads that state of the cluster for OperandRequest object and update NamespaceScope CR based on the state read
 func (r *Reconciler) ReconcileOperandRequest(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
-	exist, err := r.checkNamespaceScopeAPI()
+	ctx, log := logging.Reconcile(ctx, "namespacescope", "request", req.NamespacedName.String())
+
+	exist, err := r.checkNamespaceScopeAPI(ctx)
 	if err != nil {
 		return ctrl.Result{}, err
 	} else if !exist {
@@ -59,10 +64,10 @@ func (r *Reconciler) ReconcileOperandRequest(ctx context.Context, req ctrl.Reque
 
 	nssList, err := r.getNamespaceScopeCRList(ctx)
 	if err != nil {
-		klog.Error(err)
+		log.Error(err, "failed to get the NamespaceScope instances")
 		return ctrl.Result{}, err
 	} else if len(nssList) == 0 {
-		klog.Warning("Not found NamespaceScope instance, ignore update it.")
+		log.Info("Not found NamespaceScope instance, ignore update it.")
 		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 	}
 
@@ -74,17 +79,17 @@ func (r *Reconciler) ReconcileOperandRequest(ctx context.Context, req ctrl.Reque
 		defer func(i int, nss *nssv1.NamespaceScope) {
 			nsMemsList[i], reconcileErr = r.updateNamespaceMemberFromNamespaceScope(ctx)
 			if reconcileErr != nil {
-				klog.Error(reconcileErr)
+				log.Error(reconcileErr, "failed to get the namespace members")
 				return
 			}
 			if !util.StringSliceContentEqual(nsMemsList[i], nss.Spec.NamespaceMembers) {
 				nss.Spec.NamespaceMembers = nsMemsList[i]
 				if err := r.Patch(ctx, nss, client.MergeFrom(originalNssList[i])); err != nil {
 					reconcileErr = errors.Wrapf(err, "failed to update NamespaceScope %s/%s", nss.Namespace, nss.Name)
-					klog.Error(reconcileErr)
+					log.Error(err, "failed to update NamespaceScope", "namespacescope", nss.Namespace+"/"+nss.Name)
 					return
 				}
-				klog.V(2).Infof("Updated NamespaceScope %s/%s", nss.Namespace, nss.Name)
+				log.V(logging.LevelChange).Info("Updated NamespaceScope", "namespacescope", nss.Namespace+"/"+nss.Name)
 			}
 		}(i, nssList[i])
 	}
@@ -128,6 +133,19 @@ func (r *Reconciler) getOpreqNs(ctx context.Context) (nsMems []string, getOpreqN
 	}
 
 	for _, opreq := range opreqList.Items {
+		if opreq.Namespace == operatorNs || nsSet.Contains(opreq.Namespace) {
+			continue
+		}
+		// The namespaces opting out of ODLM are never added to the NamespaceScope
+		excluded, err := r.IsNamespaceExcluded(ctx, opreq.Namespace)
+		if err != nil {
+			getOpreqNsErr = err
+			return
+		}
+		if excluded {
+			logging.FromContext(ctx).V(logging.LevelChange).Info("Skip the excluded namespace", "namespace", opreq.Namespace)
+			continue
+		}
 		nsSet.Add(opreq.Namespace)
 	}
 
@@ -172,13 +190,13 @@ func (r *Reconciler) getOpregNs(ctx context.Context) (nsMems []string, getOpregN
 func (r *Reconciler) updateNamespaceMemberFromNamespaceScope(ctx context.Context) (nsMems []string, err error) {
 	opreqNs, err := r.getOpreqNs(ctx)
 	if err != nil {
-		klog.Error(err)
+		logging.FromContext(ctx).Error(err, "failed to get the namespaces of the OperandRequests")
 		return
 	}
 
 	opregNs, err := r.getOpregNs(ctx)
 	if err != nil {
-		klog.Error(err)
+		logging.FromContext(ctx).Error(err, "failed to get the namespaces of the OperandRegistries")
 		return
 	}
 
@@ -199,14 +217,12 @@ func (r *Reconciler) updateNamespaceMemberFromNamespaceScope(ctx context.Context
 	return
 }
 
-func (r *Reconciler) checkNamespaceScopeAPI() (bool, error) {
-	dc := discovery.NewDiscoveryClientForConfigOrDie(r.Config)
-	if exist, err := util.ResourceExists(dc, "operator.ibm.com/v1", "NamespaceScope"); err != nil {
-		err = errors.Wrap(err, "failed to check if the NamespaceScope api exist")
-		klog.Error(err)
-		return false, err
+func (r *Reconciler) checkNamespaceScopeAPI(ctx context.Context) (bool, error) {
+	if exist, err := odlmutil.ResourceExists(r.Discovery, "operator.ibm.com/v1", "NamespaceScope"); err != nil {
+		logging.FromContext(ctx).Error(err, "failed to check if the NamespaceScope api exist")
+		return false, errors.Wrap(err, "failed to check if the NamespaceScope api exist")
 	} else if !exist {
-		klog.V(2).Info("Not found NamespaceScope API, ignore update it.")
+		logging.FromContext(ctx).V(logging.LevelChange).Info("Not found NamespaceScope API, ignore update it.")
 		return false, nil
 	}
 	return true, nil
@@ -219,7 +235,8 @@ func (r *Reconciler) getNamespaceScopeCRList(ctx context.Context) ([]*nssv1.Name
 		nsScopeKey := types.NamespacedName{Name: cr, Namespace: util.GetOperatorNamespace()}
 		if err := r.Client.Get(ctx, nsScopeKey, nsScope); err != nil {
 			if apierrors.IsNotFound(err) {
-				klog.Warningf("Not found NamespaceScope CR %s, ignore update it.", nsScopeKey.String())
+				logging.FromContext(ctx).Info("Not found NamespaceScope CR, ignore update it.", "namespacescope", nsScopeKey.String())
+
 				continue
 			}
 			return nil, err
@@ -232,9 +249,13 @@ func (r *Reconciler) getNamespaceScopeCRList(ctx context.Context) ([]*nssv1.Name
 
 // SetupWithManager adds namespacescope controller to the manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	fair := throughput.NewFairQueue("namespacescope")
 	err := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(throughput.ControllerOptions("namespacescope")).
 		For(&operatorv1alpha1.OperandRequest{}).
-		Complete(reconcile.Func(r.ReconcileOperandRequest))
+		// The requests are dispatched in turn across the namespaces by the fair queue
+		Watches(fair.Source(), &handler.EnqueueRequestForObject{}).
+		Complete(fair.Reconciler(shard.Reconciler(reconcile.Func(r.ReconcileOperandRequest))))
 	if err != nil {
 		return err
 	}
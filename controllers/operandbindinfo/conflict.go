@@ -0,0 +1,112 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandbindinfo
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// copyBackoff limits the retries of an update conflicting with another writer of a Secret or a ConfigMap
+var copyBackoff = wait.Backoff{
+	Steps:    5,
+	Duration: 100 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   0.1,
+}
+
+// updateWithRetry applies the mutation to the object and updates it. When the update conflicts with another writer,
+// the object is fetched again and the update is retried with backoff.
+func (r *Reconciler) updateWithRetry(ctx context.Context, obj client.Object, mutate func()) error {
+	attempt := 0
+	return retry.OnError(copyBackoff, apierrors.IsConflict, func() error {
+		if attempt > 0 {
+			logging.FromContext(ctx).V(logging.LevelChange).Info("Retrying the update conflicting with another writer", "kind", obj.GetObjectKind().GroupVersionKind().Kind, "name", obj.GetNamespace()+"/"+obj.GetName(), "attempt", attempt)
+			if err := r.Client.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+				return err
+			}
+		}
+		attempt++
+		mutate()
+		return r.Update(ctx, obj, client.FieldOwner(constant.FieldManager))
+	})
+}
+
+// reportPropagateConflict sets a PropagateConflict condition with the competing field manager when the Secret or
+// the ConfigMap can't be updated because of another writer, and returns true. It returns false for the other errors.
+func (r *Reconciler) reportPropagateConflict(ctx context.Context, bindInfoInstance *operatorv1alpha1.OperandBindInfo, kind string, obj client.Object, err error) bool {
+	if !apierrors.IsConflict(err) && !isImmutable(err) {
+		return false
+	}
+	manager := "unknown"
+	if getErr := r.Client.Get(ctx, client.ObjectKeyFromObject(obj), obj); getErr == nil {
+		manager = competingManager(obj)
+	}
+	name := kind + " " + obj.GetNamespace() + "/" + obj.GetName()
+	logging.FromContext(ctx).Info("Failed to update the object conflicting with another writer", "kind", kind, "name", obj.GetNamespace()+"/"+obj.GetName(), "manager", manager, "error", err.Error())
+	bindInfoInstance.SetPropagateConflictCondition(name, manager, corev1.ConditionTrue)
+	return true
+}
+
+// isImmutable returns true if the update is rejected because it changes an immutable field
+func isImmutable(err error) bool {
+	if !apierrors.IsInvalid(err) {
+		return false
+	}
+	if status, ok := err.(apierrors.APIStatus); ok && status.Status().Details != nil {
+		for _, cause := range status.Status().Details.Causes {
+			if strings.Contains(cause.Message, "immutable") {
+				return true
+			}
+		}
+	}
+	return strings.Contains(err.Error(), "immutable")
+}
+
+// competingManager returns the field manager other than ODLM which updated the object last
+func competingManager(obj metav1.Object) string {
+	var manager string
+	var latest time.Time
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Manager == constant.FieldManager || entry.Manager == "" {
+			continue
+		}
+		var updated time.Time
+		if entry.Time != nil {
+			updated = entry.Time.Time
+		}
+		if manager == "" || !updated.Before(latest) {
+			manager, latest = entry.Manager, updated
+		}
+	}
+	if manager == "" {
+		return "unknown"
+	}
+	return manager
+}
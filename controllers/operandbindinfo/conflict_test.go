@@ -0,0 +1,67 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandbindinfo
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+)
+
+var _ = Describe("Propagate conflicts", func() {
+
+	It("Should find the last field manager other than ODLM", func() {
+		now := time.Now()
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{ManagedFields: []metav1.ManagedFieldsEntry{
+			{Manager: "kubectl-edit", Time: &metav1.Time{Time: now.Add(-time.Hour)}},
+			{Manager: "vault-agent", Time: &metav1.Time{Time: now.Add(-time.Minute)}},
+			{Manager: constant.FieldManager, Time: &metav1.Time{Time: now}},
+		}}}
+		Expect(competingManager(secret)).Should(Equal("vault-agent"))
+		Expect(competingManager(&corev1.Secret{})).Should(Equal("unknown"))
+	})
+
+	It("Should detect the updates of the immutable fields", func() {
+		gk := schema.GroupKind{Kind: "Secret"}
+		immutable := apierrors.NewInvalid(gk, "example", field.ErrorList{field.Invalid(field.NewPath("data"), nil, "field is immutable when `immutable` is set")})
+		Expect(isImmutable(immutable)).Should(BeTrue())
+		invalid := apierrors.NewInvalid(gk, "example", field.ErrorList{field.Required(field.NewPath("type"), "")})
+		Expect(isImmutable(invalid)).Should(BeFalse())
+		Expect(isImmutable(apierrors.NewConflict(schema.GroupResource{Resource: "secrets"}, "example", nil))).Should(BeFalse())
+	})
+
+	It("Should set and remove the PropagateConflict condition", func() {
+		bindInfo := &operatorv1alpha1.OperandBindInfo{}
+		bindInfo.SetPropagateConflictCondition("Secret ibm-common-services/example", "vault-agent", corev1.ConditionTrue)
+		bindInfo.SetPropagateConflictCondition("Secret ibm-common-services/example", "vault-agent", corev1.ConditionTrue)
+		Expect(bindInfo.Status.Conditions).Should(HaveLen(1))
+		Expect(bindInfo.Status.Conditions[0].Type).Should(Equal(operatorv1alpha1.ConditionPropagateConflict))
+		Expect(bindInfo.Status.Conditions[0].Message).Should(ContainSubstring("vault-agent"))
+
+		bindInfo.RemovePropagateConflictCondition("Secret ibm-common-services/example")
+		Expect(bindInfo.Status.Conditions).Should(BeEmpty())
+	})
+})
@@ -31,7 +31,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
-	"k8s.io/klog"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -46,7 +45,11 @@ import (
 	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
 	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/ownership"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/shard"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/throughput"
 )
 
 // Reconciler reconciles a OperandBindInfo object
@@ -66,6 +69,8 @@ var (
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
+	ctx, log := logging.Reconcile(ctx, "operandbindinfo", "bindinfo", req.NamespacedName.String())
+
 	// Fetch the OperandBindInfo instance
 	bindInfoInstance := &operatorv1alpha1.OperandBindInfo{}
 	if err := r.Client.Get(ctx, req.NamespacedName, bindInfoInstance); err != nil {
@@ -75,23 +80,37 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 
 	originalInstance := bindInfoInstance.DeepCopy()
 
+	// Skip the OperandBindInfo when its namespace opts out of ODLM, the deletion is still cleaned up
+	if bindInfoInstance.ObjectMeta.DeletionTimestamp.IsZero() {
+		excluded, err := r.IsNamespaceExcluded(ctx, bindInfoInstance.Namespace)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if excluded {
+			log.Info("Skip the OperandBindInfo in the excluded namespace", "label", constant.ExcludeNamespaceLabel)
+			return ctrl.Result{}, nil
+		}
+	}
+
 	// Always attempt to patch the status after each reconciliation.
 	defer func() {
 		if reflect.DeepEqual(originalInstance.Status, bindInfoInstance.Status) {
 			return
 		}
-		if err := r.Client.Status().Patch(ctx, bindInfoInstance, client.MergeFrom(originalInstance)); err != nil {
+		if err := r.PatchStatus(ctx, bindInfoInstance, originalInstance, func(latest client.Object) error {
+			return deploy.ReapplyStatus(&latest.(*operatorv1alpha1.OperandBindInfo).Status, &originalInstance.Status, &bindInfoInstance.Status)
+		}); err != nil {
 			reconcileErr = utilerrors.NewAggregate([]error{reconcileErr, fmt.Errorf("error while patching OperandBindInfo.Status: %v", err)})
 		}
 	}()
 
-	klog.V(1).Infof("Reconciling OperandBindInfo: %s", req.NamespacedName)
+	log.V(logging.LevelFlow).Info("Reconciling OperandBindInfo")
 
 	// If the finalizer is added, EnsureFinalizer() will return true. If the finalizer is already there, EnsureFinalizer() will return false
 	if bindInfoInstance.EnsureFinalizer() {
 		err := r.Patch(ctx, bindInfoInstance, client.MergeFrom(originalInstance))
 		if err != nil {
-			klog.Errorf("failed to update the OperandBindinfo %s: %v", req.NamespacedName.String(), err)
+			log.Error(err, "failed to update the OperandBindinfo")
 			return ctrl.Result{}, err
 		}
 		return ctrl.Result{Requeue: true}, nil
@@ -113,21 +132,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{Requeue: true}, nil
 	}
 
-	// Initialize OperandBindInfo status
+	// Initialize OperandBindInfo status, it is patched on the return
 	if !bindInfoInstance.InitBindInfoStatus() {
-		klog.V(3).Infof("Initializing the status of OperandBindInfo %s in the namespace %s", req.Name, req.Namespace)
-		if err := r.Status().Update(ctx, bindInfoInstance); err != nil {
-			return ctrl.Result{}, err
-		}
+		log.V(logging.LevelDebug).Info("Initializing the status of OperandBindInfo")
 		return ctrl.Result{Requeue: true}, nil
 	}
 
 	// Fetch the OperandRegistry instance
 	registryKey := bindInfoInstance.GetRegistryKey()
+	ctx, log = logging.WithValues(ctx, "registry", registryKey.String(), "operator", bindInfoInstance.Spec.Operand)
 	registryInstance := &operatorv1alpha1.OperandRegistry{}
 	if err := r.Client.Get(ctx, registryKey, registryInstance); err != nil {
 		if apierrors.IsNotFound(err) {
-			klog.Errorf("failed to find OperandRegistry from the NamespacedName %s: %v", registryKey.String(), err)
+			log.Error(err, "failed to find OperandRegistry")
 			r.Recorder.Eventf(bindInfoInstance, corev1.EventTypeWarning, "NotFound", "NotFound OperandRegistry from the NamespacedName %s", registryKey.String())
 		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
@@ -137,13 +154,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	// Get the OperandRequest namespace
 	requestNamespaces := registryInstance.Status.OperatorsStatus[bindInfoInstance.Spec.Operand].ReconcileRequests
 	if len(requestNamespaces) == 0 {
-		// There is no operand depend on the current bind info, nothing to do.
-		return ctrl.Result{}, nil
+		// There is no operand depend on the current bind info, only the copies left behind are deleted.
+		return ctrl.Result{}, r.deleteStaleCopies(ctx, bindInfoInstance, nil, nil)
 	}
 	// Get the operand namespace
 	operandOperator := registryInstance.GetOperator(bindInfoInstance.Spec.Operand)
 	if operandOperator == nil {
-		klog.Errorf("failed to find operator %s in the OperandRegistry %s", bindInfoInstance.Spec.Operand, registryInstance.Name)
+		log.Info("failed to find the operator in the OperandRegistry")
 		r.Recorder.Eventf(bindInfoInstance, corev1.EventTypeWarning, "NotFound", "NotFound operator %s in the OperandRegistry %s", bindInfoInstance.Spec.Operand, registryInstance.Name)
 		return ctrl.Result{}, nil
 	}
@@ -151,14 +168,44 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 
 	// If Secret or ConfigMap not found, reconcile will requeue after 1 min
 	var requeue bool
+	// The copies each OperandRequest still asks for, and the OperandRequests whose copies are left untouched
+	expected := make(map[types.NamespacedName]map[string]string)
+	skipped := make(map[types.NamespacedName]bool)
 
 	// Get OperandRequest instance and Copy Secret and/or ConfigMap
 	for _, bindRequest := range requestNamespaces {
+		// Never copy the Secret and ConfigMap into the namespaces opting out of ODLM
+		requestKey := types.NamespacedName{Name: bindRequest.Name, Namespace: bindRequest.Namespace}
+		skipped[requestKey] = true
+		excluded, err := r.IsNamespaceExcluded(ctx, bindRequest.Namespace)
+		if err != nil {
+			merr.Add(err)
+			continue
+		}
+		if excluded {
+			log.Info("Skip copying secret and/or configmap to the excluded namespace", "namespace", bindRequest.Namespace)
+			continue
+		}
+		// Never copy the Secret and ConfigMap into the namespaces the OperandBindInfo doesn't allow,
+		// and remove the copies made before the namespace was disallowed
+		allowed, err := r.allowsNamespace(ctx, bindInfoInstance, bindRequest.Namespace)
+		if err != nil {
+			merr.Add(err)
+			continue
+		}
+		if !allowed {
+			log.Info("Skip copying secret and/or configmap to the namespace not allowed", "namespace", bindRequest.Namespace)
+			r.Recorder.Eventf(bindInfoInstance, corev1.EventTypeWarning, constant.EventReasonPermissionDenied, "The namespace %s is not allowed, the Secrets and ConfigMaps are not copied for OperandRequest %s", bindRequest.Namespace, bindRequest.Name)
+			if err := r.deleteCopies(ctx, bindInfoInstance, bindRequest.Namespace); err != nil {
+				merr.Add(err)
+			}
+			continue
+		}
 		// Get the OperandRequest of operandBindInfo
 		requestInstance := &operatorv1alpha1.OperandRequest{}
 		if err := r.Client.Get(ctx, types.NamespacedName{Name: bindRequest.Name, Namespace: bindRequest.Namespace}, requestInstance); err != nil {
 			if apierrors.IsNotFound(err) {
-				klog.Errorf("failed to find OperandRequest %s in the namespace %s: %v", bindRequest.Name, bindRequest.Namespace, err)
+				log.Error(err, "failed to find OperandRequest", "request", bindRequest.Namespace+"/"+bindRequest.Name)
 				r.Recorder.Eventf(bindInfoInstance, corev1.EventTypeWarning, "NotFound", "NotFound OperandRequest %s in the namespace %s", bindRequest.Name, bindRequest.Namespace)
 			}
 			merr.Add(err)
@@ -166,11 +213,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		}
 		// Get binding information from OperandRequest
 		secretReq, cmReq := getBindingInfofromRequest(bindInfoInstance, requestInstance)
+		delete(skipped, requestKey)
+		expected[requestKey] = expectedCopies(bindInfoInstance, requestInstance, operandNamespace)
 		// Copy Secret and/or ConfigMap to the OperandRequest namespace
-		klog.V(3).Infof("Start to copy secret and/or configmap to the namespace %s", bindRequest.Namespace)
+		log.V(logging.LevelDebug).Info("Start to copy secret and/or configmap to the namespace", "namespace", bindRequest.Namespace)
 		for key, binding := range bindInfoInstance.Spec.Bindings {
 			if !privatePrefix.MatchString(key) && !protectedPrefix.MatchString(key) && !publicPrefix.MatchString(key) {
-				klog.Warningf("BindInfo key %s should have one of prefix: private, protected, public", key)
+				log.Info("BindInfo key should have one of prefix: private, protected, public", "key", key)
 				continue
 			}
 			if operandNamespace != bindRequest.Namespace {
@@ -195,9 +244,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 			requeue = requeue || requeueCm
 		}
 	}
+	// Delete the copies the OperandRequests don't ask for anymore
+	if err := r.deleteStaleCopies(ctx, bindInfoInstance, expected, skipped); err != nil {
+		merr.Add(err)
+	}
 	if len(merr.Errors) != 0 {
 		r.updateBindInfoPhase(bindInfoInstance, operatorv1alpha1.BindInfoFailed, requestNamespaces)
-		klog.Errorf("failed to reconcile the OperandBindinfo %s: %v", req.NamespacedName, merr)
+		log.Error(merr, "failed to reconcile the OperandBindinfo")
 		return ctrl.Result{}, merr
 	}
 
@@ -208,7 +261,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 
 	r.updateBindInfoPhase(bindInfoInstance, operatorv1alpha1.BindInfoCompleted, requestNamespaces)
 
-	klog.V(2).Infof("Finished reconciling OperandBindInfo: %s", req.NamespacedName)
+	log.V(logging.LevelFlow).Info("Finished reconciling OperandBindInfo")
 	return ctrl.Result{}, nil
 }
 
@@ -219,40 +272,45 @@ func (r *Reconciler) copySecret(ctx context.Context, sourceName, targetName, sou
 		return false, nil
 	}
 
-	if sourceName == targetName && sourceNs == targetNs {
-		return false, nil
-	}
-
+	targetName = copyTargetName(bindInfoInstance, sourceName, targetName, sourceNs, targetNs, key)
 	if targetName == "" {
-		if publicPrefix.MatchString(key) {
-			targetName = bindInfoInstance.Name + "-" + sourceName
-		} else {
-			return false, nil
-		}
+		return false, nil
 	}
 
 	secret := &corev1.Secret{}
 	if err := r.Client.Get(ctx, types.NamespacedName{Name: sourceName, Namespace: sourceNs}, secret); err != nil {
 		if apierrors.IsNotFound(err) {
-			klog.V(3).Infof("Secret %s is not found from the namespace %s", sourceName, sourceNs)
+			logging.FromContext(ctx).V(logging.LevelDebug).Info("Secret is not found", "secret", sourceNs+"/"+sourceName)
 			r.Recorder.Eventf(bindInfoInstance, corev1.EventTypeNormal, "NotFound", "No Secret %s in the namespace %s", sourceName, sourceNs)
 			return true, nil
 		}
 		return false, errors.Wrapf(err, "failed to get Secret %s/%s", sourceNs, sourceName)
 	}
+	requestKey := types.NamespacedName{Namespace: requestInstance.Namespace, Name: requestInstance.Name}
 	// Create the Secret to the OperandRequest namespace
 	secretLabel := make(map[string]string)
 	// Copy from the original labels to the target labels
 	for k, v := range secret.Labels {
+		// The copy is tracked for its own OperandRequest only
+		if ownership.IsKey(k) {
+			continue
+		}
 		secretLabel[k] = v
 	}
 	secretLabel[bindInfoInstance.Namespace+"."+bindInfoInstance.Name+"/bindinfo"] = "true"
+	for k, v := range ownership.Labels(requestKey) {
+		secretLabel[k] = v
+	}
 	secretLabel[constant.OpbiTypeLabel] = "copy"
 	secretCopy := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      targetName,
 			Namespace: targetNs,
 			Labels:    secretLabel,
+			Annotations: map[string]string{
+				constant.OpbiBindingAnnotation: key,
+				ownership.Key(requestKey):      "true",
+			},
 		},
 		Type:       secret.Type,
 		Data:       secret.Data,
@@ -264,8 +322,9 @@ func (r *Reconciler) copySecret(ctx context.Context, sourceName, targetName, sou
 	}
 
 	var podRefreshment bool
+	propagated := true
 	// Create the Secret in the OperandRequest namespace
-	if err := r.Create(ctx, secretCopy); err != nil {
+	if err := r.Create(ctx, secretCopy, client.FieldOwner(constant.FieldManager)); err != nil {
 		if apierrors.IsAlreadyExists(err) {
 			// If already exist, update the Secret
 			existingSecret := &corev1.Secret{}
@@ -273,39 +332,54 @@ func (r *Reconciler) copySecret(ctx context.Context, sourceName, targetName, sou
 				return false, errors.Wrapf(err, "failed to get secret %s/%s", targetNs, targetName)
 			}
 			prevResourceVersion := existingSecret.ResourceVersion
-			if err := r.Update(ctx, secretCopy); err != nil {
+			if err := r.updateWithRetry(ctx, existingSecret, func() {
+				existingSecret.Labels = secretCopy.Labels
+				existingSecret.Annotations = secretCopy.Annotations
+				existingSecret.OwnerReferences = secretCopy.OwnerReferences
+				existingSecret.Type = secretCopy.Type
+				existingSecret.Data = secretCopy.Data
+				existingSecret.StringData = secretCopy.StringData
+			}); err != nil {
+				// Retry at the next reconcile when another writer keeps changing the Secret
+				if r.reportPropagateConflict(ctx, bindInfoInstance, "Secret", existingSecret, err) {
+					return true, nil
+				}
 				return false, errors.Wrapf(err, "failed to update secret %s/%s", targetNs, targetName)
 			}
-			curSecret := &corev1.Secret{}
-			if err := r.Client.Get(ctx, types.NamespacedName{Namespace: targetNs, Name: targetName}, curSecret); err != nil {
-				return false, errors.Wrapf(err, "failed to get secret %s/%s", targetNs, targetName)
-			}
-			curResourceVersion := curSecret.ResourceVersion
-			if prevResourceVersion != curResourceVersion {
+			if prevResourceVersion != existingSecret.ResourceVersion {
 				podRefreshment = true
 			}
+			propagated = podRefreshment
 		} else {
 			return false, errors.Wrapf(err, "failed to create secret %s/%s", targetNs, targetName)
 		}
 		if podRefreshment {
-			if err := r.refreshPods(targetNs, targetName, "secret"); err != nil {
+			if err := r.refreshPods(ctx, targetNs, targetName, "secret"); err != nil {
 				return false, errors.Wrapf(err, "failed to refresh pods mounting secret %s/%s", targetNs, targetName)
 			}
 		}
 	}
 
-	ensureLabelsForSecret(secret, map[string]string{
-		constant.OpbiNsLabel:   bindInfoInstance.Namespace,
-		constant.OpbiNameLabel: bindInfoInstance.Name,
-		constant.OpbiTypeLabel: "original",
-	})
-
 	// Update the operand Secret
-	if err := r.Update(ctx, secret); err != nil {
-		klog.Errorf("failed to update Secret %s in the namespace %s: %v", secret.Name, secret.Namespace, err)
+	if err := r.updateWithRetry(ctx, secret, func() {
+		ensureLabelsForSecret(secret, map[string]string{
+			constant.OpbiNsLabel:   bindInfoInstance.Namespace,
+			constant.OpbiNameLabel: bindInfoInstance.Name,
+			constant.OpbiTypeLabel: "original",
+		})
+	}); err != nil {
+		if r.reportPropagateConflict(ctx, bindInfoInstance, "Secret", secret, err) {
+			return true, nil
+		}
+		logging.FromContext(ctx).Error(err, "failed to update Secret", "secret", secret.Namespace+"/"+secret.Name)
 		return false, err
 	}
-	klog.V(1).Infof("Secret %s is copied from the namespace %s to secret %s in the namespace %s", sourceName, sourceNs, targetName, targetNs)
+	bindInfoInstance.RemovePropagateConflictCondition("Secret " + targetNs + "/" + targetName)
+	bindInfoInstance.RemovePropagateConflictCondition("Secret " + sourceNs + "/" + sourceName)
+	logging.FromContext(ctx).V(logging.LevelFlow).Info("Secret is copied", "source", sourceNs+"/"+sourceName, "target", targetNs+"/"+targetName)
+	if propagated {
+		r.recordPropagated(bindInfoInstance, requestInstance, "Secret", sourceNs, sourceName, targetNs, targetName)
+	}
 
 	return false, nil
 }
@@ -318,40 +392,45 @@ func (r *Reconciler) copyConfigmap(ctx context.Context, sourceName, targetName,
 		return false, nil
 	}
 
-	if sourceName == targetName && sourceNs == targetNs {
-		return false, nil
-	}
-
+	targetName = copyTargetName(bindInfoInstance, sourceName, targetName, sourceNs, targetNs, key)
 	if targetName == "" {
-		if publicPrefix.MatchString(key) {
-			targetName = bindInfoInstance.Name + "-" + sourceName
-		} else {
-			return false, nil
-		}
+		return false, nil
 	}
 
 	cm := &corev1.ConfigMap{}
 	if err := r.Client.Get(ctx, types.NamespacedName{Name: sourceName, Namespace: sourceNs}, cm); err != nil {
 		if apierrors.IsNotFound(err) {
-			klog.V(3).Infof("Configmap %s/%s is not found", sourceNs, sourceName)
+			logging.FromContext(ctx).V(logging.LevelDebug).Info("Configmap is not found", "configmap", sourceNs+"/"+sourceName)
 			r.Recorder.Eventf(bindInfoInstance, corev1.EventTypeNormal, "NotFound", "No Configmap %s in the namespace %s", sourceName, sourceNs)
 			return true, nil
 		}
 		return false, errors.Wrapf(err, "failed to get Configmap %s/%s", sourceNs, sourceName)
 	}
+	requestKey := types.NamespacedName{Namespace: requestInstance.Namespace, Name: requestInstance.Name}
 	// Create the ConfigMap to the OperandRequest namespace
 	cmLabel := make(map[string]string)
 	// Copy from the original labels to the target labels
 	for k, v := range cm.Labels {
+		// The copy is tracked for its own OperandRequest only
+		if ownership.IsKey(k) {
+			continue
+		}
 		cmLabel[k] = v
 	}
 	cmLabel[bindInfoInstance.Namespace+"."+bindInfoInstance.Name+"/bindinfo"] = "true"
+	for k, v := range ownership.Labels(requestKey) {
+		cmLabel[k] = v
+	}
 	cmLabel[constant.OpbiTypeLabel] = "copy"
 	cmCopy := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      targetName,
 			Namespace: targetNs,
 			Labels:    cmLabel,
+			Annotations: map[string]string{
+				constant.OpbiBindingAnnotation: key,
+				ownership.Key(requestKey):      "true",
+			},
 		},
 		Data:       cm.Data,
 		BinaryData: cm.BinaryData,
@@ -362,8 +441,9 @@ func (r *Reconciler) copyConfigmap(ctx context.Context, sourceName, targetName,
 	}
 
 	var podRefreshment bool
+	propagated := true
 	// Create the ConfigMap in the OperandRequest namespace
-	if err := r.Create(ctx, cmCopy); err != nil {
+	if err := r.Create(ctx, cmCopy, client.FieldOwner(constant.FieldManager)); err != nil {
 		if apierrors.IsAlreadyExists(err) {
 			// If already exist, update the ConfigMap
 			existingCm := &corev1.ConfigMap{}
@@ -371,50 +451,88 @@ func (r *Reconciler) copyConfigmap(ctx context.Context, sourceName, targetName,
 				return false, errors.Wrapf(err, "failed to get ConfigMap %s/%s", targetNs, targetName)
 			}
 			prevResourceVersion := existingCm.ResourceVersion
-			if err := r.Update(ctx, cmCopy); err != nil {
+			if err := r.updateWithRetry(ctx, existingCm, func() {
+				existingCm.Labels = cmCopy.Labels
+				existingCm.Annotations = cmCopy.Annotations
+				existingCm.OwnerReferences = cmCopy.OwnerReferences
+				existingCm.Data = cmCopy.Data
+				existingCm.BinaryData = cmCopy.BinaryData
+			}); err != nil {
+				// Retry at the next reconcile when another writer keeps changing the ConfigMap
+				if r.reportPropagateConflict(ctx, bindInfoInstance, "ConfigMap", existingCm, err) {
+					return true, nil
+				}
 				return false, errors.Wrapf(err, "failed to update ConfigMap %s/%s", targetNs, sourceName)
 			}
-			curCm := &corev1.ConfigMap{}
-			if err := r.Client.Get(ctx, types.NamespacedName{Namespace: targetNs, Name: targetName}, curCm); err != nil {
-				return false, errors.Wrapf(err, "failed to get ConfigMap %s/%s", targetNs, targetName)
-			}
-			curResourceVersion := curCm.ResourceVersion
-			if prevResourceVersion != curResourceVersion {
+			if prevResourceVersion != existingCm.ResourceVersion {
 				podRefreshment = true
 			}
+			propagated = podRefreshment
 		} else {
 			return false, errors.Wrapf(err, "failed to create ConfigMap %s/%s", targetNs, sourceName)
 		}
 	}
 
 	if podRefreshment {
-		if err := r.refreshPods(targetNs, targetName, "configmap"); err != nil {
+		if err := r.refreshPods(ctx, targetNs, targetName, "configmap"); err != nil {
 			return false, errors.Wrapf(err, "failed to refresh pods mounting ConfigMap %s/%s", targetNs, targetName)
 		}
 	}
 
-	// Set the OperandBindInfo label for the ConfigMap
-	ensureLabelsForConfigMap(cm, map[string]string{
-		constant.OpbiNsLabel:   bindInfoInstance.Namespace,
-		constant.OpbiNameLabel: bindInfoInstance.Name,
-		constant.OpbiTypeLabel: "original",
-	})
-
-	// Update the operand Configmap
-	if err := r.Update(ctx, cm); err != nil {
+	// Set the OperandBindInfo label for the ConfigMap, and update the operand Configmap
+	if err := r.updateWithRetry(ctx, cm, func() {
+		ensureLabelsForConfigMap(cm, map[string]string{
+			constant.OpbiNsLabel:   bindInfoInstance.Namespace,
+			constant.OpbiNameLabel: bindInfoInstance.Name,
+			constant.OpbiTypeLabel: "original",
+		})
+	}); err != nil {
+		if r.reportPropagateConflict(ctx, bindInfoInstance, "ConfigMap", cm, err) {
+			return true, nil
+		}
 		return false, errors.Wrapf(err, "failed to update ConfigMap %s/%s", cm.Namespace, cm.Name)
 	}
-	klog.V(1).Infof("Configmap %s is copied from the namespace %s to the namespace %s", sourceName, sourceNs, targetNs)
+	bindInfoInstance.RemovePropagateConflictCondition("ConfigMap " + targetNs + "/" + targetName)
+	bindInfoInstance.RemovePropagateConflictCondition("ConfigMap " + sourceNs + "/" + sourceName)
+	logging.FromContext(ctx).V(logging.LevelFlow).Info("Configmap is copied", "source", sourceNs+"/"+sourceName, "target", targetNs+"/"+targetName)
+	if propagated {
+		r.recordPropagated(bindInfoInstance, requestInstance, "ConfigMap", sourceNs, sourceName, targetNs, targetName)
+	}
 
 	return false, nil
 }
 
+// recordPropagated records the Event of a Secret or a ConfigMap copied to the namespace of an OperandRequest
+func (r *Reconciler) recordPropagated(bindInfoInstance *operatorv1alpha1.OperandBindInfo, requestInstance *operatorv1alpha1.OperandRequest, kind, sourceNs, sourceName, targetNs, targetName string) {
+	r.Recorder.Eventf(bindInfoInstance, corev1.EventTypeNormal, constant.EventReasonBindInfoPropagated, "Copied %s %s/%s to %s/%s for OperandRequest %s", kind, sourceNs, sourceName, targetNs, targetName, requestInstance.Name)
+	r.Recorder.Eventf(requestInstance, corev1.EventTypeNormal, constant.EventReasonBindInfoPropagated, "Copied %s %s/%s to %s/%s from OperandBindInfo %s/%s", kind, sourceNs, sourceName, targetNs, targetName, bindInfoInstance.Namespace, bindInfoInstance.Name)
+}
+
 func (r *Reconciler) cleanupCopies(ctx context.Context, bindInfoInstance *operatorv1alpha1.OperandBindInfo) error {
+	if err := r.deleteCopies(ctx, bindInfoInstance, ""); err != nil {
+		return err
+	}
+	// Update finalizer to allow delete CR
+	originalBind := bindInfoInstance.DeepCopy()
+	removed := bindInfoInstance.RemoveFinalizer()
+	if removed {
+		err := r.Patch(ctx, bindInfoInstance, client.MergeFrom(originalBind))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteCopies deletes the Secrets and ConfigMaps copied by the OperandBindInfo to the namespace,
+// or to all the namespaces when the namespace is empty
+func (r *Reconciler) deleteCopies(ctx context.Context, bindInfoInstance *operatorv1alpha1.OperandBindInfo, namespace string) error {
 	secretList := &corev1.SecretList{}
 	cmList := &corev1.ConfigMapList{}
 
 	opts := []client.ListOption{
 		client.MatchingLabels(map[string]string{bindInfoInstance.Namespace + "." + bindInfoInstance.Name + "/bindinfo": "true"}),
+		client.InNamespace(namespace),
 	}
 	if err := r.Client.List(ctx, secretList, opts...); err != nil {
 		return err
@@ -424,28 +542,35 @@ func (r *Reconciler) cleanupCopies(ctx context.Context, bindInfoInstance *operat
 	}
 
 	for i := range secretList.Items {
-		if err := r.Delete(ctx, &secretList.Items[i]); err != nil {
+		if err := r.Delete(ctx, &secretList.Items[i]); client.IgnoreNotFound(err) != nil {
 			return err
 		}
 	}
 
 	for i := range cmList.Items {
-		if err := r.Delete(ctx, &cmList.Items[i]); err != nil {
-			return err
-		}
-	}
-	// Update finalizer to allow delete CR
-	originalBind := bindInfoInstance.DeepCopy()
-	removed := bindInfoInstance.RemoveFinalizer()
-	if removed {
-		err := r.Patch(ctx, bindInfoInstance, client.MergeFrom(originalBind))
-		if err != nil {
+		if err := r.Delete(ctx, &cmList.Items[i]); client.IgnoreNotFound(err) != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// allowsNamespace returns true if the OperandBindInfo allows copying the Secrets and ConfigMaps to the namespace
+func (r *Reconciler) allowsNamespace(ctx context.Context, bindInfoInstance *operatorv1alpha1.OperandBindInfo, namespace string) (bool, error) {
+	if bindInfoInstance.Spec.AllowedNamespaces == nil {
+		return true, nil
+	}
+	ns := &corev1.Namespace{}
+	if err := r.Reader.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil && !apierrors.IsNotFound(err) {
+		return false, errors.Wrapf(err, "failed to get namespace %s", namespace)
+	}
+	allowed, err := bindInfoInstance.AllowsNamespace(namespace, ns.GetLabels())
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid allowedNamespaces of OperandBindInfo %s/%s", bindInfoInstance.Namespace, bindInfoInstance.Name)
+	}
+	return allowed, nil
+}
+
 func getBindingInfofromRequest(bindInfoInstance *operatorv1alpha1.OperandBindInfo, requestInstance *operatorv1alpha1.OperandRequest) (map[string]string, map[string]string) {
 	secretReq, cmReq := make(map[string]string), make(map[string]string)
 	for _, req := range requestInstance.Spec.Requests {
@@ -496,21 +621,25 @@ func (r *Reconciler) getOperandRequestToRequestMapper(mgr manager.Manager) handl
 		or := a.(*operatorv1alpha1.OperandRequest)
 		reglist := or.GetAllRegistryReconcileRequest()
 		mgrClient := mgr.GetClient()
-		bindInfoList := &operatorv1alpha1.OperandBindInfoList{}
 
+		bindinfos := []reconcile.Request{}
+		seen := map[types.NamespacedName]bool{}
 		for _, registry := range reglist {
 			opts := []client.ListOption{
 				client.MatchingLabels(map[string]string{registry.Namespace + "." + registry.Name + "/registry": "true"}),
 			}
 
+			// Each registry is listed into its own list, so the OperandBindInfos of all the registries are enqueued
+			bindInfoList := &operatorv1alpha1.OperandBindInfoList{}
 			_ = mgrClient.List(ctx, bindInfoList, opts...)
-		}
-
-		bindinfos := []reconcile.Request{}
-		for _, bindinfo := range bindInfoList.Items {
-			namespaceName := types.NamespacedName{Name: bindinfo.Name, Namespace: bindinfo.Namespace}
-			req := reconcile.Request{NamespacedName: namespaceName}
-			bindinfos = append(bindinfos, req)
+			for _, bindinfo := range bindInfoList.Items {
+				namespaceName := types.NamespacedName{Name: bindinfo.Name, Namespace: bindinfo.Namespace}
+				if seen[namespaceName] {
+					continue
+				}
+				seen[namespaceName] = true
+				bindinfos = append(bindinfos, reconcile.Request{NamespacedName: namespaceName})
+			}
 		}
 
 		return bindinfos
@@ -558,15 +687,15 @@ func toOpbiRequest() handler.MapFunc {
 	}
 }
 
-func (r *Reconciler) refreshPods(ns, name, resourceType string) error {
+func (r *Reconciler) refreshPods(ctx context.Context, ns, name, resourceType string) error {
 	merr := &util.MultiErr{}
-	if err := r.refreshPodsFromDeploy(ns, name, resourceType); err != nil {
+	if err := r.refreshPodsFromDeploy(ctx, ns, name, resourceType); err != nil {
 		merr.Add(err)
 	}
-	if err := r.refreshPodsFromSts(ns, name, resourceType); err != nil {
+	if err := r.refreshPodsFromSts(ctx, ns, name, resourceType); err != nil {
 		merr.Add(err)
 	}
-	if err := r.refreshPodsFromDaemonSet(ns, name, resourceType); err != nil {
+	if err := r.refreshPodsFromDaemonSet(ctx, ns, name, resourceType); err != nil {
 		merr.Add(err)
 	}
 
@@ -577,7 +706,7 @@ func (r *Reconciler) refreshPods(ns, name, resourceType string) error {
 	return nil
 }
 
-func (r *Reconciler) refreshPodsFromDeploy(ns, name, resourceType string) error {
+func (r *Reconciler) refreshPodsFromDeploy(ctx context.Context, ns, name, resourceType string) error {
 	timeNow := time.Now().Format("2006-1-2.1504")
 	deploymentCandidates := []appsv1.Deployment{}
 	deployments := &appsv1.DeploymentList{}
@@ -585,7 +714,7 @@ func (r *Reconciler) refreshPodsFromDeploy(ns, name, resourceType string) error
 		client.MatchingLabels{constant.BindInfoRefreshLabel: "enabled"},
 		client.InNamespace(ns),
 	}
-	if err := r.Client.List(context.TODO(), deployments, opts...); err != nil {
+	if err := r.Client.List(ctx, deployments, opts...); err != nil {
 		return fmt.Errorf("error getting deployments: %v", err)
 	}
 	for _, deployment := range deployments.Items {
@@ -611,16 +740,16 @@ func (r *Reconciler) refreshPodsFromDeploy(ns, name, resourceType string) error
 		}
 		deployment.ObjectMeta.Annotations["bindinfo/restartTime"] = timeNow
 		deployment.Spec.Template.ObjectMeta.Annotations["bindinfo/restartTime"] = timeNow
-		err := r.Client.Update(context.TODO(), &deployment)
+		err := r.Client.Update(ctx, &deployment)
 		if err != nil {
 			return fmt.Errorf("error updating deployment: %v", err)
 		}
-		klog.V(2).Infof("BindInfo controller refreshing deployment %s/%s to pick up updated bindinfos", deployment.Namespace, deployment.Name)
+		logging.FromContext(ctx).V(logging.LevelChange).Info("BindInfo controller refreshing deployment to pick up updated bindinfos", "deployment", deployment.Namespace+"/"+deployment.Name)
 	}
 	return nil
 }
 
-func (r *Reconciler) refreshPodsFromSts(ns, name, resourceType string) error {
+func (r *Reconciler) refreshPodsFromSts(ctx context.Context, ns, name, resourceType string) error {
 	timeNow := time.Now().Format("2006-1-2.1504")
 	statefulSetCandidates := []appsv1.StatefulSet{}
 	statefulSets := &appsv1.StatefulSetList{}
@@ -628,7 +757,7 @@ func (r *Reconciler) refreshPodsFromSts(ns, name, resourceType string) error {
 		client.MatchingLabels{constant.BindInfoRefreshLabel: "enabled"},
 		client.InNamespace(ns),
 	}
-	if err := r.Client.List(context.TODO(), statefulSets, opts...); err != nil {
+	if err := r.Client.List(ctx, statefulSets, opts...); err != nil {
 		return fmt.Errorf("error getting statefulSets: %v", err)
 	}
 	for _, statefulSet := range statefulSets.Items {
@@ -654,16 +783,16 @@ func (r *Reconciler) refreshPodsFromSts(ns, name, resourceType string) error {
 		}
 		statefulSet.ObjectMeta.Annotations["bindinfo/restartTime"] = timeNow
 		statefulSet.Spec.Template.ObjectMeta.Annotations["bindinfo/restartTime"] = timeNow
-		err := r.Client.Update(context.TODO(), &statefulSet)
+		err := r.Client.Update(ctx, &statefulSet)
 		if err != nil {
 			return fmt.Errorf("error updating StatefulSet: %v", err)
 		}
-		klog.V(2).Infof("BindInfo controller refreshing StatefulSet %s/%s to pick up updated bindinfos", statefulSet.Namespace, statefulSet.Name)
+		logging.FromContext(ctx).V(logging.LevelChange).Info("BindInfo controller refreshing StatefulSet to pick up updated bindinfos", "statefulset", statefulSet.Namespace+"/"+statefulSet.Name)
 	}
 	return nil
 }
 
-func (r *Reconciler) refreshPodsFromDaemonSet(ns, name, resourceType string) error {
+func (r *Reconciler) refreshPodsFromDaemonSet(ctx context.Context, ns, name, resourceType string) error {
 	timeNow := time.Now().Format("2006-1-2.1504")
 	daemonSetCandidates := []appsv1.DaemonSet{}
 	daemonSets := &appsv1.DaemonSetList{}
@@ -671,7 +800,7 @@ func (r *Reconciler) refreshPodsFromDaemonSet(ns, name, resourceType string) err
 		client.MatchingLabels{constant.BindInfoRefreshLabel: "enabled"},
 		client.InNamespace(ns),
 	}
-	if err := r.Client.List(context.TODO(), daemonSets, opts...); err != nil {
+	if err := r.Client.List(ctx, daemonSets, opts...); err != nil {
 		return fmt.Errorf("error getting daemonSets: %v", err)
 	}
 	for _, daemonSet := range daemonSets.Items {
@@ -697,11 +826,12 @@ func (r *Reconciler) refreshPodsFromDaemonSet(ns, name, resourceType string) err
 		}
 		daemonSet.ObjectMeta.Annotations["bindinfo/restartTime"] = timeNow
 		daemonSet.Spec.Template.ObjectMeta.Annotations["bindinfo/restartTime"] = timeNow
-		err := r.Client.Update(context.TODO(), &daemonSet)
+		err := r.Client.Update(ctx, &daemonSet)
 		if err != nil {
 			return fmt.Errorf("error updating daemonSet: %v", err)
 		}
-		klog.V(2).Infof("BindInfo controller refreshing daemonSet %s/%s to pick up updated bindinfos", daemonSet.Namespace, daemonSet.Name)
+		logging.FromContext(ctx).V(logging.LevelChange).Info("BindInfo controller refreshing daemonSet to pick up updated bindinfos", "daemonset", daemonSet.Namespace+"/"+daemonSet.Name)
+
 	}
 	return nil
 }
@@ -728,6 +858,10 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 			return false
 		},
 		DeleteFunc: func(e event.DeleteEvent) bool {
+			// The copies deleted by someone else are copied again per the recreate policy
+			if e.Object.GetLabels()[constant.OpbiTypeLabel] == "copy" {
+				return util.GetRecreatePolicy() == operatorv1alpha1.RecreatePolicyImmediate
+			}
 			return true
 		},
 	}
@@ -749,7 +883,9 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 		},
 	}
 
+	fair := throughput.NewFairQueue("operandbindinfo")
 	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(throughput.ControllerOptions("operandbindinfo")).
 		For(&operatorv1alpha1.OperandBindInfo{}).
 		Watches(
 			&source.Kind{Type: &corev1.ConfigMap{}},
@@ -770,7 +906,10 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&source.Kind{Type: &operatorv1alpha1.OperandRegistry{}},
 			handler.EnqueueRequestsFromMapFunc(r.getOperandRegistryToRequestMapper(mgr)),
 			builder.WithPredicates(opregPredicates),
-		).Complete(r)
+		).
+		// The requests are dispatched in turn across the namespaces by the fair queue
+		Watches(fair.Source(), &handler.EnqueueRequestForObject{}).
+		Complete(fair.Reconciler(shard.Reconciler(r)))
 }
 
 func ensureLabelsForSecret(secret *corev1.Secret, labels map[string]string) {
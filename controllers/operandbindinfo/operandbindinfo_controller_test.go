@@ -24,8 +24,10 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/testutil"
 )
 
@@ -169,6 +171,19 @@ var _ = Describe("OperandBindInfo controller", func() {
 				return len(bindInfoInstance.Status.RequestNamespaces)
 			}, timeout, interval).Should(Equal(1))
 
+			By("Check the Events of the propagated secret and configmap")
+			Eventually(func() int {
+				events := &corev1.EventList{}
+				Expect(k8sClient.List(ctx, events, client.InNamespace(namespaceName))).Should(Succeed())
+				propagated := 0
+				for _, event := range events.Items {
+					if event.InvolvedObject.Name == name && event.Reason == constant.EventReasonBindInfoPropagated {
+						propagated++
+					}
+				}
+				return propagated
+			}, timeout, interval).Should(BeNumerically(">=", 2))
+
 			By("Deleting the OperandBindInfo")
 			Expect(k8sClient.Delete(ctx, bindInfo)).Should(Succeed())
 
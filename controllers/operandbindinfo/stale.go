@@ -0,0 +1,141 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandbindinfo
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// copyTargetName returns the name of the copy of `sourceName` in the namespace `targetNs`,
+// or an empty string when the binding `key` isn't copied there
+func copyTargetName(bindInfoInstance *operatorv1alpha1.OperandBindInfo, sourceName, targetName, sourceNs, targetNs, key string) string {
+	if sourceName == "" || sourceNs == "" || targetNs == "" {
+		return ""
+	}
+	if sourceName == targetName && sourceNs == targetNs {
+		return ""
+	}
+	if targetName == "" && publicPrefix.MatchString(key) {
+		return bindInfoInstance.Name + "-" + sourceName
+	}
+	return targetName
+}
+
+// expectedCopies returns the binding keys of the Secrets and ConfigMaps the OperandRequest asks for,
+// indexed by "<kind>/<name>" of the copies in its namespace
+func expectedCopies(bindInfoInstance *operatorv1alpha1.OperandBindInfo, requestInstance *operatorv1alpha1.OperandRequest, operandNamespace string) map[string]string {
+	copies := make(map[string]string)
+	secretReq, cmReq := getBindingInfofromRequest(bindInfoInstance, requestInstance)
+	for key, binding := range bindInfoInstance.Spec.Bindings {
+		if !privatePrefix.MatchString(key) && !protectedPrefix.MatchString(key) && !publicPrefix.MatchString(key) {
+			continue
+		}
+		if operandNamespace != requestInstance.Namespace && privatePrefix.MatchString(key) {
+			continue
+		}
+		if name := copyTargetName(bindInfoInstance, binding.Secret, secretReq[key], operandNamespace, requestInstance.Namespace, key); name != "" {
+			copies["Secret/"+name] = key
+		}
+		if name := copyTargetName(bindInfoInstance, binding.Configmap, cmReq[key], operandNamespace, requestInstance.Namespace, key); name != "" {
+			copies["ConfigMap/"+name] = key
+		}
+	}
+	return copies
+}
+
+// isStaleCopy returns true if the copy is controlled by an OperandRequest that doesn't ask for it anymore.
+// The copies of the OperandRequests in `skipped` are kept, since their bindings are unknown.
+func isStaleCopy(kind string, obj metav1.Object, expected map[types.NamespacedName]map[string]string, skipped map[types.NamespacedName]bool) bool {
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil || owner.Kind != "OperandRequest" {
+		return false
+	}
+	requestKey := types.NamespacedName{Namespace: obj.GetNamespace(), Name: owner.Name}
+	if skipped[requestKey] {
+		return false
+	}
+	_, found := expected[requestKey][kind+"/"+obj.GetName()]
+	return !found
+}
+
+// deleteStaleCopies deletes the Secrets and ConfigMaps copied by the OperandBindInfo that are no longer
+// asked for, because the bindings of their OperandRequest shrank or the OperandRequest stopped requesting the operand
+func (r *Reconciler) deleteStaleCopies(ctx context.Context, bindInfoInstance *operatorv1alpha1.OperandBindInfo, expected map[types.NamespacedName]map[string]string, skipped map[types.NamespacedName]bool) error {
+	opts := []client.ListOption{
+		client.MatchingLabels(map[string]string{bindInfoInstance.Namespace + "." + bindInfoInstance.Name + "/bindinfo": "true"}),
+	}
+	secretList := &corev1.SecretList{}
+	if err := r.Client.List(ctx, secretList, opts...); err != nil {
+		return err
+	}
+	cmList := &corev1.ConfigMapList{}
+	if err := r.Client.List(ctx, cmList, opts...); err != nil {
+		return err
+	}
+
+	var stale []client.Object
+	for i := range secretList.Items {
+		if isStaleCopy("Secret", &secretList.Items[i], expected, skipped) {
+			stale = append(stale, &secretList.Items[i])
+		}
+	}
+	for i := range cmList.Items {
+		if isStaleCopy("ConfigMap", &cmList.Items[i], expected, skipped) {
+			stale = append(stale, &cmList.Items[i])
+		}
+	}
+
+	excludedNamespaces := make(map[string]bool)
+	for _, obj := range stale {
+		excluded, ok := excludedNamespaces[obj.GetNamespace()]
+		if !ok {
+			var err error
+			if excluded, err = r.IsNamespaceExcluded(ctx, obj.GetNamespace()); err != nil {
+				return err
+			}
+			excludedNamespaces[obj.GetNamespace()] = excluded
+		}
+		// Never touch the namespaces opting out of ODLM
+		if excluded {
+			continue
+		}
+		kind := "Secret"
+		if _, isCm := obj.(*corev1.ConfigMap); isCm {
+			kind = "ConfigMap"
+		}
+		if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		// The copies made before the binding was recorded have no annotation
+		binding := obj.GetAnnotations()[constant.OpbiBindingAnnotation]
+		if binding == "" {
+			binding = "unknown"
+		}
+		logging.FromContext(ctx).V(logging.LevelChange).Info("Deleted the copy no longer requested", "kind", kind, "copy", obj.GetNamespace()+"/"+obj.GetName(), "binding", binding)
+		r.Recorder.Eventf(bindInfoInstance, corev1.EventTypeNormal, constant.EventReasonBindInfoCopyDeleted, "Deleted %s %s/%s of the binding %s, OperandRequest %s doesn't ask for it anymore", kind, obj.GetNamespace(), obj.GetName(), binding, metav1.GetControllerOf(obj).Name)
+	}
+	return nil
+}
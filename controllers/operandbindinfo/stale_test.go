@@ -0,0 +1,102 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandbindinfo
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Stale copies", func() {
+
+	bindInfo := &operatorv1alpha1.OperandBindInfo{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins-bindinfo", Namespace: "ibm-common-services"},
+		Spec: operatorv1alpha1.OperandBindInfoSpec{
+			Operand:  "jenkins",
+			Registry: "common-service",
+			Bindings: map[string]operatorv1alpha1.SecretConfigmap{
+				"public":         {Secret: "jenkins-credentials", Configmap: "jenkins-config"},
+				"protected-tls":  {Secret: "jenkins-tls"},
+				"private-admin":  {Secret: "jenkins-admin"},
+				"unknown-prefix": {Secret: "jenkins-other"},
+			},
+		},
+	}
+
+	request := func(bindings map[string]operatorv1alpha1.SecretConfigmap) *operatorv1alpha1.OperandRequest {
+		return &operatorv1alpha1.OperandRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a-request", Namespace: "team-a"},
+			Spec: operatorv1alpha1.OperandRequestSpec{Requests: []operatorv1alpha1.Request{{
+				Registry: "common-service",
+				Operands: []operatorv1alpha1.Operand{{Name: "jenkins", Bindings: bindings}},
+			}}},
+		}
+	}
+
+	It("Should name the copies of the bindings", func() {
+		Expect(copyTargetName(bindInfo, "jenkins-credentials", "", "ibm-common-services", "team-a", "public")).Should(Equal("jenkins-bindinfo-jenkins-credentials"))
+		Expect(copyTargetName(bindInfo, "jenkins-tls", "", "ibm-common-services", "team-a", "protected-tls")).Should(BeEmpty())
+		Expect(copyTargetName(bindInfo, "jenkins-tls", "team-a-tls", "ibm-common-services", "team-a", "protected-tls")).Should(Equal("team-a-tls"))
+		Expect(copyTargetName(bindInfo, "jenkins-tls", "jenkins-tls", "team-a", "team-a", "protected-tls")).Should(BeEmpty())
+		Expect(copyTargetName(bindInfo, "", "team-a-tls", "ibm-common-services", "team-a", "protected-tls")).Should(BeEmpty())
+	})
+
+	It("Should list the copies an OperandRequest asks for", func() {
+		copies := expectedCopies(bindInfo, request(map[string]operatorv1alpha1.SecretConfigmap{
+			"protected-tls": {Secret: "team-a-tls"},
+			"private-admin": {Secret: "team-a-admin"},
+		}), "ibm-common-services")
+		Expect(copies).Should(Equal(map[string]string{
+			"Secret/jenkins-bindinfo-jenkins-credentials": "public",
+			"ConfigMap/jenkins-bindinfo-jenkins-config":   "public",
+			"Secret/team-a-tls":                           "protected-tls",
+		}))
+
+		copies = expectedCopies(bindInfo, request(nil), "ibm-common-services")
+		Expect(copies).ShouldNot(HaveKey("Secret/team-a-tls"))
+	})
+
+	It("Should find the copies no longer requested", func() {
+		requestKey := types.NamespacedName{Namespace: "team-a", Name: "team-a-request"}
+		isController := true
+		copyOf := func(name, owner string) *corev1.Secret {
+			return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "team-a",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "operator.ibm.com/v1alpha1",
+					Kind:       "OperandRequest",
+					Name:       owner,
+					Controller: &isController,
+				}},
+			}}
+		}
+		expected := map[types.NamespacedName]map[string]string{requestKey: {"Secret/team-a-tls": "protected-tls"}}
+
+		Expect(isStaleCopy("Secret", copyOf("team-a-tls", "team-a-request"), expected, nil)).Should(BeFalse())
+		Expect(isStaleCopy("ConfigMap", copyOf("team-a-tls", "team-a-request"), expected, nil)).Should(BeTrue())
+		Expect(isStaleCopy("Secret", copyOf("team-a-old-tls", "team-a-request"), expected, nil)).Should(BeTrue())
+		Expect(isStaleCopy("Secret", copyOf("team-a-tls", "removed-request"), expected, nil)).Should(BeTrue())
+		Expect(isStaleCopy("Secret", copyOf("team-a-old-tls", "team-a-request"), expected, map[types.NamespacedName]bool{requestKey: true})).Should(BeFalse())
+		Expect(isStaleCopy("Secret", &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "team-a-tls", Namespace: "team-a"}}, expected, nil)).Should(BeFalse())
+	})
+})
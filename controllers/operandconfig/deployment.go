@@ -0,0 +1,109 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandconfig
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	odlmutil "github.com/IBM/operand-deployment-lifecycle-manager/pkg/util/v1"
+)
+
+// deploymentSummary collects the custom resources and the k8s resources rendered from the template of a service
+type deploymentSummary struct {
+	namespaces map[string]bool
+	// specs are the rendered specs of the custom resources, by <kind> <namespace>/<name>
+	specs   map[string]map[string]interface{}
+	drifted []string
+}
+
+func newDeploymentSummary() *deploymentSummary {
+	return &deploymentSummary{namespaces: make(map[string]bool), specs: make(map[string]map[string]interface{})}
+}
+
+// addResource records a live k8s resource of the service
+func (d *deploymentSummary) addResource(namespace string) {
+	d.namespaces[namespace] = true
+}
+
+// addCustomResource records the spec rendered for a custom resource of the service, and checks the live
+// custom resource against it. The live custom resource is nil when it isn't created yet.
+func (d *deploymentSummary) addCustomResource(kind, namespace, name string, rendered map[string]interface{}, live *unstructured.Unstructured) {
+	key := kind + " " + namespace + "/" + name
+	d.specs[key] = rendered
+	if live == nil {
+		return
+	}
+	d.namespaces[namespace] = true
+	liveSpec, _ := live.Object["spec"].(map[string]interface{})
+	if !specMatches(liveSpec, rendered) {
+		d.drifted = append(d.drifted, key)
+	}
+}
+
+// status returns the deployment status of the service
+func (d *deploymentSummary) status() operatorv1alpha1.ServiceDeployment {
+	deployment := operatorv1alpha1.ServiceDeployment{InSync: len(d.drifted) == 0}
+	for namespace := range d.namespaces {
+		deployment.Namespaces = append(deployment.Namespaces, namespace)
+	}
+	sort.Strings(deployment.Namespaces)
+	if len(d.specs) != 0 {
+		deployment.SpecHash = specHash(d.specs)
+	}
+	deployment.Drifted = append(deployment.Drifted, d.drifted...)
+	sort.Strings(deployment.Drifted)
+	return deployment
+}
+
+// setDeployment sets the deployment status of the service in the OperandConfig
+func setDeployment(instance *operatorv1alpha1.OperandConfig, service string, d *deploymentSummary) {
+	if instance.Status.Deployments == nil {
+		instance.Status.Deployments = make(map[string]operatorv1alpha1.ServiceDeployment)
+	}
+	instance.Status.Deployments[service] = d.status()
+}
+
+// renderedSpec returns the spec of the alm-example merged with the configuration of the service for its kind
+func renderedSpec(example unstructured.Unstructured, config []byte) map[string]interface{} {
+	specJSONString, _ := json.Marshal(example.Object["spec"])
+	return odlmutil.MergeCR(specJSONString, config)
+}
+
+// specMatches returns true if the live spec has all the fields of the rendered spec with the same values,
+// the fields the operators and the users add to the live spec are ignored
+func specMatches(live, rendered map[string]interface{}) bool {
+	liveRaw, _ := json.Marshal(live)
+	renderedRaw, _ := json.Marshal(rendered)
+	return reflect.DeepEqual(odlmutil.MergeCR(liveRaw, nil), odlmutil.MergeCR(liveRaw, renderedRaw))
+}
+
+// specHash returns the short hash of the rendered specs, the keys of the maps are marshaled in order
+func specHash(specs map[string]map[string]interface{}) string {
+	raw, err := json.Marshal(specs)
+	if err != nil {
+		return ""
+	}
+	hashed := sha256.Sum256(raw)
+	return hex.EncodeToString(hashed[:7])
+}
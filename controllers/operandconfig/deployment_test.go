@@ -0,0 +1,65 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandconfig
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Summarize the deployment of a service", func() {
+
+	example := func(spec map[string]interface{}) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{"kind": "EtcdCluster", "spec": spec}}
+	}
+
+	It("Should report the namespaces, the hash and the drifted custom resources", func() {
+		rendered := renderedSpec(example(map[string]interface{}{"size": int64(1), "version": "3.2.13"}), []byte(`{"size":3}`))
+		Expect(rendered).Should(Equal(map[string]interface{}{"size": float64(3), "version": "3.2.13"}))
+
+		d := newDeploymentSummary()
+		d.addResource("ibm-etcd")
+		d.addCustomResource("EtcdCluster", "ibm-operators", "example", rendered, &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"size": int64(3), "version": "3.2.13", "pod": map[string]interface{}{"antiAffinity": true}},
+		}})
+		status := d.status()
+		Expect(status.Namespaces).Should(Equal([]string{"ibm-etcd", "ibm-operators"}))
+		Expect(status.InSync).Should(BeTrue())
+		Expect(status.SpecHash).ShouldNot(BeEmpty())
+
+		// The hash changes with the rendered spec, and the live custom resource drifts from it
+		changed := renderedSpec(example(map[string]interface{}{"size": int64(1), "version": "3.2.13"}), []byte(`{"size":5}`))
+		d = newDeploymentSummary()
+		d.addCustomResource("EtcdCluster", "ibm-operators", "example", changed, &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"size": int64(3), "version": "3.2.13"},
+		}})
+		drifted := d.status()
+		Expect(drifted.SpecHash).ShouldNot(Equal(status.SpecHash))
+		Expect(drifted.InSync).Should(BeFalse())
+		Expect(drifted.Drifted).Should(Equal([]string{"EtcdCluster ibm-operators/example"}))
+	})
+
+	It("Should not check the custom resources not created yet", func() {
+		d := newDeploymentSummary()
+		d.addCustomResource("EtcdCluster", "ibm-operators", "example", map[string]interface{}{"size": float64(3)}, nil)
+		status := d.status()
+		Expect(status.Namespaces).Should(BeEmpty())
+		Expect(status.InSync).Should(BeTrue())
+		Expect(status.SpecHash).ShouldNot(BeEmpty())
+	})
+})
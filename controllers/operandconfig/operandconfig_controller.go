@@ -30,8 +30,8 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apimachinery/pkg/util/wait"
-	"k8s.io/klog"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -43,8 +43,13 @@ import (
 
 	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/crdschema"
 	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/scorecard"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/shard"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/throughput"
 )
 
 // Reconciler reconciles a OperandConfig object
@@ -58,13 +63,27 @@ type Reconciler struct {
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
+	ctx, log := logging.Reconcile(ctx, "operandconfig", "config", req.NamespacedName.String())
+
 	// Fetch the OperandConfig instance
 	instance := &operatorv1alpha1.OperandConfig{}
 	if err := r.Client.Get(ctx, req.NamespacedName, instance); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	klog.V(2).Infof("Reconciling OperandConfig: %s", req.NamespacedName)
+	// Skip the OperandConfig when its namespace opts out of ODLM
+	if instance.DeletionTimestamp.IsZero() {
+		excluded, err := r.IsNamespaceExcluded(ctx, instance.Namespace)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if excluded {
+			log.Info("Skip the OperandConfig in the excluded namespace", "label", constant.ExcludeNamespaceLabel)
+			return ctrl.Result{}, nil
+		}
+	}
+
+	log.V(logging.LevelFlow).Info("Reconciling OperandConfig")
 
 	originalInstance := instance.DeepCopy()
 
@@ -73,31 +92,41 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		if reflect.DeepEqual(originalInstance.Status, instance.Status) {
 			return
 		}
-		if err := r.Client.Status().Patch(ctx, instance, client.MergeFrom(originalInstance)); err != nil {
+		if err := r.PatchStatus(ctx, instance, originalInstance, func(latest client.Object) error {
+			return deploy.ReapplyStatus(&latest.(*operatorv1alpha1.OperandConfig).Status, &originalInstance.Status, &instance.Status)
+		}); err != nil {
 			reconcileErr = utilerrors.NewAggregate([]error{reconcileErr, fmt.Errorf("error while patching OperandConfig.Status: %v", err)})
 		}
 	}()
 
+	// Score the services against the best practices
+	if err := r.updateScorecard(ctx, instance); err != nil {
+		log.Error(err, "failed to update the scorecard for OperandConfig")
+		return ctrl.Result{}, err
+	}
+
 	// Update status of OperandConfig by checking CRs
 	if err := r.updateStatus(ctx, instance); err != nil {
-		klog.Errorf("failed to update the status for OperandConfig %s : %v", req.NamespacedName.String(), err)
+		log.Error(err, "failed to update the status for OperandConfig")
 		return ctrl.Result{}, err
 	}
 
 	// Check if all the services are deployed
 	if instance.Status.Phase != operatorv1alpha1.ServiceInit &&
 		instance.Status.Phase != operatorv1alpha1.ServiceRunning {
-		klog.V(2).Info("Waiting for all the services being deployed ...")
+		log.V(logging.LevelChange).Info("Waiting for all the services being deployed ...")
 		return ctrl.Result{RequeueAfter: constant.DefaultRequeueDuration}, nil
 	}
 
-	klog.V(2).Infof("Finished reconciling OperandConfig: %s", req.NamespacedName)
+	log.V(logging.LevelFlow).Info("Finished reconciling OperandConfig")
 	return ctrl.Result{}, nil
 }
 
 func (r *Reconciler) updateStatus(ctx context.Context, instance *operatorv1alpha1.OperandConfig) error {
+	log := logging.FromContext(ctx)
+
 	// Create an empty ServiceStatus map
-	klog.V(3).Info("Initializing OperandConfig status")
+	log.V(logging.LevelDebug).Info("Initializing OperandConfig status")
 
 	// Set the init status for OperandConfig instance
 	if instance.Status.Phase == "" {
@@ -106,6 +135,7 @@ func (r *Reconciler) updateStatus(ctx context.Context, instance *operatorv1alpha
 
 	originalStatus := deepcopy.Copy(instance.Status.ServiceStatus)
 	instance.Status.ServiceStatus = make(map[string]operatorv1alpha1.CrStatus)
+	instance.Status.Deployments = nil
 
 	registryInstance, err := r.GetOperandRegistry(ctx, types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace})
 	if err != nil {
@@ -115,6 +145,7 @@ func (r *Reconciler) updateStatus(ctx context.Context, instance *operatorv1alpha
 	for _, op := range registryInstance.Spec.Operators {
 
 		op := op
+		ctx, log := logging.WithValues(ctx, "operator", op.Name)
 
 		service := instance.GetService(op.Name)
 		if service == nil {
@@ -131,7 +162,7 @@ func (r *Reconciler) updateStatus(ctx context.Context, instance *operatorv1alpha
 		sub, err := r.GetSubscription(ctx, op.Name, namespace, op.PackageName)
 
 		if apierrors.IsNotFound(err) {
-			klog.V(3).Infof("There is no Subscription %s or %s in the namespace %s", op.Name, op.PackageName, namespace)
+			log.V(logging.LevelDebug).Info("There is no Subscription of the operator", "package", op.PackageName, "namespace", namespace)
 			continue
 		}
 
@@ -141,7 +172,7 @@ func (r *Reconciler) updateStatus(ctx context.Context, instance *operatorv1alpha
 
 		if _, ok := sub.Labels[constant.OpreqLabel]; !ok {
 			// Subscription existing and not managed by OperandRequest controller
-			klog.V(1).Infof("Subscription %s in the namespace %s isn't created by ODLM", sub.Name, sub.Namespace)
+			log.V(logging.LevelFlow).Info("Subscription isn't created by ODLM", "subscription", sub.Namespace+"/"+sub.Name)
 		}
 
 		csv, err := r.GetClusterServiceVersion(ctx, sub)
@@ -151,7 +182,7 @@ func (r *Reconciler) updateStatus(ctx context.Context, instance *operatorv1alpha
 		}
 
 		if csv == nil {
-			klog.Warningf("ClusterServiceVersion for the Subscription %s/%s doesn't exist, retry...", namespace, sub.Name)
+			log.Info("ClusterServiceVersion for the Subscription doesn't exist, retry...", "subscription", namespace+"/"+sub.Name)
 			continue
 		}
 
@@ -168,6 +199,7 @@ func (r *Reconciler) updateStatus(ctx context.Context, instance *operatorv1alpha
 		}
 
 		merr := &util.MultiErr{}
+		deployment := newDeploymentSummary()
 
 		// handle the deletion of k8s resources
 		k8sError := r.deleteK8sReousceFromStatus(ctx, originalStatus.(map[string]operatorv1alpha1.CrStatus), service, &op)
@@ -201,13 +233,15 @@ func (r *Reconciler) updateStatus(ctx context.Context, instance *operatorv1alpha
 				instance.Status.ServiceStatus[op.Name].CrStatus[resourceKey] = operatorv1alpha1.ServiceCreating
 			} else {
 				instance.Status.ServiceStatus[op.Name].CrStatus[resourceKey] = operatorv1alpha1.ServiceRunning
+				deployment.addResource(k8sNamespace)
 			}
 		}
 
 		// update the status for custom resources
 		almExamples := csv.ObjectMeta.Annotations["alm-examples"]
 		if almExamples == "" {
-			klog.Warningf("Notfound alm-examples in the ClusterServiceVersion %s/%s", csv.Namespace, csv.Name)
+			log.Info("Notfound alm-examples in the ClusterServiceVersion", "clusterServiceVersion", csv.Namespace+"/"+csv.Name)
+			setDeployment(instance, op.Name, deployment)
 			continue
 		}
 		// Create a slice for crTemplates
@@ -229,10 +263,12 @@ func (r *Reconciler) updateStatus(ctx context.Context, instance *operatorv1alpha
 			kind := unstruct.Object["kind"].(string)
 
 			existinConfig := false
-			for crName := range service.Spec {
+			var crConfig []byte
+			for crName, config := range service.Spec {
 				// Compare the name of OperandConfig and CRD name
 				if strings.EqualFold(kind, crName) {
 					existinConfig = true
+					crConfig = config.Raw
 				}
 			}
 
@@ -244,6 +280,7 @@ func (r *Reconciler) updateStatus(ctx context.Context, instance *operatorv1alpha
 			if name == "" {
 				continue
 			}
+			rendered := renderedSpec(unstruct, crConfig)
 
 			getError := r.Client.Get(ctx, types.NamespacedName{
 				Name:      name,
@@ -254,21 +291,72 @@ func (r *Reconciler) updateStatus(ctx context.Context, instance *operatorv1alpha
 				instance.Status.ServiceStatus[op.Name].CrStatus[kind] = operatorv1alpha1.ServiceFailed
 			} else if apierrors.IsNotFound(getError) {
 				instance.Status.ServiceStatus[op.Name].CrStatus[kind] = operatorv1alpha1.ServiceCreating
+				deployment.addCustomResource(kind, op.Namespace, name, rendered, nil)
 			} else {
 				instance.Status.ServiceStatus[op.Name].CrStatus[kind] = operatorv1alpha1.ServiceRunning
+				deployment.addCustomResource(kind, op.Namespace, name, rendered, &unstruct)
 			}
 		}
+		setDeployment(instance, op.Name, deployment)
 		if len(merr.Errors) != 0 {
 			return merr
 		}
 	}
 
-	klog.V(2).Info("Updating OperandConfig status")
+	log.V(logging.LevelChange).Info("Updating OperandConfig status")
 	instance.UpdateOperandPhase()
 
 	return nil
 }
 
+// updateScorecard evaluates the services against the best practices, and sets the scorecard in the status.
+// The custom resources are checked against the schemas of their CRDs once their operators are installed.
+func (r *Reconciler) updateScorecard(ctx context.Context, instance *operatorv1alpha1.OperandConfig) error {
+	// The OperandConfig configures the operators of the OperandRegistry with the same name
+	registryInstance := &operatorv1alpha1.OperandRegistry{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name}, registryInstance); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		registryInstance = nil
+	}
+
+	var services []operatorv1alpha1.ServiceScorecard
+	for i := range instance.Spec.Services {
+		service := &instance.Spec.Services[i]
+		schemaErrs, schemaKnown, err := r.validateServiceSchemas(ctx, i, service, registryInstance)
+		if err != nil {
+			return err
+		}
+		services = append(services, scorecard.Evaluate(i, service, schemaErrs, schemaKnown))
+	}
+	instance.Status.Scorecard = scorecard.Summarize(services)
+	logging.FromContext(ctx).V(logging.LevelDebug).Info("Scored the services of the OperandConfig", "score", instance.Status.Scorecard.Score)
+	return nil
+}
+
+// validateServiceSchemas validates the custom resources of the service against the schemas of their CRDs.
+// It returns false when the operator of the service isn't installed, so the schemas are unknown.
+func (r *Reconciler) validateServiceSchemas(ctx context.Context, index int, service *operatorv1alpha1.ConfigService, registryInstance *operatorv1alpha1.OperandRegistry) (field.ErrorList, bool, error) {
+	if registryInstance == nil || len(service.Spec) == 0 {
+		return nil, false, nil
+	}
+	opt := registryInstance.GetOperator(service.Name)
+	if opt == nil || opt.GetType() != operatorv1alpha1.OperatorTypeOLM {
+		return nil, false, nil
+	}
+	examples, err := r.GetALMExamples(ctx, registryInstance, opt)
+	if err != nil || examples == nil {
+		return nil, false, err
+	}
+
+	allErrs, err := crdschema.ValidateTemplates(ctx, r.Reader, r.Discovery, examples, service.Spec, field.NewPath("spec", "services").Index(index).Child("spec"))
+	if err != nil {
+		return nil, false, err
+	}
+	return allErrs, true, nil
+}
+
 // deleteK8sReousceFromStatus deletes the k8s resources from OperandConfig Status when they are not defined in OperandConfig Spec anymore
 func (r *Reconciler) deleteK8sReousceFromStatus(ctx context.Context, serviceStatus map[string]operatorv1alpha1.CrStatus, service *operatorv1alpha1.ConfigService, op *operatorv1alpha1.Operator) error {
 	merr := &util.MultiErr{}
@@ -316,6 +404,8 @@ func (r *Reconciler) deleteK8sReousceFromStatus(ctx context.Context, serviceStat
 }
 
 func (r *Reconciler) deleteK8sReousce(ctx context.Context, k8sAPIVersion, k8sKind, k8sName, k8sNamespace string) error {
+	log := logging.FromContext(ctx, "kind", k8sKind, "name", k8sNamespace+"/"+k8sName)
+
 	var k8sUnstruct unstructured.Unstructured
 	k8sUnstruct.SetAPIVersion(k8sAPIVersion)
 	k8sUnstruct.SetKind(k8sKind)
@@ -327,16 +417,16 @@ func (r *Reconciler) deleteK8sReousce(ctx context.Context, k8sAPIVersion, k8sKin
 	if k8sGetError != nil && !apierrors.IsNotFound(k8sGetError) {
 		return errors.Wrapf(k8sGetError, "failed to get k8s resource -- Kind: %s, NamespacedName: %s/%s", k8sKind, k8sNamespace, k8sName)
 	} else if apierrors.IsNotFound(k8sGetError) {
-		klog.V(3).Infof("There is no k8s resource -- Kind: %s, NamespacedName: %s/%s", k8sKind, k8sNamespace, k8sName)
+		log.V(logging.LevelDebug).Info("There is no k8s resource")
 	} else {
 		if r.CheckLabel(k8sUnstruct, map[string]string{constant.OpreqLabel: "true"}) {
-			klog.V(3).Infof("Deleting k8s resource -- Kind: %s, NamespacedName: %s/%s", k8sKind, k8sNamespace, k8sName)
+			log.V(logging.LevelChange).Info("Deleting k8s resource")
 			k8sDeleteError := r.Delete(ctx, &k8sUnstruct)
 			if k8sDeleteError != nil && !apierrors.IsNotFound(k8sDeleteError) {
 				return errors.Wrapf(k8sDeleteError, "failed to delete k8s resource -- Kind: %s, NamespacedName: %s/%s", k8sKind, k8sNamespace, k8sName)
 			}
 			waitErr := wait.PollImmediate(constant.DefaultCRDeletePeriod, constant.DefaultCRDeleteTimeout, func() (bool, error) {
-				klog.V(3).Infof("Waiting for k8s resource -- Kind: %s, NamespacedName: %s/%s removed ...", k8sKind, k8sNamespace, k8sName)
+				log.V(logging.LevelDebug).Info("Waiting for k8s resource removed ...")
 				err := r.Client.Get(ctx, types.NamespacedName{
 					Name:      k8sName,
 					Namespace: k8sNamespace,
@@ -352,7 +442,7 @@ func (r *Reconciler) deleteK8sReousce(ctx context.Context, k8sAPIVersion, k8sKin
 			if waitErr != nil {
 				return errors.Wrapf(waitErr, "failed to delete k8s resource -- Kind: %s, NamespacedName: %s/%s", k8sKind, k8sNamespace, k8sName)
 			}
-			klog.V(1).Infof("Finish deleting k8s resource -- Kind: %s, NamespacedName: %s/%s", k8sKind, k8sNamespace, k8sName)
+			log.V(logging.LevelFlow).Info("Finish deleting k8s resource")
 		}
 	}
 	return nil
@@ -397,7 +487,9 @@ func (r *Reconciler) getRequestToConfigMapper(ctx context.Context) handler.MapFu
 // SetupWithManager adds OperandConfig controller to the manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	ctx := context.Background()
+	fair := throughput.NewFairQueue("operandconfig")
 	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(throughput.ControllerOptions("operandconfig")).
 		For(&operatorv1alpha1.OperandConfig{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Watches(&source.Kind{Type: &operatorv1alpha1.OperandRequest{}}, handler.EnqueueRequestsFromMapFunc(r.getRequestToConfigMapper(ctx)), builder.WithPredicates(predicate.Funcs{
 			CreateFunc: func(e event.CreateEvent) bool {
@@ -412,5 +504,8 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 				newObject := e.ObjectNew.(*operatorv1alpha1.OperandRequest)
 				return !reflect.DeepEqual(oldObject.Status, newObject.Status)
 			},
-		})).Complete(r)
+		})).
+		// The requests are dispatched in turn across the namespaces by the fair queue
+		Watches(fair.Source(), &handler.EnqueueRequestForObject{}).
+		Complete(fair.Reconciler(shard.Reconciler(r)))
 }
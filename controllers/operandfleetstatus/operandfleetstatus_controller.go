@@ -0,0 +1,226 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandfleetstatus
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/shard"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/throughput"
+)
+
+// Reconciler reconciles the OperandFleetStatus object
+type Reconciler struct {
+	*deploy.ODLMOperator
+}
+
+// Reconcile keeps the OperandFleetStatus updated with a rollup of all the OperandRequests,
+// OperandRegistries and OperandConfigs in the cluster. It creates the OperandFleetStatus if it doesn't exist.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, log := logging.Reconcile(ctx, "operandfleetstatus", "fleetstatus", req.Name)
+
+	if req.Name != operatorv1alpha1.FleetStatusName {
+		log.V(logging.LevelChange).Info("Skip the OperandFleetStatus, ODLM only keeps " + operatorv1alpha1.FleetStatusName + " updated")
+		return ctrl.Result{}, nil
+	}
+
+	// Fetch the OperandFleetStatus instance
+	instance := &operatorv1alpha1.OperandFleetStatus{}
+	if err := r.Client.Get(ctx, req.NamespacedName, instance); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		instance.Name = operatorv1alpha1.FleetStatusName
+		log.V(logging.LevelChange).Info("Creating the OperandFleetStatus")
+		if err := r.Client.Create(ctx, instance); err != nil && !apierrors.IsAlreadyExists(err) {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to create the OperandFleetStatus %s", instance.Name)
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	if !instance.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	log.V(logging.LevelFlow).Info("Reconciling OperandFleetStatus")
+
+	status, err := r.rollup(ctx, instance.GetMaxItems())
+	if err != nil {
+		log.Error(err, "failed to roll up the OperandFleetStatus")
+		return ctrl.Result{}, err
+	}
+
+	// Only the changes of the rollup update the status, the lastUpdateTime is not compared
+	status.LastUpdateTime = instance.Status.LastUpdateTime
+	if !reflect.DeepEqual(instance.Status, *status) {
+		originalInstance := instance.DeepCopy()
+		now := metav1.Now()
+		status.LastUpdateTime = &now
+		instance.Status = *status
+		if err := r.PatchStatus(ctx, instance, originalInstance, func(latest client.Object) error {
+			latest.(*operatorv1alpha1.OperandFleetStatus).Status = *status.DeepCopy()
+			return nil
+		}); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to patch the status of the OperandFleetStatus %s", req.Name)
+		}
+	}
+
+	log.V(logging.LevelFlow).Info("Finished reconciling OperandFleetStatus")
+
+	return ctrl.Result{RequeueAfter: constant.DefaultSyncPeriod}, nil
+}
+
+// rollup summarizes the ODLM resources of the cluster
+func (r *Reconciler) rollup(ctx context.Context, maxItems int) (*operatorv1alpha1.OperandFleetStatusStatus, error) {
+	status := &operatorv1alpha1.OperandFleetStatusStatus{}
+
+	requestList := &operatorv1alpha1.OperandRequestList{}
+	if err := r.Client.List(ctx, requestList); err != nil {
+		return nil, errors.Wrap(err, "failed to list the OperandRequests")
+	}
+	sort.Slice(requestList.Items, func(i, j int) bool {
+		return requestKey(&requestList.Items[i]) < requestKey(&requestList.Items[j])
+	})
+	for _, request := range requestList.Items {
+		status.OperandRequests.Add(string(request.Status.Phase))
+		for _, member := range request.Status.Members {
+			if member.Phase.OperatorPhase != operatorv1alpha1.OperatorFailed && member.Phase.OperandPhase != operatorv1alpha1.ServiceFailed {
+				continue
+			}
+			status.FailingMemberCount++
+			if len(status.FailingMembers) < maxItems {
+				status.FailingMembers = append(status.FailingMembers, operatorv1alpha1.FleetFailingMember{
+					Request:       requestKey(&request),
+					Name:          member.Name,
+					OperatorPhase: member.Phase.OperatorPhase,
+					OperandPhase:  member.Phase.OperandPhase,
+				})
+			}
+		}
+	}
+
+	registryList := &operatorv1alpha1.OperandRegistryList{}
+	if err := r.Client.List(ctx, registryList); err != nil {
+		return nil, errors.Wrap(err, "failed to list the OperandRegistries")
+	}
+	for _, registry := range registryList.Items {
+		status.OperandRegistries.Add(string(registry.Status.Phase))
+	}
+
+	configList := &operatorv1alpha1.OperandConfigList{}
+	if err := r.Client.List(ctx, configList); err != nil {
+		return nil, errors.Wrap(err, "failed to list the OperandConfigs")
+	}
+	for _, config := range configList.Items {
+		status.OperandConfigs.Add(string(config.Status.Phase))
+	}
+
+	subList, err := r.ListManagedSubscriptions(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the Subscriptions")
+	}
+	sort.Slice(subList.Items, func(i, j int) bool {
+		return subList.Items[i].Namespace+"/"+subList.Items[i].Name < subList.Items[j].Namespace+"/"+subList.Items[j].Name
+	})
+	for _, sub := range subList.Items {
+		if !isUpgradePending(&sub) {
+			continue
+		}
+		status.PendingUpgradeCount++
+		if len(status.PendingUpgrades) < maxItems {
+			status.PendingUpgrades = append(status.PendingUpgrades, operatorv1alpha1.FleetPendingUpgrade{
+				Name:         sub.Name,
+				Namespace:    sub.Namespace,
+				State:        string(sub.Status.State),
+				InstalledCSV: sub.Status.InstalledCSV,
+				CurrentCSV:   sub.Status.CurrentCSV,
+			})
+		}
+	}
+
+	return status, nil
+}
+
+// isUpgradePending returns true if the Subscription has an upgrade available or waiting for approval
+func isUpgradePending(sub *olmv1alpha1.Subscription) bool {
+	return sub.Status.State == olmv1alpha1.SubscriptionStateUpgradeAvailable || sub.Status.State == olmv1alpha1.SubscriptionStateUpgradePending
+}
+
+func requestKey(request *operatorv1alpha1.OperandRequest) string {
+	return request.Namespace + "/" + request.Name
+}
+
+// toFleetStatus maps every event to the OperandFleetStatus kept updated by ODLM
+func toFleetStatus(object client.Object) []ctrl.Request {
+	return []ctrl.Request{{NamespacedName: types.NamespacedName{Name: operatorv1alpha1.FleetStatusName}}}
+}
+
+// SetupWithManager adds OperandFleetStatus controller to the manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Create the OperandFleetStatus when the controller starts, even if there is no ODLM resource yet
+	bootstrap := make(chan event.GenericEvent, 1)
+	fleet := &operatorv1alpha1.OperandFleetStatus{}
+	fleet.Name = operatorv1alpha1.FleetStatusName
+	bootstrap <- event.GenericEvent{Object: fleet}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(throughput.ControllerOptions("operandfleetstatus")).
+		For(&operatorv1alpha1.OperandFleetStatus{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Channel{Source: bootstrap}, &handler.EnqueueRequestForObject{}).
+		Watches(&source.Kind{Type: &operatorv1alpha1.OperandRequest{}}, handler.EnqueueRequestsFromMapFunc(toFleetStatus)).
+		Watches(&source.Kind{Type: &operatorv1alpha1.OperandRegistry{}}, handler.EnqueueRequestsFromMapFunc(toFleetStatus)).
+		Watches(&source.Kind{Type: &operatorv1alpha1.OperandConfig{}}, handler.EnqueueRequestsFromMapFunc(toFleetStatus)).
+		Watches(&source.Kind{Type: &olmv1alpha1.Subscription{}}, handler.EnqueueRequestsFromMapFunc(toFleetStatus), builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool {
+				return e.Object.GetLabels()[constant.OpreqLabel] == "true"
+			},
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				if e.ObjectNew.GetLabels()[constant.OpreqLabel] != "true" {
+					return false
+				}
+				oldObject := e.ObjectOld.(*olmv1alpha1.Subscription)
+				newObject := e.ObjectNew.(*olmv1alpha1.Subscription)
+				return oldObject.Status.State != newObject.Status.State
+			},
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				return e.Object.GetLabels()[constant.OpreqLabel] == "true"
+			},
+			GenericFunc: func(e event.GenericEvent) bool {
+				return false
+			},
+		})).
+		Complete(shard.Reconciler(r))
+}
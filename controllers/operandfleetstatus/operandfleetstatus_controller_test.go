@@ -0,0 +1,118 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandfleetstatus
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	testutil "github.com/IBM/operand-deployment-lifecycle-manager/controllers/testutil"
+)
+
+// +kubebuilder:docs-gen:collapse=Imports
+
+var _ = Describe("OperandFleetStatus controller", func() {
+	const (
+		name              = "common-service"
+		namespace         = "ibm-common-services"
+		requestName       = "ibm-cloudpak-name"
+		requestNamespace  = "ibm-cloudpak"
+		operatorNamespace = "ibm-operators"
+	)
+
+	var (
+		ctx context.Context
+
+		namespaceName         string
+		operatorNamespaceName string
+		requestNamespaceName  string
+
+		registry *operatorv1alpha1.OperandRegistry
+		config   *operatorv1alpha1.OperandConfig
+		request  *operatorv1alpha1.OperandRequest
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		namespaceName = testutil.CreateNSName(namespace)
+		operatorNamespaceName = testutil.CreateNSName(operatorNamespace)
+		requestNamespaceName = testutil.CreateNSName(requestNamespace)
+		registry = testutil.OperandRegistryObj(name, namespaceName, operatorNamespaceName)
+		config = testutil.OperandConfigObj(name, namespaceName)
+		request = testutil.OperandRequestObj(name, namespaceName, requestName, requestNamespaceName)
+
+		By("Creating the Namespace")
+		Expect(k8sClient.Create(ctx, testutil.NamespaceObj(namespaceName))).Should(Succeed())
+		Expect(k8sClient.Create(ctx, testutil.NamespaceObj(operatorNamespaceName))).Should(Succeed())
+		Expect(k8sClient.Create(ctx, testutil.NamespaceObj(requestNamespaceName))).Should(Succeed())
+
+		By("Creating the OperandRegistry")
+		Expect(k8sClient.Create(ctx, registry)).Should(Succeed())
+		By("Creating the OperandConfig")
+		Expect(k8sClient.Create(ctx, config)).Should(Succeed())
+		By("Creating the OperandRequest")
+		Expect(k8sClient.Create(ctx, request)).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		By("Deleting the OperandRequest")
+		Expect(k8sClient.Delete(ctx, request)).Should(Succeed())
+		By("Deleting the OperandConfig")
+		Expect(k8sClient.Delete(ctx, config)).Should(Succeed())
+		By("Deleting the OperandRegistry")
+		Expect(k8sClient.Delete(ctx, registry)).Should(Succeed())
+	})
+
+	Context("Rolling up the ODLM resources", func() {
+		It("Should count the resources and list the failing members", func() {
+			fleetKey := types.NamespacedName{Name: operatorv1alpha1.FleetStatusName}
+
+			By("Checking the rollup of the OperandFleetStatus")
+			fleet := &operatorv1alpha1.OperandFleetStatus{}
+			Eventually(func() int32 {
+				Expect(k8sClient.Get(ctx, fleetKey, fleet)).Should(Succeed())
+				return fleet.Status.OperandRequests.Total
+			}, timeout, interval).Should(Equal(int32(1)))
+			Expect(fleet.Status.OperandRegistries.Total).Should(Equal(int32(1)))
+			Expect(fleet.Status.OperandConfigs.Total).Should(Equal(int32(1)))
+			Expect(fleet.Status.LastUpdateTime).ShouldNot(BeNil())
+
+			By("Failing a member of the OperandRequest")
+			requestInstance := &operatorv1alpha1.OperandRequest{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: requestName, Namespace: requestNamespaceName}, requestInstance)).Should(Succeed())
+			requestInstance.Status.Phase = operatorv1alpha1.ClusterPhaseFailed
+			requestInstance.Status.Members = []operatorv1alpha1.MemberStatus{
+				{Name: "etcd", Phase: operatorv1alpha1.MemberPhase{OperatorPhase: operatorv1alpha1.OperatorFailed}},
+				{Name: "jenkins", Phase: operatorv1alpha1.MemberPhase{OperatorPhase: operatorv1alpha1.OperatorRunning}},
+			}
+			Expect(k8sClient.Status().Update(ctx, requestInstance)).Should(Succeed())
+
+			Eventually(func() int32 {
+				Expect(k8sClient.Get(ctx, fleetKey, fleet)).Should(Succeed())
+				return fleet.Status.FailingMemberCount
+			}, timeout, interval).Should(Equal(int32(1)))
+			Expect(fleet.Status.OperandRequests.Phases).Should(HaveKeyWithValue(string(operatorv1alpha1.ClusterPhaseFailed), int32(1)))
+			Expect(fleet.Status.FailingMembers).Should(Equal([]operatorv1alpha1.FleetFailingMember{
+				{Request: requestNamespaceName + "/" + requestName, Name: "etcd", OperatorPhase: operatorv1alpha1.OperatorFailed},
+			}))
+		})
+	})
+})
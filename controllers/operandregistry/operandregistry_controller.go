@@ -20,10 +20,14 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
-	"k8s.io/klog"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,7 +38,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/mirror"
 	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/shard"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/throughput"
+	odlmutil "github.com/IBM/operand-deployment-lifecycle-manager/pkg/util/v1"
 )
 
 // Reconciler reconciles a OperandRegistry object
@@ -48,12 +58,26 @@ type Reconciler struct {
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
+	ctx, log := logging.Reconcile(ctx, "operandregistry", "registry", req.NamespacedName.String())
+
 	// Fetch the OperandRegistry instance
 	instance := &operatorv1alpha1.OperandRegistry{}
 	if err := r.Client.Get(ctx, req.NamespacedName, instance); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// Skip the OperandRegistry when its namespace opts out of ODLM
+	if instance.DeletionTimestamp.IsZero() {
+		excluded, err := r.IsNamespaceExcluded(ctx, instance.Namespace)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if excluded {
+			log.Info("Skip the OperandRegistry in the excluded namespace", "label", constant.ExcludeNamespaceLabel)
+			return ctrl.Result{}, nil
+		}
+	}
+
 	originalInstance := instance.DeepCopy()
 
 	// Always attempt to patch the status after each reconciliation.
@@ -61,28 +85,82 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		if reflect.DeepEqual(originalInstance.Status, instance.Status) {
 			return
 		}
-		if err := r.Client.Status().Patch(ctx, instance, client.MergeFrom(originalInstance)); err != nil {
+		// Many OperandRequests trigger the reconcile of the same OperandRegistry, the status is computed
+		// again from the latest OperandRegistry when it was updated in the meantime
+		if err := r.PatchStatus(ctx, instance, originalInstance, func(latest client.Object) error {
+			return r.summarizeStatus(ctx, latest.(*operatorv1alpha1.OperandRegistry))
+		}); err != nil {
 			reconcileErr = utilerrors.NewAggregate([]error{reconcileErr, fmt.Errorf("error while patching OperandRegistry.Status: %v", err)})
 		}
 	}()
 
-	klog.V(2).Infof("Reconciling OperandRegistry: %s", req.NamespacedName)
+	log.V(logging.LevelFlow).Info("Reconciling OperandRegistry")
 
 	// Update all the operator status
-	if err := r.updateStatus(ctx, instance); err != nil {
-		klog.Errorf("failed to update the status for OperandRegistry %s : %v", req.NamespacedName.String(), err)
+	if err := r.summarizeStatus(ctx, instance); err != nil {
+		log.Error(err, "failed to update the status for OperandRegistry")
 		return ctrl.Result{}, err
 	}
 
+	log.V(logging.LevelFlow).Info("Finished reconciling OperandRegistry")
+
+	return ctrl.Result{}, nil
+}
+
+// summarizeStatus updates the operator status and the phase of the OperandRegistry from its OperandRequests
+func (r *Reconciler) summarizeStatus(ctx context.Context, instance *operatorv1alpha1.OperandRegistry) error {
+	if err := r.updateStatus(ctx, instance); err != nil {
+		return err
+	}
+	waiting, err := r.updateCatalogStatus(ctx, instance)
+	if err != nil {
+		return err
+	}
+
 	// Summarize instance status
 	if instance.Status.OperatorsStatus == nil || len(instance.Status.OperatorsStatus) == 0 {
 		instance.UpdateRegistryPhase(operatorv1alpha1.RegistryReady)
+	} else if waiting {
+		instance.UpdateRegistryPhase(operatorv1alpha1.RegistryWaiting)
 	} else {
 		instance.UpdateRegistryPhase(operatorv1alpha1.RegistryRunning)
 	}
+	return nil
+}
 
-	klog.V(2).Infof("Finished reconciling OperandRegistry: %s", req.NamespacedName)
-	return ctrl.Result{}, nil
+// updateCatalogStatus reports the CatalogSources which can't serve the operators of the OperandRegistry,
+// and returns true if a requested operator waits for its CatalogSource
+func (r *Reconciler) updateCatalogStatus(ctx context.Context, instance *operatorv1alpha1.OperandRegistry) (bool, error) {
+	checked := make(map[string]string)
+	unhealthy := make(map[string]string)
+	waiting := false
+	for _, o := range instance.Spec.Operators {
+		// The operators are installed from the mirrors of the disconnected cluster
+		o := o.DeepCopy()
+		mirror.Rewrite(o)
+		// The CatalogSource of the operator is resolved from the PackageManifests when it isn't set
+		if o.GetType() != operatorv1alpha1.OperatorTypeOLM || o.SourceName == "" || o.SourceNamespace == "" {
+			continue
+		}
+		catalog := o.SourceNamespace + "/" + o.SourceName
+		message, ok := checked[catalog]
+		if !ok {
+			var err error
+			if message, err = r.GetCatalogSourceHealth(ctx, o.SourceName, o.SourceNamespace); err != nil {
+				return false, err
+			}
+			checked[catalog] = message
+		}
+		if message == "" {
+			continue
+		}
+		unhealthy[catalog] = message
+		if _, requested := instance.Status.OperatorsStatus[o.Name]; requested {
+			waiting = true
+		}
+	}
+	instance.SetCatalogUnhealthyConditions(unhealthy)
+	return waiting, nil
 }
 
 func (r *Reconciler) updateStatus(ctx context.Context, instance *operatorv1alpha1.OperandRegistry) error {
@@ -93,8 +171,10 @@ func (r *Reconciler) updateStatus(ctx context.Context, instance *operatorv1alpha
 		return err
 	}
 
-	// Create an empty OperatorsStatus map
+	// Create an empty OperatorsStatus map, the transition times are kept from the previous status
+	previous := instance.Status.OperatorsStatus
 	instance.Status.OperatorsStatus = make(map[string]operatorv1alpha1.OperatorStatus)
+	phases := make(map[string]operatorv1alpha1.OperatorPhase)
 	// Update OperandRegistry status from the OperandRequest list
 	for _, item := range requestList {
 		requestKey := types.NamespacedName{Name: item.Name, Namespace: item.Namespace}
@@ -104,17 +184,75 @@ func (r *Reconciler) updateStatus(ctx context.Context, instance *operatorv1alpha
 			if registryKey.Name != instance.Name || registryKey.Namespace != instance.Namespace {
 				continue
 			}
-			for _, operand := range req.Operands {
-				instance.SetOperatorStatus(operand.Name, "", reconcile.Request{NamespacedName: requestKey})
+			for _, operand := range instance.WithRequiredOperands(req.Operands) {
+				phases[operand.Name] = mergeOperatorPhase(phases[operand.Name], memberOperatorPhase(&item, operand.Name))
+				instance.SetOperatorStatus(operand.Name, phases[operand.Name], reconcile.Request{NamespacedName: requestKey})
 			}
 		}
 	}
+
+	now := time.Now()
+	for name, s := range instance.Status.OperatorsStatus {
+		s.SummarizeRequests()
+		if err := r.setSubscriptionState(ctx, instance, name, &s); err != nil {
+			return err
+		}
+		var p *operatorv1alpha1.OperatorStatus
+		if previousStatus, ok := previous[name]; ok {
+			p = &previousStatus
+		}
+		s.KeepTransitionTime(p, now)
+		instance.Status.OperatorsStatus[name] = s
+	}
+	return nil
+}
+
+// setSubscriptionState sets the installed CSV of the operator and the health of its Subscription,
+// the operators not installed by OLM have no Subscription
+func (r *Reconciler) setSubscriptionState(ctx context.Context, instance *operatorv1alpha1.OperandRegistry, name string, s *operatorv1alpha1.OperatorStatus) error {
+	opt := instance.GetOperator(name)
+	if opt == nil || opt.GetType() != operatorv1alpha1.OperatorTypeOLM {
+		return nil
+	}
+	namespace := r.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
+	sub, err := r.GetSubscription(ctx, opt.Name, namespace, opt.PackageName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to get the Subscription %s in the namespace %s", opt.Name, namespace)
+	}
+	s.SubscriptionHealth, s.Message = deploy.GetSubscriptionHealth(sub)
+	if sub != nil {
+		s.InstalledCSV = sub.Status.InstalledCSV
+		if v, ok := odlmutil.CSVVersion(s.InstalledCSV); ok {
+			s.Version = v.String()
+		}
+	}
 	return nil
 }
 
+// memberOperatorPhase returns the phase of the operator in the status of the OperandRequest
+func memberOperatorPhase(requestInstance *operatorv1alpha1.OperandRequest, name string) operatorv1alpha1.OperatorPhase {
+	for _, m := range requestInstance.Status.Members {
+		if m.Name == name {
+			return m.Phase.OperatorPhase
+		}
+	}
+	return ""
+}
+
+// mergeOperatorPhase returns the phase of the operator reported by the OperandRequests,
+// the first one reported unless another OperandRequest reports it Failed
+func mergeOperatorPhase(current, phase operatorv1alpha1.OperatorPhase) operatorv1alpha1.OperatorPhase {
+	if current == "" || phase == operatorv1alpha1.OperatorFailed {
+		return phase
+	}
+	return current
+}
+
 // SetupWithManager adds OperandRegistry controller to the manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	fair := throughput.NewFairQueue("operandregistry")
 	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(throughput.ControllerOptions("operandregistry")).
 		For(&operatorv1alpha1.OperandRegistry{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Watches(&source.Kind{Type: &operatorv1alpha1.OperandRequest{}}, handler.EnqueueRequestsFromMapFunc(func(a client.Object) []reconcile.Request {
 			or := a.(*operatorv1alpha1.OperandRequest)
@@ -129,5 +267,61 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 				// Evaluates to false if the object has been confirmed deleted.
 				return !e.DeleteStateUnknown
 			},
-		})).Complete(r)
+		})).
+		Watches(&source.Kind{Type: &olmv1alpha1.CatalogSource{}}, handler.EnqueueRequestsFromMapFunc(r.getCatalogSourceToRegistryMapper()), builder.WithPredicates(predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return deploy.CatalogSourceStateChanged(e.ObjectOld, e.ObjectNew)
+			},
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				return !e.DeleteStateUnknown
+			},
+		})).
+		Watches(&source.Kind{Type: &olmv1alpha1.Subscription{}}, handler.EnqueueRequestsFromMapFunc(subscriptionToRegistries), builder.WithPredicates(predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				oldObject := e.ObjectOld.(*olmv1alpha1.Subscription)
+				newObject := e.ObjectNew.(*olmv1alpha1.Subscription)
+				return oldObject.Status.InstalledCSV != newObject.Status.InstalledCSV ||
+					oldObject.Status.State != newObject.Status.State ||
+					!reflect.DeepEqual(oldObject.Status.Conditions, newObject.Status.Conditions)
+			},
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				return !e.DeleteStateUnknown
+			},
+		})).
+		// The requests are dispatched in turn across the namespaces by the fair queue
+		Watches(fair.Source(), &handler.EnqueueRequestForObject{}).
+		Complete(fair.Reconciler(shard.Reconciler(r)))
+}
+
+// subscriptionToRegistries maps a Subscription to the OperandRegistries recorded in its
+// <namespace>.<name>/registry annotations, to refresh the status of their operators
+func subscriptionToRegistries(object client.Object) []reconcile.Request {
+	requests := []reconcile.Request{}
+	for anno := range object.GetAnnotations() {
+		if !strings.HasSuffix(anno, "/registry") {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimSuffix(anno, "/registry"), ".", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: parts[0], Name: parts[1]}})
+	}
+	return requests
+}
+
+// getCatalogSourceToRegistryMapper maps a CatalogSource to the OperandRegistries installing operators from it
+func (r *Reconciler) getCatalogSourceToRegistryMapper() handler.MapFunc {
+	ctx := context.Background()
+	return func(object client.Object) []reconcile.Request {
+		registries, err := r.ListOperandRegistriesByCatalogSource(ctx, object.GetName(), object.GetNamespace())
+		if err != nil {
+			return nil
+		}
+		requests := []reconcile.Request{}
+		for _, registry := range registries {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: registry.Name, Namespace: registry.Namespace}})
+		}
+		return requests
+	}
 }
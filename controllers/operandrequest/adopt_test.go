@@ -0,0 +1,72 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/ownership"
+)
+
+var _ = Describe("Adopt the existing custom resources", func() {
+
+	r := &Reconciler{ODLMOperator: &deploy.ODLMOperator{}}
+
+	newCR := func(labels, annotations map[string]string) unstructured.Unstructured {
+		cr := unstructured.Unstructured{}
+		cr.SetAPIVersion("etcd.database.coreos.com/v1beta2")
+		cr.SetKind("EtcdCluster")
+		cr.SetNamespace("ibm-common-services")
+		cr.SetName("example")
+		cr.SetLabels(labels)
+		cr.SetAnnotations(annotations)
+		return cr
+	}
+
+	It("Should only release the custom resources adopted by ODLM", func() {
+		Expect(r.isAdopted(newCR(map[string]string{constant.OpreqLabel: "true"}, map[string]string{constant.AdoptAnnotation: "true"}))).Should(BeTrue())
+		Expect(r.isAdopted(newCR(map[string]string{constant.OpreqLabel: "true"}, nil))).Should(BeFalse())
+		Expect(r.isAdopted(newCR(nil, map[string]string{constant.AdoptAnnotation: "true"}))).Should(BeFalse())
+		Expect(r.isAdopted(newCR(map[string]string{constant.OpreqLabel: "true"}, map[string]string{constant.AdoptAnnotation: "false"}))).Should(BeFalse())
+	})
+
+	It("Should only match the annotations of the OperandRequests", func() {
+		Expect(ownership.IsKey("ibm-common-services.example/request")).Should(BeTrue())
+		Expect(ownership.IsKey("ibm-common-services.example/registry")).Should(BeFalse())
+		Expect(ownership.IsKey(constant.AdoptAnnotation)).Should(BeFalse())
+	})
+
+	It("Should set and remove the Conflict condition", func() {
+		req := &operatorv1alpha1.OperandRequest{}
+		mu := &sync.Mutex{}
+		req.SetConflictCondition("EtcdCluster ibm-common-services/example", operatorv1alpha1.ResourceTypeOperand, corev1.ConditionTrue, mu)
+		req.SetConflictCondition("EtcdCluster ibm-common-services/example", operatorv1alpha1.ResourceTypeOperand, corev1.ConditionTrue, mu)
+		Expect(req.Status.Conditions).Should(HaveLen(1))
+		Expect(req.Status.Conditions[0].Type).Should(Equal(operatorv1alpha1.ConditionConflict))
+
+		req.RemoveConflictCondition("EtcdCluster ibm-common-services/example", operatorv1alpha1.ResourceTypeOperand, mu)
+		Expect(req.Status.Conditions).Should(BeEmpty())
+	})
+})
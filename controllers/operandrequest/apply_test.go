@@ -0,0 +1,75 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+)
+
+var _ = Describe("Server-side apply the custom resources", func() {
+
+	It("Should only apply the fields managed by ODLM", func() {
+		var existing unstructured.Unstructured
+		existing.SetAPIVersion("etcd.database.coreos.com/v1beta2")
+		existing.SetKind("EtcdCluster")
+		existing.SetName("example")
+		existing.SetNamespace("default")
+		existing.SetResourceVersion("42")
+		existing.SetUID("c0ffee")
+		existing.SetLabels(map[string]string{"app": "etcd"})
+		existing.Object["spec"] = map[string]interface{}{"size": int64(3), "version": "3.2.13"}
+		existing.Object["status"] = map[string]interface{}{"phase": "Running"}
+
+		applied := appliedCustomResource(existing, "ibm-common-services", map[string]interface{}{"size": int64(1)})
+
+		Expect(applied.Object).Should(Equal(map[string]interface{}{
+			"apiVersion": "etcd.database.coreos.com/v1beta2",
+			"kind":       "EtcdCluster",
+			"metadata": map[string]interface{}{
+				"name":      "example",
+				"namespace": "ibm-common-services",
+				"labels":    map[string]interface{}{"app": "etcd"},
+			},
+			"spec": map[string]interface{}{"size": int64(1)},
+		}))
+	})
+
+	It("Should only protect the spec paths of the partially managed custom resources", func() {
+		var cr unstructured.Unstructured
+		cr.SetAnnotations(map[string]string{
+			constant.ProtectedPathsAnnotation: "spec.replicas, .spec.storage.size,metadata.labels,spec.,status",
+		})
+		Expect(protectedPaths(cr)).Should(BeEmpty())
+
+		cr.SetAnnotations(map[string]string{
+			constant.ManageAnnotation:         constant.ManagePartial,
+			constant.ProtectedPathsAnnotation: "spec.replicas, .spec.storage.size,metadata.labels,spec.,status",
+		})
+		Expect(protectedPaths(cr)).Should(Equal([]string{"replicas", "storage.size"}))
+	})
+
+	It("Should build the merge patch removing the pruned fields", func() {
+		patch, err := prunePatch([]string{"greetings.second", "greetings.third", "name"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(string(patch)).Should(Equal(`{"spec":{"greetings":{"second":null,"third":null},"name":null}}`))
+	})
+})
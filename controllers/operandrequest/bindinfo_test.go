@@ -0,0 +1,104 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+)
+
+var _ = Describe("Deny the bindings to the namespaces not allowed", func() {
+
+	newReconciler := func(bindInfo *operatorv1alpha1.OperandBindInfo, namespaces ...*corev1.Namespace) *Reconciler {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(operatorv1alpha1.AddToScheme(scheme))
+		utilruntime.Must(corev1.AddToScheme(scheme))
+		builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(bindInfo)
+		for _, ns := range namespaces {
+			builder = builder.WithObjects(ns)
+		}
+		c := builder.Build()
+		return &Reconciler{ODLMOperator: &deploy.ODLMOperator{Client: c, Reader: c, Scheme: scheme}}
+	}
+
+	bindInfo := &operatorv1alpha1.OperandBindInfo{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ibm-iam-bindinfo",
+			Namespace: "ibm-common-services",
+			Labels:    map[string]string{"ibm-common-services.common-service/registry": "true"},
+		},
+		Spec: operatorv1alpha1.OperandBindInfoSpec{
+			Operand:           "ibm-iam-operator",
+			Registry:          "common-service",
+			RegistryNamespace: "ibm-common-services",
+			AllowedNamespaces: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "platform"}},
+		},
+	}
+
+	newRequest := func(namespace string) *operatorv1alpha1.OperandRequest {
+		return &operatorv1alpha1.OperandRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "iam", Namespace: namespace},
+			Spec: operatorv1alpha1.OperandRequestSpec{Requests: []operatorv1alpha1.Request{{
+				Registry:          "common-service",
+				RegistryNamespace: "ibm-common-services",
+				Operands:          []operatorv1alpha1.Operand{{Name: "ibm-iam-operator"}, {Name: "ibm-mongodb-operator"}},
+			}}},
+		}
+	}
+
+	hasPermissionDenied := func(requestInstance *operatorv1alpha1.OperandRequest, operand string) bool {
+		for _, c := range requestInstance.Status.Conditions {
+			if c.Type == operatorv1alpha1.ConditionPermissionDenied && c.Reason == string(operatorv1alpha1.ResourceTypeOperand)+" "+operand+" permission is denied" {
+				return true
+			}
+		}
+		return false
+	}
+
+	It("Should deny the namespaces not matching the allowedNamespaces", func() {
+		r := newReconciler(bindInfo, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}})
+		requestInstance := newRequest("team-a")
+		Expect(r.reconcileBindInfoPermissions(context.Background(), requestInstance)).To(Succeed())
+		Expect(hasPermissionDenied(requestInstance, "ibm-iam-operator")).To(BeTrue())
+		Expect(hasPermissionDenied(requestInstance, "ibm-mongodb-operator")).To(BeFalse())
+	})
+
+	It("Should allow the namespaces matching the allowedNamespaces", func() {
+		r := newReconciler(bindInfo, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"tier": "platform"}}})
+		requestInstance := newRequest("team-b")
+		requestInstance.SetPermissionDeniedCondition("ibm-iam-operator", "ibm-common-services/ibm-iam-bindinfo", operatorv1alpha1.ResourceTypeOperand, corev1.ConditionTrue, &r.Mutex)
+		Expect(r.reconcileBindInfoPermissions(context.Background(), requestInstance)).To(Succeed())
+		Expect(hasPermissionDenied(requestInstance, "ibm-iam-operator")).To(BeFalse())
+	})
+
+	It("Should always allow the namespace of the OperandBindInfo", func() {
+		r := newReconciler(bindInfo)
+		requestInstance := newRequest("ibm-common-services")
+		Expect(r.reconcileBindInfoPermissions(context.Background(), requestInstance)).To(Succeed())
+		Expect(hasPermissionDenied(requestInstance, "ibm-iam-operator")).To(BeFalse())
+	})
+})
@@ -0,0 +1,84 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Coexist with the previous version of an operator", func() {
+
+	newOperator := func() *operatorv1alpha1.Operator {
+		return &operatorv1alpha1.Operator{
+			Name:            "etcd",
+			Namespace:       "etcd-v2",
+			InstallMode:     operatorv1alpha1.InstallModeCluster,
+			PackageName:     "etcd",
+			Channel:         "v2",
+			SourceName:      "community-operators",
+			SourceNamespace: "openshift-marketplace",
+			Coexist:         true,
+			PreviousVersion: &operatorv1alpha1.OperatorVersion{
+				Namespace: "etcd-v1",
+				Channel:   "v1",
+			},
+		}
+	}
+
+	It("Should return the previous version of a coexisting operator", func() {
+		previous := newOperator().GetPreviousVersion()
+		Expect(previous).ShouldNot(BeNil())
+		Expect(previous.Name).Should(Equal("etcd"))
+		Expect(previous.Namespace).Should(Equal("etcd-v1"))
+		Expect(previous.Channel).Should(Equal("v1"))
+		Expect(previous.InstallMode).Should(Equal(operatorv1alpha1.InstallModeNamespace))
+		Expect(previous.SourceName).Should(Equal("community-operators"))
+		Expect(previous.GetPreviousVersion()).Should(BeNil())
+	})
+
+	It("Should not return a previous version if the operator doesn't coexist", func() {
+		opt := newOperator()
+		opt.Coexist = false
+		Expect(opt.GetPreviousVersion()).Should(BeNil())
+
+		opt = newOperator()
+		opt.PreviousVersion.Namespace = opt.Namespace
+		Expect(opt.GetPreviousVersion()).Should(BeNil())
+
+		opt = newOperator()
+		opt.Type = operatorv1alpha1.OperatorTypeHelm
+		Expect(opt.GetPreviousVersion()).Should(BeNil())
+	})
+
+	It("Should only migrate the operands in the annotation", func() {
+		req := &operatorv1alpha1.OperandRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "example",
+				Namespace:   "ibm-common-services",
+				Annotations: map[string]string{operatorv1alpha1.MigratedOperandsAnnotation: "etcd"},
+			},
+		}
+		Expect(req.IsMigrated("etcd")).Should(BeTrue())
+		Expect(req.IsMigrated("jenkins")).Should(BeFalse())
+		Expect(req.IsPaused("etcd")).Should(BeFalse())
+		Expect(requestAnnotationKey(req)).Should(Equal("ibm-common-services.example/request"))
+	})
+})
@@ -0,0 +1,61 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Report the drift diff", func() {
+
+	It("Should show the desired and the live values of the drifted fields", func() {
+		specJSON := `{"size":5,"auth":{"password":"changed"},"tls":{"enabled":false}}`
+		desiredJSON := `{"size":3,"auth":{"password":"secret"},"tls":{"enabled":true},"version":"3.2.13"}`
+
+		fields, err := driftedFields([]byte(specJSON), []byte(desiredJSON), []string{"auth.password", "size", "tls.enabled", "version"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fields).Should(Equal([]operatorv1alpha1.DriftedField{
+			{Path: "auth.password", Desired: `"<redacted>"`, Live: `"<redacted>"`},
+			{Path: "size", Desired: "3", Live: "5"},
+			{Path: "tls.enabled", Desired: "true", Live: "false"},
+			{Path: "version", Desired: `"3.2.13"`},
+		}))
+	})
+
+	It("Should sort the drifted custom resources and replace their fields", func() {
+		req := &operatorv1alpha1.OperandRequest{}
+		mu := &sync.Mutex{}
+		startDriftDiff(req)
+		req.SetDriftedResource(operatorv1alpha1.DriftedResource{Operand: "jenkins", Kind: "Jenkins", Name: "example"}, mu)
+		req.SetDriftedResource(operatorv1alpha1.DriftedResource{Operand: "etcd", Kind: "EtcdCluster", Name: "example"}, mu)
+		req.SetDriftedResource(operatorv1alpha1.DriftedResource{Operand: "etcd", Kind: "EtcdCluster", Name: "example", Fields: []operatorv1alpha1.DriftedField{{Path: "size"}}}, mu)
+		finishDriftDiff(req)
+
+		Expect(req.Status.DriftDiff).Should(HaveLen(2))
+		Expect(req.Status.DriftDiff[0].Operand).Should(Equal("etcd"))
+		Expect(req.Status.DriftDiff[0].Fields).Should(HaveLen(1))
+		Expect(req.Status.DriftDiff[1].Operand).Should(Equal("jenkins"))
+
+		startDriftDiff(req)
+		Expect(req.Status.DriftDiff).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,66 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Collect the effective configuration", func() {
+
+	It("Should record the redacted spec of the custom resources only when it is requested", func() {
+		r := &Reconciler{}
+		requestInstance := &operatorv1alpha1.OperandRequest{ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "example-ns"}}
+		cr := unstructured.Unstructured{}
+		cr.SetAPIVersion("etcd.database.coreos.com/v1beta2")
+		cr.SetKind("EtcdCluster")
+		cr.SetNamespace("example-ns")
+		cr.SetName("example")
+		spec := map[string]interface{}{"size": 3, "adminPassword": "secret"}
+
+		startEffectiveConfig(requestInstance)
+		r.recordEffectiveConfig(context.TODO(), requestInstance, "etcd", cr, spec)
+		Expect(requestInstance.Status.EffectiveConfig).Should(BeNil())
+
+		requestInstance.SetAnnotations(map[string]string{operatorv1alpha1.EffectiveConfigAnnotation: "true"})
+		previous := startEffectiveConfig(requestInstance)
+		r.recordEffectiveConfig(context.TODO(), requestInstance, "etcd", cr, spec)
+		r.recordEffectiveConfig(context.TODO(), requestInstance, "etcd", cr, spec)
+		finishEffectiveConfig(requestInstance, previous)
+
+		effective := requestInstance.Status.EffectiveConfig
+		Expect(effective).ShouldNot(BeNil())
+		Expect(effective.GeneratedTime).ShouldNot(BeEmpty())
+		Expect(effective.Resources).Should(HaveLen(1))
+		Expect(effective.Resources[0].Operand).Should(Equal("etcd"))
+		Expect(effective.Resources[0].Spec.Raw).Should(MatchJSON(`{"adminPassword":"<redacted>","size":3}`))
+
+		// The generated time is kept while the configuration doesn't change
+		effective.GeneratedTime = "2022-01-01T00:00:00Z"
+		previous = startEffectiveConfig(requestInstance)
+		r.recordEffectiveConfig(context.TODO(), requestInstance, "etcd", cr, spec)
+		finishEffectiveConfig(requestInstance, previous)
+		Expect(requestInstance.Status.EffectiveConfig.GeneratedTime).Should(Equal("2022-01-01T00:00:00Z"))
+	})
+})
@@ -0,0 +1,45 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// recordOperatorEvent records an Event of the operator on the OperandRequest and on its OperandRegistry
+func (r *Reconciler) recordOperatorEvent(requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Recorder.Eventf(requestInstance, eventtype, reason, messageFmt, args...)
+	if registryInstance != nil {
+		r.Recorder.Eventf(registryInstance, eventtype, reason, messageFmt, args...)
+	}
+}
+
+// getRegistryForEvent returns the OperandRegistry to record the Events on, or nil if it can't be found
+func (r *Reconciler) getRegistryForEvent(ctx context.Context, registryKey types.NamespacedName) *operatorv1alpha1.OperandRegistry {
+	registryInstance, err := r.GetOperandRegistry(ctx, registryKey)
+	if err != nil {
+		logging.FromContext(ctx).V(logging.LevelChange).Info("Not found OperandRegistry to record the Event", "registry", registryKey.String(), "error", err.Error())
+
+		return nil
+	}
+	return registryInstance
+}
@@ -0,0 +1,138 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+)
+
+var _ = Describe("Support the GitOps controllers", func() {
+
+	getCondition := func(req *operatorv1alpha1.OperandRequest, t operatorv1alpha1.ConditionType) *operatorv1alpha1.Condition {
+		for i := range req.Status.Conditions {
+			if req.Status.Conditions[i].Type == t {
+				return &req.Status.Conditions[i]
+			}
+		}
+		return nil
+	}
+
+	It("Should summarize the OperandRequest in the Reconciling and the Stalled conditions", func() {
+		req := &operatorv1alpha1.OperandRequest{}
+		req.Generation = 2
+		mu := &sync.Mutex{}
+		req.SetMemberStatus("etcd", operatorv1alpha1.OperatorInstalling, "", mu)
+		req.UpdateClusterPhase()
+
+		req.UpdateSummaryConditions(true)
+		Expect(req.Status.ObservedGeneration).Should(Equal(int64(2)))
+		Expect(getCondition(req, operatorv1alpha1.ConditionReconciling).Status).Should(Equal(corev1.ConditionTrue))
+		Expect(getCondition(req, operatorv1alpha1.ConditionStalled).Status).Should(Equal(corev1.ConditionFalse))
+
+		req.SetMemberStatus("etcd", operatorv1alpha1.OperatorRunning, operatorv1alpha1.ServiceRunning, mu)
+		req.UpdateClusterPhase()
+		req.UpdateSummaryConditions(true)
+		Expect(getCondition(req, operatorv1alpha1.ConditionReconciling).Status).Should(Equal(corev1.ConditionFalse))
+
+		// A new generation is reconciling until a reconcile succeeds
+		req.Generation = 3
+		req.UpdateSummaryConditions(false)
+		Expect(req.Status.ObservedGeneration).Should(Equal(int64(2)))
+		Expect(getCondition(req, operatorv1alpha1.ConditionReconciling).Reason).Should(Equal("ReconcileFailed"))
+
+		req.SetMemberStatus("etcd", "", operatorv1alpha1.ServiceFailed, mu)
+		req.UpdateClusterPhase()
+		req.UpdateSummaryConditions(true)
+		Expect(getCondition(req, operatorv1alpha1.ConditionReconciling).Status).Should(Equal(corev1.ConditionFalse))
+		Expect(getCondition(req, operatorv1alpha1.ConditionStalled).Status).Should(Equal(corev1.ConditionTrue))
+		Expect(getCondition(req, operatorv1alpha1.ConditionStalled).Message).Should(Equal("The members etcd failed"))
+	})
+
+	It("Should keep the conditions unchanged when a reconcile changes nothing", func() {
+		req := &operatorv1alpha1.OperandRequest{}
+		mu := &sync.Mutex{}
+		req.SetMemberStatus("etcd", operatorv1alpha1.OperatorRunning, operatorv1alpha1.ServiceRunning, mu)
+		req.SetThrottledCondition("jenkins", "opencloud-operators", 1, operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu)
+		req.UpdateClusterPhase()
+		req.UpdateSummaryConditions(true)
+		for i := range req.Status.Conditions {
+			req.Status.Conditions[i].LastUpdateTime = "2022-01-01T00:00:00Z"
+			req.Status.Conditions[i].LastTransitionTime = "2022-01-01T00:00:00Z"
+		}
+		previous := req.DeepCopy()
+
+		// The Throttled condition is removed and set again, it moves to the end of the conditions
+		req.SetThrottledCondition("jenkins", "opencloud-operators", 1, operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu)
+		req.UpdateSummaryConditions(true)
+		Expect(req.Status.Conditions).ShouldNot(Equal(previous.Status.Conditions))
+		req.StabilizeConditions(previous.Status.Conditions)
+		Expect(req.Status.Conditions).Should(Equal(previous.Status.Conditions))
+
+		// The condition changing its status gets a new transition time
+		req.SetMemberStatus("etcd", "", operatorv1alpha1.ServiceFailed, mu)
+		req.UpdateClusterPhase()
+		req.UpdateSummaryConditions(true)
+		req.StabilizeConditions(previous.Status.Conditions)
+		Expect(getCondition(req, operatorv1alpha1.ConditionStalled).LastTransitionTime).ShouldNot(Equal("2022-01-01T00:00:00Z"))
+		Expect(getCondition(req, operatorv1alpha1.ConditionThrottled).LastTransitionTime).Should(Equal("2022-01-01T00:00:00Z"))
+	})
+
+	It("Should reconcile the children in the order of their sync waves", func() {
+		var reconciled []string
+		child := func(name string, wave int, resource bool, err error) syncWaveChild {
+			return syncWaveChild{wave: wave, resource: resource, reconcile: func() error {
+				reconciled = append(reconciled, name)
+				return err
+			}}
+		}
+
+		err := reconcileSyncWaves(context.Background(), []syncWaveChild{
+			child("etcd-cluster", 0, false, nil),
+			child("etcd-secret", 0, true, nil),
+			child("etcd-backup", 1, false, nil),
+			child("etcd-restore", -1, false, nil),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(reconciled).Should(Equal([]string{"etcd-restore", "etcd-secret", "etcd-cluster", "etcd-backup"}))
+
+		// The next waves wait for the failed wave
+		reconciled = nil
+		err = reconcileSyncWaves(context.Background(), []syncWaveChild{
+			child("etcd-backup", 1, false, nil),
+			child("etcd-cluster", 0, false, fmt.Errorf("failed to create the etcd-cluster")),
+			child("etcd-proxy", 0, false, nil),
+		})
+		Expect(err).Should(HaveOccurred())
+		Expect(reconciled).Should(Equal([]string{"etcd-cluster", "etcd-proxy"}))
+	})
+
+	It("Should read the sync wave of the annotations", func() {
+		Expect(util.SyncWave(map[string]string{constant.SyncWaveAnnotation: "-2"})).Should(Equal(-2))
+		Expect(util.SyncWave(map[string]string{constant.SyncWaveAnnotation: "first"})).Should(Equal(0))
+		Expect(util.SyncWave(nil)).Should(Equal(0))
+	})
+})
@@ -0,0 +1,62 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"fmt"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Record the history of an OperandRequest", func() {
+
+	It("Should keep the latest entries", func() {
+		req := &operatorv1alpha1.OperandRequest{}
+		mu := &sync.Mutex{}
+		for i := 0; i < operatorv1alpha1.MaxHistoryEntries+5; i++ {
+			req.AddHistory(operatorv1alpha1.HistoryEntry{Action: operatorv1alpha1.HistoryChannelChanged, Operator: "etcd", To: fmt.Sprintf("v%d", i)}, mu)
+		}
+		Expect(req.Status.History).Should(HaveLen(operatorv1alpha1.MaxHistoryEntries))
+		Expect(req.Status.History[0].To).Should(Equal("v5"))
+		Expect(req.Status.History[operatorv1alpha1.MaxHistoryEntries-1].To).Should(Equal(fmt.Sprintf("v%d", operatorv1alpha1.MaxHistoryEntries+4)))
+		Expect(req.Status.History[0].Time).ShouldNot(BeEmpty())
+	})
+
+	It("Should record the members turning failed once", func() {
+		req := &operatorv1alpha1.OperandRequest{}
+		mu := &sync.Mutex{}
+		req.SetMemberStatus("etcd", operatorv1alpha1.OperatorInstalling, "", mu)
+		req.SetMemberStatus("etcd", operatorv1alpha1.OperatorFailed, "", mu)
+		req.SetMemberStatus("etcd", operatorv1alpha1.OperatorFailed, "", mu)
+
+		Expect(req.Status.History).Should(HaveLen(1))
+		Expect(req.Status.History[0].Action).Should(Equal(operatorv1alpha1.HistoryMemberFailed))
+		Expect(req.Status.History[0].Operator).Should(Equal("etcd"))
+		Expect(req.Status.History[0].From).Should(Equal(string(operatorv1alpha1.OperatorInstalling)))
+		Expect(req.Status.History[0].To).Should(Equal(string(operatorv1alpha1.OperatorFailed)))
+	})
+
+	It("Should hash the specs of the custom resources", func() {
+		Expect(specHash(map[string]interface{}{"size": 3})).Should(HaveLen(14))
+		Expect(specHash(map[string]interface{}{"size": 3})).Should(Equal(specHash(map[string]interface{}{"size": 3})))
+		Expect(specHash(map[string]interface{}{"size": 3})).ShouldNot(Equal(specHash(map[string]interface{}{"size": 1})))
+	})
+})
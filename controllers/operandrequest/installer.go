@@ -0,0 +1,104 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+// Installer installs the operator of an OperandRegistry entry with a backend, like OLM or Helm.
+// The OperandRequest reconciler picks the Installer registered for the type of the operator,
+// so a new backend only needs to implement Installer and register it with RegisterInstaller.
+type Installer interface {
+	// Install installs or upgrades the operator requested by the OperandRequest,
+	// and sets the operator phase of the member status.
+	Install(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, opt *operatorv1alpha1.Operator, registryKey types.NamespacedName, mu sync.Locker) error
+	// Uninstall uninstalls the operator no longer requested by the OperandRequest, with its operands.
+	Uninstall(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, configInstance *operatorv1alpha1.OperandConfig, opt *operatorv1alpha1.Operator) error
+	// GetCSV returns the ClusterServiceVersion the operands of the operator are created from.
+	// It returns nil if the operands must not be created yet, the member status is set by the Installer.
+	GetCSV(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, opt *operatorv1alpha1.Operator, mu sync.Locker) (*olmv1alpha1.ClusterServiceVersion, error)
+}
+
+// UninstallPreviewer is implemented by the Installers which can preview their Uninstall for the deletion preview.
+type UninstallPreviewer interface {
+	// PreviewUninstall adds the objects deleted and retained by Uninstall to the preview, without changing them.
+	PreviewUninstall(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, configInstance *operatorv1alpha1.OperandConfig, opt *operatorv1alpha1.Operator, preview *operatorv1alpha1.DeletionPreview) error
+}
+
+// OrderedUninstaller is implemented by the Installers whose operands can be deleted before the operator,
+// for the ordered teardown of the deleted OperandRequest.
+type OrderedUninstaller interface {
+	// GetUninstallCSV returns the ClusterServiceVersion whose operands are deleted by Uninstall,
+	// or nil if Uninstall keeps the operands.
+	GetUninstallCSV(ctx context.Context, registryInstance *operatorv1alpha1.OperandRegistry, opt *operatorv1alpha1.Operator) (*olmv1alpha1.ClusterServiceVersion, error)
+}
+
+// InstallerFactory builds an Installer working with the reconciler
+type InstallerFactory func(r *Reconciler) Installer
+
+var installerFactories = map[string]InstallerFactory{}
+
+// RegisterInstaller registers the Installer for the operators of the type.
+// It is called from the init functions of the backends.
+func RegisterInstaller(operatorType string, factory InstallerFactory) {
+	installerFactories[operatorType] = factory
+}
+
+func (r *Reconciler) getInstaller(opt *operatorv1alpha1.Operator) (Installer, error) {
+	factory, ok := installerFactories[opt.GetType()]
+	if !ok {
+		return nil, fmt.Errorf("the type %s of the operator %s is not supported", opt.GetType(), opt.Name)
+	}
+	return factory(r), nil
+}
+
+func init() {
+	RegisterInstaller(operatorv1alpha1.OperatorTypeOLM, func(r *Reconciler) Installer { return &olmInstaller{r} })
+}
+
+// olmInstaller installs the operator with an OLM Subscription
+type olmInstaller struct {
+	*Reconciler
+}
+
+func (i *olmInstaller) Install(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, opt *operatorv1alpha1.Operator, registryKey types.NamespacedName, mu sync.Locker) error {
+	return i.installSubscription(ctx, requestInstance, opt, registryKey, mu)
+}
+
+func (i *olmInstaller) Uninstall(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, configInstance *operatorv1alpha1.OperandConfig, opt *operatorv1alpha1.Operator) error {
+	return i.uninstallSubscription(ctx, requestInstance, registryInstance, configInstance, opt)
+}
+
+func (i *olmInstaller) PreviewUninstall(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, configInstance *operatorv1alpha1.OperandConfig, opt *operatorv1alpha1.Operator, preview *operatorv1alpha1.DeletionPreview) error {
+	return i.previewUninstallSubscription(ctx, requestInstance, registryInstance, configInstance, opt, preview)
+}
+
+func (i *olmInstaller) GetUninstallCSV(ctx context.Context, registryInstance *operatorv1alpha1.OperandRegistry, opt *operatorv1alpha1.Operator) (*olmv1alpha1.ClusterServiceVersion, error) {
+	return i.getUninstallSubscriptionCSV(ctx, registryInstance, opt)
+}
+
+func (i *olmInstaller) GetCSV(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, opt *operatorv1alpha1.Operator, mu sync.Locker) (*olmv1alpha1.ClusterServiceVersion, error) {
+	return i.getSubscriptionCSV(ctx, requestInstance, registryInstance, opt, mu)
+}
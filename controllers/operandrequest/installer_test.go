@@ -0,0 +1,55 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Installer", func() {
+
+	Context("Pick the Installer of the operator type", func() {
+		r := &Reconciler{}
+
+		It("Should use OLM by default", func() {
+			installer, err := r.getInstaller(&operatorv1alpha1.Operator{Name: "etcd"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(installer).Should(BeAssignableToTypeOf(&olmInstaller{}))
+		})
+
+		It("Should use the Installer registered for the type", func() {
+			for operatorType, expected := range map[string]Installer{
+				operatorv1alpha1.OperatorTypeOLM:       &olmInstaller{},
+				operatorv1alpha1.OperatorTypeManifests: &manifestsInstaller{},
+				operatorv1alpha1.OperatorTypeHelm:      &helmInstaller{},
+				operatorv1alpha1.OperatorTypeBYO:       &byoInstaller{},
+			} {
+				installer, err := r.getInstaller(&operatorv1alpha1.Operator{Name: "etcd", Type: operatorType})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(installer).Should(BeAssignableToTypeOf(expected))
+			}
+		})
+
+		It("Should fail for an unknown type", func() {
+			_, err := r.getInstaller(&operatorv1alpha1.Operator{Name: "etcd", Type: "unknown"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,147 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Reconcile the members in parallel", func() {
+
+	registryKey := types.NamespacedName{Namespace: "ibm-common-services", Name: "common-service"}
+	registry := &operatorv1alpha1.OperandRegistry{
+		Spec: operatorv1alpha1.OperandRegistrySpec{
+			Operators: []operatorv1alpha1.Operator{
+				{Name: "etcd"},
+				{Name: "mongodb", Requires: []string{"etcd"}},
+				{Name: "jenkins", Requires: []string{"mongodb"}},
+				{Name: "jaeger"},
+			},
+		},
+	}
+
+	It("Should reconcile each member after the members it requires", func() {
+		var mu sync.Mutex
+		var reconciled []string
+		member := func(name string, err error) operandMember {
+			return newOperandMember(registryKey, registry, name, func(ctx context.Context) error {
+				mu.Lock()
+				defer mu.Unlock()
+				reconciled = append(reconciled, name)
+				return err
+			})
+		}
+
+		merr := reconcileMembers(context.Background(), 2, []operandMember{
+			member("jenkins", nil),
+			member("jaeger", fmt.Errorf("failed to reconcile jaeger")),
+			member("mongodb", fmt.Errorf("failed to reconcile mongodb")),
+			member("etcd", nil),
+		})
+		Expect(merr.Errors).Should(Equal([]string{"failed to reconcile jaeger", "failed to reconcile mongodb"}))
+		Expect(reconciled).Should(HaveLen(4))
+		Expect(reconciled[:2]).Should(ConsistOf("jaeger", "etcd"))
+		Expect(reconciled[2:]).Should(Equal([]string{"mongodb", "jenkins"}))
+	})
+
+	It("Should group the members in waves", func() {
+		member := func(name string) operandMember {
+			return newOperandMember(registryKey, registry, name, nil)
+		}
+		names := func(waves [][]operandMember) [][]string {
+			var grouped [][]string
+			for _, wave := range waves {
+				var wn []string
+				for _, m := range wave {
+					wn = append(wn, m.key)
+				}
+				grouped = append(grouped, wn)
+			}
+			return grouped
+		}
+
+		Expect(names(memberWaves([]operandMember{member("jenkins"), member("etcd"), member("etcd")}))).Should(Equal([][]string{
+			{"ibm-common-services/common-service/jenkins", "ibm-common-services/common-service/etcd"},
+			{"ibm-common-services/common-service/etcd"},
+		}))
+
+		// A cycle of requires doesn't block the members
+		cyclic := &operatorv1alpha1.OperandRegistry{
+			Spec: operatorv1alpha1.OperandRegistrySpec{
+				Operators: []operatorv1alpha1.Operator{
+					{Name: "etcd", Requires: []string{"mongodb"}},
+					{Name: "mongodb", Requires: []string{"etcd"}},
+				},
+			},
+		}
+		waves := memberWaves([]operandMember{
+			newOperandMember(registryKey, cyclic, "etcd", nil),
+			newOperandMember(registryKey, cyclic, "mongodb", nil),
+		})
+		Expect(names(waves)).Should(Equal([][]string{
+			{"ibm-common-services/common-service/mongodb"},
+			{"ibm-common-services/common-service/etcd"},
+		}))
+	})
+
+	It("Should retry a failed member with an exponential backoff", func() {
+		Expect(memberRetryDelay(1)).Should(Equal(10 * time.Second))
+		Expect(memberRetryDelay(3)).Should(Equal(40 * time.Second))
+		Expect(memberRetryDelay(20)).Should(Equal(5 * time.Minute))
+
+		r := &Reconciler{}
+		req := &operatorv1alpha1.OperandRequest{}
+		attempts := 0
+		failing := func() error {
+			attempts++
+			return fmt.Errorf("failed to render the configuration of jenkins")
+		}
+
+		Expect(r.retryMember(context.Background(), req, "jenkins", failing)).Should(HaveOccurred())
+		Expect(req.Status.Members[0].Retry.Retries).Should(Equal(1))
+		Expect(req.Status.Members[0].Retry.LastError).Should(Equal("failed to render the configuration of jenkins"))
+		Expect(req.GetRetryWait(time.Now())).Should(BeNumerically("~", 10*time.Second, time.Second))
+
+		// The member backing off isn't reconciled
+		Expect(r.retryMember(context.Background(), req, "jenkins", failing)).ShouldNot(HaveOccurred())
+		Expect(attempts).Should(Equal(1))
+
+		// The member is retried at the end of its backoff, the delay doubles
+		req.Status.Members[0].Retry.NextRetryTime = time.Now().Add(-time.Second).UTC().Format(time.RFC3339)
+		Expect(r.retryMember(context.Background(), req, "jenkins", failing)).Should(HaveOccurred())
+		Expect(attempts).Should(Equal(2))
+		Expect(req.Status.Members[0].Retry.Retries).Should(Equal(2))
+		Expect(req.GetRetryWait(time.Now())).Should(BeNumerically("~", 20*time.Second, time.Second))
+
+		// A new spec resets the backoff, and the member succeeding forgets its retries
+		req.ResetMemberRetries()
+		Expect(req.GetRetryWait(time.Now())).Should(BeZero())
+		Expect(r.retryMember(context.Background(), req, "jenkins", failing)).Should(HaveOccurred())
+		req.Status.Members[0].Retry.NextRetryTime = ""
+		Expect(r.retryMember(context.Background(), req, "jenkins", func() error { return nil })).ShouldNot(HaveOccurred())
+		Expect(req.Status.Members[0].Retry).Should(BeNil())
+	})
+})
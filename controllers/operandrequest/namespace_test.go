@@ -0,0 +1,50 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+)
+
+var _ = Describe("Provision the namespaces of the operators", func() {
+
+	It("Should only set the ODLM label without provisioning", func() {
+		ns := newNamespace("ibm-operators", nil)
+		Expect(ns.Name).Should(Equal("ibm-operators"))
+		Expect(ns.Labels).Should(Equal(map[string]string{constant.OpreqLabel: "true"}))
+		Expect(ns.Annotations).Should(BeNil())
+	})
+
+	It("Should set the labels and annotations of the provisioning", func() {
+		ns := newNamespace("ibm-operators", &operatorv1alpha1.NamespaceProvisioning{
+			Labels: map[string]string{
+				"pod-security.kubernetes.io/enforce": "restricted",
+				constant.OpreqLabel:                  "false",
+			},
+			Annotations: map[string]string{"openshift.io/node-selector": "node-role.kubernetes.io/infra="},
+		})
+		Expect(ns.Labels).Should(Equal(map[string]string{
+			"pod-security.kubernetes.io/enforce": "restricted",
+			constant.OpreqLabel:                  "true",
+		}))
+		Expect(ns.Annotations).Should(HaveKeyWithValue("openshift.io/node-selector", "node-role.kubernetes.io/infra="))
+	})
+})
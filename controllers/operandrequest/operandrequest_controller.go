@@ -20,19 +20,18 @@ import (
 	"context"
 	"fmt"
 	"reflect"
-	"regexp"
-	"strings"
 	"sync"
 	"time"
 
 	olmv1 "github.com/operator-framework/api/pkg/operators/v1"
 	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
-	"k8s.io/klog"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -44,13 +43,28 @@ import (
 	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
 	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/ownership"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/shard"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/metrics"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/throughput"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/tracing"
 )
 
 // Reconciler reconciles a OperandRequest object
 type Reconciler struct {
 	*deploy.ODLMOperator
 	StepSize int
-	Mutex    sync.Mutex
+	// ValidateSchemas validates the rendered custom resources against the schemas of their CRDs before they are applied,
+	// for the clusters where the admission webhooks can't be installed
+	ValidateSchemas bool
+	Mutex           sync.Mutex
+	throttle        installThrottle
+	// watcher watches the deletion and the changes of the custom resources and k8s resources created by ODLM
+	watcher *resourceWatcher
+	// deletions remembers the operands whose PreDelete callbacks are called
+	deletions notifiedDeletions
 }
 type clusterObjects struct {
 	namespace     *corev1.Namespace
@@ -64,22 +78,62 @@ type clusterObjects struct {
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
+	ctx, log := logging.Reconcile(ctx, "operandrequest", "request", req.NamespacedName.String())
+	ctx, span := tracing.Start(ctx, "OperandRequest.Reconcile", attribute.String("namespace", req.Namespace), attribute.String("name", req.Name))
+	defer func() {
+		tracing.End(span, reconcileErr)
+	}()
+
 	// Fetch the OperandRequest instance
 	requestInstance := &operatorv1alpha1.OperandRequest{}
 	if err := r.Client.Get(ctx, req.NamespacedName, requestInstance); err != nil {
+		if apierrors.IsNotFound(err) {
+			metrics.DeleteOperandRequest(req.Namespace, req.Name)
+		}
 		// Error reading the object - requeue the request.
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
 	originalInstance := requestInstance.DeepCopy()
 
+	// Skip the OperandRequest when its namespace opts out of ODLM, the deletion is still cleaned up
+	if requestInstance.ObjectMeta.DeletionTimestamp.IsZero() {
+		excluded, err := r.IsNamespaceExcluded(ctx, requestInstance.Namespace)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if excluded {
+			log.Info("Skip the OperandRequest in the excluded namespace", "label", constant.ExcludeNamespaceLabel)
+			return ctrl.Result{}, nil
+		}
+	}
+
 	// Always attempt to patch the status after each reconciliation.
 	defer func() {
+		if requestInstance.ObjectMeta.DeletionTimestamp.IsZero() {
+			requestInstance.UpdateSummaryConditions(reconcileErr == nil)
+		}
+		requestInstance.StabilizeConditions(originalInstance.Status.Conditions)
+		recordMetrics(requestInstance)
+		r.collectForensicBundles(ctx, originalInstance, requestInstance)
+		span.SetAttributes(attribute.String("phase", string(requestInstance.Status.Phase)))
 		if reflect.DeepEqual(originalInstance.Status, requestInstance.Status) {
 			return
 		}
-		if err := r.Client.Status().Patch(ctx, requestInstance, client.MergeFrom(originalInstance)); err != nil {
-			reconcileErr = utilerrors.NewAggregate([]error{reconcileErr, fmt.Errorf("error while patching OperandRequest.Status: %v", err)})
+		statusCtx, statusSpan := tracing.Start(ctx, "UpdateStatus")
+		var statusErr error
+		defer func() {
+			tracing.End(statusSpan, statusErr)
+		}()
+		// Keep the status within the size budget before writing it to etcd
+		if statusErr = r.GuardStatusSize(statusCtx, requestInstance, &requestInstance.Status, requestInstance.TruncateStatus); statusErr != nil {
+			reconcileErr = utilerrors.NewAggregate([]error{reconcileErr, fmt.Errorf("error while checking the size of OperandRequest.Status: %v", statusErr)})
+			return
+		}
+		if statusErr = r.PatchStatus(statusCtx, requestInstance, originalInstance, func(latest client.Object) error {
+			return deploy.ReapplyStatus(&latest.(*operatorv1alpha1.OperandRequest).Status, &originalInstance.Status, &requestInstance.Status)
+		}); statusErr != nil {
+			reconcileErr = utilerrors.NewAggregate([]error{reconcileErr, fmt.Errorf("error while patching OperandRequest.Status: %v", statusErr)})
 		}
 	}()
 
@@ -89,7 +143,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		// Check and clean up the subscriptions
 		err := r.checkFinalizer(ctx, requestInstance)
 		if err != nil {
-			klog.Errorf("failed to clean up the subscriptions for OperandRequest %s: %v", req.NamespacedName.String(), err)
+			log.Error(err, "failed to clean up the subscriptions for OperandRequest")
+			return ctrl.Result{}, err
+		}
+		// The objects kept for the other OperandRequests stop mapping back to the deleted one
+		if err := r.untrackObjects(ctx, requestInstance); err != nil {
+			log.Error(err, "failed to untrack the objects of OperandRequest")
 			return ctrl.Result{}, err
 		}
 
@@ -98,7 +157,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		if requestInstance.RemoveFinalizer() {
 			err = r.Patch(ctx, requestInstance, client.MergeFrom(originalReq))
 			if err != nil {
-				klog.Errorf("failed to remove finalizer for OperandRequest %s: %v", req.NamespacedName.String(), err)
+				log.Error(err, "failed to remove finalizer for OperandRequest")
 				return ctrl.Result{}, client.IgnoreNotFound(err)
 			}
 		}
@@ -108,15 +167,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	// Check if operator has the update permission to update OperandRequest
 	hasPermission := r.checkPermission(ctx, req)
 	if !hasPermission {
-		klog.Warningf("No permission to update OperandRequest")
+		log.Info("No permission to update OperandRequest")
 		return ctrl.Result{RequeueAfter: 3 * time.Second}, nil
 	}
 
-	klog.V(1).Infof("Reconciling OperandRequest: %s", req.NamespacedName)
+	log.V(logging.LevelFlow).Info("Reconciling OperandRequest")
 	// Update labels for the request
 	if requestInstance.UpdateLabels() {
 		if err := r.Patch(ctx, requestInstance, client.MergeFrom(originalInstance)); err != nil {
-			klog.Errorf("failed to update the labels for OperandRequest %s: %v", req.NamespacedName.String(), err)
+			log.Error(err, "failed to update the labels for OperandRequest")
 			return ctrl.Result{}, err
 		}
 	}
@@ -128,34 +187,109 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 
 	// Add finalizer to the instance
 	if isAdded, err := r.addFinalizer(ctx, requestInstance); err != nil {
-		klog.Errorf("failed to add finalizer for OperandRequest %s: %v", req.NamespacedName.String(), err)
+		log.Error(err, "failed to add finalizer for OperandRequest")
 		return ctrl.Result{}, err
 	} else if !isAdded {
 		return ctrl.Result{Requeue: true}, err
 	}
 
+	// Distribute the request to the managed clusters instead of installing it in the current cluster
+	if requestInstance.Spec.Placement != nil {
+		if err := r.reconcilePlacement(ctx, requestInstance); err != nil {
+			log.Error(err, "failed to reconcile Placement for OperandRequest")
+			return ctrl.Result{}, err
+		}
+		if requestInstance.Status.Phase != operatorv1alpha1.ClusterPhaseRunning {
+			log.V(logging.LevelChange).Info("Waiting for all operators and operands to be deployed successfully in the managed clusters ...")
+			return ctrl.Result{RequeueAfter: constant.DefaultRequeueDuration}, nil
+		}
+		log.V(logging.LevelFlow).Info("Finished reconciling OperandRequest")
+		return ctrl.Result{RequeueAfter: constant.DefaultSyncPeriod}, nil
+	} else if len(requestInstance.Status.Placement) != 0 {
+		if err := r.deletePlacement(ctx, requestInstance); err != nil {
+			log.Error(err, "failed to clean up the managed clusters for OperandRequest")
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Reconcile Operators
 	if err := r.reconcileOperator(ctx, requestInstance); err != nil {
-		klog.Errorf("failed to reconcile Operators for OperandRequest %s: %v", req.NamespacedName.String(), err)
+		log.Error(err, "failed to reconcile Operators for OperandRequest")
 		return ctrl.Result{}, err
 	}
 
-	// Reconcile Operands
-	if merr := r.reconcileOperand(ctx, requestInstance); len(merr.Errors) != 0 {
-		klog.Errorf("failed to reconcile Operands for OperandRequest %s: %v", req.NamespacedName.String(), merr)
+	// Reconcile Operands, and collect the effective configuration and the drift diff of their custom resources
+	previousEffectiveConfig := startEffectiveConfig(requestInstance)
+	startDriftDiff(requestInstance)
+	merr := r.reconcileOperand(ctx, requestInstance)
+	finishEffectiveConfig(requestInstance, previousEffectiveConfig)
+	finishDriftDiff(requestInstance)
+	if len(merr.Errors) != 0 {
+		log.Error(merr, "failed to reconcile Operands for OperandRequest")
 		return ctrl.Result{}, merr
 	}
 
+	// Report the operands whose OperandBindInfo doesn't allow the namespace
+	if err := r.reconcileBindInfoPermissions(ctx, requestInstance); err != nil {
+		log.Error(err, "failed to check the OperandBindInfos for OperandRequest")
+		return ctrl.Result{}, err
+	}
+
+	// Preview the deletion of the OperandRequest
+	if err := r.reconcileDeletionPreview(ctx, requestInstance); err != nil {
+		log.Error(err, "failed to generate the deletion preview for OperandRequest")
+		return ctrl.Result{}, err
+	}
+
+	// Start the scheduled operands at their NotBefore time
+	if wait := requestInstance.GetScheduledWait(time.Now()); wait > 0 {
+		if requestInstance.Status.Phase == operatorv1alpha1.ClusterPhaseScheduled || wait < constant.DefaultRequeueDuration {
+			log.V(logging.LevelChange).Info("Waiting for the scheduled operands of OperandRequest", "wait", wait.String())
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+	}
+
+	// Retry the failed members at the end of their backoff
+	if wait := requestInstance.GetRetryWait(time.Now()); wait > 0 {
+		if requestInstance.Status.Phase == operatorv1alpha1.ClusterPhaseRunning || wait < constant.DefaultRequeueDuration {
+			log.V(logging.LevelChange).Info("Waiting for the failed members of OperandRequest to be retried", "wait", wait.String())
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+	}
+
 	// Check if all csv deploy succeed
 	if requestInstance.Status.Phase != operatorv1alpha1.ClusterPhaseRunning {
-		klog.V(2).Info("Waiting for all operators and operands to be deployed successfully ...")
+		log.V(logging.LevelChange).Info("Waiting for all operators and operands to be deployed successfully ...")
 		return ctrl.Result{RequeueAfter: constant.DefaultRequeueDuration}, nil
 	}
 
-	klog.V(1).Infof("Finished reconciling OperandRequest: %s", req.NamespacedName)
+	log.V(logging.LevelFlow).Info("Finished reconciling OperandRequest")
 	return ctrl.Result{RequeueAfter: constant.DefaultSyncPeriod}, nil
 }
 
+// recordMetrics records the phases of the OperandRequest and its operands, and the drifts of the operands
+func recordMetrics(requestInstance *operatorv1alpha1.OperandRequest) {
+	if !requestInstance.ObjectMeta.DeletionTimestamp.IsZero() {
+		metrics.DeleteOperandRequest(requestInstance.Namespace, requestInstance.Name)
+		return
+	}
+	if requestInstance.Status.Phase != "" {
+		metrics.SetOperandRequestPhase(requestInstance.Namespace, requestInstance.Name, string(requestInstance.Status.Phase))
+	}
+	phases := make(map[string]string)
+	for _, m := range requestInstance.Status.Members {
+		if m.Phase.OperandPhase != operatorv1alpha1.ServiceNone {
+			phases[m.Name] = string(m.Phase.OperandPhase)
+		}
+	}
+	metrics.SetOperandPhases(requestInstance.Namespace, requestInstance.Name, phases)
+	drifts := make(map[string]int)
+	for _, res := range requestInstance.Status.DriftDiff {
+		drifts[res.Operand] += len(res.Fields)
+	}
+	metrics.SetOperandDrifts(requestInstance.Namespace, requestInstance.Name, drifts)
+}
+
 func (r *Reconciler) checkPermission(ctx context.Context, req ctrl.Request) bool {
 	// Check update permission
 	if !r.checkUpdateAuth(ctx, req.Namespace, "operator.ibm.com", "operandrequests") {
@@ -181,11 +315,11 @@ func (r *Reconciler) checkUpdateAuth(ctx context.Context, namespace, group, reso
 	}
 
 	if err := r.Create(ctx, sar); err != nil {
-		klog.Errorf("Failed to check operator update permission: %v", err)
+		logging.FromContext(ctx).Error(err, "Failed to check operator update permission")
 		return false
 	}
 
-	klog.V(3).Infof("Operator update permission in namespace %s, Allowed: %t, Denied: %t, Reason: %s", namespace, sar.Status.Allowed, sar.Status.Denied, sar.Status.Reason)
+	logging.FromContext(ctx).V(logging.LevelDebug).Info("Operator update permission", "namespace", namespace, "resource", resource, "allowed", sar.Status.Allowed, "denied", sar.Status.Denied, "reason", sar.Status.Reason)
 	return sar.Status.Allowed
 }
 
@@ -206,23 +340,32 @@ func (r *Reconciler) addFinalizer(ctx context.Context, cr *operatorv1alpha1.Oper
 }
 
 func (r *Reconciler) checkFinalizer(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) error {
-	klog.V(1).Infof("Deleting OperandRequest %s in the namespace %s", requestInstance.Name, requestInstance.Namespace)
-	existingSub := &olmv1alpha1.SubscriptionList{}
+	logging.FromContext(ctx).V(logging.LevelFlow).Info("Deleting OperandRequest")
 
-	opts := []client.ListOption{
-		client.MatchingLabels(map[string]string{constant.OpreqLabel: "true"}),
+	// Remove the request from the managed clusters
+	if err := r.deletePlacement(ctx, requestInstance); err != nil {
+		return err
 	}
 
-	if err := r.Client.List(ctx, existingSub, opts...); err != nil {
+	existingSub, err := r.ListManagedSubscriptions(ctx)
+	if err != nil {
 		return err
 	}
 	if len(existingSub.Items) == 0 {
 		return nil
 	}
-	// Delete all the subscriptions that created by current request
+	// Delete the operands of all the operators first, then the subscriptions that created by current request
+	if err := r.teardownOperands(ctx, requestInstance); err != nil {
+		return err
+	}
 	if err := r.absentOperatorsAndOperands(ctx, requestInstance); err != nil {
 		return err
 	}
+	// Uninstall the previous versions of the coexisting operators only used by the current request
+	if err := r.releasePreviousVersions(ctx, requestInstance); err != nil {
+		return err
+	}
+	r.forgetPreDelete(requestInstance, "")
 	return nil
 }
 
@@ -241,27 +384,15 @@ func (r *Reconciler) getRegistryToRequestMapper() handler.MapFunc {
 	}
 }
 
+// getSubToRequestMapper maps the Subscriptions, and the other objects created by ODLM, to the OperandRequests
+// recorded in their <namespace>.<name>/request labels and annotations
 func (r *Reconciler) getSubToRequestMapper() handler.MapFunc {
 	return func(object client.Object) []ctrl.Request {
-		reg, _ := regexp.Compile(`^(.*)\.(.*)\/request`)
-		annotations := object.GetAnnotations()
-		var reqName, reqNamespace string
-		for annotation := range annotations {
-			if reg.MatchString(annotation) {
-				annotationSlices := strings.Split(annotation, ".")
-				reqNamespace = annotationSlices[0]
-				reqName = strings.Split(annotationSlices[1], "/")[0]
-			}
-		}
-		if reqNamespace == "" || reqName == "" {
-			return []ctrl.Request{}
-		}
-		return []ctrl.Request{
-			{NamespacedName: types.NamespacedName{
-				Name:      reqName,
-				Namespace: reqNamespace,
-			}},
+		requests := []ctrl.Request{}
+		for _, requestKey := range ownership.Requests(object) {
+			requests = append(requests, ctrl.Request{NamespacedName: requestKey})
 		}
+		return requests
 	}
 }
 
@@ -280,29 +411,100 @@ func (r *Reconciler) getConfigToRequestMapper() handler.MapFunc {
 	}
 }
 
+// getRequestPriority returns the priority of the OperandRequest from the cache, the requests not found are normal
+func (r *Reconciler) getRequestPriority(req ctrl.Request) int {
+	requestInstance := &operatorv1alpha1.OperandRequest{}
+	if err := r.Client.Get(context.TODO(), req.NamespacedName, requestInstance); err != nil {
+		return (&operatorv1alpha1.OperandRequest{}).GetPriority()
+	}
+	return requestInstance.GetPriority()
+}
+
 // SetupWithManager adds OperandRequest controller to the manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&operatorv1alpha1.OperandRequest{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+	r.watcher = newResourceWatcher(mgr.GetConfig(), mgr.GetRESTMapper())
+	if err := mgr.Add(r.watcher); err != nil {
+		return err
+	}
+	fair := throughput.NewFairQueue("operandrequest").WithPriority(r.getRequestPriority)
+	b := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(throughput.ControllerOptions("operandrequest")).
+		For(&operatorv1alpha1.OperandRequest{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				// Pause or resume the operands, migrate the coexisting operands, or preview the deletion
+				for _, anno := range []string{operatorv1alpha1.PausedOperandsAnnotation, operatorv1alpha1.MigratedOperandsAnnotation, operatorv1alpha1.DeletionPreviewAnnotation} {
+					if e.ObjectOld.GetAnnotations()[anno] != e.ObjectNew.GetAnnotations()[anno] {
+						return true
+					}
+				}
+				return false
+			},
+		}))).
 		Watches(&source.Kind{Type: &olmv1alpha1.Subscription{}}, handler.EnqueueRequestsFromMapFunc(r.getSubToRequestMapper()), builder.WithPredicates(predicate.Funcs{
 			UpdateFunc: func(e event.UpdateEvent) bool {
 				oldObject := e.ObjectOld.(*olmv1alpha1.Subscription)
 				newObject := e.ObjectNew.(*olmv1alpha1.Subscription)
 				if oldObject.Labels != nil && oldObject.Labels[constant.OpreqLabel] == "true" {
+					// The InstallPlans of the operators denying the skip range wait for ODLM to approve them
+					if newObject.Spec.InstallPlanApproval == olmv1alpha1.ApprovalManual && newObject.Status.State == olmv1alpha1.SubscriptionStateUpgradePending && oldObject.Status.State != newObject.Status.State {
+						return true
+					}
 					return (oldObject.Status.InstalledCSV != "" && newObject.Status.InstalledCSV != "" && oldObject.Status.InstalledCSV != newObject.Status.InstalledCSV)
 				}
 				return false
 			},
 			DeleteFunc: func(e event.DeleteEvent) bool {
-				// Evaluates to false if the object has been confirmed deleted.
+				// Recreate the Subscription deleted by someone else
+				return recreateDeleted(e.Object)
+			},
+		})).
+		// The operators waiting for their CatalogSource are subscribed once it is ready
+		Watches(&source.Kind{Type: &olmv1alpha1.CatalogSource{}}, handler.EnqueueRequestsFromMapFunc(r.getCatalogSourceToRequestMapper()), builder.WithPredicates(predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return deploy.CatalogSourceStateChanged(e.ObjectOld, e.ObjectNew)
+			},
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				return !e.DeleteStateUnknown
+			},
+		})).
+		Watches(&source.Kind{Type: &olmv1.OperatorGroup{}}, handler.EnqueueRequestsFromMapFunc(r.getOperatorGroupToRequestMapper()), builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool {
+				return false
+			},
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return false
+			},
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				// Recreate the OperatorGroup deleted by someone else
+				return recreateDeleted(e.Object)
+			},
+			GenericFunc: func(e event.GenericEvent) bool {
+				return false
+			},
+		})).
+		Watches(&source.Kind{Type: &operatorv1alpha1.OperandRequest{}}, handler.EnqueueRequestsFromMapFunc(r.getBlockedRequestMapper()), builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool {
+				return false
+			},
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				// The operands of a request being deleted are released
+				return e.ObjectOld.GetDeletionTimestamp() == nil && e.ObjectNew.GetDeletionTimestamp() != nil
+			},
+			GenericFunc: func(e event.GenericEvent) bool {
 				return false
 			},
 		})).
+		// The OperandBindInfos allowing or denying the namespaces of the requests
+		Watches(&source.Kind{Type: &operatorv1alpha1.OperandBindInfo{}}, handler.EnqueueRequestsFromMapFunc(r.getBindInfoToRequestMapper()), builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// The custom resources and k8s resources deleted by someone else are recreated
+		Watches(&source.Channel{Source: r.watcher.events}, handler.EnqueueRequestsFromMapFunc(r.getSubToRequestMapper())).
 		Watches(&source.Kind{Type: &operatorv1alpha1.OperandRegistry{}}, handler.EnqueueRequestsFromMapFunc(r.getRegistryToRequestMapper()), builder.WithPredicates(predicate.Funcs{
 			UpdateFunc: func(e event.UpdateEvent) bool {
 				oldObject := e.ObjectOld.(*operatorv1alpha1.OperandRegistry)
 				newObject := e.ObjectNew.(*operatorv1alpha1.OperandRegistry)
-				return !reflect.DeepEqual(oldObject.Spec, newObject.Spec)
+				// Forcing the upgrades releases the operators blocked by the ClusterOperandPolicies
+				return !reflect.DeepEqual(oldObject.Spec, newObject.Spec) ||
+					oldObject.GetAnnotations()[operatorv1alpha1.ForceUpgradeAnnotation] != newObject.GetAnnotations()[operatorv1alpha1.ForceUpgradeAnnotation]
 			},
 			DeleteFunc: func(e event.DeleteEvent) bool {
 				// Evaluates to false if the object has been confirmed deleted.
@@ -319,5 +521,13 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 				newObject := e.ObjectNew.(*operatorv1alpha1.OperandConfig)
 				return !reflect.DeepEqual(oldObject.Spec, newObject.Spec)
 			},
-		})).Complete(r)
+		}))
+	// The OperandBootstrap has the same namespace and name as the OperandRegistry
+	b = b.Watches(&source.Kind{Type: &operatorv1alpha1.OperandBootstrap{}}, handler.EnqueueRequestsFromMapFunc(r.getRegistryToRequestMapper()), builder.WithPredicates(predicate.GenerationChangedPredicate{}))
+	// The ClusterOperandPolicies are cluster scoped, they are only watched with all the namespaces
+	if !util.GetIsolatedMode() {
+		b = b.Watches(&source.Kind{Type: &operatorv1alpha1.ClusterOperandPolicy{}}, handler.EnqueueRequestsFromMapFunc(r.getPolicyToRequestMapper()), builder.WithPredicates(predicate.GenerationChangedPredicate{}))
+	}
+	// The requests are dispatched in turn across the namespaces by the fair queue
+	return b.Watches(fair.Source(), &handler.EnqueueRequestForObject{}).Complete(fair.Reconciler(shard.Reconciler(r)))
 }
@@ -21,6 +21,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"reflect"
+	"time"
 
 	v1beta2 "github.com/coreos/etcd-operator/pkg/apis/etcd/v1beta2"
 	. "github.com/onsi/ginkgo"
@@ -28,6 +29,7 @@ import (
 	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
@@ -386,6 +388,48 @@ var _ = Describe("OperandRegistry controller", func() {
 			Expect(k8sClient.Delete(ctx, registry2)).Should(Succeed())
 		})
 
+		It("Should hold the OperandRequest until its notBefore time", func() {
+			By("Creating the OperandRegistry")
+			Expect(k8sClient.Create(ctx, registry1)).Should(Succeed())
+			By("Creating the OperandConfig")
+			Expect(k8sClient.Create(ctx, config1)).Should(Succeed())
+			By("Creating the scheduled OperandRequest")
+			request1.Spec.NotBefore = &metav1.Time{Time: time.Now().Add(time.Hour)}
+			Expect(k8sClient.Create(ctx, request1)).Should(Succeed())
+
+			By("Checking status of the OperandRequest")
+			Eventually(func() operatorv1alpha1.ClusterPhase {
+				requestInstance1 := &operatorv1alpha1.OperandRequest{}
+				Expect(k8sClient.Get(ctx, requestKey1, requestInstance1)).Should(Succeed())
+				return requestInstance1.Status.Phase
+			}, testutil.Timeout, testutil.Interval).Should(Equal(operatorv1alpha1.ClusterPhaseScheduled))
+			Consistently(func() bool {
+				etcdSub := &olmv1alpha1.Subscription{}
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: "etcd", Namespace: operatorNamespaceName}, etcdSub)
+				return errors.IsNotFound(err)
+			}, 3*time.Second, testutil.Interval).Should(BeTrue())
+
+			By("Moving the notBefore time to the past")
+			Eventually(func() error {
+				requestInstance1 := &operatorv1alpha1.OperandRequest{}
+				Expect(k8sClient.Get(ctx, requestKey1, requestInstance1)).Should(Succeed())
+				requestInstance1.Spec.NotBefore = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+				return k8sClient.Update(ctx, requestInstance1)
+			}, testutil.Timeout, testutil.Interval).Should(Succeed())
+			Eventually(func() operatorv1alpha1.ClusterPhase {
+				requestInstance1 := &operatorv1alpha1.OperandRequest{}
+				Expect(k8sClient.Get(ctx, requestKey1, requestInstance1)).Should(Succeed())
+				return requestInstance1.Status.Phase
+			}, testutil.Timeout, testutil.Interval).Should(Equal(operatorv1alpha1.ClusterPhaseInstalling))
+
+			By("Deleting the OperandRequest")
+			Expect(k8sClient.Delete(ctx, request1)).Should(Succeed())
+			By("Deleting the OperandConfig")
+			Expect(k8sClient.Delete(ctx, config1)).Should(Succeed())
+			By("Deleting the OperandRegistry")
+			Expect(k8sClient.Delete(ctx, registry1)).Should(Succeed())
+		})
+
 		It("Should Config Operator by OperandRegistry", func() {
 			By("Creating the OperandRegistry")
 			Expect(k8sClient.Create(ctx, registrywithCfg)).Should(Succeed())
@@ -0,0 +1,59 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/ownership"
+)
+
+var _ = Describe("Track the objects of the OperandRequests", func() {
+
+	It("Should index the watched objects by their OperandRequests", func() {
+		requestA := types.NamespacedName{Name: "a", Namespace: "team-a"}
+		requestB := types.NamespacedName{Name: "b", Namespace: "team-b"}
+		gvk := schema.GroupVersionKind{Group: "etcd.database.coreos.com", Version: "v1beta2", Kind: "EtcdCluster"}
+		informer := cache.NewSharedIndexInformer(nil, &metav1.PartialObjectMetadata{}, 0, cache.Indexers{requestIndex: indexRequests})
+		w := &resourceWatcher{informers: map[schema.GroupVersionKind]map[string]cache.SharedIndexInformer{gvk: {"": informer}}}
+
+		newObject := func(namespace, name string, requests ...types.NamespacedName) *metav1.PartialObjectMetadata {
+			obj := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{}}}
+			for _, requestKey := range requests {
+				for k, v := range ownership.Labels(requestKey) {
+					obj.Labels[k] = v
+				}
+			}
+			return obj
+		}
+		Expect(informer.GetIndexer().Add(newObject("ibm-common-services", "example", requestA, requestB))).Should(Succeed())
+		Expect(informer.GetIndexer().Add(newObject("team-a", "backup", requestA))).Should(Succeed())
+		Expect(informer.GetIndexer().Add(newObject("team-b", "restore", requestB))).Should(Succeed())
+
+		tracked := w.tracked(requestA)
+		Expect(tracked).Should(HaveLen(2))
+		Expect(tracked[0].gvk).Should(Equal(gvk))
+		Expect(tracked[0].obj.GetName()).Should(Equal("example"))
+		Expect(tracked[1].obj.GetName()).Should(Equal("backup"))
+		Expect(w.tracked(types.NamespacedName{Name: "c", Namespace: "team-c"})).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,67 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Pause the operands", func() {
+
+	newRequest := func(paused string) *operatorv1alpha1.OperandRequest {
+		return &operatorv1alpha1.OperandRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "example",
+				Namespace:   "ibm-common-services",
+				Annotations: map[string]string{operatorv1alpha1.PausedOperandsAnnotation: paused},
+			},
+		}
+	}
+
+	It("Should only pause the operands in the annotation", func() {
+		req := newRequest("etcd, jenkins")
+		Expect(req.IsPaused("etcd")).Should(BeTrue())
+		Expect(req.IsPaused("jenkins")).Should(BeTrue())
+		Expect(req.IsPaused("mongodb")).Should(BeFalse())
+		Expect(req.IsPaused("")).Should(BeFalse())
+	})
+
+	It("Should pause all the operands with the wildcard", func() {
+		Expect(newRequest("*").IsPaused("etcd")).Should(BeTrue())
+		Expect(newRequest("").IsPaused("etcd")).Should(BeFalse())
+		Expect((&operatorv1alpha1.OperandRequest{}).IsPaused("etcd")).Should(BeFalse())
+	})
+
+	It("Should set and remove the Paused condition", func() {
+		req := newRequest("etcd")
+		mu := &sync.Mutex{}
+		req.SetPausedCondition("etcd", operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu)
+		req.SetPausedCondition("etcd", operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu)
+		Expect(req.Status.Conditions).Should(HaveLen(1))
+		Expect(req.Status.Conditions[0].Type).Should(Equal(operatorv1alpha1.ConditionPaused))
+
+		req.RemovePausedCondition("etcd", operatorv1alpha1.ResourceTypeOperator, mu)
+		Expect(req.Status.Conditions).Should(BeEmpty())
+	})
+})
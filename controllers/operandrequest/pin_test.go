@@ -0,0 +1,69 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Pin the version of an operator", func() {
+
+	It("Should override the pinned version of the OperandRegistry with the operand", func() {
+		opt := &operatorv1alpha1.Operator{Name: "etcd", PinnedVersion: "0.9.4"}
+		Expect(PinnedOperator(opt, operatorv1alpha1.Operand{Name: "etcd"})).Should(BeIdenticalTo(opt))
+
+		pinned := PinnedOperator(opt, operatorv1alpha1.Operand{Name: "etcd", PinnedVersion: "etcd.v0.9.2"})
+		Expect(pinned.PinnedVersion).Should(Equal("etcd.v0.9.2"))
+		Expect(opt.PinnedVersion).Should(Equal("0.9.4"))
+	})
+
+	It("Should return the versions pinned by the OperandRequests", func() {
+		sub := &olmv1alpha1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					pinAnnotationKey(types.NamespacedName{Namespace: "ns1", Name: "a"}): "0.9.4",
+					pinAnnotationKey(types.NamespacedName{Namespace: "ns2", Name: "b"}): "0.9.2",
+					pinAnnotationKey(types.NamespacedName{Namespace: "ns3", Name: "c"}): "0.9.4",
+					"ns1.a/request": "true",
+				},
+			},
+		}
+		Expect(pinAnnotationKey(types.NamespacedName{Namespace: "ns1", Name: "a"})).Should(Equal("ns1.a/pinned-version"))
+		Expect(getPins(sub)).Should(Equal([]string{"0.9.2", "0.9.4"}))
+	})
+
+	It("Should set the VersionPinned condition once per InstallPlan", func() {
+		req := &operatorv1alpha1.OperandRequest{}
+		mu := &sync.Mutex{}
+		Expect(req.SetVersionPinnedCondition("etcd", "install-abc", "etcd.v0.9.5", "0.9.4", operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu)).Should(BeTrue())
+		Expect(req.SetVersionPinnedCondition("etcd", "install-abc", "etcd.v0.9.5", "0.9.4", operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu)).Should(BeFalse())
+		Expect(req.SetVersionPinnedCondition("etcd", "install-def", "etcd.v0.9.6", "0.9.4", operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu)).Should(BeTrue())
+		Expect(req.Status.Conditions).Should(HaveLen(1))
+
+		req.RemoveVersionPinnedCondition("etcd", operatorv1alpha1.ResourceTypeOperator, mu)
+		Expect(req.Status.Conditions).Should(BeEmpty())
+	})
+})
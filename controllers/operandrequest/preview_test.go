@@ -0,0 +1,61 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Preview the deletion", func() {
+
+	It("Should retain the deleted resources of the operands requested by the other OperandRequests", func() {
+		sub := operatorv1alpha1.PreviewResource{Kind: "Subscription", Namespace: "ibm-common-services", Name: "etcd", Operand: "etcd"}
+		cr := operatorv1alpha1.PreviewResource{Kind: "EtcdCluster", Namespace: "ibm-common-services", Name: "example", Operand: "etcd"}
+
+		operandPreview := &operatorv1alpha1.DeletionPreview{}
+		operandPreview.AddDeleted(sub)
+		operandPreview.AddRetained(cr, operatorv1alpha1.PreviewReasonNotCreatedByODLM)
+
+		preview := &operatorv1alpha1.DeletionPreview{}
+		preview.Merge(operandPreview, "")
+		Expect(preview.Deleted).Should(ConsistOf(sub))
+		Expect(preview.Retained).Should(HaveLen(1))
+
+		preview = &operatorv1alpha1.DeletionPreview{}
+		preview.Merge(operandPreview, operatorv1alpha1.PreviewReasonRequestedByOthers)
+		Expect(preview.Deleted).Should(BeEmpty())
+		Expect(preview.Retained).Should(HaveLen(2))
+		Expect(preview.Retained[0].Reason).Should(Equal(operatorv1alpha1.PreviewReasonRequestedByOthers))
+		Expect(preview.Retained[1].Reason).Should(Equal(operatorv1alpha1.PreviewReasonNotCreatedByODLM))
+	})
+
+	It("Should find the objects requested with the other OperandRegistries", func() {
+		registry := &operatorv1alpha1.OperandRegistry{ObjectMeta: metav1.ObjectMeta{Name: "common-service", Namespace: "ibm-common-services"}}
+		Expect(requestedWithOtherRegistries(map[string]string{
+			"ibm-common-services.common-service/registry": "true",
+			"ibm-common-services.common-service/config":   "true",
+		}, registry)).Should(BeFalse())
+		Expect(requestedWithOtherRegistries(map[string]string{
+			"ibm-common-services.common-service/registry": "true",
+			"cp4d.cp4d-registry/registry":                 "true",
+		}, registry)).Should(BeTrue())
+	})
+})
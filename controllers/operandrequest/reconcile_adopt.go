@@ -0,0 +1,87 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/ownership"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// adoptCustomResource takes the ownership of an existing custom resource not created by ODLM, if it has the
+// adopt annotation. Otherwise, it reports a Conflict condition and leaves the custom resource as it is.
+func (r *Reconciler) adoptCustomResource(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, existingCR unstructured.Unstructured) (bool, error) {
+	log := logging.FromContext(ctx)
+	name := existingCR.GetKind() + " " + existingCR.GetNamespace() + "/" + existingCR.GetName()
+	if !r.CheckAnnotation(existingCR, map[string]string{constant.AdoptAnnotation: "true"}) {
+		log.V(logging.LevelChange).Info("Skip the custom resource not created by ODLM", "kind", existingCR.GetKind(), "name", existingCR.GetNamespace()+"/"+existingCR.GetName(), "annotation", constant.AdoptAnnotation)
+		requestInstance.SetConflictCondition(name, operatorv1alpha1.ResourceTypeOperand, corev1.ConditionTrue, &r.Mutex)
+		return false, nil
+	}
+
+	adoptedCR := existingCR.DeepCopy()
+	r.EnsureLabel(*adoptedCR, map[string]string{constant.OpreqLabel: "true"})
+	r.EnsureLabel(*adoptedCR, requestLabel(requestInstance))
+	r.EnsureAnnotation(*adoptedCR, requestAnnotation(requestInstance))
+	if err := r.Patch(ctx, adoptedCR, client.MergeFrom(&existingCR)); err != nil {
+		return false, errors.Wrapf(err, "failed to adopt custom resource -- Kind: %s, NamespacedName: %s/%s", existingCR.GetKind(), existingCR.GetNamespace(), existingCR.GetName())
+	}
+	log.Info("Adopted the existing custom resource", "kind", existingCR.GetKind(), "name", existingCR.GetNamespace()+"/"+existingCR.GetName())
+	r.Recorder.Eventf(requestInstance, corev1.EventTypeNormal, constant.EventReasonOperandAdopted, "Adopted the existing custom resource %s", name)
+	return true, nil
+}
+
+// releaseCustomResource strips the ownership of ODLM from an adopted custom resource, instead of deleting it
+func (r *Reconciler) releaseCustomResource(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, existingCR unstructured.Unstructured) error {
+	releasedCR := existingCR.DeepCopy()
+	labels := releasedCR.GetLabels()
+	delete(labels, constant.OpreqLabel)
+	for label := range labels {
+		if ownership.IsKey(label) {
+			delete(labels, label)
+		}
+	}
+	releasedCR.SetLabels(labels)
+	annotations := releasedCR.GetAnnotations()
+	delete(annotations, constant.LastAppliedConfigAnnotation)
+	for anno := range annotations {
+		if ownership.IsKey(anno) {
+			delete(annotations, anno)
+		}
+	}
+	releasedCR.SetAnnotations(annotations)
+	if err := r.Patch(ctx, releasedCR, client.MergeFrom(&existingCR)); err != nil {
+		return errors.Wrapf(err, "failed to release custom resource -- Kind: %s, NamespacedName: %s/%s", existingCR.GetKind(), existingCR.GetNamespace(), existingCR.GetName())
+	}
+	name := existingCR.GetKind() + " " + existingCR.GetNamespace() + "/" + existingCR.GetName()
+	logging.FromContext(ctx).Info("Released the adopted custom resource", "kind", existingCR.GetKind(), "name", existingCR.GetNamespace()+"/"+existingCR.GetName())
+	r.Recorder.Eventf(requestInstance, corev1.EventTypeNormal, constant.EventReasonOperandReleased, "Released the adopted custom resource %s", name)
+	return nil
+}
+
+// isAdopted returns true if the custom resource is adopted by ODLM
+func (r *Reconciler) isAdopted(cr unstructured.Unstructured) bool {
+	return r.CheckLabel(cr, map[string]string{constant.OpreqLabel: "true"}) && r.CheckAnnotation(cr, map[string]string{constant.AdoptAnnotation: "true"})
+}
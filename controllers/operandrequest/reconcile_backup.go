@@ -0,0 +1,67 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/backup"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// reconcileBackup validates the backup policy of the service, and adds its labels to the namespace of the custom resources
+// so the Backups selecting the namespaces by labels include the service.
+// The labels removed from the backup policy are left on the namespace, other services may still declare them
+func (r *Reconciler) reconcileBackup(ctx context.Context, service *operatorv1alpha1.ConfigService, namespace string) error {
+	if service.Backup == nil {
+		return nil
+	}
+	if err := backup.Validate(service.Backup); err != nil {
+		return errors.Wrapf(err, "failed to reconcile the backup policy of operator %s", service.Name)
+	}
+	labels := backup.Labels(service.Backup)
+	if len(labels) == 0 {
+		return nil
+	}
+
+	ns := &corev1.Namespace{}
+	if err := r.Reader.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return errors.Wrapf(err, "failed to get namespace %s", namespace)
+	}
+	missing := backup.MissingLabels(ns.GetLabels(), labels)
+	if len(missing) == 0 {
+		return nil
+	}
+	original := ns.DeepCopy()
+	if ns.Labels == nil {
+		ns.Labels = make(map[string]string)
+	}
+	for k, v := range missing {
+		ns.Labels[k] = v
+	}
+	if err := r.Client.Patch(ctx, ns, client.MergeFrom(original)); err != nil {
+		return errors.Wrapf(err, "failed to add the backup labels to namespace %s", namespace)
+	}
+	logging.FromContext(ctx).V(logging.LevelChange).Info("Added the backup labels to the namespace", "namespace", namespace, "operator", service.Name)
+	return nil
+}
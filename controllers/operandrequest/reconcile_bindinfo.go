@@ -0,0 +1,93 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+// reconcileBindInfoPermissions sets the PermissionDenied condition of the operands whose
+// OperandBindInfo doesn't allow the namespace of the request
+func (r *Reconciler) reconcileBindInfoPermissions(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) error {
+	var nsLabels map[string]string
+	nsLoaded := false
+	for _, req := range requestInstance.Spec.Requests {
+		registryKey := requestInstance.GetRegistryKey(req)
+		bindInfoList := &operatorv1alpha1.OperandBindInfoList{}
+		if err := r.Client.List(ctx, bindInfoList, client.MatchingLabels{registryKey.Namespace + "." + registryKey.Name + "/registry": "true"}); err != nil {
+			return errors.Wrapf(err, "failed to list the OperandBindInfos of OperandRegistry %s", registryKey.String())
+		}
+		for _, operand := range req.Operands {
+			denied := ""
+			for i := range bindInfoList.Items {
+				bindInfo := &bindInfoList.Items[i]
+				if bindInfo.Spec.Operand != operand.Name || bindInfo.Spec.AllowedNamespaces == nil {
+					continue
+				}
+				if !nsLoaded {
+					ns := &corev1.Namespace{}
+					if err := r.Reader.Get(ctx, types.NamespacedName{Name: requestInstance.Namespace}, ns); err != nil && !apierrors.IsNotFound(err) {
+						return errors.Wrapf(err, "failed to get namespace %s", requestInstance.Namespace)
+					}
+					nsLabels, nsLoaded = ns.GetLabels(), true
+				}
+				allowed, err := bindInfo.AllowsNamespace(requestInstance.Namespace, nsLabels)
+				if err != nil {
+					return errors.Wrapf(err, "invalid allowedNamespaces of OperandBindInfo %s/%s", bindInfo.Namespace, bindInfo.Name)
+				}
+				if !allowed {
+					denied = bindInfo.Namespace + "/" + bindInfo.Name
+					break
+				}
+			}
+			if denied != "" {
+				requestInstance.SetPermissionDeniedCondition(operand.Name, denied, operatorv1alpha1.ResourceTypeOperand, corev1.ConditionTrue, &r.Mutex)
+			} else {
+				requestInstance.RemovePermissionDeniedCondition(operand.Name, operatorv1alpha1.ResourceTypeOperand, &r.Mutex)
+			}
+		}
+	}
+	return nil
+}
+
+// getBindInfoToRequestMapper maps the OperandBindInfos to the OperandRequests using their OperandRegistry
+func (r *Reconciler) getBindInfoToRequestMapper() handler.MapFunc {
+	ctx := context.Background()
+	return func(object client.Object) []ctrl.Request {
+		bindInfo, ok := object.(*operatorv1alpha1.OperandBindInfo)
+		if !ok {
+			return nil
+		}
+		requestList, _ := r.ListOperandRequestsByRegistry(ctx, bindInfo.GetRegistryKey())
+
+		requests := []ctrl.Request{}
+		for _, request := range requestList {
+			requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Name: request.Name, Namespace: request.Namespace}})
+		}
+		return requests
+	}
+}
@@ -0,0 +1,54 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/bootstrap"
+)
+
+// checkBootstrap returns why the first install of the operator waits for the OperandBootstrap of the registry,
+// an empty message when the operator can be installed.
+// The operators already installed for the request are never held, the order only applies to their first install.
+func (r *Reconciler) checkBootstrap(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, registryKey types.NamespacedName, opt *operatorv1alpha1.Operator, mu sync.Locker) (string, error) {
+	if getOperatorPhase(requestInstance, opt.Name, mu) != operatorv1alpha1.OperatorNone && !requestInstance.IsWaitingForDependencies(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu) {
+		return "", nil
+	}
+	b, err := bootstrap.Get(ctx, r.Client, registryKey)
+	if err != nil || b == nil || b.GetStep(opt.Name) == nil {
+		return "", err
+	}
+	// Hold the operators of an invalid OperandBootstrap, their order is unknown
+	if err := bootstrap.Validate(b, registryInstance); err != nil {
+		return "The OperandBootstrap " + registryKey.String() + " is invalid: " + err.Error(), nil
+	}
+	requests, err := r.ListOperandRequestsByRegistry(ctx, registryKey)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to list the OperandRequests of OperandRegistry %s", registryKey.String())
+	}
+	waiting := bootstrap.Waiting(b, opt.Name, registryKey, requests)
+	if len(waiting) == 0 {
+		return "", nil
+	}
+	return bootstrap.Message(opt.Name, waiting), nil
+}
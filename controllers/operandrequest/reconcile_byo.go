@@ -0,0 +1,101 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"sync"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/bundle"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+func init() {
+	RegisterInstaller(operatorv1alpha1.OperatorTypeBYO, func(r *Reconciler) Installer { return &byoInstaller{r} })
+}
+
+// byoInstaller handles the operator brought by the user. It never installs or uninstalls the operator,
+// it only verifies the operator Deployment is present and available before creating the operands.
+type byoInstaller struct {
+	*Reconciler
+}
+
+func (i *byoInstaller) Install(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, opt *operatorv1alpha1.Operator, registryKey types.NamespacedName, mu sync.Locker) error {
+	_, err := i.verifyBYOOperator(ctx, requestInstance, opt, mu)
+	return err
+}
+
+// Uninstall only deletes the operands created by ODLM, the operator is left to the user
+func (i *byoInstaller) Uninstall(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, configInstance *operatorv1alpha1.OperandConfig, opt *operatorv1alpha1.Operator) error {
+	namespace := i.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
+	logging.FromContext(ctx).V(logging.LevelChange).Info("Deleting all the Custom Resources for the operator brought by the user", "operator", opt.Name)
+	if err := i.deleteAllCustomResource(ctx, bundle.EmptyCSV(opt.Name, namespace), requestInstance, configInstance, opt.Name, opt.Namespace); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).V(logging.LevelChange).Info("Deleting all the k8s Resources for the operator brought by the user", "operator", opt.Name)
+	return i.deleteAllK8sResource(ctx, configInstance, opt.Name, opt.Namespace)
+}
+
+// PreviewUninstall previews the custom resources and the k8s resources deleted by Uninstall, the operator is kept
+func (i *byoInstaller) PreviewUninstall(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, configInstance *operatorv1alpha1.OperandConfig, opt *operatorv1alpha1.Operator, preview *operatorv1alpha1.DeletionPreview) error {
+	return i.previewOperands(ctx, bundle.EmptyCSV(opt.Name, i.GetOperatorNamespace(opt.InstallMode, opt.Namespace)), requestInstance, configInstance, opt.Name, opt.Namespace, preview)
+}
+
+// GetUninstallCSV returns an empty ClusterServiceVersion, the operands are always deleted by Uninstall
+func (i *byoInstaller) GetUninstallCSV(ctx context.Context, registryInstance *operatorv1alpha1.OperandRegistry, opt *operatorv1alpha1.Operator) (*olmv1alpha1.ClusterServiceVersion, error) {
+	return bundle.EmptyCSV(opt.Name, i.GetOperatorNamespace(opt.InstallMode, opt.Namespace)), nil
+}
+
+// GetCSV returns an empty ClusterServiceVersion once the operator Deployment is available
+func (i *byoInstaller) GetCSV(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, opt *operatorv1alpha1.Operator, mu sync.Locker) (*olmv1alpha1.ClusterServiceVersion, error) {
+	available, err := i.verifyBYOOperator(ctx, requestInstance, opt, mu)
+	if err != nil || !available {
+		return nil, err
+	}
+	return bundle.EmptyCSV(opt.Name, i.GetOperatorNamespace(opt.InstallMode, opt.Namespace)), nil
+}
+
+// verifyBYOOperator returns true if the Deployment of the operator brought by the user is available
+func (r *Reconciler) verifyBYOOperator(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, opt *operatorv1alpha1.Operator, mu sync.Locker) (bool, error) {
+	// Deployments are filtered in the cache, get it from the API server
+	deploy := &appsv1.Deployment{}
+	deployKey := types.NamespacedName{Namespace: r.GetOperatorNamespace(opt.InstallMode, opt.Namespace), Name: opt.GetDeploymentName()}
+	if err := r.Reader.Get(ctx, deployKey, deploy); err != nil {
+		if apierrors.IsNotFound(err) {
+			logging.FromContext(ctx).Info("The Deployment of the operator brought by the user is not found", "deployment", deployKey.String(), "operator", opt.Name)
+			requestInstance.SetNotFoundOperatorFromRegistryCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu)
+			requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorNotFound, "", mu)
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get the Deployment %s of the operator %s", deployKey.String(), opt.Name)
+	}
+	if deploy.Status.AvailableReplicas == 0 {
+		logging.FromContext(ctx).Info("The Deployment of the operator brought by the user is not available yet, retry", "deployment", deployKey.String(), "operator", opt.Name)
+
+		requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorInstalling, "", mu)
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,119 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/callback"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// notifiedDeletions remembers the operands whose PreDelete callbacks are called, so the callbacks are called once
+// while the deletion is retried. The operands are keyed by the UID of their OperandRequest.
+type notifiedDeletions struct {
+	sync.Mutex
+	operands map[types.UID]map[string]bool
+}
+
+// notify calls the callbacks of the event for the operand. It returns an error if a callback with the Fail policy fails,
+// the failures of the other callbacks are only recorded as Events.
+func (r *Reconciler) notify(ctx context.Context, event operatorv1alpha1.CallbackEvent, requestInstance *operatorv1alpha1.OperandRequest, registryKey types.NamespacedName, opt *operatorv1alpha1.Operator) error {
+	callbacks := callback.Get()
+	if len(callbacks) == 0 {
+		return nil
+	}
+	notifier := &callback.Notifier{Reader: r.Reader, Namespace: util.GetOperatorNamespace()}
+	failures := notifier.Notify(ctx, callbacks, callback.Payload{
+		Event:          event,
+		Operand:        opt.Name,
+		OperandRequest: callback.Reference{Namespace: requestInstance.Namespace, Name: requestInstance.Name},
+		Registry:       callback.Reference{Namespace: registryKey.Namespace, Name: registryKey.Name},
+		PackageName:    opt.PackageName,
+		Channel:        opt.Channel,
+		Namespace:      opt.Namespace,
+		Timestamp:      time.Now().UTC(),
+	})
+
+	merr := &util.MultiErr{}
+	for _, f := range failures {
+		logging.FromContext(ctx).Info("Callback failed", "callback", f.Name, "event", event, "operand", opt.Name, "ignored", f.Ignored, "error", f.Err.Error())
+		r.Recorder.Eventf(requestInstance, corev1.EventTypeWarning, constant.EventReasonCallbackFailed, "%s callback %s of operand %s failed: %v", event, f.Name, opt.Name, f.Err)
+		if !f.Ignored {
+			merr.Add(f.Err)
+		}
+	}
+	if len(merr.Errors) != 0 {
+		return fmt.Errorf("%s callbacks of operand %s failed: %v", event, opt.Name, merr)
+	}
+	return nil
+}
+
+// notifyPreDelete calls the PreDelete callbacks of the operand once for the OperandRequest
+func (r *Reconciler) notifyPreDelete(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryKey types.NamespacedName, opt *operatorv1alpha1.Operator) error {
+	r.deletions.Lock()
+	notified := r.deletions.operands[requestInstance.UID][opt.Name]
+	r.deletions.Unlock()
+	if notified {
+		return nil
+	}
+	if err := r.notify(ctx, operatorv1alpha1.CallbackPreDelete, requestInstance, registryKey, opt); err != nil {
+		return err
+	}
+	r.deletions.Lock()
+	defer r.deletions.Unlock()
+	if r.deletions.operands == nil {
+		r.deletions.operands = make(map[types.UID]map[string]bool)
+	}
+	if r.deletions.operands[requestInstance.UID] == nil {
+		r.deletions.operands[requestInstance.UID] = make(map[string]bool)
+	}
+	r.deletions.operands[requestInstance.UID][opt.Name] = true
+	return nil
+}
+
+// forgetPreDelete forgets the PreDelete callbacks of the operand, or of all the operands if the name is empty
+func (r *Reconciler) forgetPreDelete(requestInstance *operatorv1alpha1.OperandRequest, name string) {
+	r.deletions.Lock()
+	defer r.deletions.Unlock()
+	if name == "" {
+		delete(r.deletions.operands, requestInstance.UID)
+		return
+	}
+	delete(r.deletions.operands[requestInstance.UID], name)
+}
+
+// getOperandPhase returns the phase of the operand in the OperandRequest status
+func getOperandPhase(requestInstance *operatorv1alpha1.OperandRequest, name string, mu sync.Locker) operatorv1alpha1.ServicePhase {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, m := range requestInstance.Status.Members {
+		if m.Name == name {
+			return m.Phase.OperandPhase
+		}
+	}
+	return operatorv1alpha1.ServiceNone
+}
@@ -0,0 +1,80 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// checkCatalogSource reports the CatalogSource of the operator which can't serve it, and returns true if it is unhealthy
+func (r *Reconciler) checkCatalogSource(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, opt *operatorv1alpha1.Operator, registryKey types.NamespacedName, mu sync.Locker) (bool, error) {
+	// The CatalogSource is unknown when no PackageManifest provides the operator
+	if opt.SourceName == "" || opt.SourceNamespace == "" {
+		return false, nil
+	}
+	message, err := r.GetCatalogSourceHealth(ctx, opt.SourceName, opt.SourceNamespace)
+	if err != nil {
+		return false, err
+	}
+	if message == "" {
+		requestInstance.RemoveCatalogUnhealthyCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+		return false, nil
+	}
+	logging.FromContext(ctx).V(logging.LevelFlow).Info("Waiting for the CatalogSource of the operator to be ready", "catalogSource", opt.SourceNamespace+"/"+opt.SourceName, "reason", message)
+	if requestInstance.SetCatalogUnhealthyCondition(opt.Name, message, operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu) {
+		r.recordOperatorEvent(requestInstance, r.getRegistryForEvent(ctx, registryKey), corev1.EventTypeWarning, constant.EventReasonCatalogUnhealthy, "Waiting for the CatalogSource of operator %s: %s", opt.Name, message)
+	}
+	return true, nil
+}
+
+// getCatalogSourceToRequestMapper maps a CatalogSource to the OperandRequests of the OperandRegistries installing operators from it
+func (r *Reconciler) getCatalogSourceToRequestMapper() handler.MapFunc {
+	ctx := context.Background()
+	return func(object client.Object) []ctrl.Request {
+		registries, err := r.ListOperandRegistriesByCatalogSource(ctx, object.GetName(), object.GetNamespace())
+		if err != nil {
+			return nil
+		}
+		seen := make(map[types.NamespacedName]bool)
+		requests := []ctrl.Request{}
+		for _, registry := range registries {
+			requestList, err := r.ListOperandRequestsByRegistry(ctx, types.NamespacedName{Name: registry.Name, Namespace: registry.Namespace})
+			if err != nil {
+				continue
+			}
+			for _, request := range requestList {
+				key := types.NamespacedName{Name: request.Name, Namespace: request.Namespace}
+				if !seen[key] {
+					seen[key] = true
+					requests = append(requests, ctrl.Request{NamespacedName: key})
+				}
+			}
+		}
+		return requests
+	}
+}
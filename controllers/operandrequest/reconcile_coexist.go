@@ -0,0 +1,168 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"strings"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// resolveOperator returns the version of the operator used by the OperandRequest. The OperandRequests already
+// recorded in the Subscription of the previous version of a coexisting operator keep it until they are migrated.
+func (r *Reconciler) resolveOperator(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, operandName string) (*operatorv1alpha1.Operator, error) {
+	opt := registryInstance.GetOperator(operandName)
+	if opt == nil {
+		return nil, nil
+	}
+	previous := opt.GetPreviousVersion()
+	if previous == nil || requestInstance.IsMigrated(operandName) {
+		return opt, nil
+	}
+	sub, err := r.getPreviousSubscription(ctx, previous)
+	if err != nil || sub == nil {
+		return opt, err
+	}
+	if _, ok := sub.Annotations[requestAnnotationKey(requestInstance)]; ok {
+		logging.FromContext(ctx).V(logging.LevelChange).Info("OperandRequest keeps the previous version of the operator", "namespace", previous.Namespace, "channel", previous.Channel, "annotation", operatorv1alpha1.MigratedOperandsAnnotation)
+		return previous, nil
+	}
+	return opt, nil
+}
+
+// releasePreviousVersion removes the OperandRequest from the Subscription of the previous version of the operator,
+// and uninstalls the previous version when no other OperandRequest uses it.
+func (r *Reconciler) releasePreviousVersion(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, opt *operatorv1alpha1.Operator) error {
+	log := logging.FromContext(ctx)
+	previous := opt.GetPreviousVersion()
+	if previous == nil {
+		return nil
+	}
+	sub, err := r.getPreviousSubscription(ctx, previous)
+	if err != nil || sub == nil {
+		return err
+	}
+	if _, ok := sub.Annotations[requestAnnotationKey(requestInstance)]; ok {
+		originalSub := sub.DeepCopy()
+		delete(sub.Annotations, requestAnnotationKey(requestInstance))
+		if err := r.Patch(ctx, sub, client.MergeFrom(originalSub)); err != nil {
+			return err
+		}
+		log.V(logging.LevelFlow).Info("OperandRequest is migrated from the previous version of the operator", "subscription", sub.Namespace+"/"+sub.Name)
+	}
+
+	consumers, err := r.getPreviousVersionConsumers(ctx, registryInstance, sub, opt.Name)
+	if err != nil {
+		return err
+	}
+	if len(consumers) != 0 {
+		log.V(logging.LevelChange).Info("Previous version of the operator is still used", "subscription", sub.Namespace+"/"+sub.Name, "requests", strings.Join(consumers, ","))
+		return nil
+	}
+
+	configInstance, err := r.GetOperandConfig(ctx, types.NamespacedName{Namespace: registryInstance.Namespace, Name: registryInstance.Name})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		configInstance = &operatorv1alpha1.OperandConfig{}
+	}
+	log.Info("Uninstalling the previous version of the operator, all the OperandRequests are migrated", "subscription", sub.Namespace+"/"+sub.Name)
+	return r.uninstallSubscription(ctx, requestInstance, registryInstance, configInstance, previous)
+}
+
+// releasePreviousVersions releases the previous versions of all the coexisting operators of the OperandRequest
+func (r *Reconciler) releasePreviousVersions(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) error {
+	for _, req := range requestInstance.Spec.Requests {
+		registryInstance, err := r.GetOperandRegistry(ctx, requestInstance.GetRegistryKey(req))
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		for _, operand := range registryInstance.WithRequiredOperands(req.Operands) {
+			opt := registryInstance.GetOperator(operand.Name)
+			if opt == nil {
+				continue
+			}
+			if err := r.releasePreviousVersion(ctx, requestInstance, registryInstance, opt); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getPreviousVersionConsumers returns the OperandRequests, which are not deleted or migrated,
+// still requesting the operator from the Subscription of its previous version.
+func (r *Reconciler) getPreviousVersionConsumers(ctx context.Context, registryInstance *operatorv1alpha1.OperandRegistry, sub *olmv1alpha1.Subscription, operandName string) ([]string, error) {
+	registryKey := types.NamespacedName{Namespace: registryInstance.Namespace, Name: registryInstance.Name}
+	requestList, err := r.ListOperandRequestsByRegistry(ctx, registryKey)
+	if err != nil {
+		return nil, err
+	}
+	var consumers []string
+	for i := range requestList {
+		item := &requestList[i]
+		if !item.DeletionTimestamp.IsZero() || item.IsMigrated(operandName) {
+			continue
+		}
+		if _, ok := sub.Annotations[requestAnnotationKey(item)]; !ok {
+			continue
+		}
+		for _, req := range item.Spec.Requests {
+			if item.GetRegistryKey(req).String() != registryKey.String() {
+				continue
+			}
+			for _, operand := range registryInstance.WithRequiredOperands(req.Operands) {
+				if operand.Name == operandName {
+					consumers = append(consumers, item.Namespace+"/"+item.Name)
+				}
+			}
+		}
+	}
+	return consumers, nil
+}
+
+// getPreviousSubscription returns the Subscription of the previous version created by ODLM, or nil if there is none
+func (r *Reconciler) getPreviousSubscription(ctx context.Context, previous *operatorv1alpha1.Operator) (*olmv1alpha1.Subscription, error) {
+	sub, err := r.GetSubscription(ctx, previous.Name, previous.Namespace, previous.PackageName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if _, ok := sub.Labels[constant.OpreqLabel]; !ok {
+		return nil, nil
+	}
+	return sub, nil
+}
+
+// requestAnnotationKey returns the key of the annotation recording the OperandRequest in a Subscription
+func requestAnnotationKey(requestInstance *operatorv1alpha1.OperandRequest) string {
+	return requestInstance.Namespace + "." + requestInstance.Name + "/request"
+}
@@ -0,0 +1,143 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"sync"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	operatorsv1 "github.com/operator-framework/operator-lifecycle-manager/pkg/package-server/apis/operators/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/compatibility"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// checkConsumers checks if the channel switch of the Subscription removes
+// any API used by the OperandRequests of the operator.
+// It returns true and sets the IncompatibleConsumers condition when the upgrade is blocked.
+func (r *Reconciler) checkConsumers(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, opt *operatorv1alpha1.Operator, registryKey types.NamespacedName, sub *olmv1alpha1.Subscription, fromChannel string, mu sync.Locker) (bool, error) {
+	consumers, err := r.listConsumers(ctx, opt.Name, registryKey)
+	if err != nil {
+		return false, err
+	}
+	if len(consumers) == 0 {
+		requestInstance.RemoveIncompatibleConsumersCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+		return false, nil
+	}
+
+	from, to, err := r.getChannelCRDs(ctx, sub, fromChannel)
+	if err != nil {
+		return false, err
+	}
+	if from == nil || to == nil {
+		logging.FromContext(ctx).V(logging.LevelChange).Info("Not found the channels of the PackageManifest, skip checking the consumers of operator", "fromChannel", fromChannel, "toChannel", sub.Spec.Channel, "package", sub.Spec.Package, "operator", opt.Name)
+		requestInstance.RemoveIncompatibleConsumersCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+		return false, nil
+	}
+
+	namespaces, apis := compatibility.Incompatible(consumers, compatibility.RemovedAPIs(from, to))
+	if len(namespaces) == 0 {
+		requestInstance.RemoveIncompatibleConsumersCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+		return false, nil
+	}
+	logging.FromContext(ctx).Info("Blocked the upgrade of operator, the APIs are used by the OperandRequests", "operator", opt.Name, "channel", sub.Spec.Channel, "apis", apis, "namespaces", namespaces)
+
+	requestInstance.SetIncompatibleConsumersCondition(opt.Name, sub.Spec.Channel, namespaces, apis, operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu)
+	r.Recorder.Eventf(requestInstance, corev1.EventTypeWarning, "IncompatibleConsumers", "The upgrade of operator %s to channel %s is blocked", opt.Name, sub.Spec.Channel)
+	return true, nil
+}
+
+// listConsumers lists the OperandRequests declaring the APIs of the operator they use
+func (r *Reconciler) listConsumers(ctx context.Context, name string, registryKey types.NamespacedName) ([]compatibility.Consumer, error) {
+	requests, err := r.ListOperandRequestsByRegistry(ctx, registryKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the OperandRequests of OperandRegistry %s", registryKey.String())
+	}
+	var consumers []compatibility.Consumer
+	seen := make(map[types.NamespacedName]bool)
+	for _, request := range requests {
+		key := types.NamespacedName{Namespace: request.Namespace, Name: request.Name}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		consumer := compatibility.Consumer{Namespace: request.Namespace, Name: request.Name}
+		for _, req := range request.Spec.Requests {
+			if request.GetRegistryKey(req) != registryKey {
+				continue
+			}
+			for i := range req.Operands {
+				if req.Operands[i].Name == name {
+					consumer.Uses = append(consumer.Uses, req.Operands[i].GetUses()...)
+				}
+			}
+		}
+		if len(consumer.Uses) > 0 {
+			consumers = append(consumers, consumer)
+		}
+	}
+	return consumers, nil
+}
+
+// getChannelCRDs returns the CRDs owned by the current CSVs of the previous
+// and the target channels of the Subscription, from its PackageManifest.
+// They are nil when the channels are not found.
+func (r *Reconciler) getChannelCRDs(ctx context.Context, sub *olmv1alpha1.Subscription, fromChannel string) (from, to []olmv1alpha1.CRDDescription, err error) {
+	channels, err := r.getPackageChannels(ctx, sub)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, channel := range channels {
+		owned := channel.CurrentCSVDesc.CustomResourceDefinitions.Owned
+		if owned == nil {
+			owned = []olmv1alpha1.CRDDescription{}
+		}
+		if channel.Name == fromChannel {
+			from = owned
+		}
+		if channel.Name == sub.Spec.Channel {
+			to = owned
+		}
+	}
+	return from, to, nil
+}
+
+// getPackageChannels returns the channels of the package of the Subscription, from the PackageManifest of its CatalogSource
+func (r *Reconciler) getPackageChannels(ctx context.Context, sub *olmv1alpha1.Subscription) ([]operatorsv1.PackageChannel, error) {
+	packageManifestList := &operatorsv1.PackageManifestList{}
+	opts := []client.ListOption{
+		client.MatchingFields{"metadata.name": sub.Spec.Package},
+		client.InNamespace(sub.Namespace),
+	}
+	if err := r.Reader.List(ctx, packageManifestList, opts...); err != nil {
+		return nil, errors.Wrapf(err, "failed to list the PackageManifest %s", sub.Spec.Package)
+	}
+	var channels []operatorsv1.PackageChannel
+	for _, pm := range packageManifestList.Items {
+		if pm.Status.CatalogSource != sub.Spec.CatalogSource || pm.Status.CatalogSourceNamespace != sub.Spec.CatalogSourceNamespace {
+			continue
+		}
+		channels = append(channels, pm.Status.Channels...)
+	}
+	return channels, nil
+}
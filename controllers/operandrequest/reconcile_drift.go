@@ -0,0 +1,134 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// startDriftDiff clears the drift diff in the status before the operands are reconciled,
+// the drifted custom resources record their fields in it when they are compared with the configuration.
+func startDriftDiff(requestInstance *operatorv1alpha1.OperandRequest) {
+	requestInstance.Status.DriftDiff = nil
+}
+
+// finishDriftDiff sorts the drift diff collected from the operands
+func finishDriftDiff(requestInstance *operatorv1alpha1.OperandRequest) {
+	diff := requestInstance.Status.DriftDiff
+	sort.SliceStable(diff, func(i, j int) bool {
+		a, b := diff[i], diff[j]
+		if a.Operand != b.Operand {
+			return a.Operand < b.Operand
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+}
+
+// recordDriftDiff records the desired and the live values of the drifted fields of the custom resource in the drift diff
+func (r *Reconciler) recordDriftDiff(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, operandName string, cr unstructured.Unstructured, specRaw, desiredRaw []byte, drifted []string) {
+	if len(drifted) == 0 {
+		return
+	}
+	fields, err := driftedFields(specRaw, desiredRaw, drifted)
+	if err != nil {
+		logging.FromContext(ctx).Error(err, "Failed to compare the drifted fields of the custom resource", "kind", cr.GetKind(), "name", cr.GetNamespace()+"/"+cr.GetName())
+		return
+	}
+	requestInstance.SetDriftedResource(operatorv1alpha1.DriftedResource{
+		Operand:    operandName,
+		APIVersion: cr.GetAPIVersion(),
+		Kind:       cr.GetKind(),
+		Namespace:  cr.GetNamespace(),
+		Name:       cr.GetName(),
+		Fields:     fields,
+	}, &r.Mutex)
+}
+
+// driftedFields returns the JSON values of the drifted fields in the desired configuration and in the spec,
+// with the sensitive values redacted
+func driftedFields(specRaw, desiredRaw []byte, drifted []string) ([]operatorv1alpha1.DriftedField, error) {
+	spec, err := decodeRedacted(specRaw)
+	if err != nil {
+		return nil, err
+	}
+	desired, err := decodeRedacted(desiredRaw)
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]operatorv1alpha1.DriftedField, 0, len(drifted))
+	for _, path := range drifted {
+		field := operatorv1alpha1.DriftedField{Path: path}
+		if field.Desired, err = fieldValue(desired, path); err != nil {
+			return nil, err
+		}
+		if field.Live, err = fieldValue(spec, path); err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// decodeRedacted decodes a custom resource spec with the sensitive fields redacted
+func decodeRedacted(raw []byte) (map[string]interface{}, error) {
+	decoded := make(map[string]interface{})
+	if len(raw) != 0 {
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			return nil, err
+		}
+	}
+	redacted, _ := logging.Redact(decoded).(map[string]interface{})
+	return redacted, nil
+}
+
+// fieldValue returns the JSON value of the field at the dot-separated path, or an empty string if it is missing
+func fieldValue(obj map[string]interface{}, path string) (string, error) {
+	keys := strings.Split(path, ".")
+	var value interface{} = obj
+	for _, key := range keys {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return "", nil
+		}
+		if value, ok = m[key]; !ok {
+			return "", nil
+		}
+	}
+	// The values are shown to the users, the HTML characters like in <redacted> are not escaped
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
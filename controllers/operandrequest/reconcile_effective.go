@@ -0,0 +1,90 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// startEffectiveConfig clears the effective configuration in the status before the operands are reconciled,
+// and returns the previous one. The custom resources record their spec in it when they are applied.
+func startEffectiveConfig(requestInstance *operatorv1alpha1.OperandRequest) *operatorv1alpha1.EffectiveConfig {
+	previous := requestInstance.Status.EffectiveConfig
+	requestInstance.Status.EffectiveConfig = nil
+	if requestInstance.IsEffectiveConfigRequested() {
+		requestInstance.Status.EffectiveConfig = &operatorv1alpha1.EffectiveConfig{}
+	}
+	return previous
+}
+
+// finishEffectiveConfig sorts the effective configuration collected from the operands.
+// The generated time is only updated when the configuration changes, so the status isn't patched at every reconcile.
+func finishEffectiveConfig(requestInstance *operatorv1alpha1.OperandRequest, previous *operatorv1alpha1.EffectiveConfig) {
+	effective := requestInstance.Status.EffectiveConfig
+	if effective == nil {
+		return
+	}
+	sort.SliceStable(effective.Resources, func(i, j int) bool {
+		a, b := effective.Resources[i], effective.Resources[j]
+		if a.Operand != b.Operand {
+			return a.Operand < b.Operand
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	if previous != nil && reflect.DeepEqual(previous.Resources, effective.Resources) {
+		effective.GeneratedTime = previous.GeneratedTime
+		return
+	}
+	effective.GeneratedTime = time.Now().UTC().Format(time.RFC3339)
+}
+
+// recordEffectiveConfig records the spec applied to the custom resource of the operand in the effective configuration,
+// with the sensitive fields redacted.
+func (r *Reconciler) recordEffectiveConfig(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, operandName string, cr unstructured.Unstructured, spec interface{}) {
+	if !requestInstance.IsEffectiveConfigRequested() {
+		return
+	}
+	raw, err := json.Marshal(logging.Redact(spec))
+	if err != nil {
+		logging.FromContext(ctx).Error(err, "Failed to marshal the effective configuration of the custom resource", "kind", cr.GetKind(), "name", cr.GetNamespace()+"/"+cr.GetName())
+		return
+	}
+	requestInstance.SetEffectiveResource(operatorv1alpha1.EffectiveResource{
+		Operand:    operandName,
+		APIVersion: cr.GetAPIVersion(),
+		Kind:       cr.GetKind(),
+		Namespace:  cr.GetNamespace(),
+		Name:       cr.GetName(),
+		Spec:       &runtime.RawExtension{Raw: raw},
+	}, &r.Mutex)
+}
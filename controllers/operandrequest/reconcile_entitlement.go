@@ -0,0 +1,52 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/entitlement"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// checkEntitlement returns true when the operator is entitled, or has no entitlement.
+// The operator which isn't entitled gets a LicenseRequired condition and is checked again with the next reconciliation.
+func (r *Reconciler) checkEntitlement(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, opt *operatorv1alpha1.Operator, registryKey types.NamespacedName, mu sync.Locker) (bool, error) {
+	if opt.Entitlement == nil {
+		requestInstance.RemoveLicenseRequiredCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+		return true, nil
+	}
+	message, err := entitlement.Check(ctx, r.Reader, nil, opt.Entitlement, registryKey.Namespace)
+	if err != nil {
+		return false, err
+	}
+	if message != "" {
+		logging.FromContext(ctx).Info("Operator is waiting for its license", "operator", opt.Name, "reason", message)
+		if requestInstance.SetLicenseRequiredCondition(opt.Name, message, operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu) {
+			r.recordOperatorEvent(requestInstance, r.getRegistryForEvent(ctx, registryKey), corev1.EventTypeWarning, constant.EventReasonLicenseRequired, "Operator %s is not subscribed until it is entitled: %s", opt.Name, message)
+		}
+		return false, nil
+	}
+	requestInstance.RemoveLicenseRequiredCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+	return true, nil
+}
@@ -0,0 +1,259 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/forensics"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// collectForensicBundles collects a forensic bundle for each operand failed in this reconcile
+func (r *Reconciler) collectForensicBundles(ctx context.Context, originalInstance, requestInstance *operatorv1alpha1.OperandRequest) {
+	if !util.GetForensicBundle() {
+		return
+	}
+	for _, m := range requestInstance.Status.Members {
+		if !isMemberFailed(m) {
+			continue
+		}
+		if original := getMember(originalInstance, m.Name); original != nil && isMemberFailed(*original) {
+			continue
+		}
+		if err := r.collectForensicBundle(ctx, requestInstance, m); err != nil {
+			logging.FromContext(ctx).Error(err, "Failed to collect the forensic bundle of the operand", "operand", m.Name)
+		}
+	}
+}
+
+// collectForensicBundle collects the Subscription, ClusterServiceVersion, InstallPlan and custom resources of the operand
+// and their recent Events, into a ConfigMap or the forensic bundle directory. The ODLM log excerpt is only written into
+// the directory, the log of the shared ODLM pod mentions the other OperandRequests, and the ConfigMap is in the namespace
+// of the OperandRequest.
+func (r *Reconciler) collectForensicBundle(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, m operatorv1alpha1.MemberStatus) error {
+	logging.FromContext(ctx).Info("Collecting the forensic bundle of the failed operand", "operand", m.Name)
+	bundle := forensics.New()
+	names := map[string]bool{requestInstance.Name: true, m.Name: true}
+	namespaces := []string{requestInstance.Namespace}
+
+	summary := fmt.Sprintf("operandRequest: %s/%s\noperand: %s\noperatorPhase: %s\noperandPhase: %s\ncollectedAt: %s\n",
+		requestInstance.Namespace, requestInstance.Name, m.Name, m.Phase.OperatorPhase, m.Phase.OperandPhase, time.Now().UTC().Format(time.RFC3339))
+	bundle.AddText("summary.yaml", summary)
+	if err := bundle.AddObjects("operandrequest.yaml", withKind(requestInstance.DeepCopy(), operatorv1alpha1.GroupVersion.WithKind("OperandRequest"))); err != nil {
+		return err
+	}
+
+	if opt := r.getOperatorForMember(ctx, requestInstance, m.Name); opt != nil {
+		namespace := r.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
+		namespaces = append(namespaces, namespace)
+		if opt.Namespace != "" && opt.Namespace != namespace {
+			namespaces = append(namespaces, opt.Namespace)
+		}
+		if err := r.addOLMObjects(ctx, bundle, opt, namespace, names); err != nil {
+			return err
+		}
+	}
+
+	var crs []runtime.Object
+	for _, cr := range m.OperandCRList {
+		names[cr.Name] = true
+		for _, ns := range namespaces {
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion(cr.APIVersion)
+			obj.SetKind(cr.Kind)
+			if err := r.Reader.Get(ctx, types.NamespacedName{Namespace: ns, Name: cr.Name}, obj); err == nil {
+				crs = append(crs, obj)
+				break
+			}
+		}
+	}
+	if err := bundle.AddObjects("operands.yaml", crs...); err != nil {
+		return err
+	}
+
+	var events []runtime.Object
+	for _, ns := range namespaces {
+		eventList := &corev1.EventList{}
+		if err := r.PagedList(ctx, eventList, client.InNamespace(ns)); err != nil {
+			logging.FromContext(ctx).Info("Failed to list the Events for the forensic bundle", "namespace", ns, "error", err.Error())
+			continue
+		}
+		for _, e := range forensics.RecentEvents(eventList.Items, names, forensics.MaxEvents) {
+			e := e
+			events = append(events, withKind(&e, corev1.SchemeGroupVersion.WithKind("Event")))
+		}
+	}
+	if err := bundle.AddObjects("events.yaml", events...); err != nil {
+		return err
+	}
+
+	if dir := util.GetForensicBundleDir(); dir != "" {
+		keywords := make([]string, 0, len(names))
+		for name := range names {
+			keywords = append(keywords, name)
+		}
+		bundle.AddText("odlm.log", forensics.LogExcerpt(r.getOperatorLog(ctx), keywords, forensics.MaxLogLines))
+		bundle.Truncate(forensics.MaxBundleSize)
+		path := filepath.Join(dir, requestInstance.Namespace, requestInstance.Name, m.Name+"-"+time.Now().UTC().Format("20060102T150405Z"))
+		if err := bundle.WriteTo(path); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(requestInstance, corev1.EventTypeWarning, constant.EventReasonForensicBundleCollected, "The forensic bundle of the failed operand %s is written into %s", m.Name, path)
+		return nil
+	}
+
+	bundle.Truncate(forensics.MaxBundleSize)
+	cm := bundle.ConfigMap(requestInstance.Name+"-"+m.Name+constant.ForensicBundleSuffix, requestInstance.Namespace, map[string]string{
+		constant.OpreqLabel:          "true",
+		constant.ForensicBundleLabel: m.Name,
+	})
+	if err := controllerutil.SetOwnerReference(requestInstance, cm, r.Scheme); err != nil {
+		return errors.Wrapf(err, "failed to set the owner of the ConfigMap %s/%s", cm.Namespace, cm.Name)
+	}
+	// ConfigMaps are filtered in the cache, get it from the API server
+	existing := &corev1.ConfigMap{}
+	if err := r.Reader.Get(ctx, types.NamespacedName{Namespace: cm.Namespace, Name: cm.Name}, existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get the ConfigMap %s/%s", cm.Namespace, cm.Name)
+		}
+		if err := r.Create(ctx, cm); err != nil {
+			return errors.Wrapf(err, "failed to create the ConfigMap %s/%s", cm.Namespace, cm.Name)
+		}
+	} else {
+		// The bundle of the latest failure replaces the previous one
+		existing.Data = cm.Data
+		if err := r.Update(ctx, existing); err != nil {
+			return errors.Wrapf(err, "failed to update the ConfigMap %s/%s", cm.Namespace, cm.Name)
+		}
+	}
+	r.Recorder.Eventf(requestInstance, corev1.EventTypeWarning, constant.EventReasonForensicBundleCollected, "The forensic bundle of the failed operand %s is stored in the ConfigMap %s", m.Name, cm.Name)
+	return nil
+}
+
+// addOLMObjects adds the Subscription, ClusterServiceVersion and InstallPlan of the operator to the bundle
+func (r *Reconciler) addOLMObjects(ctx context.Context, bundle *forensics.Bundle, opt *operatorv1alpha1.Operator, namespace string, names map[string]bool) error {
+	if opt.GetType() != operatorv1alpha1.OperatorTypeOLM {
+		return nil
+	}
+	sub, err := r.GetSubscription(ctx, opt.Name, namespace, opt.PackageName)
+	if err != nil || sub == nil {
+		logging.FromContext(ctx).V(logging.LevelChange).Info("Not found the Subscription for the forensic bundle", "subscription", namespace+"/"+opt.Name, "error", err.Error())
+		return nil
+	}
+	names[sub.Name] = true
+	if err := bundle.AddObjects("subscription.yaml", withKind(sub.DeepCopy(), olmv1alpha1.SchemeGroupVersion.WithKind(olmv1alpha1.SubscriptionKind))); err != nil {
+		return err
+	}
+
+	csvName := sub.Status.InstalledCSV
+	if csvName == "" {
+		csvName = sub.Status.CurrentCSV
+	}
+	if csvName != "" {
+		names[csvName] = true
+		csv := &olmv1alpha1.ClusterServiceVersion{}
+		if err := r.Reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: csvName}, csv); err == nil {
+			if err := bundle.AddObjects("clusterserviceversion.yaml", withKind(csv, olmv1alpha1.SchemeGroupVersion.WithKind(olmv1alpha1.ClusterServiceVersionKind))); err != nil {
+				return err
+			}
+		}
+	}
+
+	if ref := sub.Status.InstallPlanRef; ref != nil {
+		names[ref.Name] = true
+		ip := &olmv1alpha1.InstallPlan{}
+		if err := r.Reader.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, ip); err == nil {
+			if err := bundle.AddObjects("installplan.yaml", withKind(ip, olmv1alpha1.SchemeGroupVersion.WithKind(olmv1alpha1.InstallPlanKind))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getOperatorForMember returns the operator of the member from the OperandRegistries of the request
+func (r *Reconciler) getOperatorForMember(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, name string) *operatorv1alpha1.Operator {
+	for _, req := range requestInstance.Spec.Requests {
+		registryInstance, err := r.GetOperandRegistry(ctx, requestInstance.GetRegistryKey(req))
+		if err != nil {
+			continue
+		}
+		if opt := registryInstance.GetOperator(name); opt != nil {
+			return opt
+		}
+	}
+	return nil
+}
+
+// getOperatorLog returns the recent log of the ODLM Pod, the Pod name is set by the POD_NAME env
+func (r *Reconciler) getOperatorLog(ctx context.Context) string {
+	podName, namespace := util.GetPodName(), util.GetOperatorNamespace()
+	if podName == "" || namespace == "" || r.Config == nil {
+		return ""
+	}
+	clientset, err := kubernetes.NewForConfig(r.Config)
+	if err != nil {
+		logging.FromContext(ctx).Info("Failed to create the clientset to get the ODLM log", "error", err.Error())
+		return ""
+	}
+	tailLines := int64(5000)
+	data, err := clientset.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{TailLines: &tailLines}).DoRaw(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Info("Failed to get the log of the Pod", "pod", namespace+"/"+podName, "error", err.Error())
+
+		return ""
+	}
+	return string(data)
+}
+
+func isMemberFailed(m operatorv1alpha1.MemberStatus) bool {
+	return m.Phase.OperatorPhase == operatorv1alpha1.OperatorFailed || m.Phase.OperandPhase == operatorv1alpha1.ServiceFailed
+}
+
+func getMember(requestInstance *operatorv1alpha1.OperandRequest, name string) *operatorv1alpha1.MemberStatus {
+	for i := range requestInstance.Status.Members {
+		if requestInstance.Status.Members[i].Name == name {
+			return &requestInstance.Status.Members[i]
+		}
+	}
+	return nil
+}
+
+// withKind sets the kind of the object read by a typed client, it is empty in the object
+func withKind(obj client.Object, gvk schema.GroupVersionKind) runtime.Object {
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	return obj
+}
@@ -0,0 +1,158 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/health"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// checkOperandHealth evaluates the health check of the service of the operand, the operand without a health check is healthy.
+// The unhealthy operand gets an Unhealthy condition, with the reason why the health check fails.
+func (r *Reconciler) checkOperandHealth(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryKey types.NamespacedName, operand operatorv1alpha1.Operand, opdRegistry *operatorv1alpha1.Operator, csv *olmv1alpha1.ClusterServiceVersion, index int) (bool, error) {
+	configInstance, err := r.GetOperandConfig(ctx, registryKey)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, errors.Wrapf(err, "failed to get the OperandConfig %s", registryKey.String())
+	}
+	service := configInstance.GetService(operand.Name)
+	if service == nil || service.HealthCheck == nil {
+		requestInstance.RemoveUnhealthyCondition(operand.Name, operatorv1alpha1.ResourceTypeOperand, &r.Mutex)
+		return true, nil
+	}
+	check, err := health.Compile(service.HealthCheck.Expression)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid health check of the service %s", service.Name)
+	}
+
+	var crs []unstructured.Unstructured
+	namespace := opdRegistry.Namespace
+	if operand.Kind == "" {
+		crs, err = r.getConfigCustomResources(ctx, service, namespace, csv)
+	} else {
+		namespace = requestInstance.Namespace
+		crs, err = r.getRequestCustomResource(ctx, operand, types.NamespacedName{Namespace: namespace, Name: OperandInstanceName(requestInstance.Name, operand, index)})
+	}
+	if err != nil {
+		return false, err
+	}
+	objects, err := r.getHealthCheckObjects(ctx, service.HealthCheck.Objects, namespace)
+	if err != nil {
+		return false, err
+	}
+
+	healthy, err := check.Healthy(crs, objects)
+	if err != nil || !healthy {
+		message := "The health check of " + string(operatorv1alpha1.ResourceTypeOperand) + " " + operand.Name + " returns false"
+		if err != nil {
+			message = err.Error()
+		}
+		logging.FromContext(ctx).V(logging.LevelFlow).Info("Operand is unhealthy", "operand", operand.Name, "reason", message)
+		requestInstance.SetUnhealthyCondition(operand.Name, message, operatorv1alpha1.ResourceTypeOperand, corev1.ConditionTrue, &r.Mutex)
+		return false, nil
+	}
+	requestInstance.RemoveUnhealthyCondition(operand.Name, operatorv1alpha1.ResourceTypeOperand, &r.Mutex)
+	return true, nil
+}
+
+// getConfigCustomResources returns the existing custom resources of the service created from the alm-examples
+func (r *Reconciler) getConfigCustomResources(ctx context.Context, service *operatorv1alpha1.ConfigService, namespace string, csv *olmv1alpha1.ClusterServiceVersion) ([]unstructured.Unstructured, error) {
+	almExamples := csv.GetAnnotations()["alm-examples"]
+	if almExamples == "" {
+		return nil, nil
+	}
+	var almExampleList []map[string]interface{}
+	if err := json.Unmarshal([]byte(almExamples), &almExampleList); err != nil {
+		return nil, errors.Wrapf(err, "failed to convert alm-examples in the ClusterServiceVersion %s/%s to slice", csv.Namespace, csv.Name)
+	}
+
+	var crs []unstructured.Unstructured
+	for _, almExample := range almExampleList {
+		cr := unstructured.Unstructured{Object: almExample}
+		inService := false
+		for kind := range service.Spec {
+			if strings.EqualFold(cr.GetKind(), kind) {
+				inService = true
+			}
+		}
+		if !inService {
+			continue
+		}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: cr.GetName()}, &cr); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get the custom resource %s/%s", namespace, cr.GetName())
+		}
+		crs = append(crs, cr)
+	}
+	return crs, nil
+}
+
+// getRequestCustomResource returns the existing custom resource of the operand created from the OperandRequest
+func (r *Reconciler) getRequestCustomResource(ctx context.Context, operand operatorv1alpha1.Operand, key types.NamespacedName) ([]unstructured.Unstructured, error) {
+	cr := unstructured.Unstructured{}
+	cr.SetAPIVersion(operand.APIVersion)
+	cr.SetKind(operand.Kind)
+	if err := r.Client.Get(ctx, key, &cr); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the custom resource %s", key.String())
+	}
+	return []unstructured.Unstructured{cr}, nil
+}
+
+// getHealthCheckObjects lists the related objects of the health check by their name.
+// They are listed from the API server, so that no informer is started for their kinds.
+func (r *Reconciler) getHealthCheckObjects(ctx context.Context, selectors []operatorv1alpha1.HealthCheckObjects, namespace string) (map[string][]unstructured.Unstructured, error) {
+	objects := make(map[string][]unstructured.Unstructured)
+	for _, s := range selectors {
+		ns := namespace
+		if s.Namespace != "" {
+			ns = s.Namespace
+		}
+		selector, err := metav1.LabelSelectorAsSelector(s.Selector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid selector of the health check objects %s", s.Name)
+		}
+		list := &unstructured.UnstructuredList{}
+		list.SetAPIVersion(s.APIVersion)
+		list.SetKind(s.Kind + "List")
+		if err := r.Reader.List(ctx, list, client.InNamespace(ns), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, errors.Wrapf(err, "failed to list the %s of the health check objects %s in the namespace %s", s.Kind, s.Name, ns)
+		}
+		objects[s.Name] = append(objects[s.Name], list.Items...)
+	}
+	return objects, nil
+}
@@ -0,0 +1,379 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/bundle"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/helm"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	odlmutil "github.com/IBM/operand-deployment-lifecycle-manager/pkg/util/v1"
+)
+
+func init() {
+	RegisterInstaller(operatorv1alpha1.OperatorTypeHelm, func(r *Reconciler) Installer { return &helmInstaller{r} })
+}
+
+// helmInstaller installs the operator with its Helm chart
+type helmInstaller struct {
+	*Reconciler
+}
+
+func (i *helmInstaller) Install(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, opt *operatorv1alpha1.Operator, registryKey types.NamespacedName, mu sync.Locker) error {
+	return i.reconcileHelm(ctx, requestInstance, opt, registryKey, mu)
+}
+
+func (i *helmInstaller) Uninstall(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, configInstance *operatorv1alpha1.OperandConfig, opt *operatorv1alpha1.Operator) error {
+	return i.deleteHelmRelease(ctx, requestInstance, registryInstance, configInstance, opt)
+}
+
+func (i *helmInstaller) PreviewUninstall(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, configInstance *operatorv1alpha1.OperandConfig, opt *operatorv1alpha1.Operator, preview *operatorv1alpha1.DeletionPreview) error {
+	return i.previewDeleteHelmRelease(ctx, requestInstance, registryInstance, configInstance, opt, preview)
+}
+
+// GetUninstallCSV returns the ClusterServiceVersion of the Helm release if it is uninstalled with the OperandRequest
+func (i *helmInstaller) GetUninstallCSV(ctx context.Context, registryInstance *operatorv1alpha1.OperandRegistry, opt *operatorv1alpha1.Operator) (*olmv1alpha1.ClusterServiceVersion, error) {
+	namespace := i.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
+	secret := &corev1.Secret{}
+	secretKey := types.NamespacedName{Namespace: namespace, Name: opt.GetReleaseName() + constant.HelmValuesSecretSuffix}
+	if err := i.Reader.Get(ctx, secretKey, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the Helm values Secret %s", secretKey.String())
+	}
+	if _, ok := secret.Labels[constant.OpreqLabel]; !ok || requestedWithOtherRegistries(secret.Annotations, registryInstance) {
+		return nil, nil
+	}
+	return helmCSV(opt, namespace), nil
+}
+
+// GetCSV returns an empty ClusterServiceVersion once the Helm release is deployed, a chart has no alm-examples
+func (i *helmInstaller) GetCSV(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, opt *operatorv1alpha1.Operator, mu sync.Locker) (*olmv1alpha1.ClusterServiceVersion, error) {
+	release, err := i.getHelmRelease(ctx, opt)
+	if err != nil {
+		requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorFailed, "", mu)
+		return nil, err
+	}
+	requestInstance.SetMemberHelmReleaseStatus(opt.Name, release, mu)
+	if release == nil || (release.Status != helm.StatusDeployed && release.Status != helm.StatusFailed) {
+		logging.FromContext(ctx).Info("The Helm release of the operator is not deployed yet, retry", "operator", opt.Name)
+		requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorInstalling, "", mu)
+		return nil, nil
+	}
+	if release.Status == helm.StatusFailed {
+		requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorFailed, "", mu)
+		return nil, fmt.Errorf("the Helm release %s/%s of the operator %s is failed: %s", release.Namespace, release.Name, opt.Name, release.Description)
+	}
+	return helmCSV(opt, release.Namespace), nil
+}
+
+// reconcileHelm installs or upgrades the Helm release of the operator with the values merged from
+// the OperandRegistry and the OperandConfig. The release is upgraded when the chart or the values change.
+func (r *Reconciler) reconcileHelm(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, opt *operatorv1alpha1.Operator, registryKey types.NamespacedName, mu sync.Locker) error {
+	log := logging.FromContext(ctx)
+	if opt.Chart == nil || opt.Chart.Repository == "" || opt.Chart.Name == "" {
+		requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorFailed, "", mu)
+		return fmt.Errorf("the operator %s has type %s but no chart repository or name", opt.Name, opt.GetType())
+	}
+	namespace := r.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
+	release := opt.GetReleaseName()
+
+	values, err := r.helmValues(ctx, opt, registryKey)
+	if err != nil {
+		requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorFailed, "", mu)
+		return err
+	}
+	hash := helm.Hash(opt.Chart, values)
+
+	if err := r.ensureOperatorNamespaces(ctx, opt, namespace, registryKey); err != nil {
+		requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorFailed, "", mu)
+		return err
+	}
+
+	// Secrets are filtered in the cache, get it from the API server
+	secret := &corev1.Secret{}
+	secretKey := types.NamespacedName{Namespace: namespace, Name: release + constant.HelmValuesSecretSuffix}
+	if err := r.Reader.Get(ctx, secretKey, secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get the Helm values Secret %s", secretKey.String())
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretKey.Name,
+				Namespace: secretKey.Namespace,
+				Labels: map[string]string{
+					constant.OpreqLabel:       "true",
+					constant.HelmReleaseLabel: release,
+				},
+			},
+		}
+	} else if _, ok := secret.Labels[constant.OpreqLabel]; !ok {
+		log.V(logging.LevelFlow).Info("Secret isn't created by ODLM, ignore updating or deleting the Helm release", "secret", secretKey.String(), "release", release)
+		return nil
+	}
+
+	// Record the registry, config and request using the release, as the annotations of the Subscription do
+	originalSecret := secret.DeepCopy()
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[constant.HashedData] = hash
+	secret.Annotations[registryKey.Namespace+"."+registryKey.Name+"/registry"] = "true"
+	secret.Annotations[registryKey.Namespace+"."+registryKey.Name+"/config"] = "true"
+	secret.Annotations[requestInstance.Namespace+"."+requestInstance.Name+"/request"] = "true"
+	secret.Data = map[string][]byte{helm.ValuesKey: values}
+	if secret.ResourceVersion == "" {
+		if err := r.Create(ctx, secret); err != nil {
+			return errors.Wrapf(err, "failed to create the Helm values Secret %s", secretKey.String())
+		}
+	} else if !equalSecret(secret, originalSecret) {
+		if err := r.Update(ctx, secret); err != nil {
+			return errors.Wrapf(err, "failed to update the Helm values Secret %s", secretKey.String())
+		}
+	}
+
+	applied, installed := secret.Annotations[constant.HelmAppliedAnnotation]
+	if applied == hash {
+		return nil
+	}
+	phase := operatorv1alpha1.OperatorInstalling
+	if installed {
+		phase = operatorv1alpha1.OperatorUpdating
+	}
+
+	serviceAccount, err := r.getHelmServiceAccount(ctx, opt.Chart, namespace)
+	if err != nil {
+		requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorFailed, "", mu)
+		return err
+	}
+
+	jobKey := types.NamespacedName{Namespace: namespace, Name: release + constant.HelmJobSuffix + hash}
+	job := &batchv1.Job{}
+	if err := r.Reader.Get(ctx, jobKey, job); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get the Helm Job %s", jobKey.String())
+		}
+		// Only the Job for the latest chart and values is kept
+		if err := r.deleteHelmJobs(ctx, namespace, release); err != nil {
+			return err
+		}
+		log.V(logging.LevelChange).Info("Creating the Helm Job", "job", jobKey.String(), "release", release)
+		requestInstance.SetCreatingCondition(release, operatorv1alpha1.ResourceTypeHelmRelease, corev1.ConditionTrue, mu)
+		job = helm.InstallJob(jobKey.Name, jobKey.Namespace, release, serviceAccount, util.GetHelmImage(), secretKey.Name, opt.Chart)
+		if err := r.Create(ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+			requestInstance.SetCreatingCondition(release, operatorv1alpha1.ResourceTypeHelmRelease, corev1.ConditionFalse, mu)
+			requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorFailed, "", mu)
+			return errors.Wrapf(err, "failed to create the Helm Job %s", jobKey.String())
+		}
+		requestInstance.SetMemberStatus(opt.Name, phase, "", mu)
+		return nil
+	}
+
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorFailed, "", mu)
+			return fmt.Errorf("the Helm Job %s for the release %s is failed: %s", jobKey.String(), release, c.Message)
+		}
+	}
+	if job.Status.Succeeded == 0 {
+		requestInstance.SetMemberStatus(opt.Name, phase, "", mu)
+		return nil
+	}
+
+	log.V(logging.LevelFlow).Info("The Helm release is installed by the Job", "release", namespace+"/"+release, "job", jobKey.Name)
+	secret.Annotations[constant.HelmAppliedAnnotation] = hash
+	if err := r.Update(ctx, secret); err != nil {
+		return errors.Wrapf(err, "failed to update the Helm values Secret %s", secretKey.String())
+	}
+	return r.deleteHelmJobs(ctx, namespace, release)
+}
+
+// helmValues merges the values of the service in the OperandConfig into the default values in the OperandRegistry
+func (r *Reconciler) helmValues(ctx context.Context, opt *operatorv1alpha1.Operator, registryKey types.NamespacedName) ([]byte, error) {
+	var defaultValues, configValues []byte
+	if opt.Chart.Values != nil {
+		defaultValues = opt.Chart.Values.Raw
+	}
+	configInstance, err := r.GetOperandConfig(ctx, registryKey)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, errors.Wrapf(err, "failed to get the OperandConfig %s", registryKey.String())
+	}
+	if err == nil {
+		if service := configInstance.GetService(opt.Name); service != nil && service.Values != nil {
+			configValues = service.Values.Raw
+		}
+	}
+	merged := odlmutil.MergeCR(defaultValues, configValues)
+	values, err := json.Marshal(merged)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to merge the values of the Helm release %s", opt.GetReleaseName())
+	}
+	logging.Render(logging.FromContext(ctx), "Rendered the values of the Helm release", merged, "release", opt.GetReleaseName())
+	return values, nil
+}
+
+// getHelmServiceAccount returns the ServiceAccount the Helm Jobs run as, it must be set in the chart and exist
+// in the namespace. ODLM doesn't grant it any permission, the charts may need more than ODLM has.
+func (r *Reconciler) getHelmServiceAccount(ctx context.Context, chart *operatorv1alpha1.HelmChart, namespace string) (string, error) {
+	if chart.ServiceAccountName == "" {
+		return "", errors.Errorf("the chart %s has no serviceAccountName for the Helm Jobs", chart.Name)
+	}
+	sa := &corev1.ServiceAccount{}
+	if err := r.Reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: chart.ServiceAccountName}, sa); err != nil {
+		return "", errors.Wrapf(err, "failed to get the ServiceAccount %s/%s of the Helm Jobs", namespace, chart.ServiceAccountName)
+	}
+	return sa.Name, nil
+}
+
+// deleteHelmJobs deletes the Jobs installing the release
+func (r *Reconciler) deleteHelmJobs(ctx context.Context, namespace, release string) error {
+	jobList := &batchv1.JobList{}
+	if err := r.Reader.List(ctx, jobList, client.InNamespace(namespace), client.MatchingLabels{constant.HelmReleaseLabel: release}); err != nil {
+		return errors.Wrapf(err, "failed to list the Helm Jobs of the release %s/%s", namespace, release)
+	}
+	for i := range jobList.Items {
+		job := &jobList.Items[i]
+		if job.Name == release+constant.HelmUninstallJobSuffix {
+			continue
+		}
+		if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete the Helm Job %s/%s", namespace, job.Name)
+		}
+	}
+	return nil
+}
+
+// getHelmRelease returns the status of the Helm release of the operator from the Helm storage Secrets
+func (r *Reconciler) getHelmRelease(ctx context.Context, opt *operatorv1alpha1.Operator) (*operatorv1alpha1.HelmReleaseStatus, error) {
+	namespace := r.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
+	release := opt.GetReleaseName()
+	secretList := &corev1.SecretList{}
+	if err := r.Reader.List(ctx, secretList, client.InNamespace(namespace), client.MatchingLabels{"owner": "helm", "name": release}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list the Helm storage Secrets of the release %s/%s", namespace, release)
+	}
+	return helm.LatestRelease(release, namespace, secretList.Items)
+}
+
+// deleteHelmRelease uninstalls the Helm release of the operator, when no other OperandRegistry uses it
+func (r *Reconciler) deleteHelmRelease(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, configInstance *operatorv1alpha1.OperandConfig, op *operatorv1alpha1.Operator) error {
+	log := logging.FromContext(ctx)
+	namespace := r.GetOperatorNamespace(op.InstallMode, op.Namespace)
+	release := op.GetReleaseName()
+	secret := &corev1.Secret{}
+	secretKey := types.NamespacedName{Namespace: namespace, Name: release + constant.HelmValuesSecretSuffix}
+	if err := r.Reader.Get(ctx, secretKey, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.V(logging.LevelDebug).Info("There is no Helm values Secret", "secret", secretKey.String())
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get the Helm values Secret %s", secretKey.String())
+	}
+	if _, ok := secret.Labels[constant.OpreqLabel]; !ok {
+		log.V(logging.LevelChange).Info("Secret isn't created by ODLM", "secret", secretKey.String())
+		return nil
+	}
+
+	// check and remove registry and config in annotation of the Secret
+	originalSecret := secret.DeepCopy()
+	delete(secret.Annotations, registryInstance.Namespace+"."+registryInstance.Name+"/registry")
+	delete(secret.Annotations, registryInstance.Namespace+"."+registryInstance.Name+"/config")
+	reg, _ := regexp.Compile(`^(.*)\.(.*)\/registry`)
+	for anno := range secret.Annotations {
+		if reg.MatchString(anno) {
+			if err := r.Patch(ctx, secret, client.MergeFrom(originalSecret)); err != nil {
+				return err
+			}
+			log.V(logging.LevelFlow).Info("Did not delete the Helm release which is requested by OperandRequest with different OperandRegistry", "release", release)
+			return nil
+		}
+	}
+
+	csv := helmCSV(op, namespace)
+	log.V(logging.LevelChange).Info("Deleting all the Custom Resources for the Helm release", "release", release)
+	if err := r.deleteAllCustomResource(ctx, csv, requestInstance, configInstance, op.Name, op.Namespace); err != nil {
+		return err
+	}
+	log.V(logging.LevelChange).Info("Deleting all the k8s Resources for the Helm release", "release", release)
+	if err := r.deleteAllK8sResource(ctx, configInstance, op.Name, op.Namespace); err != nil {
+		return err
+	}
+	if secret.Labels[constant.NotUninstallLabel] == "true" {
+		log.V(logging.LevelFlow).Info("Operator has label operator.ibm.com/opreq-do-not-uninstall, skip the uninstall", "operator", op.Name)
+		return nil
+	}
+
+	requestInstance.SetDeletingCondition(release, operatorv1alpha1.ResourceTypeHelmRelease, corev1.ConditionTrue, &r.Mutex)
+	if err := r.deleteHelmJobs(ctx, namespace, release); err != nil {
+		requestInstance.SetDeletingCondition(release, operatorv1alpha1.ResourceTypeHelmRelease, corev1.ConditionFalse, &r.Mutex)
+		return err
+	}
+	// Without its ServiceAccount no Job can uninstall the release, it is abandoned rather than blocking
+	// the deletion of the OperandRequest
+	serviceAccount, err := r.getHelmServiceAccount(ctx, op.Chart, namespace)
+	abandoned := err != nil && (op.Chart.ServiceAccountName == "" || apierrors.IsNotFound(err))
+	if err != nil && !abandoned {
+		requestInstance.SetDeletingCondition(release, operatorv1alpha1.ResourceTypeHelmRelease, corev1.ConditionFalse, &r.Mutex)
+		return err
+	}
+	if abandoned {
+		log.Info("The ServiceAccount of the Helm Jobs is not found, abandon the Helm release", "release", namespace+"/"+release, "error", err.Error())
+		requestInstance.SetDeletingCondition(release, operatorv1alpha1.ResourceTypeHelmRelease, corev1.ConditionFalse, &r.Mutex)
+		r.Recorder.Eventf(requestInstance, corev1.EventTypeWarning, constant.EventReasonHelmReleaseAbandoned, "Left the Helm release %s/%s installed, the ServiceAccount %q of the Helm Jobs is not found", namespace, release, op.Chart.ServiceAccountName)
+	} else {
+		job := helm.UninstallJob(release+constant.HelmUninstallJobSuffix, namespace, release, serviceAccount, util.GetHelmImage())
+		if err := r.Create(ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+			requestInstance.SetDeletingCondition(release, operatorv1alpha1.ResourceTypeHelmRelease, corev1.ConditionFalse, &r.Mutex)
+			return errors.Wrapf(err, "failed to create the Helm uninstall Job %s/%s", namespace, job.Name)
+		}
+		log.V(logging.LevelFlow).Info("The Helm release is being uninstalled by the Job", "release", namespace+"/"+release, "job", job.Name)
+	}
+
+	if err := r.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+		requestInstance.SetDeletingCondition(release, operatorv1alpha1.ResourceTypeHelmRelease, corev1.ConditionFalse, &r.Mutex)
+		return errors.Wrapf(err, "failed to delete the Helm values Secret %s", secretKey.String())
+	}
+	return nil
+}
+
+// helmCSV returns the ClusterServiceVersion used to create the operands of the Helm release.
+// A Helm release has no alm-examples, its operands are created from the OperandRequest only.
+func helmCSV(op *operatorv1alpha1.Operator, namespace string) *olmv1alpha1.ClusterServiceVersion {
+	return bundle.EmptyCSV(op.GetReleaseName(), namespace)
+}
+
+func equalSecret(secret, originalSecret *corev1.Secret) bool {
+	return equality.Semantic.DeepEqual(secret.Data, originalSecret.Data) && equality.Semantic.DeepEqual(secret.Annotations, originalSecret.Annotations)
+}
@@ -0,0 +1,128 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/ledger"
+)
+
+// bindOperator records the OperandRequest in the ledger of the operator before the operator is installed for it
+func (r *Reconciler) bindOperator(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, operator string) error {
+	requestKey := types.NamespacedName{Namespace: requestInstance.Namespace, Name: requestInstance.Name}
+	return r.updateLedger(ctx, registryInstance, func(cm *corev1.ConfigMap) (bool, error) {
+		return ledger.Bind(cm, operator, requestKey), nil
+	})
+}
+
+// unbindOperator removes the OperandRequest from the ledger of the operator, and returns the other OperandRequests
+// still referencing the operator. The operator is only uninstalled when there is none.
+func (r *Reconciler) unbindOperator(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, operator string) ([]types.NamespacedName, error) {
+	requestKey := types.NamespacedName{Namespace: requestInstance.Namespace, Name: requestInstance.Name}
+	var others []types.NamespacedName
+	err := r.updateLedger(ctx, registryInstance, func(cm *corev1.ConfigMap) (bool, error) {
+		changed := ledger.Unbind(cm, operator, requestKey)
+		var pruned bool
+		var err error
+		others, pruned, err = r.pruneReferences(ctx, cm, registryInstance, operator)
+		return changed || pruned, err
+	})
+	return others, err
+}
+
+// getOtherReferences returns the OperandRequests other than this one referencing the operator in its ledger
+func (r *Reconciler) getOtherReferences(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, operator string) ([]types.NamespacedName, error) {
+	requestKey := types.NamespacedName{Namespace: requestInstance.Namespace, Name: requestInstance.Name}
+	var others []types.NamespacedName
+	err := r.updateLedger(ctx, registryInstance, func(cm *corev1.ConfigMap) (bool, error) {
+		refs, pruned, err := r.pruneReferences(ctx, cm, registryInstance, operator)
+		others = others[:0]
+		for _, ref := range refs {
+			if ref != requestKey {
+				others = append(others, ref)
+			}
+		}
+		return pruned, err
+	})
+	return others, err
+}
+
+// pruneReferences removes the references of the OperandRequests which are gone or don't request the operator anymore,
+// like the ones deleted without their finalizer, and returns the remaining references
+func (r *Reconciler) pruneReferences(ctx context.Context, cm *corev1.ConfigMap, registryInstance *operatorv1alpha1.OperandRegistry, operator string) ([]types.NamespacedName, bool, error) {
+	var remaining []types.NamespacedName
+	pruned := false
+	for _, ref := range ledger.References(cm, operator) {
+		// The OperandRequests are read from the API server, the cache may not have the latest ones yet
+		request := &operatorv1alpha1.OperandRequest{}
+		if err := r.Reader.Get(ctx, ref, request); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, false, errors.Wrapf(err, "failed to get the OperandRequest %s", ref.String())
+			}
+			request = nil
+		}
+		if request != nil && ledger.Requests(request, registryInstance, operator) {
+			remaining = append(remaining, ref)
+			continue
+		}
+		pruned = ledger.Unbind(cm, operator, ref) || pruned
+	}
+	return remaining, pruned, nil
+}
+
+// updateLedger changes the ledger of the OperandRegistry with mutate, which returns true if it changed it.
+// The ledger is read from the API server and updated with its resource version, so the concurrent changes
+// of the OperandRequests are applied one after the other: on a conflict, mutate is applied to the latest ledger again.
+func (r *Reconciler) updateLedger(ctx context.Context, registryInstance *operatorv1alpha1.OperandRegistry, mutate func(cm *corev1.ConfigMap) (bool, error)) error {
+	registryKey := types.NamespacedName{Namespace: registryInstance.Namespace, Name: registryInstance.Name}
+	key := types.NamespacedName{Namespace: registryKey.Namespace, Name: ledger.Name(registryKey.Name)}
+	return retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}, func() error {
+		// ConfigMaps are filtered in the cache, get it from the API server
+		cm := &corev1.ConfigMap{}
+		if err := r.Reader.Get(ctx, key, cm); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to get the ConfigMap %s", key.String())
+			}
+			cm = ledger.New(registryKey)
+			changed, err := mutate(cm)
+			if err != nil || !changed {
+				return err
+			}
+			// The ledger is deleted with the OperandRegistry
+			if err := controllerutil.SetOwnerReference(registryInstance, cm, r.Scheme); err != nil {
+				return errors.Wrapf(err, "failed to set the owner of the ConfigMap %s", key.String())
+			}
+			return r.Create(ctx, cm)
+		}
+		changed, err := mutate(cm)
+		if err != nil || !changed {
+			return err
+		}
+		return r.Update(ctx, cm)
+	})
+}
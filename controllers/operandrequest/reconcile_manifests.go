@@ -0,0 +1,586 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/bundle"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/profile"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	odlmutil "github.com/IBM/operand-deployment-lifecycle-manager/pkg/util/v1"
+)
+
+// manifestsRef records an object applied from the manifests of an operator
+type manifestsRef struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+func (ref manifestsRef) String() string {
+	return ref.APIVersion + "/" + ref.Kind + "/" + ref.Namespace + "/" + ref.Name
+}
+
+func init() {
+	RegisterInstaller(operatorv1alpha1.OperatorTypeManifests, func(r *Reconciler) Installer { return &manifestsInstaller{r} })
+}
+
+// manifestsInstaller installs the operator from its bundle image or manifest URL, for the clusters without OLM
+type manifestsInstaller struct {
+	*Reconciler
+}
+
+func (i *manifestsInstaller) Install(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, opt *operatorv1alpha1.Operator, registryKey types.NamespacedName, mu sync.Locker) error {
+	return i.reconcileManifests(ctx, requestInstance, opt, registryKey, mu)
+}
+
+func (i *manifestsInstaller) Uninstall(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, configInstance *operatorv1alpha1.OperandConfig, opt *operatorv1alpha1.Operator) error {
+	return i.deleteManifests(ctx, requestInstance, registryInstance, configInstance, opt)
+}
+
+func (i *manifestsInstaller) PreviewUninstall(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, configInstance *operatorv1alpha1.OperandConfig, opt *operatorv1alpha1.Operator, preview *operatorv1alpha1.DeletionPreview) error {
+	return i.previewDeleteManifests(ctx, requestInstance, registryInstance, configInstance, opt, preview)
+}
+
+// GetUninstallCSV returns the ClusterServiceVersion in the manifests if the operator is uninstalled with the OperandRequest
+func (i *manifestsInstaller) GetUninstallCSV(ctx context.Context, registryInstance *operatorv1alpha1.OperandRegistry, opt *operatorv1alpha1.Operator) (*olmv1alpha1.ClusterServiceVersion, error) {
+	namespace := i.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
+	cm := &corev1.ConfigMap{}
+	cmKey := types.NamespacedName{Namespace: namespace, Name: opt.Name + constant.ManifestsConfigMapSuffix}
+	if err := i.Reader.Get(ctx, cmKey, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the manifests ConfigMap %s", cmKey.String())
+	}
+	if _, ok := cm.Labels[constant.OpreqLabel]; !ok || requestedWithOtherRegistries(cm.Annotations, registryInstance) {
+		return nil, nil
+	}
+	manifests, err := bundle.Load(cm.Data, opt.Name, namespace, opt.TargetNamespaces)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load the manifests of the operator %s", opt.Name)
+	}
+	return manifests.CSV, nil
+}
+
+// GetCSV returns the ClusterServiceVersion in the manifests, the operator installed from its manifests has no Subscription
+func (i *manifestsInstaller) GetCSV(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, opt *operatorv1alpha1.Operator, mu sync.Locker) (*olmv1alpha1.ClusterServiceVersion, error) {
+	csv, err := i.getManifestsCSV(ctx, opt)
+	if err != nil {
+		requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorFailed, "", mu)
+		return nil, err
+	}
+	if csv == nil {
+		logging.FromContext(ctx).Info("The operator installed from the manifests is not ready yet, retry", "operator", opt.Name)
+		requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorInstalling, "", mu)
+	}
+	return csv, nil
+}
+
+// reconcileManifests installs or upgrades the operator from its manifests, for the clusters without OLM
+func (r *Reconciler) reconcileManifests(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, opt *operatorv1alpha1.Operator, registryKey types.NamespacedName, mu sync.Locker) error {
+	log := logging.FromContext(ctx)
+	namespace := r.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
+
+	cm, files, err := r.loadManifestsConfigMap(ctx, opt, namespace)
+	if err != nil {
+		var tooLarge *bundle.TooLargeError
+		if errors.As(err, &tooLarge) {
+			requestInstance.SetManifestsTooLargeCondition(opt.Name, tooLarge.Error(), operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu)
+		}
+		requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorFailed, "", mu)
+		return err
+	}
+	requestInstance.RemoveManifestsTooLargeCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+	if cm == nil {
+		log.V(logging.LevelChange).Info("The bundle image of the operator is being unpacked", "operator", opt.Name)
+		requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorInstalling, "", mu)
+		return nil
+	}
+
+	if _, ok := cm.Labels[constant.OpreqLabel]; !ok {
+		log.V(logging.LevelFlow).Info("ConfigMap isn't created by ODLM, ignore updating or deleting the operator", "configMap", cm.Namespace+"/"+cm.Name, "operator", opt.Name)
+		return nil
+	}
+
+	manifests, err := bundle.Load(files, opt.Name, namespace, opt.TargetNamespaces)
+	if err != nil {
+		requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorFailed, "", mu)
+		return errors.Wrapf(err, "failed to load the manifests of the operator %s", opt.Name)
+	}
+
+	if err := r.ensureOperatorNamespaces(ctx, opt, namespace, registryKey); err != nil {
+		requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorFailed, "", mu)
+		return err
+	}
+
+	_, installed := cm.Annotations[constant.ManifestsAppliedAnnotation]
+	changed, err := r.applyManifests(ctx, requestInstance, opt, cm, manifests)
+	if err != nil {
+		requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorFailed, "", mu)
+		return err
+	}
+
+	// Record the registry, config and request using the operator, as the annotations of the Subscription do
+	cm.Annotations[registryKey.Namespace+"."+registryKey.Name+"/registry"] = "true"
+	cm.Annotations[registryKey.Namespace+"."+registryKey.Name+"/config"] = "true"
+	cm.Annotations[requestInstance.Namespace+"."+requestInstance.Name+"/request"] = "true"
+	if err := r.Update(ctx, cm); err != nil {
+		return errors.Wrapf(err, "failed to update the manifests ConfigMap %s/%s", cm.Namespace, cm.Name)
+	}
+
+	if changed {
+		if installed {
+			requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorUpdating, "", mu)
+		} else {
+			requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorInstalling, "", mu)
+		}
+	}
+	return nil
+}
+
+// loadManifestsConfigMap returns the ConfigMap caching the manifests of the operator and the manifest files.
+// The manifests are fetched again when the source in the OperandRegistry changes.
+// It returns a nil ConfigMap when the bundle image is still being unpacked.
+func (r *Reconciler) loadManifestsConfigMap(ctx context.Context, opt *operatorv1alpha1.Operator, namespace string) (*corev1.ConfigMap, map[string]string, error) {
+	if opt.Manifests == nil || (opt.Manifests.BundleImage == "" && opt.Manifests.URL == "") {
+		return nil, nil, fmt.Errorf("the operator %s has type %s but no bundle image or URL of the manifests", opt.Name, opt.GetType())
+	}
+	if opt.Manifests.BundleImage != "" && opt.Manifests.URL != "" {
+		return nil, nil, fmt.Errorf("the operator %s has both a bundle image and a URL of the manifests", opt.Name)
+	}
+	source := opt.Manifests.BundleImage
+	if source == "" {
+		source = opt.Manifests.URL
+	}
+
+	// ConfigMaps are filtered in the cache, get it from the API server
+	cm := &corev1.ConfigMap{}
+	cmKey := types.NamespacedName{Namespace: namespace, Name: opt.Name + constant.ManifestsConfigMapSuffix}
+	if err := r.Reader.Get(ctx, cmKey, cm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, nil, errors.Wrapf(err, "failed to get the manifests ConfigMap %s", cmKey.String())
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cmKey.Name,
+				Namespace: cmKey.Namespace,
+				Labels: map[string]string{
+					constant.OpreqLabel:          "true",
+					constant.ManifestsOwnerLabel: opt.Name,
+				},
+			},
+		}
+	}
+	if cm.Annotations == nil {
+		cm.Annotations = make(map[string]string)
+	}
+	if cm.Annotations[constant.ManifestsSourceAnnotation] == source && len(cm.Data) != 0 {
+		return cm, cm.Data, nil
+	}
+
+	var files map[string]string
+	if opt.Manifests.URL != "" {
+		logging.FromContext(ctx).V(logging.LevelChange).Info("Fetching the manifests of the operator", "operator", opt.Name, "url", opt.Manifests.URL)
+		fetchCtx, cancel := context.WithTimeout(ctx, constant.DefaultManifestsFetchTimeout)
+		defer cancel()
+		data, err := bundle.Fetch(fetchCtx, opt.Manifests.URL, constant.ManifestsSizeLimit)
+		if err != nil {
+			return nil, nil, err
+		}
+		files = map[string]string{"manifests.yaml": string(data)}
+	} else {
+		unpacked, err := r.unpackBundle(ctx, opt.Name, namespace, opt.Manifests.BundleImage)
+		if err != nil || unpacked == nil {
+			return nil, nil, err
+		}
+		files = unpacked
+	}
+	// The API server would reject the ConfigMap with an opaque error
+	if err := bundle.CheckSize(files, source, constant.ManifestsSizeLimit); err != nil {
+		return nil, nil, err
+	}
+
+	cm.Annotations[constant.ManifestsSourceAnnotation] = source
+	cm.Data = files
+	var err error
+	if cm.ResourceVersion == "" {
+		err = r.Create(ctx, cm)
+	} else {
+		err = r.Update(ctx, cm)
+	}
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to save the manifests of the operator %s", opt.Name)
+	}
+	return cm, files, nil
+}
+
+// unpackBundle runs a Job to unpack the manifests of the bundle image and reads them from the pod log.
+// It returns nil manifest files until the Job is completed.
+func (r *Reconciler) unpackBundle(ctx context.Context, name, namespace, image string) (map[string]string, error) {
+	log := logging.FromContext(ctx)
+	jobKey := types.NamespacedName{Namespace: namespace, Name: name + constant.BundleUnpackJobSuffix}
+	job := &batchv1.Job{}
+	if err := r.Reader.Get(ctx, jobKey, job); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to get the bundle unpack Job %s", jobKey.String())
+		}
+		log.V(logging.LevelChange).Info("Creating the bundle unpack Job", "job", jobKey.String(), "image", image)
+		job = bundle.UnpackJob(jobKey.Name, jobKey.Namespace, image, util.GetOperatorImage())
+		if err := r.Create(ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+			return nil, errors.Wrapf(err, "failed to create the bundle unpack Job %s", jobKey.String())
+		}
+		return nil, nil
+	}
+
+	// The bundle image is changed, unpack it again
+	if job.Annotations[constant.ManifestsSourceAnnotation] != image {
+		log.V(logging.LevelChange).Info("The bundle image of the operator is changed, deleting the bundle unpack Job", "operator", name, "image", image, "job", jobKey.String())
+		if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to delete the bundle unpack Job %s", jobKey.String())
+		}
+		return nil, nil
+	}
+
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			return nil, fmt.Errorf("the bundle unpack Job %s is failed: %s", jobKey.String(), c.Message)
+		}
+	}
+	if job.Status.Succeeded == 0 {
+		return nil, nil
+	}
+
+	podList := &corev1.PodList{}
+	if err := r.Reader.List(ctx, podList, client.InNamespace(namespace), client.MatchingLabels{"job-name": jobKey.Name}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list the pods of the bundle unpack Job %s", jobKey.String())
+	}
+	var podName string
+	for _, pod := range podList.Items {
+		if pod.Status.Phase == corev1.PodSucceeded {
+			podName = pod.Name
+			break
+		}
+	}
+	if podName == "" {
+		return nil, fmt.Errorf("no succeeded pod found for the bundle unpack Job %s", jobKey.String())
+	}
+
+	clientset, err := kubernetes.NewForConfig(r.Config)
+	if err != nil {
+		return nil, err
+	}
+	podLog, err := clientset.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{Container: "bundle"}).DoRaw(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the log of the bundle unpack pod %s/%s", namespace, podName)
+	}
+	files, err := bundle.DecodeUnpacked(podLog)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to unpack the bundle image %s", image)
+	}
+
+	// The manifests are cached in the ConfigMap, the Job is not needed anymore
+	if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+		log.Info("Failed to delete the bundle unpack Job", "job", jobKey.String(), "error", err.Error())
+	}
+	return files, nil
+}
+
+// applyManifests creates or updates the objects of the manifests, and deletes the objects
+// applied from the previous manifests but not in the current ones. The applied objects are
+// recorded in the annotation of the manifests ConfigMap. It returns true if any object is changed.
+func (r *Reconciler) applyManifests(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, opt *operatorv1alpha1.Operator, cm *corev1.ConfigMap, manifests *bundle.Manifests) (bool, error) {
+	log := logging.FromContext(ctx)
+	labels := map[string]string{
+		constant.OpreqLabel:          "true",
+		constant.ManifestsOwnerLabel: opt.Name,
+	}
+
+	namespaced := make(map[string]bool)
+
+	changed := false
+	crdChanged := false
+	applied := make(map[string]manifestsRef)
+	var appliedRefs []manifestsRef
+	for _, obj := range manifests.Objects {
+		obj := obj.DeepCopy()
+		apiVersion := obj.GetAPIVersion()
+		kind := obj.GetKind()
+
+		if obj.GetNamespace() == "" {
+			gvk := apiVersion + "/" + kind
+			isNamespaced, ok := namespaced[gvk]
+			if !ok {
+				var err error
+				isNamespaced, err = odlmutil.ResourceNamespaced(r.Discovery, apiVersion, kind)
+				if err != nil {
+					return changed, errors.Wrapf(err, "failed to check resource scope for Kind: %s", kind)
+				}
+				namespaced[gvk] = isNamespaced
+			}
+			if isNamespaced {
+				obj.SetNamespace(cm.Namespace)
+			}
+		}
+
+		r.EnsureLabel(*obj, labels)
+		if err := bundle.InjectConfig(obj, opt.SubscriptionConfig, opt.PriorityClassName, profile.Affinity(opt.PlacementProfile)); err != nil {
+			return changed, err
+		}
+		hash, err := bundle.Hash(*obj)
+		if err != nil {
+			return changed, err
+		}
+		r.EnsureAnnotation(*obj, map[string]string{constant.HashedData: hash})
+
+		if err := r.checkRenderedSize(ctx, *obj); err != nil {
+			return changed, err
+		}
+
+		ref := manifestsRef{APIVersion: apiVersion, Kind: kind, Namespace: obj.GetNamespace(), Name: obj.GetName()}
+		existing := &unstructured.Unstructured{}
+		existing.SetAPIVersion(apiVersion)
+		existing.SetKind(kind)
+		err = r.Reader.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, existing)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return changed, errors.Wrapf(err, "failed to get k8s resource -- Kind: %s, NamespacedName: %s/%s", kind, ref.Namespace, ref.Name)
+		}
+
+		if apierrors.IsNotFound(err) {
+			log.V(logging.LevelChange).Info("Creating the k8s resource of the operator", "operator", opt.Name, "kind", kind, "name", ref.Namespace+"/"+ref.Name)
+			requestInstance.SetCreatingCondition(opt.Name, operatorv1alpha1.ResourceTypeManifests, corev1.ConditionTrue, &r.Mutex)
+			if err := r.Create(ctx, obj); err != nil && !apierrors.IsAlreadyExists(err) {
+				requestInstance.SetCreatingCondition(opt.Name, operatorv1alpha1.ResourceTypeManifests, corev1.ConditionFalse, &r.Mutex)
+				return changed, errors.Wrapf(err, "failed to create k8s resource -- Kind: %s, NamespacedName: %s/%s", kind, ref.Namespace, ref.Name)
+			}
+			changed = true
+			crdChanged = crdChanged || kind == "CustomResourceDefinition"
+		} else if !r.CheckLabel(*existing, map[string]string{constant.OpreqLabel: "true"}) {
+			log.V(logging.LevelFlow).Info("The k8s resource isn't created by ODLM, ignore updating or deleting it", "kind", kind, "name", ref.Namespace+"/"+ref.Name)
+			continue
+		} else if existing.GetAnnotations()[constant.HashedData] != hash {
+			log.V(logging.LevelChange).Info("Updating the k8s resource of the operator", "operator", opt.Name, "kind", kind, "name", ref.Namespace+"/"+ref.Name)
+			requestInstance.SetUpdatingCondition(opt.Name, operatorv1alpha1.ResourceTypeManifests, corev1.ConditionTrue, &r.Mutex)
+			obj.SetResourceVersion(existing.GetResourceVersion())
+			if err := r.Update(ctx, obj); err != nil {
+				requestInstance.SetUpdatingCondition(opt.Name, operatorv1alpha1.ResourceTypeManifests, corev1.ConditionFalse, &r.Mutex)
+				return changed, errors.Wrapf(err, "failed to update k8s resource -- Kind: %s, NamespacedName: %s/%s", kind, ref.Namespace, ref.Name)
+			}
+			changed = true
+			crdChanged = crdChanged || kind == "CustomResourceDefinition"
+		}
+		applied[ref.String()] = ref
+		appliedRefs = append(appliedRefs, ref)
+	}
+
+	// The kinds of the CRDs are discovered again, without waiting for the CRD watch
+	if crdChanged {
+		r.Discovery.Invalidate()
+	}
+
+	// Delete the objects removed from the manifests by an upgrade
+	for _, ref := range appliedManifestsRefs(ctx, cm) {
+		if _, ok := applied[ref.String()]; ok {
+			continue
+		}
+		deleted, err := r.deleteManifestsObject(ctx, ref, opt.Name)
+		if err != nil {
+			return changed, err
+		}
+		changed = changed || deleted
+	}
+
+	raw, err := json.Marshal(appliedRefs)
+	if err != nil {
+		return changed, err
+	}
+	cm.Annotations[constant.ManifestsAppliedAnnotation] = string(raw)
+	return changed, nil
+}
+
+// appliedManifestsRefs returns the objects recorded in the manifests ConfigMap
+func appliedManifestsRefs(ctx context.Context, cm *corev1.ConfigMap) []manifestsRef {
+	var refs []manifestsRef
+	raw, ok := cm.Annotations[constant.ManifestsAppliedAnnotation]
+	if !ok {
+		return refs
+	}
+	if err := json.Unmarshal([]byte(raw), &refs); err != nil {
+		logging.FromContext(ctx).Info("Failed to decode the applied objects of ConfigMap", "configMap", cm.Namespace+"/"+cm.Name, "error", err.Error())
+	}
+	return refs
+}
+
+// deleteManifestsObject deletes an object applied from the manifests of the operator.
+// CustomResourceDefinitions are kept, like OLM does, so that the custom resources are not lost.
+func (r *Reconciler) deleteManifestsObject(ctx context.Context, ref manifestsRef, operatorName string) (bool, error) {
+	if ref.Kind == "CustomResourceDefinition" {
+		return false, nil
+	}
+	existing := &unstructured.Unstructured{}
+	existing.SetAPIVersion(ref.APIVersion)
+	existing.SetKind(ref.Kind)
+	if err := r.Reader.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, existing); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get k8s resource -- Kind: %s, NamespacedName: %s/%s", ref.Kind, ref.Namespace, ref.Name)
+	}
+	if !r.CheckLabel(*existing, map[string]string{constant.OpreqLabel: "true", constant.ManifestsOwnerLabel: operatorName}) {
+		return false, nil
+	}
+	logging.FromContext(ctx).V(logging.LevelChange).Info("Deleting the k8s resource of the operator", "operator", operatorName, "kind", ref.Kind, "name", ref.Namespace+"/"+ref.Name)
+	if err := r.Delete(ctx, existing, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+		return false, errors.Wrapf(err, "failed to delete k8s resource -- Kind: %s, NamespacedName: %s/%s", ref.Kind, ref.Namespace, ref.Name)
+	}
+	return true, nil
+}
+
+// getManifestsCSV returns the ClusterServiceVersion of the operator installed from its manifests.
+// It returns nil until the manifests are applied and the Deployments of the operator are available.
+func (r *Reconciler) getManifestsCSV(ctx context.Context, opt *operatorv1alpha1.Operator) (*olmv1alpha1.ClusterServiceVersion, error) {
+	namespace := r.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
+	cm := &corev1.ConfigMap{}
+	cmKey := types.NamespacedName{Namespace: namespace, Name: opt.Name + constant.ManifestsConfigMapSuffix}
+	if err := r.Reader.Get(ctx, cmKey, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the manifests ConfigMap %s", cmKey.String())
+	}
+	if _, ok := cm.Annotations[constant.ManifestsAppliedAnnotation]; !ok {
+		return nil, nil
+	}
+
+	manifests, err := bundle.Load(cm.Data, opt.Name, namespace, opt.TargetNamespaces)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load the manifests of the operator %s", opt.Name)
+	}
+
+	for _, obj := range manifests.Objects {
+		if obj.GetKind() != "Deployment" {
+			continue
+		}
+		deploy := &appsv1.Deployment{}
+		// Deployments are filtered in the cache, get it from the API server
+		if err := r.Reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: obj.GetName()}, deploy); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, errors.Wrapf(err, "failed to get the Deployment %s/%s", namespace, obj.GetName())
+		}
+		if deploy.Status.AvailableReplicas == 0 {
+			logging.FromContext(ctx).V(logging.LevelChange).Info("The Deployment of the operator is not available yet", "deployment", namespace+"/"+obj.GetName(), "operator", opt.Name)
+			return nil, nil
+		}
+	}
+	return manifests.CSV, nil
+}
+
+// deleteManifests uninstalls the operator installed from its manifests, when no other OperandRegistry uses it
+func (r *Reconciler) deleteManifests(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, configInstance *operatorv1alpha1.OperandConfig, op *operatorv1alpha1.Operator) error {
+	log := logging.FromContext(ctx)
+	namespace := r.GetOperatorNamespace(op.InstallMode, op.Namespace)
+	cm := &corev1.ConfigMap{}
+	cmKey := types.NamespacedName{Namespace: namespace, Name: op.Name + constant.ManifestsConfigMapSuffix}
+	if err := r.Reader.Get(ctx, cmKey, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.V(logging.LevelDebug).Info("There is no manifests ConfigMap", "configMap", cmKey.String())
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get the manifests ConfigMap %s", cmKey.String())
+	}
+	if _, ok := cm.Labels[constant.OpreqLabel]; !ok {
+		log.V(logging.LevelChange).Info("ConfigMap isn't created by ODLM", "configMap", cmKey.String())
+		return nil
+	}
+
+	// check and remove registry and config in annotation of the ConfigMap
+	originalCM := cm.DeepCopy()
+	delete(cm.Annotations, registryInstance.Namespace+"."+registryInstance.Name+"/registry")
+	delete(cm.Annotations, registryInstance.Namespace+"."+registryInstance.Name+"/config")
+	reg, _ := regexp.Compile(`^(.*)\.(.*)\/registry`)
+	for anno := range cm.Annotations {
+		if reg.MatchString(anno) {
+			if err := r.Patch(ctx, cm, client.MergeFrom(originalCM)); err != nil {
+				return err
+			}
+			log.V(logging.LevelFlow).Info("Did not delete the operator which is requested by OperandRequest with different OperandRegistry", "operator", op.Name)
+			return nil
+		}
+	}
+
+	manifests, err := bundle.Load(cm.Data, op.Name, namespace, op.TargetNamespaces)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load the manifests of the operator %s", op.Name)
+	}
+
+	log.V(logging.LevelChange).Info("Deleting all the Custom Resources for the operator", "operator", op.Name)
+	if err := r.deleteAllCustomResource(ctx, manifests.CSV, requestInstance, configInstance, op.Name, op.Namespace); err != nil {
+		return err
+	}
+	log.V(logging.LevelChange).Info("Deleting all the k8s Resources for the operator", "operator", op.Name)
+	if err := r.deleteAllK8sResource(ctx, configInstance, op.Name, op.Namespace); err != nil {
+		return err
+	}
+	if cm.Labels[constant.NotUninstallLabel] == "true" {
+		log.V(logging.LevelFlow).Info("Operator has label operator.ibm.com/opreq-do-not-uninstall, skip the uninstall", "operator", op.Name)
+		return nil
+	}
+
+	requestInstance.SetDeletingCondition(op.Name, operatorv1alpha1.ResourceTypeManifests, corev1.ConditionTrue, &r.Mutex)
+	merr := &util.MultiErr{}
+	for _, ref := range appliedManifestsRefs(ctx, cm) {
+		if _, err := r.deleteManifestsObject(ctx, ref, op.Name); err != nil {
+			merr.Add(err)
+		}
+	}
+	if len(merr.Errors) != 0 {
+		requestInstance.SetDeletingCondition(op.Name, operatorv1alpha1.ResourceTypeManifests, corev1.ConditionFalse, &r.Mutex)
+		return merr
+	}
+
+	if err := r.Delete(ctx, cm); err != nil && !apierrors.IsNotFound(err) {
+		requestInstance.SetDeletingCondition(op.Name, operatorv1alpha1.ResourceTypeManifests, corev1.ConditionFalse, &r.Mutex)
+		return errors.Wrapf(err, "failed to delete the manifests ConfigMap %s", cmKey.String())
+	}
+	log.V(logging.LevelFlow).Info("Operator installed from the manifests is deleted", "operator", op.Name)
+
+	return nil
+}
@@ -0,0 +1,152 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// operandMember is a member of the OperandRequest reconciled by the worker pool
+type operandMember struct {
+	// key is the OperandRegistry and the name of the member
+	key string
+	// requires are the keys of the operators the member requires in its OperandRegistry
+	requires  []string
+	reconcile func(ctx context.Context) error
+}
+
+// newOperandMember returns the member of the operand, with the operators it requires in the OperandRegistry
+func newOperandMember(registryKey types.NamespacedName, registryInstance *operatorv1alpha1.OperandRegistry, name string, reconcile func(ctx context.Context) error) operandMember {
+	member := operandMember{key: registryKey.String() + "/" + name, reconcile: reconcile}
+	if opt := registryInstance.GetOperator(name); opt != nil {
+		for _, required := range opt.Requires {
+			member.requires = append(member.requires, registryKey.String()+"/"+required)
+		}
+	}
+	return member
+}
+
+// memberWaves groups the members in waves, each member is in a wave after the members it requires.
+// A member requested twice is reconciled again in a later wave, and a cycle of requires is broken where it closes.
+func memberWaves(members []operandMember) [][]operandMember {
+	byKey := make(map[string][]int)
+	for i, m := range members {
+		byKey[m.key] = append(byKey[m.key], i)
+	}
+	waves := make([]int, len(members))
+	done := make([]bool, len(members))
+	visiting := make([]bool, len(members))
+	var wave func(i int) int
+	wave = func(i int) int {
+		if done[i] || visiting[i] {
+			return waves[i]
+		}
+		visiting[i] = true
+		w := 0
+		for _, required := range members[i].requires {
+			for _, j := range byKey[required] {
+				if j != i && !visiting[j] {
+					if next := wave(j) + 1; next > w {
+						w = next
+					}
+				}
+			}
+		}
+		// The same member requested again waits for the previous one
+		for _, j := range byKey[members[i].key] {
+			if j >= i {
+				break
+			}
+			if next := wave(j) + 1; next > w {
+				w = next
+			}
+		}
+		visiting[i] = false
+		done[i] = true
+		waves[i] = w
+		return w
+	}
+	var grouped [][]operandMember
+	for i := range members {
+		w := wave(i)
+		for len(grouped) <= w {
+			grouped = append(grouped, nil)
+		}
+		grouped[w] = append(grouped[w], members[i])
+	}
+	return grouped
+}
+
+// reconcileMembers reconciles the members in parallel, at most workers at a time, each one after the members it requires.
+// A failed member doesn't stop the others, the errors of all the members are returned wave by wave.
+func reconcileMembers(ctx context.Context, workers int, members []operandMember) *util.MultiErr {
+	merr := &util.MultiErr{}
+	for i, wave := range memberWaves(members) {
+		logging.FromContext(ctx).V(logging.LevelDebug).Info("Reconciling the members", "wave", i, "members", len(wave), "workers", workers)
+		group := util.NewGroup(workers)
+		for _, m := range wave {
+			m := m
+			group.Go(func() error {
+				return m.reconcile(ctx)
+			})
+		}
+		waveErr, _ := group.Wait(0)
+		merr.Errors = append(merr.Errors, waveErr.Errors...)
+	}
+	return merr
+}
+
+// memberRetryDelay returns the delay before the next retry of a member after its failed reconciles in a row
+func memberRetryDelay(retries int) time.Duration {
+	delay := constant.MemberRetryBaseDelay
+	for i := 1; i < retries && delay < constant.MemberRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > constant.MemberRetryMaxDelay {
+		delay = constant.MemberRetryMaxDelay
+	}
+	return delay
+}
+
+// retryMember reconciles the member unless it is backing off after its failed reconciles, and records the
+// failure and the next retry of the member in its status. A failed member is retried with an exponential backoff,
+// so a member which keeps failing, for example with a bad configuration, doesn't hold back the other members.
+func (r *Reconciler) retryMember(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, name string, reconcile func() error) error {
+	log := logging.FromContext(ctx)
+	if wait := requestInstance.GetMemberRetryWait(name, time.Now(), &r.Mutex); wait > 0 {
+		log.V(logging.LevelChange).Info("Member is backing off after its failed reconciles", "operand", name, "wait", wait.String())
+		return nil
+	}
+	err := reconcile()
+	if err == nil {
+		requestInstance.ResetMemberRetry(name, &r.Mutex)
+		return nil
+	}
+	retries := requestInstance.GetMemberRetries(name, &r.Mutex) + 1
+	delay := memberRetryDelay(retries)
+	requestInstance.SetMemberRetry(name, retries, err.Error(), time.Now().Add(delay), &r.Mutex)
+	log.Info("Member failed to reconcile, retrying it later", "operand", name, "retries", retries, "delay", delay.String())
+	return err
+}
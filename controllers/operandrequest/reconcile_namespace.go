@@ -0,0 +1,104 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// ensureOperatorNamespaces creates the namespace the operator is installed in when it doesn't exist.
+// With the namespace provisioning of the OperandRegistry, the namespace gets its labels and annotations,
+// the target namespaces of the operator are created as well if enabled, and the namespaces which can't be
+// created fail the operator. Without it, the namespace is created on a best effort basis.
+func (r *Reconciler) ensureOperatorNamespaces(ctx context.Context, opt *operatorv1alpha1.Operator, namespace string, registryKey types.NamespacedName) error {
+	registryInstance, err := r.GetOperandRegistry(ctx, registryKey)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the OperandRegistry %s", registryKey.String())
+	}
+	provisioning := registryInstance.Spec.NamespaceProvisioning
+	if provisioning == nil {
+		if err := r.createNamespace(ctx, namespace, nil); err != nil {
+			logging.FromContext(ctx).Info("Failed to create the namespace, please make sure it exists", "namespace", namespace, "error", err.Error())
+		}
+		return nil
+	}
+
+	namespaces := []string{namespace}
+	if provisioning.TargetNamespaces {
+		namespaces = append(namespaces, opt.TargetNamespaces...)
+	}
+	for _, ns := range namespaces {
+		if err := r.createNamespace(ctx, ns, provisioning); err != nil {
+			return errors.Wrapf(err, "failed to provision the namespace %s of the operator %s", ns, opt.Name)
+		}
+	}
+	return nil
+}
+
+// createNamespace creates the namespace with the labels and annotations of the provisioning when it doesn't exist.
+// The namespace of ODLM is never created, and the existing namespaces are left untouched.
+func (r *Reconciler) createNamespace(ctx context.Context, name string, provisioning *operatorv1alpha1.NamespaceProvisioning) error {
+	if name == "" || name == util.GetOperatorNamespace() {
+		return nil
+	}
+	if err := r.Reader.Get(ctx, types.NamespacedName{Name: name}, &corev1.Namespace{}); err == nil {
+		return nil
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	logging.FromContext(ctx).V(logging.LevelChange).Info("Creating the Namespace for Operator", "namespace", name)
+	if err := r.Create(ctx, newNamespace(name, provisioning)); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// newNamespace builds the namespace created by ODLM, with the labels and annotations of the provisioning.
+// The ODLM label can't be overridden by the provisioning.
+func newNamespace(name string, provisioning *operatorv1alpha1.NamespaceProvisioning) *corev1.Namespace {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{},
+		},
+	}
+	if provisioning != nil {
+		for key, value := range provisioning.Labels {
+			ns.Labels[key] = value
+		}
+		if len(provisioning.Annotations) != 0 {
+			ns.Annotations = make(map[string]string, len(provisioning.Annotations))
+			for key, value := range provisioning.Annotations {
+				ns.Annotations[key] = value
+			}
+		}
+	}
+	ns.Labels[constant.OpreqLabel] = "true"
+	return ns
+}
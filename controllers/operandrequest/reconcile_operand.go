@@ -24,13 +24,17 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -38,16 +42,26 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
-	"k8s.io/client-go/discovery"
-	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/backup"
 	constant "github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/patch"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/profile"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/provenance"
 	util "github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/metrics"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/throughput"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/tracing"
+	odlmutil "github.com/IBM/operand-deployment-lifecycle-manager/pkg/util/v1"
 )
 
 func (r *Reconciler) reconcileOperand(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) *util.MultiErr {
-	klog.V(1).Infof("Reconciling Operands for OperandRequest: %s/%s", requestInstance.GetNamespace(), requestInstance.GetName())
+	log := logging.FromContext(ctx)
+	log.V(logging.LevelFlow).Info("Reconciling Operands")
 	// Update request status
 	defer func() {
 		requestInstance.UpdateClusterPhase()
@@ -58,6 +72,11 @@ func (r *Reconciler) reconcileOperand(ctx context.Context, requestInstance *oper
 		merr.Add(err)
 		return merr
 	}
+	// A new spec may fix the failed members, they are reconciled right away
+	if requestInstance.Generation != requestInstance.Status.ObservedGeneration {
+		requestInstance.ResetMemberRetries()
+	}
+	var members []operandMember
 	for _, req := range requestInstance.Spec.Requests {
 		registryKey := requestInstance.GetRegistryKey(req)
 		registryInstance, err := r.GetOperandRegistry(ctx, registryKey)
@@ -65,216 +84,373 @@ func (r *Reconciler) reconcileOperand(ctx context.Context, requestInstance *oper
 			merr.Add(errors.Wrapf(err, "failed to get the OperandRegistry %s", registryKey.String()))
 			continue
 		}
-		regName := registryInstance.ObjectMeta.Name
-		regNs := registryInstance.ObjectMeta.Namespace
-
-		for i, operand := range req.Operands {
 
-			opdRegistry := registryInstance.GetOperator(operand.Name)
-			if opdRegistry == nil {
-				klog.Warningf("Cannot find %s in the OperandRegistry instance %s in the namespace %s ", operand.Name, req.Registry, req.RegistryNamespace)
-				requestInstance.SetMemberStatus(operand.Name, operatorv1alpha1.OperatorNotFound, operatorv1alpha1.ServiceNotFound, &r.Mutex)
+		// The implicit operands follow the requested ones, the index of a requested operand is unchanged
+		for i, operand := range registryInstance.WithRequiredOperands(req.Operands) {
+			// The scheduled operand is held by reconcileSubscription until its NotBefore time
+			if requestInstance.IsScheduled(operand, time.Now()) {
 				continue
 			}
+			req, registryInstance, operand, index := req, registryInstance, operand, i
+			members = append(members, newOperandMember(registryKey, registryInstance, operand.Name, func(ctx context.Context) error {
+				return r.retryMember(ctx, requestInstance, operand.Name, func() error {
+					return r.reconcileMember(ctx, requestInstance, req, registryKey, registryInstance, operand, index)
+				})
+			}))
+		}
+	}
+	// The members are reconciled in parallel, each one after the members it requires. The failed members are
+	// retried with their own backoff, they don't fail the reconcile of the other members.
+	if memberErr := reconcileMembers(ctx, throughput.Get().OperandWorkers, members); len(memberErr.Errors) != 0 {
+		log.Error(memberErr, "failed to reconcile the members of OperandRequest, retrying them with backoff")
+	}
+	if len(merr.Errors) != 0 {
+		return merr
+	}
+	log.V(logging.LevelFlow).Info("Finished reconciling Operands")
+	return &util.MultiErr{}
+}
 
-			operatorName := opdRegistry.Name
+// reconcileMember reconciles the custom resources of a member once its operator is running
+func (r *Reconciler) reconcileMember(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, req operatorv1alpha1.Request, registryKey types.NamespacedName, registryInstance *operatorv1alpha1.OperandRegistry, operand operatorv1alpha1.Operand, index int) error {
+	log := logging.FromContext(ctx)
+	opdRegistry, err := r.resolveOperator(ctx, requestInstance, registryInstance, operand.Name)
+	if err != nil {
+		return err
+	}
+	if opdRegistry == nil {
+		log.Info("Cannot find the operand in the OperandRegistry", "operand", operand.Name, "registry", req.RegistryNamespace+"/"+req.Registry)
+		requestInstance.SetMemberStatus(operand.Name, operatorv1alpha1.OperatorNotFound, operatorv1alpha1.ServiceNotFound, &r.Mutex)
+		return nil
+	}
 
-			klog.V(3).Info("Looking for csv for the operator: ", operatorName)
+	// The Installer of the operator type sets the member status until the operator is running
+	installer, err := r.getInstaller(opdRegistry)
+	if err != nil {
+		requestInstance.SetMemberStatus(operand.Name, operatorv1alpha1.OperatorFailed, "", &r.Mutex)
+		return err
+	}
+	csvCtx, span := tracing.Start(ctx, "WaitForOperator", attribute.String("operator", operand.Name), attribute.String("registry", registryKey.String()))
+	csv, err := installer.GetCSV(csvCtx, requestInstance, registryInstance, opdRegistry, &r.Mutex)
+	if csv != nil {
+		span.SetAttributes(attribute.String("csv", csv.Name))
+	}
+	tracing.End(span, err)
+	if err != nil {
+		return err
+	}
+	if csv == nil {
+		return nil
+	}
+	// The status of the paused operand is reported above, its custom resources are left as they are
+	if requestInstance.IsPaused(operand.Name) {
+		log.V(logging.LevelChange).Info("Operand is paused, skip reconciling the custom resources", "operand", operand.Name)
+		return nil
+	}
+	merr := &util.MultiErr{}
+	// The new version is running, release the previous version used before the migration
+	if err := r.releasePreviousVersion(ctx, requestInstance, registryInstance, opdRegistry); err != nil {
+		merr.Add(err)
+	}
 
-			// Looking for the CSV
-			namespace := r.GetOperatorNamespace(opdRegistry.InstallMode, opdRegistry.Namespace)
+	operandCtx, span := tracing.Start(ctx, "ApplyOperand", attribute.String("operator", operand.Name), attribute.String("csv", csv.Name))
+	operandMerr := &util.MultiErr{}
+	r.reconcileOperandResources(operandCtx, requestInstance, req, registryKey, operand, opdRegistry, csv, index, operandMerr)
+	if len(operandMerr.Errors) != 0 {
+		merr.Errors = append(merr.Errors, operandMerr.Errors...)
+		tracing.End(span, operandMerr)
+	} else {
+		tracing.End(span, nil)
+	}
+	if len(merr.Errors) != 0 {
+		return merr
+	}
+	return nil
+}
 
-			sub, err := r.GetSubscription(ctx, operatorName, namespace, opdRegistry.PackageName)
+// getSubscriptionCSV returns the ClusterServiceVersion installed by the Subscription of the operator
+func (r *Reconciler) getSubscriptionCSV(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, opdRegistry *operatorv1alpha1.Operator, mu sync.Locker) (*olmv1alpha1.ClusterServiceVersion, error) {
+	operatorName := opdRegistry.Name
+	regName := registryInstance.ObjectMeta.Name
+	regNs := registryInstance.ObjectMeta.Namespace
 
-			if err != nil {
-				if apierrors.IsNotFound(err) || sub == nil {
-					klog.Warningf("There is no Subscription %s or %s in the namespace %s", operatorName, opdRegistry.PackageName, namespace)
-					continue
-				}
-				merr.Add(errors.Wrapf(err, "failed to get the Subscription %s in the namespace %s", operatorName, namespace))
-				return merr
-			}
+	log := logging.FromContext(ctx)
+	log.V(logging.LevelDebug).Info("Looking for CSV for the operator", "operator", operatorName)
 
-			if _, ok := sub.Labels[constant.OpreqLabel]; !ok {
-				// Subscription existing and not managed by OperandRequest controller
-				klog.Warningf("Subscription %s in the namespace %s isn't created by ODLM", sub.Name, sub.Namespace)
-			}
+	// Looking for the CSV
+	namespace := r.GetOperatorNamespace(opdRegistry.InstallMode, opdRegistry.Namespace)
 
-			// For singleton services, identify latest OperandRegistry/Config version has the priority to reconcile
-			if CheckSingletonServices(operatorName) {
-				// v1IsLarger is true if subscription has larger channel version than the version in OperandRegistry
-				// Skip this operator CR creation because it does not have the latest version in OperandRegistry
-				v1IsLarger, convertErr := util.CompareChannelVersion(sub.Spec.Channel, opdRegistry.Channel)
-				if convertErr != nil {
-					merr.Add(errors.Wrapf(err, "failed to compare channel version for the Subscription %s in the namespace %s", operatorName, namespace))
-					return merr
-				}
-				if v1IsLarger {
-					klog.V(2).Infof("Subscription %s in the namespace %s is managed by other OperandRequest with newer version %s", sub.Name, sub.Namespace, sub.Spec.Channel)
-					requestInstance.SetMemberStatus(operand.Name, operatorv1alpha1.OperatorRunning, "", &r.Mutex)
-					continue
-				}
-			} else {
-				// check config annotation in subscription, identify the first ODLM has the priority to reconcile
-				var firstMatch string
-				reg, _ := regexp.Compile(`^(.*)\.(.*)\/config`)
-				for anno := range sub.Annotations {
-					if reg.MatchString(anno) {
-						firstMatch = anno
-						break
-					}
-				}
+	sub, err := r.GetSubscription(ctx, operatorName, namespace, opdRegistry.PackageName)
 
-				if firstMatch != "" && firstMatch != regNs+"."+regName+"/config" {
-					klog.V(2).Infof("Subscription %s in the namespace %s is currently managed by %s", sub.Name, sub.Namespace, firstMatch)
-					continue
-				}
-			}
+	if err != nil {
+		if apierrors.IsNotFound(err) || sub == nil {
+			log.Info("There is no Subscription for the operator", "name", operatorName, "package", opdRegistry.PackageName, "namespace", namespace)
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the Subscription %s in the namespace %s", operatorName, namespace)
+	}
 
-			// It the installplan is not created yet, ODLM will try later
-			if sub.Status.Install == nil || sub.Status.InstallPlanRef.Name == "" {
-				klog.Warningf("The Installplan for Subscription %s is not ready. Will check it again", sub.Name)
-				requestInstance.SetMemberStatus(operand.Name, operatorv1alpha1.OperatorInstalling, "", &r.Mutex)
-				continue
-			}
+	if _, ok := sub.Labels[constant.OpreqLabel]; !ok {
+		// Subscription existing and not managed by OperandRequest controller
+		log.Info("Subscription isn't created by ODLM", "subscription", sub.Namespace+"/"+sub.Name)
+	}
 
-			// If the installplan is deleted after is completed, ODLM won't block the CR update.
-			ipName := sub.Status.InstallPlanRef.Name
-			ipNamespace := sub.Namespace
-			ip := &olmv1alpha1.InstallPlan{}
-			ipKey := types.NamespacedName{
-				Name:      ipName,
-				Namespace: ipNamespace,
-			}
-			if err := r.Client.Get(ctx, ipKey, ip); err != nil {
-				if !apierrors.IsNotFound(err) {
-					merr.Add(errors.Wrapf(err, "failed to get Installplan"))
-				}
-			} else if ip.Status.Phase == olmv1alpha1.InstallPlanPhaseFailed {
-				klog.Errorf("installplan %s/%s is failed", ipNamespace, ipName)
-				requestInstance.SetMemberStatus(operand.Name, operatorv1alpha1.OperatorFailed, "", &r.Mutex)
-				continue
+	// For singleton services, identify latest OperandRegistry/Config version has the priority to reconcile
+	if CheckSingletonServices(operatorName) {
+		// Skip this operator CR creation when the subscription has a larger channel version than the one in OperandRegistry,
+		// because it does not have the latest version in OperandRegistry
+		scheme, err := deploy.GetChannelScheme(opdRegistry)
+		if err != nil {
+			return nil, err
+		}
+		c, convertErr := scheme.Compare(sub.Spec.Channel, opdRegistry.Channel)
+		if convertErr != nil {
+			return nil, errors.Wrapf(convertErr, "failed to compare channel version for the Subscription %s in the namespace %s", operatorName, namespace)
+		}
+		if c > 0 {
+			log.V(logging.LevelChange).Info("Subscription is managed by other OperandRequest with newer version", "subscription", sub.Namespace+"/"+sub.Name, "channel", sub.Spec.Channel)
+			requestInstance.SetMemberStatus(operatorName, operatorv1alpha1.OperatorRunning, "", mu)
+			return nil, nil
+		}
+	} else {
+		// check config annotation in subscription, identify the first ODLM has the priority to reconcile
+		var firstMatch string
+		reg, _ := regexp.Compile(`^(.*)\.(.*)\/config`)
+		for anno := range sub.Annotations {
+			if reg.MatchString(anno) {
+				firstMatch = anno
+				break
 			}
+		}
 
-			csv, err := r.GetClusterServiceVersion(ctx, sub)
+		if firstMatch != "" && firstMatch != regNs+"."+regName+"/config" {
+			log.V(logging.LevelChange).Info("Subscription is currently managed by another OperandConfig", "subscription", sub.Namespace+"/"+sub.Name, "managedBy", firstMatch)
+			return nil, nil
+		}
+	}
 
-			// If can't get CSV, requeue the request
-			if err != nil {
-				merr.Add(err)
-				requestInstance.SetMemberStatus(operand.Name, operatorv1alpha1.OperatorFailed, "", &r.Mutex)
-				continue
-			}
+	// It the installplan is not created yet, ODLM will try later
+	if sub.Status.Install == nil || sub.Status.InstallPlanRef.Name == "" {
+		log.Info("The InstallPlan for Subscription is not ready, will check it again", "subscription", sub.Namespace+"/"+sub.Name)
+		requestInstance.SetMemberStatus(operatorName, operatorv1alpha1.OperatorInstalling, "", mu)
+		return nil, nil
+	}
 
-			if csv == nil {
-				klog.Warningf("ClusterServiceVersion for the Subscription %s in the namespace %s is not ready yet, retry", operatorName, namespace)
-				requestInstance.SetMemberStatus(operand.Name, operatorv1alpha1.OperatorInstalling, "", &r.Mutex)
-				continue
-			}
+	// If the installplan is deleted after is completed, ODLM won't block the CR update.
+	ipName := sub.Status.InstallPlanRef.Name
+	ipNamespace := sub.Namespace
+	ip := &olmv1alpha1.InstallPlan{}
+	ipKey := types.NamespacedName{
+		Name:      ipName,
+		Namespace: ipNamespace,
+	}
+	if err := r.Client.Get(ctx, ipKey, ip); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to get Installplan")
+		}
+	} else if ip.Status.Phase == olmv1alpha1.InstallPlanPhaseFailed {
+		log.Error(nil, "InstallPlan is failed", "installPlan", ipNamespace+"/"+ipName)
+		if getOperatorPhase(requestInstance, operatorName, mu) != operatorv1alpha1.OperatorFailed {
+			r.recordOperatorEvent(requestInstance, registryInstance, corev1.EventTypeWarning, constant.EventReasonInstallFailed, "InstallPlan %s/%s of operator %s failed", ipNamespace, ipName, operatorName)
+		}
+		requestInstance.SetMemberStatus(operatorName, operatorv1alpha1.OperatorFailed, "", mu)
+		return nil, nil
+	}
 
-			klog.V(3).Info("Generating customresource base on ClusterServiceVersion: ", csv.GetName())
-			requestInstance.SetMemberStatus(operand.Name, operatorv1alpha1.OperatorRunning, "", &r.Mutex)
-
-			// Merge and Generate CR
-			if operand.Kind == "" {
-				configInstance, err := r.GetOperandConfig(ctx, registryKey)
-				if err == nil {
-					// Check the requested Service Config if exist in specific OperandConfig
-					opdConfig := configInstance.GetService(operand.Name)
-					if opdConfig == nil {
-						klog.V(2).Infof("There is no service: %s from the OperandConfig instance: %s/%s, Skip creating CR for it", operand.Name, req.RegistryNamespace, req.Registry)
-						continue
-					}
-					err = r.reconcileCRwithConfig(ctx, opdConfig, opdRegistry.Namespace, csv)
-					if err != nil {
-						merr.Add(err)
-						requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceFailed, &r.Mutex)
-					}
-				} else if apierrors.IsNotFound(err) {
-					klog.Infof("Not Found OperandConfig: %s/%s", operand.Name, err)
-				} else {
-					merr.Add(errors.Wrapf(err, "failed to get the OperandConfig %s", registryKey.String()))
-					continue
-				}
+	csv, err := r.GetClusterServiceVersion(ctx, sub)
 
-			} else {
-				err = r.reconcileCRwithRequest(ctx, requestInstance, operand, types.NamespacedName{Name: requestInstance.Name, Namespace: requestInstance.Namespace}, i)
-				if err != nil {
-					merr.Add(err)
-					requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceFailed, &r.Mutex)
-				}
+	// If can't get CSV, requeue the request
+	if err != nil {
+		requestInstance.SetMemberStatus(operatorName, operatorv1alpha1.OperatorFailed, "", mu)
+		return nil, err
+	}
+
+	if csv == nil {
+		log.Info("ClusterServiceVersion for the Subscription is not ready yet, retry", "subscription", namespace+"/"+operatorName)
+
+		requestInstance.SetMemberStatus(operatorName, operatorv1alpha1.OperatorInstalling, "", mu)
+		return nil, nil
+	}
+
+	if csv.Status.Phase == olmv1alpha1.CSVPhaseSucceeded {
+		if _, ok := sub.Labels[constant.OpreqLabel]; ok {
+			if err := r.reconcileOperatorPods(ctx, csv, opdRegistry.PriorityClassName, profile.Affinity(opdRegistry.PlacementProfile)); err != nil {
+				return nil, err
 			}
-			requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceRunning, &r.Mutex)
+		}
+		switch getOperatorPhase(requestInstance, operatorName, mu) {
+		case operatorv1alpha1.OperatorInstalling:
+			succeeded := time.Now()
+			if csv.Status.LastTransitionTime != nil {
+				succeeded = csv.Status.LastTransitionTime.Time
+			}
+			metrics.ObserveSubscriptionInstall(regNs+"/"+regName, operatorName, namespace, succeeded.Sub(sub.CreationTimestamp.Time))
+			r.recordOperatorEvent(requestInstance, registryInstance, corev1.EventTypeNormal, constant.EventReasonInstalled, "Installed operator %s with ClusterServiceVersion %s", operatorName, csv.Name)
+		case operatorv1alpha1.OperatorUpdating:
+			r.recordOperatorEvent(requestInstance, registryInstance, corev1.EventTypeNormal, constant.EventReasonUpgraded, "Operator %s is running ClusterServiceVersion %s after the update", operatorName, csv.Name)
 		}
 	}
-	if len(merr.Errors) != 0 {
-		return merr
+	return csv, nil
+}
+
+// getOperatorPhase returns the phase of the operator in the OperandRequest status
+func getOperatorPhase(requestInstance *operatorv1alpha1.OperandRequest, name string, mu sync.Locker) operatorv1alpha1.OperatorPhase {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, m := range requestInstance.Status.Members {
+		if m.Name == name {
+			return m.Phase.OperatorPhase
+		}
 	}
-	klog.V(1).Infof("Finished reconciling Operands for OperandRequest: %s/%s", requestInstance.GetNamespace(), requestInstance.GetName())
-	return &util.MultiErr{}
+	return operatorv1alpha1.OperatorNone
 }
 
-// reconcileCRwithConfig merge and create custom resource base on OperandConfig and CSV alm-examples
-func (r *Reconciler) reconcileCRwithConfig(ctx context.Context, service *operatorv1alpha1.ConfigService, namespace string, csv *olmv1alpha1.ClusterServiceVersion) error {
-	merr := &util.MultiErr{}
+// reconcileOperandResources merges and creates the custom resources of an operand whose operator is running
+func (r *Reconciler) reconcileOperandResources(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, req operatorv1alpha1.Request, registryKey types.NamespacedName, operand operatorv1alpha1.Operand, opdRegistry *operatorv1alpha1.Operator, csv *olmv1alpha1.ClusterServiceVersion, index int, merr *util.MultiErr) {
+	log := logging.FromContext(ctx)
+	log.V(logging.LevelDebug).Info("Generating custom resources based on the ClusterServiceVersion", "csv", csv.GetName())
+	requestInstance.SetMemberStatus(operand.Name, operatorv1alpha1.OperatorRunning, "", &r.Mutex)
+	requestInstance.RemoveWaitingForCRDCondition(operand.Name, operatorv1alpha1.ResourceTypeOperand, &r.Mutex)
 
-	// Create k8s resources required by service
-	if service.Resources != nil {
-		for _, res := range service.Resources {
-			if res.APIVersion == "" {
-				return fmt.Errorf("The APIVersion of k8s resource is empty for operator " + service.Name)
+	// Merge and Generate CR
+	if operand.Kind == "" {
+		configInstance, err := r.GetOperandConfig(ctx, registryKey)
+		if err == nil {
+			// Check the requested Service Config if exist in specific OperandConfig
+			opdConfig := configInstance.GetService(operand.Name)
+			if opdConfig == nil {
+				log.V(logging.LevelChange).Info("There is no service in the OperandConfig, skip creating CR for it", "service", operand.Name, "config", req.RegistryNamespace+"/"+req.Registry)
+				return
 			}
-
-			if res.Kind == "" {
-				return fmt.Errorf("The Kind of k8s resource is empty for operator " + service.Name)
+			sizeProfile, err := r.selectSizeProfile(ctx, requestInstance, registryKey, operand, opdConfig)
+			if err != nil {
+				merr.Add(err)
+				requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceFailed, &r.Mutex)
+				return
 			}
-			if res.Name == "" {
-				return fmt.Errorf("The Name of k8s resource is empty for operator " + service.Name)
+			if opdConfig, err = renderSizeProfile(ctx, opdConfig, sizeProfile); err != nil {
+				merr.Add(err)
+				requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceFailed, &r.Mutex)
+				return
 			}
-			var k8sResNs string
-			if res.Namespace == "" {
-				k8sResNs = namespace
-			} else {
-				k8sResNs = res.Namespace
+			replicas, err := r.selectReplicas(ctx, requestInstance, registryKey, operand, opdConfig)
+			if err != nil {
+				merr.Add(err)
+				requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceFailed, &r.Mutex)
+				return
 			}
+			if opdConfig, err = renderReplicas(ctx, opdConfig, replicas); err != nil {
+				merr.Add(err)
+				requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceFailed, &r.Mutex)
+				return
+			}
+			if opdConfig, err = r.renderZoneAware(ctx, opdConfig); err != nil {
+				merr.Add(err)
+				requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceFailed, &r.Mutex)
+				return
+			}
+			if opdConfig, err = r.renderProxy(ctx, opdConfig, opdRegistry.Namespace); err != nil {
+				merr.Add(err)
+				requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceFailed, &r.Mutex)
+				return
+			}
+			if opdConfig, err = renderPlacementProfile(ctx, opdConfig); err != nil {
+				merr.Add(err)
+				requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceFailed, &r.Mutex)
+				return
+			}
+			err = r.reconcileCRwithConfig(ctx, requestInstance, opdConfig, opdRegistry.Namespace, csv, registryKey)
+			if err != nil {
+				merr.Add(err)
+				requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceFailed, &r.Mutex)
+			}
+		} else if apierrors.IsNotFound(err) {
+			log.Info("Not found OperandConfig", "operand", operand.Name, "config", registryKey.String())
+		} else {
+			merr.Add(errors.Wrapf(err, "failed to get the OperandConfig %s", registryKey.String()))
+			return
+		}
 
-			var k8sRes unstructured.Unstructured
-			k8sRes.SetAPIVersion(res.APIVersion)
-			k8sRes.SetKind(res.Kind)
-			k8sRes.SetName(res.Name)
-			k8sRes.SetNamespace(k8sResNs)
-
-			verbs := []string{"create", "delete", "get", "update"}
-			if r.checkResAuth(ctx, verbs, k8sRes) {
-				err := r.Client.Get(ctx, types.NamespacedName{
-					Name:      res.Name,
-					Namespace: k8sResNs,
-				}, &k8sRes)
+	} else {
+		err := r.reconcileCRwithRequest(ctx, requestInstance, operand, types.NamespacedName{Name: requestInstance.Name, Namespace: requestInstance.Namespace}, index, registryKey)
+		if err != nil {
+			merr.Add(err)
+			requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceFailed, &r.Mutex)
+		}
+	}
+	// The custom resources waiting for their CRD are created when it is established
+	if len(merr.Errors) == 0 && requestInstance.IsWaitingForCRD(operand.Name, &r.Mutex) {
+		requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceCreating, &r.Mutex)
+		return
+	}
+	if len(merr.Errors) == 0 {
+		healthy, err := r.checkOperandHealth(ctx, requestInstance, registryKey, operand, opdRegistry, csv, index)
+		if err != nil {
+			merr.Add(err)
+			requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceFailed, &r.Mutex)
+			return
+		}
+		if !healthy {
+			requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceCreating, &r.Mutex)
+			return
+		}
+	}
+	// The post-install hooks verify the operand before it turns Running
+	if getOperandPhase(requestInstance, operand.Name, &r.Mutex) != operatorv1alpha1.ServiceRunning {
+		verified, err := r.verifyOperand(ctx, requestInstance, registryKey, operand, opdRegistry)
+		if err != nil {
+			merr.Add(err)
+			requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceFailed, &r.Mutex)
+			return
+		}
+		if !verified {
+			requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceCreating, &r.Mutex)
+			return
+		}
+	}
+	// The PostInstall callbacks are called when the operand gets ready
+	if getOperandPhase(requestInstance, operand.Name, &r.Mutex) != operatorv1alpha1.ServiceRunning {
+		if err := r.notify(ctx, operatorv1alpha1.CallbackPostInstall, requestInstance, registryKey, opdRegistry); err != nil {
+			merr.Add(err)
+			requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceCreating, &r.Mutex)
+			return
+		}
+	}
+	requestInstance.SetMemberStatus(operand.Name, "", operatorv1alpha1.ServiceRunning, &r.Mutex)
+}
 
-				if err != nil && !apierrors.IsNotFound(err) {
-					merr.Add(errors.Wrapf(err, "failed to get k8s resource %s/%s", k8sResNs, res.Name))
-				} else if apierrors.IsNotFound(err) {
-					if err := r.createK8sResource(ctx, k8sRes, res.Data, res.Labels, res.Annotations); err != nil {
-						merr.Add(err)
-					}
-				} else {
-					if r.CheckLabel(k8sRes, map[string]string{constant.OpreqLabel: "true"}) && res.Force {
-						// Update k8s resource
-						klog.V(3).Info("Found existing k8s resource: " + res.Name)
-						if err := r.updateK8sResource(ctx, k8sRes, res.Data, res.Labels, res.Annotations); err != nil {
-							merr.Add(err)
-						}
-					} else {
-						klog.V(2).Infof("Skip the k8s resource %s/%s which is not created by ODLM", res.Kind, res.Name)
-					}
-				}
-			} else {
-				klog.Infof("ODLM doesn't have enough permission to reconcile k8s resource -- Kind: %s, NamespacedName: %s/%s", res.Kind, k8sResNs, res.Name)
-			}
+// reconcileCRwithConfig merge and create custom resource base on OperandConfig and CSV alm-examples.
+// The k8s resources and the custom resources are created in the order of their Argo CD sync waves,
+// the resources of a wave wait until the ones of the previous waves are reconciled without error.
+func (r *Reconciler) reconcileCRwithConfig(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, service *operatorv1alpha1.ConfigService, namespace string, csv *olmv1alpha1.ClusterServiceVersion, registryKey types.NamespacedName) error {
+	log := logging.FromContext(ctx)
+
+	// The k8s resources of a wave are reconciled before the custom resources of the same wave
+	var children []syncWaveChild
+	for _, res := range service.Resources {
+		if res.APIVersion == "" {
+			return fmt.Errorf("The APIVersion of k8s resource is empty for operator " + service.Name)
 		}
 
-		if len(merr.Errors) != 0 {
-			return merr
+		if res.Kind == "" {
+			return fmt.Errorf("The Kind of k8s resource is empty for operator " + service.Name)
+		}
+		if res.Name == "" {
+			return fmt.Errorf("The Name of k8s resource is empty for operator " + service.Name)
 		}
+		res := res
+		children = append(children, syncWaveChild{
+			wave:     util.SyncWave(res.Annotations),
+			resource: true,
+			reconcile: func() error {
+				return r.reconcileK8sResource(ctx, requestInstance, res, namespace)
+			},
+		})
+	}
+
+	// Include the namespace of the custom resources in the backups of the service
+	if err := r.reconcileBackup(ctx, service, namespace); err != nil {
+		return err
 	}
 
 	almExamples := csv.GetAnnotations()["alm-examples"]
@@ -297,59 +473,161 @@ func (r *Reconciler) reconcileCRwithConfig(ctx context.Context, service *operato
 		var crFromALM unstructured.Unstructured
 		crFromALM.Object = almExample.(map[string]interface{})
 
-		name := crFromALM.GetName()
-		spec := crFromALM.Object["spec"]
-		if spec == nil {
+		if crFromALM.Object["spec"] == nil {
 			continue
 		}
-
-		err := r.Client.Get(ctx, types.NamespacedName{
-			Name:      name,
-			Namespace: namespace,
-		}, &crFromALM)
-
 		for cr := range service.Spec {
 			if strings.EqualFold(crFromALM.GetKind(), cr) {
 				foundMap[cr] = true
 			}
 		}
+		children = append(children, syncWaveChild{
+			wave: util.SyncWave(crFromALM.GetAnnotations()),
+			reconcile: func() error {
+				return r.reconcileALMExample(ctx, requestInstance, crFromALM, service, namespace, registryKey)
+			},
+		})
+	}
 
-		if err != nil && !apierrors.IsNotFound(err) {
-			merr.Add(errors.Wrapf(err, "failed to get the custom resource %s/%s", namespace, name))
-			continue
-		} else if apierrors.IsNotFound(err) {
-			// Create Custom Resource
-			if err := r.compareConfigandExample(ctx, crFromALM, service, namespace); err != nil {
-				merr.Add(err)
-				continue
-			}
-		} else {
-			if r.CheckLabel(crFromALM, map[string]string{constant.OpreqLabel: "true"}) {
-				// Update or Delete Custom Resource
-				if err := r.existingCustomResource(ctx, crFromALM, spec.(map[string]interface{}), service, namespace); err != nil {
-					merr.Add(err)
-					continue
-				}
-			} else {
-				klog.V(2).Info("Skip the custom resource not created by ODLM")
-			}
+	if err := reconcileSyncWaves(ctx, children); err != nil {
+		return err
+	}
+
+	for cr, found := range foundMap {
+		if !found {
+			log.Info("Custom resource doesn't exist in the alm-example", "cr", cr, "csv", csv.GetName())
+		}
+	}
+
+	return nil
+}
+
+// syncWaveChild is a k8s resource or a custom resource ODLM creates for a service, in an Argo CD sync wave
+type syncWaveChild struct {
+	wave int
+	// resource is true for a k8s resource, which is reconciled before the custom resources of its wave
+	resource  bool
+	reconcile func() error
+}
+
+// before tells if the child is reconciled in a step before the other one
+func (c syncWaveChild) before(other syncWaveChild) bool {
+	if c.wave != other.wave {
+		return c.wave < other.wave
+	}
+	return c.resource && !other.resource
+}
+
+// reconcileSyncWaves reconciles the children wave by wave, the lowest wave first, and the k8s resources
+// of a wave before its custom resources. The children of a step are all reconciled, but the next steps
+// wait until none of them failed.
+func reconcileSyncWaves(ctx context.Context, children []syncWaveChild) error {
+	sort.SliceStable(children, func(i, j int) bool {
+		return children[i].before(children[j])
+	})
+	merr := &util.MultiErr{}
+	for i, child := range children {
+		if i > 0 && children[i-1].before(child) && len(merr.Errors) != 0 {
+			logging.FromContext(ctx).V(logging.LevelFlow).Info("Wait for the previous sync waves to be reconciled", "wave", child.wave)
+			return merr
+		}
+		if err := child.reconcile(); err != nil {
+			merr.Add(err)
 		}
 	}
 	if len(merr.Errors) != 0 {
 		return merr
 	}
+	return nil
+}
 
-	for cr, found := range foundMap {
-		if !found {
-			klog.Warningf("Custom resource %v doesn't exist in the alm-example of %v", cr, csv.GetName())
-		}
+// reconcileK8sResource creates the k8s resource required by the service, or updates it when it is forced
+func (r *Reconciler) reconcileK8sResource(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, res operatorv1alpha1.ConfigResource, namespace string) error {
+	log := logging.FromContext(ctx)
+
+	var k8sResNs string
+	if res.Namespace == "" {
+		k8sResNs = namespace
+	} else {
+		k8sResNs = res.Namespace
+	}
+	excluded, err := r.IsNamespaceExcluded(ctx, k8sResNs)
+	if err != nil {
+		return err
+	}
+	if excluded {
+		log.Info("Skip the k8s resource in the excluded namespace", "kind", res.Kind, "name", k8sResNs+"/"+res.Name)
+		return nil
 	}
 
+	var k8sRes unstructured.Unstructured
+	k8sRes.SetAPIVersion(res.APIVersion)
+	k8sRes.SetKind(res.Kind)
+	k8sRes.SetName(res.Name)
+	k8sRes.SetNamespace(k8sResNs)
+
+	verbs := []string{"create", "delete", "get", "update"}
+	if !r.checkResAuth(ctx, verbs, k8sRes) {
+		log.Info("ODLM doesn't have enough permission to reconcile k8s resource", "kind", res.Kind, "name", k8sResNs+"/"+res.Name)
+		return nil
+	}
+	err = r.Client.Get(ctx, types.NamespacedName{
+		Name:      res.Name,
+		Namespace: k8sResNs,
+	}, &k8sRes)
+
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to get k8s resource %s/%s", k8sResNs, res.Name)
+	} else if apierrors.IsNotFound(err) {
+		return r.createK8sResource(ctx, requestInstance, k8sRes, res.Data, res.Labels, res.Annotations)
+	}
+	if r.CheckLabel(k8sRes, map[string]string{constant.OpreqLabel: "true"}) && res.Force {
+		// Update k8s resource
+		log.V(logging.LevelDebug).Info("Found existing k8s resource", "kind", res.Kind, "name", res.Name)
+		return r.updateK8sResource(ctx, requestInstance, k8sRes, res.Data, res.Labels, res.Annotations)
+	}
+	log.V(logging.LevelChange).Info("Skip the k8s resource which is not created by ODLM", "kind", res.Kind, "name", res.Name)
+	return nil
+}
+
+// reconcileALMExample creates the custom resource of the alm-example merged with the service,
+// or updates the existing one
+func (r *Reconciler) reconcileALMExample(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, crFromALM unstructured.Unstructured, service *operatorv1alpha1.ConfigService, namespace string, registryKey types.NamespacedName) error {
+	name := crFromALM.GetName()
+	spec := crFromALM.Object["spec"]
+
+	err := r.Client.Get(ctx, types.NamespacedName{
+		Name:      name,
+		Namespace: namespace,
+	}, &crFromALM)
+
+	if meta.IsNoMatchError(err) {
+		return r.waitForCRD(ctx, requestInstance, service.Name, crFromALM.GroupVersionKind())
+	} else if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to get the custom resource %s/%s", namespace, name)
+	} else if apierrors.IsNotFound(err) {
+		// Create Custom Resource
+		return r.compareConfigandExample(ctx, requestInstance, crFromALM, service, namespace)
+	}
+
+	managed := r.CheckLabel(crFromALM, map[string]string{constant.OpreqLabel: "true"})
+	if !managed {
+		// Adopt the existing custom resource, or report the conflict
+		if managed, err = r.adoptCustomResource(ctx, requestInstance, crFromALM); err != nil {
+			return err
+		}
+	}
+	if managed {
+		requestInstance.RemoveConflictCondition(crFromALM.GetKind()+" "+namespace+"/"+name, operatorv1alpha1.ResourceTypeOperand, &r.Mutex)
+		// Update or Delete Custom Resource
+		return r.existingCustomResource(ctx, requestInstance, crFromALM, spec.(map[string]interface{}), service, namespace, registryKey)
+	}
 	return nil
 }
 
 // reconcileCRwithRequest merge and create custom resource base on OperandRequest and CSV alm-examples
-func (r *Reconciler) reconcileCRwithRequest(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, operand operatorv1alpha1.Operand, requestKey types.NamespacedName, index int) error {
+func (r *Reconciler) reconcileCRwithRequest(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, operand operatorv1alpha1.Operand, requestKey types.NamespacedName, index int, registryKey types.NamespacedName) error {
+	log := logging.FromContext(ctx)
 	merr := &util.MultiErr{}
 
 	// Create an unstructured object for CR and check its value
@@ -363,13 +641,7 @@ func (r *Reconciler) reconcileCRwithRequest(ctx context.Context, requestInstance
 		return fmt.Errorf("The Kind of operand is empty for operator " + operand.Name)
 	}
 
-	var name string
-	if operand.InstanceName == "" {
-		crInfo := sha256.Sum256([]byte(operand.APIVersion + operand.Kind + strconv.Itoa(index)))
-		name = requestKey.Name + "-" + hex.EncodeToString(crInfo[:7])
-	} else {
-		name = operand.InstanceName
-	}
+	name := OperandInstanceName(requestKey.Name, operand, index)
 
 	crFromRequest.SetName(name)
 	crFromRequest.SetNamespace(requestKey.Namespace)
@@ -381,23 +653,31 @@ func (r *Reconciler) reconcileCRwithRequest(ctx context.Context, requestInstance
 		Namespace: requestKey.Namespace,
 	}, &crFromRequest)
 
-	if err != nil && !apierrors.IsNotFound(err) {
+	if meta.IsNoMatchError(err) {
+		return r.waitForCRD(ctx, requestInstance, operand.Name, crFromRequest.GroupVersionKind())
+	} else if err != nil && !apierrors.IsNotFound(err) {
 		merr.Add(errors.Wrapf(err, "failed to get custom resource %s/%s", requestKey.Namespace, name))
 	} else if apierrors.IsNotFound(err) {
 		// Create Custom resource
-		if err := r.createCustomResource(ctx, crFromRequest, requestKey.Namespace, operand.Kind, operand.Spec.Raw); err != nil {
+		if err := r.createCustomResource(ctx, requestInstance, crFromRequest, requestKey.Namespace, operand.Kind, operand.Spec.Raw, nil, operand.Name, provenance.SourceOperandRequest, operand.Patches); err != nil {
 			merr.Add(err)
 		}
 		requestInstance.SetMemberCRStatus(operand.Name, name, operand.Kind, operand.APIVersion, &r.Mutex)
 	} else {
-		if r.CheckLabel(crFromRequest, map[string]string{constant.OpreqLabel: "true"}) {
+		managed := r.CheckLabel(crFromRequest, map[string]string{constant.OpreqLabel: "true"})
+		if !managed {
+			// Adopt the existing custom resource, or report the conflict
+			if managed, err = r.adoptCustomResource(ctx, requestInstance, crFromRequest); err != nil {
+				return err
+			}
+		}
+		if managed {
+			requestInstance.RemoveConflictCondition(operand.Kind+" "+requestKey.Namespace+"/"+name, operatorv1alpha1.ResourceTypeOperand, &r.Mutex)
 			// Update or Delete Custom resource
-			klog.V(3).Info("Found existing custom resource: " + operand.Kind)
-			if err := r.updateCustomResource(ctx, crFromRequest, requestKey.Namespace, operand.Kind, operand.Spec.Raw, map[string]interface{}{}); err != nil {
+			log.V(logging.LevelDebug).Info("Found existing custom resource", "kind", operand.Kind, "name", name)
+			if err := r.updateCustomResource(ctx, requestInstance, crFromRequest, requestKey.Namespace, operand.Kind, operand.Spec.Raw, map[string]interface{}{}, false, operatorv1alpha1.RemediationEnforce, nil, registryKey, operand.Name, provenance.SourceOperandRequest, operand.Patches); err != nil {
 				return err
 			}
-		} else {
-			klog.V(2).Info("Skip the custom resource not created by ODLM")
 		}
 	}
 
@@ -407,9 +687,18 @@ func (r *Reconciler) reconcileCRwithRequest(ctx context.Context, requestInstance
 	return nil
 }
 
-// deleteAllCustomResource remove custom resource base on OperandConfig and CSV alm-examples
-func (r *Reconciler) deleteAllCustomResource(ctx context.Context, csv *olmv1alpha1.ClusterServiceVersion, requestInstance *operatorv1alpha1.OperandRequest, csc *operatorv1alpha1.OperandConfig, operandName, namespace string) error {
+// OperandInstanceName returns the name of the custom resource of an operand in the OperandRequest
+func OperandInstanceName(requestName string, operand operatorv1alpha1.Operand, index int) string {
+	if operand.InstanceName != "" {
+		return operand.InstanceName
+	}
+	crInfo := sha256.Sum256([]byte(operand.APIVersion + operand.Kind + strconv.Itoa(index)))
+	return requestName + "-" + hex.EncodeToString(crInfo[:7])
+}
 
+// deleteRequestCustomResources deletes the custom resources the OperandRequest created for the operand,
+// from the kind and the spec of its operand in its namespace
+func (r *Reconciler) deleteRequestCustomResources(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, operandName string) error {
 	customeResourceMap := make(map[string]operatorv1alpha1.OperandCRMember)
 	for _, member := range requestInstance.Status.Members {
 		if len(member.OperandCRList) != 0 {
@@ -444,7 +733,7 @@ func (r *Reconciler) deleteAllCustomResource(ctx context.Context, csv *olmv1alph
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			if err := r.deleteCustomResource(ctx, crShouldBeDeleted, requestInstance.Namespace); err != nil {
+			if err := r.deleteCustomResource(ctx, requestInstance, crShouldBeDeleted, requestInstance.Namespace); err != nil {
 				r.Mutex.Lock()
 				defer r.Mutex.Unlock()
 				merr.Add(err)
@@ -458,13 +747,33 @@ func (r *Reconciler) deleteAllCustomResource(ctx context.Context, csv *olmv1alph
 	if len(merr.Errors) != 0 {
 		return merr
 	}
+	return nil
+}
+
+// deleteAllCustomResource remove custom resource base on OperandConfig and CSV alm-examples,
+// after the pre-delete hooks of the service are done
+func (r *Reconciler) deleteAllCustomResource(ctx context.Context, csv *olmv1alpha1.ClusterServiceVersion, requestInstance *operatorv1alpha1.OperandRequest, csc *operatorv1alpha1.OperandConfig, operandName, namespace string) error {
+	log := logging.FromContext(ctx)
+
+	// The pre-delete hooks of the service drain it before any of its custom resources is deleted
+	if service := csc.GetService(operandName); service != nil && service.PreDelete != nil {
+		if err := r.runPreDeleteHooks(ctx, csv, requestInstance, service, namespace); err != nil {
+			return err
+		}
+	}
+
+	if err := r.deleteRequestCustomResources(ctx, requestInstance, operandName); err != nil {
+		return err
+	}
 
 	service := csc.GetService(operandName)
 	if service == nil {
 		return nil
 	}
 	almExamples := csv.GetAnnotations()["alm-examples"]
-	klog.V(2).Info("Delete all the custom resource from Subscription ", service.Name)
+	log.V(logging.LevelChange).Info("Delete all the custom resources of the service", "service", service.Name)
+	merr := &util.MultiErr{}
+	var wg sync.WaitGroup
 
 	// Create a slice for crTemplates
 	var almExamplesRaw []interface{}
@@ -499,14 +808,15 @@ func (r *Reconciler) deleteAllCustomResource(ctx context.Context, csv *olmv1alph
 					continue
 				}
 				if apierrors.IsNotFound(err) {
-					klog.V(2).Info("Finish Deleting the CR: " + kind)
+					log.V(logging.LevelChange).Info("Finish deleting the custom resource", "kind", kind, "name", name)
+
 					continue
 				}
 				if r.CheckLabel(crTemplate, map[string]string{constant.OpreqLabel: "true"}) {
 					wg.Add(1)
 					go func() {
 						defer wg.Done()
-						if err := r.deleteCustomResource(ctx, crTemplate, namespace); err != nil {
+						if err := r.deleteCustomResource(ctx, requestInstance, crTemplate, namespace); err != nil {
 							r.Mutex.Lock()
 							defer r.Mutex.Unlock()
 							merr.Add(err)
@@ -523,17 +833,20 @@ func (r *Reconciler) deleteAllCustomResource(ctx context.Context, csv *olmv1alph
 		return merr
 	}
 
+	if service.PostInstall != nil && service.PostInstall.Job != nil {
+		return r.deletePostInstallJobs(ctx, service.Name, namespace, "")
+	}
 	return nil
 }
 
-func (r *Reconciler) compareConfigandExample(ctx context.Context, crTemplate unstructured.Unstructured, service *operatorv1alpha1.ConfigService, namespace string) error {
+func (r *Reconciler) compareConfigandExample(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, crTemplate unstructured.Unstructured, service *operatorv1alpha1.ConfigService, namespace string) error {
 	kind := crTemplate.GetKind()
 
 	for crdName, crdConfig := range service.Spec {
 		// Compare the name of OperandConfig and CRD name
 		if strings.EqualFold(kind, crdName) {
-			klog.V(3).Info("Found OperandConfig spec for custom resource: " + kind)
-			err := r.createCustomResource(ctx, crTemplate, namespace, crdName, crdConfig.Raw)
+			logging.FromContext(ctx).V(logging.LevelDebug).Info("Found OperandConfig spec for custom resource", "kind", kind)
+			err := r.createCustomResource(ctx, requestInstance, crTemplate, namespace, crdName, crdConfig.Raw, service.Backup, service.Name, provenance.SourceOperandConfig, nil)
 			if err != nil {
 				return errors.Wrapf(err, "failed to create custom resource -- Kind: %s", kind)
 			}
@@ -542,31 +855,203 @@ func (r *Reconciler) compareConfigandExample(ctx context.Context, crTemplate uns
 	return nil
 }
 
-func (r *Reconciler) createCustomResource(ctx context.Context, crTemplate unstructured.Unstructured, namespace, crName string, crConfig []byte) error {
+func (r *Reconciler) createCustomResource(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, crTemplate unstructured.Unstructured, namespace, crName string, crConfig []byte, backupPolicy *operatorv1alpha1.BackupPolicy, operandName, configSource string, patches []operatorv1alpha1.PatchOperation) error {
+	cr := RenderCustomResource(requestInstance, crTemplate, namespace, crConfig, backupPolicy)
+	merged, _ := cr.Object["spec"].(map[string]interface{})
+	if err := PatchCustomResource(&cr, patches); err != nil {
+		return err
+	}
+	AnnotateProvenance(&cr, crTemplate, crConfig, configSource, merged)
+
+	logging.Render(logging.FromContext(ctx), "Rendered the custom resource", cr.Object, "kind", cr.GetKind(), "name", namespace+"/"+cr.GetName())
+	r.recordEffectiveConfig(ctx, requestInstance, operandName, cr, cr.Object["spec"])
+	if err := r.checkRenderedSize(ctx, cr); err != nil {
+		return err
+	}
+	if err := r.validateRenderedSchema(ctx, requestInstance, operandName, cr); err != nil {
+		return err
+	}
+
+	// Create the CR
+	if err := r.applyCustomResource(ctx, &cr); err != nil {
+		return errors.Wrap(err, "failed to create custom resource")
+	}
+	r.Recorder.Eventf(requestInstance, corev1.EventTypeNormal, constant.EventReasonOperandCreated, "Created the custom resource %s %s/%s", cr.GetKind(), namespace, cr.GetName())
+	r.watcher.watch(ctx, cr.GroupVersionKind(), cr.GetNamespace())
 
+	logging.FromContext(ctx).V(logging.LevelChange).Info("Finish creating the custom resource", "kind", cr.GetKind(), "name", namespace+"/"+cr.GetName())
+
+	return nil
+}
+
+// RenderCustomResource returns the custom resource ODLM applies for the OperandRequest: the configuration
+// merged into the spec of the template, with the labels and the annotations of ODLM
+func RenderCustomResource(requestInstance *operatorv1alpha1.OperandRequest, crTemplate unstructured.Unstructured, namespace string, crConfig []byte, backupPolicy *operatorv1alpha1.BackupPolicy) unstructured.Unstructured {
 	//Convert CR template spec to string
 	specJSONString, _ := json.Marshal(crTemplate.Object["spec"])
 
 	// Merge CR template spec and OperandConfig spec
-	mergedCR := util.MergeCR(specJSONString, crConfig)
+	mergedCR := odlmutil.MergeCR(specJSONString, crConfig)
+
+	cr := appliedCustomResource(crTemplate, namespace, mergedCR)
+	labels := cr.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[constant.OpreqLabel] = "true"
+	for k, v := range requestLabel(requestInstance) {
+		labels[k] = v
+	}
+	for k, v := range backup.Labels(backupPolicy) {
+		labels[k] = v
+	}
+	cr.SetLabels(labels)
+
+	annotations := cr.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	for k, v := range backup.Annotations(backupPolicy) {
+		annotations[k] = v
+	}
+	annotations[constant.LastAppliedConfigAnnotation] = lastAppliedConfig(crConfig)
+	for k, v := range requestAnnotation(requestInstance) {
+		annotations[k] = v
+	}
+	cr.SetAnnotations(annotations)
+	return cr
+}
+
+// PatchCustomResource applies the JSON6902 patches of the operand to the spec of the rendered custom resource,
+// and records their hash in its annotations
+func PatchCustomResource(cr *unstructured.Unstructured, patches []operatorv1alpha1.PatchOperation) error {
+	if len(patches) == 0 {
+		return nil
+	}
+	spec, _ := cr.Object["spec"].(map[string]interface{})
+	patched, err := patch.Apply(spec, patches)
+	if err != nil {
+		return errors.Wrapf(err, "failed to patch the custom resource %s %s/%s", cr.GetKind(), cr.GetNamespace(), cr.GetName())
+	}
+	cr.Object["spec"] = patched
+	annotations := cr.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[constant.PatchesAnnotation] = patch.Hash(patches)
+	cr.SetAnnotations(annotations)
+	return nil
+}
+
+// AnnotateProvenance records in the annotations of the rendered custom resource where the top-level fields of its spec
+// come from: the spec of the template, the configuration of the configSource, or the patches changing the merged spec
+func AnnotateProvenance(cr *unstructured.Unstructured, crTemplate unstructured.Unstructured, crConfig []byte, configSource string, merged map[string]interface{}) {
+	defaults, _ := json.Marshal(crTemplate.Object["spec"])
+	spec, _ := cr.Object["spec"].(map[string]interface{})
+	value := provenance.Annotation(provenance.Fields(provenance.Layers{Defaults: defaults, Config: crConfig, ConfigSource: configSource, Merged: merged}, spec))
+	if value == "" {
+		return
+	}
+	annotations := cr.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[constant.ProvenanceAnnotation] = value
+	cr.SetAnnotations(annotations)
+}
 
-	crTemplate.Object["spec"] = mergedCR
-	crTemplate.SetNamespace(namespace)
+// appliedCustomResource returns the configuration of a custom resource server-side applied by ODLM.
+// It only holds the fields ODLM manages, so the fields set by the users and the other controllers are kept.
+func appliedCustomResource(crTemplate unstructured.Unstructured, namespace string, spec map[string]interface{}) unstructured.Unstructured {
+	var cr unstructured.Unstructured
+	cr.SetGroupVersionKind(crTemplate.GroupVersionKind())
+	cr.SetName(crTemplate.GetName())
+	cr.SetNamespace(namespace)
+	cr.SetLabels(crTemplate.GetLabels())
+	cr.SetAnnotations(crTemplate.GetAnnotations())
+	cr.Object["spec"] = spec
+	return cr
+}
+
+// applyCustomResource server-side applies a custom resource with the field manager of ODLM.
+// ODLM takes over the fields it applies, and the fields it stops applying are removed unless others own them too.
+func (r *Reconciler) applyCustomResource(ctx context.Context, cr *unstructured.Unstructured) error {
+	return r.Patch(ctx, cr, client.Apply, client.ForceOwnership, client.FieldOwner(constant.FieldManager))
+}
+
+// protectedPaths returns the paths of the spec fields ODLM never touches in a partially managed custom resource,
+// relative to the spec. The paths outside of the spec are skipped.
+func protectedPaths(cr unstructured.Unstructured) []string {
+	annotations := cr.GetAnnotations()
+	if annotations[constant.ManageAnnotation] != constant.ManagePartial {
+		return nil
+	}
+	var paths []string
+	for _, path := range strings.Split(annotations[constant.ProtectedPathsAnnotation], ",") {
+		path = strings.TrimPrefix(strings.TrimSpace(path), ".")
+		if !strings.HasPrefix(path, "spec.") || len(path) == len("spec.") {
+			continue
+		}
+		paths = append(paths, strings.TrimPrefix(path, "spec."))
+	}
+	return paths
+}
+
+// prunePatch returns the JSON merge patch removing the fields at the dot-separated paths of a custom resource spec
+func prunePatch(paths []string) ([]byte, error) {
+	spec := make(map[string]interface{})
+	for _, path := range paths {
+		keys := strings.Split(path, ".")
+		parent := spec
+		for _, key := range keys[:len(keys)-1] {
+			nested, ok := parent[key].(map[string]interface{})
+			if !ok {
+				nested = make(map[string]interface{})
+				parent[key] = nested
+			}
+			parent = nested
+		}
+		parent[keys[len(keys)-1]] = nil
+	}
+	return json.Marshal(map[string]interface{}{"spec": spec})
+}
 
-	r.EnsureLabel(crTemplate, map[string]string{constant.OpreqLabel: "true"})
+// specHash returns the short hash of a custom resource spec recorded in the history of the OperandRequest
+func specHash(spec interface{}) string {
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return ""
+	}
+	hashed := sha256.Sum256(raw)
+	return hex.EncodeToString(hashed[:7])
+}
 
-	// Creat the CR
-	crerr := r.Create(ctx, &crTemplate)
-	if crerr != nil && !apierrors.IsAlreadyExists(crerr) {
-		return errors.Wrap(crerr, "failed to create custom resource")
+// lastAppliedConfig returns the configuration recorded in the last applied annotation of a custom resource
+func lastAppliedConfig(crConfig []byte) string {
+	if len(crConfig) == 0 {
+		return "{}"
 	}
+	return string(crConfig)
+}
 
-	klog.V(2).Info("Finish creating the Custom Resource: ", crName)
+// checkRenderedSize checks the size of a rendered resource before it is written to etcd.
+// It warns when the resource is approaching the size limit and fails when the resource exceeds it,
+// instead of letting the API server reject the request with an opaque error.
+func (r *Reconciler) checkRenderedSize(ctx context.Context, obj unstructured.Unstructured) error {
+	name := fmt.Sprintf("rendered %s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	size, warning, err := util.CheckSizeBudget(name, obj.Object, constant.RenderedSpecSizeWarningThreshold, constant.RenderedSpecSizeLimit)
+	if err != nil {
+		return errors.Wrap(err, "reduce the size of the configuration in the OperandConfig or OperandRequest")
+	}
+	if warning {
+		logging.FromContext(ctx).Info("Rendered resource is approaching the size limit", "resource", name, "size", size, "limit", constant.RenderedSpecSizeLimit)
 
+	}
 	return nil
 }
 
-func (r *Reconciler) existingCustomResource(ctx context.Context, existingCR unstructured.Unstructured, specFromALM map[string]interface{}, service *operatorv1alpha1.ConfigService, namespace string) error {
+func (r *Reconciler) existingCustomResource(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, existingCR unstructured.Unstructured, specFromALM map[string]interface{}, service *operatorv1alpha1.ConfigService, namespace string, registryKey types.NamespacedName) error {
+	log := logging.FromContext(ctx)
 	kind := existingCR.GetKind()
 
 	var found bool
@@ -574,15 +1059,15 @@ func (r *Reconciler) existingCustomResource(ctx context.Context, existingCR unst
 		// Compare the name of OperandConfig and CRD name
 		if strings.EqualFold(kind, crName) {
 			found = true
-			klog.V(3).Info("Found OperandConfig spec for custom resource: " + kind)
-			err := r.updateCustomResource(ctx, existingCR, namespace, crName, crdConfig.Raw, specFromALM)
+			log.V(logging.LevelDebug).Info("Found OperandConfig spec for custom resource", "kind", kind)
+			err := r.updateCustomResource(ctx, requestInstance, existingCR, namespace, crName, crdConfig.Raw, specFromALM, service.IsPruneEnabled(), service.GetRemediation(), service.Backup, registryKey, service.Name, provenance.SourceOperandConfig, nil)
 			if err != nil {
 				return errors.Wrap(err, "failed to update custom resource")
 			}
 		}
 	}
 	if !found {
-		err := r.deleteCustomResource(ctx, existingCR, namespace)
+		err := r.deleteCustomResource(ctx, requestInstance, existingCR, namespace)
 		if err != nil {
 			return err
 		}
@@ -590,11 +1075,13 @@ func (r *Reconciler) existingCustomResource(ctx context.Context, existingCR unst
 	return nil
 }
 
-func (r *Reconciler) updateCustomResource(ctx context.Context, existingCR unstructured.Unstructured, namespace, crName string, crConfig []byte, configFromALM map[string]interface{}) error {
+func (r *Reconciler) updateCustomResource(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, existingCR unstructured.Unstructured, namespace, crName string, crConfig []byte, configFromALM map[string]interface{}, prune bool, remediation string, backupPolicy *operatorv1alpha1.BackupPolicy, registryKey types.NamespacedName, operatorName, configSource string, patches []operatorv1alpha1.PatchOperation) error {
+	log := logging.FromContext(ctx)
 
 	kind := existingCR.GetKind()
 	apiversion := existingCR.GetAPIVersion()
 	name := existingCR.GetName()
+	r.watcher.watch(ctx, existingCR.GroupVersionKind(), existingCR.GetNamespace())
 
 	// Update the CR
 	err := wait.PollImmediate(constant.DefaultCRFetchPeriod, constant.DefaultCRFetchTimeout, func() (bool, error) {
@@ -621,42 +1108,141 @@ func (r *Reconciler) updateCustomResource(ctx context.Context, existingCR unstru
 
 		configFromALMRaw, err := json.Marshal(configFromALM)
 		if err != nil {
-			klog.Error(err)
+			log.Error(err, "Failed to marshal the custom resource spec", "kind", kind, "name", namespace+"/"+name)
 			return false, err
 		}
 
 		existingCRRaw, err := json.Marshal(existingCR.Object["spec"])
 		if err != nil {
-			klog.Error(err)
+			log.Error(err, "Failed to marshal the custom resource spec", "kind", kind, "name", namespace+"/"+name)
 			return false, err
 		}
 
-		// Merge spec from ALM example and existing CR
-		updatedExistingCR := util.MergeCR(configFromALMRaw, existingCRRaw)
+		// The fields removed from the configuration since it was last applied are removed by the server-side apply,
+		// keep them in the applied configuration when the prune is disabled
+		lastApplied := existingCR.GetAnnotations()[constant.LastAppliedConfigAnnotation]
+		appliedConfig := crConfig
+		if !prune && lastApplied != "" {
+			appliedConfig, err = json.Marshal(odlmutil.RetainCR(existingCRRaw, []byte(lastApplied), crConfig))
+			if err != nil {
+				log.Error(err, "Failed to marshal the custom resource spec", "kind", kind, "name", namespace+"/"+name)
+				return false, err
+			}
+		}
+
+		// The protected fields of a partially managed CR are neither applied, recorded nor reported as drifted
+		paths := protectedPaths(existingCR)
+		if len(paths) != 0 {
+			appliedConfig, err = json.Marshal(odlmutil.StripCR(appliedConfig, paths))
+			if err != nil {
+				log.Error(err, "Failed to marshal the custom resource spec", "kind", kind, "name", namespace+"/"+name)
+				return false, err
+			}
+			configFromALMRaw, err = json.Marshal(odlmutil.StripCR(configFromALMRaw, paths))
+			if err != nil {
+				log.Error(err, "Failed to marshal the custom resource spec", "kind", kind, "name", namespace+"/"+name)
+				return false, err
+			}
+		}
+
+		// Compare the existing CR with the configuration last applied to it to find the changes made by others,
+		// they are kept unless the remediation policy enforces the configuration
+		recordedConfig := appliedConfig
+		var drifted, kept []string
+		if lastApplied != "" {
+			var keptConfig map[string]interface{}
+			drifted, keptConfig = odlmutil.Drift(existingCRRaw, []byte(lastApplied), appliedConfig)
+			if remediation != operatorv1alpha1.RemediationEnforce {
+				kept = drifted
+				appliedConfig, err = json.Marshal(keptConfig)
+				if err != nil {
+					log.Error(err, "Failed to marshal the custom resource spec", "kind", kind, "name", namespace+"/"+name)
+					return false, err
+				}
+			}
+		}
+		r.reportDrift(ctx, requestInstance, existingCR, drifted, remediation)
+		if remediation == operatorv1alpha1.RemediationDetect {
+			r.recordDriftDiff(ctx, requestInstance, operatorName, existingCR, existingCRRaw, recordedConfig, drifted)
+		}
 
-		updatedExistingCRRaw, err := json.Marshal(updatedExistingCR)
+		// Apply the fields of the ALM example not changed in the existing CR, so they aren't overridden
+		unchangedFromALMRaw, err := json.Marshal(odlmutil.UnchangedDefaults(configFromALMRaw, existingCRRaw))
 		if err != nil {
-			klog.Error(err)
+			log.Error(err, "Failed to marshal the custom resource spec", "kind", kind, "name", namespace+"/"+name)
 			return false, err
 		}
 
-		// Merge spec from update existing CR and OperandConfig spec
-		updatedCRSpec := util.MergeCR(updatedExistingCRRaw, crConfig)
+		// Merge spec from ALM example and OperandConfig spec, then apply the JSON6902 patches of the operand
+		mergedCRSpec := odlmutil.MergeCR(unchangedFromALMRaw, appliedConfig)
+		appliedCRSpec, err := patch.Apply(mergedCRSpec, patches)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to patch the custom resource -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
+		}
+		fieldProvenance := provenance.Annotation(provenance.Fields(provenance.Layers{
+			Defaults:     unchangedFromALMRaw,
+			Config:       appliedConfig,
+			ConfigSource: configSource,
+			Kept:         kept,
+			Merged:       mergedCRSpec,
+		}, appliedCRSpec))
+
+		appliedCRSpecRaw, err := json.Marshal(appliedCRSpec)
+		if err != nil {
+			log.Error(err, "Failed to marshal the custom resource spec", "kind", kind, "name", namespace+"/"+name)
+			return false, err
+		}
+		r.recordEffectiveConfig(ctx, requestInstance, operatorName, existingCR, appliedCRSpec)
 
 		CRgeneration := existingCR.GetGeneration()
 
-		if reflect.DeepEqual(existingCR.Object["spec"], updatedCRSpec) {
+		// The fields removed by the patches are only removed from the custom resource by applying it again,
+		// and the custom resources applied before the provenance annotation get it
+		annotated := r.CheckAnnotation(existingCR, requestAnnotation(requestInstance)) && r.CheckLabel(existingCR, requestLabel(requestInstance)) &&
+			r.CheckLabel(existingCR, backup.Labels(backupPolicy)) && r.CheckAnnotation(existingCR, backup.Annotations(backupPolicy)) &&
+			existingCR.GetAnnotations()[constant.PatchesAnnotation] == patch.Hash(patches) &&
+			existingCR.GetAnnotations()[constant.ProvenanceAnnotation] == fieldProvenance
+		if reflect.DeepEqual(odlmutil.MergeCR(existingCRRaw, nil), odlmutil.MergeCR(existingCRRaw, appliedCRSpecRaw)) && lastApplied == lastAppliedConfig(recordedConfig) && annotated {
 			return true, nil
 		}
 
-		klog.V(2).Infof("updating custom resource with apiversion: %s, kind: %s, %s/%s", apiversion, kind, namespace, name)
+		log.V(logging.LevelChange).Info("Updating custom resource", "apiVersion", apiversion, "kind", kind, "name", namespace+"/"+name)
+
+		appliedCR := appliedCustomResource(existingCR, namespace, appliedCRSpec)
+		r.EnsureLabel(appliedCR, map[string]string{constant.OpreqLabel: "true"})
+		r.EnsureLabel(appliedCR, requestLabel(requestInstance))
+		r.EnsureLabel(appliedCR, backup.Labels(backupPolicy))
+		r.EnsureAnnotation(appliedCR, backup.Annotations(backupPolicy))
+		r.EnsureAnnotation(appliedCR, map[string]string{constant.LastAppliedConfigAnnotation: lastAppliedConfig(recordedConfig)})
+		r.EnsureAnnotation(appliedCR, requestAnnotation(requestInstance))
+		if hash := patch.Hash(patches); hash != "" {
+			r.EnsureAnnotation(appliedCR, map[string]string{constant.PatchesAnnotation: hash})
+		}
+		if fieldProvenance != "" {
+			r.EnsureAnnotation(appliedCR, map[string]string{constant.ProvenanceAnnotation: fieldProvenance})
+		}
+
+		logging.Render(log, "Rendered the custom resource", appliedCR.Object, "kind", appliedCR.GetKind(), "name", namespace+"/"+appliedCR.GetName())
+		if err := r.checkRenderedSize(ctx, appliedCR); err != nil {
+			return false, err
+		}
+		if err := r.validateRenderedSchema(ctx, requestInstance, operatorName, appliedCR); err != nil {
+			return false, err
+		}
 
-		existingCR.Object["spec"] = updatedCRSpec
-		err = r.Update(ctx, &existingCR)
+		err = r.applyCustomResource(ctx, &appliedCR)
 
 		if err != nil {
+			if apierrors.IsConflict(err) {
+				metrics.IncMergeConflict(registryKey.String(), operatorName, namespace)
+			}
 			return false, errors.Wrapf(err, "failed to update custom resource -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
 		}
+		metrics.IncOperandCRUpdate(registryKey.String(), operatorName, namespace)
+		r.Recorder.Eventf(requestInstance, corev1.EventTypeNormal, constant.EventReasonOperandMerged, "Merged the configuration of operator %s into the custom resource %s %s/%s", operatorName, kind, namespace, name)
+		if remediation == operatorv1alpha1.RemediationEnforce && len(drifted) != 0 {
+			r.Recorder.Eventf(requestInstance, corev1.EventTypeNormal, constant.EventReasonOperandReverted, "Reverted the changes of the fields %s in the custom resource %s %s/%s", strings.Join(drifted, ", "), kind, namespace, name)
+		}
 
 		UpdatedCR := unstructured.Unstructured{
 			Object: map[string]interface{}{
@@ -675,8 +1261,17 @@ func (r *Reconciler) updateCustomResource(ctx context.Context, existingCR unstru
 
 		}
 
+		// The server-side apply keeps the removed fields which are also owned by others, like the fields updated by
+		// ODLM before it applied the custom resources. Remove the ones still holding the value ODLM last applied.
+		if prune && lastApplied != "" {
+			if err := r.pruneCustomResource(ctx, requestInstance, &UpdatedCR, lastApplied, appliedCRSpecRaw, paths); err != nil {
+				return false, err
+			}
+		}
+
 		if UpdatedCR.GetGeneration() != CRgeneration {
-			klog.V(2).Info("Finish updating the Custom Resource: ", crName)
+			log.V(logging.LevelChange).Info("Finish updating the custom resource", "kind", kind, "name", namespace+"/"+name)
+			requestInstance.AddHistory(operatorv1alpha1.HistoryEntry{Action: operatorv1alpha1.HistorySpecChanged, Operator: operatorName, Resource: kind + " " + namespace + "/" + name, From: specHash(existingCR.Object["spec"]), To: specHash(UpdatedCR.Object["spec"])}, &r.Mutex)
 		}
 
 		return true, nil
@@ -689,7 +1284,54 @@ func (r *Reconciler) updateCustomResource(ctx context.Context, existingCR unstru
 	return nil
 }
 
-func (r *Reconciler) deleteCustomResource(ctx context.Context, existingCR unstructured.Unstructured, namespace string) error {
+// pruneCustomResource removes the fields removed from the configuration since it was last applied to the custom resource,
+// which are left in its spec with their last applied value. The protected fields are never removed.
+func (r *Reconciler) pruneCustomResource(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, cr *unstructured.Unstructured, lastApplied string, appliedCRSpecRaw []byte, protected []string) error {
+	kind, namespace, name := cr.GetKind(), cr.GetNamespace(), cr.GetName()
+	specRaw, err := json.Marshal(cr.Object["spec"])
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal the spec of custom resource -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
+	}
+	lastAppliedRaw := []byte(lastApplied)
+	if len(protected) != 0 {
+		if lastAppliedRaw, err = json.Marshal(odlmutil.StripCR(lastAppliedRaw, protected)); err != nil {
+			return errors.Wrapf(err, "failed to marshal the last applied configuration of custom resource -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
+		}
+	}
+
+	pruned := odlmutil.PrunedPaths(specRaw, lastAppliedRaw, appliedCRSpecRaw)
+	if len(pruned) == 0 {
+		return nil
+	}
+	prunedPatch, err := prunePatch(pruned)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal the prune patch of custom resource -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
+	}
+	logging.FromContext(ctx).V(logging.LevelChange).Info("Pruning the fields removed from the configuration", "kind", kind, "name", namespace+"/"+name, "fields", pruned)
+	if err := r.Patch(ctx, cr, client.RawPatch(types.MergePatchType, prunedPatch), client.FieldOwner(constant.FieldManager)); err != nil {
+		return errors.Wrapf(err, "failed to prune custom resource -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
+	}
+	r.Recorder.Eventf(requestInstance, corev1.EventTypeNormal, constant.EventReasonOperandPruned, "Removed the fields %s from the custom resource %s %s/%s", strings.Join(pruned, ", "), kind, namespace, name)
+	return nil
+}
+
+// reportDrift reports the drifted fields of a custom resource with a Drifted condition and an event
+// when the remediation policy is Detect, and removes the Drifted condition otherwise
+func (r *Reconciler) reportDrift(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, cr unstructured.Unstructured, drifted []string, remediation string) {
+	name := cr.GetKind() + " " + cr.GetNamespace() + "/" + cr.GetName()
+	if remediation != operatorv1alpha1.RemediationDetect || len(drifted) == 0 {
+		requestInstance.RemoveDriftedCondition(name, operatorv1alpha1.ResourceTypeOperand, &r.Mutex)
+		return
+	}
+	logging.FromContext(ctx).Info("Custom resource is drifted from the configuration", "kind", cr.GetKind(), "name", cr.GetNamespace()+"/"+cr.GetName(), "fields", drifted)
+	requestInstance.SetDriftedCondition(name, drifted, operatorv1alpha1.ResourceTypeOperand, corev1.ConditionTrue, &r.Mutex)
+	r.Recorder.Eventf(requestInstance, corev1.EventTypeWarning, constant.EventReasonOperandDrifted, "The fields %s of the custom resource %s were changed and differ from the configuration", strings.Join(drifted, ", "), name)
+}
+
+// deleteCustomResource deletes the custom resource created by ODLM and waits for it to be removed.
+// When the OperandRequest is deleted, it stops waiting after the teardown grace period.
+func (r *Reconciler) deleteCustomResource(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, existingCR unstructured.Unstructured, namespace string) error {
+	log := logging.FromContext(ctx)
 
 	kind := existingCR.GetKind()
 	apiversion := existingCR.GetAPIVersion()
@@ -709,10 +1351,14 @@ func (r *Reconciler) deleteCustomResource(ctx context.Context, existingCR unstru
 		return errors.Wrapf(err, "failed to get custom resource -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
 	}
 	if apierrors.IsNotFound(err) {
-		klog.V(3).Infof("There is no custom resource: %s from custom resource definition: %s", name, kind)
+		log.V(logging.LevelDebug).Info("There is no custom resource", "kind", kind, "name", namespace+"/"+name)
 	} else {
+		if r.isAdopted(crShouldBeDeleted) && !r.CheckLabel(crShouldBeDeleted, map[string]string{constant.NotUninstallLabel: "true"}) {
+			// The adopted custom resource is released as it was before the adoption
+			return r.releaseCustomResource(ctx, requestInstance, crShouldBeDeleted)
+		}
 		if r.CheckLabel(crShouldBeDeleted, map[string]string{constant.OpreqLabel: "true"}) && !r.CheckLabel(crShouldBeDeleted, map[string]string{constant.NotUninstallLabel: "true"}) {
-			klog.V(3).Infof("Deleting custom resource: %s from custom resource definition: %s", name, kind)
+			log.V(logging.LevelChange).Info("Deleting custom resource", "kind", kind, "name", namespace+"/"+name)
 			err := r.Delete(ctx, &crShouldBeDeleted)
 			if err != nil && !apierrors.IsNotFound(err) {
 				return errors.Wrapf(err, "failed to delete custom resource -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
@@ -721,7 +1367,7 @@ func (r *Reconciler) deleteCustomResource(ctx context.Context, existingCR unstru
 				if strings.EqualFold(kind, "OperandRequest") {
 					return true, nil
 				}
-				klog.V(3).Infof("Waiting for CR %s is removed ...", kind)
+				log.V(logging.LevelDebug).Info("Waiting for the custom resource to be removed", "kind", kind, "name", namespace+"/"+name)
 				err := r.Client.Get(ctx, types.NamespacedName{
 					Name:      name,
 					Namespace: namespace,
@@ -732,24 +1378,33 @@ func (r *Reconciler) deleteCustomResource(ctx context.Context, existingCR unstru
 				if err != nil {
 					return false, errors.Wrapf(err, "failed to get custom resource -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
 				}
+				if deadline := requestInstance.GetTeardownDeadline(); !deadline.IsZero() && time.Now().After(deadline) {
+					log.Info("The custom resource is still being deleted after the teardown grace period, stop waiting", "kind", kind, "name", namespace+"/"+name)
+					r.Recorder.Eventf(requestInstance, corev1.EventTypeWarning, constant.EventReasonTeardownTimeout, "The %s %s/%s is still being deleted after the teardown grace period", kind, namespace, name)
+					return true, nil
+				}
 				return false, nil
 			})
 			if err != nil {
 				return errors.Wrapf(err, "failed to delete custom resource -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
 			}
-			klog.V(1).Infof("Finish deleting custom resource -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
+			log.V(logging.LevelFlow).Info("Finish deleting custom resource", "kind", kind, "name", namespace+"/"+name)
 		}
 	}
 	return nil
 }
 
 func (r *Reconciler) checkCustomResource(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) error {
-	klog.V(3).Infof("deleting the custom resource from OperandRequest %s/%s", requestInstance.Namespace, requestInstance.Name)
+	logging.FromContext(ctx).V(logging.LevelDebug).Info("Deleting the custom resources from OperandRequest")
 
 	members := requestInstance.Status.Members
 
 	customeResourceMap := make(map[string]operatorv1alpha1.OperandCRMember)
 	for _, member := range members {
+		// The custom resources of the paused operand are kept until its reconcile is resumed
+		if requestInstance.IsPaused(member.Name) {
+			continue
+		}
 		if len(member.OperandCRList) != 0 {
 			for _, cr := range member.OperandCRList {
 				customeResourceMap[member.Name+"/"+cr.Kind+"/"+cr.Name] = cr
@@ -793,7 +1448,7 @@ func (r *Reconciler) checkCustomResource(ctx context.Context, requestInstance *o
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			if err := r.deleteCustomResource(ctx, crShouldBeDeleted, requestInstance.Namespace); err != nil {
+			if err := r.deleteCustomResource(ctx, requestInstance, crShouldBeDeleted, requestInstance.Namespace); err != nil {
 				r.Mutex.Lock()
 				defer r.Mutex.Unlock()
 				merr.Add(err)
@@ -811,7 +1466,8 @@ func (r *Reconciler) checkCustomResource(ctx context.Context, requestInstance *o
 	return nil
 }
 
-func (r *Reconciler) createK8sResource(ctx context.Context, k8sResTemplate unstructured.Unstructured, k8sResConfig *runtime.RawExtension, newLabels, newAnnotations map[string]string) error {
+func (r *Reconciler) createK8sResource(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, k8sResTemplate unstructured.Unstructured, k8sResConfig *runtime.RawExtension, newLabels, newAnnotations map[string]string) error {
+	log := logging.FromContext(ctx)
 	kind := k8sResTemplate.GetKind()
 	name := k8sResTemplate.GetName()
 	namespace := k8sResTemplate.GetNamespace()
@@ -820,7 +1476,7 @@ func (r *Reconciler) createK8sResource(ctx context.Context, k8sResTemplate unstr
 		k8sResConfigDecoded := make(map[string]interface{})
 		k8sResConfigUnmarshalErr := json.Unmarshal(k8sResConfig.Raw, &k8sResConfigDecoded)
 		if k8sResConfigUnmarshalErr != nil {
-			klog.Errorf("failed to unmarshal k8s Resource Config: %v", k8sResConfigUnmarshalErr)
+			log.Error(k8sResConfigUnmarshalErr, "Failed to unmarshal k8s resource config", "kind", kind, "name", namespace+"/"+name)
 		}
 
 		for k, v := range k8sResConfigDecoded {
@@ -829,25 +1485,38 @@ func (r *Reconciler) createK8sResource(ctx context.Context, k8sResTemplate unstr
 	}
 
 	r.EnsureLabel(k8sResTemplate, map[string]string{constant.OpreqLabel: "true"})
+	r.EnsureLabel(k8sResTemplate, requestLabel(requestInstance))
 	r.EnsureLabel(k8sResTemplate, newLabels)
 	r.EnsureAnnotation(k8sResTemplate, newAnnotations)
+	r.EnsureAnnotation(k8sResTemplate, requestAnnotation(requestInstance))
+
+	logging.Render(log, "Rendered the k8s resource", k8sResTemplate.Object, "kind", kind, "name", namespace+"/"+name)
+	if err := r.checkRenderedSize(ctx, k8sResTemplate); err != nil {
+		return err
+	}
 
 	// Create the k8s resource
 	err := r.Create(ctx, &k8sResTemplate)
 	if err != nil && !apierrors.IsAlreadyExists(err) {
 		return errors.Wrap(err, "failed to create k8s resource")
 	}
+	if err == nil {
+		r.Recorder.Eventf(requestInstance, corev1.EventTypeNormal, constant.EventReasonOperandCreated, "Created the k8s resource %s %s/%s", kind, namespace, name)
+	}
+	r.watcher.watch(ctx, k8sResTemplate.GroupVersionKind(), k8sResTemplate.GetNamespace())
 
-	klog.V(2).Infof("Finish creating the k8s Resource: -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
+	log.V(logging.LevelChange).Info("Finish creating the k8s resource", "kind", kind, "name", namespace+"/"+name)
 
 	return nil
 }
 
-func (r *Reconciler) updateK8sResource(ctx context.Context, existingK8sRes unstructured.Unstructured, k8sResConfig *runtime.RawExtension, newLabels, newAnnotations map[string]string) error {
+func (r *Reconciler) updateK8sResource(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, existingK8sRes unstructured.Unstructured, k8sResConfig *runtime.RawExtension, newLabels, newAnnotations map[string]string) error {
+	log := logging.FromContext(ctx)
 	kind := existingK8sRes.GetKind()
 	apiversion := existingK8sRes.GetAPIVersion()
 	name := existingK8sRes.GetName()
 	namespace := existingK8sRes.GetNamespace()
+	r.watcher.watch(ctx, existingK8sRes.GroupVersionKind(), existingK8sRes.GetNamespace())
 	if kind == "Job" {
 		existingK8sRes := unstructured.Unstructured{
 			Object: map[string]interface{}{
@@ -895,7 +1564,7 @@ func (r *Reconciler) updateK8sResource(ctx context.Context, existingK8sRes unstr
 			if err := r.deleteK8sResource(ctx, existingK8sRes, namespace); err != nil {
 				return errors.Wrap(err, "failed to update k8s resource")
 			}
-			if err := r.createK8sResource(ctx, templatek8sRes, k8sResConfig, newLabels, newAnnotations); err != nil {
+			if err := r.createK8sResource(ctx, requestInstance, templatek8sRes, k8sResConfig, newLabels, newAnnotations); err != nil {
 				return errors.Wrap(err, "failed to update k8s resource")
 			}
 		}
@@ -931,7 +1600,7 @@ func (r *Reconciler) updateK8sResource(ctx context.Context, existingK8sRes unstr
 			k8sResConfigDecoded := make(map[string]interface{})
 			k8sResConfigUnmarshalErr := json.Unmarshal(k8sResConfig.Raw, &k8sResConfigDecoded)
 			if k8sResConfigUnmarshalErr != nil {
-				klog.Errorf("failed to unmarshal k8s Resource Config: %v", k8sResConfigUnmarshalErr)
+				log.Error(k8sResConfigUnmarshalErr, "Failed to unmarshal k8s resource config", "kind", kind, "name", namespace+"/"+name)
 			}
 
 			for k, v := range k8sResConfigDecoded {
@@ -949,13 +1618,22 @@ func (r *Reconciler) updateK8sResource(ctx context.Context, existingK8sRes unstr
 		r.EnsureAnnotation(existingK8sRes, newAnnotations)
 		r.EnsureLabel(existingK8sRes, newLabels)
 
-		klog.V(2).Infof("updating k8s resource with apiversion: %s, kind: %s, %s/%s", apiversion, kind, namespace, name)
+		log.V(logging.LevelChange).Info("Updating k8s resource", "apiVersion", apiversion, "kind", kind, "name", namespace+"/"+name)
 
+		logging.Render(log, "Rendered the k8s resource", existingK8sRes.Object, "kind", kind, "name", namespace+"/"+name)
+		if err := r.checkRenderedSize(ctx, existingK8sRes); err != nil {
+			return false, err
+		}
+
+		resourceVersion := existingK8sRes.GetResourceVersion()
 		err = r.Update(ctx, &existingK8sRes)
 
 		if err != nil {
 			return false, errors.Wrapf(err, "failed to update k8s resource -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
 		}
+		if existingK8sRes.GetResourceVersion() != resourceVersion {
+			r.Recorder.Eventf(requestInstance, corev1.EventTypeNormal, constant.EventReasonOperandUpdated, "Updated the k8s resource %s %s/%s", kind, namespace, name)
+		}
 
 		UpdatedK8sRes := unstructured.Unstructured{
 			Object: map[string]interface{}{
@@ -975,7 +1653,7 @@ func (r *Reconciler) updateK8sResource(ctx context.Context, existingK8sRes unstr
 		}
 
 		if UpdatedK8sRes.GetGeneration() != CRgeneration {
-			klog.V(2).Infof("Finish updating the k8s Resource: -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
+			log.V(logging.LevelChange).Info("Finish updating the k8s resource", "kind", kind, "name", namespace+"/"+name)
 		}
 
 		return true, nil
@@ -989,6 +1667,7 @@ func (r *Reconciler) updateK8sResource(ctx context.Context, existingK8sRes unstr
 }
 
 func (r *Reconciler) deleteK8sResource(ctx context.Context, existingK8sRes unstructured.Unstructured, namespace string) error {
+	log := logging.FromContext(ctx)
 
 	kind := existingK8sRes.GetKind()
 	apiversion := existingK8sRes.GetAPIVersion()
@@ -1008,16 +1687,16 @@ func (r *Reconciler) deleteK8sResource(ctx context.Context, existingK8sRes unstr
 		return errors.Wrapf(err, "failed to get k8s resource -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
 	}
 	if apierrors.IsNotFound(err) {
-		klog.V(3).Infof("There is no k8s resource: %s from kind: %s", name, kind)
+		log.V(logging.LevelDebug).Info("There is no k8s resource", "kind", kind, "name", namespace+"/"+name)
 	} else {
 		if r.CheckLabel(k8sResShouldBeDeleted, map[string]string{constant.OpreqLabel: "true"}) && !r.CheckLabel(k8sResShouldBeDeleted, map[string]string{constant.NotUninstallLabel: "true"}) {
-			klog.V(3).Infof("Deleting k8s resource: %s from kind: %s", name, kind)
+			log.V(logging.LevelChange).Info("Deleting k8s resource", "kind", kind, "name", namespace+"/"+name)
 			err := r.Delete(ctx, &k8sResShouldBeDeleted)
 			if err != nil && !apierrors.IsNotFound(err) {
 				return errors.Wrapf(err, "failed to delete k8s resource -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
 			}
 			err = wait.PollImmediate(constant.DefaultCRDeletePeriod, constant.DefaultCRDeleteTimeout, func() (bool, error) {
-				klog.V(3).Infof("Waiting for k8s resource %s is removed ...", kind)
+				log.V(logging.LevelDebug).Info("Waiting for the k8s resource to be removed", "kind", kind, "name", namespace+"/"+name)
 				err := r.Client.Get(ctx, types.NamespacedName{
 					Name:      name,
 					Namespace: namespace,
@@ -1033,7 +1712,7 @@ func (r *Reconciler) deleteK8sResource(ctx context.Context, existingK8sRes unstr
 			if err != nil {
 				return errors.Wrapf(err, "failed to delete k8s resource -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
 			}
-			klog.V(1).Infof("Finish deleting k8s resource -- Kind: %s, NamespacedName: %s/%s", kind, namespace, name)
+			log.V(logging.LevelFlow).Info("Finish deleting k8s resource", "kind", kind, "name", namespace+"/"+name)
 		}
 	}
 	return nil
@@ -1089,14 +1768,14 @@ func (r *Reconciler) deleteAllK8sResource(ctx context.Context, csc *operatorv1al
 }
 
 func (r *Reconciler) checkResAuth(ctx context.Context, verbs []string, k8sResTemplate unstructured.Unstructured) bool {
+	log := logging.FromContext(ctx)
 	kind := k8sResTemplate.GetKind()
 	apiversion := k8sResTemplate.GetAPIVersion()
 	name := k8sResTemplate.GetName()
 	namespace := k8sResTemplate.GetNamespace()
 
-	dc := discovery.NewDiscoveryClientForConfigOrDie(r.Config)
-	if namespaced, err := util.ResourceNamespaced(dc, apiversion, kind); err != nil {
-		klog.Errorf("Failed to check resource scope for Kind: %s, NamespacedName: %s/%s, %v", kind, namespace, name, err)
+	if namespaced, err := odlmutil.ResourceNamespaced(r.Discovery, apiversion, kind); err != nil {
+		log.Error(err, "Failed to check resource scope", "kind", kind, "name", namespace+"/"+name)
 	} else if !namespaced {
 		namespace = ""
 	}
@@ -1104,7 +1783,7 @@ func (r *Reconciler) checkResAuth(ctx context.Context, verbs []string, k8sResTem
 	gvk := schema.FromAPIVersionAndKind(apiversion, kind)
 	gvr, err := r.ResourceForKind(gvk, namespace)
 	if err != nil {
-		klog.Errorf("Failed to get GroupVersionResource from GroupVersionKind, %v", err)
+		log.Error(err, "Failed to get GroupVersionResource from GroupVersionKind", "kind", kind)
 		return false
 	}
 
@@ -1120,11 +1799,11 @@ func (r *Reconciler) checkResAuth(ctx context.Context, verbs []string, k8sResTem
 			},
 		}
 		if err := r.Create(ctx, sar); err != nil {
-			klog.Errorf("Failed to check operator permission for Kind: %s, NamespacedName: %s/%s, %v", kind, namespace, name, err)
+			log.Error(err, "Failed to check operator permission", "kind", kind, "name", namespace+"/"+name)
 			return false
 		}
 
-		klog.V(2).Infof("Operator %s permission in namespace %s for Kind: %s, Allowed: %t, Denied: %t, Reason: %s", verb, namespace, kind, sar.Status.Allowed, sar.Status.Denied, sar.Status.Reason)
+		log.V(logging.LevelChange).Info("Operator permission", "verb", verb, "namespace", namespace, "kind", kind, "allowed", sar.Status.Allowed, "denied", sar.Status.Denied, "reason", sar.Status.Reason)
 
 		if !sar.Status.Allowed {
 			return false
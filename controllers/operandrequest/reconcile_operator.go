@@ -29,6 +29,7 @@ import (
 	olmv1 "github.com/operator-framework/api/pkg/operators/v1"
 	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -36,16 +37,21 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
-	"k8s.io/klog"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/operatorgroup"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/tracing"
+	odlmutil "github.com/IBM/operand-deployment-lifecycle-manager/pkg/util/v1"
 )
 
 func (r *Reconciler) reconcileOperator(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) error {
-	klog.V(1).Infof("Reconciling Operators for OperandRequest: %s/%s", requestInstance.GetNamespace(), requestInstance.GetName())
+	log := logging.FromContext(ctx)
+	log.V(logging.LevelFlow).Info("Reconciling Operators for OperandRequest")
 
 	// Update request status
 	defer func() {
@@ -53,9 +59,14 @@ func (r *Reconciler) reconcileOperator(ctx context.Context, requestInstance *ope
 		requestInstance.UpdateClusterPhase()
 	}()
 
+	r.setImplicitMembers(ctx, requestInstance)
+
 	for _, req := range requestInstance.Spec.Requests {
 		registryKey := requestInstance.GetRegistryKey(req)
-		registryInstance, err := r.GetOperandRegistry(ctx, registryKey)
+		ctx, log := logging.WithValues(ctx, "registry", registryKey.String())
+		registryCtx, span := tracing.Start(ctx, "ResolveRegistry", attribute.String("registry", registryKey.String()))
+		registryInstance, err := r.GetOperandRegistry(registryCtx, registryKey)
+		tracing.End(span, err)
 		if err != nil {
 			if apierrors.IsNotFound(err) {
 				r.Recorder.Eventf(requestInstance, corev1.EventTypeWarning, "NotFound", "NotFound OperandRegistry NamespacedName %s", registryKey.String())
@@ -63,7 +74,7 @@ func (r *Reconciler) reconcileOperator(ctx context.Context, requestInstance *ope
 			} else {
 				requestInstance.SetNoSuitableRegistryCondition(registryKey.String(), err.Error(), operatorv1alpha1.ResourceTypeOperandRegistry, corev1.ConditionTrue, &r.Mutex)
 			}
-			klog.Errorf("Failed to get suitable OperandRegistry %s: %v", registryKey.String(), err)
+			log.Error(err, "Failed to get suitable OperandRegistry")
 			t := time.Now()
 			formatted := fmt.Sprintf("%d-%02d-%02dT%02d:%02d:%02d",
 				t.Year(), t.Month(), t.Day(),
@@ -81,6 +92,7 @@ func (r *Reconciler) reconcileOperator(ctx context.Context, requestInstance *ope
 			return err
 		}
 		merr := &util.MultiErr{}
+		operands := registryInstance.WithRequiredOperands(req.Operands)
 
 		// Get the chunk size
 		var chunkSize int
@@ -91,19 +103,23 @@ func (r *Reconciler) reconcileOperator(ctx context.Context, requestInstance *ope
 		}
 
 		// reconcile subscription in batch
-		for i := 0; i < len(req.Operands); i += chunkSize {
+		for i := 0; i < len(operands); i += chunkSize {
 			j := i + chunkSize
-			if j > len(req.Operands) {
-				j = len(req.Operands)
+			if j > len(operands) {
+				j = len(operands)
 			}
 			var (
 				wg sync.WaitGroup
 			)
-			for _, operand := range req.Operands[i:j] {
+			for _, operand := range operands[i:j] {
 				wg.Add(1)
 				go func(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, operand operatorv1alpha1.Operand, registryKey types.NamespacedName, mu *sync.Mutex) {
 					defer wg.Done()
-					if err := r.reconcileSubscription(ctx, requestInstance, registryInstance, operand, registryKey, mu); err != nil {
+					ctx, _ = logging.WithValues(ctx, "operator", operand.Name)
+					ctx, span := tracing.Start(ctx, "InstallOperator", attribute.String("operator", operand.Name), attribute.String("registry", registryKey.String()))
+					err := r.reconcileSubscription(ctx, requestInstance, registryInstance, operand, registryKey, mu)
+					tracing.End(span, err)
+					if err != nil {
 						mu.Lock()
 						defer mu.Unlock()
 						merr.Add(err)
@@ -133,37 +149,182 @@ func (r *Reconciler) reconcileOperator(ctx context.Context, requestInstance *ope
 	if err := r.absentOperatorsAndOperands(ctx, requestInstance); err != nil {
 		return err
 	}
-	klog.V(1).Infof("Finished reconciling Operators for OperandRequest: %s/%s", requestInstance.GetNamespace(), requestInstance.GetName())
+	log.V(logging.LevelFlow).Info("Finished reconciling Operators for OperandRequest")
 
 	return nil
 }
 
+// setImplicitMembers records the operands included from the requires of the OperandRegistries in the request status
+func (r *Reconciler) setImplicitMembers(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) {
+	requiredBy := make(map[string][]string)
+	for _, req := range requestInstance.Spec.Requests {
+		registryKey := requestInstance.GetRegistryKey(req)
+		registryInstance, err := r.GetOperandRegistry(ctx, registryKey)
+		if err != nil {
+			// Keep the implicit members until the OperandRegistry is found
+			logging.FromContext(ctx).V(logging.LevelChange).Info("Skip updating the implicit operands of OperandRequest", "registry", registryKey.String(), "reason", err.Error())
+			return
+		}
+		for name, operands := range registryInstance.GetRequiredOperands(req.Operands) {
+			logging.FromContext(ctx).V(logging.LevelChange).Info("Operator is required by the requested operands", "operator", name, "requiredBy", strings.Join(operands, ","), "registry", registryKey.String())
+			requiredBy[name] = append(requiredBy[name], operands...)
+		}
+	}
+	requestInstance.SetImplicitMembers(requiredBy, &r.Mutex)
+}
+
 func (r *Reconciler) reconcileSubscription(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, operand operatorv1alpha1.Operand, registryKey types.NamespacedName, mu sync.Locker) error {
+	log := logging.FromContext(ctx)
+	// Hold the operand until its NotBefore time
+	if requestInstance.IsScheduled(operand, time.Now()) {
+		log.V(logging.LevelChange).Info("Operator is scheduled", "notBefore", requestInstance.GetNotBefore(operand).String())
+		requestInstance.SetMemberStatus(operand.Name, operatorv1alpha1.OperatorScheduled, "", mu)
+		requestInstance.SetScheduledCondition(operand.Name, requestInstance.GetNotBefore(operand), operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu)
+		return nil
+	}
+	if notBefore := requestInstance.GetNotBefore(operand); !notBefore.IsZero() {
+		requestInstance.SetScheduledCondition(operand.Name, notBefore, operatorv1alpha1.ResourceTypeOperator, corev1.ConditionFalse, mu)
+	}
+	// Leave the paused operand as it is, its status is still reported by reconcileOperand
+	if requestInstance.IsPaused(operand.Name) {
+		log.V(logging.LevelFlow).Info("Operator is paused, skip reconciling", "annotation", operatorv1alpha1.PausedOperandsAnnotation)
+		requestInstance.SetPausedCondition(operand.Name, operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu)
+		return nil
+	}
+	requestInstance.RemovePausedCondition(operand.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+
 	// Check the requested Operand if exist in specific OperandRegistry
-	opt := registryInstance.GetOperator(operand.Name)
+	opt, err := r.resolveOperator(ctx, requestInstance, registryInstance, operand.Name)
+	if err != nil {
+		return err
+	}
+	if opt != nil {
+		opt = PinnedOperator(opt, operand)
+	}
 	if opt == nil {
-		klog.V(1).Infof("Operator %s not found in the OperandRegistry %s/%s", operand.Name, registryInstance.Namespace, registryInstance.Name)
+		log.V(logging.LevelFlow).Info("Operator not found in the OperandRegistry")
 		requestInstance.SetNotFoundOperatorFromRegistryCondition(operand.Name, operatorv1alpha1.ResourceTypeSub, corev1.ConditionTrue, mu)
 		requestInstance.SetMemberStatus(operand.Name, operatorv1alpha1.OperatorNotFound, operatorv1alpha1.ServiceNotFound, mu)
 		return nil
 	}
 	if opt.Scope == operatorv1alpha1.ScopePrivate && requestInstance.Namespace != registryInstance.Namespace {
-		klog.Warningf("Operator %s is private. It can't be requested from namespace %s", operand.Name, requestInstance.Namespace)
+		log.Info("Operator is private. It can't be requested from the namespace of the OperandRequest")
+
 		requestInstance.SetOutofScopeCondition(operand.Name, operatorv1alpha1.ResourceTypeSub, corev1.ConditionTrue, mu)
 		return nil
 	}
 
+	// Never install anything in the namespaces opting out of ODLM
+	for _, ns := range []string{r.GetOperatorNamespace(opt.InstallMode, opt.Namespace), opt.Namespace} {
+		excluded, err := r.IsNamespaceExcluded(ctx, ns)
+		if err != nil {
+			return err
+		}
+		if excluded {
+			log.Info("Skip the operator in the excluded namespace", "namespace", ns, "label", constant.ExcludeNamespaceLabel)
+			requestInstance.SetExcludedCondition(operand.Name, ns, operatorv1alpha1.ResourceTypeSub, corev1.ConditionTrue, mu)
+			return nil
+		}
+	}
+	requestInstance.RemoveExcludedCondition(operand.Name, operatorv1alpha1.ResourceTypeSub, mu)
+
+	// Leave the operator as it is while it violates a ClusterOperandPolicy
+	violation, err := r.checkPolicies(ctx, requestInstance, registryInstance, opt)
+	if err != nil {
+		return err
+	}
+	if violation != nil {
+		log.Info("Operator is blocked by the ClusterOperandPolicy", "policy", violation.Policy, "reason", violation.Message)
+		if requestInstance.SetBlockedCondition(operand.Name, violation.Message, operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu) {
+			r.Recorder.Event(requestInstance, corev1.EventTypeWarning, constant.EventReasonBlocked, violation.Message)
+		}
+		return nil
+	}
+	requestInstance.RemoveBlockedCondition(operand.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+
+	// Install the operator after the operators it depends on in the OperandBootstrap
+	message, err := r.checkBootstrap(ctx, requestInstance, registryInstance, registryKey, opt, mu)
+	if err != nil {
+		return err
+	}
+	if message != "" {
+		log.V(logging.LevelChange).Info("Operator is waiting for its dependencies", "reason", message)
+		if !requestInstance.IsWaitingForDependencies(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu) {
+			r.Recorder.Event(requestInstance, corev1.EventTypeNormal, constant.EventReasonWaitingForDependencies, message)
+		}
+		requestInstance.SetWaitingForDependenciesCondition(opt.Name, message, operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu)
+		requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorInstalling, "", mu)
+		return nil
+	}
+	requestInstance.RemoveWaitingForDependenciesCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+
+	installer, err := r.getInstaller(opt)
+	if err != nil {
+		requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorFailed, "", mu)
+		return err
+	}
+	// The PreInstall callbacks are called before the operator is installed for the request
+	if getOperatorPhase(requestInstance, opt.Name, mu) == operatorv1alpha1.OperatorNone {
+		if err := r.notify(ctx, operatorv1alpha1.CallbackPreInstall, requestInstance, registryKey, opt); err != nil {
+			return err
+		}
+		r.forgetPreDelete(requestInstance, opt.Name)
+	}
+	// The operator is referenced in the ledger before it is installed, so a concurrent uninstall keeps it
+	if err := r.bindOperator(ctx, requestInstance, registryInstance, opt.Name); err != nil {
+		return err
+	}
+	return installer.Install(ctx, requestInstance, opt, registryKey, mu)
+}
+
+// installSubscription installs or upgrades the operator with an OLM Subscription
+func (r *Reconciler) installSubscription(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, opt *operatorv1alpha1.Operator, registryKey types.NamespacedName, mu sync.Locker) error {
 	// Check subscription if exist
 	namespace := r.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
 	sub, err := r.GetSubscription(ctx, opt.Name, namespace, opt.PackageName)
 
+	// OLM can't resolve the operator while its CatalogSource is unhealthy
+	catalogUnhealthy, catalogErr := r.checkCatalogSource(ctx, requestInstance, opt, registryKey, mu)
+	if catalogErr != nil {
+		return catalogErr
+	}
+
 	if err != nil {
 		if apierrors.IsNotFound(err) {
-			// Subscription does not exist, create a new one
+			// Wait for the CatalogSource to be ready instead of retrying to subscribe the operator,
+			// the request is reconciled again when the CatalogSource changes
+			if catalogUnhealthy {
+				requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorInstalling, "", mu)
+				return nil
+			}
+			// Don't subscribe to the operator until it is entitled, the request is requeued while it is installing
+			entitled, err := r.checkEntitlement(ctx, requestInstance, opt, registryKey, mu)
+			if err != nil {
+				return err
+			}
+			if !entitled {
+				requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorInstalling, "", mu)
+				return nil
+			}
+			// Subscription does not exist, create a new one when its CatalogSource is not busy
+			reserved, limit, err := r.reserveInstall(ctx, namespace, opt.Name, opt.SourceName, opt.SourceNamespace)
+			if err != nil {
+				return err
+			}
+			if !reserved {
+				requestInstance.SetThrottledCondition(opt.Name, opt.SourceNamespace+"/"+opt.SourceName, limit, operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu)
+				requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorInstalling, "", mu)
+				return nil
+			}
+			requestInstance.RemoveThrottledCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
 			if err = r.createSubscription(ctx, requestInstance, opt, registryKey); err != nil {
+				r.releaseInstall(namespace, opt.Name)
+				r.recordOperatorEvent(requestInstance, r.getRegistryForEvent(ctx, registryKey), corev1.EventTypeWarning, constant.EventReasonInstallFailed, "Failed to create the Subscription of operator %s: %v", opt.Name, err)
 				requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorFailed, "", mu)
 				return err
 			}
+			r.recordOperatorEvent(requestInstance, r.getRegistryForEvent(ctx, registryKey), corev1.EventTypeNormal, constant.EventReasonSubscribed, "Subscribed operator %s to channel %s of package %s", opt.Name, opt.Channel, opt.PackageName)
+			requestInstance.AddHistory(operatorv1alpha1.HistoryEntry{Action: operatorv1alpha1.HistorySubscriptionCreated, Operator: opt.Name, Resource: "Subscription " + namespace + "/" + opt.Name, To: opt.Channel, Message: "Subscribed to package " + opt.PackageName}, mu)
 			requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorInstalling, "", mu)
 			return nil
 		}
@@ -172,84 +333,113 @@ func (r *Reconciler) reconcileSubscription(ctx context.Context, requestInstance
 
 	// Subscription existing and managed by OperandRequest controller
 	if _, ok := sub.Labels[constant.OpreqLabel]; ok {
+		// Recreate the OperatorGroup deleted by someone else
+		if namespace != constant.ClusterOperatorNamespace {
+			if err := r.ensureOperatorGroup(ctx, requestInstance, opt.Name, generateOperatorGroup(opt.Namespace, opt.TargetNamespaces), mu); err != nil {
+				return err
+			}
+		}
 		originalSub := sub.DeepCopy()
 		sub.Spec.CatalogSource = opt.SourceName
 		sub.Spec.CatalogSourceNamespace = opt.SourceNamespace
 		sub.Spec.Package = opt.PackageName
 		// For singleton services, compare the channel version to install the latest one
 		if CheckSingletonServices(opt.Name) {
-			v1IsLarger, convertErr := util.CompareChannelVersion(opt.Channel, originalSub.Spec.Channel)
+			scheme, err := deploy.GetChannelScheme(opt)
+			if err != nil {
+				return err
+			}
+			c, convertErr := scheme.Compare(opt.Channel, originalSub.Spec.Channel)
 			if convertErr != nil {
 				return convertErr
 			}
-			if v1IsLarger {
+			if c > 0 {
 				sub.Spec.Channel = opt.Channel
 			}
 		} else {
 			sub.Spec.Channel = opt.Channel
 		}
-		if opt.InstallPlanApproval != "" && sub.Spec.InstallPlanApproval != opt.InstallPlanApproval {
-			sub.Spec.InstallPlanApproval = opt.InstallPlanApproval
-		}
-		if opt.SubscriptionConfig != nil {
-			sub.Spec.Config = opt.SubscriptionConfig
-		}
 		// add annotations to existing Subscriptions for upgrade case
 		if sub.Annotations == nil {
 			sub.Annotations = make(map[string]string)
 		}
+		if err := r.reconcileReplacesChain(ctx, requestInstance, opt, sub, originalSub.Spec.Channel, mu); err != nil {
+			return err
+		}
+		if opt.InstallPlanApproval != "" && sub.Spec.InstallPlanApproval != opt.InstallPlanApproval {
+			sub.Spec.InstallPlanApproval = opt.InstallPlanApproval
+		}
+		applySubscriptionConfig(sub, opt.SubscriptionConfig)
+		if err := r.reconcileUpgradePath(ctx, requestInstance, opt, sub, mu); err != nil {
+			return err
+		}
 		sub.Annotations[registryKey.Namespace+"."+registryKey.Name+"/registry"] = "true"
 		sub.Annotations[registryKey.Namespace+"."+registryKey.Name+"/config"] = "true"
 		sub.Annotations[requestInstance.Namespace+"."+requestInstance.Name+"/request"] = "true"
+		if sub.Spec.Channel == originalSub.Spec.Channel {
+			requestInstance.RemoveIncompatibleConsumersCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+			requestInstance.RemoveUpgradeBlockedCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+		}
 		if compareSub(sub, originalSub) {
+			if sub.Spec.Channel != originalSub.Spec.Channel {
+				blocked, err := r.checkUpgradePath(ctx, requestInstance, opt, sub, originalSub.Spec.Channel, mu)
+				if err != nil {
+					return err
+				}
+				if blocked {
+					return nil
+				}
+				blocked, err = r.checkConsumers(ctx, requestInstance, opt, registryKey, sub, originalSub.Spec.Channel, mu)
+				if err != nil {
+					return err
+				}
+				if blocked {
+					return nil
+				}
+				if err := r.validateSkipRange(ctx, requestInstance, opt, sub); err != nil {
+					return err
+				}
+			}
 			if err = r.updateSubscription(ctx, requestInstance, sub); err != nil {
 				requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorFailed, "", mu)
 				return err
 			}
+			if sub.Spec.Channel != originalSub.Spec.Channel {
+				r.recordOperatorEvent(requestInstance, r.getRegistryForEvent(ctx, registryKey), corev1.EventTypeNormal, constant.EventReasonUpgrading, "Switched operator %s from channel %s to channel %s", opt.Name, originalSub.Spec.Channel, sub.Spec.Channel)
+				requestInstance.AddHistory(operatorv1alpha1.HistoryEntry{Action: operatorv1alpha1.HistoryChannelChanged, Operator: opt.Name, Resource: "Subscription " + sub.Namespace + "/" + sub.Name, From: originalSub.Spec.Channel, To: sub.Spec.Channel}, mu)
+			}
 			requestInstance.SetMemberStatus(opt.Name, operatorv1alpha1.OperatorUpdating, "", mu)
 		}
 	} else {
 		// Subscription existing and not managed by OperandRequest controller
-		klog.V(1).Infof("Subscription %s in namespace %s isn't created by ODLM. Ignore update/delete it.", sub.Name, sub.Namespace)
+		logging.FromContext(ctx).V(logging.LevelFlow).Info("Subscription isn't created by ODLM, ignore updating or deleting it", "subscription", sub.Namespace+"/"+sub.Name)
 	}
 	return nil
 }
 
 func (r *Reconciler) createSubscription(ctx context.Context, cr *operatorv1alpha1.OperandRequest, opt *operatorv1alpha1.Operator, key types.NamespacedName) error {
+	log := logging.FromContext(ctx)
 	namespace := r.GetOperatorNamespace(opt.InstallMode, opt.Namespace)
-	klog.V(3).Info("Subscription Namespace: ", namespace)
+	log.V(logging.LevelDebug).Info("Subscription namespace", "namespace", namespace)
 
-	co := r.generateClusterObjects(opt, key, types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name})
+	co := r.generateClusterObjects(ctx, opt, key, types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name})
 
 	// Create required namespace
-	ns := co.namespace
-	klog.V(3).Info("Creating the Namespace for Operator: " + opt.Name)
-
-	// Compare namespace and create namespace
-	oprNs := util.GetOperatorNamespace()
-	if ns.Name != oprNs && ns.Name != constant.ClusterOperatorNamespace {
-		if err := r.Create(ctx, ns); err != nil && !apierrors.IsAlreadyExists(err) {
-			klog.Warningf("failed to create the namespace %s, please make sure it exists: %s", ns.Name, err)
+	if co.namespace.Name != constant.ClusterOperatorNamespace {
+		if err := r.ensureOperatorNamespaces(ctx, opt, co.namespace.Name, key); err != nil {
+			return err
 		}
 	}
 
 	if namespace != constant.ClusterOperatorNamespace {
 		// Create required operatorgroup
-		existOG := &olmv1.OperatorGroupList{}
-		if err := r.Client.List(ctx, existOG, &client.ListOptions{Namespace: co.operatorGroup.Namespace}); err != nil {
+		if err := r.ensureOperatorGroup(ctx, cr, opt.Name, co.operatorGroup, &r.Mutex); err != nil {
 			return err
 		}
-		if len(existOG.Items) == 0 {
-			og := co.operatorGroup
-			klog.V(3).Info("Creating the OperatorGroup for Subscription: " + opt.Name)
-			if err := r.Create(ctx, og); err != nil && !apierrors.IsAlreadyExists(err) {
-				return err
-			}
-		}
 	}
 
 	// Create subscription
-	klog.V(2).Info("Creating the Subscription: " + opt.Name)
+	log.V(logging.LevelChange).Info("Creating the Subscription", "subscription", co.subscription.Namespace+"/"+co.subscription.Name)
 	if co.subscription.Spec.CatalogSource == "" || co.subscription.Spec.CatalogSourceNamespace == "" {
 		return fmt.Errorf("failed to find catalogsource for subscription %s/%s", co.subscription.Namespace, co.subscription.Name)
 	}
@@ -265,8 +455,7 @@ func (r *Reconciler) createSubscription(ctx context.Context, cr *operatorv1alpha
 }
 
 func (r *Reconciler) updateSubscription(ctx context.Context, cr *operatorv1alpha1.OperandRequest, sub *olmv1alpha1.Subscription) error {
-
-	klog.V(2).Infof("Updating Subscription %s/%s ...", sub.Namespace, sub.Name)
+	logging.FromContext(ctx).V(logging.LevelChange).Info("Updating the Subscription", "subscription", sub.Namespace+"/"+sub.Name)
 	cr.SetUpdatingCondition(sub.Name, operatorv1alpha1.ResourceTypeSub, corev1.ConditionTrue, &r.Mutex)
 
 	if err := r.Update(ctx, sub); err != nil {
@@ -279,30 +468,82 @@ func (r *Reconciler) updateSubscription(ctx context.Context, cr *operatorv1alpha
 func (r *Reconciler) deleteSubscription(ctx context.Context, operandName string, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, configInstance *operatorv1alpha1.OperandConfig) error {
 	op := registryInstance.GetOperator(operandName)
 	if op == nil {
-		klog.Warningf("Operand %s not found", operandName)
+		logging.FromContext(ctx).Info("Operand not found in the OperandRegistry", "operand", operandName)
 		return nil
 	}
 
+	installer, err := r.getInstaller(op)
+	if err != nil {
+		return err
+	}
+	// The operator and the shared custom resources of the OperandConfig are kept while another OperandRequest
+	// references the operator in the ledger, the custom resources created by this OperandRequest are deleted anyway
+	others, err := r.unbindOperator(ctx, requestInstance, registryInstance, op.Name)
+	if err != nil {
+		return err
+	}
+	if len(others) != 0 {
+		logging.FromContext(ctx).V(logging.LevelFlow).Info("Operator is still referenced by other OperandRequests, skip the uninstall", "operator", op.Name, "requests", others)
+		return r.deleteRequestCustomResources(ctx, requestInstance, op.Name)
+	}
+	if err := r.notifyPreDelete(ctx, requestInstance, types.NamespacedName{Namespace: registryInstance.Namespace, Name: registryInstance.Name}, op); err != nil {
+		return err
+	}
+	if err := installer.Uninstall(ctx, requestInstance, registryInstance, configInstance, op); err != nil {
+		return err
+	}
+	// The previous version of a coexisting operator is uninstalled with the new one
+	if err := r.releasePreviousVersion(ctx, requestInstance, registryInstance, op); err != nil {
+		return err
+	}
+	if requestInstance.DeletionTimestamp == nil {
+		r.Recorder.Eventf(requestInstance, corev1.EventTypeNormal, constant.EventReasonOperandRemoved, "Pruned the operator %s and its operands, removed from the OperandRequest", op.Name)
+		requestInstance.AddHistory(operatorv1alpha1.HistoryEntry{Action: operatorv1alpha1.HistoryOperandRemoved, Operator: op.Name, Message: "Uninstalled the operator and its operands, removed from the OperandRequest"}, &r.Mutex)
+	}
+	return nil
+}
+
+// orphanOperand leaves the operator and the operands removed from the OperandRequest running,
+// it only releases the reference of the OperandRequest to the operator in the ledger
+func (r *Reconciler) orphanOperand(ctx context.Context, operandName string, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry) error {
+	op := registryInstance.GetOperator(operandName)
+	if op == nil {
+		logging.FromContext(ctx).Info("Operand not found in the OperandRegistry", "operand", operandName)
+		return nil
+	}
+	if _, err := r.unbindOperator(ctx, requestInstance, registryInstance, op.Name); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).V(logging.LevelChange).Info("Orphaned the operator removed from the OperandRequest", "operator", op.Name)
+	r.Recorder.Eventf(requestInstance, corev1.EventTypeNormal, constant.EventReasonOperandOrphaned, "Left the operator %s and its operands running, removed from the OperandRequest with the Orphan prune policy", op.Name)
+	requestInstance.AddHistory(operatorv1alpha1.HistoryEntry{Action: operatorv1alpha1.HistoryOperandOrphaned, Operator: op.Name, Message: "Left the operator and its operands running, removed from the OperandRequest"}, &r.Mutex)
+	return nil
+}
+
+// uninstallSubscription deletes the OLM Subscription and ClusterServiceVersion of the operator, with its operands
+func (r *Reconciler) uninstallSubscription(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, configInstance *operatorv1alpha1.OperandConfig, op *operatorv1alpha1.Operator) error {
+	log := logging.FromContext(ctx)
+	operandName := op.Name
 	namespace := r.GetOperatorNamespace(op.InstallMode, op.Namespace)
 	sub, err := r.GetSubscription(ctx, operandName, namespace, op.PackageName)
 	originalsub := sub.DeepCopy()
 	if apierrors.IsNotFound(err) {
-		klog.V(3).Infof("There is no Subscription %s or %s in the namespace %s", operandName, op.PackageName, namespace)
+		log.V(logging.LevelDebug).Info("There is no Subscription for the operator", "name", operandName, "package", op.PackageName, "namespace", namespace)
 		return nil
 	} else if err != nil {
-		klog.Errorf("Failed to get Subscription %s or %s in the namespace %s", operandName, op.PackageName, namespace)
+		log.Error(err, "Failed to get the Subscription", "name", operandName, "package", op.PackageName, "namespace", namespace)
 		return err
 	}
 
 	if sub.Labels == nil {
 		// Subscription existing and not managed by OperandRequest controller
-		klog.V(2).Infof("Subscription %s in the namespace %s isn't created by ODLM", sub.Name, sub.Namespace)
+		log.V(logging.LevelChange).Info("Subscription isn't created by ODLM", "subscription", sub.Namespace+"/"+sub.Name)
 		return nil
 	}
 
 	if _, ok := sub.Labels[constant.OpreqLabel]; !ok {
 		// Subscription existing and not managed by OperandRequest controller
-		klog.V(2).Infof("Subscription %s in the namespace %s isn't created by ODLM", sub.Name, sub.Namespace)
+		log.V(logging.LevelChange).Info("Subscription isn't created by ODLM", "subscription", sub.Namespace+"/"+sub.Name)
 		return nil
 	}
 
@@ -311,6 +552,7 @@ func (r *Reconciler) deleteSubscription(ctx context.Context, operandName string,
 	regNs := registryInstance.ObjectMeta.Namespace
 	delete(sub.Annotations, regNs+"."+regName+"/registry")
 	delete(sub.Annotations, regNs+"."+regName+"/config")
+	delete(sub.Annotations, pinAnnotationKey(types.NamespacedName{Namespace: requestInstance.Namespace, Name: requestInstance.Name}))
 	reg, _ := regexp.Compile(`^(.*)\.(.*)\/registry`)
 	annoSlice := make([]string, 0)
 	for anno := range sub.Annotations {
@@ -324,7 +566,7 @@ func (r *Reconciler) deleteSubscription(ctx context.Context, operandName string,
 			requestInstance.SetUpdatingCondition(sub.Name, operatorv1alpha1.ResourceTypeSub, corev1.ConditionFalse, &r.Mutex)
 			return err
 		}
-		klog.V(1).Infof("Did not delete Subscription %s/%s which is requested by OperandRequest with different OperandRegistry", sub.Namespace, sub.Name)
+		log.V(logging.LevelFlow).Info("Did not delete the Subscription which is requested by OperandRequest with different OperandRegistry", "subscription", sub.Namespace+"/"+sub.Name)
 		return nil
 	}
 
@@ -335,42 +577,42 @@ func (r *Reconciler) deleteSubscription(ctx context.Context, operandName string,
 	}
 
 	if csv != nil {
-		klog.V(2).Infof("Deleting all the Custom Resources for CSV, Namespace: %s, Name: %s", csv.Namespace, csv.Name)
+		log.V(logging.LevelChange).Info("Deleting all the Custom Resources for CSV", "csv", csv.Namespace+"/"+csv.Name)
 		if err := r.deleteAllCustomResource(ctx, csv, requestInstance, configInstance, operandName, op.Namespace); err != nil {
 			return err
 		}
-		klog.V(2).Infof("Deleting all the k8s Resources for CSV, Namespace: %s, Name: %s", csv.Namespace, csv.Name)
+		log.V(logging.LevelChange).Info("Deleting all the k8s Resources for CSV", "csv", csv.Namespace+"/"+csv.Name)
 		if err := r.deleteAllK8sResource(ctx, configInstance, operandName, op.Namespace); err != nil {
 			return err
 		}
 		if r.checkUninstallLabel(ctx, op.Name, namespace) {
-			klog.V(1).Infof("Operator %s has label operator.ibm.com/opreq-do-not-uninstall. Skip the uninstall", op.Name)
+			log.V(logging.LevelFlow).Info("Operator has label operator.ibm.com/opreq-do-not-uninstall, skip the uninstall", "operator", op.Name)
 			return nil
 		}
 
-		klog.V(3).Info("Set Deleting Condition in the operandRequest")
+		log.V(logging.LevelDebug).Info("Set Deleting Condition in the OperandRequest")
 		requestInstance.SetDeletingCondition(csv.Name, operatorv1alpha1.ResourceTypeCsv, corev1.ConditionTrue, &r.Mutex)
 
-		klog.V(1).Infof("Deleting the ClusterServiceVersion, Namespace: %s, Name: %s", csv.Namespace, csv.Name)
+		log.V(logging.LevelChange).Info("Deleting the ClusterServiceVersion", "csv", csv.Namespace+"/"+csv.Name)
 		if err := r.Delete(ctx, csv); err != nil {
 			requestInstance.SetDeletingCondition(csv.Name, operatorv1alpha1.ResourceTypeCsv, corev1.ConditionFalse, &r.Mutex)
 			return errors.Wrap(err, "failed to delete the ClusterServiceVersion")
 		}
 	}
 
-	klog.V(2).Infof("Deleting the Subscription, Namespace: %s, Name: %s", namespace, op.Name)
+	log.V(logging.LevelChange).Info("Deleting the Subscription", "subscription", namespace+"/"+op.Name)
 	requestInstance.SetDeletingCondition(op.Name, operatorv1alpha1.ResourceTypeSub, corev1.ConditionTrue, &r.Mutex)
 
 	if err := r.Delete(ctx, sub); err != nil {
 		if apierrors.IsNotFound(err) {
-			klog.Warningf("Subscription %s was not found in namespace %s", op.Name, namespace)
+			log.Info("Subscription was not found", "subscription", namespace+"/"+op.Name)
 		} else {
 			requestInstance.SetDeletingCondition(op.Name, operatorv1alpha1.ResourceTypeSub, corev1.ConditionFalse, &r.Mutex)
 			return errors.Wrap(err, "failed to delete subscription")
 		}
 	}
 
-	klog.V(1).Infof("Subscription %s/%s is deleted", namespace, op.Name)
+	log.V(logging.LevelFlow).Info("Subscription is deleted", "subscription", namespace+"/"+op.Name)
 	return nil
 }
 
@@ -379,10 +621,14 @@ func (r *Reconciler) absentOperatorsAndOperands(ctx context.Context, requestInst
 	if err != nil {
 		return err
 	}
-
-	var (
-		wg sync.WaitGroup
-	)
+	// The operands with the Retain deletion policy are kept with the deleted request
+	if requestInstance.DeletionTimestamp != nil {
+		retainedOperands, err := r.getRetainedOperands(ctx, requestInstance)
+		if err != nil {
+			return err
+		}
+		needDeletedOperands = needDeletedOperands.Difference(retainedOperands)
+	}
 
 	for _, req := range requestInstance.Spec.Requests {
 		registryKey := requestInstance.GetRegistryKey(req)
@@ -399,24 +645,33 @@ func (r *Reconciler) absentOperatorsAndOperands(ctx context.Context, requestInst
 			}
 		}
 		merr := &util.MultiErr{}
+		group := util.NewGroup(0)
 		remainingOp := needDeletedOperands.Clone()
 		for o := range needDeletedOperands.Iter() {
 			var (
 				o = o
 			)
-			wg.Add(1)
-			go func() {
-				defer wg.Done()
-				if err := r.deleteSubscription(ctx, fmt.Sprintf("%v", o), requestInstance, registryInstance, configInstance); err != nil {
-					r.Mutex.Lock()
-					defer r.Mutex.Unlock()
+			// The paused operand is kept until its reconcile is resumed, unless the request is deleted
+			if requestInstance.DeletionTimestamp == nil && requestInstance.IsPaused(fmt.Sprintf("%v", o)) {
+				remainingOp.Remove(o)
+				continue
+			}
+			// The operand removed from the request with the Orphan prune policy is left running
+			if requestInstance.DeletionTimestamp == nil && requestInstance.GetPrunePolicy() == operatorv1alpha1.PrunePolicyOrphan {
+				if err := r.orphanOperand(ctx, fmt.Sprintf("%v", o), requestInstance, registryInstance); err != nil {
 					merr.Add(err)
 				}
 				remainingOp.Remove(o)
-			}()
+				continue
+			}
+			group.Go(func() error {
+				defer remainingOp.Remove(o)
+				return r.deleteSubscription(ctx, fmt.Sprintf("%v", o), requestInstance, registryInstance, configInstance)
+			})
 		}
-		timeout := util.WaitTimeout(&wg, constant.DefaultSubDeleteTimeout)
-		if timeout {
+		deleteErr, timedOut := group.Wait(constant.DefaultSubDeleteTimeout)
+		merr.Errors = append(merr.Errors, deleteErr.Errors...)
+		if timedOut {
 			merr.Add(fmt.Errorf("timeout for cleaning up subscription %v", strings.Trim(fmt.Sprint(remainingOp.ToSlice()), "[]")))
 		}
 		if len(merr.Errors) != 0 {
@@ -428,7 +683,7 @@ func (r *Reconciler) absentOperatorsAndOperands(ctx context.Context, requestInst
 }
 
 func (r *Reconciler) getNeedDeletedOperands(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) (gset.Set, error) {
-	klog.V(3).Info("Getting the operater need to be delete")
+	logging.FromContext(ctx).V(logging.LevelDebug).Info("Getting the operators that need to be deleted")
 	deployedOperands := gset.NewSet()
 	for _, req := range requestInstance.Status.Members {
 		deployedOperands.Add(req.Name)
@@ -444,7 +699,13 @@ func (r *Reconciler) getNeedDeletedOperands(ctx context.Context, requestInstance
 }
 
 func (r *Reconciler) getCurrentOperands(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) (gset.Set, error) {
-	klog.V(3).Info("Getting the operaters have been deployed")
+	logging.FromContext(ctx).V(logging.LevelDebug).Info("Getting the operators that have been deployed")
+	return r.getRequestedOperands(ctx, requestInstance, false)
+}
+
+// getRequestedOperands returns the operands requested in the registries of the OperandRequest,
+// by all the OperandRequests which are not deleted, or only by the other ones when excludeSelf is true.
+func (r *Reconciler) getRequestedOperands(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, excludeSelf bool) (gset.Set, error) {
 	deployedOperands := gset.NewSet()
 	for _, req := range requestInstance.Spec.Requests {
 		registryKey := requestInstance.GetRegistryKey(req)
@@ -452,16 +713,27 @@ func (r *Reconciler) getCurrentOperands(ctx context.Context, requestInstance *op
 		if err != nil {
 			return nil, err
 		}
+		// The operators required by the requested operands are deployed too
+		registryInstance, err := r.GetOperandRegistry(ctx, registryKey)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			registryInstance = &operatorv1alpha1.OperandRegistry{}
+		}
 		for _, item := range requestList {
 			if !item.DeletionTimestamp.IsZero() {
 				continue
 			}
+			if excludeSelf && item.Namespace == requestInstance.Namespace && item.Name == requestInstance.Name {
+				continue
+			}
 			for _, existingReq := range item.Spec.Requests {
 				existRegistryKey := item.GetRegistryKey(existingReq)
 				if registryKey.String() != existRegistryKey.String() {
 					continue
 				}
-				for _, operand := range existingReq.Operands {
+				for _, operand := range registryInstance.WithRequiredOperands(existingReq.Operands) {
 					deployedOperands.Add(operand.Name)
 				}
 			}
@@ -470,19 +742,15 @@ func (r *Reconciler) getCurrentOperands(ctx context.Context, requestInstance *op
 	return deployedOperands, nil
 }
 
-func (r *Reconciler) generateClusterObjects(o *operatorv1alpha1.Operator, registryKey, requestKey types.NamespacedName) *clusterObjects {
-	klog.V(3).Info("Generating Cluster Objects")
+func (r *Reconciler) generateClusterObjects(ctx context.Context, o *operatorv1alpha1.Operator, registryKey, requestKey types.NamespacedName) *clusterObjects {
+	log := logging.FromContext(ctx)
+	log.V(logging.LevelDebug).Info("Generating Cluster Objects")
 	co := &clusterObjects{}
 	labels := map[string]string{
 		constant.OpreqLabel: "true",
 	}
-	annotations := map[string]string{
-		registryKey.Namespace + "." + registryKey.Name + "/registry": "true",
-		registryKey.Namespace + "." + registryKey.Name + "/config":   "true",
-		requestKey.Namespace + "." + requestKey.Name + "/request":    "true",
-	}
 
-	klog.V(3).Info("Generating Namespace: ", o.Namespace)
+	log.V(logging.LevelDebug).Info("Generating Namespace", "namespace", o.Namespace)
 	// Namespace Object
 	co.namespace = &corev1.Namespace{
 		TypeMeta: metav1.TypeMeta{
@@ -496,7 +764,7 @@ func (r *Reconciler) generateClusterObjects(o *operatorv1alpha1.Operator, regist
 	}
 
 	// Operator Group Object
-	klog.V(3).Info("Generating Operator Group in the Namespace: ", o.Namespace, " with target namespace: ", o.TargetNamespaces)
+	log.V(logging.LevelDebug).Info("Generating OperatorGroup", "namespace", o.Namespace, "targetNamespaces", o.TargetNamespaces)
 	og := generateOperatorGroup(o.Namespace, o.TargetNamespaces)
 	co.operatorGroup = og
 
@@ -504,11 +772,31 @@ func (r *Reconciler) generateClusterObjects(o *operatorv1alpha1.Operator, regist
 	namespace := r.GetOperatorNamespace(o.InstallMode, o.Namespace)
 
 	// Subscription Object
+	sub := NewSubscription(o, namespace, registryKey, requestKey)
+	log.V(logging.LevelDebug).Info("Generating Subscription", "subscription", namespace+"/"+o.Name)
+	co.subscription = sub
+	return co
+}
+
+// NewSubscription returns the Subscription ODLM creates in the namespace for the operator of the OperandRegistry
+// requested by the OperandRequest
+func NewSubscription(o *operatorv1alpha1.Operator, namespace string, registryKey, requestKey types.NamespacedName) *olmv1alpha1.Subscription {
+	annotations := map[string]string{
+		registryKey.Namespace + "." + registryKey.Name + "/registry": "true",
+		registryKey.Namespace + "." + registryKey.Name + "/config":   "true",
+		requestKey.Namespace + "." + requestKey.Name + "/request":    "true",
+	}
+	if o.PinnedVersion != "" {
+		annotations[pinAnnotationKey(requestKey)] = o.PinnedVersion
+	}
+
 	sub := &olmv1alpha1.Subscription{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        o.Name,
-			Namespace:   namespace,
-			Labels:      labels,
+			Name:      o.Name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				constant.OpreqLabel: "true",
+			},
 			Annotations: annotations,
 		},
 		Spec: &olmv1alpha1.SubscriptionSpec{
@@ -518,13 +806,75 @@ func (r *Reconciler) generateClusterObjects(o *operatorv1alpha1.Operator, regist
 			CatalogSourceNamespace: o.SourceNamespace,
 			InstallPlanApproval:    o.InstallPlanApproval,
 			StartingCSV:            o.StartingCSV,
-			Config:                 o.SubscriptionConfig,
 		},
 	}
+	applySubscriptionConfig(sub, o.SubscriptionConfig)
+	// ODLM approves the InstallPlans of the operators denying the skip range or pinned to a version
+	if o.DeniesSkipRange() || o.PinnedVersion != "" {
+		sub.Spec.InstallPlanApproval = olmv1alpha1.ApprovalManual
+	}
+	if sub.Spec.StartingCSV == "" {
+		sub.Spec.StartingCSV = odlmutil.PinnedCSV(o.PinnedVersion)
+	}
 	sub.SetGroupVersionKind(schema.GroupVersionKind{Group: olmv1alpha1.SchemeGroupVersion.Group, Kind: "Subscription", Version: olmv1alpha1.SchemeGroupVersion.Version})
-	klog.V(3).Info("Generating Subscription:  ", o.Name, " in the Namespace: ", namespace)
-	co.subscription = sub
-	return co
+	return sub
+}
+
+// ensureOperatorGroup creates the OperatorGroup when the namespace has none, and updates the one created by ODLM
+// to target the namespaces of the operator. The OperatorGroups OLM can't install the operator with are repaired
+// when the repair is enabled, otherwise they are reported in the OperatorGroupConflict condition.
+func (r *Reconciler) ensureOperatorGroup(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, name string, og *olmv1.OperatorGroup, mu sync.Locker) error {
+	log := logging.FromContext(ctx)
+	existOG := &olmv1.OperatorGroupList{}
+	if err := r.Client.List(ctx, existOG, &client.ListOptions{Namespace: og.Namespace}); err != nil {
+		return err
+	}
+	resolution := operatorgroup.Resolve(og, existOG.Items, util.GetRepairOperatorGroups())
+	if resolution.Create != nil {
+		log.V(logging.LevelChange).Info("Creating the OperatorGroup for Subscription", "operatorGroup", og.Namespace+"/"+og.Name)
+		if err := r.Create(ctx, resolution.Create); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	if resolution.Update != nil {
+		log.Info("Updating the target namespaces of the OperatorGroup", "operatorGroup", resolution.Update.Namespace+"/"+resolution.Update.Name, "targetNamespaces", resolution.Update.Spec.TargetNamespaces)
+		if err := r.Update(ctx, resolution.Update); err != nil {
+			return errors.Wrapf(err, "failed to update the OperatorGroup %s/%s", resolution.Update.Namespace, resolution.Update.Name)
+		}
+	}
+	if len(resolution.Delete) != 0 {
+		var deleted []string
+		for i := range resolution.Delete {
+			log.Info("Deleting the OperatorGroup merged into another one", "operatorGroup", resolution.Delete[i].Namespace+"/"+resolution.Delete[i].Name)
+			if err := r.Delete(ctx, &resolution.Delete[i]); client.IgnoreNotFound(err) != nil {
+				return errors.Wrapf(err, "failed to delete the OperatorGroup %s/%s", resolution.Delete[i].Namespace, resolution.Delete[i].Name)
+			}
+			deleted = append(deleted, resolution.Delete[i].Name)
+		}
+		r.Recorder.Eventf(requestInstance, corev1.EventTypeNormal, constant.EventReasonOperatorGroupRepaired, "Merged the OperatorGroups of namespace %s for operator %s, deleted %s", og.Namespace, name, strings.Join(deleted, ","))
+	}
+
+	if resolution.Conflict == nil {
+		requestInstance.RemoveOperatorGroupConflictCondition(name, operatorv1alpha1.ResourceTypeOperator, mu)
+		return nil
+	}
+	if !hasOperatorGroupConflict(requestInstance, name, mu) {
+		r.Recorder.Eventf(requestInstance, corev1.EventTypeWarning, constant.EventReasonOperatorGroupConflict, "Operator %s can't be installed: %s", name, resolution.Conflict.Message)
+	}
+	requestInstance.SetOperatorGroupConflictCondition(name, resolution.Conflict.Message, operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu)
+	return nil
+}
+
+// hasOperatorGroupConflict returns true if the OperatorGroups of the operator are already reported in conflict
+func hasOperatorGroupConflict(requestInstance *operatorv1alpha1.OperandRequest, name string, mu sync.Locker) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, c := range requestInstance.Status.Conditions {
+		if c.Type == operatorv1alpha1.ConditionOperatorGroupConflict && c.Reason == string(operatorv1alpha1.ResourceTypeOperator)+" "+name+" OperatorGroup conflicts" {
+			return true
+		}
+	}
+	return false
 }
 
 func generateOperatorGroup(namespace string, targetNamespaces []string) *olmv1.OperatorGroup {
@@ -554,7 +904,8 @@ func (r *Reconciler) checkUninstallLabel(ctx context.Context, name, namespace st
 	sub := &olmv1alpha1.Subscription{}
 	subKey := types.NamespacedName{Name: name, Namespace: namespace}
 	if err := r.Client.Get(ctx, subKey, sub); err != nil {
-		klog.Warning("failed to get subscription: ", err)
+		logging.FromContext(ctx).Info("Failed to get the Subscription", "subscription", subKey.String(), "error", err.Error())
+
 		return true
 	}
 	subLabels := sub.GetLabels()
@@ -0,0 +1,113 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/ownership"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// requestIndex is the index of the watched objects by the namespace/name of the OperandRequests tracking them
+const requestIndex = "operandrequest"
+
+// indexRequests returns the namespace/name of the OperandRequests tracking the object
+func indexRequests(obj interface{}) ([]string, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, nil
+	}
+	return ownership.IndexValues(accessor), nil
+}
+
+// trackedObject is an object of a watched kind tracked for an OperandRequest
+type trackedObject struct {
+	gvk schema.GroupVersionKind
+	obj metav1.Object
+}
+
+// tracked returns the objects of the watched kinds tracked for the OperandRequest, in all the namespaces,
+// sorted by kind, namespace and name
+func (w *resourceWatcher) tracked(requestKey types.NamespacedName) []trackedObject {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var objects []trackedObject
+	for gvk, informers := range w.informers {
+		for _, informer := range informers {
+			items, err := informer.GetIndexer().ByIndex(requestIndex, requestKey.String())
+			if err != nil {
+				continue
+			}
+			for _, item := range items {
+				if obj, err := meta.Accessor(item); err == nil {
+					objects = append(objects, trackedObject{gvk: gvk, obj: obj})
+				}
+			}
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].gvk.Kind != objects[j].gvk.Kind {
+			return objects[i].gvk.Kind < objects[j].gvk.Kind
+		}
+		if objects[i].obj.GetNamespace() != objects[j].obj.GetNamespace() {
+			return objects[i].obj.GetNamespace() < objects[j].obj.GetNamespace()
+		}
+		return objects[i].obj.GetName() < objects[j].obj.GetName()
+	})
+	return objects
+}
+
+// untrackObjects removes the label and the annotation of the deleted OperandRequest from the objects still
+// tracking it once its operands are torn down, the objects kept for the other OperandRequests don't map back
+// to it anymore. The objects left without any OperandRequest are logged, ODLM doesn't delete them here.
+func (r *Reconciler) untrackObjects(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) error {
+	log := logging.FromContext(ctx)
+	requestKey := types.NamespacedName{Namespace: requestInstance.Namespace, Name: requestInstance.Name}
+	for _, tracked := range r.watcher.tracked(requestKey) {
+		name := tracked.obj.GetNamespace() + "/" + tracked.obj.GetName()
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(tracked.gvk)
+		obj.SetNamespace(tracked.obj.GetNamespace())
+		obj.SetName(tracked.obj.GetName())
+		if err := r.Client.Patch(ctx, obj, ownership.Untrack(requestKey)); err != nil {
+			if client.IgnoreNotFound(err) == nil {
+				continue
+			}
+			return errors.Wrapf(err, "failed to untrack the %s %s from the OperandRequest %s", tracked.gvk.Kind, name, requestKey)
+		}
+		log.V(logging.LevelDebug).Info("Untracked the object from the OperandRequest", "kind", tracked.gvk.Kind, "name", name)
+		if len(ownership.Requests(obj)) == 0 && obj.GetLabels()[constant.OpreqLabel] == "true" {
+			log.V(logging.LevelChange).Info("The object created by ODLM is left without any OperandRequest", "kind", tracked.gvk.Kind, "name", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	odlmutil "github.com/IBM/operand-deployment-lifecycle-manager/pkg/util/v1"
+)
+
+// pinAnnotationSuffix is the suffix of the annotations recording the versions pinned by the OperandRequests in a Subscription
+const pinAnnotationSuffix = "/pinned-version"
+
+// pinAnnotationKey returns the key of the annotation recording the version pinned by the OperandRequest in a Subscription
+func pinAnnotationKey(requestKey types.NamespacedName) string {
+	return requestKey.Namespace + "." + requestKey.Name + pinAnnotationSuffix
+}
+
+// PinnedOperator returns the operator with the version pinned by the operand, which overrides the OperandRegistry
+func PinnedOperator(opt *operatorv1alpha1.Operator, operand operatorv1alpha1.Operand) *operatorv1alpha1.Operator {
+	if operand.PinnedVersion == "" || operand.PinnedVersion == opt.PinnedVersion {
+		return opt
+	}
+	pinned := opt.DeepCopy()
+	pinned.PinnedVersion = operand.PinnedVersion
+	return pinned
+}
+
+// getPins returns the versions pinned by the OperandRequests of the Subscription
+func getPins(sub *olmv1alpha1.Subscription) []string {
+	var pins []string
+	for key, pin := range sub.Annotations {
+		if strings.HasSuffix(key, pinAnnotationSuffix) && pin != "" && !util.Contains(pins, pin) {
+			pins = append(pins, pin)
+		}
+	}
+	sort.Strings(pins)
+	return pins
+}
+
+// reconcilePin records the version pinned by the request in the Subscription. While any OperandRequest pins the
+// operator, the InstallPlans are approved by ODLM, only when they install the pinned version. It returns true
+// when the operator is pinned, its InstallPlans are then left to the pin.
+func (r *Reconciler) reconcilePin(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, opt *operatorv1alpha1.Operator, sub *olmv1alpha1.Subscription, mu sync.Locker) (bool, error) {
+	log := logging.FromContext(ctx)
+
+	key := pinAnnotationKey(types.NamespacedName{Namespace: requestInstance.Namespace, Name: requestInstance.Name})
+	if opt.PinnedVersion != "" {
+		sub.Annotations[key] = opt.PinnedVersion
+	} else {
+		delete(sub.Annotations, key)
+	}
+	pins := getPins(sub)
+	if len(pins) == 0 {
+		// The InstallPlan refused while the operator was pinned is approved once the pin is lifted,
+		// unless its InstallPlans are approved manually or by the skip range check
+		if hasCondition(requestInstance, operatorv1alpha1.ConditionVersionPinned, mu) && !opt.DeniesSkipRange() && opt.InstallPlanApproval != olmv1alpha1.ApprovalManual {
+			if err := r.approvePendingInstallPlan(ctx, sub); err != nil {
+				return false, err
+			}
+		}
+		requestInstance.RemoveVersionPinnedCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+		return false, nil
+	}
+	// OLM would install the upgrades past the pin before ODLM checks them
+	sub.Spec.InstallPlanApproval = olmv1alpha1.ApprovalManual
+
+	ip, err := r.getPendingInstallPlan(ctx, sub)
+	if err != nil || ip == nil {
+		if ip == nil {
+			requestInstance.RemoveVersionPinnedCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+		}
+		return true, err
+	}
+	csv := sub.Status.CurrentCSV
+	for _, pin := range pins {
+		if !odlmutil.MatchesPin(pin, csv) {
+			pinned := strings.Join(pins, ", ")
+			log.V(logging.LevelFlow).Info("InstallPlan isn't approved past the pinned version", "installPlan", ip.Namespace+"/"+ip.Name, "csv", csv, "pinnedVersion", pinned)
+			if requestInstance.SetVersionPinnedCondition(opt.Name, ip.Name, csv, pinned, operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu) {
+				r.Recorder.Eventf(requestInstance, corev1.EventTypeWarning, constant.EventReasonVersionPinned, "InstallPlan %s installs %s of operator %s past the pinned version %s", ip.Name, csv, opt.Name, pinned)
+			}
+			return true, nil
+		}
+	}
+	requestInstance.RemoveVersionPinnedCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+	// The InstallPlans of the operators approved manually in the OperandRegistry are left to the user
+	if opt.InstallPlanApproval == olmv1alpha1.ApprovalManual {
+		return true, nil
+	}
+	return true, r.approveInstallPlan(ctx, ip, csv)
+}
+
+// approvePendingInstallPlan approves the InstallPlan of the Subscription waiting for an approval, if any
+func (r *Reconciler) approvePendingInstallPlan(ctx context.Context, sub *olmv1alpha1.Subscription) error {
+	ip, err := r.getPendingInstallPlan(ctx, sub)
+	if err != nil || ip == nil {
+		return err
+	}
+	return r.approveInstallPlan(ctx, ip, sub.Status.CurrentCSV)
+}
+
+// approveInstallPlan approves the InstallPlan installing the CSV
+func (r *Reconciler) approveInstallPlan(ctx context.Context, ip *olmv1alpha1.InstallPlan, csv string) error {
+	logging.FromContext(ctx).Info("Approving the InstallPlan", "installPlan", ip.Namespace+"/"+ip.Name, "csv", csv)
+	originalIP := ip.DeepCopy()
+	ip.Spec.Approved = true
+	if err := r.Patch(ctx, ip, client.MergeFrom(originalIP)); err != nil {
+		return errors.Wrapf(err, "failed to approve the InstallPlan %s/%s", ip.Namespace, ip.Name)
+	}
+	return nil
+}
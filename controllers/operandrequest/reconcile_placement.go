@@ -0,0 +1,237 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/placement"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// reconcilePlacement distributes the operators and operands of the request to the managed clusters,
+// and aggregates their status in the managed clusters back to the request
+func (r *Reconciler) reconcilePlacement(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) error {
+	log := logging.FromContext(ctx)
+	log.V(logging.LevelFlow).Info("Reconciling Placement")
+	requestKey := types.NamespacedName{Namespace: requestInstance.Namespace, Name: requestInstance.Name}
+
+	opp := requestInstance.Spec.Placement
+	if opp.GetType() != operatorv1alpha1.PlacementTypeManifestWork {
+		return fmt.Errorf("the placement type %s of the OperandRequest %s is not supported", opp.GetType(), requestKey.String())
+	}
+
+	manifests, err := r.placementManifests(ctx, requestInstance)
+	if err != nil {
+		return err
+	}
+
+	var managedClusters []unstructured.Unstructured
+	if opp.ClusterSelector != nil {
+		clusterList := &unstructured.UnstructuredList{}
+		clusterList.SetGroupVersionKind(placement.ManagedClusterGVK.GroupVersion().WithKind(placement.ManagedClusterGVK.Kind + "List"))
+		if err := r.Reader.List(ctx, clusterList); err != nil {
+			return errors.Wrap(err, "failed to list the managed clusters, please make sure Open Cluster Management is installed")
+		}
+		managedClusters = clusterList.Items
+	}
+	clusters, err := placement.SelectClusters(opp, managedClusters)
+	if err != nil {
+		return errors.Wrapf(err, "failed to select the managed clusters for the OperandRequest %s", requestKey.String())
+	}
+
+	existingWorks, err := r.listPlacementWorks(ctx, requestKey)
+	if err != nil {
+		return err
+	}
+
+	merr := &util.MultiErr{}
+	var statuses []operatorv1alpha1.ClusterPlacementStatus
+	for _, cluster := range clusters {
+		work := placement.NewManifestWork(cluster, requestKey, manifests)
+		existing, ok := existingWorks[cluster]
+		delete(existingWorks, cluster)
+
+		if !ok {
+			log.V(logging.LevelChange).Info("Creating the ManifestWork", "manifestWork", cluster+"/"+work.GetName())
+			if err := r.Create(ctx, work); err != nil && !apierrors.IsAlreadyExists(err) {
+				merr.Add(errors.Wrapf(err, "failed to create the ManifestWork %s/%s", cluster, work.GetName()))
+				statuses = append(statuses, operatorv1alpha1.ClusterPlacementStatus{Cluster: cluster, Phase: operatorv1alpha1.ClusterPhaseFailed, Message: err.Error()})
+				continue
+			}
+			statuses = append(statuses, placement.Status(work))
+			continue
+		}
+
+		if !equality.Semantic.DeepEqual(existing.Object["spec"], work.Object["spec"]) {
+			log.V(logging.LevelChange).Info("Updating the ManifestWork", "manifestWork", cluster+"/"+work.GetName())
+			existing.Object["spec"] = work.Object["spec"]
+			if err := r.Update(ctx, &existing); err != nil {
+				merr.Add(errors.Wrapf(err, "failed to update the ManifestWork %s/%s", cluster, work.GetName()))
+				statuses = append(statuses, operatorv1alpha1.ClusterPlacementStatus{Cluster: cluster, Phase: operatorv1alpha1.ClusterPhaseFailed, Message: err.Error()})
+				continue
+			}
+		}
+		statuses = append(statuses, placement.Status(&existing))
+	}
+
+	// Remove the request from the clusters no longer selected
+	for cluster, work := range existingWorks {
+		work := work
+		logging.FromContext(ctx).V(logging.LevelChange).Info("Deleting the ManifestWork", "manifestWork", cluster+"/"+work.GetName())
+		if err := r.Delete(ctx, &work); err != nil && !apierrors.IsNotFound(err) {
+			merr.Add(errors.Wrapf(err, "failed to delete the ManifestWork %s/%s", cluster, work.GetName()))
+		}
+	}
+
+	requestInstance.SetPlacementStatus(statuses)
+
+	if len(merr.Errors) != 0 {
+		return merr
+	}
+	log.V(logging.LevelFlow).Info("Finished reconciling Placement")
+
+	return nil
+}
+
+// placementManifests returns the namespaces, OperatorGroups, Subscriptions and custom resources of the request
+// to be applied in the managed clusters
+func (r *Reconciler) placementManifests(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) ([]map[string]interface{}, error) {
+	requestKey := types.NamespacedName{Namespace: requestInstance.Namespace, Name: requestInstance.Name}
+
+	var objects []runtime.Object
+	for _, req := range requestInstance.Spec.Requests {
+		registryKey := requestInstance.GetRegistryKey(req)
+		registryInstance, err := r.GetOperandRegistry(ctx, registryKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the OperandRegistry %s", registryKey.String())
+		}
+
+		for i, operand := range registryInstance.WithRequiredOperands(req.Operands) {
+			opt := registryInstance.GetOperator(operand.Name)
+			if opt == nil {
+				return nil, fmt.Errorf("cannot find %s in the OperandRegistry %s", operand.Name, registryKey.String())
+			}
+			if opt.GetType() != operatorv1alpha1.OperatorTypeOLM {
+				return nil, fmt.Errorf("the operator %s with type %s can't be placed to the managed clusters, only the type %s is supported", opt.Name, opt.GetType(), operatorv1alpha1.OperatorTypeOLM)
+			}
+
+			co := r.generateClusterObjects(ctx, opt, registryKey, requestKey)
+			if r.GetOperatorNamespace(opt.InstallMode, opt.Namespace) != constant.ClusterOperatorNamespace {
+				objects = append(objects, co.namespace, co.operatorGroup)
+			}
+			objects = append(objects, co.subscription)
+
+			if operand.Kind == "" {
+				continue
+			}
+			cr := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			if operand.Spec != nil && len(operand.Spec.Raw) != 0 {
+				spec := make(map[string]interface{})
+				if err := json.Unmarshal(operand.Spec.Raw, &spec); err != nil {
+					return nil, errors.Wrapf(err, "failed to decode the spec of the operand %s", operand.Name)
+				}
+				cr.Object["spec"] = spec
+			}
+			cr.SetAPIVersion(operand.APIVersion)
+			cr.SetKind(operand.Kind)
+			cr.SetName(OperandInstanceName(requestKey.Name, operand, i))
+			cr.SetNamespace(requestKey.Namespace)
+			cr.SetLabels(map[string]string{constant.OpreqLabel: "true"})
+			requestNs := co.namespace.DeepCopy()
+			requestNs.Name = requestKey.Namespace
+			objects = append(objects, requestNs, cr)
+		}
+	}
+
+	// The operators sharing a namespace share its namespace and OperatorGroup
+	found := make(map[string]bool)
+	var manifests []map[string]interface{}
+	for _, obj := range objects {
+		manifest, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert %s to a manifest", obj.GetObjectKind().GroupVersionKind().Kind)
+		}
+		u := unstructured.Unstructured{Object: manifest}
+		key := u.GroupVersionKind().String() + "/" + u.GetNamespace() + "/" + u.GetName()
+		if found[key] {
+			continue
+		}
+		found[key] = true
+		unstructured.RemoveNestedField(manifest, "metadata", "creationTimestamp")
+		unstructured.RemoveNestedField(manifest, "status")
+		manifests = append(manifests, manifest)
+	}
+	return manifests, nil
+}
+
+// listPlacementWorks returns the ManifestWorks of the request by the managed cluster
+func (r *Reconciler) listPlacementWorks(ctx context.Context, requestKey types.NamespacedName) (map[string]unstructured.Unstructured, error) {
+	workList := &unstructured.UnstructuredList{}
+	workList.SetGroupVersionKind(placement.ManifestWorkGVK.GroupVersion().WithKind(placement.ManifestWorkGVK.Kind + "List"))
+	if err := r.Reader.List(ctx, workList, client.MatchingLabels{constant.OpreqLabel: "true"}); err != nil {
+		return nil, errors.Wrap(err, "failed to list the ManifestWorks, please make sure Open Cluster Management is installed")
+	}
+
+	works := make(map[string]unstructured.Unstructured)
+	for _, work := range workList.Items {
+		if placement.IsOwnedBy(&work, requestKey) {
+			works[work.GetNamespace()] = work
+		}
+	}
+	return works, nil
+}
+
+// deletePlacement removes the operators and operands of the request from all the managed clusters
+func (r *Reconciler) deletePlacement(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) error {
+	requestKey := types.NamespacedName{Namespace: requestInstance.Namespace, Name: requestInstance.Name}
+	works, err := r.listPlacementWorks(ctx, requestKey)
+	if err != nil {
+		if meta.IsNoMatchError(errors.Cause(err)) {
+			return nil
+		}
+		return err
+	}
+
+	merr := &util.MultiErr{}
+	for cluster, work := range works {
+		work := work
+		logging.FromContext(ctx).V(logging.LevelChange).Info("Deleting the ManifestWork", "manifestWork", cluster+"/"+work.GetName())
+		if err := r.Delete(ctx, &work); err != nil && !apierrors.IsNotFound(err) {
+			merr.Add(errors.Wrapf(err, "failed to delete the ManifestWork %s/%s", cluster, work.GetName()))
+		}
+	}
+	if len(merr.Errors) != 0 {
+		return merr
+	}
+	requestInstance.Status.Placement = nil
+	return nil
+}
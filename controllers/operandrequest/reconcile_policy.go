@@ -0,0 +1,113 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/policy"
+)
+
+// checkPolicies returns the ClusterOperandPolicy violated by the operator of the request, if any.
+// The channel switches forced by the OperandRegistry aren't checked.
+func (r *Reconciler) checkPolicies(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, opt *operatorv1alpha1.Operator) (*policy.Violation, error) {
+	policies, err := policy.List(ctx, r.Client)
+	if err != nil || len(policies) == 0 {
+		return nil, err
+	}
+
+	ns := &corev1.Namespace{}
+	if err := r.Reader.Get(ctx, types.NamespacedName{Name: requestInstance.Namespace}, ns); err != nil {
+		return nil, errors.Wrapf(err, "failed to get namespace %s", requestInstance.Namespace)
+	}
+	if violation, err := policy.CheckOperator(policies, opt.Name, ns.Name, ns.Labels); err != nil || violation != nil {
+		return violation, err
+	}
+
+	requestList := &operatorv1alpha1.OperandRequestList{}
+	if err := r.Client.List(ctx, requestList, client.InNamespace(requestInstance.Namespace)); err != nil {
+		return nil, errors.Wrapf(err, "failed to list the OperandRequests in namespace %s", requestInstance.Namespace)
+	}
+	if violation := policy.CheckQuota(policies, requestInstance, requestList.Items)[opt.Name]; violation != nil {
+		return violation, nil
+	}
+
+	if opt.GetType() != operatorv1alpha1.OperatorTypeOLM || registryInstance.IsUpgradeForced(opt.Name) {
+		return nil, nil
+	}
+	sub, err := r.GetSubscription(ctx, opt.Name, r.GetOperatorNamespace(opt.InstallMode, opt.Namespace), opt.PackageName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if sub == nil || sub.Labels[constant.OpreqLabel] != "true" {
+		return nil, nil
+	}
+	scheme, err := deploy.GetChannelScheme(opt)
+	if err != nil {
+		return nil, err
+	}
+	return policy.CheckChannelSwitch(policies, opt.Name, scheme, sub.Spec.Channel, opt.Channel)
+}
+
+// getPolicyToRequestMapper maps the ClusterOperandPolicies to all the OperandRequests
+func (r *Reconciler) getPolicyToRequestMapper() handler.MapFunc {
+	ctx := context.Background()
+	return func(object client.Object) []ctrl.Request {
+		requestList := &operatorv1alpha1.OperandRequestList{}
+		if err := r.Client.List(ctx, requestList); err != nil {
+			return nil
+		}
+		requests := []ctrl.Request{}
+		for _, request := range requestList.Items {
+			requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Name: request.Name, Namespace: request.Namespace}})
+		}
+		return requests
+	}
+}
+
+// getBlockedRequestMapper maps a deleted OperandRequest to the blocked OperandRequests of its namespace,
+// the operands it frees may be granted to them
+func (r *Reconciler) getBlockedRequestMapper() handler.MapFunc {
+	ctx := context.Background()
+	return func(object client.Object) []ctrl.Request {
+		requestList := &operatorv1alpha1.OperandRequestList{}
+		if err := r.Client.List(ctx, requestList, client.InNamespace(object.GetNamespace())); err != nil {
+			return nil
+		}
+		requests := []ctrl.Request{}
+		for _, request := range requestList.Items {
+			if request.Name != object.GetName() && request.IsBlocked() {
+				requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Name: request.Name, Namespace: request.Namespace}})
+			}
+		}
+		return requests
+	}
+}
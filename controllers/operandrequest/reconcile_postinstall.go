@@ -0,0 +1,131 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/postinstall"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// verifyOperand runs the post-install hooks of the service of the operand, the operand without hooks is verified.
+// It returns false while the Job of the hooks is running, and an error when a hook fails, with a VerificationFailed condition.
+func (r *Reconciler) verifyOperand(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryKey types.NamespacedName, operand operatorv1alpha1.Operand, opdRegistry *operatorv1alpha1.Operator) (bool, error) {
+	configInstance, err := r.GetOperandConfig(ctx, registryKey)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, errors.Wrapf(err, "failed to get the OperandConfig %s", registryKey.String())
+	}
+	service := configInstance.GetService(operand.Name)
+	if service == nil || service.PostInstall == nil {
+		requestInstance.RemoveVerificationFailedCondition(operand.Name, operatorv1alpha1.ResourceTypeOperand, &r.Mutex)
+		return true, nil
+	}
+	hooks := service.PostInstall
+
+	if hooks.Job != nil {
+		succeeded, failure, err := r.checkPostInstallJob(ctx, hooks, service.Name, opdRegistry.Namespace)
+		if err != nil {
+			return false, err
+		}
+		if failure != "" {
+			return false, r.verificationFailed(ctx, requestInstance, operand.Name, failure)
+		}
+		if !succeeded {
+			logging.FromContext(ctx).V(logging.LevelDebug).Info("Waiting for the post-install Job", "operand", operand.Name)
+			return false, nil
+		}
+	}
+	if hooks.HTTP != nil {
+		if err := postinstall.CheckHTTP(ctx, nil, hooks.HTTP); err != nil {
+			return false, r.verificationFailed(ctx, requestInstance, operand.Name, err.Error())
+		}
+	}
+
+	logging.FromContext(ctx).V(logging.LevelFlow).Info("The post-install hooks succeeded", "operand", operand.Name)
+	r.Recorder.Eventf(requestInstance, corev1.EventTypeNormal, constant.EventReasonVerified, "The post-install hooks of the operand %s succeeded", operand.Name)
+	requestInstance.RemoveVerificationFailedCondition(operand.Name, operatorv1alpha1.ResourceTypeOperand, &r.Mutex)
+	return true, nil
+}
+
+// verificationFailed records the failure of a post-install hook of the operand and returns it as an error
+func (r *Reconciler) verificationFailed(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, name, failure string) error {
+	logging.FromContext(ctx).Info("The post-install hooks failed", "operand", name, "reason", failure)
+	r.Recorder.Eventf(requestInstance, corev1.EventTypeWarning, constant.EventReasonVerificationFailed, "The post-install hooks of the operand %s failed: %s", name, failure)
+	requestInstance.SetVerificationFailedCondition(name, failure, operatorv1alpha1.ResourceTypeOperand, corev1.ConditionTrue, &r.Mutex)
+	return fmt.Errorf("the post-install hooks of the operand %s failed: %s", name, failure)
+}
+
+// checkPostInstallJob returns true if the post-install Job of the service succeeded, and the reason of its failure if it failed.
+// The Job is created when it doesn't exist for the current spec, the Jobs of the previous specs are deleted.
+// The failed Job is kept, so its logs can be read, delete it to run the hook again.
+func (r *Reconciler) checkPostInstallJob(ctx context.Context, hooks *operatorv1alpha1.PostInstallHooks, service, namespace string) (bool, string, error) {
+	jobKey := types.NamespacedName{Namespace: namespace, Name: postinstall.JobName(service, postinstall.Hash(hooks))}
+	job := &batchv1.Job{}
+	if err := r.Reader.Get(ctx, jobKey, job); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return false, "", errors.Wrapf(err, "failed to get the post-install Job %s", jobKey.String())
+		}
+		job, err = postinstall.NewJob(hooks, service, namespace)
+		if err != nil {
+			return false, err.Error(), nil
+		}
+		// Only the Job for the latest spec is kept
+		if err := r.deletePostInstallJobs(ctx, service, namespace, jobKey.Name); err != nil {
+			return false, "", err
+		}
+		logging.FromContext(ctx).V(logging.LevelChange).Info("Creating the post-install Job", "job", jobKey.String(), "service", service)
+		if err := r.Create(ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+			return false, "", errors.Wrapf(err, "failed to create the post-install Job %s", jobKey.String())
+		}
+		return false, "", nil
+	}
+	succeeded, failure := postinstall.JobResult(job)
+	return succeeded, failure, nil
+}
+
+// deletePostInstallJobs deletes the post-install Jobs of the service, except the one with the name to keep
+func (r *Reconciler) deletePostInstallJobs(ctx context.Context, service, namespace, keep string) error {
+	jobList := &batchv1.JobList{}
+	if err := r.Reader.List(ctx, jobList, client.InNamespace(namespace), client.MatchingLabels{constant.PostInstallHookLabel: service}); err != nil {
+		return errors.Wrapf(err, "failed to list the post-install Jobs of the service %s/%s", namespace, service)
+	}
+	for i := range jobList.Items {
+		job := &jobList.Items[i]
+		if job.Name == keep {
+			continue
+		}
+		if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete the post-install Job %s/%s", namespace, job.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/predelete"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// runPreDeleteHooks runs the pre-delete hooks of the service before its custom resources are deleted. The hooks start
+// once, their start time is recorded on the custom resources, and they are checked at every reconcile. It returns
+// an error while the hooks are running, and when they fail or time out with the Fail policy, so the custom resources
+// are kept and the deletion is retried.
+func (r *Reconciler) runPreDeleteHooks(ctx context.Context, csv *olmv1alpha1.ClusterServiceVersion, requestInstance *operatorv1alpha1.OperandRequest, service *operatorv1alpha1.ConfigService, namespace string) error {
+	log := logging.FromContext(ctx)
+	hooks := service.PreDelete
+
+	crs, err := r.getPreDeleteCustomResources(ctx, csv, requestInstance, service, namespace)
+	if err != nil {
+		return err
+	}
+	if len(crs) == 0 {
+		// Nothing is left to drain
+		return r.deletePreDeleteJob(ctx, service.Name, namespace)
+	}
+
+	started := predelete.Started(crs)
+	if started.IsZero() {
+		started = time.Now()
+		log.V(logging.LevelChange).Info("Starting the pre-delete hooks", "service", service.Name, "namespace", namespace)
+		r.Recorder.Eventf(requestInstance, corev1.EventTypeNormal, constant.EventReasonPreDeleteHookStarted, "Started the pre-delete hooks of the service %s before deleting its custom resources", service.Name)
+		for _, cr := range crs {
+			patch, err := predelete.Patch(hooks, cr, started)
+			if err != nil {
+				return err
+			}
+			cr := cr
+			if err := r.Patch(ctx, &cr, client.RawPatch(types.MergePatchType, patch)); err != nil && !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to start the pre-delete hooks on the custom resource %s %s/%s", cr.GetKind(), cr.GetNamespace(), cr.GetName())
+			}
+		}
+	}
+
+	done, failure, err := r.checkPreDeleteHooks(ctx, hooks, service.Name, namespace, crs)
+	if err != nil {
+		return err
+	}
+	if done {
+		log.V(logging.LevelFlow).Info("The pre-delete hooks are done", "service", service.Name, "namespace", namespace)
+		return r.deletePreDeleteJob(ctx, service.Name, namespace)
+	}
+	if failure == "" && time.Since(started) > hooks.GetTimeout() {
+		failure = fmt.Sprintf("the pre-delete hooks didn't finish in %s", hooks.GetTimeout())
+	}
+	if failure == "" {
+		log.V(logging.LevelDebug).Info("Waiting for the pre-delete hooks", "service", service.Name, "namespace", namespace)
+		return fmt.Errorf("waiting for the pre-delete hooks of the service %s in the namespace %s", service.Name, namespace)
+	}
+
+	r.Recorder.Eventf(requestInstance, corev1.EventTypeWarning, constant.EventReasonPreDeleteHookFailed, "The pre-delete hooks of the service %s failed: %s", service.Name, failure)
+	if hooks.ContinuesOnError() {
+		log.Info("The pre-delete hooks failed, delete the custom resources anyway", "service", service.Name, "namespace", namespace, "reason", failure)
+		return r.deletePreDeleteJob(ctx, service.Name, namespace)
+	}
+	return fmt.Errorf("the pre-delete hooks of the service %s in the namespace %s failed: %s", service.Name, namespace, failure)
+}
+
+// checkPreDeleteHooks returns true if the Job of the hooks succeeded and the custom resources are drained,
+// and the reason of the failure if the Job failed. The Job is created if it doesn't exist yet.
+func (r *Reconciler) checkPreDeleteHooks(ctx context.Context, hooks *operatorv1alpha1.PreDeleteHooks, service, namespace string, crs []unstructured.Unstructured) (bool, string, error) {
+	jobDone := true
+	if hooks.Job != nil {
+		jobKey := types.NamespacedName{Namespace: namespace, Name: predelete.JobName(service)}
+		job := &batchv1.Job{}
+		if err := r.Reader.Get(ctx, jobKey, job); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return false, "", errors.Wrapf(err, "failed to get the pre-delete Job %s", jobKey.String())
+			}
+			job, err = predelete.NewJob(hooks, service, namespace)
+			if err != nil {
+				return false, err.Error(), nil
+			}
+			logging.FromContext(ctx).V(logging.LevelChange).Info("Creating the pre-delete Job", "job", jobKey.String(), "service", service)
+			if err := r.Create(ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+				return false, "", errors.Wrapf(err, "failed to create the pre-delete Job %s", jobKey.String())
+			}
+			return false, "", nil
+		}
+		succeeded, failure := predelete.JobResult(job)
+		if failure != "" {
+			return false, failure, nil
+		}
+		jobDone = succeeded
+	}
+
+	drained, err := predelete.Drained(hooks, crs)
+	if err != nil {
+		return false, err.Error(), nil
+	}
+	return jobDone && drained, "", nil
+}
+
+// getPreDeleteCustomResources returns the custom resources of the service ODLM would delete, the custom resources
+// created from the OperandRequest and the ones created from the alm-examples with the OperandConfig.
+func (r *Reconciler) getPreDeleteCustomResources(ctx context.Context, csv *olmv1alpha1.ClusterServiceVersion, requestInstance *operatorv1alpha1.OperandRequest, service *operatorv1alpha1.ConfigService, namespace string) ([]unstructured.Unstructured, error) {
+	var refs []unstructured.Unstructured
+	for _, member := range requestInstance.Status.Members {
+		if member.Name != service.Name {
+			continue
+		}
+		for _, cr := range member.OperandCRList {
+			ref := unstructured.Unstructured{}
+			ref.SetAPIVersion(cr.APIVersion)
+			ref.SetKind(cr.Kind)
+			ref.SetName(cr.Name)
+			ref.SetNamespace(requestInstance.Namespace)
+			refs = append(refs, ref)
+		}
+	}
+
+	if almExamples := csv.GetAnnotations()["alm-examples"]; almExamples != "" {
+		var almExamplesRaw []unstructured.Unstructured
+		if err := json.Unmarshal([]byte(almExamples), &almExamplesRaw); err != nil {
+			return nil, errors.Wrapf(err, "failed to convert alm-examples in the Subscription %s to slice", service.Name)
+		}
+		for _, example := range almExamplesRaw {
+			for crdName := range service.Spec {
+				if strings.EqualFold(example.GetKind(), crdName) {
+					ref := unstructured.Unstructured{}
+					ref.SetAPIVersion(example.GetAPIVersion())
+					ref.SetKind(example.GetKind())
+					ref.SetName(example.GetName())
+					ref.SetNamespace(namespace)
+					refs = append(refs, ref)
+				}
+			}
+		}
+	}
+
+	var crs []unstructured.Unstructured
+	for _, ref := range refs {
+		cr := unstructured.Unstructured{}
+		cr.SetGroupVersionKind(ref.GroupVersionKind())
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: ref.GetNamespace(), Name: ref.GetName()}, &cr); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get custom resource -- Kind: %s, NamespacedName: %s/%s", ref.GetKind(), ref.GetNamespace(), ref.GetName())
+		}
+		// The hooks only run for the custom resources ODLM deletes
+		if !r.CheckLabel(cr, map[string]string{constant.OpreqLabel: "true"}) || r.CheckLabel(cr, map[string]string{constant.NotUninstallLabel: "true"}) || r.isAdopted(cr) {
+			continue
+		}
+		crs = append(crs, cr)
+	}
+	return crs, nil
+}
+
+// deletePreDeleteJob deletes the pre-delete Job of the service
+func (r *Reconciler) deletePreDeleteJob(ctx context.Context, service, namespace string) error {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      predelete.JobName(service),
+			Namespace: namespace,
+		},
+	}
+	if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete the pre-delete Job %s/%s", namespace, job.Name)
+	}
+	return nil
+}
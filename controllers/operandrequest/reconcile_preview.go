@@ -0,0 +1,360 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	gset "github.com/deckarep/golang-set"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/bundle"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+var otherRegistryAnnotation = regexp.MustCompile(`^(.*)\.(.*)\/registry`)
+
+// reconcileDeletionPreview sets the deletion preview in the status when the OperandRequest asks for it, and removes it otherwise.
+// The generated time is only updated when the preview changes, so the status isn't patched at every reconcile.
+func (r *Reconciler) reconcileDeletionPreview(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) error {
+	if !requestInstance.IsDeletionPreviewRequested() {
+		requestInstance.Status.DeletionPreview = nil
+		return nil
+	}
+	logging.FromContext(ctx).V(logging.LevelChange).Info("Generating the deletion preview of OperandRequest")
+	preview, err := r.previewDeletion(ctx, requestInstance)
+	if err != nil {
+		return err
+	}
+	if existing := requestInstance.Status.DeletionPreview; existing != nil {
+		preview.GeneratedTime = existing.GeneratedTime
+		if reflect.DeepEqual(existing, preview) {
+			return nil
+		}
+	}
+	preview.GeneratedTime = time.Now().UTC().Format(time.RFC3339)
+	requestInstance.Status.DeletionPreview = preview
+	return nil
+}
+
+// previewDeletion lists the resources deleted and retained when the OperandRequest is deleted, like checkFinalizer
+// uninstalls its operands. Nothing is deleted or changed.
+func (r *Reconciler) previewDeletion(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) (*operatorv1alpha1.DeletionPreview, error) {
+	preview := &operatorv1alpha1.DeletionPreview{}
+	requestedByOthers, err := r.getRequestedOperands(ctx, requestInstance, true)
+	if err != nil {
+		return nil, err
+	}
+
+	previewed := gset.NewSet()
+	for _, req := range requestInstance.Spec.Requests {
+		registryKey := requestInstance.GetRegistryKey(req)
+		registryInstance, err := r.GetOperandRegistry(ctx, registryKey)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		configInstance, err := r.GetOperandConfig(ctx, registryKey)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			configInstance = &operatorv1alpha1.OperandConfig{}
+		}
+		for _, member := range requestInstance.Status.Members {
+			op := registryInstance.GetOperator(member.Name)
+			if op == nil || previewed.Contains(member.Name) {
+				continue
+			}
+			previewed.Add(member.Name)
+			installer, err := r.getInstaller(op)
+			if err != nil {
+				return nil, err
+			}
+			previewer, ok := installer.(UninstallPreviewer)
+			if !ok {
+				continue
+			}
+			operandPreview := &operatorv1alpha1.DeletionPreview{}
+			if err := previewer.PreviewUninstall(ctx, requestInstance, registryInstance, configInstance, op, operandPreview); err != nil {
+				return nil, err
+			}
+			// The operand still requested by the other OperandRequests isn't uninstalled
+			reason := ""
+			if requestedByOthers.Contains(member.Name) {
+				reason = operatorv1alpha1.PreviewReasonRequestedByOthers
+			}
+			preview.Merge(operandPreview, reason)
+		}
+	}
+
+	if err := r.previewBindInfoCopies(ctx, requestInstance, preview); err != nil {
+		return nil, err
+	}
+	return preview, nil
+}
+
+// previewBindInfoCopies adds the Secrets and ConfigMaps copied by the OperandBindInfos to the preview,
+// they are owned by the OperandRequest and garbage collected with it.
+func (r *Reconciler) previewBindInfoCopies(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, preview *operatorv1alpha1.DeletionPreview) error {
+	opts := []client.ListOption{
+		client.InNamespace(requestInstance.Namespace),
+		client.MatchingLabels(map[string]string{constant.OpbiTypeLabel: "copy"}),
+	}
+	// Secrets are filtered in the cache, list them from the API server
+	secretList := &corev1.SecretList{}
+	if err := r.PagedList(ctx, secretList, opts...); err != nil {
+		return errors.Wrapf(err, "failed to list the Secrets copied to the namespace %s", requestInstance.Namespace)
+	}
+	for i := range secretList.Items {
+		if metav1.IsControlledBy(&secretList.Items[i], requestInstance) {
+			preview.AddDeleted(operatorv1alpha1.PreviewResource{APIVersion: "v1", Kind: "Secret", Namespace: requestInstance.Namespace, Name: secretList.Items[i].Name})
+		}
+	}
+	cmList := &corev1.ConfigMapList{}
+	if err := r.PagedList(ctx, cmList, opts...); err != nil {
+		return errors.Wrapf(err, "failed to list the ConfigMaps copied to the namespace %s", requestInstance.Namespace)
+	}
+	for i := range cmList.Items {
+		if metav1.IsControlledBy(&cmList.Items[i], requestInstance) {
+			preview.AddDeleted(operatorv1alpha1.PreviewResource{APIVersion: "v1", Kind: "ConfigMap", Namespace: requestInstance.Namespace, Name: cmList.Items[i].Name})
+		}
+	}
+	return nil
+}
+
+// previewOperands adds the custom resources and the k8s resources of the operand to the preview, like
+// deleteAllCustomResource and deleteAllK8sResource delete them.
+func (r *Reconciler) previewOperands(ctx context.Context, csv *olmv1alpha1.ClusterServiceVersion, requestInstance *operatorv1alpha1.OperandRequest, configInstance *operatorv1alpha1.OperandConfig, operandName, namespace string, preview *operatorv1alpha1.DeletionPreview) error {
+	for _, member := range requestInstance.Status.Members {
+		if member.Name != operandName {
+			continue
+		}
+		for _, cr := range member.OperandCRList {
+			if err := r.previewObject(ctx, operatorv1alpha1.PreviewResource{APIVersion: cr.APIVersion, Kind: cr.Kind, Namespace: requestInstance.Namespace, Name: cr.Name, Operand: operandName}, true, preview); err != nil {
+				return err
+			}
+		}
+	}
+
+	service := configInstance.GetService(operandName)
+	if service == nil {
+		return nil
+	}
+	if almExamples := csv.GetAnnotations()["alm-examples"]; almExamples != "" {
+		var almExampleList []map[string]interface{}
+		if err := json.Unmarshal([]byte(almExamples), &almExampleList); err != nil {
+			return errors.Wrapf(err, "failed to convert alm-examples in the ClusterServiceVersion %s/%s to slice", csv.Namespace, csv.Name)
+		}
+		for _, almExample := range almExampleList {
+			cr := unstructured.Unstructured{Object: almExample}
+			for kind := range service.Spec {
+				if strings.EqualFold(cr.GetKind(), kind) {
+					if err := r.previewObject(ctx, operatorv1alpha1.PreviewResource{APIVersion: cr.GetAPIVersion(), Kind: cr.GetKind(), Namespace: namespace, Name: cr.GetName(), Operand: operandName}, true, preview); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	for _, res := range service.Resources {
+		resNamespace := namespace
+		if res.Namespace != "" {
+			resNamespace = res.Namespace
+		}
+		if err := r.previewObject(ctx, operatorv1alpha1.PreviewResource{APIVersion: res.APIVersion, Kind: res.Kind, Namespace: resNamespace, Name: res.Name, Operand: operandName}, false, preview); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// previewObject adds the existing object to the preview, it is only deleted if it is created by ODLM
+// and doesn't have the label operator.ibm.com/opreq-do-not-uninstall. The adoptable custom resources adopted
+// by ODLM are released instead of being deleted.
+func (r *Reconciler) previewObject(ctx context.Context, res operatorv1alpha1.PreviewResource, adoptable bool, preview *operatorv1alpha1.DeletionPreview) error {
+	obj := unstructured.Unstructured{}
+	obj.SetAPIVersion(res.APIVersion)
+	obj.SetKind(res.Kind)
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: res.Namespace, Name: res.Name}, &obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get the %s %s/%s", res.Kind, res.Namespace, res.Name)
+	}
+	switch {
+	case !r.CheckLabel(obj, map[string]string{constant.OpreqLabel: "true"}):
+		preview.AddRetained(res, operatorv1alpha1.PreviewReasonNotCreatedByODLM)
+	case r.CheckLabel(obj, map[string]string{constant.NotUninstallLabel: "true"}):
+		preview.AddRetained(res, operatorv1alpha1.PreviewReasonDoNotUninstall)
+	case adoptable && r.isAdopted(obj):
+		preview.AddRetained(res, operatorv1alpha1.PreviewReasonAdopted)
+	default:
+		preview.AddDeleted(res)
+	}
+	return nil
+}
+
+// requestedWithOtherRegistries returns true if the object of the operator is also requested with another OperandRegistry
+func requestedWithOtherRegistries(annotations map[string]string, registryInstance *operatorv1alpha1.OperandRegistry) bool {
+	for anno := range annotations {
+		if anno != registryInstance.Namespace+"."+registryInstance.Name+"/registry" && otherRegistryAnnotation.MatchString(anno) {
+			return true
+		}
+	}
+	return false
+}
+
+// previewUninstallSubscription previews uninstallSubscription
+func (r *Reconciler) previewUninstallSubscription(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, configInstance *operatorv1alpha1.OperandConfig, op *operatorv1alpha1.Operator, preview *operatorv1alpha1.DeletionPreview) error {
+	namespace := r.GetOperatorNamespace(op.InstallMode, op.Namespace)
+	sub, err := r.GetSubscription(ctx, op.Name, namespace, op.PackageName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	subRes := operatorv1alpha1.PreviewResource{APIVersion: olmv1alpha1.SchemeGroupVersion.String(), Kind: olmv1alpha1.SubscriptionKind, Namespace: sub.Namespace, Name: sub.Name, Operand: op.Name}
+	if _, ok := sub.Labels[constant.OpreqLabel]; !ok {
+		preview.AddRetained(subRes, operatorv1alpha1.PreviewReasonNotCreatedByODLM)
+		return nil
+	}
+	if requestedWithOtherRegistries(sub.Annotations, registryInstance) {
+		preview.AddRetained(subRes, operatorv1alpha1.PreviewReasonOtherRegistries)
+		return nil
+	}
+
+	csv, err := r.GetClusterServiceVersion(ctx, sub)
+	if err != nil {
+		return err
+	}
+	if csv != nil {
+		if err := r.previewOperands(ctx, csv, requestInstance, configInstance, op.Name, op.Namespace, preview); err != nil {
+			return err
+		}
+		csvRes := operatorv1alpha1.PreviewResource{APIVersion: olmv1alpha1.SchemeGroupVersion.String(), Kind: olmv1alpha1.ClusterServiceVersionKind, Namespace: csv.Namespace, Name: csv.Name, Operand: op.Name}
+		if r.checkUninstallLabel(ctx, op.Name, namespace) {
+			preview.AddRetained(csvRes, operatorv1alpha1.PreviewReasonDoNotUninstall)
+			preview.AddRetained(subRes, operatorv1alpha1.PreviewReasonDoNotUninstall)
+			return nil
+		}
+		preview.AddDeleted(csvRes)
+	}
+	preview.AddDeleted(subRes)
+	return nil
+}
+
+// previewDeleteHelmRelease previews deleteHelmRelease
+func (r *Reconciler) previewDeleteHelmRelease(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, configInstance *operatorv1alpha1.OperandConfig, op *operatorv1alpha1.Operator, preview *operatorv1alpha1.DeletionPreview) error {
+	namespace := r.GetOperatorNamespace(op.InstallMode, op.Namespace)
+	release := op.GetReleaseName()
+	secret := &corev1.Secret{}
+	secretKey := types.NamespacedName{Namespace: namespace, Name: release + constant.HelmValuesSecretSuffix}
+	if err := r.Reader.Get(ctx, secretKey, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get the Helm values Secret %s", secretKey.String())
+	}
+	releaseRes := operatorv1alpha1.PreviewResource{Kind: "HelmRelease", Namespace: namespace, Name: release, Operand: op.Name}
+	secretRes := operatorv1alpha1.PreviewResource{APIVersion: "v1", Kind: "Secret", Namespace: namespace, Name: secret.Name, Operand: op.Name}
+	if _, ok := secret.Labels[constant.OpreqLabel]; !ok {
+		preview.AddRetained(releaseRes, operatorv1alpha1.PreviewReasonNotCreatedByODLM)
+		return nil
+	}
+	if requestedWithOtherRegistries(secret.Annotations, registryInstance) {
+		preview.AddRetained(releaseRes, operatorv1alpha1.PreviewReasonOtherRegistries)
+		return nil
+	}
+
+	if err := r.previewOperands(ctx, helmCSV(op, namespace), requestInstance, configInstance, op.Name, op.Namespace, preview); err != nil {
+		return err
+	}
+	if secret.Labels[constant.NotUninstallLabel] == "true" {
+		preview.AddRetained(releaseRes, operatorv1alpha1.PreviewReasonDoNotUninstall)
+		preview.AddRetained(secretRes, operatorv1alpha1.PreviewReasonDoNotUninstall)
+		return nil
+	}
+	preview.AddDeleted(releaseRes)
+	preview.AddDeleted(secretRes)
+	return nil
+}
+
+// previewDeleteManifests previews deleteManifests
+func (r *Reconciler) previewDeleteManifests(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, configInstance *operatorv1alpha1.OperandConfig, op *operatorv1alpha1.Operator, preview *operatorv1alpha1.DeletionPreview) error {
+	namespace := r.GetOperatorNamespace(op.InstallMode, op.Namespace)
+	cm := &corev1.ConfigMap{}
+	cmKey := types.NamespacedName{Namespace: namespace, Name: op.Name + constant.ManifestsConfigMapSuffix}
+	if err := r.Reader.Get(ctx, cmKey, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get the manifests ConfigMap %s", cmKey.String())
+	}
+	cmRes := operatorv1alpha1.PreviewResource{APIVersion: "v1", Kind: "ConfigMap", Namespace: namespace, Name: cm.Name, Operand: op.Name}
+	if _, ok := cm.Labels[constant.OpreqLabel]; !ok {
+		preview.AddRetained(cmRes, operatorv1alpha1.PreviewReasonNotCreatedByODLM)
+		return nil
+	}
+	if requestedWithOtherRegistries(cm.Annotations, registryInstance) {
+		preview.AddRetained(cmRes, operatorv1alpha1.PreviewReasonOtherRegistries)
+		return nil
+	}
+
+	manifests, err := bundle.Load(cm.Data, op.Name, namespace, op.TargetNamespaces)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load the manifests of the operator %s", op.Name)
+	}
+	if err := r.previewOperands(ctx, manifests.CSV, requestInstance, configInstance, op.Name, op.Namespace, preview); err != nil {
+		return err
+	}
+	refs := appliedManifestsRefs(ctx, cm)
+	if cm.Labels[constant.NotUninstallLabel] == "true" {
+		for _, ref := range refs {
+			preview.AddRetained(operatorv1alpha1.PreviewResource{APIVersion: ref.APIVersion, Kind: ref.Kind, Namespace: ref.Namespace, Name: ref.Name, Operand: op.Name}, operatorv1alpha1.PreviewReasonDoNotUninstall)
+		}
+		preview.AddRetained(cmRes, operatorv1alpha1.PreviewReasonDoNotUninstall)
+		return nil
+	}
+	for _, ref := range refs {
+		res := operatorv1alpha1.PreviewResource{APIVersion: ref.APIVersion, Kind: ref.Kind, Namespace: ref.Namespace, Name: ref.Name, Operand: op.Name}
+		if ref.Kind == "CustomResourceDefinition" {
+			preview.AddRetained(res, operatorv1alpha1.PreviewReasonCRD)
+		} else {
+			preview.AddDeleted(res)
+		}
+	}
+	preview.AddDeleted(cmRes)
+	return nil
+}
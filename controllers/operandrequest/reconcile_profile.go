@@ -0,0 +1,64 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/profile"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// renderPlacementProfile returns a copy of the service with its placement profile rendered into the pod templates
+// of its k8s resources and into its custom resources. The service is returned unchanged when it has no placement.
+func renderPlacementProfile(ctx context.Context, service *operatorv1alpha1.ConfigService) (*operatorv1alpha1.ConfigService, error) {
+	if service.Placement == nil {
+		return service, nil
+	}
+	p, err := profile.Lookup(service.Placement.Profile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to render the placement of the service %s", service.Name)
+	}
+	if p == nil {
+		return service, nil
+	}
+	logging.FromContext(ctx).V(logging.LevelDebug).Info("Rendering the placement profile of the service", "service", service.Name, "profile", p.Name)
+
+	rendered := service.DeepCopy()
+	for i, res := range rendered.Resources {
+		if res.Data == nil {
+			continue
+		}
+		data, err := profile.RenderResource(res.Kind, res.Data.Raw, p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render the placement profile of the k8s resource %s/%s", res.Kind, res.Name)
+		}
+		rendered.Resources[i].Data = &runtime.RawExtension{Raw: data}
+	}
+	for kind, spec := range rendered.Spec {
+		data, err := profile.RenderCustomResource(kind, spec.Raw, service.Placement, p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render the placement profile of the custom resource %s", kind)
+		}
+		rendered.Spec[kind] = runtime.RawExtension{Raw: data}
+	}
+	return rendered, nil
+}
@@ -0,0 +1,96 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/proxy"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// renderProxy returns a copy of the service with the proxy and the CA bundle of the cluster rendered into its custom resources
+// in the namespace. The service is returned unchanged when it declares no proxy.
+func (r *Reconciler) renderProxy(ctx context.Context, service *operatorv1alpha1.ConfigService, namespace string) (*operatorv1alpha1.ConfigService, error) {
+	p := service.Proxy
+	if p == nil || len(p.CustomResources) == 0 {
+		return service, nil
+	}
+
+	settings := proxy.FromEnvironment()
+	if proxy.NeedsCABundle(p) {
+		caBundle, err := r.getCABundle(ctx, p, namespace)
+		if err != nil {
+			return nil, err
+		}
+		settings.CABundle = caBundle
+		settings.CABundleConfigMap = proxy.CABundleConfigMapName(p)
+	}
+
+	rendered := service.DeepCopy()
+	for kind, spec := range rendered.Spec {
+		data, err := proxy.RenderCustomResource(kind, spec.Raw, p, settings)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render the proxy of the custom resource %s", kind)
+		}
+		rendered.Spec[kind] = runtime.RawExtension{Raw: data}
+	}
+	return rendered, nil
+}
+
+// getCABundle returns the CA bundle of the service in the namespace. The default ConfigMap is created for OpenShift
+// to inject the trusted CA bundle of the cluster, the CA bundle is empty until it is injected.
+func (r *Reconciler) getCABundle(ctx context.Context, p *operatorv1alpha1.ProxyInjection, namespace string) (string, error) {
+	key := types.NamespacedName{Namespace: namespace, Name: proxy.CABundleConfigMapName(p)}
+	// ConfigMaps are filtered in the cache, get it from the API server
+	cm := &corev1.ConfigMap{}
+	err := r.Reader.Get(ctx, key, cm)
+	if err == nil {
+		return cm.Data[proxy.CABundleKey], nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return "", errors.Wrapf(err, "failed to get the CA bundle ConfigMap %s", key.String())
+	}
+	if key.Name != proxy.TrustedCABundleConfigMap {
+		return "", errors.Errorf("the CA bundle ConfigMap %s is not found", key.String())
+	}
+
+	cm = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+			Labels: map[string]string{
+				constant.OpreqLabel:              "true",
+				proxy.InjectTrustedCABundleLabel: "true",
+			},
+		},
+	}
+	if err := r.Create(ctx, cm); err != nil && !apierrors.IsAlreadyExists(err) {
+		return "", errors.Wrapf(err, "failed to create the CA bundle ConfigMap %s", key.String())
+	}
+	logging.FromContext(ctx).V(logging.LevelChange).Info("Created the ConfigMap for the trusted CA bundle of the cluster", "configMap", key.String())
+	return "", nil
+}
@@ -0,0 +1,248 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/ownership"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// recreateDeleted returns true when the object is created by ODLM and is recreated as soon as it is deleted
+func recreateDeleted(obj client.Object) bool {
+	return obj.GetLabels()[constant.OpreqLabel] == "true" && util.GetRecreatePolicy() == operatorv1alpha1.RecreatePolicyImmediate
+}
+
+// getOperatorGroupToRequestMapper maps a deleted OperatorGroup to the OperandRequests of the Subscriptions in its namespace
+func (r *Reconciler) getOperatorGroupToRequestMapper() handler.MapFunc {
+	ctx := context.Background()
+	subToRequest := r.getSubToRequestMapper()
+	return func(object client.Object) []ctrl.Request {
+		subList, err := r.ListManagedSubscriptions(ctx, client.InNamespace(object.GetNamespace()))
+		if err != nil {
+			logging.Logger("operandrequest").Error(err, "failed to list the Subscriptions of the deleted OperatorGroup", "operatorGroup", object.GetNamespace()+"/"+object.GetName())
+			return nil
+		}
+		var requests []ctrl.Request
+		for i := range subList.Items {
+			requests = append(requests, subToRequest(&subList.Items[i])...)
+		}
+		return requests
+	}
+}
+
+// resourceEventsBufferSize is the number of the deleted or changed objects waiting to be enqueued
+const resourceEventsBufferSize = 1024
+
+// resourceWatcher watches the deletion and the spec changes of the custom resources and k8s resources created by ODLM.
+// Their kinds are only known when ODLM creates them, so the watches are added at runtime
+// and the deleted or changed objects are sent to the OperandRequest controller through the events channel,
+// to recreate them and to remediate their drift from the configuration. The watches of a kind are stopped
+// when its CRD is deleted, and started again when the CRD is established again.
+// When ODLM doesn't watch all the namespaces, it may only list the kinds in the namespaces it watches,
+// so the kinds are watched in the namespaces ODLM creates their objects in rather than in all the namespaces.
+type resourceWatcher struct {
+	config *rest.Config
+	mapper meta.RESTMapper
+	events chan event.GenericEvent
+
+	mu      sync.Mutex
+	ctx     context.Context
+	client  metadata.Interface
+	watched map[schema.GroupVersionKind]schema.GroupVersionResource
+	// namespaces are the namespaces the kinds are watched in, kept when their CRD is deleted
+	namespaces map[schema.GroupVersionKind]map[string]bool
+	// informers index the watched objects by the OperandRequests tracking them, by kind and namespace
+	informers map[schema.GroupVersionKind]map[string]cache.SharedIndexInformer
+	// stops stop the informers of the kinds whose CRD is deleted
+	stops map[schema.GroupVersionKind]map[string]context.CancelFunc
+	// dropped are the kinds whose CRD is deleted, watched again when it is created again
+	dropped map[schema.GroupVersionKind]droppedWatch
+	// crds lists and watches the CRDs, to see when they are established
+	crds dynamic.Interface
+	// waiting are the OperandRequests whose custom resources wait for the CRD of their kind
+	waiting map[schema.GroupKind]map[types.NamespacedName]bool
+}
+
+func newResourceWatcher(config *rest.Config, mapper meta.RESTMapper) *resourceWatcher {
+	return &resourceWatcher{
+		config:     config,
+		mapper:     mapper,
+		events:     make(chan event.GenericEvent, resourceEventsBufferSize),
+		watched:    make(map[schema.GroupVersionKind]schema.GroupVersionResource),
+		namespaces: make(map[schema.GroupVersionKind]map[string]bool),
+		informers:  make(map[schema.GroupVersionKind]map[string]cache.SharedIndexInformer),
+		stops:      make(map[schema.GroupVersionKind]map[string]context.CancelFunc),
+		dropped:    make(map[schema.GroupVersionKind]droppedWatch),
+		waiting:    make(map[schema.GroupKind]map[types.NamespacedName]bool),
+	}
+}
+
+// Start starts the watches added before the manager started, and the ones added later are started right away
+func (w *resourceWatcher) Start(ctx context.Context) error {
+	mc, err := metadata.NewForConfig(w.config)
+	if err != nil {
+		return err
+	}
+	dc, err := dynamic.NewForConfig(w.config)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	w.ctx, w.client, w.crds = ctx, mc, dc
+	for gvk, gvr := range w.watched {
+		w.runAll(gvk, gvr)
+	}
+	w.mu.Unlock()
+	w.watchCRDs(ctx)
+	<-ctx.Done()
+	return nil
+}
+
+// watch adds a watch on the objects of the kind created by ODLM in the namespace
+func (w *resourceWatcher) watch(ctx context.Context, gvk schema.GroupVersionKind, namespace string) {
+	if w == nil {
+		return
+	}
+	if util.GetWatchNamespace() == "" {
+		namespace = metav1.NamespaceAll
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.namespaces[gvk] == nil {
+		w.namespaces[gvk] = make(map[string]bool)
+	}
+	added := !w.namespaces[gvk][namespace]
+	w.namespaces[gvk][namespace] = true
+	if gvr, ok := w.watched[gvk]; ok {
+		if added && w.ctx != nil {
+			w.run(gvk, gvr, namespace)
+		}
+		return
+	}
+	mapping, err := w.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		logging.FromContext(ctx).Info("Failed to watch the kind, its objects are reconciled at the next reconcile", "kind", gvk.String(), "error", err.Error())
+		return
+	}
+	w.watched[gvk] = mapping.Resource
+	if w.ctx != nil {
+		w.runAll(gvk, mapping.Resource)
+	}
+}
+
+// runAll runs the informers of the kind in all the namespaces it is watched in
+func (w *resourceWatcher) runAll(gvk schema.GroupVersionKind, gvr schema.GroupVersionResource) {
+	for namespace := range w.namespaces[gvk] {
+		w.run(gvk, gvr, namespace)
+	}
+}
+
+// run runs the informer of the metadata of the resources labeled by ODLM in the namespace until the manager stops
+func (w *resourceWatcher) run(gvk schema.GroupVersionKind, gvr schema.GroupVersionResource, namespace string) {
+	informer := metadatainformer.NewFilteredMetadataInformer(w.client, gvr, namespace, 0, cache.Indexers{
+		requestIndex: indexRequests,
+	}, func(options *metav1.ListOptions) {
+		options.LabelSelector = constant.OpreqLabel + "=true"
+	}).Informer()
+	if w.informers[gvk] == nil {
+		w.informers[gvk] = make(map[string]cache.SharedIndexInformer)
+		w.stops[gvk] = make(map[string]context.CancelFunc)
+	}
+	w.informers[gvk][namespace] = informer
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldChanged, ok := oldObj.(client.Object)
+			if !ok {
+				return
+			}
+			changed, ok := newObj.(client.Object)
+			if !ok || changed.GetGeneration() == oldChanged.GetGeneration() {
+				return
+			}
+			w.send(changed)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			deleted, ok := obj.(client.Object)
+			if !ok || !recreateDeleted(deleted) {
+				return
+			}
+			w.send(deleted)
+		},
+	})
+	ctx, stop := context.WithCancel(w.ctx)
+	w.stops[gvk][namespace] = stop
+	go informer.Run(ctx.Done())
+}
+
+// send sends the object to the OperandRequest controller
+func (w *resourceWatcher) send(obj client.Object) {
+	select {
+	case w.events <- event.GenericEvent{Object: obj}:
+	case <-w.ctx.Done():
+	}
+}
+
+// sendAfter sends the object to the OperandRequest controller after the delay,
+// to reconcile it again when nothing else changes in the meantime
+func (w *resourceWatcher) sendAfter(obj client.Object, delay time.Duration) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	started := w.ctx != nil
+	w.mu.Unlock()
+	if !started {
+		return
+	}
+	time.AfterFunc(delay, func() {
+		w.send(obj)
+	})
+}
+
+// requestAnnotation returns the annotation mapping the objects created by ODLM back to the OperandRequest
+func requestAnnotation(requestInstance *operatorv1alpha1.OperandRequest) map[string]string {
+	return ownership.Annotations(types.NamespacedName{Namespace: requestInstance.Namespace, Name: requestInstance.Name})
+}
+
+// requestLabel returns the label selecting the objects created by ODLM for the OperandRequest in all the namespaces
+func requestLabel(requestInstance *operatorv1alpha1.OperandRequest) map[string]string {
+	return ownership.Labels(types.NamespacedName{Namespace: requestInstance.Namespace, Name: requestInstance.Name})
+}
@@ -0,0 +1,257 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	operatorsv1 "github.com/operator-framework/operator-lifecycle-manager/pkg/package-server/apis/operators/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	odlmutil "github.com/IBM/operand-deployment-lifecycle-manager/pkg/util/v1"
+)
+
+// replacesChainGracePeriod is how long the replacement chain must stay broken before ODLM steps the Subscription
+// to an intermediate channel, OLM resolves a channel switch well within it
+const replacesChainGracePeriod = 2 * time.Minute
+
+// subscriptionResolutionFailed is the condition set by OLM when it can't resolve the Subscription
+const subscriptionResolutionFailed olmv1alpha1.SubscriptionConditionType = "ResolutionFailed"
+
+// reconcileReplacesChain detects the channel of the Subscription whose replacement chain skips the installed CSV,
+// so OLM can't upgrade the operator to it. It reports the intermediate channels to upgrade through in a
+// ReplacesChainBroken condition, and steps the Subscription through them when the operator has AutoStepChannels.
+// sub has the channel the Subscription is switched to, fromChannel is its current channel.
+func (r *Reconciler) reconcileReplacesChain(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, opt *operatorv1alpha1.Operator, sub *olmv1alpha1.Subscription, fromChannel string, mu sync.Locker) error {
+	log := logging.FromContext(ctx)
+
+	if stepping := sub.Annotations[constant.SteppingChannelAnnotation]; stepping != "" {
+		if opt.AutoStepChannels && stepping != sub.Spec.Channel {
+			reached, err := r.reachedChannelHead(ctx, sub, stepping)
+			if err != nil {
+				return err
+			}
+			if !reached {
+				// Hold the Subscription in the intermediate channel until the head of the channel is installed
+				sub.Spec.Channel = stepping
+				return nil
+			}
+			log.Info("Stepped operator through the intermediate channel", "operator", opt.Name, "channel", stepping)
+		}
+		delete(sub.Annotations, constant.SteppingChannelAnnotation)
+		return nil
+	}
+
+	installedCSV := sub.Status.InstalledCSV
+	stuck := sub.Status.State == olmv1alpha1.SubscriptionStateAtLatest || sub.Status.GetCondition(subscriptionResolutionFailed).Status == corev1.ConditionTrue
+	if sub.Spec.Channel != fromChannel || installedCSV == "" || sub.Status.CurrentCSV != installedCSV || !stuck {
+		requestInstance.RemoveReplacesChainBrokenCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+		return nil
+	}
+
+	channels, err := r.getPackageChannels(ctx, sub)
+	if err != nil {
+		return err
+	}
+	var heads []odlmutil.ChannelHead
+	var target *odlmutil.ChannelHead
+	for _, channel := range channels {
+		head := odlmutil.ChannelHead{Channel: channel.Name, CSV: channel.CurrentCSV, Version: channel.CurrentCSVDesc.Version.Version}
+		heads = append(heads, head)
+		if channel.Name == sub.Spec.Channel {
+			target = &head
+		}
+	}
+	if target == nil || target.CSV == installedCSV {
+		requestInstance.RemoveReplacesChainBrokenCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+		return nil
+	}
+
+	csv, err := r.GetClusterServiceVersion(ctx, sub)
+	if err != nil || csv == nil {
+		return err
+	}
+	installed := csv.Spec.Version.Version
+	if !installed.LT(target.Version) {
+		requestInstance.RemoveReplacesChainBrokenCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+		return nil
+	}
+
+	intermediates := odlmutil.IntermediateChannels(heads, installed, target.Version)
+	var steps []string
+	for _, head := range intermediates {
+		steps = append(steps, head.Channel+" ("+head.Version.String()+")")
+	}
+	detected, isNew := requestInstance.SetReplacesChainBrokenCondition(opt.Name, sub.Spec.Channel, installedCSV, steps, operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu)
+	if isNew {
+		log.Info("The channel of operator doesn't replace the installed CSV", "operator", opt.Name, "channel", sub.Spec.Channel, "installedCSV", installedCSV, "intermediateChannels", steps)
+		r.Recorder.Eventf(requestInstance, corev1.EventTypeWarning, constant.EventReasonReplacesChainBroken, "The channel %s of operator %s doesn't replace the installed CSV %s", sub.Spec.Channel, opt.Name, installedCSV)
+	}
+	if !opt.AutoStepChannels || len(intermediates) == 0 {
+		return nil
+	}
+	if wait := replacesChainGracePeriod - time.Since(detected); wait > 0 {
+		r.watcher.sendAfter(sub.DeepCopy(), wait)
+		return nil
+	}
+
+	next := intermediates[0]
+	sub.Spec.Channel = next.Channel
+	sub.Annotations[constant.SteppingChannelAnnotation] = next.Channel
+	r.Recorder.Eventf(requestInstance, corev1.EventTypeNormal, constant.EventReasonUpgrading, "Stepped operator %s to the intermediate channel %s, the channel %s doesn't replace the installed CSV %s", opt.Name, next.Channel, fromChannel, installedCSV)
+	return nil
+}
+
+// reachedChannelHead returns true when the Subscription installed the CSV at the head of the channel,
+// or the channel isn't in the package anymore
+func (r *Reconciler) reachedChannelHead(ctx context.Context, sub *olmv1alpha1.Subscription, channelName string) (bool, error) {
+	channels, err := r.getPackageChannels(ctx, sub)
+	if err != nil {
+		return false, err
+	}
+	for _, channel := range channels {
+		if channel.Name == channelName {
+			return sub.Status.InstalledCSV == channel.CurrentCSV, nil
+		}
+	}
+	return true, nil
+}
+
+// checkUpgradePath verifies that OLM can upgrade the installed CSV through the channel the Subscription is switched to,
+// before it is switched. When no CSV of the channel upgrades from the installed CSV, the Subscription is switched to the
+// first intermediate channel when the operator has AutoStepChannels, or kept on its channel with an UpgradeBlocked condition
+// naming the missing edge. It returns true when the Subscription is kept on its channel.
+// sub has the channel the Subscription is switched to, fromChannel is its current channel.
+func (r *Reconciler) checkUpgradePath(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, opt *operatorv1alpha1.Operator, sub *olmv1alpha1.Subscription, fromChannel string, mu sync.Locker) (bool, error) {
+	log := logging.FromContext(ctx)
+
+	installedCSV := sub.Status.InstalledCSV
+	if installedCSV == "" {
+		requestInstance.RemoveUpgradeBlockedCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+		return false, nil
+	}
+
+	channels, err := r.getPackageChannels(ctx, sub)
+	if err != nil {
+		return false, err
+	}
+	var heads []odlmutil.ChannelHead
+	var target *odlmutil.ChannelHead
+	var skipRange string
+	for _, channel := range channels {
+		head := odlmutil.ChannelHead{Channel: channel.Name, CSV: channel.CurrentCSV, Version: channel.CurrentCSVDesc.Version.Version}
+		heads = append(heads, head)
+		if channel.Name == sub.Spec.Channel {
+			target = &head
+			skipRange = channel.CurrentCSVDesc.Annotations[odlmutil.SkipRangeAnnotation]
+		}
+	}
+	if target == nil {
+		requestInstance.RemoveUpgradeBlockedCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+		return false, nil
+	}
+
+	entries, err := r.getChannelEntries(ctx, sub)
+	if err != nil {
+		return false, err
+	}
+	oldest, verified := odlmutil.MissingUpgradeEdge(installedCSV, entries[target.Channel], skipRange)
+	if !verified || oldest == "" {
+		if !verified {
+			log.V(logging.LevelDebug).Info("The PackageManifest doesn't list the CSVs of the channel, skip verifying the upgrade path", "channel", target.Channel, "package", sub.Spec.Package)
+		}
+		requestInstance.RemoveUpgradeBlockedCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+		return false, nil
+	}
+
+	var intermediates []odlmutil.ChannelHead
+	if csv, err := r.GetClusterServiceVersion(ctx, sub); err != nil {
+		return false, err
+	} else if csv != nil {
+		intermediates = odlmutil.IntermediateChannels(heads, csv.Spec.Version.Version, target.Version)
+	}
+	if opt.AutoStepChannels && len(intermediates) != 0 {
+		next := intermediates[0]
+		log.Info("The channel of operator has no upgrade path from the installed CSV, stepping through the intermediate channel", "channel", target.Channel, "installedCSV", installedCSV, "intermediateChannel", next.Channel)
+		sub.Spec.Channel = next.Channel
+		sub.Annotations[constant.SteppingChannelAnnotation] = next.Channel
+		requestInstance.RemoveUpgradeBlockedCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+		r.Recorder.Eventf(requestInstance, corev1.EventTypeNormal, constant.EventReasonUpgrading, "Stepped operator %s to the intermediate channel %s, the channel %s has no upgrade path from the installed CSV %s", opt.Name, next.Channel, target.Channel, installedCSV)
+		return false, nil
+	}
+
+	var steps []string
+	for _, head := range intermediates {
+		steps = append(steps, head.Channel+" ("+head.Version.String()+")")
+	}
+	log.Info("Blocked the upgrade of operator, the channel has no upgrade path from the installed CSV", "channel", target.Channel, "fromChannel", fromChannel, "missingEdge", installedCSV+" -> "+oldest, "intermediateChannels", steps)
+	if requestInstance.SetUpgradeBlockedCondition(opt.Name, target.Channel, installedCSV, oldest, steps, operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu) {
+		r.Recorder.Eventf(requestInstance, corev1.EventTypeWarning, constant.EventReasonUpgradeBlocked, "The channel %s of operator %s has no upgrade path from the installed CSV %s, the edge to %s is missing", target.Channel, opt.Name, installedCSV, oldest)
+	}
+	sub.Spec.Channel = fromChannel
+	return true, nil
+}
+
+// getChannelEntries returns the CSVs of the channels of the package of the Subscription, from the entries of its
+// PackageManifest. The typed PackageManifest of this OLM version doesn't have them, and the older catalogs don't list them.
+func (r *Reconciler) getChannelEntries(ctx context.Context, sub *olmv1alpha1.Subscription) (map[string][]string, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(operatorsv1.SchemeGroupVersion.WithKind(operatorsv1.PackageManifestListKind))
+	opts := []client.ListOption{
+		client.MatchingFields{"metadata.name": sub.Spec.Package},
+		client.InNamespace(sub.Namespace),
+	}
+	if err := r.Reader.List(ctx, list, opts...); err != nil {
+		return nil, errors.Wrapf(err, "failed to list the PackageManifest %s", sub.Spec.Package)
+	}
+	entries := make(map[string][]string)
+	for _, pm := range list.Items {
+		source, _, _ := unstructured.NestedString(pm.Object, "status", "catalogSource")
+		sourceNamespace, _, _ := unstructured.NestedString(pm.Object, "status", "catalogSourceNamespace")
+		if source != sub.Spec.CatalogSource || sourceNamespace != sub.Spec.CatalogSourceNamespace {
+			continue
+		}
+		channels, _, _ := unstructured.NestedSlice(pm.Object, "status", "channels")
+		for _, c := range channels {
+			channel, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(channel, "name")
+			items, _, _ := unstructured.NestedSlice(channel, "entries")
+			for _, item := range items {
+				entry, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if csv, _, _ := unstructured.NestedString(entry, "name"); csv != "" {
+					entries[name] = append(entries[name], csv)
+				}
+			}
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,77 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/scaling"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// selectReplicas returns the replicas of the service requested by the OperandRequests of the registry.
+// The custom resources of the service are shared by the requests, so the highest replicas apply.
+// It is nil when no request sets the replicas.
+func (r *Reconciler) selectReplicas(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryKey types.NamespacedName, operand operatorv1alpha1.Operand, service *operatorv1alpha1.ConfigService) (*int32, error) {
+	if service.Scaling == nil {
+		if operand.Replicas != nil {
+			return nil, errors.Errorf("the service %s declares no scaling in the OperandConfig, its replicas can't be set", service.Name)
+		}
+		return nil, nil
+	}
+	if operand.Replicas != nil {
+		if err := scaling.Validate(service.Scaling, *operand.Replicas); err != nil {
+			return nil, errors.Wrapf(err, "failed to scale the service %s", service.Name)
+		}
+	}
+	shared, err := r.listSharedOperands(ctx, requestInstance, registryKey, operand.Name)
+	if err != nil {
+		return nil, err
+	}
+	requested := []*int32{operand.Replicas}
+	for _, o := range shared {
+		// The replicas out of the range fail the operands of the other requests, they aren't applied
+		if o.Replicas != nil && scaling.Validate(service.Scaling, *o.Replicas) == nil {
+			requested = append(requested, o.Replicas)
+		}
+	}
+	return scaling.Highest(requested), nil
+}
+
+// renderReplicas returns a copy of the service with the replicas rendered into its custom resources.
+// The service is returned unchanged when no replicas are requested.
+func renderReplicas(ctx context.Context, service *operatorv1alpha1.ConfigService, replicas *int32) (*operatorv1alpha1.ConfigService, error) {
+	if service.Scaling == nil || replicas == nil {
+		return service, nil
+	}
+	logging.FromContext(ctx).V(logging.LevelDebug).Info("Rendering the replicas of the service", "service", service.Name, "replicas", *replicas)
+
+	rendered := service.DeepCopy()
+	for kind, spec := range rendered.Spec {
+		data, err := scaling.RenderCustomResource(kind, spec.Raw, service.Scaling, *replicas)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render the replicas of the service %s", service.Name)
+		}
+		rendered.Spec[kind] = runtime.RawExtension{Raw: data}
+	}
+	return rendered, nil
+}
@@ -0,0 +1,85 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/crdschema"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// validateRenderedSchema validates the spec of a rendered custom resource against the OpenAPI schema of its CRD
+// before it is applied, when the admission webhooks can't validate the OperandConfigs and the OperandRequests.
+// The member fails with a SchemaValidationFailed condition listing the invalid fields. The custom resources
+// whose CRD isn't found, like the ones waiting for it, are applied without validation.
+func (r *Reconciler) validateRenderedSchema(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, operandName string, cr unstructured.Unstructured) error {
+	if !r.ValidateSchemas {
+		return nil
+	}
+	resource := cr.GetKind() + " " + cr.GetNamespace() + "/" + cr.GetName()
+	specSchema, err := crdschema.GetSpecSchema(ctx, r.Reader, r.Discovery, cr.GetAPIVersion(), cr.GetKind())
+	if err != nil {
+		return err
+	}
+	allErrs, err := validateRenderedSpec(specSchema, cr)
+	if err != nil {
+		return err
+	}
+	if len(allErrs) == 0 {
+		requestInstance.RemoveSchemaValidationFailedCondition(resource, operatorv1alpha1.ResourceTypeOperand, &r.Mutex)
+		return nil
+	}
+
+	message := schemaErrorsMessage(resource, allErrs)
+	logging.FromContext(ctx).Info("The rendered custom resource doesn't match the schema of its CRD", "operand", operandName, "kind", cr.GetKind(), "name", cr.GetNamespace()+"/"+cr.GetName(), "errors", allErrs.ToAggregate().Error())
+	requestInstance.SetSchemaValidationFailedCondition(resource, message, operatorv1alpha1.ResourceTypeOperand, corev1.ConditionTrue, &r.Mutex)
+	r.Recorder.Event(requestInstance, corev1.EventTypeWarning, constant.EventReasonSchemaValidationFailed, message)
+	return errors.New(message)
+}
+
+// validateRenderedSpec returns the fields of the custom resource spec which don't match the schema, with their path
+func validateRenderedSpec(specSchema *apiextensionsv1.JSONSchemaProps, cr unstructured.Unstructured) (field.ErrorList, error) {
+	if specSchema == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(cr.Object["spec"])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal the spec of the custom resource %s %s/%s", cr.GetKind(), cr.GetNamespace(), cr.GetName())
+	}
+	return crdschema.ValidateSpec(specSchema, &runtime.RawExtension{Raw: raw}, field.NewPath("spec")), nil
+}
+
+// schemaErrorsMessage returns the message of the SchemaValidationFailed condition, one invalid field per sentence
+func schemaErrorsMessage(resource string, allErrs field.ErrorList) string {
+	messages := make([]string, 0, len(allErrs))
+	for _, err := range allErrs {
+		messages = append(messages, err.Error())
+	}
+	return "The custom resource " + resource + " doesn't match the schema of its CRD: " + strings.Join(messages, "; ")
+}
@@ -0,0 +1,131 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	odlmutil "github.com/IBM/operand-deployment-lifecycle-manager/pkg/util/v1"
+)
+
+// selectSizeProfile returns the size profile of the service selected by the OperandRequests of the registry.
+// The CRs of the service are shared by the requests, so the largest profile they select applies, and the default
+// profile applies when none selects one. A profile unknown to the service fails the operand of the request selecting it.
+func (r *Reconciler) selectSizeProfile(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryKey types.NamespacedName, operand operatorv1alpha1.Operand, service *operatorv1alpha1.ConfigService) (*operatorv1alpha1.SizeProfile, error) {
+	if operand.SizeProfile != "" && service.GetSizeProfile(operand.SizeProfile) == nil {
+		return nil, errors.Errorf("the service %s has no size profile %s", service.Name, operand.SizeProfile)
+	}
+	selected := []string{operand.SizeProfile}
+	if len(service.SizeProfiles) > 0 {
+		shared, err := r.listSharedOperands(ctx, requestInstance, registryKey, operand.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range shared {
+			selected = append(selected, o.SizeProfile)
+		}
+	}
+	p := largestSizeProfile(service, selected)
+	if p == nil && service.DefaultSizeProfile != "" {
+		if p = service.GetSizeProfile(service.DefaultSizeProfile); p == nil {
+			return nil, errors.Errorf("the service %s has no default size profile %s", service.Name, service.DefaultSizeProfile)
+		}
+	}
+	return p, nil
+}
+
+// listSharedOperands lists the operands of the other OperandRequests of the registry sharing the custom resources
+// of the service in the OperandConfig with the request
+func (r *Reconciler) listSharedOperands(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, registryKey types.NamespacedName, operandName string) ([]operatorv1alpha1.Operand, error) {
+	requests, err := r.ListOperandRequestsByRegistry(ctx, registryKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the OperandRequests of OperandRegistry %s", registryKey.String())
+	}
+	var operands []operatorv1alpha1.Operand
+	for _, request := range requests {
+		// The request being reconciled is the latest version of itself, and the deleted requests release the service
+		if (request.Namespace == requestInstance.Namespace && request.Name == requestInstance.Name) || request.DeletionTimestamp != nil {
+			continue
+		}
+		for _, req := range request.Spec.Requests {
+			if request.GetRegistryKey(req) != registryKey {
+				continue
+			}
+			for _, o := range req.Operands {
+				if o.Name == operandName && o.Kind == "" {
+					operands = append(operands, o)
+				}
+			}
+		}
+	}
+	return operands, nil
+}
+
+// largestSizeProfile returns the profile declared last in the service among the names, the unknown names are ignored
+func largestSizeProfile(service *operatorv1alpha1.ConfigService, names []string) *operatorv1alpha1.SizeProfile {
+	var largest *operatorv1alpha1.SizeProfile
+	for i := range service.SizeProfiles {
+		for _, name := range names {
+			if service.SizeProfiles[i].Name == name {
+				largest = &service.SizeProfiles[i]
+			}
+		}
+	}
+	return largest
+}
+
+// renderSizeProfile returns a copy of the service with the size profile merged into the specs of its custom resources.
+// The settings of the profile take precedence, the kinds which aren't in the spec of the service are ignored.
+func renderSizeProfile(ctx context.Context, service *operatorv1alpha1.ConfigService, p *operatorv1alpha1.SizeProfile) (*operatorv1alpha1.ConfigService, error) {
+	if p == nil {
+		return service, nil
+	}
+	log := logging.FromContext(ctx)
+	log.V(logging.LevelDebug).Info("Rendering the size profile of the service", "service", service.Name, "profile", p.Name)
+
+	rendered := service.DeepCopy()
+	for kind, sizing := range p.Spec {
+		target := ""
+		for k := range rendered.Spec {
+			if k == kind || (target == "" && strings.EqualFold(k, kind)) {
+				target = k
+			}
+		}
+		if target == "" {
+			log.V(logging.LevelDebug).Info("Skip the custom resource of the size profile which isn't in the service", "service", service.Name, "profile", p.Name, "kind", kind)
+			continue
+		}
+		merged, err := odlmutil.Merge(rendered.Spec[target].Raw, sizing.Raw, odlmutil.MergeStrategyDeep)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to merge the size profile %s into the custom resource %s of the service %s", p.Name, target, service.Name)
+		}
+		raw, err := json.Marshal(merged)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal the custom resource %s of the service %s", target, service.Name)
+		}
+		rendered.Spec[target] = runtime.RawExtension{Raw: raw}
+	}
+	return rendered, nil
+}
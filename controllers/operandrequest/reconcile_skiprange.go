@@ -0,0 +1,215 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	odlmutil "github.com/IBM/operand-deployment-lifecycle-manager/pkg/util/v1"
+)
+
+// reconcileUpgradePath records how OLM upgraded the operator in the annotations of the Subscription and the member
+// status of the request. When the operator denies the skip range, the InstallPlans are approved by ODLM,
+// only for the CSV replacing the installed one.
+func (r *Reconciler) reconcileUpgradePath(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, opt *operatorv1alpha1.Operator, sub *olmv1alpha1.Subscription, mu sync.Locker) error {
+	log := logging.FromContext(ctx)
+
+	installed := sub.Status.InstalledCSV
+	if previous := sub.Annotations[constant.InstalledCSVAnnotation]; previous != "" && installed != "" && previous != installed {
+		csv, err := r.getCSVUpgrade(ctx, sub.Namespace, installed)
+		if err != nil || csv == nil {
+			// Keep the previous CSV until the new one is found
+			return err
+		}
+		path := csv.pathFrom(previous)
+		log.Info("Operator is upgraded", "from", previous, "to", installed, "path", path)
+		sub.Annotations[constant.UpgradedFromAnnotation] = previous
+		sub.Annotations[constant.UpgradePathAnnotation] = path
+		if path == "" {
+			path = "an unknown path"
+		}
+		r.Recorder.Eventf(requestInstance, corev1.EventTypeNormal, constant.EventReasonUpgradePath, "Upgraded operator %s from %s to %s through %s", opt.Name, previous, installed, path)
+	}
+	if installed != "" {
+		sub.Annotations[constant.InstalledCSVAnnotation] = installed
+	}
+	if from := sub.Annotations[constant.UpgradedFromAnnotation]; from != "" {
+		requestInstance.SetMemberUpgrade(opt.Name, from, sub.Annotations[constant.InstalledCSVAnnotation], sub.Annotations[constant.UpgradePathAnnotation], mu)
+	}
+
+	// The pin takes over the approval of the InstallPlans from the skip range check
+	pinned, err := r.reconcilePin(ctx, requestInstance, opt, sub, mu)
+	if err != nil || pinned {
+		requestInstance.RemoveSkipRangeDeniedCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+		return err
+	}
+	if !opt.DeniesSkipRange() {
+		requestInstance.RemoveSkipRangeDeniedCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+		return nil
+	}
+	// OLM would install the upgrades through the skip range before ODLM checks them
+	sub.Spec.InstallPlanApproval = olmv1alpha1.ApprovalManual
+
+	ip, err := r.getPendingInstallPlan(ctx, sub)
+	if err != nil || ip == nil {
+		if ip == nil {
+			requestInstance.RemoveSkipRangeDeniedCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+		}
+		return err
+	}
+	if installed != "" {
+		csv, err := getInstallPlanCSV(ip, sub.Status.CurrentCSV)
+		if err != nil || csv == nil {
+			// OLM is still computing the steps of the InstallPlan
+			return err
+		}
+		if csv.pathFrom(installed) != odlmutil.UpgradePathReplaces {
+			if !hasCondition(requestInstance, operatorv1alpha1.ConditionSkipRangeDenied, mu) {
+				r.Recorder.Eventf(requestInstance, corev1.EventTypeWarning, constant.EventReasonSkipRangeDenied, "InstallPlan %s upgrades operator %s from %s to %s skipping versions, the OperandRegistry doesn't allow the skip range", ip.Name, opt.Name, installed, csv.Metadata.Name)
+			}
+			requestInstance.SetSkipRangeDeniedCondition(opt.Name, ip.Name, installed, csv.Metadata.Name, operatorv1alpha1.ResourceTypeOperator, corev1.ConditionTrue, mu)
+			return nil
+		}
+	}
+	requestInstance.RemoveSkipRangeDeniedCondition(opt.Name, operatorv1alpha1.ResourceTypeOperator, mu)
+	// The InstallPlans of the operators approved manually in the OperandRegistry are left to the user
+	if opt.InstallPlanApproval == olmv1alpha1.ApprovalManual {
+		return nil
+	}
+	return r.approveInstallPlan(ctx, ip, sub.Status.CurrentCSV)
+}
+
+// validateSkipRange checks the olm.skipRange of the head of the channel the Subscription is switched to
+// against the allowSkipRange of the operator, and records an Event when they don't match
+func (r *Reconciler) validateSkipRange(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, opt *operatorv1alpha1.Operator, sub *olmv1alpha1.Subscription) error {
+	if opt.AllowSkipRange == nil {
+		return nil
+	}
+	channels, err := r.getPackageChannels(ctx, sub)
+	if err != nil {
+		return err
+	}
+	for _, channel := range channels {
+		if channel.Name != sub.Spec.Channel {
+			continue
+		}
+		skipRange := channel.CurrentCSVDesc.Annotations[odlmutil.SkipRangeAnnotation]
+		switch {
+		case *opt.AllowSkipRange && skipRange == "":
+			r.Recorder.Eventf(requestInstance, corev1.EventTypeWarning, constant.EventReasonSkipRange, "The head CSV %s of channel %s doesn't declare %s, operator %s is upgraded through the replaces only", channel.CurrentCSV, channel.Name, odlmutil.SkipRangeAnnotation, opt.Name)
+		case !*opt.AllowSkipRange && channel.CurrentCSV != sub.Status.InstalledCSV && odlmutil.InSkipRange(skipRange, sub.Status.InstalledCSV):
+			r.Recorder.Eventf(requestInstance, corev1.EventTypeWarning, constant.EventReasonSkipRange, "The head CSV %s of channel %s skips the installed CSV %s with %s %s, operator %s is only upgraded through the replaces", channel.CurrentCSV, channel.Name, sub.Status.InstalledCSV, odlmutil.SkipRangeAnnotation, skipRange, opt.Name)
+		}
+	}
+	return nil
+}
+
+// getPendingInstallPlan returns the InstallPlan of the Subscription waiting for an approval, if any
+func (r *Reconciler) getPendingInstallPlan(ctx context.Context, sub *olmv1alpha1.Subscription) (*olmv1alpha1.InstallPlan, error) {
+	ref := sub.Status.InstallPlanRef
+	if ref == nil || ref.Name == "" {
+		return nil, nil
+	}
+	ip := &olmv1alpha1.InstallPlan{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: sub.Namespace, Name: ref.Name}, ip); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the InstallPlan %s/%s", sub.Namespace, ref.Name)
+	}
+	if ip.Spec.Approved || ip.Spec.Approval != olmv1alpha1.ApprovalManual {
+		return nil, nil
+	}
+	return ip, nil
+}
+
+// csvUpgrade is the upgrade graph of a CSV, the typed CSV of this OLM API version doesn't have the skips
+type csvUpgrade struct {
+	Metadata struct {
+		Name        string            `json:"name"`
+		Annotations map[string]string `json:"annotations,omitempty"`
+	} `json:"metadata"`
+	Spec struct {
+		Replaces string   `json:"replaces,omitempty"`
+		Skips    []string `json:"skips,omitempty"`
+	} `json:"spec"`
+}
+
+// pathFrom returns the path from the previous CSV to the CSV
+func (c *csvUpgrade) pathFrom(previous string) string {
+	return odlmutil.GetUpgradePath(c.Spec.Replaces, c.Spec.Skips, c.Metadata.Annotations[odlmutil.SkipRangeAnnotation], previous)
+}
+
+// getCSVUpgrade returns the upgrade graph of the CSV, it is read once per upgrade without the cache
+func (r *Reconciler) getCSVUpgrade(ctx context.Context, namespace, name string) (*csvUpgrade, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(olmv1alpha1.SchemeGroupVersion.WithKind(olmv1alpha1.ClusterServiceVersionKind))
+	if err := r.Reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the ClusterServiceVersion %s/%s", namespace, name)
+	}
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	csv := &csvUpgrade{}
+	if err := json.Unmarshal(data, csv); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode the ClusterServiceVersion %s/%s", namespace, name)
+	}
+	return csv, nil
+}
+
+// getInstallPlanCSV returns the upgrade graph of the CSV installed by a step of the InstallPlan
+func getInstallPlanCSV(ip *olmv1alpha1.InstallPlan, name string) (*csvUpgrade, error) {
+	for _, step := range ip.Status.Plan {
+		if step == nil || step.Resource.Kind != olmv1alpha1.ClusterServiceVersionKind || step.Resource.Name != name {
+			continue
+		}
+		csv := &csvUpgrade{}
+		if err := json.Unmarshal([]byte(step.Resource.Manifest), csv); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode the ClusterServiceVersion %s of the InstallPlan %s/%s", name, ip.Namespace, ip.Name)
+		}
+		return csv, nil
+	}
+	return nil, nil
+}
+
+// hasCondition returns true if the request has a condition of the type
+func hasCondition(requestInstance *operatorv1alpha1.OperandRequest, t operatorv1alpha1.ConditionType, mu sync.Locker) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, c := range requestInstance.Status.Conditions {
+		if c.Type == t {
+			return true
+		}
+	}
+	return false
+}
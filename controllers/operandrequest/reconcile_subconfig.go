@@ -0,0 +1,178 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// applySubscriptionConfig merges the SubscriptionConfig of the operator into the config of the Subscription.
+// The config last applied is recorded in the annotation of the Subscription, so the fields removed from the
+// OperandRegistry are removed from the Subscription, while the fields set by others are kept.
+// OLM rolls the operator Deployments out when the config changes.
+func applySubscriptionConfig(sub *olmv1alpha1.Subscription, desired *olmv1alpha1.SubscriptionConfig) {
+	var lastApplied *olmv1alpha1.SubscriptionConfig
+	if recorded, ok := sub.Annotations[constant.SubscriptionConfigAnnotation]; ok {
+		lastApplied = &olmv1alpha1.SubscriptionConfig{}
+		if err := json.Unmarshal([]byte(recorded), lastApplied); err != nil {
+			lastApplied = nil
+		}
+	}
+	sub.Spec.Config = mergeSubscriptionConfig(sub.Spec.Config, lastApplied, desired)
+
+	if desired == nil {
+		delete(sub.Annotations, constant.SubscriptionConfigAnnotation)
+		return
+	}
+	recorded, err := json.Marshal(desired)
+	if err != nil {
+		return
+	}
+	if sub.Annotations == nil {
+		sub.Annotations = make(map[string]string)
+	}
+	sub.Annotations[constant.SubscriptionConfigAnnotation] = string(recorded)
+}
+
+// mergeSubscriptionConfig returns the current config with the fields of the desired config, and without
+// the fields last applied but no longer desired. The environment variables are merged by name.
+func mergeSubscriptionConfig(current, lastApplied, desired *olmv1alpha1.SubscriptionConfig) *olmv1alpha1.SubscriptionConfig {
+	merged := &olmv1alpha1.SubscriptionConfig{}
+	if current != nil {
+		merged = current.DeepCopy()
+	}
+	if lastApplied == nil {
+		lastApplied = &olmv1alpha1.SubscriptionConfig{}
+	}
+	if desired == nil {
+		desired = &olmv1alpha1.SubscriptionConfig{}
+	}
+
+	switch {
+	case desired.Selector != nil:
+		merged.Selector = desired.Selector.DeepCopy()
+	case lastApplied.Selector != nil:
+		merged.Selector = nil
+	}
+	switch {
+	case desired.NodeSelector != nil:
+		merged.NodeSelector = desired.NodeSelector
+	case lastApplied.NodeSelector != nil:
+		merged.NodeSelector = nil
+	}
+	switch {
+	case desired.Tolerations != nil:
+		merged.Tolerations = desired.Tolerations
+	case lastApplied.Tolerations != nil:
+		merged.Tolerations = nil
+	}
+	switch {
+	case desired.Resources != nil:
+		merged.Resources = desired.Resources.DeepCopy()
+	case lastApplied.Resources != nil:
+		merged.Resources = nil
+	}
+	switch {
+	case desired.EnvFrom != nil:
+		merged.EnvFrom = desired.EnvFrom
+	case lastApplied.EnvFrom != nil:
+		merged.EnvFrom = nil
+	}
+	switch {
+	case desired.Volumes != nil:
+		merged.Volumes = desired.Volumes
+	case lastApplied.Volumes != nil:
+		merged.Volumes = nil
+	}
+	switch {
+	case desired.VolumeMounts != nil:
+		merged.VolumeMounts = desired.VolumeMounts
+	case lastApplied.VolumeMounts != nil:
+		merged.VolumeMounts = nil
+	}
+
+	// The environment variables set by others are kept, the ones last applied are replaced by the desired ones
+	owned := make(map[string]bool)
+	for _, env := range lastApplied.Env {
+		owned[env.Name] = true
+	}
+	for _, env := range desired.Env {
+		owned[env.Name] = true
+	}
+	var envs []corev1.EnvVar
+	for _, env := range merged.Env {
+		if !owned[env.Name] {
+			envs = append(envs, env)
+		}
+	}
+	merged.Env = append(envs, desired.Env...)
+
+	if reflect.DeepEqual(*merged, olmv1alpha1.SubscriptionConfig{}) {
+		return nil
+	}
+	return merged
+}
+
+// reconcileOperatorPods sets the priority class and the affinity of the operator pods in the Deployments of the
+// ClusterServiceVersion, the config of the Subscription doesn't support them. The Deployments are only patched
+// when their priority class differs, or when they have no affinity.
+func (r *Reconciler) reconcileOperatorPods(ctx context.Context, csv *olmv1alpha1.ClusterServiceVersion, priorityClassName string, affinity *corev1.Affinity) error {
+	if priorityClassName == "" && affinity == nil {
+		return nil
+	}
+	for _, spec := range csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs {
+		deploy := &appsv1.Deployment{}
+		key := types.NamespacedName{Namespace: csv.Namespace, Name: spec.Name}
+		if err := r.Reader.Get(ctx, key, deploy); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return errors.Wrapf(err, "failed to get the Deployment %s of the ClusterServiceVersion %s", key.String(), csv.Name)
+		}
+		podSpec := &deploy.Spec.Template.Spec
+		setPriorityClass := priorityClassName != "" && podSpec.PriorityClassName != priorityClassName
+		setAffinity := affinity != nil && podSpec.Affinity == nil
+		if !setPriorityClass && !setAffinity {
+			continue
+		}
+		patch := client.MergeFrom(deploy.DeepCopy())
+		if setPriorityClass {
+			podSpec.PriorityClassName = priorityClassName
+		}
+		if setAffinity {
+			podSpec.Affinity = affinity.DeepCopy()
+		}
+		logging.FromContext(ctx).V(logging.LevelChange).Info("Setting the priority class and the affinity of the operator Deployment", "deployment", key.String(), "priorityClassName", priorityClassName, "affinity", setAffinity)
+		if err := r.Client.Patch(ctx, deploy, patch); err != nil {
+			return errors.Wrapf(err, "failed to set the priority class and the affinity of the Deployment %s", key.String())
+		}
+	}
+	return nil
+}
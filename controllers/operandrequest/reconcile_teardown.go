@@ -0,0 +1,167 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gset "github.com/deckarep/golang-set"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// teardownOperands deletes the custom resources and the k8s resources of all the operands uninstalled with the
+// deleted OperandRequest, before any operator is uninstalled, so that the operators can still remove the finalizers
+// of their custom resources. It waits for the custom resources to be removed until the teardown deadline.
+func (r *Reconciler) teardownOperands(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) error {
+	log := logging.FromContext(ctx)
+	needDeletedOperands, err := r.getNeedDeletedOperands(ctx, requestInstance)
+	if err != nil {
+		return err
+	}
+	retainedOperands, err := r.getRetainedOperands(ctx, requestInstance)
+	if err != nil {
+		return err
+	}
+	operands := needDeletedOperands.Difference(retainedOperands)
+	if operands.Cardinality() == 0 {
+		return nil
+	}
+	log.V(logging.LevelFlow).Info("Deleting the operands before the operators", "operands", strings.Trim(fmt.Sprint(operands.ToSlice()), "[]"), "deadline", requestInstance.GetTeardownDeadline().String())
+
+	group := util.NewGroup(0)
+	tornDown := gset.NewSet()
+	for _, req := range requestInstance.Spec.Requests {
+		registryKey := requestInstance.GetRegistryKey(req)
+		registryInstance, err := r.GetOperandRegistry(ctx, registryKey)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		configInstance, err := r.GetOperandConfig(ctx, registryKey)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+			configInstance = &operatorv1alpha1.OperandConfig{}
+		}
+		for o := range operands.Iter() {
+			op := registryInstance.GetOperator(fmt.Sprintf("%v", o))
+			if op == nil || tornDown.Contains(op.Name) {
+				continue
+			}
+			tornDown.Add(op.Name)
+			installer, err := r.getInstaller(op)
+			if err != nil {
+				return err
+			}
+			uninstaller, ok := installer.(OrderedUninstaller)
+			if !ok {
+				continue
+			}
+			group.Go(func() error {
+				// The shared custom resources of the OperandConfig are kept while another OperandRequest references
+				// the operator in the ledger, the custom resources created by this OperandRequest are deleted anyway
+				others, err := r.getOtherReferences(ctx, requestInstance, registryInstance, op.Name)
+				if err != nil {
+					return err
+				}
+				if len(others) != 0 {
+					log.V(logging.LevelFlow).Info("Operator is still referenced by other OperandRequests, keep its shared operands", "operator", op.Name, "requests", others)
+					return r.deleteRequestCustomResources(ctx, requestInstance, op.Name)
+				}
+				// The PreDelete callbacks are called before the custom resources are deleted
+				if err := r.notifyPreDelete(ctx, requestInstance, registryKey, op); err != nil {
+					return err
+				}
+				return r.teardownOperand(ctx, uninstaller, requestInstance, registryInstance, configInstance, op)
+			})
+		}
+	}
+	merr, timedOut := group.Wait(constant.DefaultSubDeleteTimeout)
+	if timedOut {
+		merr.Add(fmt.Errorf("timeout for deleting the operands %v", strings.Trim(fmt.Sprint(operands.ToSlice()), "[]")))
+	}
+	if len(merr.Errors) != 0 {
+		return merr
+	}
+	return nil
+}
+
+// teardownOperand deletes the custom resources and the k8s resources of the operand, if its Uninstall deletes them
+func (r *Reconciler) teardownOperand(ctx context.Context, uninstaller OrderedUninstaller, requestInstance *operatorv1alpha1.OperandRequest, registryInstance *operatorv1alpha1.OperandRegistry, configInstance *operatorv1alpha1.OperandConfig, op *operatorv1alpha1.Operator) error {
+	csv, err := uninstaller.GetUninstallCSV(ctx, registryInstance, op)
+	if err != nil || csv == nil {
+		return err
+	}
+	logging.FromContext(ctx).V(logging.LevelChange).Info("Deleting all the Custom Resources before the operator", "operator", op.Name)
+	if err := r.deleteAllCustomResource(ctx, csv, requestInstance, configInstance, op.Name, op.Namespace); err != nil {
+		return err
+	}
+	return r.deleteAllK8sResource(ctx, configInstance, op.Name, op.Namespace)
+}
+
+// getRetainedOperands returns the operands kept when the OperandRequest is deleted, with the operators they require
+func (r *Reconciler) getRetainedOperands(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest) (gset.Set, error) {
+	retained := gset.NewSet()
+	for _, req := range requestInstance.Spec.Requests {
+		var operands []operatorv1alpha1.Operand
+		for _, operand := range req.Operands {
+			if operand.GetDeletionPolicy() == operatorv1alpha1.DeletionPolicyRetain {
+				operands = append(operands, operand)
+			}
+		}
+		if len(operands) == 0 {
+			continue
+		}
+		registryInstance, err := r.GetOperandRegistry(ctx, requestInstance.GetRegistryKey(req))
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			registryInstance = &operatorv1alpha1.OperandRegistry{}
+		}
+		for _, operand := range registryInstance.WithRequiredOperands(operands) {
+			retained.Add(operand.Name)
+		}
+	}
+	return retained, nil
+}
+
+// getUninstallSubscriptionCSV returns the ClusterServiceVersion of the Subscription if it is uninstalled with the OperandRequest
+func (r *Reconciler) getUninstallSubscriptionCSV(ctx context.Context, registryInstance *operatorv1alpha1.OperandRegistry, op *operatorv1alpha1.Operator) (*olmv1alpha1.ClusterServiceVersion, error) {
+	sub, err := r.GetSubscription(ctx, op.Name, r.GetOperatorNamespace(op.InstallMode, op.Namespace), op.PackageName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if _, ok := sub.Labels[constant.OpreqLabel]; !ok || requestedWithOtherRegistries(sub.Annotations, registryInstance) {
+		return nil, nil
+	}
+	return r.GetClusterServiceVersion(ctx, sub)
+}
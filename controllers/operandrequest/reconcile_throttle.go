@@ -0,0 +1,124 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// reservationTimeout is how long a created Subscription is counted as installing
+// before it shows up in the cache.
+const reservationTimeout = time.Minute
+
+// installThrottle holds the Subscriptions created by ODLM which are not in the cache yet,
+// so that the parallel reconciliations don't exceed the limit of a CatalogSource.
+type installThrottle struct {
+	sync.Mutex
+	reservations map[string]reservation
+}
+
+// reservation is an install reserved from a CatalogSource.
+type reservation struct {
+	catalog string
+	created time.Time
+}
+
+// reserveInstall reserves an install of the Subscription namespace/name from the CatalogSource.
+// It returns false and the limit of the CatalogSource when it is already installing as many Subscriptions as its limit.
+func (r *Reconciler) reserveInstall(ctx context.Context, namespace, name, sourceName, sourceNamespace string) (bool, int, error) {
+	limit := util.GetMaxParallelInstalls(sourceName, sourceNamespace)
+	if limit == 0 {
+		return true, 0, nil
+	}
+
+	r.throttle.Lock()
+	defer r.throttle.Unlock()
+
+	subList, err := r.ListManagedSubscriptions(ctx)
+	if err != nil {
+		return false, limit, errors.Wrap(err, "failed to list the Subscriptions managed by ODLM")
+	}
+	if r.throttle.reservations == nil {
+		r.throttle.reservations = make(map[string]reservation)
+	}
+
+	catalog := sourceNamespace + "/" + sourceName
+	installing := countInstalling(subList.Items, catalog, r.throttle.reservations, time.Now())
+	if installing >= limit {
+		logging.FromContext(ctx).V(logging.LevelChange).Info("CatalogSource is busy installing Subscriptions, throttle the Subscription", "catalog", catalog, "installing", installing, "subscription", namespace+"/"+name)
+
+		return false, limit, nil
+	}
+	r.throttle.reservations[namespace+"/"+name] = reservation{catalog: catalog, created: time.Now()}
+	return true, limit, nil
+}
+
+// releaseInstall releases the install reserved for the Subscription namespace/name when it is not created.
+func (r *Reconciler) releaseInstall(namespace, name string) {
+	r.throttle.Lock()
+	defer r.throttle.Unlock()
+	delete(r.throttle.reservations, namespace+"/"+name)
+}
+
+// countInstalling returns how many Subscriptions are being installed from the catalog, including the reserved ones.
+// The reservations of the Subscriptions found in the list and the expired ones are removed.
+func countInstalling(subs []olmv1alpha1.Subscription, catalog string, reservations map[string]reservation, now time.Time) int {
+	installing := 0
+	for i := range subs {
+		sub := &subs[i]
+		delete(reservations, sub.Namespace+"/"+sub.Name)
+		if sub.Spec == nil || sub.Spec.CatalogSourceNamespace+"/"+sub.Spec.CatalogSource != catalog {
+			continue
+		}
+		if isInstalling(sub) {
+			installing++
+		}
+	}
+	for key, res := range reservations {
+		if now.Sub(res.created) > reservationTimeout {
+			delete(reservations, key)
+			continue
+		}
+		if res.catalog == catalog {
+			installing++
+		}
+	}
+	return installing
+}
+
+// isInstalling returns true if OLM is resolving or installing the Subscription.
+// The Subscriptions waiting for a manual approval and the failed ones don't count.
+func isInstalling(sub *olmv1alpha1.Subscription) bool {
+	if sub.Status.InstalledCSV != "" {
+		return false
+	}
+	switch sub.Status.State {
+	case olmv1alpha1.SubscriptionStateFailed:
+		return false
+	case olmv1alpha1.SubscriptionStateUpgradePending:
+		return sub.Spec.InstallPlanApproval != olmv1alpha1.ApprovalManual
+	}
+	return true
+}
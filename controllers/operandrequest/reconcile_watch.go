@@ -0,0 +1,186 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/ownership"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// droppedWatch is the watch of a kind whose CRD is deleted
+type droppedWatch struct {
+	gvr schema.GroupVersionResource
+	// requests are the OperandRequests tracking the objects of the kind when its CRD was deleted
+	requests []types.NamespacedName
+}
+
+// crdName returns the name of the CRD serving the resource, empty for the built-in resources
+func crdName(gvr schema.GroupVersionResource) string {
+	if gvr.Group == "" {
+		return ""
+	}
+	return gvr.Resource + "." + gvr.Group
+}
+
+// watchCRDs runs the informer of the CRDs until the manager stops, to stop and start again the watches
+// of their kinds when they are deleted and established, and to create the custom resources waiting for them
+func (w *resourceWatcher) watchCRDs(ctx context.Context) {
+	informer := dynamicinformer.NewFilteredDynamicInformer(w.crds, apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions"), metav1.NamespaceAll, 0, cache.Indexers{}, nil).Informer()
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if crd, ok := establishedCRD(obj); ok {
+				w.crdEstablished(crd.Name, schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind})
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if _, ok := establishedCRD(oldObj); ok {
+				return
+			}
+			if crd, ok := establishedCRD(newObj); ok {
+				w.crdEstablished(crd.Name, schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind})
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if crd, err := meta.Accessor(obj); err == nil {
+				w.crdDeleted(crd.GetName())
+			}
+		},
+	})
+	go informer.Run(ctx.Done())
+}
+
+// establishedCRD returns the CRD of the informer object, and true if it is established
+func establishedCRD(obj interface{}) (*apiextensionsv1.CustomResourceDefinition, bool) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, false
+	}
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, crd); err != nil {
+		return nil, false
+	}
+	for _, c := range crd.Status.Conditions {
+		if c.Type == apiextensionsv1.Established {
+			return crd, c.Status == apiextensionsv1.ConditionTrue
+		}
+	}
+	return crd, false
+}
+
+// waitForCRD reconciles the OperandRequest again as soon as the CRD of the kind is established
+func (w *resourceWatcher) waitForCRD(gk schema.GroupKind, requestKey types.NamespacedName) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.waiting[gk] == nil {
+		w.waiting[gk] = make(map[types.NamespacedName]bool)
+	}
+	w.waiting[gk][requestKey] = true
+}
+
+// crdDeleted stops the watches of the kinds of the deleted CRD, the informers would fail to list them until it is back
+func (w *resourceWatcher) crdDeleted(name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for gvk, gvr := range w.watched {
+		if crdName(gvr) != name {
+			continue
+		}
+		dropped := droppedWatch{gvr: gvr}
+		seen := make(map[types.NamespacedName]bool)
+		for _, informer := range w.informers[gvk] {
+			for _, item := range informer.GetStore().List() {
+				obj, err := meta.Accessor(item)
+				if err != nil {
+					continue
+				}
+				for _, requestKey := range ownership.Requests(obj) {
+					if !seen[requestKey] {
+						seen[requestKey] = true
+						dropped.requests = append(dropped.requests, requestKey)
+					}
+				}
+			}
+		}
+		for _, stop := range w.stops[gvk] {
+			stop()
+		}
+		delete(w.watched, gvk)
+		delete(w.informers, gvk)
+		delete(w.stops, gvk)
+		w.dropped[gvk] = dropped
+		logging.Logger("operandrequest").Info("Stopped watching the kind, its CRD is deleted", "kind", gvk.String(), "crd", name)
+	}
+}
+
+// crdEstablished starts again the watches of the kinds of the established CRD, and reconciles the OperandRequests
+// which had objects of these kinds to create them again, and the ones whose custom resources wait for the CRD
+func (w *resourceWatcher) crdEstablished(name string, gk schema.GroupKind) {
+	w.mu.Lock()
+	var requests []types.NamespacedName
+	for gvk, dropped := range w.dropped {
+		if crdName(dropped.gvr) != name {
+			continue
+		}
+		delete(w.dropped, gvk)
+		w.watched[gvk] = dropped.gvr
+		w.runAll(gvk, dropped.gvr)
+		requests = append(requests, dropped.requests...)
+		logging.Logger("operandrequest").Info("Started watching the kind again, its CRD is established", "kind", gvk.String(), "crd", name)
+	}
+	for requestKey := range w.waiting[gk] {
+		requests = append(requests, requestKey)
+	}
+	if len(w.waiting[gk]) != 0 {
+		logging.Logger("operandrequest").Info("Creating the custom resources waiting for the established CRD", "kind", gk.String(), "crd", name)
+	}
+	delete(w.waiting, gk)
+	w.mu.Unlock()
+
+	for _, requestKey := range requests {
+		w.send(&metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Annotations: ownership.Annotations(requestKey)}})
+	}
+}
+
+// waitForCRD holds the creation of the custom resource of the operand until the CRD of its kind is established,
+// the operator may still be installing it. The OperandRequest is reconciled again when the CRD is established,
+// rather than after the backoff of a failed reconcile.
+func (r *Reconciler) waitForCRD(ctx context.Context, requestInstance *operatorv1alpha1.OperandRequest, operandName string, gvk schema.GroupVersionKind) error {
+	logging.FromContext(ctx).Info("The CRD of the custom resource is not established, waiting for it", "operand", operandName, "kind", gvk.String())
+	requestInstance.SetWaitingForCRDCondition(operandName, gvk.String(), operatorv1alpha1.ResourceTypeOperand, corev1.ConditionTrue, &r.Mutex)
+	r.watcher.waitForCRD(gvk.GroupKind(), types.NamespacedName{Namespace: requestInstance.Namespace, Name: requestInstance.Name})
+	return nil
+}
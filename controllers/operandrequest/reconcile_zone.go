@@ -0,0 +1,111 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/zone"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// renderZoneAware returns a copy of the service with the zone-aware settings rendered from the zones of the cluster.
+// The service is returned unchanged when it isn't zone-aware or the cluster has less than two zones.
+func (r *Reconciler) renderZoneAware(ctx context.Context, service *operatorv1alpha1.ConfigService) (*operatorv1alpha1.ConfigService, error) {
+	za := service.ZoneAware
+	if za == nil {
+		return service, nil
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := r.PagedList(ctx, nodeList); err != nil {
+		return nil, errors.Wrapf(err, "failed to list the nodes to detect the zones for the service %s", service.Name)
+	}
+	zones := zone.Detect(nodeList.Items)
+	if len(zones) < 2 {
+		logging.FromContext(ctx).V(logging.LevelChange).Info("Not enough zones in the cluster, skip rendering the zone-aware settings of the service", "zones", len(zones), "service", service.Name)
+		return service, nil
+	}
+
+	if za.StorageClass != "" {
+		if err := r.ensureZoneStorageClasses(ctx, za.StorageClass, zones); err != nil {
+			return nil, err
+		}
+	}
+
+	rendered := service.DeepCopy()
+	for i, res := range rendered.Resources {
+		if res.Data == nil {
+			continue
+		}
+		data, err := zone.RenderResource(res.Kind, res.Data.Raw, za)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render the zone-aware settings of the k8s resource %s/%s", res.Kind, res.Name)
+		}
+		rendered.Resources[i].Data = &runtime.RawExtension{Raw: data}
+	}
+	for kind, spec := range rendered.Spec {
+		data, err := zone.RenderCustomResource(kind, spec.Raw, za, zones)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render the zone-aware settings of the custom resource %s", kind)
+		}
+		rendered.Spec[kind] = runtime.RawExtension{Raw: data}
+	}
+	return rendered, nil
+}
+
+// ensureZoneStorageClasses creates the copies of the storage class pinned to each zone.
+// The existing copies are kept as they are, because the parameters of a StorageClass are immutable.
+// The copies are left behind on purpose when no service uses them anymore: the persistent volumes provisioned
+// from them outlive the OperandRequests, and can't be expanded or provisioned again without them.
+func (r *Reconciler) ensureZoneStorageClasses(ctx context.Context, name string, zones []string) error {
+	base := &storagev1.StorageClass{}
+	if err := r.Reader.Get(ctx, types.NamespacedName{Name: name}, base); err != nil {
+		return errors.Wrapf(err, "failed to get the StorageClass %s", name)
+	}
+
+	for _, sc := range zone.StorageClasses(base, zones) {
+		sc := sc
+		err := r.Reader.Get(ctx, types.NamespacedName{Name: sc.Name}, &storagev1.StorageClass{})
+		if err == nil {
+			continue
+		}
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get the StorageClass %s", sc.Name)
+		}
+		if sc.Labels == nil {
+			sc.Labels = make(map[string]string)
+		}
+		sc.Labels[constant.OpreqLabel] = "true"
+		sc.Labels[constant.ZoneStorageClassLabel] = name
+		if err := r.Create(ctx, &sc); err != nil && !apierrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to create the StorageClass %s", sc.Name)
+		}
+		logging.FromContext(ctx).V(logging.LevelChange).Info("Created the StorageClass pinned to the zone", "storageClass", sc.Name)
+
+	}
+	return nil
+}
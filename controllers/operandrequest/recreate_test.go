@@ -0,0 +1,64 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+)
+
+var _ = Describe("Recreate the deleted objects", func() {
+
+	newObject := func(labels, annotations map[string]string) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "ibm-common-services", Labels: labels, Annotations: annotations},
+		}
+	}
+
+	AfterEach(func() {
+		util.SetRecreatePolicy(operatorv1alpha1.RecreatePolicyImmediate)
+	})
+
+	It("Should map the deleted object to all the OperandRequests in its annotations", func() {
+		r := &Reconciler{}
+		obj := newObject(nil, map[string]string{
+			"ns1.request1/request":                  "true",
+			"ns2.request2/request":                  "true",
+			"ibm-common-services.registry/registry": "true",
+		})
+		Expect(r.getSubToRequestMapper()(obj)).Should(ConsistOf(
+			ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "request1"}},
+			ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns2", Name: "request2"}},
+		))
+	})
+
+	It("Should only recreate the objects created by ODLM with the Immediate policy", func() {
+		created := newObject(map[string]string{constant.OpreqLabel: "true"}, nil)
+		Expect(recreateDeleted(created)).Should(BeTrue())
+		Expect(recreateDeleted(newObject(nil, nil))).Should(BeFalse())
+
+		util.SetRecreatePolicy(operatorv1alpha1.RecreatePolicyResync)
+		Expect(recreateDeleted(created)).Should(BeFalse())
+	})
+})
@@ -0,0 +1,83 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Required operands", func() {
+
+	registry := &operatorv1alpha1.OperandRegistry{
+		Spec: operatorv1alpha1.OperandRegistrySpec{
+			Operators: []operatorv1alpha1.Operator{
+				{Name: "jenkins", Requires: []string{"etcd", "mongodb"}},
+				{Name: "etcd", Requires: []string{"cert-manager"}},
+				{Name: "mongodb", Requires: []string{"cert-manager", "jenkins"}},
+				{Name: "cert-manager"},
+			},
+		},
+	}
+
+	It("Should include the required operators transitively", func() {
+		requiredBy := registry.GetRequiredOperands([]operatorv1alpha1.Operand{{Name: "jenkins"}})
+		Expect(requiredBy).Should(Equal(map[string][]string{
+			"etcd":         {"jenkins"},
+			"mongodb":      {"jenkins"},
+			"cert-manager": {"jenkins"},
+		}))
+	})
+
+	It("Should not include the requested operands", func() {
+		requiredBy := registry.GetRequiredOperands([]operatorv1alpha1.Operand{{Name: "mongodb"}, {Name: "etcd"}})
+		Expect(requiredBy).Should(Equal(map[string][]string{
+			"cert-manager": {"etcd", "mongodb"},
+			"jenkins":      {"mongodb"},
+		}))
+	})
+
+	It("Should append the implicit operands after the requested operands", func() {
+		operands := registry.WithRequiredOperands([]operatorv1alpha1.Operand{{Name: "etcd", Kind: "EtcdCluster"}})
+		Expect(operands).Should(Equal([]operatorv1alpha1.Operand{
+			{Name: "etcd", Kind: "EtcdCluster"},
+			{Name: "cert-manager"},
+		}))
+		Expect(registry.WithRequiredOperands([]operatorv1alpha1.Operand{{Name: "cert-manager"}})).Should(HaveLen(1))
+	})
+
+	It("Should keep the implicit members in the status", func() {
+		request := &operatorv1alpha1.OperandRequest{
+			Spec: operatorv1alpha1.OperandRequestSpec{
+				Requests: []operatorv1alpha1.Request{{Registry: "common-service", Operands: []operatorv1alpha1.Operand{{Name: "etcd"}}}},
+			},
+		}
+		mu := &sync.Mutex{}
+		request.SetImplicitMembers(map[string][]string{"cert-manager": {"etcd"}}, mu)
+		request.FreshMemberStatus()
+		Expect(request.Status.Members).Should(HaveLen(1))
+		Expect(request.Status.Members[0].RequiredBy).Should(Equal([]string{"etcd"}))
+
+		request.SetImplicitMembers(map[string][]string{}, mu)
+		request.FreshMemberStatus()
+		Expect(request.Status.Members).Should(BeEmpty())
+	})
+})
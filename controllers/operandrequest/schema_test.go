@@ -0,0 +1,83 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Validate the rendered custom resources", func() {
+
+	specSchema := &apiextensionsv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"size": {Type: "integer"},
+			"pod": {
+				Type:       "object",
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{"antiAffinity": {Type: "boolean"}},
+			},
+		},
+	}
+	cr := func(spec map[string]interface{}) unstructured.Unstructured {
+		obj := unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		obj.SetKind("EtcdCluster")
+		obj.SetNamespace("ibm-common-services")
+		obj.SetName("example")
+		return obj
+	}
+
+	It("Should report the exact path of the invalid fields", func() {
+		allErrs, err := validateRenderedSpec(specSchema, cr(map[string]interface{}{"size": int64(3), "pod": map[string]interface{}{"antiAffinity": true}}))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(allErrs).Should(BeEmpty())
+
+		allErrs, err = validateRenderedSpec(specSchema, cr(map[string]interface{}{"size": "three", "pod": map[string]interface{}{"antiAffinity": true, "zone": "a"}}))
+		Expect(err).ShouldNot(HaveOccurred())
+		var fields []string
+		for _, e := range allErrs {
+			fields = append(fields, e.Field)
+		}
+		Expect(fields).Should(ConsistOf("spec.size", "spec.pod.zone"))
+		Expect(schemaErrorsMessage("EtcdCluster ibm-common-services/example", allErrs)).Should(ContainSubstring("spec.pod.zone: Forbidden"))
+
+		// The custom resources whose CRD isn't found are not validated
+		allErrs, err = validateRenderedSpec(nil, cr(map[string]interface{}{"size": "three"}))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(allErrs).Should(BeEmpty())
+	})
+
+	It("Should set and remove the SchemaValidationFailed condition of the custom resource", func() {
+		req := &operatorv1alpha1.OperandRequest{}
+		mu := &sync.Mutex{}
+		req.SetSchemaValidationFailedCondition("EtcdCluster ibm-common-services/example", "invalid", operatorv1alpha1.ResourceTypeOperand, corev1.ConditionTrue, mu)
+		req.SetSchemaValidationFailedCondition("EtcdCluster ibm-common-services/example", "still invalid", operatorv1alpha1.ResourceTypeOperand, corev1.ConditionTrue, mu)
+		Expect(req.Status.Conditions).Should(HaveLen(1))
+		Expect(req.Status.Conditions[0].Type).Should(Equal(operatorv1alpha1.ConditionSchemaValidationFailed))
+		Expect(req.Status.Conditions[0].Message).Should(Equal("still invalid"))
+
+		req.RemoveSchemaValidationFailedCondition("EtcdCluster ibm-common-services/example", operatorv1alpha1.ResourceTypeOperand, mu)
+		Expect(req.Status.Conditions).Should(BeEmpty())
+	})
+})
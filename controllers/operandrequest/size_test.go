@@ -0,0 +1,81 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Size profiles", func() {
+
+	service := &operatorv1alpha1.ConfigService{
+		Name: "ibm-etcd-operator",
+		Spec: map[string]runtime.RawExtension{
+			"etcdCluster": {Raw: []byte(`{"size":1,"storage":{"class":"fast"},"pod":{"resources":{"limits":{"cpu":"500m"}}}}`)},
+		},
+		SizeProfiles: []operatorv1alpha1.SizeProfile{
+			{Name: "small"},
+			{Name: "medium", Spec: map[string]runtime.RawExtension{"etcdCluster": {Raw: []byte(`{"size":3,"pod":{"resources":{"limits":{"cpu":"1"}}}}`)}}},
+			{Name: "large", Spec: map[string]runtime.RawExtension{
+				"EtcdCluster": {Raw: []byte(`{"size":5}`)},
+				"etcdBackup":  {Raw: []byte(`{"storageType":"S3"}`)},
+			}},
+		},
+	}
+
+	It("Should select the largest profile of the requests", func() {
+		Expect(largestSizeProfile(service, []string{"medium", "small"}).Name).Should(Equal("medium"))
+		Expect(largestSizeProfile(service, []string{"", "large", "medium", "unknown"}).Name).Should(Equal("large"))
+		Expect(largestSizeProfile(service, []string{""})).Should(BeNil())
+	})
+
+	It("Should merge the profile into the custom resources of the service", func() {
+		rendered, err := renderSizeProfile(context.TODO(), service, service.GetSizeProfile("medium"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(rendered.Spec["etcdCluster"].Raw).Should(MatchJSON(`{"size":3,"storage":{"class":"fast"},"pod":{"resources":{"limits":{"cpu":"1"}}}}`))
+		Expect(service.Spec["etcdCluster"].Raw).Should(MatchJSON(`{"size":1,"storage":{"class":"fast"},"pod":{"resources":{"limits":{"cpu":"500m"}}}}`))
+
+		rendered, err = renderSizeProfile(context.TODO(), service, service.GetSizeProfile("large"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(rendered.Spec).Should(HaveLen(1))
+		Expect(rendered.Spec["etcdCluster"].Raw).Should(MatchJSON(`{"size":5,"storage":{"class":"fast"},"pod":{"resources":{"limits":{"cpu":"500m"}}}}`))
+
+		rendered, err = renderSizeProfile(context.TODO(), service, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(rendered).Should(BeIdenticalTo(service))
+	})
+
+	It("Should render the replicas into the custom resources of the service", func() {
+		scaled := service.DeepCopy()
+		scaled.Scaling = &operatorv1alpha1.ScalingHints{CustomResources: []operatorv1alpha1.ScalingResource{{Kind: "EtcdCluster", ReplicasPath: "size"}}}
+		rendered, err := renderReplicas(context.TODO(), scaled, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(rendered).Should(BeIdenticalTo(scaled))
+
+		replicas := int32(4)
+		rendered, err = renderReplicas(context.TODO(), scaled, &replicas)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(rendered.Spec["etcdCluster"].Raw).Should(MatchJSON(`{"size":4,"storage":{"class":"fast"},"pod":{"resources":{"limits":{"cpu":"500m"}}}}`))
+		Expect(scaled.Spec["etcdCluster"].Raw).Should(MatchJSON(service.Spec["etcdCluster"].Raw))
+	})
+})
@@ -0,0 +1,63 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+)
+
+var _ = Describe("Merge the SubscriptionConfig", func() {
+
+	It("Should keep the fields set by others and remove the fields no longer in the OperandRegistry", func() {
+		sub := &olmv1alpha1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "etcd"}, Spec: &olmv1alpha1.SubscriptionSpec{}}
+		applySubscriptionConfig(sub, &olmv1alpha1.SubscriptionConfig{
+			Env:          []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "http://proxy:3128"}},
+			NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+		})
+		Expect(sub.Annotations).Should(HaveKey(constant.SubscriptionConfigAnnotation))
+		Expect(sub.Spec.Config.NodeSelector).Should(HaveKey("node-role.kubernetes.io/infra"))
+
+		// Someone else sets an environment variable and the tolerations
+		sub.Spec.Config.Env = append(sub.Spec.Config.Env, corev1.EnvVar{Name: "LOG_LEVEL", Value: "debug"})
+		sub.Spec.Config.Tolerations = []corev1.Toleration{{Key: "infra", Operator: corev1.TolerationOpExists}}
+
+		// The node selector is removed from the OperandRegistry, and the proxy is changed
+		applySubscriptionConfig(sub, &olmv1alpha1.SubscriptionConfig{
+			Env: []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "http://other:3128"}},
+		})
+		Expect(sub.Spec.Config.NodeSelector).Should(BeNil())
+		Expect(sub.Spec.Config.Tolerations).Should(HaveLen(1))
+		Expect(sub.Spec.Config.Env).Should(Equal([]corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "HTTP_PROXY", Value: "http://other:3128"}}))
+
+		// The config is removed from the OperandRegistry
+		applySubscriptionConfig(sub, nil)
+		Expect(sub.Annotations).ShouldNot(HaveKey(constant.SubscriptionConfigAnnotation))
+		Expect(sub.Spec.Config.Env).Should(Equal([]corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}}))
+		Expect(sub.Spec.Config.Tolerations).Should(HaveLen(1))
+	})
+
+	It("Should leave the Subscription without config when none is set", func() {
+		Expect(mergeSubscriptionConfig(nil, nil, nil)).Should(BeNil())
+		Expect(mergeSubscriptionConfig(nil, &olmv1alpha1.SubscriptionConfig{NodeSelector: map[string]string{"a": "b"}}, nil)).Should(BeNil())
+	})
+})
@@ -0,0 +1,118 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+)
+
+var _ = Describe("Tear down the operands", func() {
+
+	It("Should delete the operands by default", func() {
+		Expect((&operatorv1alpha1.Operand{Name: "etcd"}).GetDeletionPolicy()).Should(Equal(operatorv1alpha1.DeletionPolicyDelete))
+		operand := &operatorv1alpha1.Operand{Name: "etcd", DeletionPolicy: operatorv1alpha1.DeletionPolicyRetain}
+		Expect(operand.GetDeletionPolicy()).Should(Equal(operatorv1alpha1.DeletionPolicyRetain))
+	})
+
+	It("Should only have a teardown deadline when the request is deleted", func() {
+		req := &operatorv1alpha1.OperandRequest{}
+		Expect(req.GetTeardownDeadline().IsZero()).Should(BeTrue())
+
+		deleted := metav1.NewTime(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+		req.DeletionTimestamp = &deleted
+		Expect(req.GetTeardownDeadline()).Should(Equal(deleted.Add(operatorv1alpha1.DefaultTeardownGracePeriod)))
+
+		req.Spec.TeardownGracePeriod = &metav1.Duration{Duration: time.Minute}
+		Expect(req.GetTeardownDeadline()).Should(Equal(deleted.Add(time.Minute)))
+	})
+
+	It("Should delete the custom resources of the OperandRequest while the operator is kept for the others", func() {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(operatorv1alpha1.AddToScheme(scheme))
+		newCR := func() *unstructured.Unstructured {
+			cr := &unstructured.Unstructured{}
+			cr.SetAPIVersion("etcd.database.coreos.com/v1beta2")
+			cr.SetKind("EtcdCluster")
+			return cr
+		}
+		cr := newCR()
+		cr.SetName("example")
+		cr.SetNamespace("team-a")
+		cr.SetLabels(map[string]string{constant.OpreqLabel: "true"})
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cr).Build()
+		r := &Reconciler{ODLMOperator: &deploy.ODLMOperator{Client: c, Reader: c, Scheme: scheme}}
+
+		req := &operatorv1alpha1.OperandRequest{ObjectMeta: metav1.ObjectMeta{Name: "etcd", Namespace: "team-a"}}
+		req.Status.Members = []operatorv1alpha1.MemberStatus{{
+			Name:          "etcd",
+			OperandCRList: []operatorv1alpha1.OperandCRMember{{Name: "example", Kind: "EtcdCluster", APIVersion: "etcd.database.coreos.com/v1beta2"}},
+		}}
+		Expect(r.deleteRequestCustomResources(context.TODO(), req, "etcd")).Should(Succeed())
+		err := c.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: "example"}, newCR())
+		Expect(apierrors.IsNotFound(err)).Should(BeTrue())
+		Expect(req.Status.Members[0].OperandCRList).Should(BeEmpty())
+	})
+
+	It("Should abandon the Helm release when the ServiceAccount of its Jobs is not found", func() {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(operatorv1alpha1.AddToScheme(scheme))
+		utilruntime.Must(corev1.AddToScheme(scheme))
+		utilruntime.Must(batchv1.AddToScheme(scheme))
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      "etcd" + constant.HelmValuesSecretSuffix,
+			Namespace: "ibm-common-services",
+			Labels:    map[string]string{constant.OpreqLabel: "true"},
+		}}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+		recorder := record.NewFakeRecorder(10)
+		r := &Reconciler{ODLMOperator: &deploy.ODLMOperator{Client: c, Reader: c, Scheme: scheme, Recorder: recorder}}
+
+		req := &operatorv1alpha1.OperandRequest{ObjectMeta: metav1.ObjectMeta{Name: "etcd", Namespace: "team-a"}}
+		registry := &operatorv1alpha1.OperandRegistry{ObjectMeta: metav1.ObjectMeta{Name: "common-service", Namespace: "ibm-common-services"}}
+		op := &operatorv1alpha1.Operator{
+			Name:      "etcd",
+			Namespace: "ibm-common-services",
+			Chart:     &operatorv1alpha1.HelmChart{Name: "etcd", Repository: "https://charts.example.com", ServiceAccountName: "etcd-installer"},
+		}
+		Expect(r.deleteHelmRelease(context.TODO(), req, registry, &operatorv1alpha1.OperandConfig{}, op)).Should(Succeed())
+
+		err := c.Get(context.TODO(), types.NamespacedName{Namespace: "ibm-common-services", Name: secret.Name}, &corev1.Secret{})
+		Expect(apierrors.IsNotFound(err)).Should(BeTrue())
+		jobs := &batchv1.JobList{}
+		Expect(c.List(context.TODO(), jobs)).Should(Succeed())
+		Expect(jobs.Items).Should(BeEmpty())
+		Expect(recorder.Events).Should(Receive(ContainSubstring(constant.EventReasonHelmReleaseAbandoned)))
+	})
+})
@@ -0,0 +1,67 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Install throttle", func() {
+
+	newSub := func(name, catalog, installedCSV string, state olmv1alpha1.SubscriptionState, approval olmv1alpha1.Approval) olmv1alpha1.Subscription {
+		return olmv1alpha1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ibm-common-services"},
+			Spec: &olmv1alpha1.SubscriptionSpec{
+				CatalogSource:          catalog,
+				CatalogSourceNamespace: "openshift-marketplace",
+				InstallPlanApproval:    approval,
+			},
+			Status: olmv1alpha1.SubscriptionStatus{InstalledCSV: installedCSV, State: state},
+		}
+	}
+
+	subs := []olmv1alpha1.Subscription{
+		newSub("etcd", "opencloud-operators", "", olmv1alpha1.SubscriptionStateUpgradePending, olmv1alpha1.ApprovalAutomatic),
+		newSub("jenkins", "opencloud-operators", "", olmv1alpha1.SubscriptionStateNone, olmv1alpha1.ApprovalAutomatic),
+		newSub("mongodb", "opencloud-operators", "mongodb.v1.0.0", olmv1alpha1.SubscriptionStateAtLatest, olmv1alpha1.ApprovalAutomatic),
+		newSub("cert-manager", "opencloud-operators", "", olmv1alpha1.SubscriptionStateUpgradePending, olmv1alpha1.ApprovalManual),
+		newSub("redis", "opencloud-operators", "", olmv1alpha1.SubscriptionStateFailed, olmv1alpha1.ApprovalAutomatic),
+		newSub("kafka", "community-operators", "", olmv1alpha1.SubscriptionStateNone, olmv1alpha1.ApprovalAutomatic),
+	}
+
+	It("Should count the Subscriptions being installed from the catalog", func() {
+		Expect(countInstalling(subs, "openshift-marketplace/opencloud-operators", map[string]reservation{}, time.Now())).Should(Equal(2))
+		Expect(countInstalling(subs, "openshift-marketplace/community-operators", map[string]reservation{}, time.Now())).Should(Equal(1))
+	})
+
+	It("Should count the reservations until they are cached or expired", func() {
+		now := time.Now()
+		reservations := map[string]reservation{
+			"ibm-common-services/etcd":    {catalog: "openshift-marketplace/opencloud-operators", created: now},
+			"ibm-common-services/couchdb": {catalog: "openshift-marketplace/opencloud-operators", created: now},
+			"ibm-common-services/mysql":   {catalog: "openshift-marketplace/opencloud-operators", created: now.Add(-2 * reservationTimeout)},
+		}
+		Expect(countInstalling(subs, "openshift-marketplace/opencloud-operators", reservations, now)).Should(Equal(3))
+		Expect(reservations).Should(HaveLen(1))
+		Expect(reservations).Should(HaveKey("ibm-common-services/couchdb"))
+	})
+})
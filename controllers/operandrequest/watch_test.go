@@ -0,0 +1,167 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequest
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/ownership"
+)
+
+var _ = Describe("Watch the kinds of the CRDs", func() {
+
+	It("Should stop and start again the watches of the kinds when their CRD is deleted and established", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		scheme := runtime.NewScheme()
+		Expect(metav1.AddMetaToScheme(scheme)).Should(Succeed())
+
+		w := newResourceWatcher(nil, nil)
+		w.ctx, w.client = ctx, metadatafake.NewSimpleMetadataClient(scheme)
+		gvk := schema.GroupVersionKind{Group: "etcd.database.coreos.com", Version: "v1beta2", Kind: "EtcdCluster"}
+		gvr := schema.GroupVersionResource{Group: "etcd.database.coreos.com", Version: "v1beta2", Resource: "etcdclusters"}
+		w.watched[gvk] = gvr
+		w.namespaces[gvk] = map[string]bool{"ibm-common-services": true}
+		w.runAll(gvk, gvr)
+
+		requestKey := types.NamespacedName{Name: "etcd", Namespace: "team-a"}
+		cr := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "ibm-common-services", Annotations: ownership.Annotations(requestKey)}}
+		Expect(w.informers[gvk]["ibm-common-services"].GetStore().Add(cr)).Should(Succeed())
+
+		w.crdDeleted("jenkins.jenkins.io")
+		Expect(w.watched).Should(HaveKey(gvk))
+
+		w.crdDeleted("etcdclusters.etcd.database.coreos.com")
+		Expect(w.watched).ShouldNot(HaveKey(gvk))
+		Expect(w.informers).ShouldNot(HaveKey(gvk))
+		Expect(w.dropped[gvk].requests).Should(Equal([]types.NamespacedName{requestKey}))
+
+		w.crdEstablished("etcdclusters.etcd.database.coreos.com", gvk.GroupKind())
+		Expect(w.watched).Should(HaveKeyWithValue(gvk, gvr))
+		Expect(w.informers[gvk]).Should(HaveKey("ibm-common-services"))
+		Expect(w.dropped).Should(BeEmpty())
+
+		var e event.GenericEvent
+		Eventually(w.events).Should(Receive(&e))
+		Expect(ownership.Requests(e.Object)).Should(Equal([]types.NamespacedName{requestKey}))
+	})
+
+	It("Should only watch the kinds in the namespaces of their objects when ODLM doesn't watch all the namespaces", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		scheme := runtime.NewScheme()
+		Expect(metav1.AddMetaToScheme(scheme)).Should(Succeed())
+		gvk := schema.GroupVersionKind{Group: "etcd.database.coreos.com", Version: "v1beta2", Kind: "EtcdCluster"}
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(gvk, meta.RESTScopeNamespace)
+
+		os.Setenv("WATCH_NAMESPACE", "team-a,team-b")
+		defer os.Unsetenv("WATCH_NAMESPACE")
+		w := newResourceWatcher(nil, mapper)
+		w.ctx, w.client = ctx, metadatafake.NewSimpleMetadataClient(scheme)
+		w.watch(ctx, gvk, "team-a")
+		w.watch(ctx, gvk, "team-a")
+		w.watch(ctx, gvk, "team-b")
+		Expect(w.informers[gvk]).Should(HaveLen(2))
+		Expect(w.informers[gvk]).Should(HaveKey("team-a"))
+		Expect(w.informers[gvk]).Should(HaveKey("team-b"))
+
+		os.Setenv("WATCH_NAMESPACE", "")
+		w = newResourceWatcher(nil, mapper)
+		w.ctx, w.client = ctx, metadatafake.NewSimpleMetadataClient(scheme)
+		w.watch(ctx, gvk, "team-a")
+		w.watch(ctx, gvk, "team-b")
+		Expect(w.informers[gvk]).Should(HaveLen(1))
+		Expect(w.informers[gvk]).Should(HaveKey(metav1.NamespaceAll))
+	})
+
+	It("Should reconcile the OperandRequests waiting for the CRD when it is established", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		w := newResourceWatcher(nil, nil)
+		w.ctx = ctx
+		gk := schema.GroupKind{Group: "etcd.database.coreos.com", Kind: "EtcdCluster"}
+		requestKey := types.NamespacedName{Name: "etcd", Namespace: "team-a"}
+		w.waitForCRD(gk, requestKey)
+		w.waitForCRD(gk, requestKey)
+
+		w.crdEstablished("jenkins.jenkins.io", schema.GroupKind{Group: "jenkins.io", Kind: "Jenkins"})
+		Consistently(w.events).ShouldNot(Receive())
+
+		w.crdEstablished("etcdclusters.etcd.database.coreos.com", gk)
+		var e event.GenericEvent
+		Eventually(w.events).Should(Receive(&e))
+		Expect(ownership.Requests(e.Object)).Should(Equal([]types.NamespacedName{requestKey}))
+		Expect(w.waiting).Should(BeEmpty())
+		Consistently(w.events).ShouldNot(Receive())
+	})
+
+	It("Should only see the established CRDs", func() {
+		crd := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apiextensions.k8s.io/v1",
+			"kind":       "CustomResourceDefinition",
+			"metadata":   map[string]interface{}{"name": "etcdclusters.etcd.database.coreos.com"},
+			"spec": map[string]interface{}{
+				"group": "etcd.database.coreos.com",
+				"names": map[string]interface{}{"kind": "EtcdCluster", "plural": "etcdclusters"},
+			},
+		}}
+		_, ok := establishedCRD(crd)
+		Expect(ok).Should(BeFalse())
+
+		Expect(unstructured.SetNestedSlice(crd.Object, []interface{}{
+			map[string]interface{}{"type": "NamesAccepted", "status": "True"},
+			map[string]interface{}{"type": "Established", "status": "True"},
+		}, "status", "conditions")).Should(Succeed())
+		established, ok := establishedCRD(crd)
+		Expect(ok).Should(BeTrue())
+		Expect(established.Spec.Names.Kind).Should(Equal("EtcdCluster"))
+	})
+
+	It("Should surface the kind waiting for its CRD on the member status", func() {
+		req := &operatorv1alpha1.OperandRequest{}
+		mu := &sync.Mutex{}
+		req.SetWaitingForCRDCondition("etcd", "etcd.database.coreos.com/v1beta2, Kind=EtcdCluster", operatorv1alpha1.ResourceTypeOperand, corev1.ConditionTrue, mu)
+		Expect(req.IsWaitingForCRD("etcd", mu)).Should(BeTrue())
+		Expect(req.Status.Members[0].WaitingForCRD).Should(Equal("etcd.database.coreos.com/v1beta2, Kind=EtcdCluster"))
+		Expect(req.Status.Conditions[0].Type).Should(Equal(operatorv1alpha1.ConditionWaitingForCRD))
+
+		req.RemoveWaitingForCRDCondition("etcd", operatorv1alpha1.ResourceTypeOperand, mu)
+		Expect(req.IsWaitingForCRD("etcd", mu)).Should(BeFalse())
+		Expect(req.Status.Conditions).Should(BeEmpty())
+	})
+
+	It("Should only map the CRDs of the custom resources", func() {
+		Expect(crdName(schema.GroupVersionResource{Group: "etcd.database.coreos.com", Version: "v1beta2", Resource: "etcdclusters"})).Should(Equal("etcdclusters.etcd.database.coreos.com"))
+		Expect(crdName(schema.GroupVersionResource{Version: "v1", Resource: "configmaps"})).Should(BeEmpty())
+	})
+})
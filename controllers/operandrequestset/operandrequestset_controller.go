@@ -0,0 +1,257 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequestset
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/shard"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/throughput"
+)
+
+// Reconciler reconciles the OperandRequestSet object
+type Reconciler struct {
+	*deploy.ODLMOperator
+}
+
+// Reconcile creates the OperandRequest of the OperandRequestSet in every selected namespace, and deletes it from the
+// namespaces which are no longer selected. The OperandRequests are owned by the OperandRequestSet, they are deleted
+// with it by the garbage collector.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, log := logging.Reconcile(ctx, "operandrequestset", "requestset", req.Name)
+
+	// Fetch the OperandRequestSet instance
+	instance := &operatorv1alpha1.OperandRequestSet{}
+	if err := r.Client.Get(ctx, req.NamespacedName, instance); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !instance.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	log.V(logging.LevelFlow).Info("Reconciling OperandRequestSet")
+
+	namespaces, err := r.selectNamespaces(ctx, instance)
+	if err != nil {
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, constant.EventReasonRequestSetInvalid, "The namespaces of the OperandRequestSet can't be selected: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	merr := &util.MultiErr{}
+	members := make([]operatorv1alpha1.OperandRequestSetMember, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		member, err := r.applyRequest(ctx, instance, namespace)
+		if err != nil {
+			merr.Add(err)
+		}
+		members = append(members, member)
+	}
+	if err := r.pruneRequests(ctx, instance, namespaces); err != nil {
+		merr.Add(err)
+	}
+
+	if err := r.updateStatus(ctx, instance, members); err != nil {
+		merr.Add(err)
+	}
+	if len(merr.Errors) != 0 {
+		return ctrl.Result{}, merr
+	}
+
+	log.V(logging.LevelFlow).Info("Finished reconciling OperandRequestSet")
+	return ctrl.Result{}, nil
+}
+
+// selectNamespaces returns the sorted names of the namespaces matching the selector of the OperandRequestSet,
+// except the namespaces excluded from ODLM and the terminating namespaces
+func (r *Reconciler) selectNamespaces(ctx context.Context, instance *operatorv1alpha1.OperandRequestSet) ([]string, error) {
+	if instance.Spec.NamespaceSelector == nil {
+		return nil, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(instance.Spec.NamespaceSelector)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid namespace selector of the OperandRequestSet %s", instance.Name)
+	}
+	nsList := &corev1.NamespaceList{}
+	if err := r.Reader.List(ctx, nsList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list the namespaces of the OperandRequestSet %s", instance.Name)
+	}
+	var namespaces []string
+	for _, ns := range nsList.Items {
+		if ns.Labels[constant.ExcludeNamespaceLabel] == "true" || ns.Status.Phase == corev1.NamespaceTerminating {
+			continue
+		}
+		namespaces = append(namespaces, ns.Name)
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
+
+// applyRequest creates or updates the OperandRequest of the OperandRequestSet in the namespace from its template.
+// An OperandRequest with the same name which isn't owned by the OperandRequestSet is left untouched and reported.
+func (r *Reconciler) applyRequest(ctx context.Context, instance *operatorv1alpha1.OperandRequestSet, namespace string) (operatorv1alpha1.OperandRequestSetMember, error) {
+	member := operatorv1alpha1.OperandRequestSetMember{Namespace: namespace}
+
+	request := &operatorv1alpha1.OperandRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: instance.Name, Namespace: namespace},
+	}
+	err := r.Client.Get(ctx, client.ObjectKeyFromObject(request), request)
+	if client.IgnoreNotFound(err) != nil {
+		return member, errors.Wrapf(err, "failed to get OperandRequest %s/%s", namespace, instance.Name)
+	}
+	if err == nil && !metav1.IsControlledBy(request, instance) {
+		member.Phase = operatorv1alpha1.ClusterPhaseFailed
+		member.Message = fmt.Sprintf("the OperandRequest %s/%s already exists and isn't owned by the OperandRequestSet", namespace, instance.Name)
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, constant.EventReasonRequestSetInvalid, "The OperandRequest of the namespace %s can't be created: %s", namespace, member.Message)
+		return member, nil
+	}
+
+	template := instance.Spec.Template.DeepCopy()
+	result, err := controllerutil.CreateOrPatch(ctx, r.Client, request, func() error {
+		if request.Labels == nil {
+			request.Labels = make(map[string]string)
+		}
+		for key, value := range template.Labels {
+			request.Labels[key] = value
+		}
+		request.Labels[constant.OperandRequestSetLabel] = instance.Name
+		if len(template.Annotations) != 0 && request.Annotations == nil {
+			request.Annotations = make(map[string]string)
+		}
+		for key, value := range template.Annotations {
+			request.Annotations[key] = value
+		}
+		request.Spec = template.Spec
+		return controllerutil.SetControllerReference(instance, request, r.Scheme)
+	})
+	if err != nil {
+		member.Phase = operatorv1alpha1.ClusterPhaseFailed
+		member.Message = err.Error()
+		return member, errors.Wrapf(err, "failed to apply the OperandRequest %s/%s", namespace, instance.Name)
+	}
+	if result != controllerutil.OperationResultNone {
+		logging.FromContext(ctx).V(logging.LevelChange).Info("Applied the OperandRequest", "namespace", namespace, "operation", result)
+	}
+	member.Phase = request.Status.Phase
+	return member, nil
+}
+
+// pruneRequests deletes the OperandRequests of the OperandRequestSet from the namespaces which are no longer selected
+func (r *Reconciler) pruneRequests(ctx context.Context, instance *operatorv1alpha1.OperandRequestSet, namespaces []string) error {
+	selected := make(map[string]bool, len(namespaces))
+	for _, namespace := range namespaces {
+		selected[namespace] = true
+	}
+
+	requestList := &operatorv1alpha1.OperandRequestList{}
+	if err := r.Client.List(ctx, requestList, client.MatchingLabels{constant.OperandRequestSetLabel: instance.Name}); err != nil {
+		return errors.Wrapf(err, "failed to list the OperandRequests of the OperandRequestSet %s", instance.Name)
+	}
+	for i := range requestList.Items {
+		request := &requestList.Items[i]
+		if selected[request.Namespace] || !metav1.IsControlledBy(request, instance) || !request.DeletionTimestamp.IsZero() {
+			continue
+		}
+		logging.FromContext(ctx).V(logging.LevelChange).Info("Deleting the OperandRequest of the namespace no longer selected", "namespace", request.Namespace)
+		if err := r.Client.Delete(ctx, request); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete the OperandRequest %s/%s", request.Namespace, request.Name)
+		}
+	}
+	return nil
+}
+
+// updateStatus aggregates the members into the status of the OperandRequestSet, the status is patched when it changes
+func (r *Reconciler) updateStatus(ctx context.Context, instance *operatorv1alpha1.OperandRequestSet, members []operatorv1alpha1.OperandRequestSetMember) error {
+	originalInstance := instance.DeepCopy()
+	instance.Status.ObservedGeneration = instance.Generation
+	instance.Status.Members = members
+	if len(members) == 0 {
+		instance.Status.Members = nil
+	}
+	instance.UpdatePhase()
+	if reflect.DeepEqual(originalInstance.Status, instance.Status) {
+		return nil
+	}
+
+	status := instance.Status.DeepCopy()
+	if err := r.PatchStatus(ctx, instance, originalInstance, func(latest client.Object) error {
+		latest.(*operatorv1alpha1.OperandRequestSet).Status = *status.DeepCopy()
+		return nil
+	}); err != nil {
+		return errors.Wrapf(err, "failed to patch the status of the OperandRequestSet %s", instance.Name)
+	}
+	return nil
+}
+
+// SetupWithManager adds OperandRequestSet controller to the manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// The namespaces are watched with a cache of their own, the manager cache may be restricted to the watched namespaces
+	nsCache, err := cache.New(mgr.GetConfig(), cache.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		return errors.Wrap(err, "failed to create the cache of the namespaces")
+	}
+	if err := mgr.Add(nsCache); err != nil {
+		return err
+	}
+
+	// The namespaces are matched against the selectors when the OperandRequestSets are reconciled
+	toRequestSets := func(object client.Object) []ctrl.Request {
+		setList := &operatorv1alpha1.OperandRequestSetList{}
+		if err := r.Client.List(context.Background(), setList); err != nil {
+			logging.Logger("operandrequestset").Error(err, "failed to list the OperandRequestSets")
+			return nil
+		}
+		requests := make([]ctrl.Request, 0, len(setList.Items))
+		for _, set := range setList.Items {
+			requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Name: set.Name}})
+		}
+		return requests
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(throughput.ControllerOptions("operandrequestset")).
+		For(&operatorv1alpha1.OperandRequestSet{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&operatorv1alpha1.OperandRequest{}).
+		Watches(source.NewKindWithCache(&corev1.Namespace{}, nsCache), handler.EnqueueRequestsFromMapFunc(toRequestSets), builder.WithPredicates(predicate.Or(predicate.LabelChangedPredicate{}, predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return e.ObjectOld.(*corev1.Namespace).Status.Phase != e.ObjectNew.(*corev1.Namespace).Status.Phase
+			},
+		}))).
+		Complete(shard.Reconciler(r))
+}
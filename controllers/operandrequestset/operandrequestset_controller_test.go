@@ -0,0 +1,124 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandrequestset
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	testutil "github.com/IBM/operand-deployment-lifecycle-manager/controllers/testutil"
+)
+
+// +kubebuilder:docs-gen:collapse=Imports
+
+var _ = Describe("OperandRequestSet controller", func() {
+	const (
+		name          = "common-service"
+		namespace     = "ibm-common-services"
+		tenant        = "ibm-tenant"
+		tenantLabel   = "tenant.example.com/team"
+		requestSetKey = "ibm-cloudpak-set"
+	)
+
+	var (
+		ctx context.Context
+
+		registryNamespaceName string
+		tenantNamespaceNames  []string
+
+		requestSet *operatorv1alpha1.OperandRequestSet
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		registryNamespaceName = testutil.CreateNSName(namespace)
+		tenantNamespaceNames = []string{testutil.CreateNSName(tenant), testutil.CreateNSName(tenant)}
+
+		By("Creating the Namespaces")
+		Expect(k8sClient.Create(ctx, testutil.NamespaceObj(registryNamespaceName))).Should(Succeed())
+		for _, ns := range tenantNamespaceNames {
+			nsObj := testutil.NamespaceObj(ns)
+			nsObj.Labels = map[string]string{tenantLabel: "true"}
+			Expect(k8sClient.Create(ctx, nsObj)).Should(Succeed())
+		}
+
+		requestSet = &operatorv1alpha1.OperandRequestSet{
+			ObjectMeta: metav1.ObjectMeta{Name: requestSetKey},
+			Spec: operatorv1alpha1.OperandRequestSetSpec{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{tenantLabel: "true"}},
+				Template: operatorv1alpha1.OperandRequestTemplate{
+					Labels: map[string]string{"team": "tenant"},
+					Spec:   testutil.OperandRequestObj(name, registryNamespaceName, requestSetKey, "").Spec,
+				},
+			},
+		}
+		By("Creating the OperandRequestSet")
+		Expect(k8sClient.Create(ctx, requestSet)).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		By("Deleting the OperandRequestSet")
+		Expect(k8sClient.Delete(ctx, requestSet)).Should(Succeed())
+	})
+
+	Context("Stamping the OperandRequests", func() {
+		It("Should create the OperandRequest in the selected namespaces and delete it when they drop out", func() {
+			By("Checking the OperandRequests of the selected namespaces")
+			for _, ns := range tenantNamespaceNames {
+				request := &operatorv1alpha1.OperandRequest{}
+				Eventually(func() error {
+					return k8sClient.Get(ctx, types.NamespacedName{Name: requestSetKey, Namespace: ns}, request)
+				}, timeout, interval).Should(Succeed())
+				Expect(request.Labels).Should(HaveKeyWithValue(constant.OperandRequestSetLabel, requestSetKey))
+				Expect(request.Labels).Should(HaveKeyWithValue("team", "tenant"))
+				Expect(request.Spec.Requests).Should(Equal(requestSet.Spec.Template.Spec.Requests))
+				Expect(metav1.IsControlledBy(request, requestSet)).Should(BeTrue())
+			}
+
+			setInstance := &operatorv1alpha1.OperandRequestSet{}
+			Eventually(func() int32 {
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: requestSetKey}, setInstance)).Should(Succeed())
+				return setInstance.Status.Namespaces
+			}, timeout, interval).Should(Equal(int32(2)))
+			Expect(setInstance.Status.Members).Should(HaveLen(2))
+
+			By("Removing a namespace from the selector")
+			nsObj := testutil.NamespaceObj(tenantNamespaceNames[0])
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: tenantNamespaceNames[0]}, nsObj)).Should(Succeed())
+			delete(nsObj.Labels, tenantLabel)
+			Expect(k8sClient.Update(ctx, nsObj)).Should(Succeed())
+
+			Eventually(func() bool {
+				request := &operatorv1alpha1.OperandRequest{}
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: requestSetKey, Namespace: tenantNamespaceNames[0]}, request)
+				return apierrors.IsNotFound(err) || (err == nil && !request.DeletionTimestamp.IsZero())
+			}, timeout, interval).Should(BeTrue())
+			Eventually(func() []operatorv1alpha1.OperandRequestSetMember {
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: requestSetKey}, setInstance)).Should(Succeed())
+				return setInstance.Status.Members
+			}, timeout, interval).Should(HaveLen(1))
+			Expect(setInstance.Status.Members[0].Namespace).Should(Equal(tenantNamespaceNames[1]))
+		})
+	})
+})
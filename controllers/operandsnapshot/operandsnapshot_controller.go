@@ -0,0 +1,443 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandsnapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/shard"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/throughput"
+)
+
+// Reconciler reconciles a OperandSnapshot object
+type Reconciler struct {
+	*deploy.ODLMOperator
+}
+
+// Reconcile captures or restores the ODLM-managed state described by an OperandSnapshot.
+// Each generation of an OperandSnapshot runs its operation once.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
+	ctx, log := logging.Reconcile(ctx, "operandsnapshot", "snapshot", req.NamespacedName.String())
+
+	// Fetch the OperandSnapshot instance
+	instance := &operatorv1alpha1.OperandSnapshot{}
+	if err := r.Client.Get(ctx, req.NamespacedName, instance); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !instance.DeletionTimestamp.IsZero() || instance.IsDone() {
+		return ctrl.Result{}, nil
+	}
+
+	// Skip the OperandSnapshot when its namespace opts out of ODLM
+	excluded, err := r.IsNamespaceExcluded(ctx, instance.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if excluded {
+		log.Info("Skip the OperandSnapshot in the excluded namespace", "label", constant.ExcludeNamespaceLabel)
+		return ctrl.Result{}, nil
+	}
+
+	log.V(logging.LevelFlow).Info("Reconciling OperandSnapshot")
+
+	originalInstance := instance.DeepCopy()
+
+	// Always attempt to patch the status after each reconciliation.
+	defer func() {
+		if reflect.DeepEqual(originalInstance.Status, instance.Status) {
+			return
+		}
+		// The captured content can be large, keep the status within the size budget
+		if err := r.GuardStatusSize(ctx, instance, &instance.Status, instance.TruncateStatus); err != nil {
+			reconcileErr = utilerrors.NewAggregate([]error{reconcileErr, fmt.Errorf("error while checking the size of OperandSnapshot.Status: %v", err)})
+			return
+		}
+		if err := r.PatchStatus(ctx, instance, originalInstance, func(latest client.Object) error {
+			return deploy.ReapplyStatus(&latest.(*operatorv1alpha1.OperandSnapshot).Status, &originalInstance.Status, &instance.Status)
+		}); err != nil {
+			reconcileErr = utilerrors.NewAggregate([]error{reconcileErr, fmt.Errorf("error while patching OperandSnapshot.Status: %v", err)})
+		}
+	}()
+
+	switch instance.GetOperation() {
+	case operatorv1alpha1.SnapshotOperationCapture:
+		instance.SetPhase(operatorv1alpha1.SnapshotCapturing, "")
+		if err := r.capture(ctx, instance); err != nil {
+			log.Error(err, "failed to capture OperandSnapshot")
+			instance.SetPhase(operatorv1alpha1.SnapshotFailed, err.Error())
+			r.Recorder.Eventf(instance, corev1.EventTypeWarning, "CaptureFailed", "Failed to capture the snapshot: %v", err)
+			return ctrl.Result{}, nil
+		}
+		instance.SetPhase(operatorv1alpha1.SnapshotCaptured, "")
+		r.Recorder.Event(instance, corev1.EventTypeNormal, "Captured", "The snapshot is captured")
+	case operatorv1alpha1.SnapshotOperationRestore:
+		instance.SetPhase(operatorv1alpha1.SnapshotRestoring, "")
+		if err := r.restore(ctx, instance); err != nil {
+			if meta.IsNoMatchError(errors.Cause(err)) {
+				// The operators of the operands are still being installed
+				log.Info("waiting for the operand APIs to restore OperandSnapshot", "reason", err.Error())
+				instance.Status.Message = err.Error()
+				return ctrl.Result{RequeueAfter: constant.DefaultRequeueDuration}, nil
+			}
+			log.Error(err, "failed to restore OperandSnapshot")
+			instance.SetPhase(operatorv1alpha1.SnapshotFailed, err.Error())
+			r.Recorder.Eventf(instance, corev1.EventTypeWarning, "RestoreFailed", "Failed to restore the snapshot: %v", err)
+			return ctrl.Result{}, nil
+		}
+		instance.SetPhase(operatorv1alpha1.SnapshotRestored, "")
+		r.Recorder.Event(instance, corev1.EventTypeNormal, "Restored", "The snapshot is restored")
+	default:
+		instance.SetPhase(operatorv1alpha1.SnapshotFailed, "unknown operation "+string(instance.Spec.Operation))
+	}
+
+	log.V(logging.LevelFlow).Info("Finished reconciling OperandSnapshot")
+	return ctrl.Result{}, nil
+}
+
+// capture captures the OperandRequests, their OperandRegistries, OperandConfigs and operand custom resources
+func (r *Reconciler) capture(ctx context.Context, instance *operatorv1alpha1.OperandSnapshot) error {
+	requests, err := r.listOperandRequests(ctx, instance.Spec.Namespaces)
+	if err != nil {
+		return err
+	}
+
+	content := &operatorv1alpha1.SnapshotContent{Version: operatorv1alpha1.SnapshotFormatVersion}
+	registryKeys := make(map[types.NamespacedName]bool)
+
+	for i := range requests {
+		request := &requests[i]
+		obj, err := r.newSnapshotObject(request, request.Spec)
+		if err != nil {
+			return err
+		}
+		content.OperandRequests = append(content.OperandRequests, obj)
+
+		for _, req := range request.Spec.Requests {
+			registryKeys[request.GetRegistryKey(req)] = true
+		}
+
+		operands, err := r.captureOperands(ctx, request)
+		if err != nil {
+			return err
+		}
+		content.Operands = append(content.Operands, operands...)
+	}
+
+	var keys []types.NamespacedName
+	for key := range registryKeys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	for _, key := range keys {
+		registry := &operatorv1alpha1.OperandRegistry{}
+		if err := r.Client.Get(ctx, key, registry); err != nil {
+			if apierrors.IsNotFound(err) {
+				logging.FromContext(ctx).Info("skip capturing OperandRegistry, it is not found", "registry", key.String())
+				continue
+			}
+			return errors.Wrapf(err, "failed to get OperandRegistry %s", key.String())
+		}
+		obj, err := r.newSnapshotObject(registry, registry.Spec)
+		if err != nil {
+			return err
+		}
+		content.OperandRegistries = append(content.OperandRegistries, obj)
+
+		// The OperandConfig has the same name and namespace as the OperandRegistry
+		config := &operatorv1alpha1.OperandConfig{}
+		if err := r.Client.Get(ctx, key, config); err != nil {
+			if apierrors.IsNotFound(err) {
+				logging.FromContext(ctx).Info("skip capturing OperandConfig, it is not found", "config", key.String())
+				continue
+			}
+			return errors.Wrapf(err, "failed to get OperandConfig %s", key.String())
+		}
+		obj, err = r.newSnapshotObject(config, config.Spec)
+		if err != nil {
+			return err
+		}
+		content.OperandConfigs = append(content.OperandConfigs, obj)
+	}
+
+	instance.Status.Content = content
+
+	if instance.Spec.Export != nil && instance.Spec.Export.ConfigMap != "" {
+		if err := r.exportToConfigMap(ctx, instance.Namespace, instance.Spec.Export.ConfigMap, content); err != nil {
+			return errors.Wrapf(err, "failed to export the snapshot to ConfigMap %s/%s", instance.Namespace, instance.Spec.Export.ConfigMap)
+		}
+	}
+	return nil
+}
+
+// captureOperands captures the custom resources created by the OperandRequest
+func (r *Reconciler) captureOperands(ctx context.Context, request *operatorv1alpha1.OperandRequest) ([]operatorv1alpha1.SnapshotObject, error) {
+	var operands []operatorv1alpha1.SnapshotObject
+	for _, member := range request.Status.Members {
+		for _, cr := range member.OperandCRList {
+			existingCR := unstructured.Unstructured{}
+			existingCR.SetAPIVersion(cr.APIVersion)
+			existingCR.SetKind(cr.Kind)
+			if err := r.Client.Get(ctx, types.NamespacedName{Namespace: request.Namespace, Name: cr.Name}, &existingCR); err != nil {
+				if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+					logging.FromContext(ctx).Info("skip capturing custom resource, it is not found", "kind", cr.Kind, "name", request.Namespace+"/"+cr.Name)
+					continue
+				}
+				return nil, errors.Wrapf(err, "failed to get custom resource -- Kind: %s, NamespacedName: %s/%s", cr.Kind, request.Namespace, cr.Name)
+			}
+			obj, err := r.newSnapshotObject(&existingCR, existingCR.Object["spec"])
+			if err != nil {
+				return nil, err
+			}
+			operands = append(operands, obj)
+		}
+	}
+	return operands, nil
+}
+
+// listOperandRequests lists the OperandRequests in the namespaces, or in all the watched namespaces if none is specified
+func (r *Reconciler) listOperandRequests(ctx context.Context, namespaces []string) ([]operatorv1alpha1.OperandRequest, error) {
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	var requests []operatorv1alpha1.OperandRequest
+	for _, ns := range namespaces {
+		requestList := &operatorv1alpha1.OperandRequestList{}
+		if err := r.Client.List(ctx, requestList, client.InNamespace(ns)); err != nil {
+			return nil, errors.Wrapf(err, "failed to list OperandRequests in namespace %s", ns)
+		}
+		for _, request := range requestList.Items {
+			if request.DeletionTimestamp.IsZero() {
+				requests = append(requests, request)
+			}
+		}
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		if requests[i].Namespace != requests[j].Namespace {
+			return requests[i].Namespace < requests[j].Namespace
+		}
+		return requests[i].Name < requests[j].Name
+	})
+	return requests, nil
+}
+
+// newSnapshotObject converts an object and its spec into a SnapshotObject
+func (r *Reconciler) newSnapshotObject(obj client.Object, spec interface{}) (operatorv1alpha1.SnapshotObject, error) {
+	gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+	if err != nil {
+		return operatorv1alpha1.SnapshotObject{}, err
+	}
+	snapshotObj := operatorv1alpha1.SnapshotObject{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Name:       obj.GetName(),
+		Namespace:  obj.GetNamespace(),
+		Labels:     obj.GetLabels(),
+	}
+	if spec != nil {
+		raw, err := json.Marshal(spec)
+		if err != nil {
+			return operatorv1alpha1.SnapshotObject{}, errors.Wrapf(err, "failed to marshal the spec of %s %s/%s", gvk.Kind, obj.GetNamespace(), obj.GetName())
+		}
+		snapshotObj.Spec = &runtime.RawExtension{Raw: raw}
+	}
+	return snapshotObj, nil
+}
+
+// exportToConfigMap saves the snapshot content into a ConfigMap
+func (r *Reconciler) exportToConfigMap(ctx context.Context, namespace, name string, content *operatorv1alpha1.SnapshotContent) error {
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Data: map[string]string{
+			constant.SnapshotConfigMapKey: string(raw),
+		},
+	}
+	return r.CreateOrUpdateConfigMap(ctx, cm)
+}
+
+// restore reapplies the snapshot content to the cluster
+func (r *Reconciler) restore(ctx context.Context, instance *operatorv1alpha1.OperandSnapshot) error {
+	content, err := r.getSnapshotContent(ctx, instance)
+	if err != nil {
+		return err
+	}
+	if content.Version != operatorv1alpha1.SnapshotFormatVersion {
+		return fmt.Errorf("unsupported snapshot version %q, the supported version is %q", content.Version, operatorv1alpha1.SnapshotFormatVersion)
+	}
+
+	// The OperandRegistries and OperandConfigs must exist before the OperandRequests using them,
+	// and the operands are restored at last, once their operators have been requested.
+	var objects []operatorv1alpha1.SnapshotObject
+	objects = append(objects, content.OperandRegistries...)
+	objects = append(objects, content.OperandConfigs...)
+	objects = append(objects, content.OperandRequests...)
+	objects = append(objects, content.Operands...)
+
+	for _, obj := range objects {
+		if err := r.applySnapshotObject(ctx, obj); err != nil {
+			return errors.Wrapf(err, "failed to restore %s %s/%s", obj.Kind, obj.Namespace, obj.Name)
+		}
+	}
+	return nil
+}
+
+// getSnapshotContent gets the snapshot content from the source of the OperandSnapshot
+func (r *Reconciler) getSnapshotContent(ctx context.Context, instance *operatorv1alpha1.OperandSnapshot) (*operatorv1alpha1.SnapshotContent, error) {
+	source := instance.Spec.Source
+	if source != nil && source.ConfigMap != "" {
+		content := &operatorv1alpha1.SnapshotContent{}
+		if err := r.readConfigMap(ctx, types.NamespacedName{Namespace: instance.Namespace, Name: source.ConfigMap}, constant.SnapshotConfigMapKey, content); err != nil {
+			return nil, err
+		}
+		return content, nil
+	}
+
+	snapshot := instance
+	if source != nil && source.Snapshot != "" {
+		snapshot = &operatorv1alpha1.OperandSnapshot{}
+		key := types.NamespacedName{Namespace: instance.Namespace, Name: source.Snapshot}
+		if err := r.Client.Get(ctx, key, snapshot); err != nil {
+			return nil, errors.Wrapf(err, "failed to get OperandSnapshot %s", key.String())
+		}
+	}
+	if snapshot.Status.Content != nil {
+		return snapshot.Status.Content, nil
+	}
+
+	// The content may have been moved to the status overflow ConfigMap
+	status := &operatorv1alpha1.OperandSnapshotStatus{}
+	key := types.NamespacedName{Namespace: snapshot.Namespace, Name: snapshot.Name + constant.StatusOverflowSuffix}
+	if err := r.readConfigMap(ctx, key, constant.StatusOverflowKey, status); err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			return nil, fmt.Errorf("OperandSnapshot %s/%s has no captured snapshot", snapshot.Namespace, snapshot.Name)
+		}
+		return nil, err
+	}
+	if status.Content == nil {
+		return nil, fmt.Errorf("OperandSnapshot %s/%s has no captured snapshot", snapshot.Namespace, snapshot.Name)
+	}
+	return status.Content, nil
+}
+
+// readConfigMap decodes the JSON data of a ConfigMap key
+func (r *Reconciler) readConfigMap(ctx context.Context, key types.NamespacedName, dataKey string, out interface{}) error {
+	// ConfigMaps are filtered in the cache, get it from the API server
+	cm := &corev1.ConfigMap{}
+	if err := r.Reader.Get(ctx, key, cm); err != nil {
+		return errors.Wrapf(err, "failed to get ConfigMap %s", key.String())
+	}
+	data, ok := cm.Data[dataKey]
+	if !ok {
+		return fmt.Errorf("ConfigMap %s has no key %s", key.String(), dataKey)
+	}
+	if err := json.Unmarshal([]byte(data), out); err != nil {
+		return errors.Wrapf(err, "failed to decode the key %s of ConfigMap %s", dataKey, key.String())
+	}
+	return nil
+}
+
+// applySnapshotObject creates the object from the snapshot, or updates its labels and spec if it already exists
+func (r *Reconciler) applySnapshotObject(ctx context.Context, obj operatorv1alpha1.SnapshotObject) error {
+	var spec interface{}
+	if obj.Spec != nil && len(obj.Spec.Raw) != 0 {
+		if err := json.Unmarshal(obj.Spec.Raw, &spec); err != nil {
+			return err
+		}
+	}
+
+	existing := &unstructured.Unstructured{}
+	existing.SetAPIVersion(obj.APIVersion)
+	existing.SetKind(obj.Kind)
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}, existing)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	if apierrors.IsNotFound(err) {
+		newObj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		newObj.SetAPIVersion(obj.APIVersion)
+		newObj.SetKind(obj.Kind)
+		newObj.SetName(obj.Name)
+		newObj.SetNamespace(obj.Namespace)
+		newObj.SetLabels(obj.Labels)
+		if spec != nil {
+			newObj.Object["spec"] = spec
+		}
+		logging.FromContext(ctx).V(logging.LevelChange).Info("Restoring", "kind", obj.Kind, "name", obj.Namespace+"/"+obj.Name)
+		return r.Client.Create(ctx, newObj)
+	}
+
+	labels := existing.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	for k, v := range obj.Labels {
+		labels[k] = v
+	}
+	existing.SetLabels(labels)
+	if spec != nil {
+		existing.Object["spec"] = spec
+	}
+	logging.FromContext(ctx).V(logging.LevelChange).Info("Restoring existing", "kind", obj.Kind, "name", obj.Namespace+"/"+obj.Name)
+
+	return r.Client.Update(ctx, existing)
+}
+
+// SetupWithManager adds OperandSnapshot controller to the manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	fair := throughput.NewFairQueue("operandsnapshot")
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(throughput.ControllerOptions("operandsnapshot")).
+		For(&operatorv1alpha1.OperandSnapshot{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// The requests are dispatched in turn across the namespaces by the fair queue
+		Watches(fair.Source(), &handler.EnqueueRequestForObject{}).
+		Complete(fair.Reconciler(shard.Reconciler(r)))
+}
@@ -0,0 +1,141 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operandsnapshot
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	testutil "github.com/IBM/operand-deployment-lifecycle-manager/controllers/testutil"
+)
+
+// +kubebuilder:docs-gen:collapse=Imports
+
+var _ = Describe("OperandSnapshot controller", func() {
+	const (
+		name              = "common-service"
+		namespace         = "ibm-common-services"
+		requestName       = "ibm-cloudpak-name"
+		requestNamespace  = "ibm-cloudpak"
+		operatorNamespace = "ibm-operators"
+		snapshotName      = "snapshot"
+		restoreName       = "restore"
+	)
+
+	var (
+		ctx context.Context
+
+		namespaceName         string
+		operatorNamespaceName string
+		requestNamespaceName  string
+
+		registry *operatorv1alpha1.OperandRegistry
+		config   *operatorv1alpha1.OperandConfig
+		request  *operatorv1alpha1.OperandRequest
+		snapshot *operatorv1alpha1.OperandSnapshot
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		namespaceName = testutil.CreateNSName(namespace)
+		operatorNamespaceName = testutil.CreateNSName(operatorNamespace)
+		requestNamespaceName = testutil.CreateNSName(requestNamespace)
+		registry = testutil.OperandRegistryObj(name, namespaceName, operatorNamespaceName)
+		config = testutil.OperandConfigObj(name, namespaceName)
+		request = testutil.OperandRequestObj(name, namespaceName, requestName, requestNamespaceName)
+		snapshot = testutil.OperandSnapshotObj(snapshotName, namespaceName, operatorv1alpha1.SnapshotOperationCapture, []string{requestNamespaceName})
+		snapshot.Spec.Export = &operatorv1alpha1.SnapshotExport{ConfigMap: snapshotName}
+
+		By("Creating the Namespace")
+		Expect(k8sClient.Create(ctx, testutil.NamespaceObj(namespaceName))).Should(Succeed())
+		Expect(k8sClient.Create(ctx, testutil.NamespaceObj(operatorNamespaceName))).Should(Succeed())
+		Expect(k8sClient.Create(ctx, testutil.NamespaceObj(requestNamespaceName))).Should(Succeed())
+
+		By("Creating the OperandRegistry")
+		Expect(k8sClient.Create(ctx, registry)).Should(Succeed())
+		By("Creating the OperandConfig")
+		Expect(k8sClient.Create(ctx, config)).Should(Succeed())
+		By("Creating the OperandRequest")
+		Expect(k8sClient.Create(ctx, request)).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		By("Deleting the OperandSnapshot")
+		Expect(k8sClient.Delete(ctx, snapshot)).Should(Succeed())
+		By("Deleting the OperandRequest")
+		Expect(k8sClient.Delete(ctx, request)).Should(Succeed())
+		By("Deleting the OperandConfig")
+		Expect(k8sClient.Delete(ctx, config)).Should(Succeed())
+		By("Deleting the OperandRegistry")
+		Expect(k8sClient.Delete(ctx, registry)).Should(Succeed())
+	})
+
+	Context("Capturing and restoring an OperandSnapshot", func() {
+		It("Should restore the captured OperandRequest", func() {
+
+			By("Creating the OperandSnapshot")
+			Expect(k8sClient.Create(ctx, snapshot)).Should(Succeed())
+
+			By("Checking status of the OperandSnapshot")
+			snapshotInstance := &operatorv1alpha1.OperandSnapshot{}
+			Eventually(func() operatorv1alpha1.SnapshotPhase {
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: snapshotName, Namespace: namespaceName}, snapshotInstance)).Should(Succeed())
+				return snapshotInstance.Status.Phase
+			}, timeout, interval).Should(Equal(operatorv1alpha1.SnapshotCaptured))
+			Expect(snapshotInstance.Status.Content).ShouldNot(BeNil())
+			Expect(snapshotInstance.Status.Content.OperandRequests).Should(HaveLen(1))
+			Expect(snapshotInstance.Status.Content.OperandRegistries).Should(HaveLen(1))
+			Expect(snapshotInstance.Status.Content.OperandConfigs).Should(HaveLen(1))
+
+			By("Checking the exported ConfigMap")
+			cm := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: snapshotName, Namespace: namespaceName}, cm)).Should(Succeed())
+			Expect(cm.Data).Should(HaveKey(constant.SnapshotConfigMapKey))
+
+			By("Deleting the OperandRequest")
+			Expect(k8sClient.Delete(ctx, request)).Should(Succeed())
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: requestName, Namespace: requestNamespaceName}, &operatorv1alpha1.OperandRequest{})
+				return err != nil
+			}, timeout, interval).Should(BeTrue())
+
+			By("Restoring the OperandSnapshot from the exported ConfigMap")
+			restore := testutil.OperandSnapshotObj(restoreName, namespaceName, operatorv1alpha1.SnapshotOperationRestore, nil)
+			restore.Spec.Source = &operatorv1alpha1.SnapshotSource{ConfigMap: snapshotName}
+			Expect(k8sClient.Create(ctx, restore)).Should(Succeed())
+
+			Eventually(func() operatorv1alpha1.SnapshotPhase {
+				restoreInstance := &operatorv1alpha1.OperandSnapshot{}
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: restoreName, Namespace: namespaceName}, restoreInstance)).Should(Succeed())
+				return restoreInstance.Status.Phase
+			}, timeout, interval).Should(Equal(operatorv1alpha1.SnapshotRestored))
+
+			By("Checking the restored OperandRequest")
+			requestInstance := &operatorv1alpha1.OperandRequest{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: requestName, Namespace: requestNamespaceName}, requestInstance)).Should(Succeed())
+			Expect(requestInstance.Spec.Requests).Should(Equal(request.Spec.Requests))
+
+			Expect(k8sClient.Delete(ctx, restore)).Should(Succeed())
+		})
+	})
+})
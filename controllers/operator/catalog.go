@@ -0,0 +1,118 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/mirror"
+)
+
+// CatalogSourceReady is the last observed state of the connection to a CatalogSource serving the operators
+const CatalogSourceReady = "READY"
+
+// CatalogSourceUnhealthyReason returns why OLM can't install the operators from the CatalogSource,
+// or an empty string if the CatalogSource is ready
+func CatalogSourceUnhealthyReason(catalog *olmv1alpha1.CatalogSource) string {
+	key := catalog.Namespace + "/" + catalog.Name
+	state := catalog.Status.GRPCConnectionState
+	if state == nil || state.LastObservedState == "" {
+		return "CatalogSource " + key + " is not connected yet"
+	}
+	if state.LastObservedState != CatalogSourceReady {
+		return "CatalogSource " + key + " is " + state.LastObservedState
+	}
+	return ""
+}
+
+// GetCatalogSourceHealth returns why OLM can't install the operators from the CatalogSource,
+// or an empty string if the CatalogSource is ready.
+// The CatalogSources are read from the API server, they may not be in the namespaces cached by ODLM
+func (m *ODLMOperator) GetCatalogSourceHealth(ctx context.Context, name, namespace string) (string, error) {
+	catalog := &olmv1alpha1.CatalogSource{}
+	if err := m.Reader.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, catalog); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "CatalogSource " + namespace + "/" + name + " is not found", nil
+		}
+		return "", err
+	}
+	return CatalogSourceUnhealthyReason(catalog), nil
+}
+
+// subscriptionResolutionFailed is the condition set by OLM when it can't resolve the Subscription
+const subscriptionResolutionFailed olmv1alpha1.SubscriptionConditionType = "ResolutionFailed"
+
+// GetSubscriptionHealth returns the health of the Subscription of an operator, and why it isn't healthy
+func GetSubscriptionHealth(sub *olmv1alpha1.Subscription) (apiv1alpha1.SubscriptionHealth, string) {
+	if sub == nil {
+		return apiv1alpha1.SubscriptionNotFound, "The Subscription is not found"
+	}
+	for _, t := range []olmv1alpha1.SubscriptionConditionType{subscriptionResolutionFailed, olmv1alpha1.SubscriptionCatalogSourcesUnhealthy, olmv1alpha1.SubscriptionInstallPlanFailed} {
+		if c := sub.Status.GetCondition(t); c.Status == corev1.ConditionTrue {
+			message := c.Message
+			if message == "" {
+				message = string(t)
+			}
+			return apiv1alpha1.SubscriptionUnhealthy, message
+		}
+	}
+	if sub.Status.State == olmv1alpha1.SubscriptionStateAtLatest && sub.Status.InstalledCSV != "" {
+		return apiv1alpha1.SubscriptionHealthy, ""
+	}
+	return apiv1alpha1.SubscriptionProgressing, ""
+}
+
+// CatalogSourceStateChanged returns true if the connection state of the CatalogSource changed
+func CatalogSourceStateChanged(oldObject, newObject client.Object) bool {
+	oldCatalog, ok := oldObject.(*olmv1alpha1.CatalogSource)
+	if !ok {
+		return false
+	}
+	newCatalog, ok := newObject.(*olmv1alpha1.CatalogSource)
+	if !ok {
+		return false
+	}
+	return CatalogSourceUnhealthyReason(oldCatalog) != CatalogSourceUnhealthyReason(newCatalog)
+}
+
+// ListOperandRegistriesByCatalogSource lists the OperandRegistries with an operator installed from the CatalogSource
+func (m *ODLMOperator) ListOperandRegistriesByCatalogSource(ctx context.Context, name, namespace string) ([]apiv1alpha1.OperandRegistry, error) {
+	registryList := &apiv1alpha1.OperandRegistryList{}
+	if err := m.Client.List(ctx, registryList); err != nil {
+		return nil, errors.Wrap(err, "failed to list the OperandRegistries")
+	}
+	var registries []apiv1alpha1.OperandRegistry
+	for _, registry := range registryList.Items {
+		for _, o := range registry.Spec.Operators {
+			o := o.DeepCopy()
+			mirror.Rewrite(o)
+			if o.GetType() == apiv1alpha1.OperatorTypeOLM && o.SourceName == name && o.SourceNamespace == namespace {
+				registries = append(registries, registry)
+				break
+			}
+		}
+	}
+	return registries, nil
+}
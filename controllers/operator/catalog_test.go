@@ -0,0 +1,100 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+func catalogOf(name, state string) *olmv1alpha1.CatalogSource {
+	catalog := &olmv1alpha1.CatalogSource{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "openshift-marketplace"}}
+	if state != "" {
+		catalog.Status.GRPCConnectionState = &olmv1alpha1.GRPCConnectionState{LastObservedState: state}
+	}
+	return catalog
+}
+
+var _ = Describe("CatalogSource health", func() {
+
+	var (
+		ctx = context.Background()
+		m   *ODLMOperator
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(olmv1alpha1.AddToScheme(scheme))
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			catalogOf("ready", CatalogSourceReady),
+			catalogOf("failing", "TRANSIENT_FAILURE"),
+			catalogOf("new", ""),
+		).Build()
+		m = &ODLMOperator{Client: c, Reader: c, Scheme: scheme}
+	})
+
+	It("Should report the ready CatalogSource as healthy", func() {
+		reason, err := m.GetCatalogSourceHealth(ctx, "ready", "openshift-marketplace")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(BeEmpty())
+	})
+
+	It("Should report why the CatalogSource is unhealthy", func() {
+		reason, err := m.GetCatalogSourceHealth(ctx, "failing", "openshift-marketplace")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(Equal("CatalogSource openshift-marketplace/failing is TRANSIENT_FAILURE"))
+
+		reason, err = m.GetCatalogSourceHealth(ctx, "new", "openshift-marketplace")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(Equal("CatalogSource openshift-marketplace/new is not connected yet"))
+	})
+
+	It("Should report the health of the Subscriptions", func() {
+		health, _ := GetSubscriptionHealth(nil)
+		Expect(health).To(Equal(apiv1alpha1.SubscriptionNotFound))
+
+		sub := &olmv1alpha1.Subscription{Status: olmv1alpha1.SubscriptionStatus{State: olmv1alpha1.SubscriptionStateAtLatest, InstalledCSV: "ibm-iam-operator.v3.23.1"}}
+		health, message := GetSubscriptionHealth(sub)
+		Expect(health).To(Equal(apiv1alpha1.SubscriptionHealthy))
+		Expect(message).To(BeEmpty())
+
+		sub.Status.State = olmv1alpha1.SubscriptionStateUpgradePending
+		health, _ = GetSubscriptionHealth(sub)
+		Expect(health).To(Equal(apiv1alpha1.SubscriptionProgressing))
+
+		sub.Status.Conditions = []olmv1alpha1.SubscriptionCondition{{Type: "ResolutionFailed", Status: corev1.ConditionTrue, Message: "constraints not satisfiable"}}
+		health, message = GetSubscriptionHealth(sub)
+		Expect(health).To(Equal(apiv1alpha1.SubscriptionUnhealthy))
+		Expect(message).To(Equal("constraints not satisfiable"))
+	})
+
+	It("Should report the missing CatalogSource as unhealthy", func() {
+		reason, err := m.GetCatalogSourceHealth(ctx, "missing", "openshift-marketplace")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(Equal("CatalogSource openshift-marketplace/missing is not found"))
+	})
+})
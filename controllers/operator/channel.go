@@ -0,0 +1,37 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"github.com/pkg/errors"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	odlmutil "github.com/IBM/operand-deployment-lifecycle-manager/pkg/util/v1"
+)
+
+// GetChannelScheme returns the scheme reading the versions of the channels of the operator,
+// it is nil for the semver scheme
+func GetChannelScheme(opt *apiv1alpha1.Operator) (*odlmutil.ChannelScheme, error) {
+	if opt == nil || opt.ChannelScheme == nil {
+		return nil, nil
+	}
+	scheme, err := odlmutil.NewChannelScheme(opt.ChannelScheme.Name, opt.ChannelScheme.Pattern, opt.ChannelScheme.Order)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the channel scheme of the operator %s", opt.Name)
+	}
+	return scheme, nil
+}
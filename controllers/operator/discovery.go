@@ -0,0 +1,116 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"sync"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	constant "github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	odlmutil "github.com/IBM/operand-deployment-lifecycle-manager/pkg/util/v1"
+)
+
+// sharedDiscovery is the discovery client shared by all the controllers of the manager
+var sharedDiscovery struct {
+	once sync.Once
+	dc   *odlmutil.CachedDiscovery
+}
+
+// getSharedDiscovery returns the cached discovery client of the manager.
+// The first call adds the CRD watch invalidating the cache to the manager.
+func getSharedDiscovery(mgr manager.Manager) *odlmutil.CachedDiscovery {
+	sharedDiscovery.once.Do(func() {
+		sharedDiscovery.dc = odlmutil.NewCachedDiscovery(discovery.NewDiscoveryClientForConfigOrDie(mgr.GetConfig()), constant.DefaultDiscoveryCacheTTL)
+		if err := mgr.Add(&discoveryInvalidator{config: mgr.GetConfig(), dc: sharedDiscovery.dc}); err != nil {
+			logging.Logger("discovery").Error(err, "failed to watch the CRDs, the discovered API resources are refreshed every TTL", "ttl", constant.DefaultDiscoveryCacheTTL.String())
+		}
+	})
+	return sharedDiscovery.dc
+}
+
+// discoveryInvalidator drops the cached discovery when a CRD is added, removed, updated or established,
+// so the new and removed API resources are seen before the cache expires
+type discoveryInvalidator struct {
+	config *rest.Config
+	dc     *odlmutil.CachedDiscovery
+}
+
+// Start watches the CRDs until the context is done
+func (i *discoveryInvalidator) Start(ctx context.Context) error {
+	dc, err := dynamic.NewForConfig(i.config)
+	if err != nil {
+		return err
+	}
+	informer := dynamicinformer.NewFilteredDynamicInformer(dc, apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions"), metav1.NamespaceAll, 0, cache.Indexers{}, nil).Informer()
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			i.dc.Invalidate()
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if crdChanged(oldObj, newObj) {
+				i.dc.Invalidate()
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			i.dc.Invalidate()
+		},
+	})
+	informer.Run(ctx.Done())
+	return nil
+}
+
+// crdChanged returns true when the served API resources of the CRD may have changed: the served versions and the scope
+// are in the spec of the CRD, and its resources are only served once it is established, which only updates its status
+func crdChanged(oldObj, newObj interface{}) bool {
+	oldCRD, ok := oldObj.(*unstructured.Unstructured)
+	if !ok {
+		return true
+	}
+	newCRD, ok := newObj.(*unstructured.Unstructured)
+	if !ok {
+		return true
+	}
+	return oldCRD.GetGeneration() != newCRD.GetGeneration() || crdEstablished(oldCRD) != crdEstablished(newCRD)
+}
+
+// crdEstablished returns true if the Established condition of the CRD is true
+func crdEstablished(crd *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if ok && condition["type"] == string(apiextensionsv1.Established) {
+			return condition["status"] == string(apiextensionsv1.ConditionTrue)
+		}
+	}
+	return false
+}
+
+// NeedLeaderElection returns false, every replica keeps its own discovery cache
+func (i *discoveryInvalidator) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,52 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Invalidating the discovery", func() {
+
+	newCRD := func(generation int64, established string) *unstructured.Unstructured {
+		crd := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apiextensions.k8s.io/v1",
+			"kind":       "CustomResourceDefinition",
+			"metadata":   map[string]interface{}{"name": "etcdclusters.etcd.database.coreos.com"},
+		}}
+		crd.SetGeneration(generation)
+		if established != "" {
+			Expect(unstructured.SetNestedSlice(crd.Object, []interface{}{
+				map[string]interface{}{"type": "NamesAccepted", "status": "True"},
+				map[string]interface{}{"type": "Established", "status": established},
+			}, "status", "conditions")).To(Succeed())
+		}
+		return crd
+	}
+
+	It("Should invalidate the discovery when the CRD is established", func() {
+		Expect(crdChanged(newCRD(1, ""), newCRD(1, "False"))).To(BeFalse())
+		Expect(crdChanged(newCRD(1, "False"), newCRD(1, "True"))).To(BeTrue())
+		Expect(crdChanged(newCRD(1, "True"), newCRD(1, "True"))).To(BeFalse())
+	})
+
+	It("Should invalidate the discovery when the spec of the CRD changes", func() {
+		Expect(crdChanged(newCRD(1, "True"), newCRD(2, "True"))).To(BeTrue())
+	})
+})
@@ -0,0 +1,208 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	constant "github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// indexedManagers records the field indexes added to the cache of each manager,
+// an index can only be added once to the cache of a manager
+var indexedManagers = struct {
+	sync.Mutex
+	managers map[manager.Manager]map[string]bool
+}{managers: map[manager.Manager]map[string]bool{}}
+
+// fieldIndex is a field index of the ODLM controllers
+type fieldIndex struct {
+	obj          client.Object
+	field        string
+	extractValue client.IndexerFunc
+	// fallback is what is done without the index
+	fallback string
+}
+
+// fieldIndexes are the field indexes of the ODLM controllers. The Subscriptions and the ClusterServiceVersions
+// are looked up in the shared informers by their package and by the ODLM label, rather than by listing a
+// whole namespace or the whole cluster and filtering them at each reconcile.
+var fieldIndexes = []fieldIndex{
+	{&apiv1alpha1.OperandRequest{}, constant.RegistryIndexField, RegistryIndexValues, "all the OperandRequests are listed to find the ones using an OperandRegistry"},
+	{&olmv1alpha1.Subscription{}, constant.SubscriptionPackageIndexField, SubscriptionPackageIndexValues, "the Subscriptions of the namespace are listed to find the one of a package"},
+	{&olmv1alpha1.Subscription{}, constant.ManagedIndexField, ManagedIndexValues, "the Subscriptions managed by ODLM are selected by their label"},
+	{&olmv1alpha1.ClusterServiceVersion{}, constant.CSVPackageIndexField, CSVPackageIndexValues, "the ClusterServiceVersions of the namespace are listed to find the ones of a package"},
+}
+
+// setupIndexes adds the field indexes of the ODLM controllers to the cache of the manager,
+// it returns the fields the cache indexes
+func setupIndexes(mgr manager.Manager) map[string]bool {
+	indexedManagers.Lock()
+	defer indexedManagers.Unlock()
+	if indexed, ok := indexedManagers.managers[mgr]; ok {
+		return indexed
+	}
+	indexed := map[string]bool{}
+	for _, index := range fieldIndexes {
+		if err := mgr.GetFieldIndexer().IndexField(context.Background(), index.obj, index.field, index.extractValue); err != nil {
+			logging.Logger("index").Error(err, "failed to add the field index, "+index.fallback, "field", index.field)
+			continue
+		}
+		indexed[index.field] = true
+	}
+	indexedManagers.managers[mgr] = indexed
+	return indexed
+}
+
+// matchingFields returns the list option selecting the objects by the field index,
+// none when the cache doesn't have the index
+func (m *ODLMOperator) matchingFields(field, value string) []client.ListOption {
+	if !m.indexed[field] {
+		return nil
+	}
+	return []client.ListOption{client.MatchingFields{field: value}}
+}
+
+// RegistryIndexValues returns the namespace/name of the OperandRegistries requested by an OperandRequest
+func RegistryIndexValues(obj client.Object) []string {
+	requestInstance, ok := obj.(*apiv1alpha1.OperandRequest)
+	if !ok {
+		return nil
+	}
+	var values []string
+	seen := map[string]bool{}
+	for _, req := range requestInstance.Spec.Requests {
+		key := requestInstance.GetRegistryKey(req).String()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		values = append(values, key)
+	}
+	return values
+}
+
+// SubscriptionPackageIndexValues returns the package of a Subscription
+func SubscriptionPackageIndexValues(obj client.Object) []string {
+	sub, ok := obj.(*olmv1alpha1.Subscription)
+	if !ok || sub.Spec == nil || sub.Spec.Package == "" {
+		return nil
+	}
+	return []string{sub.Spec.Package}
+}
+
+// ManagedIndexValues returns the value of the ODLM label of an object
+func ManagedIndexValues(obj client.Object) []string {
+	value, ok := obj.GetLabels()[constant.OpreqLabel]
+	if !ok {
+		return nil
+	}
+	return []string{value}
+}
+
+// CSVPackageIndexValues returns the packages in the properties of a ClusterServiceVersion
+func CSVPackageIndexValues(obj client.Object) []string {
+	properties, ok := obj.GetAnnotations()[constant.CSVPropertiesAnnotation]
+	if !ok {
+		return nil
+	}
+	var parsed struct {
+		Properties []struct {
+			Type  string `json:"type"`
+			Value struct {
+				PackageName string `json:"packageName"`
+			} `json:"value"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(properties), &parsed); err != nil {
+		return nil
+	}
+	var values []string
+	for _, property := range parsed.Properties {
+		if property.Type == "olm.package" && property.Value.PackageName != "" {
+			values = append(values, property.Value.PackageName)
+		}
+	}
+	return values
+}
+
+// ListManagedSubscriptions lists the Subscriptions managed by ODLM from the cache
+func (m *ODLMOperator) ListManagedSubscriptions(ctx context.Context, opts ...client.ListOption) (*olmv1alpha1.SubscriptionList, error) {
+	subList := &olmv1alpha1.SubscriptionList{}
+	opts = append(opts, client.MatchingLabels{constant.OpreqLabel: "true"})
+	opts = append(opts, m.matchingFields(constant.ManagedIndexField, "true")...)
+	if err := m.Client.List(ctx, subList, opts...); err != nil {
+		return nil, err
+	}
+	return subList, nil
+}
+
+// ListClusterServiceVersionsByPackage lists the ClusterServiceVersions of the package in the namespace from the cache.
+// The packages are still matched in case the client doesn't support the field selector.
+func (m *ODLMOperator) ListClusterServiceVersionsByPackage(ctx context.Context, namespace, packageName string) ([]olmv1alpha1.ClusterServiceVersion, error) {
+	csvList := &olmv1alpha1.ClusterServiceVersionList{}
+	opts := append([]client.ListOption{client.InNamespace(namespace)}, m.matchingFields(constant.CSVPackageIndexField, packageName)...)
+	if err := m.Client.List(ctx, csvList, opts...); err != nil {
+		return nil, err
+	}
+	var csvs []olmv1alpha1.ClusterServiceVersion
+	for _, csv := range csvList.Items {
+		for _, name := range CSVPackageIndexValues(&csv) {
+			if name == packageName {
+				csvs = append(csvs, csv)
+				break
+			}
+		}
+	}
+	return csvs, nil
+}
+
+// PagedList lists the objects from the API server page by page, so a large collection
+// isn't returned by a single call
+func (m *ODLMOperator) PagedList(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	var items []runtime.Object
+	opts = append(opts, client.Limit(constant.DefaultListPageSize))
+	continueToken := ""
+	for {
+		if err := m.Reader.List(ctx, list, append(opts, client.Continue(continueToken))...); err != nil {
+			return err
+		}
+		page, err := apimeta.ExtractList(list)
+		if err != nil {
+			return err
+		}
+		// The next page is decoded into the same list, keep a copy of the items
+		for _, item := range page {
+			items = append(items, item.DeepCopyObject())
+		}
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			break
+		}
+	}
+	return apimeta.SetList(list, items)
+}
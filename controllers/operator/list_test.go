@@ -0,0 +1,147 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+)
+
+func requestOf(name, namespace string, requests ...apiv1alpha1.Request) *apiv1alpha1.OperandRequest {
+	return &apiv1alpha1.OperandRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       apiv1alpha1.OperandRequestSpec{Requests: requests},
+	}
+}
+
+func subscriptionOf(name, namespace, packageName string, managed bool) *olmv1alpha1.Subscription {
+	sub := &olmv1alpha1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       &olmv1alpha1.SubscriptionSpec{Package: packageName},
+	}
+	if managed {
+		sub.Labels = map[string]string{constant.OpreqLabel: "true"}
+	}
+	return sub
+}
+
+func csvOf(name, namespace, packageName string) *olmv1alpha1.ClusterServiceVersion {
+	return &olmv1alpha1.ClusterServiceVersion{ObjectMeta: metav1.ObjectMeta{
+		Name:      name,
+		Namespace: namespace,
+		Annotations: map[string]string{
+			constant.CSVPropertiesAnnotation: `{"properties":[{"type":"olm.gvk","value":{"group":"operator.ibm.com","kind":"Etcd","version":"v1"}},{"type":"olm.package","value":{"packageName":"` + packageName + `","version":"1.0.0"}}]}`,
+		},
+	}}
+}
+
+var _ = Describe("Listing", func() {
+
+	var (
+		ctx = context.Background()
+		m   *ODLMOperator
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(apiv1alpha1.AddToScheme(scheme))
+		utilruntime.Must(corev1.AddToScheme(scheme))
+		utilruntime.Must(olmv1alpha1.AddToScheme(scheme))
+		objs := []client.Object{
+			requestOf("local", "team-a", apiv1alpha1.Request{Registry: "common-service"}),
+			requestOf("twice", "team-b",
+				apiv1alpha1.Request{Registry: "common-service", RegistryNamespace: "team-a"},
+				apiv1alpha1.Request{Registry: "common-service", RegistryNamespace: "team-a"},
+			),
+			requestOf("other", "team-b", apiv1alpha1.Request{Registry: "common-service"}),
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "one", Namespace: "team-a"}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "two", Namespace: "team-a"}},
+			subscriptionOf("etcd-sub", "team-a", "etcd", true),
+			subscriptionOf("jenkins", "team-a", "jenkins", false),
+			subscriptionOf("etcd", "team-b", "etcd", true),
+			csvOf("etcd.v1.0.0", "team-a", "etcd"),
+			csvOf("etcd-proxy.v1.0.0", "team-a", "etcd-proxy"),
+			csvOf("jenkins.v1.0.0", "team-a", "jenkins"),
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+		m = &ODLMOperator{Client: c, Reader: c, Scheme: scheme}
+	})
+
+	It("Should index the OperandRequests by the registries they request", func() {
+		Expect(RegistryIndexValues(requestOf("local", "team-a", apiv1alpha1.Request{Registry: "common-service"}))).To(Equal([]string{"team-a/common-service"}))
+		Expect(RegistryIndexValues(requestOf("twice", "team-b",
+			apiv1alpha1.Request{Registry: "common-service", RegistryNamespace: "team-a"},
+			apiv1alpha1.Request{Registry: "common-service", RegistryNamespace: "team-a"},
+			apiv1alpha1.Request{Registry: "common-service"},
+		))).To(Equal([]string{"team-a/common-service", "team-b/common-service"}))
+		Expect(RegistryIndexValues(&corev1.Secret{})).To(BeEmpty())
+	})
+
+	It("Should list each OperandRequest using the registry once", func() {
+		requests, err := m.ListOperandRequestsByRegistry(ctx, types.NamespacedName{Namespace: "team-a", Name: "common-service"})
+		Expect(err).NotTo(HaveOccurred())
+		names := []string{}
+		for _, r := range requests {
+			names = append(names, r.Namespace+"/"+r.Name)
+		}
+		Expect(names).To(ConsistOf("team-a/local", "team-b/twice"))
+	})
+
+	It("Should index the Subscriptions and the ClusterServiceVersions by their package and label", func() {
+		Expect(SubscriptionPackageIndexValues(subscriptionOf("etcd-sub", "team-a", "etcd", true))).To(Equal([]string{"etcd"}))
+		Expect(SubscriptionPackageIndexValues(&olmv1alpha1.Subscription{})).To(BeEmpty())
+		Expect(ManagedIndexValues(subscriptionOf("etcd-sub", "team-a", "etcd", true))).To(Equal([]string{"true"}))
+		Expect(ManagedIndexValues(subscriptionOf("jenkins", "team-a", "jenkins", false))).To(BeEmpty())
+		Expect(CSVPackageIndexValues(csvOf("etcd.v1.0.0", "team-a", "etcd"))).To(Equal([]string{"etcd"}))
+		Expect(CSVPackageIndexValues(&olmv1alpha1.ClusterServiceVersion{})).To(BeEmpty())
+	})
+
+	It("Should find the Subscriptions and the ClusterServiceVersions of a package", func() {
+		sub, err := m.GetSubscription(ctx, "etcd", "team-a", "etcd")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sub.Name).To(Equal("etcd-sub"))
+
+		subList, err := m.ListManagedSubscriptions(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subList.Items).To(HaveLen(2))
+
+		// The package of the properties is matched exactly, etcd-proxy isn't a CSV of etcd
+		csvs, err := m.ListClusterServiceVersionsByPackage(ctx, "team-a", "etcd")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(csvs).To(HaveLen(1))
+		Expect(csvs[0].Name).To(Equal("etcd.v1.0.0"))
+	})
+
+	It("Should list all the pages from the API server", func() {
+		secretList := &corev1.SecretList{}
+		Expect(m.PagedList(ctx, secretList, client.InNamespace("team-a"))).To(Succeed())
+		Expect(secretList.Items).To(HaveLen(2))
+	})
+})
@@ -18,6 +18,7 @@ package operator
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -25,19 +26,23 @@ import (
 	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	operatorsv1 "github.com/operator-framework/operator-lifecycle-manager/pkg/package-server/apis/operators/v1"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
-	"k8s.io/klog"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
 	constant "github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/mirror"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/profile"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	odlmutil "github.com/IBM/operand-deployment-lifecycle-manager/pkg/util/v1"
 )
 
 // ODLMOperator is the struct for ODLM controllers
@@ -47,6 +52,10 @@ type ODLMOperator struct {
 	*rest.Config
 	Recorder record.EventRecorder
 	Scheme   *runtime.Scheme
+	// Discovery caches the API resources discovered from the API server
+	Discovery *odlmutil.CachedDiscovery
+	// indexed are the fields the cache indexes
+	indexed map[string]bool
 }
 
 // NewODLMOperator is the method to initialize an Operator struct
@@ -57,6 +66,9 @@ func NewODLMOperator(mgr manager.Manager, name string) *ODLMOperator {
 		Config:   mgr.GetConfig(),
 		Recorder: mgr.GetEventRecorderFor(name),
 		Scheme:   mgr.GetScheme(),
+		// The discovery is shared by the controllers, so the cache is dropped once for a CRD change
+		Discovery: getSharedDiscovery(mgr),
+		indexed:   setupIndexes(mgr),
 	}
 }
 
@@ -74,15 +86,7 @@ func (m *ODLMOperator) GetOperandRegistry(ctx context.Context, key types.Namespa
 	}
 
 	for i, o := range reg.Spec.Operators {
-		if o.Scope == "" {
-			reg.Spec.Operators[i].Scope = apiv1alpha1.ScopePrivate
-		}
-		if o.InstallMode == "" {
-			reg.Spec.Operators[i].InstallMode = apiv1alpha1.InstallModeNamespace
-		}
-		if o.InstallPlanApproval == "" {
-			reg.Spec.Operators[i].InstallPlanApproval = olmv1alpha1.ApprovalAutomatic
-		}
+		SetOperatorDefaults(&reg.Spec.Operators[i])
 		if o.SourceName == "" || o.SourceNamespace == "" {
 			catalogSourceName, catalogSourceNs, err := m.GetCatalogSourceFromPackage(ctx, o.PackageName, o.Namespace, o.Channel, key.Namespace, excludedCatalogSources)
 			if err != nil {
@@ -90,11 +94,17 @@ func (m *ODLMOperator) GetOperandRegistry(ctx context.Context, key types.Namespa
 			}
 
 			if catalogSourceName == "" || catalogSourceNs == "" {
-				klog.V(2).Infof("no catalogsource found for %v", o.PackageName)
+				logging.FromContext(ctx).V(logging.LevelChange).Info("no catalogsource found", "package", o.PackageName)
 			}
 
 			reg.Spec.Operators[i].SourceName, reg.Spec.Operators[i].SourceNamespace = catalogSourceName, catalogSourceNs
 		}
+		// Install the operator from the mirrors of the disconnected cluster
+		mirror.Rewrite(&reg.Spec.Operators[i])
+		// Schedule the operator pods with the placement profile of the OperatorConfig
+		if err := profile.ExpandOperator(&reg.Spec.Operators[i]); err != nil {
+			return reg, err
+		}
 	}
 	return reg, nil
 }
@@ -145,13 +155,14 @@ func (m *ODLMOperator) GetCatalogSourceFromPackage(ctx context.Context, packageN
 	}
 	number := len(packageManifestList.Items)
 
+	log := logging.FromContext(ctx, "package", packageName, "namespace", namespace, "channel", channel)
 	switch number {
 	case 0:
-		klog.V(2).Infof("Not found PackageManifest %s in the namespace %s has channel %s", packageName, namespace, channel)
+		log.V(logging.LevelChange).Info("Not found PackageManifest having the channel")
 		return "", "", nil
 	case 1:
 		if excludedCatalogSources != nil && util.Contains(excludedCatalogSources, packageManifestList.Items[0].Status.CatalogSource) {
-			klog.V(2).Infof("Not found available CatalogSource for PackageManifest %s in the namespace %s, CatalogSource %s is excluded from OperandRegistry annotations", packageName, namespace, packageManifestList.Items[0].Status.CatalogSource)
+			log.V(logging.LevelChange).Info("Not found available CatalogSource for PackageManifest, the CatalogSource is excluded from OperandRegistry annotations", "catalogSource", packageManifestList.Items[0].Status.CatalogSource)
 			return "", "", nil
 		}
 		return packageManifestList.Items[0].Status.CatalogSource, packageManifestList.Items[0].Status.CatalogSourceNamespace, nil
@@ -164,7 +175,7 @@ func (m *ODLMOperator) GetCatalogSourceFromPackage(ctx context.Context, packageN
 			catalogSourceCandidate = append(catalogSourceCandidate, CatalogSource{Name: pm.Status.CatalogSource, Namespace: pm.Status.CatalogSourceNamespace, OpNamespace: namespace, RegistryNamespace: registryNs})
 		}
 		if len(catalogSourceCandidate) == 0 {
-			klog.Errorf("Not found PackageManifest %s in the namespace %s has channel %s", packageName, namespace, channel)
+			log.Info("Not found PackageManifest having the channel")
 			return "", "", nil
 		}
 		// Sort CatalogSources by priority
@@ -195,22 +206,27 @@ func (m *ODLMOperator) ListOperandRegistry(ctx context.Context, label map[string
 		return nil, err
 	}
 	for index, item := range registryList.Items {
-		for i, o := range item.Spec.Operators {
-			if o.Scope == "" {
-				registryList.Items[index].Spec.Operators[i].Scope = apiv1alpha1.ScopePrivate
-			}
-			if o.InstallMode == "" {
-				registryList.Items[index].Spec.Operators[i].InstallMode = apiv1alpha1.InstallModeNamespace
-			}
-			if o.InstallPlanApproval == "" {
-				registryList.Items[index].Spec.Operators[i].InstallPlanApproval = olmv1alpha1.ApprovalAutomatic
-			}
+		for i := range item.Spec.Operators {
+			SetOperatorDefaults(&registryList.Items[index].Spec.Operators[i])
 		}
 	}
 
 	return registryList, nil
 }
 
+// SetOperatorDefaults sets the default scope, install mode and install plan approval of the operator
+func SetOperatorDefaults(o *apiv1alpha1.Operator) {
+	if o.Scope == "" {
+		o.Scope = apiv1alpha1.ScopePrivate
+	}
+	if o.InstallMode == "" {
+		o.InstallMode = apiv1alpha1.InstallModeNamespace
+	}
+	if o.InstallPlanApproval == "" {
+		o.InstallPlanApproval = olmv1alpha1.ApprovalAutomatic
+	}
+}
+
 // GetOperandConfig gets the OperandConfig
 func (m *ODLMOperator) GetOperandConfig(ctx context.Context, key types.NamespacedName) (*apiv1alpha1.OperandConfig, error) {
 	config := &apiv1alpha1.OperandConfig{}
@@ -261,40 +277,30 @@ func (m *ODLMOperator) ListOperandRequests(ctx context.Context, label map[string
 
 // ListOperandRequestsByRegistry list all the OperandRequests
 // using the specific OperandRegistry
-func (m *ODLMOperator) ListOperandRequestsByRegistry(ctx context.Context, key types.NamespacedName) (requestList []apiv1alpha1.OperandRequest, err error) {
-	requestCandidates := &apiv1alpha1.OperandRequestList{}
-	if err = m.Client.List(ctx, requestCandidates); err != nil {
-		return
-	}
-	// Set default value for all the OperandRequest
-	for _, item := range requestCandidates.Items {
-		for _, r := range item.Spec.Requests {
-			if r.RegistryNamespace == "" {
-				r.RegistryNamespace = item.GetNamespace()
-			}
-			if r.Registry == key.Name && r.RegistryNamespace == key.Namespace {
-				requestList = append(requestList, item)
-			}
-		}
-	}
-	return
+func (m *ODLMOperator) ListOperandRequestsByRegistry(ctx context.Context, key types.NamespacedName) ([]apiv1alpha1.OperandRequest, error) {
+	return m.listOperandRequestsByRegistryKey(ctx, key)
 }
 
 // ListOperandRequestsByConfig list all the OperandRequests
 // using the specific OperandConfig
-func (m *ODLMOperator) ListOperandRequestsByConfig(ctx context.Context, key types.NamespacedName) (requestList []apiv1alpha1.OperandRequest, err error) {
+func (m *ODLMOperator) ListOperandRequestsByConfig(ctx context.Context, key types.NamespacedName) ([]apiv1alpha1.OperandRequest, error) {
+	// The OperandConfig has the same namespace and name as the OperandRegistry
+	return m.listOperandRequestsByRegistryKey(ctx, key)
+}
+
+// listOperandRequestsByRegistryKey lists the OperandRequests having a request on the OperandRegistry key.
+// The cache index is used when it is available, the requests are still matched in case the
+// client doesn't support the field selector.
+func (m *ODLMOperator) listOperandRequestsByRegistryKey(ctx context.Context, key types.NamespacedName) (requestList []apiv1alpha1.OperandRequest, err error) {
 	requestCandidates := &apiv1alpha1.OperandRequestList{}
-	if err = m.Client.List(ctx, requestCandidates); err != nil {
+	if err = m.Client.List(ctx, requestCandidates, m.matchingFields(constant.RegistryIndexField, key.String())...); err != nil {
 		return
 	}
-	// Set default value for all the OperandRequest
 	for _, item := range requestCandidates.Items {
 		for _, r := range item.Spec.Requests {
-			if r.RegistryNamespace == "" {
-				r.RegistryNamespace = item.GetNamespace()
-			}
-			if r.Registry == key.Name && r.RegistryNamespace == key.Namespace {
+			if item.GetRegistryKey(r) == key {
 				requestList = append(requestList, item)
+				break
 			}
 		}
 	}
@@ -303,7 +309,7 @@ func (m *ODLMOperator) ListOperandRequestsByConfig(ctx context.Context, key type
 
 // GetSubscription gets Subscription by name and package name
 func (m *ODLMOperator) GetSubscription(ctx context.Context, name, namespace, packageName string) (*olmv1alpha1.Subscription, error) {
-	klog.V(3).Infof("Fetch Subscription: %s/%s", namespace, name)
+	logging.FromContext(ctx).V(logging.LevelDebug).Info("Fetch Subscription", "subscription", namespace+"/"+name)
 	sub := &olmv1alpha1.Subscription{}
 	subKey := types.NamespacedName{
 		Name:      name,
@@ -316,16 +322,17 @@ func (m *ODLMOperator) GetSubscription(ctx context.Context, name, namespace, pac
 		return nil, err
 	}
 
+	// The Subscription is looked up by its package in the cache index, the packages are
+	// still matched in case the client doesn't support the field selector
 	subList := &olmv1alpha1.SubscriptionList{}
-	if err := m.Client.List(ctx, subList, &client.ListOptions{
-		Namespace: namespace,
-	}); err != nil {
+	opts := append([]client.ListOption{client.InNamespace(namespace)}, m.matchingFields(constant.SubscriptionPackageIndexField, packageName)...)
+	if err := m.Client.List(ctx, subList, opts...); err != nil {
 		return nil, err
 	}
 
 	var subCandidates []olmv1alpha1.Subscription
 	for _, sub := range subList.Items {
-		if sub.Spec.Package == packageName {
+		if sub.Spec != nil && sub.Spec.Package == packageName {
 			subCandidates = append(subCandidates, sub)
 		}
 	}
@@ -345,7 +352,7 @@ func (m *ODLMOperator) GetSubscription(ctx context.Context, name, namespace, pac
 func (m *ODLMOperator) GetClusterServiceVersion(ctx context.Context, sub *olmv1alpha1.Subscription) (*olmv1alpha1.ClusterServiceVersion, error) {
 	// Check the ClusterServiceVersion status in the subscription
 	if sub.Status.InstalledCSV == "" {
-		klog.Warningf("The ClusterServiceVersion for Subscription %s is not ready. Will check it again", sub.Name)
+		logging.FromContext(ctx).Info("The ClusterServiceVersion for Subscription is not ready. Will check it again", "subscription", sub.Namespace+"/"+sub.Name)
 		return nil, nil
 	}
 
@@ -359,24 +366,77 @@ func (m *ODLMOperator) GetClusterServiceVersion(ctx context.Context, sub *olmv1a
 	}
 	if err := m.Client.Get(ctx, csvKey, csv); err != nil {
 		if apierrors.IsNotFound(err) {
-			klog.V(3).Infof("ClusterServiceVersion %s is not ready. Will check it when it is stable", sub.Name)
+			logging.FromContext(ctx).V(logging.LevelDebug).Info("ClusterServiceVersion is not ready. Will check it when it is stable", "subscription", sub.Namespace+"/"+sub.Name)
 			return nil, nil
 		}
 		return nil, errors.Wrapf(err, "failed to get ClusterServiceVersion %s/%s", csvNamespace, csvName)
 	}
 
-	klog.V(3).Infof("Get ClusterServiceVersion %s in the namespace %s", csvName, csvNamespace)
+	logging.FromContext(ctx).V(logging.LevelDebug).Info("Get ClusterServiceVersion", "clusterServiceVersion", csvNamespace+"/"+csvName)
+
 	return csv, nil
 }
 
+// GetALMExamples returns the custom resources of the alm-examples of the installed operator, or nil if it isn't installed
+func (m *ODLMOperator) GetALMExamples(ctx context.Context, registryInstance *apiv1alpha1.OperandRegistry, opt *apiv1alpha1.Operator) ([]unstructured.Unstructured, error) {
+	namespace := opt.Namespace
+	if namespace == "" {
+		namespace = registryInstance.Namespace
+	}
+	sub, err := m.GetSubscription(ctx, opt.Name, m.GetOperatorNamespace(opt.InstallMode, namespace), opt.PackageName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	csv, err := m.GetClusterServiceVersion(ctx, sub)
+	if err != nil || csv == nil {
+		return nil, err
+	}
+	almExamples := csv.GetAnnotations()["alm-examples"]
+	if almExamples == "" {
+		return nil, nil
+	}
+	var examples []unstructured.Unstructured
+	var objs []map[string]interface{}
+	if err := json.Unmarshal([]byte(almExamples), &objs); err != nil {
+		return nil, errors.Wrapf(err, "failed to convert alm-examples in the ClusterServiceVersion %s/%s to slice", csv.Namespace, csv.Name)
+	}
+	for _, obj := range objs {
+		examples = append(examples, unstructured.Unstructured{Object: obj})
+	}
+	return examples, nil
+}
+
 // GetOperatorNamespace returns the operator namespace based on the install mode
 func (m *ODLMOperator) GetOperatorNamespace(installMode, namespace string) string {
+	return OperatorNamespace(installMode, namespace)
+}
+
+// OperatorNamespace returns the namespace the operator is installed in with the install mode
+func OperatorNamespace(installMode, namespace string) string {
 	if installMode == apiv1alpha1.InstallModeCluster {
 		return constant.ClusterOperatorNamespace
 	}
 	return namespace
 }
 
+// IsNamespaceExcluded returns true when the namespace opts out of ODLM with the exclude label
+func (m *ODLMOperator) IsNamespaceExcluded(ctx context.Context, namespace string) (bool, error) {
+	if namespace == "" {
+		return false, nil
+	}
+	ns := &corev1.Namespace{}
+	if err := m.Reader.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get namespace %s", namespace)
+	}
+	return ns.GetLabels()[constant.ExcludeNamespaceLabel] == "true", nil
+}
+
 func (m *ODLMOperator) CheckLabel(unstruct unstructured.Unstructured, labels map[string]string) bool {
 	for k, v := range labels {
 		if !m.HasLabel(unstruct, k) {
@@ -389,6 +449,15 @@ func (m *ODLMOperator) CheckLabel(unstruct unstructured.Unstructured, labels map
 	return true
 }
 
+func (m *ODLMOperator) CheckAnnotation(unstruct unstructured.Unstructured, annotations map[string]string) bool {
+	for k, v := range annotations {
+		if unstruct.GetAnnotations()[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
 func (m *ODLMOperator) HasLabel(cr unstructured.Unstructured, labelName string) bool {
 	if cr.GetLabels() == nil {
 		return false
@@ -420,3 +489,21 @@ func (m *ODLMOperator) EnsureAnnotation(cr unstructured.Unstructured, annotation
 	}
 	cr.SetAnnotations(existingAnnotations)
 }
+
+// CreateOrUpdateConfigMap creates the ConfigMap, or updates its labels, owner references and data if it already exists
+func (m *ODLMOperator) CreateOrUpdateConfigMap(ctx context.Context, cm *corev1.ConfigMap) error {
+	err := m.Client.Create(ctx, cm)
+	if err == nil || !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	// ConfigMaps are filtered in the cache, get it from the API server
+	existingCM := &corev1.ConfigMap{}
+	if err := m.Reader.Get(ctx, types.NamespacedName{Namespace: cm.Namespace, Name: cm.Name}, existingCM); err != nil {
+		return err
+	}
+	existingCM.Labels = cm.Labels
+	existingCM.OwnerReferences = cm.OwnerReferences
+	existingCM.Data = cm.Data
+	return m.Client.Update(ctx, existingCM)
+}
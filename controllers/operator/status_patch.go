@@ -0,0 +1,91 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PatchStatus patches the status of the object with the changes made since the original, only if the object
+// isn't updated by someone else in the meantime. On a conflict, the latest object is read from the API server,
+// mutate makes the changes of the reconcile to it again, and the patch is retried. So a status written
+// concurrently is never overwritten by a patch computed from a stale object.
+func (m *ODLMOperator) PatchStatus(ctx context.Context, obj, original client.Object, mutate func(latest client.Object) error) error {
+	var reader client.Reader = m.Client
+	if m.Reader != nil {
+		reader = m.Reader
+	}
+	key := client.ObjectKeyFromObject(obj)
+	latest := obj
+	// The object may be updated by the reconcile itself, the patch is checked against its latest version
+	base := original.DeepCopyObject().(client.Object)
+	base.SetResourceVersion(obj.GetResourceVersion())
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if latest == nil {
+			latest = obj.DeepCopyObject().(client.Object)
+			if err := reader.Get(ctx, key, latest); err != nil {
+				return err
+			}
+			base = latest.DeepCopyObject().(client.Object)
+			if err := mutate(latest); err != nil {
+				return err
+			}
+		}
+		err := m.Client.Status().Patch(ctx, latest, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+		if apierrors.IsConflict(err) {
+			latest = nil
+		}
+		return err
+	})
+}
+
+// ReapplyStatus makes the changes of the reconcile, from the original status to the current one, to the latest status.
+// The fields the reconcile didn't change keep the values written concurrently, the status is a pointer to a struct.
+func ReapplyStatus(latest, original, current interface{}) error {
+	originalJSON, err := json.Marshal(original)
+	if err != nil {
+		return err
+	}
+	currentJSON, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	changes, err := jsonpatch.CreateMergePatch(originalJSON, currentJSON)
+	if err != nil {
+		return err
+	}
+	latestJSON, err := json.Marshal(latest)
+	if err != nil {
+		return err
+	}
+	merged, err := jsonpatch.MergePatch(latestJSON, changes)
+	if err != nil {
+		return err
+	}
+	// The fields removed by the changes are reset, unmarshaling would keep them
+	v := reflect.ValueOf(latest).Elem()
+	v.Set(reflect.Zero(v.Type()))
+	return json.Unmarshal(merged, latest)
+}
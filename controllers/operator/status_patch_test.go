@@ -0,0 +1,114 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Patching the status", func() {
+
+	var (
+		ctx = context.Background()
+		key = types.NamespacedName{Namespace: "ibm-common-services", Name: "common-service"}
+		c   client.Client
+		m   *ODLMOperator
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(apiv1alpha1.AddToScheme(scheme))
+		c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(&apiv1alpha1.OperandRegistry{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		}).Build()
+		m = &ODLMOperator{Client: c, Reader: c, Scheme: scheme}
+	})
+
+	It("Should patch the status without calling mutate when the object is unchanged", func() {
+		instance := &apiv1alpha1.OperandRegistry{}
+		Expect(c.Get(ctx, key, instance)).To(Succeed())
+		original := instance.DeepCopy()
+		instance.Status.Phase = apiv1alpha1.RegistryRunning
+
+		Expect(m.PatchStatus(ctx, instance, original, func(latest client.Object) error {
+			Fail("mutate is called without a conflict")
+			return nil
+		})).To(Succeed())
+
+		Expect(c.Get(ctx, key, instance)).To(Succeed())
+		Expect(instance.Status.Phase).To(Equal(apiv1alpha1.RegistryRunning))
+	})
+
+	It("Should apply the changes to the latest object on a conflict", func() {
+		instance := &apiv1alpha1.OperandRegistry{}
+		Expect(c.Get(ctx, key, instance)).To(Succeed())
+		original := instance.DeepCopy()
+		instance.Status.Phase = apiv1alpha1.RegistryRunning
+
+		// Another reconcile writes the status in the meantime
+		concurrent := original.DeepCopy()
+		concurrent.Status.Phase = apiv1alpha1.RegistryReady
+		concurrent.Status.Conditions = []apiv1alpha1.Condition{{Type: apiv1alpha1.ConditionReady, Reason: "concurrent"}}
+		Expect(c.Status().Update(ctx, concurrent)).To(Succeed())
+
+		calls := 0
+		Expect(m.PatchStatus(ctx, instance, original, func(latest client.Object) error {
+			calls++
+			registry := latest.(*apiv1alpha1.OperandRegistry)
+			Expect(registry.Status.Conditions).To(HaveLen(1))
+			registry.Status.Phase = apiv1alpha1.RegistryRunning
+			return nil
+		})).To(Succeed())
+		Expect(calls).To(Equal(1))
+
+		Expect(c.Get(ctx, key, instance)).To(Succeed())
+		Expect(instance.Status.Phase).To(Equal(apiv1alpha1.RegistryRunning))
+		Expect(instance.Status.Conditions).To(HaveLen(1))
+	})
+
+	It("Should only reapply the changes of the reconcile to the latest status", func() {
+		original := &apiv1alpha1.OperandRequestStatus{
+			Phase:   apiv1alpha1.ClusterPhaseCreating,
+			Members: []apiv1alpha1.MemberStatus{{Name: "etcd"}},
+		}
+		current := original.DeepCopy()
+		current.Phase = apiv1alpha1.ClusterPhaseRunning
+		current.Members = nil
+
+		// Another reconcile records a history entry and a condition in the meantime
+		latest := original.DeepCopy()
+		latest.History = []apiv1alpha1.HistoryEntry{{Action: apiv1alpha1.HistoryMemberFailed, Operator: "etcd"}}
+		latest.Conditions = []apiv1alpha1.Condition{{Type: apiv1alpha1.ConditionReady, Reason: "concurrent"}}
+
+		Expect(ReapplyStatus(latest, original, current)).To(Succeed())
+		Expect(latest.Phase).To(Equal(apiv1alpha1.ClusterPhaseRunning))
+		Expect(latest.Members).To(BeEmpty())
+		Expect(latest.History).To(HaveLen(1))
+		Expect(latest.Conditions).To(HaveLen(1))
+	})
+})
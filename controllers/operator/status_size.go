@@ -0,0 +1,101 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	constant "github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+// GuardStatusSize keeps the status of an ODLM resource within the size budget before it is written to etcd.
+// It warns when the status grows over the warning threshold. When the status exceeds the limit,
+// the full status is saved into a companion ConfigMap and truncate is called to trim the status,
+// with the name of the ConfigMap, so that the truncated status can point to it.
+// The status must be passed as a pointer so that the truncated result can be measured again.
+func (m *ODLMOperator) GuardStatusSize(ctx context.Context, obj client.Object, status interface{}, truncate func(configMapName string)) error {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if gvk, err := apiutil.GVKForObject(obj, m.Scheme); err == nil {
+		kind = gvk.Kind
+	}
+	key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+
+	size, warning, err := util.CheckSizeBudget("status of "+kind+" "+key.String(), status, constant.StatusSizeWarningThreshold, constant.StatusSizeLimit)
+	if err == nil {
+		if warning {
+			logging.FromContext(ctx).Info("the status is approaching the size limit", "kind", kind, "key", key.String(), "size", size, "limit", constant.StatusSizeLimit)
+			m.Recorder.Eventf(obj, corev1.EventTypeWarning, "StatusSizeWarning", "The status is %d bytes, which is approaching the size limit of %d bytes", size, constant.StatusSizeLimit)
+		}
+		return nil
+	}
+	if _, ok := err.(*util.SizeExceededError); !ok {
+		return errors.Wrapf(err, "failed to measure the status of %s %s", kind, key.String())
+	}
+
+	configMapName := obj.GetName() + constant.StatusOverflowSuffix
+	if err := m.saveStatusOverflow(ctx, obj, configMapName, status); err != nil {
+		return errors.Wrapf(err, "failed to save the full status of %s %s", kind, key.String())
+	}
+
+	truncate(configMapName)
+
+	logging.FromContext(ctx).Info("the status exceeds the size limit, it is truncated and the full status is saved in a ConfigMap", "kind", kind, "key", key.String(), "size", size, "limit", constant.StatusSizeLimit, "configMap", key.Namespace+"/"+configMapName)
+
+	m.Recorder.Eventf(obj, corev1.EventTypeWarning, "StatusTruncated", "The status is %d bytes and exceeds the size limit of %d bytes, the full status is saved in ConfigMap %s", size, constant.StatusSizeLimit, configMapName)
+
+	if _, _, err := util.CheckSizeBudget("truncated status of "+kind+" "+key.String(), status, 0, constant.StatusSizeLimit); err != nil {
+		return err
+	}
+	return nil
+}
+
+// saveStatusOverflow saves the full status into a ConfigMap owned by the ODLM resource
+func (m *ODLMOperator) saveStatusOverflow(ctx context.Context, obj client.Object, name string, status interface{}) error {
+	raw, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: obj.GetNamespace(),
+			Labels: map[string]string{
+				constant.StatusOverflowLabel: "true",
+			},
+		},
+		Data: map[string]string{
+			constant.StatusOverflowKey: string(raw),
+		},
+	}
+	if err := controllerutil.SetOwnerReference(obj, cm, m.Scheme); err != nil {
+		return err
+	}
+
+	return m.CreateOrUpdateConfigMap(ctx, cm)
+}
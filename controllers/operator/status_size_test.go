@@ -0,0 +1,123 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+)
+
+var _ = Describe("Guarding the status size", func() {
+
+	var (
+		ctx = context.Background()
+		c   client.Client
+		m   *ODLMOperator
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+		utilruntime.Must(apiv1alpha1.AddToScheme(scheme))
+		c = fake.NewClientBuilder().WithScheme(scheme).Build()
+		m = &ODLMOperator{Client: c, Reader: c, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+	})
+
+	It("Should truncate an OperandRequest with an oversized members list", func() {
+		request := &apiv1alpha1.OperandRequest{ObjectMeta: metav1.ObjectMeta{Name: "common-service", Namespace: "ibm-common-services", UID: "uid"}}
+		lastError := strings.Repeat("the operator keeps failing ", 150)
+		for i := 0; i < 2000; i++ {
+			member := apiv1alpha1.MemberStatus{
+				Name:    fmt.Sprintf("operator-%d", i),
+				Phase:   apiv1alpha1.MemberPhase{OperatorPhase: apiv1alpha1.OperatorRunning, OperandPhase: apiv1alpha1.ServiceRunning},
+				Upgrade: &apiv1alpha1.UpgradeStatus{From: "operator.v1.0.0", To: "operator.v1.1.0", Path: "replaces"},
+				OperandCRList: []apiv1alpha1.OperandCRMember{
+					{Name: "example", Kind: "Example", APIVersion: "operator.ibm.com/v1"},
+					{Name: "example-backup", Kind: "ExampleBackup", APIVersion: "operator.ibm.com/v1"},
+				},
+			}
+			if i%5 == 0 {
+				member.Phase.OperandPhase = apiv1alpha1.ServiceFailed
+				member.Retry = &apiv1alpha1.MemberRetry{Retries: 3, LastError: lastError}
+			}
+			request.Status.Members = append(request.Status.Members, member)
+		}
+		for i := 0; i < 30; i++ {
+			request.Status.History = append(request.Status.History, apiv1alpha1.HistoryEntry{Action: apiv1alpha1.HistoryMemberFailed, Operator: fmt.Sprintf("operator-%d", i), Message: lastError})
+		}
+
+		Expect(m.GuardStatusSize(ctx, request, &request.Status, request.TruncateStatus)).To(Succeed())
+
+		raw, err := json.Marshal(request.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(raw)).To(BeNumerically("<", constant.StatusSizeLimit))
+
+		// All the members are kept with their phases, only the failed ones keep their custom resources
+		Expect(request.Status.Members).To(HaveLen(2000))
+		for i, member := range request.Status.Members {
+			Expect(member.Name).To(Equal(fmt.Sprintf("operator-%d", i)))
+			Expect(member.Phase.OperatorPhase).To(Equal(apiv1alpha1.OperatorRunning))
+			Expect(member.Upgrade).To(BeNil())
+			if i%5 != 0 {
+				Expect(member.Phase.OperandPhase).To(Equal(apiv1alpha1.ServiceRunning))
+				Expect(member.OperandCRList).To(BeEmpty())
+				continue
+			}
+			Expect(member.Phase.OperandPhase).To(Equal(apiv1alpha1.ServiceFailed))
+			Expect(member.OperandCRList).To(HaveLen(2))
+			Expect(len(member.Retry.LastError)).To(BeNumerically("<=", apiv1alpha1.MaxMessageLengthAfterTruncation+3))
+		}
+		Expect(request.Status.History).To(HaveLen(apiv1alpha1.MaxHistoryAfterTruncation))
+		Expect(request.Status.Conditions).To(ContainElement(WithTransform(func(c apiv1alpha1.Condition) string { return c.Message },
+			ContainSubstring("the custom resources of 1600 running members are left out"))))
+
+		overflow := &corev1.ConfigMap{}
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: "ibm-common-services", Name: "common-service" + constant.StatusOverflowSuffix}, overflow)).To(Succeed())
+	})
+
+	It("Should cut the long messages without splitting a character", func() {
+		request := &apiv1alpha1.OperandRequest{}
+		request.Status.Members = []apiv1alpha1.MemberStatus{{
+			Name:  "operator",
+			Retry: &apiv1alpha1.MemberRetry{Retries: 1, LastError: "a" + strings.Repeat("é", apiv1alpha1.MaxMessageLengthAfterTruncation)},
+		}}
+		request.TruncateStatus("common-service" + constant.StatusOverflowSuffix)
+
+		lastError := request.Status.Members[0].Retry.LastError
+		Expect(utf8.ValidString(lastError)).To(BeTrue())
+		Expect(lastError).To(HaveSuffix("é..."))
+		Expect(len(lastError)).To(BeNumerically("<=", apiv1alpha1.MaxMessageLengthAfterTruncation+3))
+	})
+})
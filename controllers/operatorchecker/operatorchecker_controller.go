@@ -18,16 +18,24 @@ package operatorchecker
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
 	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
-	"k8s.io/klog"
+	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
 	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/ownership"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/shard"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/throughput"
 )
 
 // Reconciler reconciles a OperatorChecker object
@@ -37,29 +45,32 @@ type Reconciler struct {
 
 // Reconcile watchs on the Subscription of the target namespace and apply the recovery to fixing the Subscription failed error
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reconcileErr error) {
+	// The operator checker can be disabled at runtime by the OperatorConfig
+	if util.GetoperatorCheckerMode() {
+		return ctrl.Result{}, nil
+	}
+	settings := Get()
+
+	ctx, log := logging.Reconcile(ctx, "operatorchecker", "subscription", req.NamespacedName.String())
+
 	subscriptionInstance, err := r.getSubscription(ctx, req)
 	if err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	klog.V(2).Info("Operator Checker is monitoring Subscription...")
-
-	if _, ok := subscriptionInstance.Labels[constant.OpreqLabel]; !ok {
-		return ctrl.Result{RequeueAfter: constant.DefaultRequeueDuration}, nil
+	// The Subscriptions out of the scope are checked again in case the scope changes
+	if _, ok := subscriptionInstance.Labels[constant.OpreqLabel]; !ok || !settings.InScope(subscriptionInstance) {
+		return ctrl.Result{RequeueAfter: settings.Next()}, nil
 	}
 
+	log.V(logging.LevelChange).Info("Operator Checker is monitoring Subscription...")
+
 	if subscriptionInstance.Status.CurrentCSV == "" && subscriptionInstance.Status.State == "" {
 		// cover fresh install case
-		csvList, err := r.getCSVBySubscription(ctx, subscriptionInstance)
-		if err != nil {
-			klog.Error(err)
-			return ctrl.Result{RequeueAfter: constant.DefaultRequeueDuration}, nil
-		}
-		if len(csvList) != 1 {
-			klog.Warning("Not found matched CSV, CSVList length: ", len(csvList))
-			return ctrl.Result{RequeueAfter: constant.DefaultRequeueDuration}, nil
+		csv, ok, err := r.getStuckCSV(ctx, subscriptionInstance)
+		if err != nil || !ok {
+			return ctrl.Result{RequeueAfter: settings.Next()}, nil
 		}
-		csv := csvList[0]
 
 		time.Sleep(constant.DefaultCSVWaitPeriod)
 		subscriptionInstance, err := r.getSubscription(ctx, req)
@@ -70,21 +81,17 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 			if err = r.deleteCSV(ctx, csv.Name, csv.Namespace); err != nil {
 				return ctrl.Result{}, client.IgnoreNotFound(err)
 			}
+			r.recordFinding(ctx, subscriptionInstance, corev1.EventTypeNormal, constant.EventReasonOperatorRecovered,
+				fmt.Sprintf("Deleted the ClusterServiceVersion %s the Subscription %s doesn't reference, to install it again", csv.Name, subscriptionInstance.Name))
 		}
 	}
 
 	if subscriptionInstance.Status.State == "UpgradePending" && subscriptionInstance.Status.CurrentCSV != "" && subscriptionInstance.Status.InstalledCSV != "" {
 		// cover upgrade case
-		csvList, err := r.getCSVBySubscription(ctx, subscriptionInstance)
-		if err != nil {
-			klog.Error(err)
-			return ctrl.Result{RequeueAfter: constant.DefaultRequeueDuration}, nil
+		csv, ok, err := r.getStuckCSV(ctx, subscriptionInstance)
+		if err != nil || !ok {
+			return ctrl.Result{RequeueAfter: settings.Next()}, nil
 		}
-		if len(csvList) != 1 {
-			klog.Warning("Not found matched CSV, CSVList length: ", len(csvList))
-			return ctrl.Result{RequeueAfter: constant.DefaultRequeueDuration}, nil
-		}
-		csv := csvList[0]
 
 		currentCSVVersion := ""
 		if len(strings.SplitN(subscriptionInstance.Status.CurrentCSV, ".", 2)) == 2 {
@@ -101,30 +108,54 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 				if err = r.deleteCSV(ctx, csv.Name, csv.Namespace); err != nil {
 					return ctrl.Result{}, client.IgnoreNotFound(err)
 				}
+				r.recordFinding(ctx, subscriptionInstance, corev1.EventTypeNormal, constant.EventReasonOperatorRecovered,
+					fmt.Sprintf("Deleted the ClusterServiceVersion %s blocking the upgrade of the Subscription %s to %s", csv.Name, subscriptionInstance.Name, subscriptionInstance.Status.CurrentCSV))
 			}
 		}
 	}
 
-	return ctrl.Result{RequeueAfter: constant.DefaultRequeueDuration}, nil
+	return ctrl.Result{RequeueAfter: settings.Next()}, nil
 }
 
-func (r *Reconciler) getCSVBySubscription(ctx context.Context, subscriptionInstance *olmv1alpha1.Subscription) ([]olmv1alpha1.ClusterServiceVersion, error) {
-	csvList := &olmv1alpha1.ClusterServiceVersionList{}
-	opts := []client.ListOption{
-		client.InNamespace(subscriptionInstance.Namespace),
+// getStuckCSV returns the ClusterServiceVersion of the package of the Subscription stuck in OLM,
+// and false when there is none or several of them, the checker can't tell which one to recover
+func (r *Reconciler) getStuckCSV(ctx context.Context, subscriptionInstance *olmv1alpha1.Subscription) (*olmv1alpha1.ClusterServiceVersion, bool, error) {
+	log := logging.FromContext(ctx)
+	csvList, err := r.getCSVBySubscription(ctx, subscriptionInstance)
+	if err != nil {
+		log.Error(err, "failed to list the ClusterServiceVersions of the Subscription")
+		return nil, false, err
 	}
-	if err := r.Client.List(ctx, csvList, opts...); err != nil {
-		return nil, client.IgnoreNotFound(err)
+	if len(csvList) != 1 {
+		log.Info("Not found matched CSV", "count", len(csvList))
+		if len(csvList) > 1 {
+			r.recordFinding(ctx, subscriptionInstance, corev1.EventTypeWarning, constant.EventReasonOperatorStuck,
+				fmt.Sprintf("The Subscription %s is stuck in OLM, and its package %s has %d ClusterServiceVersions", subscriptionInstance.Name, subscriptionInstance.Spec.Package, len(csvList)))
+		}
+		return nil, false, nil
 	}
+	return &csvList[0], true, nil
+}
 
-	var matchCSVList = []olmv1alpha1.ClusterServiceVersion{}
-	for _, csv := range csvList.Items {
-		csvPackageName := csv.GetAnnotations()["operatorframework.io/properties"]
-		if strings.Contains(csvPackageName, subscriptionInstance.Spec.Package) {
-			matchCSVList = append(matchCSVList, csv)
+// recordFinding records the finding of the operator checker on the Subscription and on the OperandRequests it is created for
+func (r *Reconciler) recordFinding(ctx context.Context, sub *olmv1alpha1.Subscription, eventType, reason, message string) {
+	logging.FromContext(ctx).Info(message, "reason", reason)
+	r.Recorder.Event(sub, eventType, reason, message)
+	for _, requestKey := range ownership.Requests(sub) {
+		requestInstance := &operatorv1alpha1.OperandRequest{}
+		if err := r.Client.Get(ctx, requestKey, requestInstance); err != nil {
+			continue
 		}
+		r.Recorder.Event(requestInstance, eventType, reason, message)
+	}
+}
+
+func (r *Reconciler) getCSVBySubscription(ctx context.Context, subscriptionInstance *olmv1alpha1.Subscription) ([]olmv1alpha1.ClusterServiceVersion, error) {
+	csvList, err := r.ListClusterServiceVersionsByPackage(ctx, subscriptionInstance.Namespace, subscriptionInstance.Spec.Package)
+	if err != nil {
+		return nil, client.IgnoreNotFound(err)
 	}
-	return matchCSVList, nil
+	return csvList, nil
 }
 
 func (r *Reconciler) getSubscription(ctx context.Context, req ctrl.Request) (*olmv1alpha1.Subscription, error) {
@@ -148,6 +179,11 @@ func (r *Reconciler) deleteCSV(ctx context.Context, name, namespace string) erro
 
 // SetupWithManager adds subscription to watch to the manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	fair := throughput.NewFairQueue("operatorchecker")
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&olmv1alpha1.Subscription{}).Complete(r)
+		WithOptions(throughput.ControllerOptions("operatorchecker")).
+		For(&olmv1alpha1.Subscription{}).
+		// The requests are dispatched in turn across the namespaces by the fair queue
+		Watches(fair.Source(), &handler.EnqueueRequestForObject{}).
+		Complete(fair.Reconciler(shard.Reconciler(r)))
 }
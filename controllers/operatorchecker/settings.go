@@ -0,0 +1,126 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operatorchecker
+
+import (
+	"flag"
+	"math/rand"
+	"sync"
+	"time"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+)
+
+const (
+	// DefaultInterval is the default time between two checks of a Subscription
+	DefaultInterval = 20 * time.Second
+	// MinInterval is the shortest time between two checks of a Subscription
+	MinInterval = 10 * time.Second
+	// DefaultJitter is the default percentage of the interval added at random to each check
+	DefaultJitter = 10
+)
+
+// Options are the flags of the operator checker
+type Options struct {
+	// Interval is the time between two checks of a Subscription
+	Interval time.Duration
+	// Jitter is the percentage of the interval added at random to each check
+	Jitter int
+}
+
+// BindFlags binds the options of the operator checker to the flags
+func (o *Options) BindFlags(fs *flag.FlagSet) {
+	fs.DurationVar(&o.Interval, "operator-checker-interval", DefaultInterval, "The time between two checks of a Subscription by the operator checker.")
+	fs.IntVar(&o.Jitter, "operator-checker-jitter", DefaultJitter, "The percentage of the interval added at random to each check of the operator checker.")
+}
+
+// Settings are the interval and the scope the operator checker runs with
+type Settings struct {
+	Interval time.Duration
+	Jitter   int
+	// Namespaces are the namespaces of the Subscriptions checked, all the namespaces when empty
+	Namespaces []string
+	// Packages are the packages of the Subscriptions checked, all the packages when empty
+	Packages []string
+}
+
+// configured holds the settings of the flags and the ones the operator checker is running with
+var configured = struct {
+	sync.RWMutex
+	defaults Settings
+	running  Settings
+}{
+	defaults: Settings{Interval: DefaultInterval, Jitter: DefaultJitter},
+	running:  Settings{Interval: DefaultInterval, Jitter: DefaultJitter},
+}
+
+// Setup sets the settings of the flags, the operator checker runs with them unless the OperatorConfig overrides them
+func Setup(o Options) {
+	configured.Lock()
+	defer configured.Unlock()
+	configured.defaults = Settings{Interval: o.Interval, Jitter: o.Jitter}
+	configured.running = Settings{Interval: o.Interval, Jitter: o.Jitter}
+}
+
+// GetDefaults returns the settings of the flags
+func GetDefaults() Settings {
+	configured.RLock()
+	defer configured.RUnlock()
+	return configured.defaults
+}
+
+// Set sets the settings the operator checker runs with at runtime
+func Set(s Settings) {
+	configured.Lock()
+	defer configured.Unlock()
+	configured.running = s
+}
+
+// Get returns the settings the operator checker is running with
+func Get() Settings {
+	configured.RLock()
+	defer configured.RUnlock()
+	return configured.running
+}
+
+// InScope returns true if the Subscription is in the namespaces and the packages checked
+func (s Settings) InScope(sub *olmv1alpha1.Subscription) bool {
+	if len(s.Namespaces) != 0 && !contains(s.Namespaces, sub.Namespace) {
+		return false
+	}
+	if len(s.Packages) != 0 && (sub.Spec == nil || !contains(s.Packages, sub.Spec.Package)) {
+		return false
+	}
+	return true
+}
+
+// Next returns the time until the next check of a Subscription, the interval and up to jitter percent of it
+func (s Settings) Next() time.Duration {
+	if s.Jitter <= 0 {
+		return s.Interval
+	}
+	return s.Interval + time.Duration(rand.Int63n(int64(s.Interval)*int64(s.Jitter)/100+1))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operatorchecker
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Operator checker settings", func() {
+
+	subscription := func(namespace, packageName string) *olmv1alpha1.Subscription {
+		return &olmv1alpha1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{Name: packageName, Namespace: namespace},
+			Spec:       &olmv1alpha1.SubscriptionSpec{Package: packageName},
+		}
+	}
+
+	It("Should check the Subscriptions in the namespaces and the packages of the scope", func() {
+		Expect(Settings{}.InScope(subscription("ibm-common-services", "etcd"))).Should(BeTrue())
+
+		settings := Settings{Namespaces: []string{"ibm-common-services"}, Packages: []string{"etcd", "jenkins"}}
+		Expect(settings.InScope(subscription("ibm-common-services", "etcd"))).Should(BeTrue())
+		Expect(settings.InScope(subscription("team-a", "etcd"))).Should(BeFalse())
+		Expect(settings.InScope(subscription("ibm-common-services", "mongodb"))).Should(BeFalse())
+	})
+
+	It("Should add up to the jitter to the interval", func() {
+		Expect(Settings{Interval: time.Minute}.Next()).Should(Equal(time.Minute))
+
+		settings := Settings{Interval: time.Minute, Jitter: 50}
+		for i := 0; i < 100; i++ {
+			next := settings.Next()
+			Expect(next).Should(BeNumerically(">=", time.Minute))
+			Expect(next).Should(BeNumerically("<=", 90*time.Second))
+		}
+	})
+
+	It("Should run with the settings of the flags until the OperatorConfig sets them", func() {
+		defer Setup(Options{Interval: DefaultInterval, Jitter: DefaultJitter})
+		Setup(Options{Interval: time.Minute, Jitter: 20})
+		Expect(Get()).Should(Equal(Settings{Interval: time.Minute, Jitter: 20}))
+
+		Set(Settings{Interval: 30 * time.Second, Namespaces: []string{"ibm-common-services"}})
+		Expect(Get().Namespaces).Should(Equal([]string{"ibm-common-services"}))
+		Expect(GetDefaults()).Should(Equal(Settings{Interval: time.Minute, Jitter: 20}))
+	})
+})
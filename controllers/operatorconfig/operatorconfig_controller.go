@@ -0,0 +1,539 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operatorconfig
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/callback"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/garbagecollector"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/mirror"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/operatorchecker"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/profile"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/shard"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/metrics"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/throughput"
+)
+
+// Reconciler reconciles a OperatorConfig object
+type Reconciler struct {
+	*deploy.ODLMOperator
+}
+
+// Reconcile applies the settings of the OperatorConfig to the running ODLM.
+// The isolated mode and the throughput can't be changed at runtime, they are applied when ODLM restarts.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, log := logging.Reconcile(ctx, "operatorconfig", "config", req.NamespacedName.String())
+
+	instance := &operatorv1alpha1.OperatorConfig{}
+	if err := r.Client.Get(ctx, req.NamespacedName, instance); err != nil {
+		if apierrors.IsNotFound(err) && isOperatorConfig(req.NamespacedName) {
+			log.Info("OperatorConfig is deleted, apply the settings from the environment variables")
+			Apply(EnvSettings(), false)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	originalInstance := instance.DeepCopy()
+
+	if !isOperatorConfig(req.NamespacedName) {
+		instance.SetPhase(operatorv1alpha1.OperatorConfigIgnored, fmt.Sprintf("ODLM only reads the OperatorConfig %s in the namespace %s", operatorv1alpha1.OperatorConfigName, util.GetOperatorNamespace()), nil)
+	} else {
+		log.V(logging.LevelFlow).Info("Reconciling OperatorConfig")
+		settings := instance.GetSettings(EnvSettings())
+		if err := Validate(settings); err != nil {
+			running := RunningSettings()
+			instance.SetPhase(operatorv1alpha1.OperatorConfigInvalid, err.Error(), &running)
+		} else {
+			Apply(settings, false)
+			running := RunningSettings()
+			if restart := restartRequired(settings, running); len(restart) != 0 {
+				instance.SetPhase(operatorv1alpha1.OperatorConfigRestartRequired, fmt.Sprintf("The %s take effect when ODLM restarts", strings.Join(restart, " and ")), &running)
+			} else {
+				instance.SetPhase(operatorv1alpha1.OperatorConfigApplied, "", &running)
+			}
+		}
+	}
+
+	if reflect.DeepEqual(originalInstance.Status, instance.Status) {
+		return ctrl.Result{}, nil
+	}
+	if err := r.PatchStatus(ctx, instance, originalInstance, func(latest client.Object) error {
+		// The report of the garbage collection is written by the collector
+		status := instance.Status.DeepCopy()
+		status.GarbageCollection = latest.(*operatorv1alpha1.OperatorConfig).Status.GarbageCollection
+		latest.(*operatorv1alpha1.OperatorConfig).Status = *status
+		return nil
+	}); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to update the status of OperatorConfig %s", req.NamespacedName)
+	}
+	return ctrl.Result{}, nil
+}
+
+// Load reads the OperatorConfig when ODLM starts and applies its settings.
+// The OperatorConfig is created from the environment variables if it doesn't exist.
+func Load(ctx context.Context, c client.Client, namespace string) error {
+	instance := &operatorv1alpha1.OperatorConfig{}
+	key := types.NamespacedName{Namespace: namespace, Name: operatorv1alpha1.OperatorConfigName}
+	if err := c.Get(ctx, key, instance); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get the OperatorConfig %s", key)
+		}
+		instance = operatorv1alpha1.NewOperatorConfig(namespace, EnvSettings())
+		if err := c.Create(ctx, instance); err != nil {
+			if !apierrors.IsAlreadyExists(err) {
+				return errors.Wrapf(err, "failed to create the OperatorConfig %s", key)
+			}
+			// Another replica starting at the same time created it first
+			if err := c.Get(ctx, key, instance); err != nil {
+				return errors.Wrapf(err, "failed to get the OperatorConfig %s", key)
+			}
+		} else {
+			logging.Logger("operatorconfig").Info("Created the OperatorConfig from the environment variables", "config", key.String())
+		}
+	}
+	settings := instance.GetSettings(EnvSettings())
+	if err := Validate(settings); err != nil {
+		return errors.Wrapf(err, "invalid OperatorConfig %s", key)
+	}
+	Apply(settings, true)
+	return nil
+}
+
+// EnvSettings returns the settings from the environment variables and the flags of the operator
+func EnvSettings() operatorv1alpha1.OperatorSettings {
+	verbosity, controllers := logging.GetDefaultVerbosity()
+	checker := operatorchecker.GetDefaults()
+	return operatorv1alpha1.OperatorSettings{
+		IsolatedMode:              util.GetIsolatedModeFromEnv(),
+		WatchNamespace:            util.GetWatchNamespaceFromEnv(),
+		WatchNamespaceSelector:    util.GetWatchNamespaceSelectorFromEnv(),
+		Shard:                     shard.GetDefaults(),
+		InstallScope:              util.GetInstallScopeFromEnv(),
+		OperatorChecker:           !util.GetoperatorCheckerModeFromEnv(),
+		OperatorCheckerInterval:   metav1.Duration{Duration: checker.Interval},
+		OperatorCheckerJitter:     checker.Jitter,
+		ForensicBundle:            util.GetForensicBundleFromEnv(),
+		ForensicBundleDirectory:   util.GetForensicBundleDirFromEnv(),
+		MaxParallelInstalls:       util.GetMaxParallelInstallsFromEnv(),
+		Verbosity:                 verbosity,
+		ControllerVerbosities:     controllerVerbosities(controllers),
+		Throughput:                throughput.GetDefaults(),
+		RecreatePolicy:            util.GetRecreatePolicyFromEnv(),
+		RepairOperatorGroups:      util.GetRepairOperatorGroupsFromEnv(),
+		MaxMetricLabelValues:      metrics.DefaultMaxLabelValues,
+		GarbageCollectionMode:     operatorv1alpha1.GarbageCollectionDisabled,
+		GarbageCollectionInterval: metav1.Duration{Duration: garbagecollector.DefaultInterval},
+	}
+}
+
+// RunningSettings returns the settings ODLM is running with
+func RunningSettings() operatorv1alpha1.OperatorSettings {
+	maxParallelInstalls, catalogs := util.GetInstallThrottle()
+	var catalogInstallLimits []operatorv1alpha1.CatalogInstallLimit
+	for key, limit := range catalogs {
+		namespace, name := splitCatalogKey(key)
+		catalogInstallLimits = append(catalogInstallLimits, operatorv1alpha1.CatalogInstallLimit{SourceName: name, SourceNamespace: namespace, MaxParallelInstalls: limit})
+	}
+	sort.Slice(catalogInstallLimits, func(i, j int) bool {
+		if catalogInstallLimits[i].SourceNamespace != catalogInstallLimits[j].SourceNamespace {
+			return catalogInstallLimits[i].SourceNamespace < catalogInstallLimits[j].SourceNamespace
+		}
+		return catalogInstallLimits[i].SourceName < catalogInstallLimits[j].SourceName
+	})
+	verbosity, controllers := logging.GetVerbosity()
+	catalogMirrors, registryMirrors := mirror.Get()
+	gcMode, gcInterval := garbagecollector.Get()
+	checker := operatorchecker.Get()
+	return operatorv1alpha1.OperatorSettings{
+		IsolatedMode:              util.GetIsolatedMode(),
+		WatchNamespace:            util.GetWatchNamespace(),
+		WatchNamespaceSelector:    util.GetWatchNamespaceSelector(),
+		Shard:                     shard.Get(),
+		InstallScope:              util.GetInstallScope(),
+		OperatorChecker:           !util.GetoperatorCheckerMode(),
+		OperatorCheckerInterval:   metav1.Duration{Duration: checker.Interval},
+		OperatorCheckerJitter:     checker.Jitter,
+		OperatorCheckerNamespaces: checker.Namespaces,
+		OperatorCheckerPackages:   checker.Packages,
+		ForensicBundle:            util.GetForensicBundle(),
+		ForensicBundleDirectory:   util.GetForensicBundleDir(),
+		MaxParallelInstalls:       maxParallelInstalls,
+		CatalogInstallLimits:      catalogInstallLimits,
+		Verbosity:                 verbosity,
+		ControllerVerbosities:     controllerVerbosities(controllers),
+		Throughput:                throughput.Get(),
+		RecreatePolicy:            util.GetRecreatePolicy(),
+		RepairOperatorGroups:      util.GetRepairOperatorGroups(),
+		Callbacks:                 callback.Get(),
+		CatalogMirrors:            catalogMirrors,
+		RegistryMirrors:           registryMirrors,
+		PlacementProfiles:         profile.Get(),
+		MaxMetricLabelValues:      metrics.GetMaxLabelValues(),
+		GarbageCollectionMode:     gcMode,
+		GarbageCollectionInterval: metav1.Duration{Duration: gcInterval},
+	}
+}
+
+// Validate checks the settings
+func Validate(settings operatorv1alpha1.OperatorSettings) error {
+	switch settings.InstallScope {
+	case operatorv1alpha1.InstallScopeCluster, operatorv1alpha1.InstallScopeNamespaced:
+	default:
+		return fmt.Errorf("the installScope %s is not one of %s and %s", settings.InstallScope, operatorv1alpha1.InstallScopeCluster, operatorv1alpha1.InstallScopeNamespaced)
+	}
+	if settings.WatchNamespace != "" {
+		for _, namespace := range strings.Split(settings.WatchNamespace, ",") {
+			if errs := validation.IsDNS1123Label(namespace); len(errs) != 0 {
+				return fmt.Errorf("the watchNamespace %s is not a namespace: %s", namespace, strings.Join(errs, ", "))
+			}
+		}
+	}
+	if _, err := labels.Parse(settings.WatchNamespaceSelector); err != nil {
+		return fmt.Errorf("the watchNamespaceSelector %s is not a label selector: %v", settings.WatchNamespaceSelector, err)
+	}
+	if settings.Shard != "" {
+		if errs := validation.IsDNS1123Subdomain(settings.Shard); len(errs) != 0 {
+			return fmt.Errorf("the shard %s is not the name of an OperandShard: %s", settings.Shard, strings.Join(errs, ", "))
+		}
+	}
+	if settings.OperatorCheckerInterval.Duration < operatorchecker.MinInterval {
+		return fmt.Errorf("the operatorCheckerOptions interval %s is shorter than %s", settings.OperatorCheckerInterval.Duration, operatorchecker.MinInterval)
+	}
+	if settings.OperatorCheckerJitter < 0 || settings.OperatorCheckerJitter > 100 {
+		return fmt.Errorf("the operatorCheckerOptions jitter %d is not between 0 and 100", settings.OperatorCheckerJitter)
+	}
+	for _, namespace := range settings.OperatorCheckerNamespaces {
+		if namespace == "" {
+			return fmt.Errorf("the operatorCheckerOptions namespaces have an empty namespace")
+		}
+	}
+	for _, pkg := range settings.OperatorCheckerPackages {
+		if pkg == "" {
+			return fmt.Errorf("the operatorCheckerOptions packages have an empty package")
+		}
+	}
+	if settings.ForensicBundleDirectory != "" && !filepath.IsAbs(settings.ForensicBundleDirectory) {
+		return fmt.Errorf("the forensicBundle directory %s is not an absolute path", settings.ForensicBundleDirectory)
+	}
+	if settings.MaxMetricLabelValues < 0 {
+		return fmt.Errorf("the metrics maxLabelValues %d is negative", settings.MaxMetricLabelValues)
+	}
+	switch settings.GarbageCollectionMode {
+	case operatorv1alpha1.GarbageCollectionDisabled, operatorv1alpha1.GarbageCollectionDryRun, operatorv1alpha1.GarbageCollectionDelete:
+	default:
+		return fmt.Errorf("the garbageCollection mode %s is not one of %s, %s and %s", settings.GarbageCollectionMode, operatorv1alpha1.GarbageCollectionDisabled, operatorv1alpha1.GarbageCollectionDryRun, operatorv1alpha1.GarbageCollectionDelete)
+	}
+	if settings.GarbageCollectionInterval.Duration < garbagecollector.MinInterval {
+		return fmt.Errorf("the garbageCollection interval %s is shorter than %s", settings.GarbageCollectionInterval.Duration, garbagecollector.MinInterval)
+	}
+	if settings.MaxParallelInstalls < 0 {
+		return fmt.Errorf("the installThrottle maxParallelInstalls %d is negative", settings.MaxParallelInstalls)
+	}
+	catalogs := make(map[string]bool)
+	for _, c := range settings.CatalogInstallLimits {
+		if c.SourceName == "" || c.SourceNamespace == "" {
+			return fmt.Errorf("the installThrottle catalog %s/%s has no sourceName or sourceNamespace", c.SourceNamespace, c.SourceName)
+		}
+		if c.MaxParallelInstalls < 0 {
+			return fmt.Errorf("the installThrottle maxParallelInstalls %d of catalog %s/%s is negative", c.MaxParallelInstalls, c.SourceNamespace, c.SourceName)
+		}
+		if catalogs[c.SourceNamespace+"/"+c.SourceName] {
+			return fmt.Errorf("the installThrottle catalog %s/%s is duplicated", c.SourceNamespace, c.SourceName)
+		}
+		catalogs[c.SourceNamespace+"/"+c.SourceName] = true
+	}
+	if settings.Verbosity < 0 {
+		return fmt.Errorf("the logging verbosity %d is negative", settings.Verbosity)
+	}
+	controllers := make(map[string]bool)
+	for _, c := range settings.ControllerVerbosities {
+		if c.Name == "" {
+			return fmt.Errorf("the logging controller has no name")
+		}
+		if c.Verbosity < 0 {
+			return fmt.Errorf("the logging verbosity %d of controller %s is negative", c.Verbosity, c.Name)
+		}
+		if controllers[c.Name] {
+			return fmt.Errorf("the logging controller %s is duplicated", c.Name)
+		}
+		controllers[c.Name] = true
+	}
+	switch settings.RecreatePolicy {
+	case operatorv1alpha1.RecreatePolicyImmediate, operatorv1alpha1.RecreatePolicyResync:
+	default:
+		return fmt.Errorf("the recreatePolicy %s is not one of %s and %s", settings.RecreatePolicy, operatorv1alpha1.RecreatePolicyImmediate, operatorv1alpha1.RecreatePolicyResync)
+	}
+	if err := validateCallbacks(settings.Callbacks); err != nil {
+		return err
+	}
+	if err := validateMirrors(settings.CatalogMirrors, settings.RegistryMirrors); err != nil {
+		return err
+	}
+	if err := validatePlacementProfiles(settings.PlacementProfiles); err != nil {
+		return err
+	}
+	return validateThroughput(settings.Throughput)
+}
+
+// validateMirrors checks the catalog mirrors replace each CatalogSource once, and the registry mirrors replace each prefix once
+func validateMirrors(catalogs []operatorv1alpha1.CatalogMirror, registries []operatorv1alpha1.RegistryMirror) error {
+	sources := make(map[string]bool)
+	for _, c := range catalogs {
+		if c.SourceNamespace == "" {
+			return fmt.Errorf("the mirrors catalog %s has no sourceNamespace", c.SourceName)
+		}
+		if c.MirrorName == "" && c.MirrorNamespace == "" {
+			return fmt.Errorf("the mirrors catalog %s/%s has no mirrorName or mirrorNamespace", c.SourceNamespace, c.SourceName)
+		}
+		if sources[c.SourceNamespace+"/"+c.SourceName] {
+			return fmt.Errorf("the mirrors catalog %s/%s is duplicated", c.SourceNamespace, c.SourceName)
+		}
+		sources[c.SourceNamespace+"/"+c.SourceName] = true
+	}
+	prefixes := make(map[string]bool)
+	for _, r := range registries {
+		source := strings.TrimSuffix(r.Source, "/")
+		if source == "" || strings.TrimSuffix(r.Mirror, "/") == "" {
+			return fmt.Errorf("the mirrors registry %s has no source or mirror", r.Source)
+		}
+		if strings.Contains(source, "://") || strings.Contains(r.Mirror, "://") {
+			return fmt.Errorf("the mirrors registry %s is not a registry host or repository", r.Source)
+		}
+		if prefixes[source] {
+			return fmt.Errorf("the mirrors registry %s is duplicated", r.Source)
+		}
+		prefixes[source] = true
+	}
+	return nil
+}
+
+// validatePlacementProfiles checks the placement profiles have a unique name
+func validatePlacementProfiles(profiles []operatorv1alpha1.PlacementProfile) error {
+	names := make(map[string]bool)
+	for _, p := range profiles {
+		if p.Name == "" {
+			return fmt.Errorf("the placement profile has no name")
+		}
+		if names[p.Name] {
+			return fmt.Errorf("the placement profile %s is duplicated", p.Name)
+		}
+		names[p.Name] = true
+	}
+	return nil
+}
+
+// validateCallbacks checks the URLs, the events and the policies of the callbacks
+func validateCallbacks(callbacks []operatorv1alpha1.Callback) error {
+	names := make(map[string]bool)
+	for _, c := range callbacks {
+		if c.Name == "" {
+			return fmt.Errorf("the callback has no name")
+		}
+		if names[c.Name] {
+			return fmt.Errorf("the callback %s is duplicated", c.Name)
+		}
+		names[c.Name] = true
+		u, err := url.Parse(c.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("the url %s of callback %s is not an http or https URL", c.URL, c.Name)
+		}
+		for _, e := range c.Events {
+			switch e {
+			case operatorv1alpha1.CallbackPreInstall, operatorv1alpha1.CallbackPostInstall, operatorv1alpha1.CallbackPreDelete:
+			default:
+				return fmt.Errorf("the event %s of callback %s is not one of %s, %s and %s", e, c.Name, operatorv1alpha1.CallbackPreInstall, operatorv1alpha1.CallbackPostInstall, operatorv1alpha1.CallbackPreDelete)
+			}
+		}
+		if c.SecretKeyRef != nil && (c.SecretKeyRef.Name == "" || c.SecretKeyRef.Key == "") {
+			return fmt.Errorf("the secretKeyRef of callback %s has no name or key", c.Name)
+		}
+		if c.Timeout != nil && c.Timeout.Duration < 0 {
+			return fmt.Errorf("the timeout %s of callback %s is negative", c.Timeout.Duration, c.Name)
+		}
+		switch c.FailurePolicy {
+		case "", operatorv1alpha1.CallbackFailurePolicyFail, operatorv1alpha1.CallbackFailurePolicyIgnore:
+		default:
+			return fmt.Errorf("the failurePolicy %s of callback %s is not one of %s and %s", c.FailurePolicy, c.Name, operatorv1alpha1.CallbackFailurePolicyFail, operatorv1alpha1.CallbackFailurePolicyIgnore)
+		}
+	}
+	return nil
+}
+
+// validateThroughput checks the throughput of the client and the controllers
+func validateThroughput(t operatorv1alpha1.Throughput) error {
+	if t.QPS < 0 || t.Burst < 0 {
+		return fmt.Errorf("the throughput qps %d or burst %d is negative", t.QPS, t.Burst)
+	}
+	if t.OperandWorkers < 0 {
+		return fmt.Errorf("the throughput operandWorkers %d is negative", t.OperandWorkers)
+	}
+	if err := validateControllerThroughput("all the controllers", t.ControllerThroughput); err != nil {
+		return err
+	}
+	controllers := make(map[string]bool)
+	for _, c := range t.Controllers {
+		if c.Name == "" {
+			return fmt.Errorf("the throughput controller has no name")
+		}
+		if controllers[c.Name] {
+			return fmt.Errorf("the throughput controller %s is duplicated", c.Name)
+		}
+		controllers[c.Name] = true
+		if err := validateControllerThroughput("controller "+c.Name, t.ForController(c.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateControllerThroughput(name string, c operatorv1alpha1.ControllerThroughput) error {
+	if c.MaxConcurrentReconciles < 0 {
+		return fmt.Errorf("the throughput maxConcurrentReconciles %d of %s is negative", c.MaxConcurrentReconciles, name)
+	}
+	r := c.RateLimiter
+	if r == nil {
+		return nil
+	}
+	if r.QPS < 0 || r.Burst < 0 {
+		return fmt.Errorf("the throughput rateLimiter qps %d or burst %d of %s is negative", r.QPS, r.Burst, name)
+	}
+	if (r.BaseDelay != nil && r.BaseDelay.Duration < 0) || (r.MaxDelay != nil && r.MaxDelay.Duration < 0) {
+		return fmt.Errorf("the throughput rateLimiter delays of %s are negative", name)
+	}
+	if r.BaseDelay != nil && r.MaxDelay != nil && r.BaseDelay.Duration > r.MaxDelay.Duration {
+		return fmt.Errorf("the throughput rateLimiter baseDelay %s of %s is longer than the maxDelay %s", r.BaseDelay.Duration, name, r.MaxDelay.Duration)
+	}
+	return nil
+}
+
+// restartRequired returns the settings which only take effect when ODLM restarts and differ from the running ones
+func restartRequired(settings, running operatorv1alpha1.OperatorSettings) []string {
+	var restart []string
+	if settings.IsolatedMode != running.IsolatedMode {
+		restart = append(restart, "isolatedMode")
+	}
+	if settings.WatchNamespace != running.WatchNamespace {
+		restart = append(restart, "watchNamespace")
+	}
+	if settings.WatchNamespaceSelector != running.WatchNamespaceSelector {
+		restart = append(restart, "watchNamespaceSelector")
+	}
+	if settings.Shard != running.Shard {
+		restart = append(restart, "shard")
+	}
+	if !reflect.DeepEqual(settings.Throughput, running.Throughput) {
+		restart = append(restart, "throughput")
+	}
+	return restart
+}
+
+// Apply applies the settings to the running ODLM.
+// The isolated mode, the watched namespaces, the shard and the throughput are only applied when ODLM starts.
+func Apply(settings operatorv1alpha1.OperatorSettings, starting bool) {
+	if starting {
+		util.SetIsolatedMode(settings.IsolatedMode)
+		util.SetWatchNamespace(settings.WatchNamespace)
+		util.SetWatchNamespaceSelector(settings.WatchNamespaceSelector)
+		shard.Set(settings.Shard)
+		throughput.Set(settings.Throughput)
+	}
+
+	util.SetInstallScope(settings.InstallScope)
+	util.SetoperatorCheckerMode(!settings.OperatorChecker)
+	operatorchecker.Set(operatorchecker.Settings{
+		Interval:   settings.OperatorCheckerInterval.Duration,
+		Jitter:     settings.OperatorCheckerJitter,
+		Namespaces: settings.OperatorCheckerNamespaces,
+		Packages:   settings.OperatorCheckerPackages,
+	})
+	util.SetForensicBundle(settings.ForensicBundle, settings.ForensicBundleDirectory)
+	catalogs := make(map[string]int)
+	for _, c := range settings.CatalogInstallLimits {
+		catalogs[c.SourceNamespace+"/"+c.SourceName] = c.MaxParallelInstalls
+	}
+	util.SetInstallThrottle(settings.MaxParallelInstalls, catalogs)
+	util.SetRecreatePolicy(settings.RecreatePolicy)
+	util.SetRepairOperatorGroups(settings.RepairOperatorGroups)
+	callback.Set(settings.Callbacks)
+	mirror.Set(settings.CatalogMirrors, settings.RegistryMirrors)
+	profile.Set(settings.PlacementProfiles)
+	metrics.SetMaxLabelValues(settings.MaxMetricLabelValues)
+	garbagecollector.Set(settings.GarbageCollectionMode, settings.GarbageCollectionInterval.Duration)
+	controllers := make(map[string]int)
+	for _, c := range settings.ControllerVerbosities {
+		controllers[c.Name] = c.Verbosity
+	}
+	logging.SetVerbosity(settings.Verbosity, controllers)
+}
+
+// controllerVerbosities returns the verbosities of the controllers sorted by name
+func controllerVerbosities(controllers map[string]int) []operatorv1alpha1.ControllerVerbosity {
+	var verbosities []operatorv1alpha1.ControllerVerbosity
+	for name, v := range controllers {
+		verbosities = append(verbosities, operatorv1alpha1.ControllerVerbosity{Name: name, Verbosity: v})
+	}
+	sort.Slice(verbosities, func(i, j int) bool {
+		return verbosities[i].Name < verbosities[j].Name
+	})
+	return verbosities
+}
+
+func splitCatalogKey(key string) (namespace, name string) {
+	parts := strings.SplitN(key, "/", 2)
+	if len(parts) != 2 {
+		return "", key
+	}
+	return parts[0], parts[1]
+}
+
+func isOperatorConfig(key types.NamespacedName) bool {
+	return key.Name == operatorv1alpha1.OperatorConfigName && key.Namespace == util.GetOperatorNamespace()
+}
+
+// SetupWithManager adds OperatorConfig controller to the manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	fair := throughput.NewFairQueue("operatorconfig")
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(throughput.ControllerOptions("operatorconfig")).
+		For(&operatorv1alpha1.OperatorConfig{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// The requests are dispatched in turn across the namespaces by the fair queue
+		Watches(fair.Source(), &handler.EnqueueRequestForObject{}).
+		Complete(fair.Reconciler(r))
+}
@@ -0,0 +1,233 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operatorconfig
+
+import (
+	"context"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/operatorchecker"
+	testutil "github.com/IBM/operand-deployment-lifecycle-manager/controllers/testutil"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/metrics"
+)
+
+// +kubebuilder:docs-gen:collapse=Imports
+
+var _ = Describe("OperatorConfig controller", func() {
+	const (
+		operatorNamespace = "ibm-operators"
+		otherNamespace    = "ibm-cloudpak"
+	)
+
+	var (
+		ctx context.Context
+
+		operatorNamespaceName string
+		otherNamespaceName    string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		operatorNamespaceName = testutil.CreateNSName(operatorNamespace)
+		otherNamespaceName = testutil.CreateNSName(otherNamespace)
+		Expect(os.Setenv("OPERATOR_NAMESPACE", operatorNamespaceName)).Should(Succeed())
+
+		By("Creating the Namespace")
+		Expect(k8sClient.Create(ctx, testutil.NamespaceObj(operatorNamespaceName))).Should(Succeed())
+		Expect(k8sClient.Create(ctx, testutil.NamespaceObj(otherNamespaceName))).Should(Succeed())
+	})
+
+	Context("Applying the OperatorConfig", func() {
+		It("Should apply the settings at runtime", func() {
+			key := types.NamespacedName{Name: operatorv1alpha1.OperatorConfigName, Namespace: operatorNamespaceName}
+
+			By("Loading the OperatorConfig from the environment variables")
+			Expect(os.Setenv("INSTALL_SCOPE", operatorv1alpha1.InstallScopeCluster)).Should(Succeed())
+			Expect(Load(ctx, k8sClient, operatorNamespaceName)).Should(Succeed())
+			instance := &operatorv1alpha1.OperatorConfig{}
+			Expect(k8sClient.Get(ctx, key, instance)).Should(Succeed())
+			Expect(instance.Spec.InstallScope).Should(Equal(operatorv1alpha1.InstallScopeCluster))
+
+			By("Changing the settings")
+			instance.Spec.InstallScope = operatorv1alpha1.InstallScopeNamespaced
+			checker := false
+			instance.Spec.OperatorChecker = &checker
+			Expect(k8sClient.Update(ctx, instance)).Should(Succeed())
+
+			Eventually(func() operatorv1alpha1.OperatorConfigPhase {
+				Expect(k8sClient.Get(ctx, key, instance)).Should(Succeed())
+				if instance.Status.ObservedGeneration != instance.Generation {
+					return ""
+				}
+				return instance.Status.Phase
+			}, timeout, interval).Should(Equal(operatorv1alpha1.OperatorConfigApplied))
+			Expect(util.GetInstallScope()).Should(Equal(operatorv1alpha1.InstallScopeNamespaced))
+			Expect(util.GetoperatorCheckerMode()).Should(BeTrue())
+			Expect(instance.Status.Applied.InstallScope).Should(Equal(operatorv1alpha1.InstallScopeNamespaced))
+
+			By("Changing the isolated mode")
+			isolated := !util.GetIsolatedMode()
+			instance.Spec.IsolatedMode = &isolated
+			Expect(k8sClient.Update(ctx, instance)).Should(Succeed())
+
+			Eventually(func() operatorv1alpha1.OperatorConfigPhase {
+				Expect(k8sClient.Get(ctx, key, instance)).Should(Succeed())
+				if instance.Status.ObservedGeneration != instance.Generation {
+					return ""
+				}
+				return instance.Status.Phase
+			}, timeout, interval).Should(Equal(operatorv1alpha1.OperatorConfigRestartRequired))
+			Expect(util.GetIsolatedMode()).Should(Equal(!isolated))
+
+			Expect(k8sClient.Delete(ctx, instance)).Should(Succeed())
+		})
+
+		It("Should reject the invalid callbacks", func() {
+			settings := EnvSettings()
+			settings.Callbacks = []operatorv1alpha1.Callback{{Name: "cmdb", URL: "https://cmdb.example.com/odlm", FailurePolicy: operatorv1alpha1.CallbackFailurePolicyFail}}
+			Expect(Validate(settings)).Should(Succeed())
+
+			settings.Callbacks = append(settings.Callbacks, operatorv1alpha1.Callback{Name: "cmdb", URL: "https://itsm.example.com"})
+			Expect(Validate(settings)).ShouldNot(Succeed())
+
+			settings.Callbacks = []operatorv1alpha1.Callback{{Name: "itsm", URL: "itsm.example.com"}}
+			Expect(Validate(settings)).ShouldNot(Succeed())
+
+			settings.Callbacks = []operatorv1alpha1.Callback{{Name: "itsm", URL: "https://itsm.example.com", Events: []operatorv1alpha1.CallbackEvent{"PostDelete"}}}
+			Expect(Validate(settings)).ShouldNot(Succeed())
+		})
+
+		It("Should reject the invalid mirrors", func() {
+			settings := EnvSettings()
+			settings.CatalogMirrors = []operatorv1alpha1.CatalogMirror{{SourceName: "opencloud-operators", SourceNamespace: "openshift-marketplace", MirrorName: "opencloud-mirror"}}
+			settings.RegistryMirrors = []operatorv1alpha1.RegistryMirror{{Source: "icr.io/cpopen", Mirror: "mirror.example.com:5000/cpopen"}}
+			Expect(Validate(settings)).Should(Succeed())
+
+			settings.CatalogMirrors = append(settings.CatalogMirrors, operatorv1alpha1.CatalogMirror{SourceName: "opencloud-operators", SourceNamespace: "openshift-marketplace", MirrorNamespace: "mirrors"})
+			Expect(Validate(settings)).ShouldNot(Succeed())
+
+			settings.CatalogMirrors = []operatorv1alpha1.CatalogMirror{{SourceNamespace: "openshift-marketplace"}}
+			Expect(Validate(settings)).ShouldNot(Succeed())
+
+			settings.CatalogMirrors = nil
+			settings.RegistryMirrors = []operatorv1alpha1.RegistryMirror{{Source: "https://icr.io", Mirror: "mirror.example.com:5000"}}
+			Expect(Validate(settings)).ShouldNot(Succeed())
+		})
+
+		It("Should reject the invalid placement profiles", func() {
+			settings := EnvSettings()
+			settings.PlacementProfiles = []operatorv1alpha1.PlacementProfile{{Name: "infra", NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""}}}
+			Expect(Validate(settings)).Should(Succeed())
+
+			settings.PlacementProfiles = append(settings.PlacementProfiles, operatorv1alpha1.PlacementProfile{Name: "infra"})
+			Expect(Validate(settings)).ShouldNot(Succeed())
+
+			settings.PlacementProfiles = []operatorv1alpha1.PlacementProfile{{}}
+			Expect(Validate(settings)).ShouldNot(Succeed())
+		})
+
+		It("Should reject a negative number of metric label values", func() {
+			settings := EnvSettings()
+			Expect(settings.MaxMetricLabelValues).Should(Equal(metrics.DefaultMaxLabelValues))
+			settings.MaxMetricLabelValues = 0
+			Expect(Validate(settings)).Should(Succeed())
+
+			settings.MaxMetricLabelValues = -1
+			Expect(Validate(settings)).ShouldNot(Succeed())
+		})
+
+		It("Should reject the invalid garbage collection", func() {
+			settings := EnvSettings()
+			Expect(settings.GarbageCollectionMode).Should(Equal(operatorv1alpha1.GarbageCollectionDisabled))
+			settings.GarbageCollectionMode = operatorv1alpha1.GarbageCollectionDryRun
+			Expect(Validate(settings)).Should(Succeed())
+
+			settings.GarbageCollectionMode = "Always"
+			Expect(Validate(settings)).ShouldNot(Succeed())
+
+			settings.GarbageCollectionMode = operatorv1alpha1.GarbageCollectionDelete
+			settings.GarbageCollectionInterval = metav1.Duration{Duration: 30 * time.Second}
+			Expect(Validate(settings)).ShouldNot(Succeed())
+		})
+
+		It("Should reject the invalid watched namespaces", func() {
+			settings := EnvSettings()
+			settings.WatchNamespace = ""
+			Expect(Validate(settings)).Should(Succeed())
+			settings.WatchNamespace = "ibm-common-services,cloudpak1"
+			Expect(Validate(settings)).Should(Succeed())
+
+			settings.WatchNamespace = "ibm-common-services,,cloudpak1"
+			Expect(Validate(settings)).ShouldNot(Succeed())
+			settings.WatchNamespace = "IBM_Common_Services"
+			Expect(Validate(settings)).ShouldNot(Succeed())
+
+			settings.WatchNamespace = "ibm-common-services"
+			settings.WatchNamespaceSelector = "tenant in (gold,silver),!excluded"
+			Expect(Validate(settings)).Should(Succeed())
+			settings.WatchNamespaceSelector = "tenant in gold"
+			Expect(Validate(settings)).ShouldNot(Succeed())
+		})
+
+		It("Should reject the invalid shard", func() {
+			settings := EnvSettings()
+			settings.Shard = "shard-0"
+			Expect(Validate(settings)).Should(Succeed())
+			settings.Shard = "Shard_0"
+			Expect(Validate(settings)).ShouldNot(Succeed())
+		})
+
+		It("Should reject the invalid operator checker options", func() {
+			settings := EnvSettings()
+			Expect(settings.OperatorCheckerInterval.Duration).Should(Equal(operatorchecker.DefaultInterval))
+			settings.OperatorCheckerJitter = 100
+			settings.OperatorCheckerNamespaces = []string{"ibm-common-services"}
+			Expect(Validate(settings)).Should(Succeed())
+
+			settings.OperatorCheckerJitter = 101
+			Expect(Validate(settings)).ShouldNot(Succeed())
+
+			settings.OperatorCheckerJitter = 0
+			settings.OperatorCheckerInterval = metav1.Duration{Duration: 5 * time.Second}
+			Expect(Validate(settings)).ShouldNot(Succeed())
+
+			settings.OperatorCheckerInterval = metav1.Duration{Duration: time.Minute}
+			settings.OperatorCheckerPackages = []string{""}
+			Expect(Validate(settings)).ShouldNot(Succeed())
+		})
+
+		It("Should ignore the OperatorConfig outside of the operator namespace", func() {
+			instance := operatorv1alpha1.NewOperatorConfig(otherNamespaceName, EnvSettings())
+			Expect(k8sClient.Create(ctx, instance)).Should(Succeed())
+
+			Eventually(func() operatorv1alpha1.OperatorConfigPhase {
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: instance.Name, Namespace: otherNamespaceName}, instance)).Should(Succeed())
+				return instance.Status.Phase
+			}, timeout, interval).Should(Equal(operatorv1alpha1.OperatorConfigIgnored))
+
+			Expect(k8sClient.Delete(ctx, instance)).Should(Succeed())
+		})
+	})
+})
@@ -0,0 +1,200 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package operatorgroup resolves the OperatorGroups ODLM needs in the namespaces of the operators.
+// OLM doesn't install an operator in a namespace with several OperatorGroups, or whose
+// OperatorGroup doesn't target the namespaces the operator is installed for.
+package operatorgroup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	olmv1 "github.com/operator-framework/api/pkg/operators/v1"
+
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+)
+
+// Conflict explains why OLM can't install an operator with the OperatorGroups of a namespace
+type Conflict struct {
+	// Namespace of the OperatorGroups
+	Namespace string
+	// Groups are the names of the conflicting OperatorGroups
+	Groups []string
+	// Message explains the conflict
+	Message string
+}
+
+func (c *Conflict) Error() string {
+	return c.Message
+}
+
+// Resolution is what ODLM does to the OperatorGroups of a namespace
+type Resolution struct {
+	// Create is the OperatorGroup to create
+	Create *olmv1.OperatorGroup
+	// Update is the OperatorGroup to update with the merged target namespaces
+	Update *olmv1.OperatorGroup
+	// Delete are the OperatorGroups merged into the updated one
+	Delete []olmv1.OperatorGroup
+	// Conflict is the conflict left to the administrator
+	Conflict *Conflict
+}
+
+// Resolve returns how the existing OperatorGroups of the namespace are changed to install an operator
+// for the desired OperatorGroup. The OperatorGroups created by ODLM are updated to target the namespaces
+// of the desired one. The other conflicts are only repaired with repair, by merging the target namespaces
+// of all the OperatorGroups into one and deleting the others.
+func Resolve(desired *olmv1.OperatorGroup, existing []olmv1.OperatorGroup, repair bool) Resolution {
+	if len(existing) == 0 {
+		return Resolution{Create: desired}
+	}
+
+	if len(existing) == 1 {
+		og := existing[0]
+		if Covers(&og, desired.Spec.TargetNamespaces) {
+			return Resolution{}
+		}
+		if og.Spec.Selector != nil {
+			return Resolution{Conflict: &Conflict{
+				Namespace: og.Namespace,
+				Groups:    []string{og.Name},
+				Message:   fmt.Sprintf("The OperatorGroup %s/%s selects the namespaces by labels, it may not target the namespaces %s", og.Namespace, og.Name, strings.Join(desired.Spec.TargetNamespaces, ",")),
+			}}
+		}
+		if !repair && og.Labels[constant.OpreqLabel] != "true" {
+			return Resolution{Conflict: &Conflict{
+				Namespace: og.Namespace,
+				Groups:    []string{og.Name},
+				Message:   fmt.Sprintf("The OperatorGroup %s/%s targets the namespaces %s, not %s", og.Namespace, og.Name, strings.Join(og.Spec.TargetNamespaces, ","), strings.Join(missing(&og, desired.Spec.TargetNamespaces), ",")),
+			}}
+		}
+		updated := og.DeepCopy()
+		updated.Spec.TargetNamespaces = merge(og.Spec.TargetNamespaces, desired.Spec.TargetNamespaces)
+		return Resolution{Update: updated}
+	}
+
+	groups := make([]olmv1.OperatorGroup, len(existing))
+	copy(groups, existing)
+	sortGroups(groups)
+	names := make([]string, 0, len(groups))
+	for _, og := range groups {
+		names = append(names, og.Name)
+	}
+	conflict := &Conflict{
+		Namespace: desired.Namespace,
+		Groups:    names,
+		Message:   fmt.Sprintf("The namespace %s has %d OperatorGroups %s, OLM only installs the operators in a namespace with one OperatorGroup", desired.Namespace, len(groups), strings.Join(names, ",")),
+	}
+	if !repair {
+		return Resolution{Conflict: conflict}
+	}
+	for _, og := range groups {
+		if og.Spec.Selector != nil {
+			conflict.Message += fmt.Sprintf(", the OperatorGroup %s selects the namespaces by labels and can't be merged", og.Name)
+			return Resolution{Conflict: conflict}
+		}
+	}
+
+	// Keep the OperatorGroup created by ODLM, or the oldest one
+	kept := groups[0].DeepCopy()
+	allNamespaces := false
+	targets := desired.Spec.TargetNamespaces
+	for i := range groups {
+		if len(groups[i].Spec.TargetNamespaces) == 0 {
+			allNamespaces = true
+		}
+		targets = merge(targets, groups[i].Spec.TargetNamespaces)
+	}
+	if allNamespaces {
+		targets = nil
+	}
+	resolution := Resolution{Delete: groups[1:]}
+	if !equal(kept.Spec.TargetNamespaces, targets) {
+		kept.Spec.TargetNamespaces = targets
+		resolution.Update = kept
+	}
+	return resolution
+}
+
+// Covers returns true if the OperatorGroup targets all the namespaces. An OperatorGroup without target
+// namespaces and selector targets all the namespaces, and the namespaces selected by labels are not checked.
+func Covers(og *olmv1.OperatorGroup, namespaces []string) bool {
+	if len(og.Spec.TargetNamespaces) == 0 {
+		return true
+	}
+	return len(missing(og, namespaces)) == 0
+}
+
+// missing returns the namespaces the OperatorGroup doesn't target
+func missing(og *olmv1.OperatorGroup, namespaces []string) []string {
+	targets := make(map[string]bool, len(og.Spec.TargetNamespaces))
+	for _, ns := range og.Spec.TargetNamespaces {
+		targets[ns] = true
+	}
+	var missing []string
+	for _, ns := range namespaces {
+		if !targets[ns] {
+			missing = append(missing, ns)
+		}
+	}
+	return missing
+}
+
+// merge returns the sorted union of the namespaces
+func merge(a, b []string) []string {
+	set := make(map[string]bool, len(a)+len(b))
+	for _, ns := range append(append([]string{}, a...), b...) {
+		set[ns] = true
+	}
+	merged := make([]string, 0, len(set))
+	for ns := range set {
+		merged = append(merged, ns)
+	}
+	sort.Strings(merged)
+	return merged
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = append([]string{}, a...), append([]string{}, b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sortGroups sorts the OperatorGroups created by ODLM first, then the oldest first
+func sortGroups(groups []olmv1.OperatorGroup) {
+	sort.SliceStable(groups, func(i, j int) bool {
+		oi, oj := groups[i].Labels[constant.OpreqLabel] == "true", groups[j].Labels[constant.OpreqLabel] == "true"
+		if oi != oj {
+			return oi
+		}
+		ti, tj := groups[i].CreationTimestamp, groups[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return groups[i].Name < groups[j].Name
+	})
+}
@@ -0,0 +1,115 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operatorgroup
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	olmv1 "github.com/operator-framework/api/pkg/operators/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+)
+
+func group(name string, odlm bool, created time.Time, targets ...string) olmv1.OperatorGroup {
+	og := olmv1.OperatorGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ibm-common-services", CreationTimestamp: metav1.NewTime(created)},
+		Spec:       olmv1.OperatorGroupSpec{TargetNamespaces: targets},
+	}
+	if odlm {
+		og.Labels = map[string]string{constant.OpreqLabel: "true"}
+	}
+	return og
+}
+
+var _ = Describe("OperatorGroup", func() {
+
+	now := time.Now()
+	desired := group("operand-deployment-lifecycle-manager-operatorgroup", true, time.Time{}, "ibm-common-services", "team-a")
+
+	It("Should create the OperatorGroup in a namespace without one", func() {
+		resolution := Resolve(&desired, nil, false)
+		Expect(resolution.Create).To(Equal(&desired))
+		Expect(resolution.Conflict).To(BeNil())
+	})
+
+	It("Should keep the OperatorGroup targeting the namespaces", func() {
+		Expect(Resolve(&desired, []olmv1.OperatorGroup{group("og", false, now, "ibm-common-services", "team-a", "team-b")}, false)).To(Equal(Resolution{}))
+		Expect(Resolve(&desired, []olmv1.OperatorGroup{group("global", false, now)}, false)).To(Equal(Resolution{}))
+	})
+
+	It("Should update the target namespaces of the OperatorGroup created by ODLM", func() {
+		resolution := Resolve(&desired, []olmv1.OperatorGroup{group("og", true, now, "ibm-common-services")}, false)
+		Expect(resolution.Conflict).To(BeNil())
+		Expect(resolution.Update).NotTo(BeNil())
+		Expect(resolution.Update.Spec.TargetNamespaces).To(Equal([]string{"ibm-common-services", "team-a"}))
+	})
+
+	It("Should report the OperatorGroup of someone else not targeting the namespaces", func() {
+		resolution := Resolve(&desired, []olmv1.OperatorGroup{group("og", false, now, "ibm-common-services")}, false)
+		Expect(resolution.Update).To(BeNil())
+		Expect(resolution.Conflict).NotTo(BeNil())
+		Expect(resolution.Conflict.Message).To(ContainSubstring("not team-a"))
+
+		resolution = Resolve(&desired, []olmv1.OperatorGroup{group("og", false, now, "ibm-common-services")}, true)
+		Expect(resolution.Conflict).To(BeNil())
+		Expect(resolution.Update.Spec.TargetNamespaces).To(Equal([]string{"ibm-common-services", "team-a"}))
+	})
+
+	Context("Several OperatorGroups in the namespace", func() {
+		existing := []olmv1.OperatorGroup{
+			group("newer", false, now, "team-b"),
+			group("older", false, now.Add(-time.Hour), "ibm-common-services"),
+			group("odlm", true, now, "ibm-common-services"),
+		}
+
+		It("Should only report the conflict without the repair", func() {
+			resolution := Resolve(&desired, existing, false)
+			Expect(resolution.Update).To(BeNil())
+			Expect(resolution.Delete).To(BeEmpty())
+			Expect(resolution.Conflict.Groups).To(Equal([]string{"odlm", "older", "newer"}))
+		})
+
+		It("Should merge the target namespaces into the OperatorGroup created by ODLM", func() {
+			resolution := Resolve(&desired, existing, true)
+			Expect(resolution.Conflict).To(BeNil())
+			Expect(resolution.Update.Name).To(Equal("odlm"))
+			Expect(resolution.Update.Spec.TargetNamespaces).To(Equal([]string{"ibm-common-services", "team-a", "team-b"}))
+			Expect(resolution.Delete).To(HaveLen(2))
+			Expect(resolution.Delete[0].Name).To(Equal("older"))
+			Expect(resolution.Delete[1].Name).To(Equal("newer"))
+		})
+
+		It("Should keep the oldest OperatorGroup targeting all the namespaces", func() {
+			resolution := Resolve(&desired, []olmv1.OperatorGroup{group("newer", false, now, "team-b"), group("older", false, now.Add(-time.Hour))}, true)
+			Expect(resolution.Conflict).To(BeNil())
+			Expect(resolution.Update).To(BeNil())
+			Expect(resolution.Delete).To(HaveLen(1))
+			Expect(resolution.Delete[0].Name).To(Equal("newer"))
+		})
+
+		It("Should not merge the OperatorGroups selecting the namespaces by labels", func() {
+			selecting := group("selecting", false, now)
+			selecting.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "platform"}}
+			resolution := Resolve(&desired, []olmv1.OperatorGroup{group("odlm", true, now, "ibm-common-services"), selecting}, true)
+			Expect(resolution.Delete).To(BeEmpty())
+			Expect(resolution.Conflict.Message).To(ContainSubstring("can't be merged"))
+		})
+	})
+})
@@ -0,0 +1,125 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package ownership tracks the objects ODLM creates for the OperandRequests. The ownerReferences can't
+// cross the namespaces, so an object records the OperandRequests it is created for in the
+// <namespace>.<name>/request label and annotation, one per OperandRequest sharing it. The label lets
+// the objects of an OperandRequest be selected in all the namespaces, and the annotation keeps the
+// OperandRequests whose key is too long for a label.
+package ownership
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// requestSuffix is the suffix of the keys recording the OperandRequests
+const requestSuffix = "/request"
+
+// Key returns the key of the label and the annotation recording the OperandRequest
+func Key(requestKey types.NamespacedName) string {
+	return requestKey.Namespace + "." + requestKey.Name + requestSuffix
+}
+
+// Labels returns the label tracking the objects of the OperandRequest,
+// it is empty when the key of the OperandRequest is too long for a label
+func Labels(requestKey types.NamespacedName) map[string]string {
+	key := Key(requestKey)
+	if len(validation.IsQualifiedName(key)) != 0 {
+		return map[string]string{}
+	}
+	return map[string]string{key: "true"}
+}
+
+// Annotations returns the annotation tracking the objects of the OperandRequest
+func Annotations(requestKey types.NamespacedName) map[string]string {
+	return map[string]string{Key(requestKey): "true"}
+}
+
+// IsKey returns true if the label or annotation key records an OperandRequest
+func IsKey(key string) bool {
+	_, ok := parseKey(key)
+	return ok
+}
+
+// parseKey returns the OperandRequest of the key. The namespaces have no dots, the names may have some.
+func parseKey(key string) (types.NamespacedName, bool) {
+	if !strings.HasSuffix(key, requestSuffix) {
+		return types.NamespacedName{}, false
+	}
+	parts := strings.SplitN(strings.TrimSuffix(key, requestSuffix), ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || strings.Contains(parts[1], "/") {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: parts[0], Name: parts[1]}, true
+}
+
+// Requests returns the OperandRequests the object is tracked for, sorted by namespace and name
+func Requests(obj metav1.Object) []types.NamespacedName {
+	seen := make(map[types.NamespacedName]bool)
+	for _, keys := range []map[string]string{obj.GetLabels(), obj.GetAnnotations()} {
+		for key := range keys {
+			if requestKey, ok := parseKey(key); ok {
+				seen[requestKey] = true
+			}
+		}
+	}
+	requests := make([]types.NamespacedName, 0, len(seen))
+	for requestKey := range seen {
+		requests = append(requests, requestKey)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].String() < requests[j].String()
+	})
+	return requests
+}
+
+// IsTracked returns true if the object is tracked for the OperandRequest
+func IsTracked(obj metav1.Object, requestKey types.NamespacedName) bool {
+	key := Key(requestKey)
+	_, labeled := obj.GetLabels()[key]
+	_, annotated := obj.GetAnnotations()[key]
+	return labeled || annotated
+}
+
+// Untrack returns the merge patch removing the label and the annotation of the OperandRequest from an object
+func Untrack(requestKey types.NamespacedName) client.Patch {
+	key := Key(requestKey)
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":      map[string]interface{}{key: nil},
+			"annotations": map[string]interface{}{key: nil},
+		},
+	})
+	return client.RawPatch(types.MergePatchType, patch)
+}
+
+// IndexValues returns the namespace/name of the OperandRequests the object is tracked for,
+// to index the objects by their OperandRequests
+func IndexValues(obj metav1.Object) []string {
+	requests := Requests(obj)
+	values := make([]string, 0, len(requests))
+	for _, requestKey := range requests {
+		values = append(values, requestKey.String())
+	}
+	return values
+}
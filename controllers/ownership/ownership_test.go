@@ -0,0 +1,71 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ownership
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Ownership", func() {
+
+	requestA := types.NamespacedName{Name: "a", Namespace: "team-a"}
+	requestB := types.NamespacedName{Name: "common-service.v2", Namespace: "team-b"}
+
+	It("Should track the objects of the OperandRequests", func() {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:        "etcd-credentials",
+			Namespace:   "ibm-common-services",
+			Labels:      Labels(requestB),
+			Annotations: Annotations(requestA),
+		}}
+		secret.Annotations["operator.ibm.com/opreq-control"] = "true"
+		secret.Annotations["ibm-common-services.common-service/registry"] = "true"
+
+		Expect(Requests(secret)).Should(Equal([]types.NamespacedName{requestA, requestB}))
+		Expect(IndexValues(secret)).Should(Equal([]string{"team-a/a", "team-b/common-service.v2"}))
+		Expect(IsTracked(secret, requestA)).Should(BeTrue())
+		Expect(IsTracked(secret, types.NamespacedName{Name: "c", Namespace: "team-c"})).Should(BeFalse())
+	})
+
+	It("Should only match the keys of the OperandRequests", func() {
+		Expect(IsKey("ibm-common-services.example/request")).Should(BeTrue())
+		Expect(IsKey("ibm-common-services.example/registry")).Should(BeFalse())
+		Expect(IsKey("ibm-common-services/request")).Should(BeFalse())
+		Expect(IsKey("odlm.ibm.com/adopt")).Should(BeFalse())
+	})
+
+	It("Should only label the OperandRequests with a valid label key", func() {
+		Expect(Labels(requestA)).Should(Equal(map[string]string{"team-a.a/request": "true"}))
+		long := types.NamespacedName{Name: strings.Repeat("a", 250), Namespace: "team-a"}
+		Expect(Labels(long)).Should(BeEmpty())
+		Expect(Annotations(long)).Should(HaveLen(1))
+	})
+
+	It("Should untrack the objects with a merge patch", func() {
+		patch := Untrack(requestA)
+		Expect(string(patch.Type())).Should(Equal(string(types.MergePatchType)))
+		data, err := patch.Data(nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(data)).Should(Equal(`{"metadata":{"annotations":{"team-a.a/request":null},"labels":{"team-a.a/request":null}}}`))
+	})
+})
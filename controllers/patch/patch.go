@@ -0,0 +1,106 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package patch applies the JSON6902 patches of the OperandRequests to the custom resources of their operands,
+// after their configuration is merged into them.
+package patch
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+// specPath is the prefix of the paths the patches may change, ODLM only manages the spec of the custom resources
+const specPath = "/spec"
+
+// Validate checks the operations of the patches: their paths, and the from and the value they require
+func Validate(ops []operatorv1alpha1.PatchOperation) error {
+	for i, op := range ops {
+		if !inSpec(op.Path) {
+			return errors.Errorf("the path %q of the patch %d doesn't start with %s", op.Path, i, specPath)
+		}
+		switch op.Op {
+		case "add", "replace", "test":
+			if op.Value == nil {
+				return errors.Errorf("the %s patch %d has no value", op.Op, i)
+			}
+		case "move", "copy":
+			if !inSpec(op.From) {
+				return errors.Errorf("the from %q of the %s patch %d doesn't start with %s", op.From, op.Op, i, specPath)
+			}
+		case "remove":
+		default:
+			return errors.Errorf("the patch %d has the unknown operation %q", i, op.Op)
+		}
+	}
+	return nil
+}
+
+// inSpec returns true if the JSON pointer is the spec or a field in it
+func inSpec(path string) bool {
+	return path == specPath || strings.HasPrefix(path, specPath+"/")
+}
+
+// Apply returns the spec of a custom resource with the patches applied in order.
+// The spec is returned unchanged when there is no patch.
+func Apply(spec map[string]interface{}, ops []operatorv1alpha1.PatchOperation) (map[string]interface{}, error) {
+	if len(ops) == 0 {
+		return spec, nil
+	}
+	if err := Validate(ops); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(ops)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the patches")
+	}
+	p, err := jsonpatch.DecodePatch(raw)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode the patches")
+	}
+	doc, err := json.Marshal(map[string]interface{}{"spec": spec})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the spec")
+	}
+	patched, err := p.Apply(doc)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to apply the patches")
+	}
+	var cr map[string]interface{}
+	if err := json.Unmarshal(patched, &cr); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal the patched spec")
+	}
+	patchedSpec, _ := cr["spec"].(map[string]interface{})
+	return patchedSpec, nil
+}
+
+// Hash returns a short hash of the patches recorded on the custom resource, it is empty when there is no patch.
+// The custom resource is applied again when it changes, so the fields removed by the patches are removed from it.
+func Hash(ops []operatorv1alpha1.PatchOperation) string {
+	if len(ops) == 0 {
+		return ""
+	}
+	raw, _ := json.Marshal(ops)
+	hashed := sha256.Sum256(raw)
+	return hex.EncodeToString(hashed[:7])
+}
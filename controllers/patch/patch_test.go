@@ -0,0 +1,82 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package patch
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("JSON6902 patches", func() {
+
+	value := func(raw string) *apiextensionsv1.JSON {
+		return &apiextensionsv1.JSON{Raw: []byte(raw)}
+	}
+	spec := func() map[string]interface{} {
+		return map[string]interface{}{
+			"size": float64(3),
+			"tls":  map[string]interface{}{"static": true},
+			"args": []interface{}{"--debug", "--trace"},
+		}
+	}
+
+	It("Should remove a field and insert a list item at an index", func() {
+		patched, err := Apply(spec(), []operatorv1alpha1.PatchOperation{
+			{Op: "remove", Path: "/spec/tls"},
+			{Op: "add", Path: "/spec/args/1", Value: value(`"--verbose"`)},
+			{Op: "replace", Path: "/spec/size", Value: value(`5`)},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(patched).Should(Equal(map[string]interface{}{
+			"size": float64(5),
+			"args": []interface{}{"--debug", "--verbose", "--trace"},
+		}))
+	})
+
+	It("Should return the spec unchanged without patch", func() {
+		patched, err := Apply(spec(), nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(patched).Should(Equal(spec()))
+		Expect(Hash(nil)).Should(BeEmpty())
+	})
+
+	It("Should fail the patches which can't be applied", func() {
+		_, err := Apply(spec(), []operatorv1alpha1.PatchOperation{{Op: "remove", Path: "/spec/storage"}})
+		Expect(err).Should(HaveOccurred())
+		_, err = Apply(spec(), []operatorv1alpha1.PatchOperation{{Op: "test", Path: "/spec/size", Value: value(`1`)}})
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("Should reject the invalid patches", func() {
+		Expect(Validate([]operatorv1alpha1.PatchOperation{{Op: "remove", Path: "/metadata/labels"}})).Should(HaveOccurred())
+		Expect(Validate([]operatorv1alpha1.PatchOperation{{Op: "add", Path: "/spec/size"}})).Should(HaveOccurred())
+		Expect(Validate([]operatorv1alpha1.PatchOperation{{Op: "move", Path: "/spec/size", From: "/status/size"}})).Should(HaveOccurred())
+		Expect(Validate([]operatorv1alpha1.PatchOperation{{Op: "merge", Path: "/spec/size"}})).Should(HaveOccurred())
+		Expect(Validate([]operatorv1alpha1.PatchOperation{{Op: "copy", Path: "/spec/replicas", From: "/spec/size"}})).ShouldNot(HaveOccurred())
+	})
+
+	It("Should change the hash with the patches", func() {
+		remove := []operatorv1alpha1.PatchOperation{{Op: "remove", Path: "/spec/tls"}}
+		replace := []operatorv1alpha1.PatchOperation{{Op: "replace", Path: "/spec/size", Value: value(`5`)}}
+		Expect(Hash(remove)).ShouldNot(BeEmpty())
+		Expect(Hash(remove)).Should(Equal(Hash(remove)))
+		Expect(Hash(remove)).ShouldNot(Equal(Hash(replace)))
+	})
+})
@@ -0,0 +1,168 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package placement distributes the operators and operands of an OperandRequest to the managed clusters
+// with the Open Cluster Management ManifestWork, and reads their status back.
+package placement
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+)
+
+const (
+	// maxNameLength is the maximum length of the name of a ManifestWork
+	maxNameLength = 253
+
+	conditionApplied   = "Applied"
+	conditionAvailable = "Available"
+	conditionDegraded  = "Degraded"
+)
+
+var (
+	// ManifestWorkGVK is the GroupVersionKind of the Open Cluster Management ManifestWork
+	ManifestWorkGVK = schema.GroupVersionKind{Group: "work.open-cluster-management.io", Version: "v1", Kind: "ManifestWork"}
+	// ManagedClusterGVK is the GroupVersionKind of the Open Cluster Management ManagedCluster
+	ManagedClusterGVK = schema.GroupVersionKind{Group: "cluster.open-cluster-management.io", Version: "v1", Kind: "ManagedCluster"}
+)
+
+// WorkName returns the name of the ManifestWork of the OperandRequest
+func WorkName(requestKey types.NamespacedName) string {
+	name := "odlm-" + requestKey.Namespace + "-" + requestKey.Name
+	if len(name) <= maxNameLength {
+		return name
+	}
+	hash := sha256.Sum256([]byte(requestKey.String()))
+	suffix := "-" + hex.EncodeToString(hash[:7])
+	return name[:maxNameLength-len(suffix)] + suffix
+}
+
+// SelectClusters returns the sorted names of the managed clusters the request is distributed to.
+// They are the clusters listed by name and the managed clusters matching the cluster selector.
+func SelectClusters(placement *operatorv1alpha1.OperandPlacement, managedClusters []unstructured.Unstructured) ([]string, error) {
+	found := make(map[string]bool)
+	for _, cluster := range placement.Clusters {
+		found[cluster] = true
+	}
+	if placement.ClusterSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(placement.ClusterSelector)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse the cluster selector")
+		}
+		for _, cluster := range managedClusters {
+			if selector.Matches(labels.Set(cluster.GetLabels())) {
+				found[cluster.GetName()] = true
+			}
+		}
+	}
+	clusters := make([]string, 0, len(found))
+	for cluster := range found {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+	return clusters, nil
+}
+
+// NewManifestWork returns the ManifestWork applying the manifests in the managed cluster.
+// The ManifestWork lives in the namespace of the managed cluster in the hub cluster.
+func NewManifestWork(cluster string, requestKey types.NamespacedName, manifests []map[string]interface{}) *unstructured.Unstructured {
+	work := &unstructured.Unstructured{}
+	work.SetGroupVersionKind(ManifestWorkGVK)
+	work.SetName(WorkName(requestKey))
+	work.SetNamespace(cluster)
+	work.SetLabels(map[string]string{constant.OpreqLabel: "true"})
+	work.SetAnnotations(map[string]string{constant.PlacementRequestAnnotation: requestKey.String()})
+
+	// The namespaces are kept in the managed cluster when the ManifestWork is deleted,
+	// because they may hold the resources not created by ODLM
+	var list, orphans []interface{}
+	for _, manifest := range manifests {
+		list = append(list, manifest)
+		if manifest["kind"] == "Namespace" && manifest["apiVersion"] == "v1" {
+			name, _, _ := unstructured.NestedString(manifest, "metadata", "name")
+			orphans = append(orphans, map[string]interface{}{
+				"group":    "",
+				"resource": "namespaces",
+				"name":     name,
+			})
+		}
+	}
+	spec := map[string]interface{}{
+		"workload": map[string]interface{}{
+			"manifests": list,
+		},
+	}
+	if len(orphans) != 0 {
+		spec["deleteOption"] = map[string]interface{}{
+			"propagationPolicy": "SelectivelyOrphan",
+			"selectivelyOrphans": map[string]interface{}{
+				"orphaningRules": orphans,
+			},
+		}
+	}
+	work.Object["spec"] = spec
+	return work
+}
+
+// IsOwnedBy returns true if the ManifestWork is created for the OperandRequest
+func IsOwnedBy(work *unstructured.Unstructured, requestKey types.NamespacedName) bool {
+	return work.GetLabels()[constant.OpreqLabel] == "true" && work.GetAnnotations()[constant.PlacementRequestAnnotation] == requestKey.String()
+}
+
+// Status returns the status of the request in the managed cluster of the ManifestWork
+func Status(work *unstructured.Unstructured) operatorv1alpha1.ClusterPlacementStatus {
+	status := operatorv1alpha1.ClusterPlacementStatus{
+		Cluster: work.GetNamespace(),
+		Phase:   operatorv1alpha1.ClusterPhaseCreating,
+		Message: "Waiting for the ManifestWork to be applied",
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(work.Object, "status", "conditions")
+	found := make(map[string]map[string]interface{})
+	for _, c := range conditions {
+		if condition, ok := c.(map[string]interface{}); ok {
+			if t, ok := condition["type"].(string); ok {
+				found[t] = condition
+			}
+		}
+	}
+
+	if c, ok := found[conditionDegraded]; ok && c["status"] == string(metav1.ConditionTrue) {
+		status.Phase = operatorv1alpha1.ClusterPhaseFailed
+		status.Message, _ = c["message"].(string)
+	} else if c, ok := found[conditionApplied]; ok && c["status"] == string(metav1.ConditionFalse) {
+		status.Phase = operatorv1alpha1.ClusterPhaseFailed
+		status.Message, _ = c["message"].(string)
+	} else if c, ok := found[conditionAvailable]; ok && c["status"] == string(metav1.ConditionTrue) {
+		status.Phase = operatorv1alpha1.ClusterPhaseRunning
+		status.Message, _ = c["message"].(string)
+	} else if _, ok := found[conditionApplied]; ok {
+		status.Phase = operatorv1alpha1.ClusterPhaseInstalling
+		status.Message = "Waiting for the operators and operands to be available"
+	}
+	return status
+}
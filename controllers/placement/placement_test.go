@@ -0,0 +1,117 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package placement
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+func managedCluster(name string, labels map[string]string) unstructured.Unstructured {
+	cluster := unstructured.Unstructured{}
+	cluster.SetGroupVersionKind(ManagedClusterGVK)
+	cluster.SetName(name)
+	cluster.SetLabels(labels)
+	return cluster
+}
+
+func workWithConditions(conditions ...map[string]interface{}) *unstructured.Unstructured {
+	work := NewManifestWork("spoke", types.NamespacedName{Namespace: "ns", Name: "request"}, nil)
+	var list []interface{}
+	for _, c := range conditions {
+		list = append(list, c)
+	}
+	work.Object["status"] = map[string]interface{}{"conditions": list}
+	return work
+}
+
+func condition(t, status, message string) map[string]interface{} {
+	return map[string]interface{}{"type": t, "status": status, "message": message}
+}
+
+var _ = Describe("Placement", func() {
+	requestKey := types.NamespacedName{Namespace: "ns", Name: "request"}
+
+	Context("Selecting the managed clusters", func() {
+		It("Should return the clusters by name and by selector", func() {
+			clusters, err := SelectClusters(&operatorv1alpha1.OperandPlacement{
+				Clusters:        []string{"spoke-b", "spoke-a"},
+				ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+			}, []unstructured.Unstructured{
+				managedCluster("spoke-c", map[string]string{"env": "prod"}),
+				managedCluster("spoke-a", map[string]string{"env": "prod"}),
+				managedCluster("spoke-d", map[string]string{"env": "dev"}),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clusters).Should(Equal([]string{"spoke-a", "spoke-b", "spoke-c"}))
+		})
+	})
+
+	Context("Generating the ManifestWork", func() {
+		It("Should apply the manifests and keep the namespaces", func() {
+			work := NewManifestWork("spoke", requestKey, []map[string]interface{}{
+				{"apiVersion": "v1", "kind": "Namespace", "metadata": map[string]interface{}{"name": "operators"}},
+				{"apiVersion": "operators.coreos.com/v1alpha1", "kind": "Subscription", "metadata": map[string]interface{}{"name": "etcd", "namespace": "operators"}},
+			})
+			Expect(work.GetNamespace()).Should(Equal("spoke"))
+			Expect(work.GetName()).Should(Equal("odlm-ns-request"))
+			Expect(IsOwnedBy(work, requestKey)).Should(BeTrue())
+			Expect(IsOwnedBy(work, types.NamespacedName{Namespace: "ns", Name: "other"})).Should(BeFalse())
+
+			manifests, _, _ := unstructured.NestedSlice(work.Object, "spec", "workload", "manifests")
+			Expect(manifests).Should(HaveLen(2))
+			rules, _, _ := unstructured.NestedSlice(work.Object, "spec", "deleteOption", "selectivelyOrphans", "orphaningRules")
+			Expect(rules).Should(Equal([]interface{}{map[string]interface{}{"group": "", "resource": "namespaces", "name": "operators"}}))
+		})
+
+		It("Should keep the name of the ManifestWork within the limit", func() {
+			name := WorkName(types.NamespacedName{Namespace: "ns", Name: strings.Repeat("a", 300)})
+			Expect(len(name)).Should(Equal(maxNameLength))
+		})
+	})
+
+	Context("Reading the status of the ManifestWork", func() {
+		It("Should be creating before the ManifestWork is applied", func() {
+			Expect(Status(workWithConditions()).Phase).Should(Equal(operatorv1alpha1.ClusterPhaseCreating))
+		})
+
+		It("Should be installing until the manifests are available", func() {
+			status := Status(workWithConditions(condition(conditionApplied, "True", "")))
+			Expect(status.Cluster).Should(Equal("spoke"))
+			Expect(status.Phase).Should(Equal(operatorv1alpha1.ClusterPhaseInstalling))
+		})
+
+		It("Should be running when the manifests are available", func() {
+			status := Status(workWithConditions(condition(conditionApplied, "True", ""), condition(conditionAvailable, "True", "All resources are available")))
+			Expect(status.Phase).Should(Equal(operatorv1alpha1.ClusterPhaseRunning))
+			Expect(status.Message).Should(Equal("All resources are available"))
+		})
+
+		It("Should be failed when the ManifestWork is degraded", func() {
+			status := Status(workWithConditions(condition(conditionAvailable, "True", ""), condition(conditionDegraded, "True", "failed to apply")))
+			Expect(status.Phase).Should(Equal(operatorv1alpha1.ClusterPhaseFailed))
+			Expect(status.Message).Should(Equal("failed to apply"))
+		})
+	})
+})
@@ -0,0 +1,242 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package policy evaluates the ClusterOperandPolicies restricting the OperandRequests.
+package policy
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/blang/semver/v4"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	odlmutil "github.com/IBM/operand-deployment-lifecycle-manager/pkg/util/v1"
+)
+
+// Violation is a ClusterOperandPolicy violated by an operand of an OperandRequest
+type Violation struct {
+	// Policy is the name of the ClusterOperandPolicy
+	Policy string
+	// Message explains the restriction of the policy
+	Message string
+}
+
+func (v *Violation) Error() string {
+	return v.Message
+}
+
+// List returns the ClusterOperandPolicies sorted by name, none when their CRD isn't installed.
+// The policies are cluster scoped, they don't apply in the isolated mode.
+func List(ctx context.Context, reader client.Reader) ([]operatorv1alpha1.ClusterOperandPolicy, error) {
+	if util.GetIsolatedMode() {
+		return nil, nil
+	}
+	policyList := &operatorv1alpha1.ClusterOperandPolicyList{}
+	if err := reader.List(ctx, policyList); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to list the ClusterOperandPolicies")
+	}
+	sort.Slice(policyList.Items, func(i, j int) bool {
+		return policyList.Items[i].Name < policyList.Items[j].Name
+	})
+	return policyList.Items, nil
+}
+
+// CheckOperator returns the first policy which doesn't allow the namespace to request the operator
+func CheckOperator(policies []operatorv1alpha1.ClusterOperandPolicy, operator, namespace string, namespaceLabels map[string]string) (*Violation, error) {
+	for i := range policies {
+		allowed, err := policies[i].AllowsOperator(operator, namespace, namespaceLabels)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to evaluate the ClusterOperandPolicy %s", policies[i].Name)
+		}
+		if !allowed {
+			return &Violation{
+				Policy:  policies[i].Name,
+				Message: "The ClusterOperandPolicy " + policies[i].Name + " doesn't allow the namespace " + namespace + " to request the operator " + operator,
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// CheckQuota returns the violations of the lowest MaxOperandsPerNamespace by the operands of the request,
+// keyed by operand name. The operands of the older requests in the namespace are counted first,
+// so a new request never blocks the operands already granted. requests may contain the request itself.
+func CheckQuota(policies []operatorv1alpha1.ClusterOperandPolicy, requestInstance *operatorv1alpha1.OperandRequest, requests []operatorv1alpha1.OperandRequest) map[string]*Violation {
+	var limiting *operatorv1alpha1.ClusterOperandPolicy
+	for i := range policies {
+		max := policies[i].Spec.MaxOperandsPerNamespace
+		if max != nil && (limiting == nil || *max < *limiting.Spec.MaxOperandsPerNamespace) {
+			limiting = &policies[i]
+		}
+	}
+	if limiting == nil {
+		return nil
+	}
+	limit := int(*limiting.Spec.MaxOperandsPerNamespace)
+
+	granted := make(map[string]bool)
+	for i := range requests {
+		older := &requests[i]
+		if older.Namespace != requestInstance.Namespace || older.Name == requestInstance.Name || older.DeletionTimestamp != nil {
+			continue
+		}
+		if !isOlder(older, requestInstance) {
+			continue
+		}
+		for _, name := range operandNames(older) {
+			if len(granted) < limit {
+				granted[name] = true
+			}
+		}
+	}
+
+	violations := make(map[string]*Violation)
+	for _, name := range operandNames(requestInstance) {
+		if granted[name] {
+			continue
+		}
+		if len(granted) < limit {
+			granted[name] = true
+			continue
+		}
+		violations[name] = &Violation{
+			Policy:  limiting.Name,
+			Message: "The ClusterOperandPolicy " + limiting.Name + " allows " + strconv.Itoa(limit) + " operands in the namespace " + requestInstance.Namespace + ", the operand " + name + " exceeds it",
+		}
+	}
+	return violations
+}
+
+// CheckDowngrade returns the first policy denying the switch of the operator from the channel to a lower one,
+// the channels are ordered by the scheme of the operator
+func CheckDowngrade(policies []operatorv1alpha1.ClusterOperandPolicy, operator string, scheme *odlmutil.ChannelScheme, fromChannel, toChannel string) (*Violation, error) {
+	if fromChannel == "" || toChannel == "" || fromChannel == toChannel {
+		return nil, nil
+	}
+	for i := range policies {
+		if !policies[i].Spec.DenyChannelDowngrade {
+			continue
+		}
+		c, err := scheme.Compare(fromChannel, toChannel)
+		if err != nil {
+			// The channels without a version can't be ordered
+			return nil, nil
+		}
+		if c <= 0 {
+			return nil, nil
+		}
+		return &Violation{
+			Policy:  policies[i].Name,
+			Message: "The ClusterOperandPolicy " + policies[i].Name + " denies the downgrade of the operator " + operator + " from the channel " + fromChannel + " to the channel " + toChannel,
+		}, nil
+	}
+	return nil, nil
+}
+
+// CheckChannelJump returns the first policy denying the switch of the operator from the channel to a channel more minor
+// versions higher than its MaxMinorVersionJump, the versions of the channels are read by the scheme of the operator
+func CheckChannelJump(policies []operatorv1alpha1.ClusterOperandPolicy, operator string, scheme *odlmutil.ChannelScheme, fromChannel, toChannel string) *Violation {
+	if fromChannel == "" || toChannel == "" || fromChannel == toChannel {
+		return nil
+	}
+	from, fromOK := scheme.Version(fromChannel)
+	to, toOK := scheme.Version(toChannel)
+	if !fromOK || !toOK {
+		// The jumps between the channels without a version can't be measured
+		return nil
+	}
+	for i := range policies {
+		max := policies[i].Spec.MaxMinorVersionJump
+		if max == nil || minorVersionJump(from, to) <= uint64(*max) {
+			continue
+		}
+		return &Violation{
+			Policy:  policies[i].Name,
+			Message: "The ClusterOperandPolicy " + policies[i].Name + " denies the jump of the operator " + operator + " from the channel " + fromChannel + " to the channel " + toChannel + ", it allows " + strconv.Itoa(int(*max)) + " minor versions at once",
+		}
+	}
+	return nil
+}
+
+// minorVersionJump returns the number of minor versions from the version to a higher one. The next major version is
+// a jump of its minor version plus one, and the later major versions can't be reached. The lower versions are no jump.
+func minorVersionJump(from, to semver.Version) uint64 {
+	switch {
+	case to.Major == from.Major && to.Minor > from.Minor:
+		return to.Minor - from.Minor
+	case to.Major == from.Major+1:
+		return to.Minor + 1
+	case to.Major > from.Major+1:
+		return math.MaxUint64
+	}
+	return 0
+}
+
+// CheckChannelSwitch returns the first policy denying the switch of the operator from the channel to another one,
+// as a downgrade or a jump across too many minor versions. The message tells how to force the switch.
+func CheckChannelSwitch(policies []operatorv1alpha1.ClusterOperandPolicy, operator string, scheme *odlmutil.ChannelScheme, fromChannel, toChannel string) (*Violation, error) {
+	violation, err := CheckDowngrade(policies, operator, scheme, fromChannel, toChannel)
+	if err != nil {
+		return nil, err
+	}
+	if violation == nil {
+		violation = CheckChannelJump(policies, operator, scheme, fromChannel, toChannel)
+	}
+	if violation != nil {
+		violation.Message += ". Add the operator to the " + operatorv1alpha1.ForceUpgradeAnnotation + " annotation of the OperandRegistry to force it"
+	}
+	return violation, nil
+}
+
+// isOlder returns true if the request a was created before the request b.
+// A request being created is the newest.
+func isOlder(a, b *operatorv1alpha1.OperandRequest) bool {
+	if a.CreationTimestamp.IsZero() {
+		return false
+	}
+	if b.CreationTimestamp.IsZero() {
+		return true
+	}
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
+// operandNames returns the distinct names of the operands of the request, in order
+func operandNames(requestInstance *operatorv1alpha1.OperandRequest) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, req := range requestInstance.Spec.Requests {
+		for _, operand := range req.Operands {
+			if !seen[operand.Name] {
+				seen[operand.Name] = true
+				names = append(names, operand.Name)
+			}
+		}
+	}
+	return names
+}
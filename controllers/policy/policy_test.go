@@ -0,0 +1,191 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package policy
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	odlmutil "github.com/IBM/operand-deployment-lifecycle-manager/pkg/util/v1"
+)
+
+func request(name string, created time.Time, operands ...string) operatorv1alpha1.OperandRequest {
+	req := operatorv1alpha1.Request{Registry: "common-service"}
+	for _, operand := range operands {
+		req.Operands = append(req.Operands, operatorv1alpha1.Operand{Name: operand})
+	}
+	r := operatorv1alpha1.OperandRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a"},
+		Spec:       operatorv1alpha1.OperandRequestSpec{Requests: []operatorv1alpha1.Request{req}},
+	}
+	if !created.IsZero() {
+		r.CreationTimestamp = metav1.NewTime(created)
+	}
+	return r
+}
+
+func maxOperands(name string, max int32) operatorv1alpha1.ClusterOperandPolicy {
+	return operatorv1alpha1.ClusterOperandPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       operatorv1alpha1.ClusterOperandPolicySpec{MaxOperandsPerNamespace: &max},
+	}
+}
+
+var _ = Describe("Policy", func() {
+
+	Context("Restricting the namespaces of the operators", func() {
+		policies := []operatorv1alpha1.ClusterOperandPolicy{{
+			ObjectMeta: metav1.ObjectMeta{Name: "restricted"},
+			Spec: operatorv1alpha1.ClusterOperandPolicySpec{
+				OperatorRules: []operatorv1alpha1.OperatorRule{
+					{Operators: []string{"ibm-iam-operator"}, Namespaces: []string{"team-*"}},
+					{Operators: []string{"ibm-iam-operator"}, NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "platform"}}},
+				},
+			},
+		}}
+
+		It("Should allow the namespaces matching a rule of the operator", func() {
+			violation, err := CheckOperator(policies, "ibm-iam-operator", "team-a", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(violation).Should(BeNil())
+
+			violation, err = CheckOperator(policies, "ibm-iam-operator", "platform", map[string]string{"tier": "platform"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(violation).Should(BeNil())
+		})
+
+		It("Should deny the other namespaces", func() {
+			violation, err := CheckOperator(policies, "ibm-iam-operator", "default", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(violation).ShouldNot(BeNil())
+			Expect(violation.Policy).Should(Equal("restricted"))
+			Expect(violation.Message).Should(Equal("The ClusterOperandPolicy restricted doesn't allow the namespace default to request the operator ibm-iam-operator"))
+		})
+
+		It("Should allow the operators without a rule", func() {
+			violation, err := CheckOperator(policies, "ibm-licensing-operator", "default", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(violation).Should(BeNil())
+		})
+	})
+
+	Context("Capping the operands of a namespace", func() {
+		now := time.Now()
+
+		It("Should block the operands of the newer request beyond the lowest limit", func() {
+			policies := []operatorv1alpha1.ClusterOperandPolicy{maxOperands("loose", 5), maxOperands("strict", 2)}
+			older := request("older", now.Add(-time.Hour), "ibm-iam-operator", "ibm-im-mongodb-operator")
+			newer := request("newer", now, "ibm-iam-operator", "ibm-licensing-operator")
+
+			Expect(CheckQuota(policies, &older, []operatorv1alpha1.OperandRequest{older, newer})).Should(BeEmpty())
+
+			violations := CheckQuota(policies, &newer, []operatorv1alpha1.OperandRequest{older, newer})
+			Expect(violations).Should(HaveLen(1))
+			Expect(violations).Should(HaveKey("ibm-licensing-operator"))
+			Expect(violations["ibm-licensing-operator"].Policy).Should(Equal("strict"))
+		})
+
+		It("Should count the request being created after the existing ones", func() {
+			policies := []operatorv1alpha1.ClusterOperandPolicy{maxOperands("strict", 1)}
+			existing := request("existing", now, "ibm-iam-operator")
+			created := request("created", time.Time{}, "ibm-licensing-operator")
+
+			Expect(CheckQuota(policies, &created, []operatorv1alpha1.OperandRequest{existing})).Should(HaveKey("ibm-licensing-operator"))
+		})
+
+		It("Should not cap the namespaces without a limit", func() {
+			req := request("req", now, "ibm-iam-operator")
+			Expect(CheckQuota(nil, &req, nil)).Should(BeEmpty())
+		})
+	})
+
+	Context("Denying the channel downgrades", func() {
+		policies := []operatorv1alpha1.ClusterOperandPolicy{{
+			ObjectMeta: metav1.ObjectMeta{Name: "no-downgrade"},
+			Spec:       operatorv1alpha1.ClusterOperandPolicySpec{DenyChannelDowngrade: true},
+		}}
+
+		It("Should deny a lower channel", func() {
+			violation, err := CheckDowngrade(policies, "ibm-iam-operator", nil, "v3.20", "v3.19")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(violation).ShouldNot(BeNil())
+			Expect(violation.Policy).Should(Equal("no-downgrade"))
+		})
+
+		It("Should allow a higher channel", func() {
+			violation, err := CheckDowngrade(policies, "ibm-iam-operator", nil, "v3.19", "v3.20")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(violation).Should(BeNil())
+		})
+
+		It("Should allow a downgrade without a denying policy", func() {
+			violation, err := CheckDowngrade(nil, "ibm-iam-operator", nil, "v3.20", "v3.19")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(violation).Should(BeNil())
+		})
+
+		It("Should order the channels by the scheme of the operator", func() {
+			scheme, err := odlmutil.NewChannelScheme(odlmutil.ChannelSchemeOpenShift, "", nil)
+			Expect(err).NotTo(HaveOccurred())
+			violation, err := CheckDowngrade(policies, "ibm-iam-operator", scheme, "stable-4.14", "fast-4.14")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(violation).ShouldNot(BeNil())
+
+			scheme, err = odlmutil.NewChannelScheme("", "", []string{"alpha", "beta", "stable"})
+			Expect(err).NotTo(HaveOccurred())
+			violation, err = CheckDowngrade(policies, "ibm-iam-operator", scheme, "stable", "beta")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(violation).ShouldNot(BeNil())
+		})
+	})
+
+	Context("Denying the channel jumps", func() {
+		maxJump := int32(2)
+		policies := []operatorv1alpha1.ClusterOperandPolicy{{
+			ObjectMeta: metav1.ObjectMeta{Name: "small-steps"},
+			Spec:       operatorv1alpha1.ClusterOperandPolicySpec{MaxMinorVersionJump: &maxJump},
+		}}
+
+		It("Should allow the jumps up to the maximum minor versions", func() {
+			Expect(CheckChannelJump(policies, "ibm-iam-operator", nil, "v3.20", "v3.22")).Should(BeNil())
+			Expect(CheckChannelJump(policies, "ibm-iam-operator", nil, "v3.20", "v3.19")).Should(BeNil())
+			Expect(CheckChannelJump(policies, "ibm-iam-operator", nil, "v3.20", "stable-v4.1")).Should(BeNil())
+		})
+
+		It("Should deny the jumps across more minor versions or major versions", func() {
+			violation := CheckChannelJump(policies, "ibm-iam-operator", nil, "v3.20", "v3.23")
+			Expect(violation).ShouldNot(BeNil())
+			Expect(violation.Policy).Should(Equal("small-steps"))
+			Expect(CheckChannelJump(policies, "ibm-iam-operator", nil, "v3.20", "v4.2")).ShouldNot(BeNil())
+			Expect(CheckChannelJump(policies, "ibm-iam-operator", nil, "v3.20", "v5.0")).ShouldNot(BeNil())
+		})
+
+		It("Should skip the channels without a version", func() {
+			Expect(CheckChannelJump(policies, "ibm-iam-operator", nil, "alpha", "stable")).Should(BeNil())
+		})
+
+		It("Should tell how to force the denied switches", func() {
+			violation, err := CheckChannelSwitch(policies, "ibm-iam-operator", nil, "v3.20", "v3.23")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(violation.Message).Should(HaveSuffix("Add the operator to the operator.ibm.com/force-upgrade annotation of the OperandRegistry to force it"))
+		})
+	})
+})
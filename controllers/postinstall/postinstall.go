@@ -0,0 +1,130 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package postinstall builds the post-install hooks of the services, which verify the services
+// after their custom resources are ready, like the smoke tests.
+package postinstall
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+)
+
+// DefaultHTTPTimeout is the timeout of the HTTP check when it has none
+const DefaultHTTPTimeout = 10 * time.Second
+
+// Hash returns a short hash of the Job spec of the hooks, the Job is created again when it changes
+func Hash(hooks *operatorv1alpha1.PostInstallHooks) string {
+	h := sha256.Sum256(hooks.Job.Raw)
+	return hex.EncodeToString(h[:7])
+}
+
+// JobName returns the name of the post-install Job of the service for the hash of its spec
+func JobName(service, hash string) string {
+	return service + constant.PostInstallJobSuffix + hash
+}
+
+// NewJob builds the post-install Job of the service from the Job spec of its hooks
+func NewJob(hooks *operatorv1alpha1.PostInstallHooks, service, namespace string) (*batchv1.Job, error) {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      JobName(service, Hash(hooks)),
+			Namespace: namespace,
+			Labels: map[string]string{
+				constant.OpreqLabel:           "true",
+				constant.PostInstallHookLabel: service,
+			},
+		},
+	}
+	if err := json.Unmarshal(hooks.Job.Raw, &job.Spec); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the post-install Job of the service %s", service)
+	}
+	if len(job.Spec.Template.Spec.Containers) == 0 {
+		return nil, fmt.Errorf("the post-install Job of the service %s has no container", service)
+	}
+	// The pods of a Job can't restart always
+	if job.Spec.Template.Spec.RestartPolicy == "" {
+		job.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	}
+	return job, nil
+}
+
+// JobResult returns true if the Job succeeded, and the reason of its failure if it failed
+func JobResult(job *batchv1.Job) (bool, string) {
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			return false, fmt.Sprintf("the post-install Job %s/%s failed: %s", job.Namespace, job.Name, c.Message)
+		}
+	}
+	return job.Status.Succeeded > 0, ""
+}
+
+// CheckHTTP calls the HTTP endpoint of the check, it fails if the endpoint doesn't answer with the expected status in time.
+// The default client is used when the httpClient is nil and the check verifies the certificate of the endpoint.
+func CheckHTTP(ctx context.Context, httpClient *http.Client, check *operatorv1alpha1.HTTPCheck) error {
+	timeout := DefaultHTTPTimeout
+	if check.Timeout != nil && check.Timeout.Duration > 0 {
+		timeout = check.Timeout.Duration
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, check.URL, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the request of the HTTP check %s", check.URL)
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+		if check.InsecureSkipTLSVerify {
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+			httpClient = &http.Client{Transport: transport}
+		}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to call the HTTP check %s", check.URL)
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection is reused
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if check.ExpectedStatus != 0 {
+		if int32(resp.StatusCode) != check.ExpectedStatus {
+			return fmt.Errorf("the HTTP check %s answered with status %s, expected %d", check.URL, resp.Status, check.ExpectedStatus)
+		}
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("the HTTP check %s answered with status %s", check.URL, resp.Status)
+	}
+	return nil
+}
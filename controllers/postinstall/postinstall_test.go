@@ -0,0 +1,100 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package postinstall
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+)
+
+var _ = Describe("PostInstall", func() {
+
+	hooks := &operatorv1alpha1.PostInstallHooks{
+		Job: &runtime.RawExtension{Raw: []byte(`{"template":{"spec":{"containers":[{"name":"smoke-test","image":"etcd-smoke-test"}]}}}`)},
+	}
+
+	It("Should build the Job of the hooks named after the hash of its spec", func() {
+		job, err := NewJob(hooks, "ibm-etcd-operator", "ibm-common-services")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(job.Name).To(Equal("ibm-etcd-operator-post-install-" + Hash(hooks)))
+		Expect(job.Namespace).To(Equal("ibm-common-services"))
+		Expect(job.Labels).To(HaveKeyWithValue(constant.PostInstallHookLabel, "ibm-etcd-operator"))
+		Expect(job.Spec.Template.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+
+		changed := hooks.DeepCopy()
+		changed.Job.Raw = []byte(`{"template":{"spec":{"containers":[{"name":"smoke-test","image":"etcd-smoke-test:2"}]}}}`)
+		Expect(Hash(changed)).NotTo(Equal(Hash(hooks)))
+
+		_, err = NewJob(&operatorv1alpha1.PostInstallHooks{Job: &runtime.RawExtension{Raw: []byte(`{"template":{"spec":{}}}`)}}, "ibm-etcd-operator", "")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should report the result of the Job", func() {
+		job := &batchv1.Job{}
+		succeeded, failure := JobResult(job)
+		Expect(succeeded).To(BeFalse())
+		Expect(failure).To(BeEmpty())
+
+		job.Status.Succeeded = 1
+		succeeded, _ = JobResult(job)
+		Expect(succeeded).To(BeTrue())
+
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}}
+		_, failure = JobResult(job)
+		Expect(failure).To(ContainSubstring("BackoffLimitExceeded"))
+	})
+
+	It("Should check the status of the HTTP endpoint", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			switch req.URL.Path {
+			case "/healthz":
+				w.WriteHeader(http.StatusNoContent)
+			case "/slow":
+				time.Sleep(200 * time.Millisecond)
+			default:
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer server.Close()
+
+		Expect(CheckHTTP(context.TODO(), server.Client(), &operatorv1alpha1.HTTPCheck{URL: server.URL + "/healthz"})).To(Succeed())
+		Expect(CheckHTTP(context.TODO(), server.Client(), &operatorv1alpha1.HTTPCheck{URL: server.URL + "/healthz", ExpectedStatus: http.StatusOK})).NotTo(Succeed())
+		Expect(CheckHTTP(context.TODO(), server.Client(), &operatorv1alpha1.HTTPCheck{URL: server.URL + "/unavailable", ExpectedStatus: http.StatusServiceUnavailable})).To(Succeed())
+		Expect(CheckHTTP(context.TODO(), server.Client(), &operatorv1alpha1.HTTPCheck{URL: server.URL + "/unavailable"})).NotTo(Succeed())
+		Expect(CheckHTTP(context.TODO(), server.Client(), &operatorv1alpha1.HTTPCheck{URL: server.URL + "/slow", Timeout: &metav1.Duration{Duration: 50 * time.Millisecond}})).NotTo(Succeed())
+	})
+
+	It("Should skip the verification of the certificate when it is insecure", func() {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+		defer server.Close()
+
+		Expect(CheckHTTP(context.TODO(), nil, &operatorv1alpha1.HTTPCheck{URL: server.URL})).NotTo(Succeed())
+		Expect(CheckHTTP(context.TODO(), nil, &operatorv1alpha1.HTTPCheck{URL: server.URL, InsecureSkipTLSVerify: true})).To(Succeed())
+	})
+})
@@ -0,0 +1,131 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package predelete builds the pre-delete hooks of the services, which drain the services
+// before their custom resources are deleted.
+package predelete
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/health"
+)
+
+// JobName returns the name of the pre-delete Job of the service
+func JobName(service string) string {
+	return service + constant.PreDeleteJobSuffix
+}
+
+// NewJob builds the pre-delete Job of the service from the Job spec of its hooks
+func NewJob(hooks *operatorv1alpha1.PreDeleteHooks, service, namespace string) (*batchv1.Job, error) {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      JobName(service),
+			Namespace: namespace,
+			Labels: map[string]string{
+				constant.OpreqLabel:         "true",
+				constant.PreDeleteHookLabel: service,
+			},
+		},
+	}
+	if err := json.Unmarshal(hooks.Job.Raw, &job.Spec); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the pre-delete Job of the service %s", service)
+	}
+	if len(job.Spec.Template.Spec.Containers) == 0 {
+		return nil, fmt.Errorf("the pre-delete Job of the service %s has no container", service)
+	}
+	// The pods of a Job can't restart always
+	if job.Spec.Template.Spec.RestartPolicy == "" {
+		job.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	}
+	return job, nil
+}
+
+// JobResult returns true if the Job succeeded, and the reason of its failure if it failed
+func JobResult(job *batchv1.Job) (bool, string) {
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			return false, fmt.Sprintf("the pre-delete Job %s/%s failed: %s", job.Namespace, job.Name, c.Message)
+		}
+	}
+	return job.Status.Succeeded > 0, ""
+}
+
+// Started returns when the hooks started on the custom resources, it is zero if they haven't started yet
+func Started(crs []unstructured.Unstructured) time.Time {
+	var started time.Time
+	for _, cr := range crs {
+		t, err := time.Parse(time.RFC3339, cr.GetAnnotations()[constant.PreDeleteStartedAnnotation])
+		if err != nil {
+			continue
+		}
+		if started.IsZero() || t.Before(started) {
+			started = t
+		}
+	}
+	return started
+}
+
+// Patch returns the merge patch starting the hooks on the custom resource. It records the start time,
+// and sets the fields of the hooks for the kind of the custom resource.
+func Patch(hooks *operatorv1alpha1.PreDeleteHooks, cr unstructured.Unstructured, started time.Time) ([]byte, error) {
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				constant.PreDeleteStartedAnnotation: started.UTC().Format(time.RFC3339),
+			},
+		},
+	}
+	for _, res := range hooks.CustomResources {
+		if !strings.EqualFold(res.Kind, cr.GetKind()) || res.Spec == nil {
+			continue
+		}
+		spec := make(map[string]interface{})
+		if err := json.Unmarshal(res.Spec.Raw, &spec); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the pre-delete spec of the kind %s", res.Kind)
+		}
+		patch["spec"] = spec
+	}
+	return json.Marshal(patch)
+}
+
+// Drained evaluates the Done expression of the hooks with the custom resources. The hooks without
+// an expression are done. A field missing from the custom resources means they are not drained yet.
+func Drained(hooks *operatorv1alpha1.PreDeleteHooks, crs []unstructured.Unstructured) (bool, error) {
+	if hooks.Done == "" {
+		return true, nil
+	}
+	check, err := health.Compile(hooks.Done)
+	if err != nil {
+		return false, err
+	}
+	done, err := check.Healthy(crs, nil)
+	if err != nil {
+		return false, nil
+	}
+	return done, nil
+}
@@ -0,0 +1,109 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package predelete
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+)
+
+var _ = Describe("PreDelete", func() {
+
+	hooks := &operatorv1alpha1.PreDeleteHooks{
+		Job:             &runtime.RawExtension{Raw: []byte(`{"backoffLimit":2,"template":{"spec":{"containers":[{"name":"drain","image":"etcd-drain"}]}}}`)},
+		CustomResources: []operatorv1alpha1.PreDeleteResource{{Kind: "etcdCluster", Spec: &runtime.RawExtension{Raw: []byte(`{"drain":true}`)}}},
+		Done:            "crs.all(cr, cr.status.drained)",
+	}
+
+	newCR := func(kind string, annotations map[string]string, status map[string]interface{}) unstructured.Unstructured {
+		cr := unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "etcd.database.coreos.com/v1beta2", "kind": kind}}
+		cr.SetName("example")
+		cr.SetAnnotations(annotations)
+		if status != nil {
+			cr.Object["status"] = status
+		}
+		return cr
+	}
+
+	It("Should build the Job of the hooks", func() {
+		job, err := NewJob(hooks, "ibm-etcd-operator", "ibm-common-services")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(job.Name).To(Equal("ibm-etcd-operator-pre-delete"))
+		Expect(job.Namespace).To(Equal("ibm-common-services"))
+		Expect(job.Labels).To(HaveKeyWithValue(constant.PreDeleteHookLabel, "ibm-etcd-operator"))
+		Expect(*job.Spec.BackoffLimit).To(Equal(int32(2)))
+		Expect(job.Spec.Template.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+
+		_, err = NewJob(&operatorv1alpha1.PreDeleteHooks{Job: &runtime.RawExtension{Raw: []byte(`{"template":{"spec":{}}}`)}}, "ibm-etcd-operator", "")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should report the result of the Job", func() {
+		job := &batchv1.Job{}
+		succeeded, failure := JobResult(job)
+		Expect(succeeded).To(BeFalse())
+		Expect(failure).To(BeEmpty())
+
+		job.Status.Succeeded = 1
+		succeeded, _ = JobResult(job)
+		Expect(succeeded).To(BeTrue())
+
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}}
+		_, failure = JobResult(job)
+		Expect(failure).To(ContainSubstring("BackoffLimitExceeded"))
+	})
+
+	It("Should start the hooks on the custom resources", func() {
+		started := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+		patch, err := Patch(hooks, newCR("EtcdCluster", nil, nil), started)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patch).To(MatchJSON(`{"metadata":{"annotations":{"operator.ibm.com/opreq-pre-delete-started":"2022-03-01T10:00:00Z"}},"spec":{"drain":true}}`))
+
+		patch, err = Patch(hooks, newCR("EtcdBackup", nil, nil), started)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patch).To(MatchJSON(`{"metadata":{"annotations":{"operator.ibm.com/opreq-pre-delete-started":"2022-03-01T10:00:00Z"}}}`))
+
+		Expect(Started([]unstructured.Unstructured{newCR("EtcdCluster", nil, nil)}).IsZero()).To(BeTrue())
+		Expect(Started([]unstructured.Unstructured{
+			newCR("EtcdCluster", map[string]string{constant.PreDeleteStartedAnnotation: "2022-03-01T10:05:00Z"}, nil),
+			newCR("EtcdBackup", map[string]string{constant.PreDeleteStartedAnnotation: "2022-03-01T10:00:00Z"}, nil),
+		})).To(Equal(started))
+	})
+
+	It("Should check the custom resources are drained", func() {
+		done, err := Drained(hooks, []unstructured.Unstructured{newCR("EtcdCluster", nil, nil)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(done).To(BeFalse())
+
+		done, err = Drained(hooks, []unstructured.Unstructured{newCR("EtcdCluster", nil, map[string]interface{}{"drained": true})})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(done).To(BeTrue())
+
+		done, err = Drained(&operatorv1alpha1.PreDeleteHooks{}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(done).To(BeTrue())
+	})
+})
@@ -0,0 +1,270 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package profile expands the placement profiles of the OperatorConfig into the node selectors, tolerations
+// and affinities of the operators and operands, the same way for the Subscriptions, the k8s resources and the custom resources.
+package profile
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+// workloadKinds are the kinds of k8s resources whose pod template is rendered with the placement profile
+var workloadKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"ReplicaSet":  true,
+	"DaemonSet":   true,
+	"Job":         true,
+}
+
+// configured are the placement profiles of the running OperatorConfig
+var configured struct {
+	sync.RWMutex
+	profiles []operatorv1alpha1.PlacementProfile
+}
+
+// Set sets the placement profiles at runtime
+func Set(profiles []operatorv1alpha1.PlacementProfile) {
+	configured.Lock()
+	defer configured.Unlock()
+	configured.profiles = profiles
+}
+
+// Get returns the placement profiles ODLM is running with
+func Get() []operatorv1alpha1.PlacementProfile {
+	configured.RLock()
+	defer configured.RUnlock()
+	return configured.profiles
+}
+
+// Lookup returns the placement profile with the name, or nil when the name is empty
+func Lookup(name string) (*operatorv1alpha1.PlacementProfile, error) {
+	if name == "" {
+		return nil, nil
+	}
+	for _, p := range Get() {
+		if p.Name == name {
+			return p.DeepCopy(), nil
+		}
+	}
+	return nil, errors.Errorf("the placement profile %s is not found in the OperatorConfig", name)
+}
+
+// Affinity returns the affinity of the placement profile with the name, or nil when it isn't found
+func Affinity(name string) *corev1.Affinity {
+	p, err := Lookup(name)
+	if err != nil || p == nil {
+		return nil
+	}
+	return p.Affinity
+}
+
+// ExpandOperator merges the node selector and the tolerations of the placement profile of the operator
+// into its SubscriptionConfig, the node selector keys and the tolerations set in the SubscriptionConfig are kept
+func ExpandOperator(o *operatorv1alpha1.Operator) error {
+	p, err := Lookup(o.PlacementProfile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to expand the placement profile of the operator %s", o.Name)
+	}
+	if p == nil || (len(p.NodeSelector) == 0 && len(p.Tolerations) == 0) {
+		return nil
+	}
+	config := o.SubscriptionConfig.DeepCopy()
+	if config == nil {
+		config = &olmv1alpha1.SubscriptionConfig{}
+	}
+	config.NodeSelector = mergeNodeSelector(config.NodeSelector, p.NodeSelector)
+	config.Tolerations = mergeTolerations(config.Tolerations, p.Tolerations)
+	o.SubscriptionConfig = config
+	return nil
+}
+
+// ApplyToPodSpec merges the placement profile into the pod spec
+func ApplyToPodSpec(spec *corev1.PodSpec, p *operatorv1alpha1.PlacementProfile) {
+	if p == nil {
+		return
+	}
+	spec.NodeSelector = mergeNodeSelector(spec.NodeSelector, p.NodeSelector)
+	spec.Tolerations = mergeTolerations(spec.Tolerations, p.Tolerations)
+	if spec.Affinity == nil && p.Affinity != nil {
+		spec.Affinity = p.Affinity.DeepCopy()
+	}
+}
+
+// RenderResource renders the placement profile into the pod template of a Deployment, StatefulSet, ReplicaSet, DaemonSet or Job.
+// The data of other kinds is returned unchanged.
+func RenderResource(kind string, data []byte, p *operatorv1alpha1.PlacementProfile) ([]byte, error) {
+	if !workloadKinds[kind] || len(data) == 0 || p == nil {
+		return data, nil
+	}
+	obj := make(map[string]interface{})
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode the data of the %s", kind)
+	}
+	podSpec := []string{"spec", "template", "spec"}
+	changed, err := render(obj, p, append(podSpec, "nodeSelector"), append(podSpec, "tolerations"), append(podSpec, "affinity"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to render the placement profile %s into the %s", p.Name, kind)
+	}
+	if !changed {
+		return data, nil
+	}
+	return json.Marshal(obj)
+}
+
+// RenderCustomResource renders the placement profile into the paths of the spec of a custom resource
+func RenderCustomResource(kind string, spec []byte, pi *operatorv1alpha1.PlacementInjection, p *operatorv1alpha1.PlacementProfile) ([]byte, error) {
+	if p == nil {
+		return spec, nil
+	}
+	var changed bool
+	obj := make(map[string]interface{})
+	if len(spec) != 0 {
+		if err := json.Unmarshal(spec, &obj); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode the spec of the %s", kind)
+		}
+	}
+	for _, res := range pi.CustomResources {
+		if !strings.EqualFold(res.Kind, kind) {
+			continue
+		}
+		set, err := render(obj, p, splitPath(res.NodeSelectorPath), splitPath(res.TolerationsPath), splitPath(res.AffinityPath))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render the placement profile %s into the %s", p.Name, kind)
+		}
+		changed = changed || set
+	}
+	if !changed {
+		return spec, nil
+	}
+	return json.Marshal(obj)
+}
+
+// render merges the placement profile into the node selector, the tolerations and the affinity at the paths of the object.
+// An empty path is skipped.
+func render(obj map[string]interface{}, p *operatorv1alpha1.PlacementProfile, nodeSelectorPath, tolerationsPath, affinityPath []string) (bool, error) {
+	var changed bool
+	if len(nodeSelectorPath) != 0 && len(p.NodeSelector) != 0 {
+		current, _, err := unstructured.NestedStringMap(obj, nodeSelectorPath...)
+		if err != nil {
+			return false, err
+		}
+		merged := mergeNodeSelector(current, p.NodeSelector)
+		if !reflect.DeepEqual(current, merged) {
+			if err := unstructured.SetNestedStringMap(obj, merged, nodeSelectorPath...); err != nil {
+				return false, err
+			}
+			changed = true
+		}
+	}
+	if len(tolerationsPath) != 0 && len(p.Tolerations) != 0 {
+		var current []corev1.Toleration
+		if raw, found, _ := unstructured.NestedFieldNoCopy(obj, tolerationsPath...); found {
+			if err := convert(raw, &current); err != nil {
+				return false, err
+			}
+		}
+		merged := mergeTolerations(current, p.Tolerations)
+		if len(merged) != len(current) {
+			var tolerations []interface{}
+			for i := range merged {
+				u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&merged[i])
+				if err != nil {
+					return false, err
+				}
+				tolerations = append(tolerations, u)
+			}
+			if err := unstructured.SetNestedSlice(obj, tolerations, tolerationsPath...); err != nil {
+				return false, err
+			}
+			changed = true
+		}
+	}
+	if len(affinityPath) != 0 && p.Affinity != nil {
+		if _, found, _ := unstructured.NestedFieldNoCopy(obj, affinityPath...); !found {
+			u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(p.Affinity)
+			if err != nil {
+				return false, err
+			}
+			if err := unstructured.SetNestedMap(obj, u, affinityPath...); err != nil {
+				return false, err
+			}
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+// mergeNodeSelector returns the node selector with the keys of the profile it doesn't have
+func mergeNodeSelector(current, profile map[string]string) map[string]string {
+	if len(profile) == 0 {
+		return current
+	}
+	merged := make(map[string]string, len(current)+len(profile))
+	for k, v := range profile {
+		merged[k] = v
+	}
+	for k, v := range current {
+		merged[k] = v
+	}
+	return merged
+}
+
+// mergeTolerations returns the tolerations with the tolerations of the profile they don't match
+func mergeTolerations(current, profile []corev1.Toleration) []corev1.Toleration {
+	merged := current
+	for i := range profile {
+		found := false
+		for _, c := range current {
+			if c.MatchToleration(&profile[i]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, profile[i])
+		}
+	}
+	return merged
+}
+
+// convert converts the unstructured value into the typed value
+func convert(raw, out interface{}) error {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
@@ -0,0 +1,138 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package profile
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Placement profile", func() {
+
+	infra := operatorv1alpha1.PlacementProfile{
+		Name:         "infra",
+		NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+		Tolerations:  []corev1.Toleration{{Key: "node-role.kubernetes.io/infra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+		Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "kubernetes.io/arch", Operator: corev1.NodeSelectorOpIn, Values: []string{"amd64"}}},
+			}}},
+		}},
+	}
+
+	BeforeEach(func() {
+		Set([]operatorv1alpha1.PlacementProfile{infra})
+	})
+
+	AfterEach(func() {
+		Set(nil)
+	})
+
+	It("Should look up the placement profiles", func() {
+		p, err := Lookup("infra")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(p.Name).Should(Equal("infra"))
+		p, err = Lookup("")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(p).Should(BeNil())
+		_, err = Lookup("tenant")
+		Expect(err).Should(HaveOccurred())
+		Expect(Affinity("infra")).Should(Equal(infra.Affinity))
+		Expect(Affinity("tenant")).Should(BeNil())
+	})
+
+	It("Should expand the placement profile into the SubscriptionConfig of the operator", func() {
+		o := &operatorv1alpha1.Operator{
+			Name:             "ibm-iam-operator",
+			PlacementProfile: "infra",
+			SubscriptionConfig: &olmv1alpha1.SubscriptionConfig{
+				NodeSelector: map[string]string{"node-role.kubernetes.io/infra": "dedicated"},
+				Env:          []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "http://proxy:3128"}},
+			},
+		}
+		Expect(ExpandOperator(o)).Should(Succeed())
+		Expect(o.SubscriptionConfig.NodeSelector).Should(Equal(map[string]string{"node-role.kubernetes.io/infra": "dedicated"}))
+		Expect(o.SubscriptionConfig.Tolerations).Should(Equal(infra.Tolerations))
+		Expect(o.SubscriptionConfig.Env).Should(HaveLen(1))
+
+		// The tolerations already in the SubscriptionConfig aren't added again
+		Expect(ExpandOperator(o)).Should(Succeed())
+		Expect(o.SubscriptionConfig.Tolerations).Should(HaveLen(1))
+
+		o = &operatorv1alpha1.Operator{Name: "ibm-iam-operator", PlacementProfile: "infra"}
+		Expect(ExpandOperator(o)).Should(Succeed())
+		Expect(o.SubscriptionConfig.NodeSelector).Should(Equal(infra.NodeSelector))
+
+		Expect(ExpandOperator(&operatorv1alpha1.Operator{Name: "ibm-iam-operator", PlacementProfile: "tenant"})).ShouldNot(Succeed())
+		o = &operatorv1alpha1.Operator{Name: "ibm-iam-operator"}
+		Expect(ExpandOperator(o)).Should(Succeed())
+		Expect(o.SubscriptionConfig).Should(BeNil())
+	})
+
+	It("Should render the placement profile into the pod templates of the workloads", func() {
+		deploy := appsv1.Deployment{}
+		deploy.Spec.Template.Spec.NodeSelector = map[string]string{"disktype": "ssd"}
+		deploy.Spec.Template.Spec.Affinity = &corev1.Affinity{}
+		data, err := json.Marshal(deploy)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		rendered, err := RenderResource("Deployment", data, &infra)
+		Expect(err).ShouldNot(HaveOccurred())
+		result := appsv1.Deployment{}
+		Expect(json.Unmarshal(rendered, &result)).Should(Succeed())
+		podSpec := result.Spec.Template.Spec
+		Expect(podSpec.NodeSelector).Should(Equal(map[string]string{"disktype": "ssd", "node-role.kubernetes.io/infra": ""}))
+		Expect(podSpec.Tolerations).Should(Equal(infra.Tolerations))
+		Expect(podSpec.Affinity).Should(Equal(&corev1.Affinity{}))
+
+		rendered, err = RenderResource("Deployment", rendered, &infra)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(json.Unmarshal(rendered, &result)).Should(Succeed())
+		Expect(result.Spec.Template.Spec.Tolerations).Should(HaveLen(1))
+
+		configMap := []byte(`{"data":{"key":"value"}}`)
+		Expect(RenderResource("ConfigMap", configMap, &infra)).Should(Equal(configMap))
+	})
+
+	It("Should render the placement profile into the paths of the custom resources", func() {
+		pi := &operatorv1alpha1.PlacementInjection{
+			Profile: "infra",
+			CustomResources: []operatorv1alpha1.PlacementResource{
+				{Kind: "Authentication", NodeSelectorPath: "placement.nodeSelector", TolerationsPath: "placement.tolerations", AffinityPath: "placement.affinity"},
+			},
+		}
+		rendered, err := RenderCustomResource("authentication", []byte(`{"replicas":2,"placement":{"nodeSelector":{"node-role.kubernetes.io/infra":"dedicated"}}}`), pi, &infra)
+		Expect(err).ShouldNot(HaveOccurred())
+		spec := make(map[string]interface{})
+		Expect(json.Unmarshal(rendered, &spec)).Should(Succeed())
+		placement := spec["placement"].(map[string]interface{})
+		Expect(placement["nodeSelector"]).Should(Equal(map[string]interface{}{"node-role.kubernetes.io/infra": "dedicated"}))
+		Expect(placement["tolerations"]).Should(HaveLen(1))
+		Expect(placement["affinity"]).Should(HaveKey("nodeAffinity"))
+		Expect(spec["replicas"]).Should(BeEquivalentTo(2))
+
+		unchanged := []byte(`{"replicas":2}`)
+		Expect(RenderCustomResource("Policy", unchanged, pi, &infra)).Should(Equal(unchanged))
+	})
+})
@@ -0,0 +1,102 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package provenance records where the top-level fields of the spec of a custom resource managed by ODLM come from,
+// so the value of a field can be traced back to the alm-example, the OperandConfig, the OperandRequest, a change
+// kept in the custom resource or a patch of the operand without comparing them.
+package provenance
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+const (
+	// SourceALMExample is the source of the fields set by the alm-example of the ClusterServiceVersion
+	SourceALMExample = "alm-example"
+	// SourceOperandConfig is the source of the fields set by the service of the OperandConfig
+	SourceOperandConfig = "OperandConfig"
+	// SourceOperandRequest is the source of the fields set by the spec of the operand in the OperandRequest
+	SourceOperandRequest = "OperandRequest"
+	// SourceUser is the source of the fields changed in the custom resource and kept by the remediation policy
+	SourceUser = "user"
+	// SourcePatch is the source of the fields changed by the JSON6902 patches of the operand
+	SourcePatch = "patch"
+)
+
+// Layers are the layers merged into the spec of a custom resource, from the lowest to the highest
+type Layers struct {
+	// Defaults is the spec of the alm-example applied to the custom resource
+	Defaults []byte
+	// Config is the configuration merged into the defaults, ConfigSource is the resource it comes from
+	Config       []byte
+	ConfigSource string
+	// Kept are the dot-separated paths of the changes made in the custom resource and kept in the configuration
+	Kept []string
+	// Merged is the spec before the patches of the operand are applied, nil when the operand has no patch
+	Merged map[string]interface{}
+}
+
+// Fields returns the source of each top-level field of the spec, the highest layer setting or changing it.
+// The fields in none of the layers aren't returned.
+func Fields(layers Layers, spec map[string]interface{}) map[string]string {
+	defaults := decode(layers.Defaults)
+	config := decode(layers.Config)
+	kept := make(map[string]bool)
+	for _, path := range layers.Kept {
+		kept[strings.SplitN(path, ".", 2)[0]] = true
+	}
+
+	fields := make(map[string]string)
+	for key, value := range spec {
+		if _, ok := defaults[key]; ok {
+			fields[key] = SourceALMExample
+		}
+		if _, ok := config[key]; ok {
+			fields[key] = layers.ConfigSource
+		}
+		if kept[key] {
+			fields[key] = SourceUser
+		}
+		if layers.Merged == nil {
+			continue
+		}
+		if merged, ok := layers.Merged[key]; !ok || !reflect.DeepEqual(merged, value) {
+			fields[key] = SourcePatch
+		}
+	}
+	return fields
+}
+
+// Annotation returns the value of the provenance annotation, the JSON object of the sources by field.
+// It is empty when there is no field.
+func Annotation(fields map[string]string) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	// The keys of the maps are marshaled in order, the annotation only changes with the sources
+	raw, _ := json.Marshal(fields)
+	return string(raw)
+}
+
+func decode(raw []byte) map[string]interface{} {
+	decoded := make(map[string]interface{})
+	if len(raw) != 0 {
+		_ = json.Unmarshal(raw, &decoded)
+	}
+	return decoded
+}
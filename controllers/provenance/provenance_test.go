@@ -0,0 +1,65 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package provenance
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Field provenance", func() {
+
+	It("Should record the highest layer setting each field", func() {
+		spec := map[string]interface{}{
+			"license": map[string]interface{}{"accept": true},
+			"size":    float64(3),
+			"storage": map[string]interface{}{"class": "fast", "size": "10Gi"},
+			"version": "3.4",
+		}
+		fields := Fields(Layers{
+			Defaults:     []byte(`{"license":{"accept":false},"size":1,"storage":{"size":"10Gi"}}`),
+			Config:       []byte(`{"license":{"accept":true},"size":3,"storage":{"class":"fast"}}`),
+			ConfigSource: SourceOperandConfig,
+			Kept:         []string{"storage.class"},
+		}, spec)
+		Expect(fields).Should(Equal(map[string]string{
+			"license": SourceOperandConfig,
+			"size":    SourceOperandConfig,
+			"storage": SourceUser,
+		}))
+	})
+
+	It("Should record the fields changed and added by the patches", func() {
+		merged := map[string]interface{}{"service": map[string]interface{}{"port": float64(8081), "nodePort": float64(30081)}, "size": float64(1)}
+		spec := map[string]interface{}{"service": map[string]interface{}{"port": float64(8081)}, "size": float64(1), "sidecar": true}
+		fields := Fields(Layers{
+			Config:       []byte(`{"service":{"port":8081,"nodePort":30081},"size":1}`),
+			ConfigSource: SourceOperandRequest,
+			Merged:       merged,
+		}, spec)
+		Expect(fields).Should(Equal(map[string]string{
+			"service": SourcePatch,
+			"sidecar": SourcePatch,
+			"size":    SourceOperandRequest,
+		}))
+	})
+
+	It("Should marshal the annotation in order", func() {
+		Expect(Annotation(map[string]string{"size": SourceOperandConfig, "license": SourceALMExample})).Should(Equal(`{"license":"alm-example","size":"OperandConfig"}`))
+		Expect(Annotation(nil)).Should(BeEmpty())
+	})
+})
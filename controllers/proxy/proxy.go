@@ -0,0 +1,123 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package proxy renders the proxy and the CA bundle of the cluster into the custom resources of the services.
+package proxy
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+const (
+	// TrustedCABundleConfigMap is the default ConfigMap of the CA bundle, created by ODLM in the namespace of the service
+	TrustedCABundleConfigMap = "odlm-trusted-ca-bundle"
+	// InjectTrustedCABundleLabel asks OpenShift to inject the trusted CA bundle of the cluster into the ConfigMap
+	InjectTrustedCABundleLabel = "config.openshift.io/inject-trusted-cabundle"
+	// CABundleKey is the key of the PEM CA bundle in the ConfigMap
+	CABundleKey = "ca-bundle.crt"
+)
+
+// Settings are the proxy and the CA bundle of the cluster rendered into the custom resources
+type Settings struct {
+	HTTPProxy         string
+	HTTPSProxy        string
+	NoProxy           string
+	CABundle          string
+	CABundleConfigMap string
+}
+
+// FromEnvironment returns the proxy of the cluster in the environment of ODLM.
+// OLM sets it from the cluster-wide Proxy of OpenShift, and it can be set in the Deployment of ODLM on the other clusters.
+func FromEnvironment() Settings {
+	return Settings{
+		HTTPProxy:  getenv("HTTP_PROXY"),
+		HTTPSProxy: getenv("HTTPS_PROXY"),
+		NoProxy:    getenv("NO_PROXY"),
+	}
+}
+
+// getenv returns the env in upper case, or in lower case like curl does
+func getenv(name string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return os.Getenv(strings.ToLower(name))
+}
+
+// CABundleConfigMapName returns the ConfigMap holding the CA bundle of the service
+func CABundleConfigMapName(p *operatorv1alpha1.ProxyInjection) string {
+	if p.CABundleConfigMap == "" {
+		return TrustedCABundleConfigMap
+	}
+	return p.CABundleConfigMap
+}
+
+// NeedsCABundle returns true if the CA bundle is rendered into a custom resource of the service
+func NeedsCABundle(p *operatorv1alpha1.ProxyInjection) bool {
+	for _, res := range p.CustomResources {
+		if res.CABundlePath != "" || res.CABundleConfigMapPath != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderCustomResource renders the proxy and the CA bundle into the spec of a custom resource.
+// The fields already set in the spec and the empty settings are not rendered.
+func RenderCustomResource(kind string, spec []byte, p *operatorv1alpha1.ProxyInjection, s Settings) ([]byte, error) {
+	var changed bool
+	obj := make(map[string]interface{})
+	if len(spec) != 0 {
+		if err := json.Unmarshal(spec, &obj); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode the spec of the %s", kind)
+		}
+	}
+	for _, res := range p.CustomResources {
+		if !strings.EqualFold(res.Kind, kind) {
+			continue
+		}
+		for path, value := range map[string]string{
+			res.HTTPProxyPath:         s.HTTPProxy,
+			res.HTTPSProxyPath:        s.HTTPSProxy,
+			res.NoProxyPath:           s.NoProxy,
+			res.CABundlePath:          s.CABundle,
+			res.CABundleConfigMapPath: s.CABundleConfigMap,
+		} {
+			if path == "" || value == "" {
+				continue
+			}
+			fields := strings.Split(path, ".")
+			if _, found, _ := unstructured.NestedFieldNoCopy(obj, fields...); found {
+				continue
+			}
+			if err := unstructured.SetNestedField(obj, value, fields...); err != nil {
+				return nil, errors.Wrapf(err, "failed to render the proxy of the %s at %s", kind, path)
+			}
+			changed = true
+		}
+	}
+	if !changed {
+		return spec, nil
+	}
+	return json.Marshal(obj)
+}
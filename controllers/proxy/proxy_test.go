@@ -0,0 +1,89 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package proxy
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Proxy", func() {
+
+	p := &operatorv1alpha1.ProxyInjection{
+		CustomResources: []operatorv1alpha1.ProxyResource{
+			{
+				Kind:                  "Authentication",
+				HTTPProxyPath:         "proxy.httpProxy",
+				HTTPSProxyPath:        "proxy.httpsProxy",
+				NoProxyPath:           "proxy.noProxy",
+				CABundlePath:          "tls.caBundle",
+				CABundleConfigMapPath: "tls.caConfigMap",
+			},
+		},
+	}
+	settings := Settings{
+		HTTPProxy:         "http://proxy:3128",
+		HTTPSProxy:        "http://proxy:3128",
+		CABundle:          "-----BEGIN CERTIFICATE-----",
+		CABundleConfigMap: TrustedCABundleConfigMap,
+	}
+
+	It("Should read the proxy from the environment", func() {
+		defer os.Unsetenv("HTTP_PROXY")
+		defer os.Unsetenv("no_proxy")
+		Expect(os.Setenv("HTTP_PROXY", "http://proxy:3128")).Should(Succeed())
+		Expect(os.Setenv("no_proxy", ".cluster.local")).Should(Succeed())
+		Expect(FromEnvironment()).Should(Equal(Settings{HTTPProxy: "http://proxy:3128", NoProxy: ".cluster.local"}))
+	})
+
+	It("Should return the ConfigMap of the CA bundle", func() {
+		Expect(CABundleConfigMapName(p)).Should(Equal(TrustedCABundleConfigMap))
+		Expect(CABundleConfigMapName(&operatorv1alpha1.ProxyInjection{CABundleConfigMap: "custom-ca"})).Should(Equal("custom-ca"))
+		Expect(NeedsCABundle(p)).Should(BeTrue())
+		Expect(NeedsCABundle(&operatorv1alpha1.ProxyInjection{CustomResources: []operatorv1alpha1.ProxyResource{{Kind: "Authentication", HTTPProxyPath: "proxy"}}})).Should(BeFalse())
+	})
+
+	It("Should render the proxy into the paths of the custom resource", func() {
+		data, err := RenderCustomResource("authentication", []byte(`{"replicas":1,"proxy":{"httpsProxy":"http://other:8080"}}`), p, settings)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).Should(MatchJSON(`{
+			"replicas": 1,
+			"proxy": {"httpProxy": "http://proxy:3128", "httpsProxy": "http://other:8080"},
+			"tls": {"caBundle": "-----BEGIN CERTIFICATE-----", "caConfigMap": "odlm-trusted-ca-bundle"}
+		}`))
+	})
+
+	It("Should leave the other custom resources and the empty settings", func() {
+		spec := []byte(`{"replicas":1}`)
+		data, err := RenderCustomResource("Policy", spec, p, settings)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).Should(Equal(spec))
+
+		data, err = RenderCustomResource("Authentication", spec, p, Settings{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).Should(Equal(spec))
+	})
+
+	It("Should fail when the path crosses a field which isn't an object", func() {
+		_, err := RenderCustomResource("Authentication", []byte(`{"proxy":"http://proxy:3128"}`), p, settings)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,91 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package scaling renders the replicas requested by the OperandRequests into the custom resources of the services,
+// at the replica paths declared in the OperandConfig.
+package scaling
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+// Validate checks the replicas are in the range of the scaling hints
+func Validate(h *operatorv1alpha1.ScalingHints, replicas int32) error {
+	if h.MinReplicas != nil && replicas < *h.MinReplicas {
+		return errors.Errorf("the replicas %d are below the minimum %d of the service", replicas, *h.MinReplicas)
+	}
+	if h.MaxReplicas != nil && replicas > *h.MaxReplicas {
+		return errors.Errorf("the replicas %d are above the maximum %d of the service", replicas, *h.MaxReplicas)
+	}
+	return nil
+}
+
+// Highest returns the highest of the requested replicas, it is nil when none is requested
+func Highest(requested []*int32) *int32 {
+	var highest *int32
+	for _, replicas := range requested {
+		if replicas != nil && (highest == nil || *replicas > *highest) {
+			highest = replicas
+		}
+	}
+	return highest
+}
+
+// RenderCustomResource renders the replicas into the spec of a custom resource, at the replica paths of its kind.
+// The replicas requested by the OperandRequests take precedence over the ones in the spec.
+func RenderCustomResource(kind string, spec []byte, h *operatorv1alpha1.ScalingHints, replicas int32) ([]byte, error) {
+	var changed bool
+	obj := make(map[string]interface{})
+	if len(spec) != 0 {
+		if err := json.Unmarshal(spec, &obj); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode the spec of the %s", kind)
+		}
+	}
+	for _, res := range h.CustomResources {
+		if !strings.EqualFold(res.Kind, kind) || res.ReplicasPath == "" {
+			continue
+		}
+		path := strings.Split(res.ReplicasPath, ".")
+		if current, found, _ := unstructured.NestedFieldNoCopy(obj, path...); found && isNumber(current, replicas) {
+			continue
+		}
+		if err := unstructured.SetNestedField(obj, int64(replicas), path...); err != nil {
+			return nil, errors.Wrapf(err, "failed to render the replicas of the %s at %s", kind, res.ReplicasPath)
+		}
+		changed = true
+	}
+	if !changed {
+		return spec, nil
+	}
+	return json.Marshal(obj)
+}
+
+// isNumber returns true if the decoded JSON value is the number
+func isNumber(value interface{}, n int32) bool {
+	switch v := value.(type) {
+	case float64:
+		return v == float64(n)
+	case int64:
+		return v == int64(n)
+	}
+	return false
+}
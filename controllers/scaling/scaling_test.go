@@ -0,0 +1,71 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package scaling
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Scaling", func() {
+
+	int32Ptr := func(i int32) *int32 { return &i }
+
+	hints := &operatorv1alpha1.ScalingHints{
+		MinReplicas: int32Ptr(1),
+		MaxReplicas: int32Ptr(5),
+		CustomResources: []operatorv1alpha1.ScalingResource{
+			{Kind: "EtcdCluster", ReplicasPath: "size"},
+			{Kind: "etcdCluster", ReplicasPath: "proxy.replicas"},
+			{Kind: "EtcdBackup", ReplicasPath: "workers"},
+		},
+	}
+
+	It("Should render the replicas at the paths of the kind", func() {
+		spec, err := RenderCustomResource("etcdCluster", []byte(`{"size":1,"storage":{"class":"fast"}}`), hints, 3)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(spec).Should(MatchJSON(`{"size":3,"proxy":{"replicas":3},"storage":{"class":"fast"}}`))
+
+		spec, err = RenderCustomResource("etcdRestore", []byte(`{"size":1}`), hints, 3)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(spec).Should(MatchJSON(`{"size":1}`))
+	})
+
+	It("Should keep the spec when the replicas are already set", func() {
+		original := []byte(`{"size":3, "proxy":{"replicas":3}}`)
+		spec, err := RenderCustomResource("etcdCluster", original, hints, 3)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(spec).Should(Equal(original))
+	})
+
+	It("Should fail when the path crosses a value", func() {
+		_, err := RenderCustomResource("etcdCluster", []byte(`{"proxy":"none"}`), hints, 3)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("Should validate the range and select the highest replicas", func() {
+		Expect(Validate(hints, 1)).Should(Succeed())
+		Expect(Validate(hints, 0)).Should(MatchError(ContainSubstring("minimum 1")))
+		Expect(Validate(hints, 6)).Should(MatchError(ContainSubstring("maximum 5")))
+		Expect(Validate(&operatorv1alpha1.ScalingHints{}, 100)).Should(Succeed())
+
+		Expect(Highest(nil)).Should(BeNil())
+		Expect(*Highest([]*int32{nil, int32Ptr(2), int32Ptr(4), int32Ptr(3)})).Should(Equal(int32(4)))
+	})
+})
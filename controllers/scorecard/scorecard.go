@@ -0,0 +1,307 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package scorecard evaluates the services of the OperandConfigs against the best practices,
+// like the scorecard of the Operator SDK evaluates the bundles.
+package scorecard
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+// The checks of the scorecard
+const (
+	// CheckResourceLimits checks the containers have CPU and memory limits
+	CheckResourceLimits = "ResourceLimits"
+	// CheckProbes checks the containers have liveness and readiness probes, and the HTTP probes have a path
+	CheckProbes = "Probes"
+	// CheckPlaintextSecrets checks the configuration holds no password, token or key in plain text
+	CheckPlaintextSecrets = "PlaintextSecrets"
+	// CheckSchema checks the custom resources match the schemas of their CRDs
+	CheckSchema = "Schema"
+)
+
+// maxFindings is the maximum number of the findings of a service in the status
+const maxFindings = 20
+
+// workloadKinds are the kinds of k8s resources whose pod template is checked
+var workloadKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"ReplicaSet":  true,
+	"DaemonSet":   true,
+	"Job":         true,
+}
+
+// sensitiveKey matches the names of the fields holding a password, a token or a key.
+// The fields referencing a Secret, like passwordSecretName, don't match.
+var sensitiveKey = regexp.MustCompile(`(?i)(password|passwd|passphrase|token|apikey|api_key|secretkey|secret_key|privatekey|private_key|accesskey|access_key)$`)
+
+// Evaluate returns the scorecard of the service at the index of the OperandConfig.
+// schemaErrs are the violations of the CRD schemas, the Schema check is skipped when the schemas aren't known.
+func Evaluate(index int, service *operatorv1alpha1.ConfigService, schemaErrs field.ErrorList, schemaKnown bool) operatorv1alpha1.ServiceScorecard {
+	servicePath := field.NewPath("spec", "services").Index(index)
+	findings := map[string][]operatorv1alpha1.ScorecardFinding{
+		CheckResourceLimits:   nil,
+		CheckProbes:           nil,
+		CheckPlaintextSecrets: nil,
+	}
+
+	for i, res := range service.Resources {
+		if res.Data == nil || len(res.Data.Raw) == 0 {
+			continue
+		}
+		data := make(map[string]interface{})
+		if err := json.Unmarshal(res.Data.Raw, &data); err != nil {
+			continue
+		}
+		dataPath := servicePath.Child("resources").Index(i).Child("data")
+		if workloadKinds[res.Kind] {
+			findings[CheckResourceLimits] = append(findings[CheckResourceLimits], checkWorkloadLimits(data, dataPath)...)
+			if res.Kind != "Job" {
+				findings[CheckProbes] = append(findings[CheckProbes], checkWorkloadProbes(data, dataPath)...)
+			}
+		}
+		if res.Kind == "Secret" {
+			findings[CheckPlaintextSecrets] = append(findings[CheckPlaintextSecrets], checkSecretData(data, dataPath)...)
+			continue
+		}
+		findings[CheckPlaintextSecrets] = append(findings[CheckPlaintextSecrets], checkPlaintext(data, dataPath)...)
+	}
+
+	kinds := make([]string, 0, len(service.Spec))
+	for kind := range service.Spec {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		spec := service.Spec[kind]
+		if len(spec.Raw) == 0 {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(spec.Raw, &value); err != nil {
+			continue
+		}
+		specPath := servicePath.Child("spec").Key(kind)
+		findings[CheckResourceLimits] = append(findings[CheckResourceLimits], checkResourcesLimits(value, specPath)...)
+		findings[CheckPlaintextSecrets] = append(findings[CheckPlaintextSecrets], checkPlaintext(value, specPath)...)
+	}
+
+	if schemaKnown {
+		findings[CheckSchema] = nil
+		for _, err := range schemaErrs {
+			findings[CheckSchema] = append(findings[CheckSchema], operatorv1alpha1.ScorecardFinding{Check: CheckSchema, Path: err.Field, Message: err.ErrorBody()})
+		}
+	}
+
+	return score(service.Name, findings)
+}
+
+// Summarize returns the scorecard of the OperandConfig from the scorecards of its services
+func Summarize(services []operatorv1alpha1.ServiceScorecard) *operatorv1alpha1.Scorecard {
+	var passed, evaluated int
+	for _, s := range services {
+		passed += len(s.Passed)
+		evaluated += len(s.Passed) + len(s.Failed)
+	}
+	sc := &operatorv1alpha1.Scorecard{Score: 100, Services: services}
+	if evaluated != 0 {
+		sc.Score = passed * 100 / evaluated
+	}
+	return sc
+}
+
+// score returns the scorecard of the service from the findings of the evaluated checks
+func score(name string, findings map[string][]operatorv1alpha1.ScorecardFinding) operatorv1alpha1.ServiceScorecard {
+	checks := make([]string, 0, len(findings))
+	for check := range findings {
+		checks = append(checks, check)
+	}
+	sort.Strings(checks)
+
+	sc := operatorv1alpha1.ServiceScorecard{Name: name}
+	for _, check := range checks {
+		if len(findings[check]) == 0 {
+			sc.Passed = append(sc.Passed, check)
+			continue
+		}
+		sc.Failed = append(sc.Failed, check)
+		for _, f := range findings[check] {
+			if len(sc.Findings) == maxFindings {
+				sc.OmittedFindings++
+				continue
+			}
+			sc.Findings = append(sc.Findings, f)
+		}
+	}
+	sc.Score = len(sc.Passed) * 100 / len(checks)
+	return sc
+}
+
+// containers returns the containers and the init containers of the pod template of the workload, with their paths
+func containers(data map[string]interface{}, dataPath *field.Path, initContainers bool) ([]map[string]interface{}, []*field.Path) {
+	podSpecPath := dataPath.Child("spec", "template", "spec")
+	var found []map[string]interface{}
+	var paths []*field.Path
+	keys := []string{"containers"}
+	if initContainers {
+		keys = append(keys, "initContainers")
+	}
+	for _, key := range keys {
+		list, _, _ := unstructured.NestedSlice(data, "spec", "template", "spec", key)
+		for i, item := range list {
+			if c, ok := item.(map[string]interface{}); ok {
+				found = append(found, c)
+				paths = append(paths, podSpecPath.Child(key).Index(i))
+			}
+		}
+	}
+	return found, paths
+}
+
+// checkWorkloadLimits checks the containers of the workload have CPU and memory limits
+func checkWorkloadLimits(data map[string]interface{}, dataPath *field.Path) []operatorv1alpha1.ScorecardFinding {
+	var findings []operatorv1alpha1.ScorecardFinding
+	list, paths := containers(data, dataPath, true)
+	for i, c := range list {
+		limits, _, _ := unstructured.NestedMap(c, "resources", "limits")
+		for _, resource := range []string{"cpu", "memory"} {
+			if _, ok := limits[resource]; !ok {
+				findings = append(findings, operatorv1alpha1.ScorecardFinding{
+					Check:   CheckResourceLimits,
+					Path:    paths[i].Child("resources", "limits", resource).String(),
+					Message: fmt.Sprintf("the container %v has no %s limit", c["name"], resource),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// checkResourcesLimits checks the resources fields of a custom resource, which set requests, have limits
+func checkResourcesLimits(value interface{}, fldPath *field.Path) []operatorv1alpha1.ScorecardFinding {
+	var findings []operatorv1alpha1.ScorecardFinding
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			if res, ok := v[key].(map[string]interface{}); ok && key == "resources" {
+				if _, hasRequests := res["requests"]; hasRequests {
+					if _, hasLimits := res["limits"]; !hasLimits {
+						findings = append(findings, operatorv1alpha1.ScorecardFinding{
+							Check:   CheckResourceLimits,
+							Path:    fldPath.Child(key, "limits").String(),
+							Message: "the resources set requests without limits",
+						})
+					}
+				}
+				continue
+			}
+			findings = append(findings, checkResourcesLimits(v[key], fldPath.Child(key))...)
+		}
+	case []interface{}:
+		for i, item := range v {
+			findings = append(findings, checkResourcesLimits(item, fldPath.Index(i))...)
+		}
+	}
+	return findings
+}
+
+// checkWorkloadProbes checks the containers of the workload have liveness and readiness probes with a known HTTP path
+func checkWorkloadProbes(data map[string]interface{}, dataPath *field.Path) []operatorv1alpha1.ScorecardFinding {
+	var findings []operatorv1alpha1.ScorecardFinding
+	list, paths := containers(data, dataPath, false)
+	for i, c := range list {
+		for _, probe := range []string{"livenessProbe", "readinessProbe"} {
+			p, found, _ := unstructured.NestedMap(c, probe)
+			if !found {
+				findings = append(findings, operatorv1alpha1.ScorecardFinding{
+					Check:   CheckProbes,
+					Path:    paths[i].Child(probe).String(),
+					Message: fmt.Sprintf("the container %v has no %s", c["name"], probe),
+				})
+				continue
+			}
+			if httpGet, ok := p["httpGet"].(map[string]interface{}); ok {
+				if path, _ := httpGet["path"].(string); path == "" {
+					findings = append(findings, operatorv1alpha1.ScorecardFinding{
+						Check:   CheckProbes,
+						Path:    paths[i].Child(probe, "httpGet", "path").String(),
+						Message: fmt.Sprintf("the %s of the container %v has no HTTP path", probe, c["name"]),
+					})
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// checkSecretData reports the values of a Secret, they are stored in plain text in the OperandConfig
+func checkSecretData(data map[string]interface{}, dataPath *field.Path) []operatorv1alpha1.ScorecardFinding {
+	var findings []operatorv1alpha1.ScorecardFinding
+	for _, key := range []string{"data", "stringData"} {
+		values, _, _ := unstructured.NestedMap(data, key)
+		for _, name := range sortedKeys(values) {
+			findings = append(findings, operatorv1alpha1.ScorecardFinding{
+				Check:   CheckPlaintextSecrets,
+				Path:    dataPath.Child(key).Key(name).String(),
+				Message: "the Secret value is stored in the OperandConfig, create the Secret outside of the OperandConfig",
+			})
+		}
+	}
+	return findings
+}
+
+// checkPlaintext reports the non-empty string fields named like a password, a token or a key
+func checkPlaintext(value interface{}, fldPath *field.Path) []operatorv1alpha1.ScorecardFinding {
+	var findings []operatorv1alpha1.ScorecardFinding
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			if s, ok := v[key].(string); ok && s != "" && sensitiveKey.MatchString(key) {
+				findings = append(findings, operatorv1alpha1.ScorecardFinding{
+					Check:   CheckPlaintextSecrets,
+					Path:    fldPath.Child(key).String(),
+					Message: "the field holds a plain text value, reference a Secret instead",
+				})
+				continue
+			}
+			findings = append(findings, checkPlaintext(v[key], fldPath.Child(key))...)
+		}
+	case []interface{}:
+		for i, item := range v {
+			findings = append(findings, checkPlaintext(item, fldPath.Index(i))...)
+		}
+	}
+	return findings
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,106 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package scorecard
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Scorecard", func() {
+
+	deployment := `{"spec":{"template":{"spec":{"containers":[{"name":"operand",
+		"resources":{"limits":{"cpu":"500m","memory":"512Mi"}},
+		"livenessProbe":{"httpGet":{"path":"/healthz","port":8080}},
+		"readinessProbe":{"httpGet":{"port":8080}}}]}}}}`
+
+	It("Should score a service following the best practices", func() {
+		service := &operatorv1alpha1.ConfigService{
+			Name: "ibm-iam-operator",
+			Spec: map[string]runtime.RawExtension{
+				"authentication": {Raw: []byte(`{"resources":{"requests":{"cpu":"100m"},"limits":{"cpu":"1"}},"adminPasswordSecretName":"admin"}`)},
+			},
+		}
+		sc := Evaluate(0, service, nil, true)
+		Expect(sc.Score).Should(Equal(100))
+		Expect(sc.Passed).Should(Equal([]string{CheckPlaintextSecrets, CheckProbes, CheckResourceLimits, CheckSchema}))
+		Expect(sc.Findings).Should(BeEmpty())
+
+		sc = Evaluate(0, service, nil, false)
+		Expect(sc.Passed).ShouldNot(ContainElement(CheckSchema))
+	})
+
+	It("Should report the findings of the failed checks", func() {
+		service := &operatorv1alpha1.ConfigService{
+			Name: "ibm-iam-operator",
+			Spec: map[string]runtime.RawExtension{
+				"authentication": {Raw: []byte(`{"resources":{"requests":{"cpu":"100m"}},"config":{"adminPassword":"passw0rd"}}`)},
+			},
+			Resources: []operatorv1alpha1.ConfigResource{
+				{Name: "operand", Kind: "Deployment", APIVersion: "apps/v1", Data: &runtime.RawExtension{Raw: []byte(deployment)}},
+				{Name: "credentials", Kind: "Secret", APIVersion: "v1", Data: &runtime.RawExtension{Raw: []byte(`{"stringData":{"password":"passw0rd"}}`)}},
+			},
+		}
+		schemaErrs := field.ErrorList{field.Invalid(field.NewPath("spec", "services").Index(1).Child("spec").Key("authentication").Child("replicas"), "two", "must be an integer")}
+		sc := Evaluate(1, service, schemaErrs, true)
+		Expect(sc.Score).Should(Equal(0))
+		Expect(sc.Failed).Should(Equal([]string{CheckPlaintextSecrets, CheckProbes, CheckResourceLimits, CheckSchema}))
+
+		paths := map[string][]string{}
+		for _, f := range sc.Findings {
+			paths[f.Check] = append(paths[f.Check], f.Path)
+		}
+		Expect(paths[CheckPlaintextSecrets]).Should(ConsistOf(
+			"spec.services[1].resources[1].data.stringData[password]",
+			"spec.services[1].spec[authentication].config.adminPassword",
+		))
+		Expect(paths[CheckProbes]).Should(ConsistOf("spec.services[1].resources[0].data.spec.template.spec.containers[0].readinessProbe.httpGet.path"))
+		Expect(paths[CheckResourceLimits]).Should(ConsistOf("spec.services[1].spec[authentication].resources.limits"))
+		Expect(paths[CheckSchema]).Should(ConsistOf("spec.services[1].spec[authentication].replicas"))
+	})
+
+	It("Should keep the status small", func() {
+		data := `{"stringData":{`
+		for i := 0; i < maxFindings+5; i++ {
+			if i > 0 {
+				data += ","
+			}
+			data += `"key` + string(rune('a'+i)) + `":"value"`
+		}
+		data += `}}`
+		service := &operatorv1alpha1.ConfigService{
+			Name:      "ibm-iam-operator",
+			Resources: []operatorv1alpha1.ConfigResource{{Name: "credentials", Kind: "Secret", APIVersion: "v1", Data: &runtime.RawExtension{Raw: []byte(data)}}},
+		}
+		sc := Evaluate(0, service, nil, false)
+		Expect(sc.Findings).Should(HaveLen(maxFindings))
+		Expect(sc.OmittedFindings).Should(Equal(5))
+	})
+
+	It("Should summarize the scores of the services", func() {
+		services := []operatorv1alpha1.ServiceScorecard{
+			{Name: "a", Passed: []string{CheckProbes, CheckResourceLimits, CheckPlaintextSecrets}},
+			{Name: "b", Passed: []string{CheckProbes}, Failed: []string{CheckResourceLimits, CheckPlaintextSecrets, CheckSchema}},
+		}
+		Expect(Summarize(services).Score).Should(Equal(57))
+		Expect(Summarize(nil).Score).Should(Equal(100))
+	})
+})
@@ -0,0 +1,376 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package shard spreads the namespaces across several ODLM instances on the large clusters, where a single
+// instance can't keep up with all the OperandRequests. Each instance claims an OperandShard, and reconciles
+// the ODLM resources of the namespaces of its shard: the namespaces labeled with the name of the shard,
+// and the namespaces without the label whose name hashes to the index of the shard. The shard with the
+// index 0 also reconciles the cluster scoped resources.
+package shard
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+const (
+	// ClaimDuration is how long an OperandShard stays claimed after the last renewal of its holder
+	ClaimDuration = time.Minute
+	// RenewInterval is the time between two renewals of the claim
+	RenewInterval = 20 * time.Second
+)
+
+var log = logging.Logger("shard")
+
+// Options are the flags of the sharding
+type Options struct {
+	// Name is the name of the OperandShard claimed by ODLM
+	Name string
+}
+
+// BindFlags binds the options of the sharding to the flags
+func (o *Options) BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Name, "shard", "", "The name of the OperandShard claimed by ODLM, it reconciles all the namespaces when it is empty.")
+}
+
+// configured holds the shard of the flags, the one of the OperatorConfig and the claimed one
+var configured = struct {
+	sync.RWMutex
+	defaults string
+	name     string
+	// claimed is the spec of the claimed OperandShard, nil when ODLM isn't sharded
+	claimed *operatorv1alpha1.OperandShardSpec
+	// labels are the shards of the namespaces labeled with operator.ibm.com/odlm-shard
+	labels map[string]string
+}{}
+
+// Setup sets the shard of the flags, ODLM claims it unless the OperatorConfig overrides it
+func Setup(o Options) {
+	configured.Lock()
+	defer configured.Unlock()
+	configured.defaults = o.Name
+	configured.name = o.Name
+}
+
+// GetDefaults returns the shard of the flags
+func GetDefaults() string {
+	configured.RLock()
+	defer configured.RUnlock()
+	return configured.defaults
+}
+
+// Get returns the name of the shard ODLM claims, empty when it isn't sharded
+func Get() string {
+	configured.RLock()
+	defer configured.RUnlock()
+	return configured.name
+}
+
+// Set sets the name of the shard ODLM claims, it is only set when ODLM starts
+func Set(name string) {
+	configured.Lock()
+	defer configured.Unlock()
+	configured.name = name
+}
+
+// Index returns the index of the shard of the namespace by the hash of its name
+func Index(namespace string, shards int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(namespace))
+	return int(h.Sum32() % uint32(shards))
+}
+
+// Owns returns true if the namespace belongs to the claimed shard, the cluster scoped resources, without
+// a namespace, belong to the shard with the index 0. All the namespaces belong to ODLM when it isn't sharded.
+func Owns(namespace string) bool {
+	configured.RLock()
+	defer configured.RUnlock()
+	if configured.claimed == nil {
+		return true
+	}
+	if namespace == "" {
+		return configured.claimed.Index == 0
+	}
+	if name, ok := configured.labels[namespace]; ok {
+		return name == configured.name
+	}
+	return Index(namespace, configured.claimed.Shards) == configured.claimed.Index
+}
+
+// Primary returns true if ODLM reconciles the cluster scoped resources
+func Primary() bool {
+	return Owns("")
+}
+
+// Reconciler skips the resources of the namespaces of the other shards
+func Reconciler(r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		if !Owns(req.Namespace) {
+			return reconcile.Result{}, nil
+		}
+		return r.Reconcile(ctx, req)
+	})
+}
+
+// Claim claims the OperandShard of the settings for the holder, and tracks the namespaces labeled with
+// the shards until the context is done. It fails when the shards are inconsistent, or when another ODLM
+// instance holds the OperandShard.
+func Claim(ctx context.Context, c client.Client, config *rest.Config, holder string) error {
+	name := Get()
+	if name == "" {
+		return nil
+	}
+	instance := &operatorv1alpha1.OperandShard{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, instance); err != nil {
+		return errors.Wrapf(err, "failed to get the OperandShard %s", name)
+	}
+	shards := &operatorv1alpha1.OperandShardList{}
+	if err := c.List(ctx, shards); err != nil {
+		return errors.Wrap(err, "failed to list the OperandShards")
+	}
+	if err := Validate(instance, shards.Items); err != nil {
+		return err
+	}
+	if err := claim(ctx, c, instance, holder, time.Now()); err != nil {
+		return err
+	}
+	labels, err := trackNamespaces(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	configured.Lock()
+	defer configured.Unlock()
+	spec := instance.Spec
+	configured.claimed = &spec
+	labels.notify(func(namespaces map[string]string) {
+		configured.Lock()
+		defer configured.Unlock()
+		configured.labels = namespaces
+	})
+	configured.labels = labels.get()
+	log.Info("Claimed the OperandShard", "shard", name, "index", spec.Index, "shards", spec.Shards)
+	return nil
+}
+
+// Validate checks that the OperandShard has a valid index, and the same number of shards and a different index
+// than the other OperandShards, otherwise some namespaces would be reconciled twice or never
+func Validate(instance *operatorv1alpha1.OperandShard, shards []operatorv1alpha1.OperandShard) error {
+	if instance.Spec.Shards < 1 || instance.Spec.Index < 0 || instance.Spec.Index >= instance.Spec.Shards {
+		return fmt.Errorf("the index %d of the OperandShard %s is not between 0 and %d", instance.Spec.Index, instance.Name, instance.Spec.Shards-1)
+	}
+	for _, other := range shards {
+		if other.Name == instance.Name {
+			continue
+		}
+		if other.Spec.Shards != instance.Spec.Shards {
+			return fmt.Errorf("the OperandShard %s has %d shards, the OperandShard %s has %d", instance.Name, instance.Spec.Shards, other.Name, other.Spec.Shards)
+		}
+		if other.Spec.Index == instance.Spec.Index {
+			return fmt.Errorf("the OperandShards %s and %s have the same index %d", instance.Name, other.Name, instance.Spec.Index)
+		}
+	}
+	return nil
+}
+
+// claimable returns an error when another holder renewed its claim of the OperandShard less than ClaimDuration ago
+func claimable(instance *operatorv1alpha1.OperandShard, holder string, now time.Time) error {
+	status := instance.Status
+	if status.Holder == "" || status.Holder == holder || status.Phase != operatorv1alpha1.ShardClaimed || status.RenewTime == nil {
+		return nil
+	}
+	if now.Before(status.RenewTime.Add(ClaimDuration)) {
+		return fmt.Errorf("the OperandShard %s is claimed by the ODLM in %s, renewed at %s", instance.Name, status.Holder, status.RenewTime.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// claim sets the holder of the OperandShard, the update fails if another instance claimed it in the meantime
+func claim(ctx context.Context, c client.Client, instance *operatorv1alpha1.OperandShard, holder string, now time.Time) error {
+	if err := claimable(instance, holder, now); err != nil {
+		return err
+	}
+	instance.Status.Holder = holder
+	instance.Status.Phase = operatorv1alpha1.ShardClaimed
+	instance.Status.RenewTime = &metav1.Time{Time: now}
+	if err := c.Status().Update(ctx, instance); err != nil {
+		return errors.Wrapf(err, "failed to claim the OperandShard %s", instance.Name)
+	}
+	return nil
+}
+
+// Renewer renews the claim of the OperandShard while ODLM is the leader, and marks it Expired when it stops,
+// so another instance can claim it at once
+type Renewer struct {
+	client client.Client
+	reader client.Reader
+	holder string
+}
+
+// NewRenewer returns the renewer of the claimed OperandShard
+func NewRenewer(c client.Client, reader client.Reader, holder string) *Renewer {
+	return &Renewer{client: c, reader: reader, holder: holder}
+}
+
+// Start renews the claim until the context is done, it fails when another instance claimed the OperandShard
+func (r *Renewer) Start(ctx context.Context) error {
+	name := Get()
+	ticker := time.NewTicker(RenewInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			r.release(name)
+			return nil
+		case <-ticker.C:
+			if err := r.renew(ctx, name, time.Now()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// NeedLeaderElection returns true, only the leader renews the claim
+func (r *Renewer) NeedLeaderElection() bool {
+	return true
+}
+
+// renew renews the claim, the update fails if another instance claimed the OperandShard in the meantime
+func (r *Renewer) renew(ctx context.Context, name string, now time.Time) error {
+	instance := &operatorv1alpha1.OperandShard{}
+	if err := r.reader.Get(ctx, types.NamespacedName{Name: name}, instance); err != nil {
+		log.Error(err, "failed to get the OperandShard to renew its claim", "shard", name)
+		return nil
+	}
+	if instance.Status.Holder != r.holder {
+		return fmt.Errorf("the OperandShard %s is claimed by the ODLM in %s", name, instance.Status.Holder)
+	}
+	if err := claim(ctx, r.client, instance, r.holder, now); err != nil {
+		log.Error(err, "failed to renew the claim of the OperandShard", "shard", name)
+	}
+	return nil
+}
+
+// release marks the OperandShard Expired when ODLM stops
+func (r *Renewer) release(name string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	instance := &operatorv1alpha1.OperandShard{}
+	if err := r.reader.Get(ctx, types.NamespacedName{Name: name}, instance); err != nil || instance.Status.Holder != r.holder {
+		return
+	}
+	instance.Status.Phase = operatorv1alpha1.ShardExpired
+	if err := r.client.Status().Update(ctx, instance); err != nil {
+		log.Error(err, "failed to release the OperandShard", "shard", name)
+	}
+}
+
+// namespaceLabels are the shards of the labeled namespaces
+type namespaceLabels struct {
+	mu         sync.Mutex
+	namespaces map[string]string
+	onChange   func(map[string]string)
+}
+
+// get returns a copy of the shards of the labeled namespaces
+func (l *namespaceLabels) get() map[string]string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	namespaces := make(map[string]string, len(l.namespaces))
+	for namespace, name := range l.namespaces {
+		namespaces[namespace] = name
+	}
+	return namespaces
+}
+
+// notify calls the function with the shards of the labeled namespaces each time they change
+func (l *namespaceLabels) notify(onChange func(map[string]string)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onChange = onChange
+}
+
+// set sets the shard of a namespace, it is removed when the name is empty
+func (l *namespaceLabels) set(namespace, name string) {
+	l.mu.Lock()
+	if name == "" {
+		delete(l.namespaces, namespace)
+	} else {
+		l.namespaces[namespace] = name
+	}
+	onChange := l.onChange
+	l.mu.Unlock()
+	if onChange != nil {
+		onChange(l.get())
+	}
+}
+
+// trackNamespaces watches the namespaces labeled with the shards until the context is done
+func trackNamespaces(ctx context.Context, config *rest.Config) (*namespaceLabels, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	labels := &namespaceLabels{namespaces: map[string]string{}}
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0, informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+		options.LabelSelector = operatorv1alpha1.ShardLabel
+	}))
+	informer := factory.Core().V1().Namespaces().Informer()
+	informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if ns, ok := obj.(*corev1.Namespace); ok {
+				labels.set(ns.Name, ns.Labels[operatorv1alpha1.ShardLabel])
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if ns, ok := obj.(*corev1.Namespace); ok {
+				labels.set(ns.Name, ns.Labels[operatorv1alpha1.ShardLabel])
+			}
+		},
+		// The watch of a label selector deletes the namespaces losing the label
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if ns, ok := obj.(*corev1.Namespace); ok {
+				labels.set(ns.Name, "")
+			}
+		},
+	})
+	go informer.Run(ctx.Done())
+	if !toolscache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return nil, fmt.Errorf("failed to sync the namespaces labeled with %s", operatorv1alpha1.ShardLabel)
+	}
+	return labels, nil
+}
@@ -0,0 +1,128 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package shard
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Shard the namespaces", func() {
+
+	newShard := func(name string, shards, index int) *operatorv1alpha1.OperandShard {
+		return &operatorv1alpha1.OperandShard{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       operatorv1alpha1.OperandShardSpec{Shards: shards, Index: index},
+		}
+	}
+
+	claimShard := func(name string, shards, index int, labels map[string]string) {
+		Set(name)
+		configured.Lock()
+		configured.claimed = &operatorv1alpha1.OperandShardSpec{Shards: shards, Index: index}
+		configured.labels = labels
+		configured.Unlock()
+	}
+
+	AfterEach(func() {
+		Set("")
+		configured.Lock()
+		configured.claimed = nil
+		configured.labels = nil
+		configured.Unlock()
+	})
+
+	It("Should reconcile all the namespaces when ODLM isn't sharded", func() {
+		Expect(Owns("tenant-a")).Should(BeTrue())
+		Expect(Primary()).Should(BeTrue())
+	})
+
+	It("Should spread the namespaces by their labels and the hash of their names", func() {
+		claimShard("shard-1", 2, 1, map[string]string{"tenant-b": "shard-0"})
+		Expect(Primary()).Should(BeFalse())
+		Expect(Owns("tenant-b")).Should(BeFalse())
+
+		for _, namespace := range []string{"tenant-a", "tenant-c", "tenant-d", "tenant-e"} {
+			Expect(Owns(namespace)).Should(Equal(Index(namespace, 2) == 1))
+		}
+
+		// The label takes precedence over the hash
+		configured.Lock()
+		configured.labels = map[string]string{"tenant-b": "shard-1"}
+		configured.Unlock()
+		Expect(Owns("tenant-b")).Should(BeTrue())
+	})
+
+	It("Should skip the resources of the namespaces of the other shards", func() {
+		claimShard("shard-0", 2, 0, map[string]string{"tenant-a": "shard-0", "tenant-b": "shard-1"})
+		var reconciled []string
+		r := Reconciler(reconcile.Func(func(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+			reconciled = append(reconciled, req.Namespace)
+			return reconcile.Result{}, nil
+		}))
+		for _, namespace := range []string{"tenant-a", "tenant-b", ""} {
+			_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "common-service"}})
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+		Expect(reconciled).Should(Equal([]string{"tenant-a", ""}))
+	})
+
+	It("Should reject the inconsistent OperandShards", func() {
+		shards := []operatorv1alpha1.OperandShard{*newShard("shard-0", 2, 0), *newShard("shard-1", 2, 1)}
+		Expect(Validate(newShard("shard-1", 2, 1), shards)).Should(Succeed())
+		Expect(Validate(newShard("shard-2", 2, 2), shards)).ShouldNot(Succeed())
+		Expect(Validate(newShard("shard-2", 3, 2), shards)).ShouldNot(Succeed())
+		Expect(Validate(newShard("shard-2", 2, 1), shards)).ShouldNot(Succeed())
+	})
+
+	It("Should claim the OperandShard unless another ODLM renewed its claim", func() {
+		scheme := runtime.NewScheme()
+		Expect(operatorv1alpha1.AddToScheme(scheme)).Should(Succeed())
+		instance := newShard("shard-0", 2, 0)
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(instance).Build()
+		ctx := context.Background()
+		now := time.Now()
+
+		Expect(claim(ctx, c, instance, "odlm-a", now)).Should(Succeed())
+		claimed := &operatorv1alpha1.OperandShard{}
+		Expect(c.Get(ctx, types.NamespacedName{Name: "shard-0"}, claimed)).Should(Succeed())
+		Expect(claimed.Status.Holder).Should(Equal("odlm-a"))
+		Expect(claimed.Status.Phase).Should(Equal(operatorv1alpha1.ShardClaimed))
+
+		Expect(claim(ctx, c, claimed, "odlm-b", now.Add(ClaimDuration/2))).ShouldNot(Succeed())
+		Expect(claim(ctx, c, claimed, "odlm-a", now.Add(ClaimDuration/2))).Should(Succeed())
+
+		// The claim expires without renewal
+		Expect(c.Get(ctx, types.NamespacedName{Name: "shard-0"}, claimed)).Should(Succeed())
+		Expect(claim(ctx, c, claimed, "odlm-b", now.Add(2*ClaimDuration))).Should(Succeed())
+
+		// The renewer of the previous holder stops
+		Set("shard-0")
+		renewer := NewRenewer(c, c, "odlm-a")
+		Expect(renewer.renew(ctx, "shard-0", now.Add(2*ClaimDuration))).ShouldNot(Succeed())
+	})
+})
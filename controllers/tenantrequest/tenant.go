@@ -0,0 +1,120 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package tenantrequest
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+)
+
+// tenantRequest is the schema of a request in a tenant ConfigMap. It is a subset of the Request of the
+// OperandRequest, the tenants choose the operands of a registry but not the custom resources created for them.
+type tenantRequest struct {
+	Registry          string          `json:"registry"`
+	RegistryNamespace string          `json:"registryNamespace,omitempty"`
+	Description       string          `json:"description,omitempty"`
+	Operands          []tenantOperand `json:"operands"`
+}
+
+// tenantOperand is the schema of an operand in a tenant ConfigMap
+type tenantOperand struct {
+	Name     string                                 `json:"name"`
+	Bindings map[string]apiv1alpha1.SecretConfigmap `json:"bindings,omitempty"`
+}
+
+// parseTenantRequest converts the data of a tenant ConfigMap into the spec of an OperandRequest.
+// The unknown fields are rejected, so a tenant can't set the kind or the spec of the operands.
+func parseTenantRequest(cm *corev1.ConfigMap) (*apiv1alpha1.OperandRequestSpec, error) {
+	fldPath := field.NewPath("data")
+	var allErrs field.ErrorList
+	for key := range cm.Data {
+		if key != constant.TenantRequestDataKey {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Key(key), key, []string{constant.TenantRequestDataKey}))
+		}
+	}
+	if len(cm.BinaryData) != 0 {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("binaryData"), "the requests must be in the data"))
+	}
+
+	data, ok := cm.Data[constant.TenantRequestDataKey]
+	if !ok {
+		allErrs = append(allErrs, field.Required(fldPath.Key(constant.TenantRequestDataKey), "the requests of the tenant must be set"))
+		return nil, allErrs.ToAggregate()
+	}
+
+	var requests []tenantRequest
+	if err := yaml.UnmarshalStrict([]byte(data), &requests); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Key(constant.TenantRequestDataKey), "", err.Error()))
+		return nil, allErrs.ToAggregate()
+	}
+	if len(requests) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Key(constant.TenantRequestDataKey), "at least one request must be set"))
+	}
+
+	spec := &apiv1alpha1.OperandRequestSpec{}
+	for i, req := range requests {
+		reqPath := fldPath.Key(constant.TenantRequestDataKey).Index(i)
+		request := apiv1alpha1.Request{
+			Registry:          strings.TrimSpace(req.Registry),
+			RegistryNamespace: strings.TrimSpace(req.RegistryNamespace),
+			Description:       req.Description,
+		}
+		if request.Registry == "" {
+			allErrs = append(allErrs, field.Required(reqPath.Child("registry"), ""))
+		}
+		// Default the registry namespace as the OperandRequest does, so the converted spec is stable
+		if request.RegistryNamespace == "" {
+			request.RegistryNamespace = cm.Namespace
+		}
+		if len(req.Operands) == 0 {
+			allErrs = append(allErrs, field.Required(reqPath.Child("operands"), "at least one operand must be set"))
+		}
+		names := make(map[string]bool)
+		for j, operand := range req.Operands {
+			name := strings.TrimSpace(operand.Name)
+			namePath := reqPath.Child("operands").Index(j).Child("name")
+			if name == "" {
+				allErrs = append(allErrs, field.Required(namePath, ""))
+				continue
+			}
+			if names[name] {
+				allErrs = append(allErrs, field.Duplicate(namePath, name))
+				continue
+			}
+			names[name] = true
+			request.Operands = append(request.Operands, apiv1alpha1.Operand{Name: name, Bindings: operand.Bindings})
+		}
+		spec.Requests = append(spec.Requests, request)
+	}
+
+	if len(allErrs) != 0 {
+		return nil, allErrs.ToAggregate()
+	}
+	return spec, nil
+}
+
+// conflictError is returned when the OperandRequest of a tenant ConfigMap isn't created for it
+func conflictError(cm *corev1.ConfigMap) error {
+	return errors.Errorf("the OperandRequest %s/%s already exists and isn't managed by the ConfigMap", cm.Namespace, cm.Name)
+}
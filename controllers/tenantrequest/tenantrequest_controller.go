@@ -0,0 +1,189 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package tenantrequest
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/k8sutil"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/shard"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/throughput"
+)
+
+// PhaseInvalid is the phase reported in a tenant ConfigMap which can't be converted into an OperandRequest
+const PhaseInvalid = "Invalid"
+
+// Reconciler converts the tenant ConfigMaps into OperandRequests, for the tenants allowed to create
+// ConfigMaps but not the ODLM custom resources
+type Reconciler struct {
+	*deploy.ODLMOperator
+}
+
+// Reconcile creates or updates the OperandRequest of a tenant ConfigMap, with the same name and namespace.
+// The OperandRequest is owned by the ConfigMap, it is deleted with it by the garbage collector.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, log := logging.Reconcile(ctx, "tenantrequest", "configmap", req.NamespacedName.String())
+
+	// The ConfigMaps of the manager cache are filtered by the OperandBindInfo labels
+	cm := &corev1.ConfigMap{}
+	if err := r.Reader.Get(ctx, req.NamespacedName, cm); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !cm.DeletionTimestamp.IsZero() || cm.Labels[constant.TenantRequestLabel] != "true" {
+		return ctrl.Result{}, nil
+	}
+
+	excluded, err := r.IsNamespaceExcluded(ctx, cm.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if excluded {
+		log.Info("Skip the tenant ConfigMap in the excluded namespace", "label", constant.ExcludeNamespaceLabel)
+		return ctrl.Result{}, nil
+	}
+
+	log.V(logging.LevelFlow).Info("Reconciling tenant ConfigMap")
+
+	spec, err := parseTenantRequest(cm)
+	if err != nil {
+		log.Info("The tenant ConfigMap is invalid", "reason", err.Error())
+		r.Recorder.Eventf(cm, corev1.EventTypeWarning, constant.EventReasonTenantRequestInvalid, "The ConfigMap can't be converted into an OperandRequest: %v", err)
+		return ctrl.Result{}, r.reportStatus(ctx, cm, PhaseInvalid, err.Error())
+	}
+
+	request := &operatorv1alpha1.OperandRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: cm.Name, Namespace: cm.Namespace},
+	}
+	err = r.Client.Get(ctx, client.ObjectKeyFromObject(request), request)
+	if client.IgnoreNotFound(err) != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to get OperandRequest %s", req.NamespacedName.String())
+	}
+	if err == nil && !metav1.IsControlledBy(request, cm) {
+		err := conflictError(cm)
+		r.Recorder.Eventf(cm, corev1.EventTypeWarning, constant.EventReasonTenantRequestInvalid, "The ConfigMap can't be converted into an OperandRequest: %v", err)
+		return ctrl.Result{}, r.reportStatus(ctx, cm, PhaseInvalid, err.Error())
+	}
+
+	result, err := controllerutil.CreateOrPatch(ctx, r.Client, request, func() error {
+		if request.Labels == nil {
+			request.Labels = make(map[string]string)
+		}
+		request.Labels[constant.TenantRequestLabel] = "true"
+		request.Spec.Requests = spec.Requests
+		return controllerutil.SetControllerReference(cm, request, r.Scheme)
+	})
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to apply the OperandRequest of tenant ConfigMap %s", req.NamespacedName.String())
+	}
+	if result != controllerutil.OperationResultNone {
+		log.Info("Converted the tenant ConfigMap into an OperandRequest", "operation", result)
+	}
+
+	log.V(logging.LevelFlow).Info("Finished reconciling tenant ConfigMap")
+	return ctrl.Result{}, r.reportStatus(ctx, cm, string(request.Status.Phase), "")
+}
+
+// reportStatus records the phase of the OperandRequest and the error of the conversion in the annotations of
+// the tenant ConfigMap, the tenants may not be allowed to read the OperandRequest
+func (r *Reconciler) reportStatus(ctx context.Context, cm *corev1.ConfigMap, phase, message string) error {
+	annotations := cm.GetAnnotations()
+	if annotations[constant.TenantRequestPhaseAnnotation] == phase && annotations[constant.TenantRequestMessageAnnotation] == message {
+		return nil
+	}
+
+	original := cm.DeepCopy()
+	if cm.Annotations == nil {
+		cm.Annotations = make(map[string]string)
+	}
+	for key, value := range map[string]string{constant.TenantRequestPhaseAnnotation: phase, constant.TenantRequestMessageAnnotation: message} {
+		if value == "" {
+			delete(cm.Annotations, key)
+		} else {
+			cm.Annotations[key] = value
+		}
+	}
+	if err := r.Client.Patch(ctx, cm, client.MergeFrom(original)); err != nil {
+		return errors.Wrapf(err, "failed to report the status of tenant ConfigMap %s/%s", cm.Namespace, cm.Name)
+	}
+	return nil
+}
+
+// SetupWithManager adds the tenant request controller to the manager.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// The ConfigMaps of the manager cache are filtered by the OperandBindInfo labels,
+	// the tenant ConfigMaps are watched with a cache of their own
+	opts := cache.Options{
+		Scheme: mgr.GetScheme(),
+		Mapper: mgr.GetRESTMapper(),
+		SelectorsByObject: cache.SelectorsByObject{
+			&corev1.ConfigMap{}: {Label: labels.SelectorFromSet(labels.Set{constant.TenantRequestLabel: "true"})},
+		},
+	}
+	newCache := cache.New
+	if watchNamespace := util.GetWatchNamespace(); watchNamespace != "" {
+		namespaceSelector, err := k8sutil.NamespaceSelector(util.GetWatchNamespaceSelector())
+		if err != nil {
+			return errors.Wrap(err, "failed to parse the label selector of the watched namespaces")
+		}
+		if namespaceSelector != nil {
+			newCache = k8sutil.DynamicNamespacedCacheBuilder(cache.New, strings.Split(watchNamespace, ","), namespaceSelector)
+		} else {
+			newCache = cache.MultiNamespacedCacheBuilder(strings.Split(watchNamespace, ","))
+		}
+	}
+	tenantCache, err := newCache(mgr.GetConfig(), opts)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the cache of the tenant ConfigMaps")
+	}
+	if err := mgr.Add(tenantCache); err != nil {
+		return err
+	}
+
+	// The OperandRequest of a tenant ConfigMap has the same name and namespace as the ConfigMap
+	tenantPredicate := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetLabels()[constant.TenantRequestLabel] == "true"
+	})
+
+	fair := throughput.NewFairQueue("tenantrequest")
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("tenantrequest").
+		WithOptions(throughput.ControllerOptions("tenantrequest")).
+		For(&operatorv1alpha1.OperandRequest{}, builder.WithPredicates(tenantPredicate)).
+		Watches(source.NewKindWithCache(&corev1.ConfigMap{}, tenantCache), &handler.EnqueueRequestForObject{}).
+		// The requests are dispatched in turn across the namespaces by the fair queue
+		Watches(fair.Source(), &handler.EnqueueRequestForObject{}).
+		Complete(fair.Reconciler(shard.Reconciler(r)))
+}
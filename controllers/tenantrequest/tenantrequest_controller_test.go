@@ -0,0 +1,173 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package tenantrequest
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+)
+
+var _ = Describe("Tenant request", func() {
+
+	const namespace = "tenant-a"
+
+	var (
+		ctx        = context.TODO()
+		reconciler *Reconciler
+		fakeClient client.Client
+		key        = types.NamespacedName{Name: "tenant-services", Namespace: namespace}
+	)
+
+	newConfigMap := func(data string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				UID:       "tenant-services-uid",
+				Labels:    map[string]string{constant.TenantRequestLabel: "true"},
+			},
+			Data: map[string]string{constant.TenantRequestDataKey: data},
+		}
+	}
+
+	setup := func(objs ...client.Object) {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+		utilruntime.Must(operatorv1alpha1.AddToScheme(scheme))
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+		reconciler = &Reconciler{
+			ODLMOperator: &deploy.ODLMOperator{
+				Client:   fakeClient,
+				Reader:   fakeClient,
+				Recorder: record.NewFakeRecorder(10),
+				Scheme:   scheme,
+			},
+		}
+	}
+
+	reconcile := func() {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("Should convert the ConfigMap into an OperandRequest owned by it", func() {
+		setup(newConfigMap(`
+- registry: common-service
+  registryNamespace: ibm-common-services
+  operands:
+  - name: ibm-iam-operator
+  - name: " ibm-licensing-operator "
+    bindings:
+      public:
+        secret: licensing-token
+- registry: tenant-registry
+  operands:
+  - name: tenant-operator
+`))
+		reconcile()
+
+		request := &operatorv1alpha1.OperandRequest{}
+		Expect(fakeClient.Get(ctx, key, request)).To(Succeed())
+		Expect(request.Labels).To(HaveKeyWithValue(constant.TenantRequestLabel, "true"))
+		Expect(metav1.GetControllerOf(request).Name).To(Equal(key.Name))
+		Expect(request.Spec.Requests).To(Equal([]operatorv1alpha1.Request{
+			{
+				Registry:          "common-service",
+				RegistryNamespace: "ibm-common-services",
+				Operands: []operatorv1alpha1.Operand{
+					{Name: "ibm-iam-operator"},
+					{Name: "ibm-licensing-operator", Bindings: map[string]operatorv1alpha1.SecretConfigmap{"public": {Secret: "licensing-token"}}},
+				},
+			},
+			{
+				Registry:          "tenant-registry",
+				RegistryNamespace: namespace,
+				Operands:          []operatorv1alpha1.Operand{{Name: "tenant-operator"}},
+			},
+		}))
+	})
+
+	It("Should report the ConfigMap with unknown fields as invalid", func() {
+		setup(newConfigMap(`
+- registry: common-service
+  operands:
+  - name: ibm-iam-operator
+    kind: Authentication
+    spec:
+      replicas: 3
+`))
+		reconcile()
+
+		Expect(fakeClient.Get(ctx, key, &operatorv1alpha1.OperandRequest{})).NotTo(Succeed())
+		cm := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(ctx, key, cm)).To(Succeed())
+		Expect(cm.Annotations).To(HaveKeyWithValue(constant.TenantRequestPhaseAnnotation, PhaseInvalid))
+		Expect(cm.Annotations[constant.TenantRequestMessageAnnotation]).To(ContainSubstring(`unknown field "kind"`))
+	})
+
+	It("Should not take over an OperandRequest it didn't create", func() {
+		setup(newConfigMap(`
+- registry: common-service
+  operands:
+  - name: ibm-iam-operator
+`), &operatorv1alpha1.OperandRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec:       operatorv1alpha1.OperandRequestSpec{Requests: []operatorv1alpha1.Request{{Registry: "other"}}},
+		})
+		reconcile()
+
+		request := &operatorv1alpha1.OperandRequest{}
+		Expect(fakeClient.Get(ctx, key, request)).To(Succeed())
+		Expect(request.Spec.Requests[0].Registry).To(Equal("other"))
+		cm := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(ctx, key, cm)).To(Succeed())
+		Expect(cm.Annotations).To(HaveKeyWithValue(constant.TenantRequestPhaseAnnotation, PhaseInvalid))
+	})
+})
+
+var _ = Describe("Parsing the tenant ConfigMap", func() {
+
+	It("Should reject the missing requests, the other keys and the duplicate operands", func() {
+		_, err := parseTenantRequest(&corev1.ConfigMap{Data: map[string]string{"extra": ""}})
+		Expect(err).To(MatchError(ContainSubstring("data[requests]: Required value")))
+		Expect(err).To(MatchError(ContainSubstring("data[extra]: Unsupported value")))
+
+		_, err = parseTenantRequest(&corev1.ConfigMap{Data: map[string]string{constant.TenantRequestDataKey: `
+- operands:
+  - name: ibm-iam-operator
+  - name: ibm-iam-operator
+`}})
+		Expect(err).To(MatchError(ContainSubstring("data[requests][0].registry: Required value")))
+		Expect(err).To(MatchError(ContainSubstring("data[requests][0].operands[1].name: Duplicate value")))
+	})
+})
@@ -320,6 +320,20 @@ func OperandBindInfoObj(name, namespace, registryName, registryNamespace string)
 	}
 }
 
+// Return OperandSnapshot obj
+func OperandSnapshotObj(name, namespace string, operation apiv1alpha1.SnapshotOperation, namespaces []string) *apiv1alpha1.OperandSnapshot {
+	return &apiv1alpha1.OperandSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: apiv1alpha1.OperandSnapshotSpec{
+			Operation:  operation,
+			Namespaces: namespaces,
+		},
+	}
+}
+
 func NamespaceScopeObj(namespace string) *nssv1.NamespaceScope {
 	return &nssv1.NamespaceScope{
 		ObjectMeta: metav1.ObjectMeta{
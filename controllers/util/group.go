@@ -0,0 +1,92 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Group runs the functions of the members in parallel, at most limit at a time, and aggregates their errors
+// in the order the functions are added, so a failed member doesn't stop the others
+type Group struct {
+	group errgroup.Group
+	mu    sync.Mutex
+	errs  []error
+}
+
+// NewGroup returns a group running at most limit functions at a time, with no limit when it isn't positive
+func NewGroup(limit int) *Group {
+	g := &Group{}
+	if limit > 0 {
+		g.group.SetLimit(limit)
+	}
+	return g
+}
+
+// Go runs the function in a new goroutine, it blocks while limit functions are running
+func (g *Group) Go(f func() error) {
+	g.mu.Lock()
+	i := len(g.errs)
+	g.errs = append(g.errs, nil)
+	g.mu.Unlock()
+	g.group.Go(func() error {
+		err := f()
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.errs[i] = err
+		return nil
+	})
+}
+
+// Wait waits for the functions until the timeout, with no timeout when it isn't positive.
+// It returns the errors of the finished functions, and true if waiting timed out.
+func (g *Group) Wait(timeout time.Duration) (*MultiErr, bool) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = g.group.Wait()
+	}()
+	timedOut := false
+	if timeout > 0 {
+		select {
+		case <-done:
+		case <-time.After(timeout):
+			timedOut = true
+		}
+	} else {
+		<-done
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	merr := &MultiErr{}
+	for _, err := range g.errs {
+		if err == nil {
+			continue
+		}
+		// The errors of a member are kept as they are rather than nested
+		if memberErr, ok := err.(*MultiErr); ok {
+			merr.Errors = append(merr.Errors, memberErr.Errors...)
+			continue
+		}
+		merr.Add(err)
+	}
+	return merr, timedOut
+}
@@ -0,0 +1,75 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Group of the members", func() {
+
+	It("Should run at most the limit of functions at a time and aggregate their errors in order", func() {
+		g := NewGroup(2)
+		var running, maxRunning int32
+		for i := 0; i < 6; i++ {
+			i := i
+			g.Go(func() error {
+				n := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				for {
+					m := atomic.LoadInt32(&maxRunning)
+					if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				switch i {
+				case 1:
+					return errors.New("failed to reconcile etcd")
+				case 4:
+					return &MultiErr{Errors: []string{"failed to reconcile jenkins", "failed to reconcile the Jenkins CR"}}
+				}
+				return nil
+			})
+		}
+		merr, timedOut := g.Wait(0)
+		Expect(timedOut).Should(BeFalse())
+		Expect(maxRunning).Should(Equal(int32(2)))
+		Expect(merr.Errors).Should(Equal([]string{"failed to reconcile etcd", "failed to reconcile jenkins", "failed to reconcile the Jenkins CR"}))
+	})
+
+	It("Should stop waiting for the functions after the timeout", func() {
+		g := NewGroup(0)
+		release := make(chan struct{})
+		defer close(release)
+		g.Go(func() error {
+			return errors.New("failed to delete etcd")
+		})
+		g.Go(func() error {
+			<-release
+			return nil
+		})
+		merr, timedOut := g.Wait(50 * time.Millisecond)
+		Expect(timedOut).Should(BeTrue())
+		Expect(merr.Errors).Should(Equal([]string{"failed to delete etcd"}))
+	})
+})
@@ -0,0 +1,57 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SizeExceededError is returned when an object is larger than the allowed size budget
+type SizeExceededError struct {
+	Name  string
+	Size  int
+	Limit int
+}
+
+// Error is the error message
+func (e *SizeExceededError) Error() string {
+	return fmt.Sprintf("%s is %d bytes, which exceeds the size limit of %d bytes", e.Name, e.Size, e.Limit)
+}
+
+// ObjectSize returns the size in bytes of the JSON encoding of the object
+func ObjectSize(obj interface{}) (int, error) {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return 0, err
+	}
+	return len(raw), nil
+}
+
+// CheckSizeBudget checks the JSON size of the object against the warning threshold and the hard limit.
+// It returns the size, whether the warning threshold is exceeded,
+// and a SizeExceededError if the hard limit is exceeded.
+func CheckSizeBudget(name string, obj interface{}, warningThreshold, limit int) (size int, warning bool, err error) {
+	size, err = ObjectSize(obj)
+	if err != nil {
+		return 0, false, err
+	}
+	if limit > 0 && size > limit {
+		return size, true, &SizeExceededError{Name: name, Size: size, Limit: limit}
+	}
+	return size, warningThreshold > 0 && size > warningThreshold, nil
+}
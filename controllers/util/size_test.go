@@ -0,0 +1,49 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Size budget", func() {
+
+	Context("Check the size of an object", func() {
+		It("Should pass when the object is under the warning threshold", func() {
+			size, warning, err := CheckSizeBudget("small", map[string]string{"key": "value"}, 100, 200)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warning).Should(BeFalse())
+			Expect(size).Should(Equal(len(`{"key":"value"}`)))
+		})
+
+		It("Should warn when the object is over the warning threshold", func() {
+			_, warning, err := CheckSizeBudget("medium", map[string]string{"key": strings.Repeat("v", 150)}, 100, 200)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warning).Should(BeTrue())
+		})
+
+		It("Should fail when the object is over the limit", func() {
+			_, _, err := CheckSizeBudget("large", map[string]string{"key": strings.Repeat("v", 250)}, 100, 200)
+			Expect(err).To(HaveOccurred())
+			_, ok := err.(*SizeExceededError)
+			Expect(ok).Should(BeTrue())
+		})
+	})
+})
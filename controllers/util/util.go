@@ -22,9 +22,8 @@ import (
 	"strconv"
 	"strings"
 	"sync"
-	"time"
 
-	"k8s.io/client-go/discovery"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
 )
 
 // GetOperatorNamespace returns the Namespace of the operator
@@ -36,8 +35,23 @@ func GetOperatorNamespace() string {
 	return ns
 }
 
-// GetWatchNamespace returns the Namespace of the operator
+// GetPodName returns the name of the operator Pod
+func GetPodName() string {
+	return os.Getenv("POD_NAME")
+}
+
+// GetWatchNamespace returns the namespaces watched by ODLM, separated by commas
 func GetWatchNamespace() string {
+	runtimeSettings.RLock()
+	defer runtimeSettings.RUnlock()
+	if runtimeSettings.watchNamespace != nil {
+		return *runtimeSettings.watchNamespace
+	}
+	return GetWatchNamespaceFromEnv()
+}
+
+// GetWatchNamespaceFromEnv returns the namespaces of the WATCH_NAMESPACE env, the namespace of the operator when it is unset
+func GetWatchNamespaceFromEnv() string {
 	ns, found := os.LookupEnv("WATCH_NAMESPACE")
 	if !found {
 		return GetOperatorNamespace()
@@ -45,8 +59,69 @@ func GetWatchNamespace() string {
 	return ns
 }
 
+// GetWatchNamespaceSelector returns the label selector of the namespaces watched by ODLM
+// in addition to the watched namespaces, it is empty when no namespace is watched for its labels
+func GetWatchNamespaceSelector() string {
+	runtimeSettings.RLock()
+	defer runtimeSettings.RUnlock()
+	if runtimeSettings.watchNamespaceSelector != nil {
+		return *runtimeSettings.watchNamespaceSelector
+	}
+	return GetWatchNamespaceSelectorFromEnv()
+}
+
+// GetWatchNamespaceSelectorFromEnv returns the label selector of the WATCH_NAMESPACE_SELECTOR env
+func GetWatchNamespaceSelectorFromEnv() string {
+	return os.Getenv("WATCH_NAMESPACE_SELECTOR")
+}
+
+// GetOperatorImage returns the image of the operator, it is used to unpack the bundle images
+func GetOperatorImage() string {
+	image, found := os.LookupEnv("OPERATOR_IMAGE")
+	if !found {
+		return "icr.io/cpopen/odlm:latest"
+	}
+	return image
+}
+
+// GetHelmImage returns the image running the Helm CLI for the Helm releases
+func GetHelmImage() string {
+	image, found := os.LookupEnv("HELM_IMAGE")
+	if !found {
+		return "docker.io/alpine/helm:3.9.4"
+	}
+	return image
+}
+
+// runtimeSettings holds the settings applied from the OperatorConfig,
+// they take precedence over the environment variables
+var runtimeSettings struct {
+	sync.RWMutex
+	installScope            *string
+	isolatedMode            *bool
+	watchNamespace          *string
+	watchNamespaceSelector  *string
+	operatorCheckerDisabled *bool
+	forensicBundle          *bool
+	forensicBundleDir       *string
+	maxParallelInstalls     *int
+	catalogInstallLimits    map[string]int
+	recreatePolicy          *string
+	repairOperatorGroups    *bool
+}
+
 // GetInstallScope returns the scope of the installation
 func GetInstallScope() string {
+	runtimeSettings.RLock()
+	defer runtimeSettings.RUnlock()
+	if runtimeSettings.installScope != nil {
+		return *runtimeSettings.installScope
+	}
+	return GetInstallScopeFromEnv()
+}
+
+// GetInstallScopeFromEnv returns the scope of the installation from the INSTALL_SCOPE env
+func GetInstallScopeFromEnv() string {
 	ns, found := os.LookupEnv("INSTALL_SCOPE")
 	if !found {
 		return "cluster"
@@ -54,7 +129,25 @@ func GetInstallScope() string {
 	return ns
 }
 
+// SetInstallScope sets the scope of the installation at runtime
+func SetInstallScope(scope string) {
+	runtimeSettings.Lock()
+	defer runtimeSettings.Unlock()
+	runtimeSettings.installScope = &scope
+}
+
+// GetIsolatedMode returns true if ODLM is limited to the watched namespaces
 func GetIsolatedMode() bool {
+	runtimeSettings.RLock()
+	defer runtimeSettings.RUnlock()
+	if runtimeSettings.isolatedMode != nil {
+		return *runtimeSettings.isolatedMode
+	}
+	return GetIsolatedModeFromEnv()
+}
+
+// GetIsolatedModeFromEnv returns the isolated mode from the ISOLATED_MODE env
+func GetIsolatedModeFromEnv() bool {
 	isEnable, found := os.LookupEnv("ISOLATED_MODE")
 	if !found || isEnable != "true" {
 		return false
@@ -62,7 +155,39 @@ func GetIsolatedMode() bool {
 	return true
 }
 
+// SetIsolatedMode sets the isolated mode, it is only set when ODLM starts
+func SetIsolatedMode(isolated bool) {
+	runtimeSettings.Lock()
+	defer runtimeSettings.Unlock()
+	runtimeSettings.isolatedMode = &isolated
+}
+
+// SetWatchNamespace sets the namespaces watched by ODLM, it is only set when ODLM starts
+func SetWatchNamespace(namespaces string) {
+	runtimeSettings.Lock()
+	defer runtimeSettings.Unlock()
+	runtimeSettings.watchNamespace = &namespaces
+}
+
+// SetWatchNamespaceSelector sets the label selector of the watched namespaces, it is only set when ODLM starts
+func SetWatchNamespaceSelector(selector string) {
+	runtimeSettings.Lock()
+	defer runtimeSettings.Unlock()
+	runtimeSettings.watchNamespaceSelector = &selector
+}
+
+// GetoperatorCheckerMode returns true if the operator checker is disabled
 func GetoperatorCheckerMode() bool {
+	runtimeSettings.RLock()
+	defer runtimeSettings.RUnlock()
+	if runtimeSettings.operatorCheckerDisabled != nil {
+		return *runtimeSettings.operatorCheckerDisabled
+	}
+	return GetoperatorCheckerModeFromEnv()
+}
+
+// GetoperatorCheckerModeFromEnv returns true if the operator checker is disabled by the OPERATORCHECKER_MODE env
+func GetoperatorCheckerModeFromEnv() bool {
 	isEnable, found := os.LookupEnv("OPERATORCHECKER_MODE")
 	if found && isEnable == "false" {
 		return true
@@ -70,114 +195,163 @@ func GetoperatorCheckerMode() bool {
 	return false
 }
 
-// ResourceExists returns true if the given resource kind exists
-// in the given api groupversion
-func ResourceExists(dc discovery.DiscoveryInterface, apiGroupVersion, kind string) (bool, error) {
-	_, apiLists, err := dc.ServerGroupsAndResources()
-	if err != nil {
-		return false, err
-	}
-	for _, apiList := range apiLists {
-		if apiList.GroupVersion == apiGroupVersion {
-			for _, r := range apiList.APIResources {
-				if r.Kind == kind {
-					return true, nil
-				}
-			}
-		}
-	}
-	return false, nil
+// SetoperatorCheckerMode disables or enables the operator checker at runtime
+func SetoperatorCheckerMode(disabled bool) {
+	runtimeSettings.Lock()
+	defer runtimeSettings.Unlock()
+	runtimeSettings.operatorCheckerDisabled = &disabled
 }
 
-//StringSliceContentEqual checks if the contant from two string slice are the same
-func StringSliceContentEqual(a, b []string) bool {
-	if len(a) != len(b) {
-		return false
+// GetForensicBundle returns true if the forensic bundles are collected for the failed operands
+func GetForensicBundle() bool {
+	runtimeSettings.RLock()
+	defer runtimeSettings.RUnlock()
+	if runtimeSettings.forensicBundle != nil {
+		return *runtimeSettings.forensicBundle
 	}
-	sort.Strings(a)
-	sort.Strings(b)
-	for i, v := range a {
-		if v != b[i] {
-			return false
-		}
+	return GetForensicBundleFromEnv()
+}
+
+// GetForensicBundleFromEnv returns true if the forensic bundles are enabled by the FORENSIC_BUNDLE env
+func GetForensicBundleFromEnv() bool {
+	isEnable, found := os.LookupEnv("FORENSIC_BUNDLE")
+	return found && isEnable == "true"
+}
+
+// GetForensicBundleDir returns the directory the forensic bundles are written into,
+// they are stored in ConfigMaps if it is empty
+func GetForensicBundleDir() string {
+	runtimeSettings.RLock()
+	defer runtimeSettings.RUnlock()
+	if runtimeSettings.forensicBundleDir != nil {
+		return *runtimeSettings.forensicBundleDir
 	}
-	return true
+	return GetForensicBundleDirFromEnv()
+}
+
+// GetForensicBundleDirFromEnv returns the directory of the forensic bundles from the FORENSIC_BUNDLE_DIR env
+func GetForensicBundleDirFromEnv() string {
+	return os.Getenv("FORENSIC_BUNDLE_DIR")
+}
+
+// SetForensicBundle enables or disables the forensic bundles at runtime
+func SetForensicBundle(enabled bool, dir string) {
+	runtimeSettings.Lock()
+	defer runtimeSettings.Unlock()
+	runtimeSettings.forensicBundle = &enabled
+	runtimeSettings.forensicBundleDir = &dir
 }
 
-// WaitTimeout waits for the waitgroup for the specified max timeout.
-// Returns true if waiting timed out.
-func WaitTimeout(wg *sync.WaitGroup, timeout time.Duration) bool {
-	c := make(chan struct{})
-	go func() {
-		defer close(c)
-		wg.Wait()
-	}()
-	select {
-	case <-c:
-		return false // completed normally
-	case <-time.After(timeout):
-		return true // timed out
+// GetMaxParallelInstalls returns how many Subscriptions are installed in parallel from the CatalogSource,
+// 0 means unlimited
+func GetMaxParallelInstalls(sourceName, sourceNamespace string) int {
+	runtimeSettings.RLock()
+	defer runtimeSettings.RUnlock()
+	if limit, ok := runtimeSettings.catalogInstallLimits[sourceNamespace+"/"+sourceName]; ok {
+		return limit
 	}
+	if runtimeSettings.maxParallelInstalls != nil {
+		return *runtimeSettings.maxParallelInstalls
+	}
+	return GetMaxParallelInstallsFromEnv()
 }
 
-// ResourceNamespaced returns true if the given resource is namespaced
-func ResourceNamespaced(dc discovery.DiscoveryInterface, apiGroupVersion, kind string) (bool, error) {
-	_, apiLists, err := dc.ServerGroupsAndResources()
-	if err != nil {
-		return false, err
-	}
-	for _, apiList := range apiLists {
-		if apiList.GroupVersion == apiGroupVersion {
-			for _, r := range apiList.APIResources {
-				if r.Kind == kind {
-					return r.Namespaced, nil
-				}
-			}
-		}
+// GetMaxParallelInstallsFromEnv returns the limit of the parallel installs per CatalogSource
+// from the MAX_PARALLEL_INSTALLS_PER_CATALOG env, 0 means unlimited
+func GetMaxParallelInstallsFromEnv() int {
+	limit, err := strconv.Atoi(os.Getenv("MAX_PARALLEL_INSTALLS_PER_CATALOG"))
+	if err != nil || limit < 0 {
+		return 0
 	}
-	return false, nil
+	return limit
 }
 
-func CompareChannelVersion(v1, v2 string) (v1IsLarger bool, err error) {
-	_, v1Cut, isExist := strings.Cut(v1, "v")
-	if !isExist {
-		v1Cut = "0.0"
+// GetInstallThrottle returns the limit of the parallel installs of every CatalogSource
+// and the limits of the CatalogSources keyed by their namespace/name
+func GetInstallThrottle() (int, map[string]int) {
+	runtimeSettings.RLock()
+	defer runtimeSettings.RUnlock()
+	catalogs := make(map[string]int, len(runtimeSettings.catalogInstallLimits))
+	for key, limit := range runtimeSettings.catalogInstallLimits {
+		catalogs[key] = limit
 	}
-	v1Slice := strings.Split(v1Cut, ".")
-	if len(v1Slice) == 1 {
-		v1Cut = v1Cut + ".0"
+	if runtimeSettings.maxParallelInstalls != nil {
+		return *runtimeSettings.maxParallelInstalls, catalogs
 	}
+	return GetMaxParallelInstallsFromEnv(), catalogs
+}
+
+// SetInstallThrottle sets the limit of the parallel installs per CatalogSource at runtime,
+// the catalogs are keyed by the namespace/name of the CatalogSource
+func SetInstallThrottle(maxParallelInstalls int, catalogs map[string]int) {
+	runtimeSettings.Lock()
+	defer runtimeSettings.Unlock()
+	runtimeSettings.maxParallelInstalls = &maxParallelInstalls
+	runtimeSettings.catalogInstallLimits = catalogs
+}
 
-	_, v2Cut, isExist := strings.Cut(v2, "v")
-	if !isExist {
-		v1Cut = "0.0"
+// GetRecreatePolicy returns when the objects created by ODLM and deleted by someone else are recreated
+func GetRecreatePolicy() string {
+	runtimeSettings.RLock()
+	defer runtimeSettings.RUnlock()
+	if runtimeSettings.recreatePolicy != nil {
+		return *runtimeSettings.recreatePolicy
 	}
-	v2Slice := strings.Split(v2Cut, ".")
-	if len(v2Slice) == 1 {
-		v2Cut = v2Cut + ".0"
+	return GetRecreatePolicyFromEnv()
+}
+
+// GetRecreatePolicyFromEnv returns the recreate policy from the RECREATE_POLICY env
+func GetRecreatePolicyFromEnv() string {
+	policy, found := os.LookupEnv("RECREATE_POLICY")
+	if !found {
+		return "Immediate"
 	}
+	return policy
+}
 
-	v1Slice = strings.Split(v1Cut, ".")
-	v2Slice = strings.Split(v2Cut, ".")
-	for index := range v1Slice {
-		v1SplitInt, e1 := strconv.Atoi(v1Slice[index])
-		if e1 != nil {
-			return false, e1
-		}
-		v2SplitInt, e2 := strconv.Atoi(v2Slice[index])
-		if e2 != nil {
-			return false, e2
-		}
+// SetRecreatePolicy sets the recreate policy at runtime
+func SetRecreatePolicy(policy string) {
+	runtimeSettings.Lock()
+	defer runtimeSettings.Unlock()
+	runtimeSettings.recreatePolicy = &policy
+}
+
+// GetRepairOperatorGroups returns true if ODLM merges the OperatorGroups of a namespace having several of them
+func GetRepairOperatorGroups() bool {
+	runtimeSettings.RLock()
+	defer runtimeSettings.RUnlock()
+	if runtimeSettings.repairOperatorGroups != nil {
+		return *runtimeSettings.repairOperatorGroups
+	}
+	return GetRepairOperatorGroupsFromEnv()
+}
+
+// GetRepairOperatorGroupsFromEnv returns true if the OperatorGroups are repaired by the REPAIR_OPERATOR_GROUPS env
+func GetRepairOperatorGroupsFromEnv() bool {
+	isEnable, found := os.LookupEnv("REPAIR_OPERATOR_GROUPS")
+	return found && isEnable == "true"
+}
 
-		if v1SplitInt > v2SplitInt {
-			return true, nil
-		} else if v1SplitInt == v2SplitInt {
-			continue
-		} else {
-			return false, nil
+// SetRepairOperatorGroups enables or disables the repair of the OperatorGroups at runtime
+func SetRepairOperatorGroups(repair bool) {
+	runtimeSettings.Lock()
+	defer runtimeSettings.Unlock()
+	runtimeSettings.repairOperatorGroups = &repair
+}
+
+//StringSliceContentEqual checks if the contant from two string slice are the same
+func StringSliceContentEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sort.Strings(a)
+	sort.Strings(b)
+	for i, v := range a {
+		if v != b[i] {
+			return false
 		}
 	}
-	return false, nil
+	return true
 }
 
 func Contains(list []string, s string) bool {
@@ -188,3 +362,12 @@ func Contains(list []string, s string) bool {
 	}
 	return false
 }
+
+// SyncWave returns the Argo CD sync wave of the annotations, 0 when it isn't set or isn't an integer
+func SyncWave(annotations map[string]string) int {
+	wave, err := strconv.Atoi(strings.TrimSpace(annotations[constant.SyncWaveAnnotation]))
+	if err != nil {
+		return 0
+	}
+	return wave
+}
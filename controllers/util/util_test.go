@@ -61,6 +61,89 @@ var _ = Describe("Get environmental variables", func() {
 			Expect(ns).Should(Equal(scope))
 		})
 
+		It("Should override the environmental variables at runtime", func() {
+			err := os.Setenv("INSTALL_SCOPE", "namespaced")
+			Expect(err).NotTo(HaveOccurred())
+			err = os.Setenv("OPERATORCHECKER_MODE", "false")
+			Expect(err).NotTo(HaveOccurred())
+			err = os.Setenv("WATCH_NAMESPACE", "system,cloudpak1")
+			Expect(err).NotTo(HaveOccurred())
+			err = os.Setenv("WATCH_NAMESPACE_SELECTOR", "tenant=true")
+			Expect(err).NotTo(HaveOccurred())
+			defer func() {
+				os.Unsetenv("WATCH_NAMESPACE_SELECTOR")
+				runtimeSettings.installScope = nil
+				runtimeSettings.operatorCheckerDisabled = nil
+				runtimeSettings.watchNamespace = nil
+				runtimeSettings.watchNamespaceSelector = nil
+			}()
+
+			Expect(GetoperatorCheckerMode()).Should(BeTrue())
+			SetInstallScope("cluster")
+			SetoperatorCheckerMode(false)
+			SetWatchNamespace("")
+			SetWatchNamespaceSelector("tenant in (gold,silver)")
+			Expect(GetInstallScope()).Should(Equal("cluster"))
+			Expect(GetInstallScopeFromEnv()).Should(Equal("namespaced"))
+			Expect(GetoperatorCheckerMode()).Should(BeFalse())
+			Expect(GetWatchNamespace()).Should(BeEmpty())
+			Expect(GetWatchNamespaceFromEnv()).Should(Equal("system,cloudpak1"))
+			Expect(GetWatchNamespaceSelector()).Should(Equal("tenant in (gold,silver)"))
+			Expect(GetWatchNamespaceSelectorFromEnv()).Should(Equal("tenant=true"))
+		})
+
+		It("Should override the forensic bundle settings at runtime", func() {
+			err := os.Setenv("FORENSIC_BUNDLE", "true")
+			Expect(err).NotTo(HaveOccurred())
+			err = os.Setenv("FORENSIC_BUNDLE_DIR", "/var/odlm/forensics")
+			Expect(err).NotTo(HaveOccurred())
+			defer func() {
+				os.Unsetenv("FORENSIC_BUNDLE")
+				os.Unsetenv("FORENSIC_BUNDLE_DIR")
+				runtimeSettings.forensicBundle = nil
+				runtimeSettings.forensicBundleDir = nil
+			}()
+
+			Expect(GetForensicBundle()).Should(BeTrue())
+			Expect(GetForensicBundleDir()).Should(Equal("/var/odlm/forensics"))
+			SetForensicBundle(true, "")
+			Expect(GetForensicBundle()).Should(BeTrue())
+			Expect(GetForensicBundleDir()).Should(BeEmpty())
+			SetForensicBundle(false, "")
+			Expect(GetForensicBundle()).Should(BeFalse())
+		})
+
+		It("Should override the parallel installs per catalog at runtime", func() {
+			err := os.Setenv("MAX_PARALLEL_INSTALLS_PER_CATALOG", "5")
+			Expect(err).NotTo(HaveOccurred())
+			defer func() {
+				os.Unsetenv("MAX_PARALLEL_INSTALLS_PER_CATALOG")
+				runtimeSettings.maxParallelInstalls = nil
+				runtimeSettings.catalogInstallLimits = nil
+			}()
+
+			Expect(GetMaxParallelInstalls("opencloud-operators", "openshift-marketplace")).Should(Equal(5))
+			SetInstallThrottle(3, map[string]int{"openshift-marketplace/opencloud-operators": 1})
+			Expect(GetMaxParallelInstalls("opencloud-operators", "openshift-marketplace")).Should(Equal(1))
+			Expect(GetMaxParallelInstalls("community-operators", "openshift-marketplace")).Should(Equal(3))
+			SetInstallThrottle(0, nil)
+			Expect(GetMaxParallelInstalls("opencloud-operators", "openshift-marketplace")).Should(Equal(0))
+		})
+
+		It("Should override the recreate policy at runtime", func() {
+			Expect(GetRecreatePolicy()).Should(Equal("Immediate"))
+			err := os.Setenv("RECREATE_POLICY", "Resync")
+			Expect(err).NotTo(HaveOccurred())
+			defer func() {
+				os.Unsetenv("RECREATE_POLICY")
+				runtimeSettings.recreatePolicy = nil
+			}()
+
+			Expect(GetRecreatePolicy()).Should(Equal("Resync"))
+			SetRecreatePolicy("Immediate")
+			Expect(GetRecreatePolicy()).Should(Equal("Immediate"))
+		})
+
 		It("Should string slice be equal", func() {
 			a := []string{"apple", "pine", "pineapple"}
 			b := []string{"apple", "pineapple", "pine"}
@@ -0,0 +1,158 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	apiv1beta2 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1beta2"
+)
+
+var _ = Describe("OperandConfig conversion", func() {
+
+	var webhook *conversion.Webhook
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(apiv1alpha1.AddToScheme(scheme))
+		utilruntime.Must(apiv1beta2.AddToScheme(scheme))
+		webhook = &conversion.Webhook{}
+		Expect(webhook.InjectScheme(scheme)).To(Succeed())
+	})
+
+	// convert sends the object to the conversion webhook and returns the converted object
+	convert := func(obj runtime.Object, desiredAPIVersion string, converted runtime.Object) {
+		raw, err := json.Marshal(obj)
+		Expect(err).NotTo(HaveOccurred())
+		review := &apiextensionsv1.ConversionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+			Request: &apiextensionsv1.ConversionRequest{
+				UID:               "conversion",
+				DesiredAPIVersion: desiredAPIVersion,
+				Objects:           []runtime.RawExtension{{Raw: raw}},
+			},
+		}
+		body, err := json.Marshal(review)
+		Expect(err).NotTo(HaveOccurred())
+
+		recorder := httptest.NewRecorder()
+		webhook.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/convert", bytes.NewReader(body)))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		response := &apiextensionsv1.ConversionReview{}
+		Expect(json.Unmarshal(recorder.Body.Bytes(), response)).To(Succeed())
+		Expect(response.Response.Result.Status).To(Equal(metav1.StatusSuccess), response.Response.Result.Message)
+		Expect(response.Response.ConvertedObjects).To(HaveLen(1))
+		Expect(json.Unmarshal(response.Response.ConvertedObjects[0].Raw, converted)).To(Succeed())
+	}
+
+	newConfig := func() *apiv1alpha1.OperandConfig {
+		prune := false
+		return &apiv1alpha1.OperandConfig{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "operator.ibm.com/v1alpha1", Kind: "OperandConfig"},
+			ObjectMeta: metav1.ObjectMeta{Name: "common-service", Namespace: "ibm-common-services"},
+			Spec: apiv1alpha1.OperandConfigSpec{
+				Services: []apiv1alpha1.ConfigService{
+					{
+						Name: "ibm-etcd-operator",
+						Spec: map[string]runtime.RawExtension{
+							"etcdCluster":    {Raw: []byte(`{"size":3}`)},
+							"etcdBackup":     {Raw: []byte(`{"storageType":"S3"}`)},
+							"etcdRestoreJob": {},
+						},
+						Prune:       &prune,
+						Remediation: apiv1alpha1.RemediationDetect,
+						Placement:   &apiv1alpha1.PlacementInjection{Profile: "infra"},
+						SizeProfiles: []apiv1alpha1.SizeProfile{
+							{Name: "small", Spec: map[string]runtime.RawExtension{"etcdCluster": {Raw: []byte(`{"size":1}`)}}},
+							{Name: "large"},
+						},
+						DefaultSizeProfile: "small",
+						Scaling:            &apiv1alpha1.ScalingHints{CustomResources: []apiv1alpha1.ScalingResource{{Kind: "etcdCluster", ReplicasPath: "size"}}},
+					},
+					{
+						Name:  "ibm-mongodb-operator",
+						State: "absent",
+					},
+				},
+			},
+			Status: apiv1alpha1.OperandConfigStatus{Phase: apiv1alpha1.ServiceRunning},
+		}
+	}
+
+	It("Should convert the spec map of v1alpha1 into the custom resources of v1beta2", func() {
+		converted := &apiv1beta2.OperandConfig{}
+		convert(newConfig(), "operator.ibm.com/v1beta2", converted)
+
+		Expect(converted.APIVersion).To(Equal("operator.ibm.com/v1beta2"))
+		Expect(converted.Name).To(Equal("common-service"))
+		Expect(converted.Status.Phase).To(Equal(apiv1alpha1.ServiceRunning))
+		Expect(converted.Spec.Services).To(HaveLen(2))
+		service := converted.Spec.Services[0]
+		Expect(*service.Prune).To(BeFalse())
+		Expect(service.Remediation).To(Equal(apiv1alpha1.RemediationDetect))
+		Expect(service.CustomResources).To(Equal([]apiv1beta2.ConfigCustomResource{
+			{Kind: "etcdBackup", Spec: &runtime.RawExtension{Raw: []byte(`{"storageType":"S3"}`)}},
+			{Kind: "etcdCluster", Spec: &runtime.RawExtension{Raw: []byte(`{"size":3}`)}},
+			{Kind: "etcdRestoreJob"},
+		}))
+		Expect(service.GetCustomResource("EtcdCluster").Kind).To(Equal("etcdCluster"))
+		Expect(service.Placement.Profile).To(Equal("infra"))
+		Expect(service.SizeProfiles).To(Equal([]apiv1beta2.SizeProfile{
+			{Name: "small", CustomResources: []apiv1beta2.ConfigCustomResource{{Kind: "etcdCluster", Spec: &runtime.RawExtension{Raw: []byte(`{"size":1}`)}}}},
+			{Name: "large"},
+		}))
+		Expect(service.DefaultSizeProfile).To(Equal("small"))
+		Expect(converted.Spec.Services[1].State).To(Equal("absent"))
+		Expect(converted.Spec.Services[1].CustomResources).To(BeEmpty())
+	})
+
+	It("Should convert the OperandConfig back to v1alpha1 without losing the configuration", func() {
+		original := newConfig()
+		converted := &apiv1beta2.OperandConfig{}
+		convert(original, "operator.ibm.com/v1beta2", converted)
+
+		roundTrip := &apiv1alpha1.OperandConfig{}
+		convert(converted, "operator.ibm.com/v1alpha1", roundTrip)
+
+		Expect(roundTrip.Spec.Services).To(HaveLen(2))
+		Expect(roundTrip.Spec.Services[0].Spec).To(HaveLen(3))
+		Expect(roundTrip.Spec.Services[0].Spec["etcdCluster"].Raw).To(MatchJSON(`{"size":3}`))
+		Expect(roundTrip.Spec.Services[0].Spec["etcdBackup"].Raw).To(MatchJSON(`{"storageType":"S3"}`))
+		Expect(roundTrip.Spec.Services[0].Spec["etcdRestoreJob"].Raw).To(BeNil())
+		Expect(roundTrip.Spec.Services[0].Remediation).To(Equal(apiv1alpha1.RemediationDetect))
+		Expect(roundTrip.Spec.Services[0].Placement).To(Equal(original.Spec.Services[0].Placement))
+		Expect(roundTrip.Spec.Services[0].SizeProfiles).To(HaveLen(2))
+		Expect(roundTrip.Spec.Services[0].SizeProfiles[0].Spec["etcdCluster"].Raw).To(MatchJSON(`{"size":1}`))
+		Expect(roundTrip.Spec.Services[0].SizeProfiles[1].Spec).To(BeNil())
+		Expect(roundTrip.Spec.Services[0].DefaultSizeProfile).To(Equal("small"))
+		Expect(roundTrip.Spec.Services[0].Scaling).To(Equal(original.Spec.Services[0].Scaling))
+		Expect(roundTrip.Spec.Services[1]).To(Equal(original.Spec.Services[1]))
+	})
+})
@@ -0,0 +1,218 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webhooks
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/crdschema"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/health"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/postinstall"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/predelete"
+)
+
+// +kubebuilder:webhook:path=/validate-operator-ibm-com-v1alpha1-operandconfig,mutating=false,failurePolicy=ignore,sideEffects=None,groups=operator.ibm.com,resources=operandconfigs,verbs=create;update,versions=v1alpha1,name=voperandconfig.operator.ibm.com,admissionReviewVersions=v1
+
+// OperandConfigValidator rejects the OperandConfigs whose service templates don't match the schemas of their CRDs,
+// before the OperandRequests merge them into the custom resources
+type OperandConfigValidator struct {
+	*deploy.ODLMOperator
+	decoder *admission.Decoder
+}
+
+// Handle validates the created and updated OperandConfigs
+func (v *OperandConfigValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	configInstance := &apiv1alpha1.OperandConfig{}
+	if err := v.decoder.Decode(req, configInstance); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if req.Operation == admissionv1.Update {
+		if configInstance.DeletionTimestamp != nil {
+			return admission.Allowed("")
+		}
+		oldInstance := &apiv1alpha1.OperandConfig{}
+		if err := v.decoder.DecodeRaw(req.OldObject, oldInstance); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if equality.Semantic.DeepEqual(oldInstance.Spec, configInstance.Spec) {
+			return admission.Allowed("")
+		}
+	}
+
+	allErrs, err := v.validateOperandConfig(ctx, configInstance)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if len(allErrs) != 0 {
+		return denied(apierrors.NewInvalid(apiv1alpha1.GroupVersion.WithKind("OperandConfig").GroupKind(), configInstance.Name, allErrs))
+	}
+	return admission.Allowed("")
+}
+
+// validateOperandConfig validates the size profiles, the scaling and the hooks of the services, and their templates against the CRDs of the custom
+// resources in the alm-examples of their operators. The services whose operators aren't installed yet are validated at the next update.
+func (v *OperandConfigValidator) validateOperandConfig(ctx context.Context, configInstance *apiv1alpha1.OperandConfig) (field.ErrorList, error) {
+	var allErrs field.ErrorList
+	for i := range configInstance.Spec.Services {
+		servicePath := field.NewPath("spec", "services").Index(i)
+		allErrs = append(allErrs, validateSizeProfiles(&configInstance.Spec.Services[i], servicePath)...)
+		allErrs = append(allErrs, validateScaling(configInstance.Spec.Services[i].Scaling, servicePath.Child("scaling"))...)
+		allErrs = append(allErrs, validatePreDelete(&configInstance.Spec.Services[i], servicePath.Child("preDelete"))...)
+		allErrs = append(allErrs, validatePostInstall(&configInstance.Spec.Services[i], servicePath.Child("postInstall"))...)
+	}
+
+	// The OperandConfig configures the operators of the OperandRegistry with the same name
+	registryInstance := &apiv1alpha1.OperandRegistry{}
+	if err := v.Client.Get(ctx, types.NamespacedName{Namespace: configInstance.Namespace, Name: configInstance.Name}, registryInstance); err != nil {
+		if apierrors.IsNotFound(err) {
+			return allErrs, nil
+		}
+		return nil, err
+	}
+
+	for i, service := range configInstance.Spec.Services {
+		if len(service.Spec) == 0 {
+			continue
+		}
+		opt := registryInstance.GetOperator(service.Name)
+		if opt == nil || opt.GetType() != apiv1alpha1.OperatorTypeOLM {
+			continue
+		}
+		examples, err := v.GetALMExamples(ctx, registryInstance, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		errs, err := crdschema.ValidateTemplates(ctx, v.Reader, v.Discovery, examples, service.Spec, field.NewPath("spec", "services").Index(i).Child("spec"))
+		if err != nil {
+			return nil, err
+		}
+		allErrs = append(allErrs, errs...)
+	}
+	return allErrs, nil
+}
+
+// validateSizeProfiles checks the size profiles of the service have unique names and the default profile is one of them
+func validateSizeProfiles(service *apiv1alpha1.ConfigService, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := make(map[string]bool)
+	for i, p := range service.SizeProfiles {
+		namePath := fldPath.Child("sizeProfiles").Index(i).Child("name")
+		if p.Name == "" {
+			allErrs = append(allErrs, field.Required(namePath, "the size profile must have a name"))
+			continue
+		}
+		if names[p.Name] {
+			allErrs = append(allErrs, field.Duplicate(namePath, p.Name))
+		}
+		names[p.Name] = true
+	}
+	if service.DefaultSizeProfile != "" && !names[service.DefaultSizeProfile] {
+		allErrs = append(allErrs, field.NotFound(fldPath.Child("defaultSizeProfile"), service.DefaultSizeProfile))
+	}
+	return allErrs
+}
+
+// validateScaling checks the range of the replicas and the replica paths of the custom resources
+func validateScaling(h *apiv1alpha1.ScalingHints, fldPath *field.Path) field.ErrorList {
+	if h == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	if h.MinReplicas != nil && h.MaxReplicas != nil && *h.MinReplicas > *h.MaxReplicas {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxReplicas"), *h.MaxReplicas, "the maximum replicas must not be below the minimum replicas"))
+	}
+	for i, res := range h.CustomResources {
+		resPath := fldPath.Child("customResources").Index(i)
+		if res.Kind == "" {
+			allErrs = append(allErrs, field.Required(resPath.Child("kind"), "the kind of the custom resource is required"))
+		}
+		if res.ReplicasPath == "" || strings.Contains("."+res.ReplicasPath+".", "..") {
+			allErrs = append(allErrs, field.Invalid(resPath.Child("replicasPath"), res.ReplicasPath, "the replicas path must be a dot-separated path, like replicas or server.size"))
+		}
+	}
+	return allErrs
+}
+
+// validatePreDelete checks the Job of the pre-delete hooks is a valid Job spec, their Done expression compiles,
+// and their custom resources have a kind
+func validatePreDelete(service *apiv1alpha1.ConfigService, fldPath *field.Path) field.ErrorList {
+	h := service.PreDelete
+	if h == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	if h.Job != nil {
+		if _, err := predelete.NewJob(h, service.Name, ""); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("job"), string(h.Job.Raw), err.Error()))
+		}
+	}
+	if h.Done != "" {
+		if _, err := health.Compile(h.Done); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("done"), h.Done, err.Error()))
+		}
+	}
+	for i, res := range h.CustomResources {
+		if res.Kind == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("customResources").Index(i).Child("kind"), "the kind of the custom resource is required"))
+		}
+	}
+	return allErrs
+}
+
+// validatePostInstall checks the Job of the post-install hooks is a valid Job spec, and their HTTP check
+// has an absolute http or https URL and a valid status code
+func validatePostInstall(service *apiv1alpha1.ConfigService, fldPath *field.Path) field.ErrorList {
+	h := service.PostInstall
+	if h == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	if h.Job != nil {
+		if _, err := postinstall.NewJob(h, service.Name, ""); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("job"), string(h.Job.Raw), err.Error()))
+		}
+	}
+	if h.HTTP != nil {
+		if u, err := url.Parse(h.HTTP.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("http", "url"), h.HTTP.URL, "the URL must be an absolute http or https URL"))
+		}
+		if h.HTTP.ExpectedStatus != 0 && (h.HTTP.ExpectedStatus < 100 || h.HTTP.ExpectedStatus > 599) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("http", "expectedStatus"), h.HTTP.ExpectedStatus, "the status code must be between 100 and 599"))
+		}
+	}
+	return allErrs
+}
+
+// InjectDecoder injects the decoder of the webhook server
+func (v *OperandConfigValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
@@ -0,0 +1,242 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	odlmutil "github.com/IBM/operand-deployment-lifecycle-manager/pkg/util/v1"
+)
+
+var _ = Describe("OperandConfig webhook", func() {
+
+	var (
+		validator *OperandConfigValidator
+		config    *apiv1alpha1.OperandConfig
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(apiv1alpha1.AddToScheme(scheme))
+		utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+		utilruntime.Must(olmv1alpha1.AddToScheme(scheme))
+
+		registry := &apiv1alpha1.OperandRegistry{
+			ObjectMeta: metav1.ObjectMeta{Name: "common-service", Namespace: "ibm-common-services"},
+			Spec: apiv1alpha1.OperandRegistrySpec{
+				Operators: []apiv1alpha1.Operator{
+					{Name: "ibm-etcd-operator", Namespace: "ibm-common-services", PackageName: "ibm-etcd-operator-app", Channel: "v3"},
+					{Name: "ibm-mongodb-operator", Namespace: "ibm-common-services", PackageName: "ibm-mongodb-operator-app", Channel: "v3"},
+				},
+			},
+		}
+		sub := &olmv1alpha1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{Name: "ibm-etcd-operator", Namespace: "ibm-common-services"},
+			Spec:       &olmv1alpha1.SubscriptionSpec{Package: "ibm-etcd-operator-app", Channel: "v3"},
+			Status:     olmv1alpha1.SubscriptionStatus{InstalledCSV: "ibm-etcd-operator.v3.0.0"},
+		}
+		csv := &olmv1alpha1.ClusterServiceVersion{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "ibm-etcd-operator.v3.0.0",
+				Namespace:   "ibm-common-services",
+				Annotations: map[string]string{"alm-examples": `[{"apiVersion":"etcd.ibm.com/v1","kind":"EtcdCluster","metadata":{"name":"example"},"spec":{"size":1}}]`},
+			},
+		}
+		crd := &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "etcdclusters.etcd.ibm.com"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "etcd.ibm.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{
+						Name: "v1",
+						Schema: &apiextensionsv1.CustomResourceValidation{
+							OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"spec": {
+										Type: "object",
+										Properties: map[string]apiextensionsv1.JSONSchemaProps{
+											"size": {Type: "integer"},
+											"storage": {
+												Type:                   "object",
+												XPreserveUnknownFields: &[]bool{true}[0],
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		dc := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+		dc.Resources = []*metav1.APIResourceList{
+			{
+				GroupVersion: "etcd.ibm.com/v1",
+				APIResources: []metav1.APIResource{
+					{Name: "etcdclusters", Kind: "EtcdCluster", Namespaced: true},
+				},
+			},
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(registry, sub, csv, crd).Build()
+		validator = &OperandConfigValidator{
+			ODLMOperator: &deploy.ODLMOperator{
+				Client:    c,
+				Reader:    c,
+				Scheme:    scheme,
+				Discovery: odlmutil.NewCachedDiscovery(dc, time.Minute),
+			},
+		}
+		decoder, err := admission.NewDecoder(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(validator.InjectDecoder(decoder)).To(Succeed())
+
+		config = &apiv1alpha1.OperandConfig{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "operator.ibm.com/v1alpha1", Kind: "OperandConfig"},
+			ObjectMeta: metav1.ObjectMeta{Name: "common-service", Namespace: "ibm-common-services"},
+			Spec: apiv1alpha1.OperandConfigSpec{
+				Services: []apiv1alpha1.ConfigService{
+					{
+						Name: "ibm-etcd-operator",
+						Spec: map[string]runtime.RawExtension{"etcdCluster": {Raw: []byte(`{"size":3,"storage":{"class":"fast"}}`)}},
+					},
+					{
+						// The operator isn't installed, its template isn't validated yet
+						Name: "ibm-mongodb-operator",
+						Spec: map[string]runtime.RawExtension{"mongoDB": {Raw: []byte(`{"replicas":"three"}`)}},
+					},
+				},
+			},
+		}
+	})
+
+	admissionRequest := func(obj *apiv1alpha1.OperandConfig) admission.Request {
+		raw, err := json.Marshal(obj)
+		Expect(err).NotTo(HaveOccurred())
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Create, Object: runtime.RawExtension{Raw: raw}}}
+	}
+
+	It("Should allow the templates matching the schemas", func() {
+		resp := validator.Handle(context.TODO(), admissionRequest(config))
+		Expect(resp.Allowed).To(BeTrue())
+	})
+
+	It("Should reject the template with a type mismatch", func() {
+		config.Spec.Services[0].Spec["etcdCluster"] = runtime.RawExtension{Raw: []byte(`{"size":"three"}`)}
+		resp := validator.Handle(context.TODO(), admissionRequest(config))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Details.Causes).To(HaveLen(1))
+		Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.services[0].spec[etcdCluster].size"))
+	})
+
+	It("Should reject the template with an unknown field", func() {
+		config.Spec.Services[0].Spec["etcdCluster"] = runtime.RawExtension{Raw: []byte(`{"size":3,"sise":3}`)}
+		resp := validator.Handle(context.TODO(), admissionRequest(config))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Details.Causes).To(HaveLen(1))
+		Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.services[0].spec[etcdCluster].sise"))
+	})
+
+	It("Should reject the duplicated size profiles and a missing default profile", func() {
+		config.Spec.Services[0].SizeProfiles = []apiv1alpha1.SizeProfile{{Name: "small"}, {Name: "large"}, {Name: "small"}}
+		config.Spec.Services[0].DefaultSizeProfile = "small"
+		resp := validator.Handle(context.TODO(), admissionRequest(config))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Details.Causes).To(HaveLen(1))
+		Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.services[0].sizeProfiles[2].name"))
+
+		config.Spec.Services[0].SizeProfiles = config.Spec.Services[0].SizeProfiles[:2]
+		config.Spec.Services[0].DefaultSizeProfile = "medium"
+		resp = validator.Handle(context.TODO(), admissionRequest(config))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.services[0].defaultSizeProfile"))
+	})
+
+	It("Should reject the scaling with an invalid range or replica path", func() {
+		min, max := int32(3), int32(1)
+		config.Spec.Services[0].Scaling = &apiv1alpha1.ScalingHints{
+			MinReplicas: &min,
+			MaxReplicas: &max,
+			CustomResources: []apiv1alpha1.ScalingResource{
+				{Kind: "etcdCluster", ReplicasPath: "size"},
+				{Kind: "etcdCluster", ReplicasPath: "proxy..replicas"},
+			},
+		}
+		resp := validator.Handle(context.TODO(), admissionRequest(config))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Details.Causes).To(HaveLen(2))
+		Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.services[0].scaling.maxReplicas"))
+		Expect(resp.Result.Details.Causes[1].Field).To(Equal("spec.services[0].scaling.customResources[1].replicasPath"))
+	})
+
+	It("Should reject the pre-delete hooks with an invalid Job or Done expression", func() {
+		config.Spec.Services[0].PreDelete = &apiv1alpha1.PreDeleteHooks{
+			Job:             &runtime.RawExtension{Raw: []byte(`{"template":{"spec":{"containers":[{"name":"drain","image":"etcd-drain"}]}}}`)},
+			Done:            "crs.all(cr, has(cr.status.drained) && cr.status.drained)",
+			CustomResources: []apiv1alpha1.PreDeleteResource{{Kind: "etcdCluster", Spec: &runtime.RawExtension{Raw: []byte(`{"drain":true}`)}}},
+		}
+		resp := validator.Handle(context.TODO(), admissionRequest(config))
+		Expect(resp.Allowed).To(BeTrue())
+
+		config.Spec.Services[0].PreDelete.Job = &runtime.RawExtension{Raw: []byte(`{"template":{"spec":{}}}`)}
+		config.Spec.Services[0].PreDelete.Done = "crs.all(cr, cr.status.drained"
+		config.Spec.Services[0].PreDelete.CustomResources[0].Kind = ""
+		resp = validator.Handle(context.TODO(), admissionRequest(config))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Details.Causes).To(HaveLen(3))
+		Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.services[0].preDelete.job"))
+		Expect(resp.Result.Details.Causes[1].Field).To(Equal("spec.services[0].preDelete.done"))
+		Expect(resp.Result.Details.Causes[2].Field).To(Equal("spec.services[0].preDelete.customResources[0].kind"))
+	})
+
+	It("Should reject the post-install hooks with an invalid Job or HTTP check", func() {
+		config.Spec.Services[0].PostInstall = &apiv1alpha1.PostInstallHooks{
+			Job:  &runtime.RawExtension{Raw: []byte(`{"template":{"spec":{"containers":[{"name":"smoke-test","image":"etcd-smoke-test"}]}}}`)},
+			HTTP: &apiv1alpha1.HTTPCheck{URL: "https://etcd.ibm-common-services.svc:2379/health", ExpectedStatus: 200},
+		}
+		resp := validator.Handle(context.TODO(), admissionRequest(config))
+		Expect(resp.Allowed).To(BeTrue())
+
+		config.Spec.Services[0].PostInstall.Job = &runtime.RawExtension{Raw: []byte(`{"template":{"spec":{}}}`)}
+		config.Spec.Services[0].PostInstall.HTTP = &apiv1alpha1.HTTPCheck{URL: "etcd:2379/health", ExpectedStatus: 1000}
+		resp = validator.Handle(context.TODO(), admissionRequest(config))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Details.Causes).To(HaveLen(3))
+		Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.services[0].postInstall.job"))
+		Expect(resp.Result.Details.Causes[1].Field).To(Equal("spec.services[0].postInstall.http.url"))
+		Expect(resp.Result.Details.Causes[2].Field).To(Equal("spec.services[0].postInstall.http.expectedStatus"))
+	})
+})
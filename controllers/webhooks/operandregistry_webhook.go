@@ -0,0 +1,112 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webhooks
+
+import (
+	"context"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/policy"
+)
+
+// +kubebuilder:webhook:path=/validate-operator-ibm-com-v1alpha1-operandregistry,mutating=false,failurePolicy=ignore,sideEffects=None,groups=operator.ibm.com,resources=operandregistries,verbs=update,versions=v1alpha1,name=voperandregistry.operator.ibm.com,admissionReviewVersions=v1
+
+// OperandRegistryValidator rejects the channel switches of the operators denied by the ClusterOperandPolicies,
+// so they are reported at apply time instead of blocking the OperandRequests
+type OperandRegistryValidator struct {
+	*deploy.ODLMOperator
+	decoder *admission.Decoder
+}
+
+// Handle validates the updated OperandRegistries
+func (v *OperandRegistryValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+	registryInstance := &apiv1alpha1.OperandRegistry{}
+	if err := v.decoder.Decode(req, registryInstance); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if registryInstance.DeletionTimestamp != nil {
+		return admission.Allowed("")
+	}
+	oldInstance := &apiv1alpha1.OperandRegistry{}
+	if err := v.decoder.DecodeRaw(req.OldObject, oldInstance); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	allErrs, err := v.validateChannels(ctx, oldInstance, registryInstance)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if len(allErrs) != 0 {
+		return denied(apierrors.NewInvalid(apiv1alpha1.GroupVersion.WithKind("OperandRegistry").GroupKind(), registryInstance.Name, allErrs))
+	}
+	return admission.Allowed("")
+}
+
+// validateChannels checks the channel switches of the OLM operators against the ClusterOperandPolicies,
+// unless the OperandRegistry forces them. The Subscriptions may be on another channel, they are checked in reconcile.
+func (v *OperandRegistryValidator) validateChannels(ctx context.Context, oldInstance, registryInstance *apiv1alpha1.OperandRegistry) (field.ErrorList, error) {
+	var switched []int
+	for i, opt := range registryInstance.Spec.Operators {
+		oldOpt := oldInstance.GetOperator(opt.Name)
+		if oldOpt == nil || oldOpt.Channel == opt.Channel || opt.GetType() != apiv1alpha1.OperatorTypeOLM || registryInstance.IsUpgradeForced(opt.Name) {
+			continue
+		}
+		switched = append(switched, i)
+	}
+	if len(switched) == 0 {
+		return nil, nil
+	}
+	policies, err := policy.List(ctx, v.Client)
+	if err != nil || len(policies) == 0 {
+		return nil, err
+	}
+
+	var allErrs field.ErrorList
+	for _, i := range switched {
+		opt := &registryInstance.Spec.Operators[i]
+		optPath := field.NewPath("spec", "operators").Index(i)
+		scheme, err := deploy.GetChannelScheme(opt)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(optPath.Child("channelScheme"), opt.ChannelScheme, err.Error()))
+			continue
+		}
+		violation, err := policy.CheckChannelSwitch(policies, opt.Name, scheme, oldInstance.GetOperator(opt.Name).Channel, opt.Channel)
+		if err != nil {
+			return nil, err
+		}
+		if violation != nil {
+			allErrs = append(allErrs, field.Forbidden(optPath.Child("channel"), violation.Message))
+		}
+	}
+	return allErrs, nil
+}
+
+// InjectDecoder injects the decoder of the webhook server
+func (v *OperandRegistryValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
@@ -0,0 +1,115 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+)
+
+var _ = Describe("OperandRegistry webhook", func() {
+
+	var (
+		validator *OperandRegistryValidator
+		registry  *apiv1alpha1.OperandRegistry
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(apiv1alpha1.AddToScheme(scheme))
+
+		maxJump := int32(1)
+		policy := &apiv1alpha1.ClusterOperandPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "upgrades"},
+			Spec:       apiv1alpha1.ClusterOperandPolicySpec{DenyChannelDowngrade: true, MaxMinorVersionJump: &maxJump},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy).Build()
+		validator = &OperandRegistryValidator{
+			ODLMOperator: &deploy.ODLMOperator{Client: c, Reader: c, Scheme: scheme},
+		}
+		decoder, err := admission.NewDecoder(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(validator.InjectDecoder(decoder)).To(Succeed())
+
+		registry = &apiv1alpha1.OperandRegistry{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "operator.ibm.com/v1alpha1", Kind: "OperandRegistry"},
+			ObjectMeta: metav1.ObjectMeta{Name: "common-service", Namespace: "ibm-common-services"},
+			Spec: apiv1alpha1.OperandRegistrySpec{
+				Operators: []apiv1alpha1.Operator{
+					{Name: "ibm-etcd-operator", PackageName: "ibm-etcd-operator-app", Channel: "v3.20"},
+					{Name: "ibm-mongodb-operator", PackageName: "ibm-mongodb-operator-app", Channel: "v1.4"},
+				},
+			},
+		}
+	})
+
+	admissionRequest := func(obj, old *apiv1alpha1.OperandRegistry) admission.Request {
+		req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Update}}
+		raw, err := json.Marshal(obj)
+		Expect(err).NotTo(HaveOccurred())
+		req.Object = runtime.RawExtension{Raw: raw}
+		raw, err = json.Marshal(old)
+		Expect(err).NotTo(HaveOccurred())
+		req.OldObject = runtime.RawExtension{Raw: raw}
+		return req
+	}
+
+	It("Should allow the channel switches within the policies", func() {
+		updated := registry.DeepCopy()
+		updated.Spec.Operators[0].Channel = "v3.21"
+		resp := validator.Handle(context.TODO(), admissionRequest(updated, registry))
+		Expect(resp.Allowed).To(BeTrue())
+
+		updated.Spec.Operators[0].Channel = "v4.0"
+		resp = validator.Handle(context.TODO(), admissionRequest(updated, registry))
+		Expect(resp.Allowed).To(BeTrue())
+	})
+
+	It("Should reject the downgrades and the channel jumps", func() {
+		updated := registry.DeepCopy()
+		updated.Spec.Operators[0].Channel = "v3.19"
+		updated.Spec.Operators[1].Channel = "v1.6"
+		resp := validator.Handle(context.TODO(), admissionRequest(updated, registry))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Details.Causes).To(HaveLen(2))
+		Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.operators[0].channel"))
+		Expect(resp.Result.Details.Causes[0].Message).To(ContainSubstring("denies the downgrade"))
+		Expect(resp.Result.Details.Causes[1].Field).To(Equal("spec.operators[1].channel"))
+		Expect(resp.Result.Details.Causes[1].Message).To(ContainSubstring("it allows 1 minor versions at once"))
+		Expect(resp.Result.Details.Causes[1].Message).To(ContainSubstring(apiv1alpha1.ForceUpgradeAnnotation))
+	})
+
+	It("Should allow the channel switches forced by the annotation", func() {
+		updated := registry.DeepCopy()
+		updated.Spec.Operators[0].Channel = "v5.0"
+		updated.Annotations = map[string]string{apiv1alpha1.ForceUpgradeAnnotation: "ibm-mongodb-operator, ibm-etcd-operator"}
+		resp := validator.Handle(context.TODO(), admissionRequest(updated, registry))
+		Expect(resp.Allowed).To(BeTrue())
+	})
+})
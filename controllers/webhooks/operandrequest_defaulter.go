@@ -0,0 +1,117 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+)
+
+// registryLabelPattern matches the tracking labels <namespace>.<name>/registry of the OperandRegistries
+var registryLabelPattern = regexp.MustCompile(`^(.*)\.(.*)\/registry$`)
+
+// +kubebuilder:webhook:path=/mutate-operator-ibm-com-v1alpha1-operandrequest,mutating=true,failurePolicy=ignore,sideEffects=None,groups=operator.ibm.com,resources=operandrequests,verbs=create;update,versions=v1alpha1,name=moperandrequest.operator.ibm.com,admissionReviewVersions=v1
+
+// OperandRequestDefaulter fills in the defaults of the OperandRequests, so the reconciler and the indexers
+// don't have to default the registries of the requests again
+type OperandRequestDefaulter struct {
+	*deploy.ODLMOperator
+	decoder *admission.Decoder
+}
+
+// Handle defaults the created and updated OperandRequests
+func (d *OperandRequestDefaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	requestInstance := &apiv1alpha1.OperandRequest{}
+	if err := d.decoder.Decode(req, requestInstance); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if req.Operation == admissionv1.Update && requestInstance.DeletionTimestamp != nil {
+		return admission.Allowed("")
+	}
+
+	if err := d.defaultOperandRequest(ctx, requestInstance); err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	marshaled, err := json.Marshal(requestInstance)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// defaultOperandRequest sets the registry namespaces, the registry when its namespace has a single OperandRegistry,
+// trims the names of the operands, and labels the request with the OperandRegistries it uses
+func (d *OperandRequestDefaulter) defaultOperandRequest(ctx context.Context, requestInstance *apiv1alpha1.OperandRequest) error {
+	registryLabels := make(map[string]string)
+	for i := range requestInstance.Spec.Requests {
+		req := &requestInstance.Spec.Requests[i]
+		req.Registry = strings.TrimSpace(req.Registry)
+		req.RegistryNamespace = strings.TrimSpace(req.RegistryNamespace)
+		if req.RegistryNamespace == "" {
+			req.RegistryNamespace = requestInstance.Namespace
+		}
+		if req.Registry == "" {
+			registryList := &apiv1alpha1.OperandRegistryList{}
+			if err := d.Client.List(ctx, registryList, client.InNamespace(req.RegistryNamespace)); err != nil {
+				return err
+			}
+			if len(registryList.Items) == 1 {
+				req.Registry = registryList.Items[0].Name
+			}
+		}
+		for j := range req.Operands {
+			req.Operands[j].Name = strings.TrimSpace(req.Operands[j].Name)
+		}
+
+		if key := req.RegistryNamespace + "." + req.Registry + "/registry"; req.Registry != "" && len(validation.IsQualifiedName(key)) == 0 {
+			registryLabels[key] = "true"
+		}
+	}
+
+	labels := requestInstance.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	// Remove the labels of the OperandRegistries the request doesn't use anymore
+	for key, value := range labels {
+		if value == "true" && registryLabelPattern.MatchString(key) && registryLabels[key] == "" {
+			delete(labels, key)
+		}
+	}
+	for key, value := range registryLabels {
+		labels[key] = value
+	}
+	requestInstance.SetLabels(labels)
+	return nil
+}
+
+// InjectDecoder injects the decoder of the webhook server
+func (d *OperandRequestDefaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
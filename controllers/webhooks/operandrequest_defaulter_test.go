@@ -0,0 +1,120 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+)
+
+var _ = Describe("OperandRequest defaulter", func() {
+
+	var (
+		defaulter *OperandRequestDefaulter
+		request   *apiv1alpha1.OperandRequest
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(apiv1alpha1.AddToScheme(scheme))
+
+		registries := []runtime.Object{
+			&apiv1alpha1.OperandRegistry{ObjectMeta: metav1.ObjectMeta{Name: "common-service", Namespace: "ibm-common-services"}},
+			&apiv1alpha1.OperandRegistry{ObjectMeta: metav1.ObjectMeta{Name: "cloudpak", Namespace: "ibm-cloudpak"}},
+			&apiv1alpha1.OperandRegistry{ObjectMeta: metav1.ObjectMeta{Name: "cloudpak-preview", Namespace: "ibm-cloudpak"}},
+		}
+		defaulter = &OperandRequestDefaulter{
+			ODLMOperator: &deploy.ODLMOperator{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(registries...).Build(),
+				Scheme: scheme,
+			},
+		}
+		decoder, err := admission.NewDecoder(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(defaulter.InjectDecoder(decoder)).To(Succeed())
+
+		request = &apiv1alpha1.OperandRequest{
+			TypeMeta: metav1.TypeMeta{APIVersion: "operator.ibm.com/v1alpha1", Kind: "OperandRequest"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "common-service",
+				Namespace: "ibm-cloudpak",
+				Labels:    map[string]string{"ibm-cloudpak.old-registry/registry": "true", "app": "cloudpak"},
+			},
+			Spec: apiv1alpha1.OperandRequestSpec{
+				Requests: []apiv1alpha1.Request{
+					{
+						Registry:          "common-service",
+						RegistryNamespace: "ibm-common-services",
+						Operands:          []apiv1alpha1.Operand{{Name: " ibm-etcd-operator "}},
+					},
+					{
+						Registry: "cloudpak",
+					},
+				},
+			},
+		}
+	})
+
+	// mutate returns the OperandRequest patched by the defaulter
+	mutate := func(obj *apiv1alpha1.OperandRequest) *apiv1alpha1.OperandRequest {
+		raw, err := json.Marshal(obj)
+		Expect(err).NotTo(HaveOccurred())
+		resp := defaulter.Handle(context.TODO(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Create, Object: runtime.RawExtension{Raw: raw}}})
+		Expect(resp.Allowed).To(BeTrue())
+		patch, err := json.Marshal(resp.Patches)
+		Expect(err).NotTo(HaveOccurred())
+		decoded, err := jsonpatch.DecodePatch(patch)
+		Expect(err).NotTo(HaveOccurred())
+		patched, err := decoded.Apply(raw)
+		Expect(err).NotTo(HaveOccurred())
+		mutated := &apiv1alpha1.OperandRequest{}
+		Expect(json.Unmarshal(patched, mutated)).To(Succeed())
+		return mutated
+	}
+
+	It("Should default the registry namespace and label the request with its registries", func() {
+		mutated := mutate(request)
+		Expect(mutated.Spec.Requests[1].RegistryNamespace).To(Equal("ibm-cloudpak"))
+		Expect(mutated.Spec.Requests[0].Operands[0].Name).To(Equal("ibm-etcd-operator"))
+		Expect(mutated.Labels).To(Equal(map[string]string{
+			"ibm-common-services.common-service/registry": "true",
+			"ibm-cloudpak.cloudpak/registry":              "true",
+			"app":                                         "cloudpak",
+		}))
+	})
+
+	It("Should default the registry only when its namespace has a single OperandRegistry", func() {
+		request.Spec.Requests[0].Registry = ""
+		request.Spec.Requests[1].Registry = ""
+		mutated := mutate(request)
+		Expect(mutated.Spec.Requests[0].Registry).To(Equal("common-service"))
+		Expect(mutated.Spec.Requests[1].Registry).To(BeEmpty())
+	})
+})
@@ -0,0 +1,309 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webhooks
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/crdschema"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/patch"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/policy"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/scaling"
+)
+
+// channelPattern matches the channel names of the OLM packages, like v3, stable-v1 or release-1.2
+var channelPattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._-]*[A-Za-z0-9])?$`)
+
+// +kubebuilder:webhook:path=/validate-operator-ibm-com-v1alpha1-operandrequest,mutating=false,failurePolicy=ignore,sideEffects=None,groups=operator.ibm.com,resources=operandrequests,verbs=create;update,versions=v1alpha1,name=voperandrequest.operator.ibm.com,admissionReviewVersions=v1
+
+// OperandRequestValidator rejects the OperandRequests which can't be reconciled,
+// so kubectl reports the errors at apply time instead of the status of the request
+type OperandRequestValidator struct {
+	*deploy.ODLMOperator
+	decoder *admission.Decoder
+}
+
+// Handle validates the created and updated OperandRequests
+func (v *OperandRequestValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	requestInstance := &apiv1alpha1.OperandRequest{}
+	if err := v.decoder.Decode(req, requestInstance); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if req.Operation == admissionv1.Update {
+		// The request being deleted must be able to remove its finalizer, even if its registry is gone
+		if requestInstance.DeletionTimestamp != nil {
+			return admission.Allowed("")
+		}
+		oldInstance := &apiv1alpha1.OperandRequest{}
+		if err := v.decoder.DecodeRaw(req.OldObject, oldInstance); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if equality.Semantic.DeepEqual(oldInstance.Spec, requestInstance.Spec) {
+			if oldInstance.GetAnnotations()[apiv1alpha1.PriorityAnnotation] == requestInstance.GetAnnotations()[apiv1alpha1.PriorityAnnotation] {
+				return admission.Allowed("")
+			}
+			if allErrs := validatePriority(requestInstance); len(allErrs) != 0 {
+				return denied(apierrors.NewInvalid(apiv1alpha1.GroupVersion.WithKind("OperandRequest").GroupKind(), requestInstance.Name, allErrs))
+			}
+			return admission.Allowed("")
+		}
+	}
+
+	allErrs, err := v.validateOperandRequest(ctx, requestInstance)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	policyErrs, err := v.validatePolicies(ctx, requestInstance)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	allErrs = append(allErrs, policyErrs...)
+	allErrs = append(allErrs, validatePriority(requestInstance)...)
+	if len(allErrs) != 0 {
+		return denied(apierrors.NewInvalid(apiv1alpha1.GroupVersion.WithKind("OperandRequest").GroupKind(), requestInstance.Name, allErrs))
+	}
+	return admission.Allowed("")
+}
+
+// validateOperandRequest checks the registries, the operators and the specs of the custom resources of the requests
+func (v *OperandRequestValidator) validateOperandRequest(ctx context.Context, requestInstance *apiv1alpha1.OperandRequest) (field.ErrorList, error) {
+	var allErrs field.ErrorList
+	for i, req := range requestInstance.Spec.Requests {
+		reqPath := field.NewPath("spec", "requests").Index(i)
+		registryKey := requestInstance.GetRegistryKey(req)
+		// The catalog sources of the operators are resolved in reconcile, the webhook doesn't look them up
+		registryInstance := &apiv1alpha1.OperandRegistry{}
+		if err := v.Client.Get(ctx, registryKey, registryInstance); err != nil {
+			if apierrors.IsNotFound(err) {
+				allErrs = append(allErrs, field.NotFound(reqPath.Child("registry"), registryKey.String()))
+				continue
+			}
+			return nil, err
+		}
+
+		configInstance, err := v.getOperandConfig(ctx, req.Operands, registryKey)
+		if err != nil {
+			return nil, err
+		}
+
+		for j, operand := range req.Operands {
+			operandPath := reqPath.Child("operands").Index(j)
+			allErrs = append(allErrs, validateSizeProfile(operand, configInstance, registryKey, operandPath)...)
+			allErrs = append(allErrs, validateReplicas(operand, configInstance, registryKey, operandPath)...)
+			allErrs = append(allErrs, validatePatches(operand, operandPath)...)
+			opt := registryInstance.GetOperator(operand.Name)
+			if opt == nil {
+				allErrs = append(allErrs, &field.Error{
+					Type:     field.ErrorTypeNotFound,
+					Field:    operandPath.Child("name").String(),
+					BadValue: operand.Name,
+					Detail:   "the operator isn't in the OperandRegistry " + registryKey.String() + ", the available operators are " + strings.Join(operatorNames(registryInstance), ", "),
+				})
+				continue
+			}
+			if opt.GetType() == apiv1alpha1.OperatorTypeOLM && !channelPattern.MatchString(opt.Channel) {
+				allErrs = append(allErrs, field.Invalid(operandPath.Child("name"), operand.Name, "the channel "+opt.Channel+" of the operator in the OperandRegistry "+registryKey.String()+" is malformed"))
+			}
+			if operand.Kind == "" || operand.APIVersion == "" {
+				continue
+			}
+			specSchema, err := crdschema.GetSpecSchema(ctx, v.Reader, v.Discovery, operand.APIVersion, operand.Kind)
+			if err != nil {
+				return nil, err
+			}
+			// The CRD of the operand is created with its operator, the spec is validated by the API server then
+			allErrs = append(allErrs, crdschema.ValidateSpec(specSchema, operand.Spec, operandPath.Child("spec"))...)
+		}
+	}
+	return allErrs, nil
+}
+
+// validatePriority checks the priority annotation of the OperandRequest is one of the priority classes
+func validatePriority(requestInstance *apiv1alpha1.OperandRequest) field.ErrorList {
+	priority, ok := requestInstance.GetAnnotations()[apiv1alpha1.PriorityAnnotation]
+	if !ok || apiv1alpha1.IsPriorityClass(priority) {
+		return nil
+	}
+	classes := []string{apiv1alpha1.PriorityCritical, apiv1alpha1.PriorityHigh, apiv1alpha1.PriorityNormal, apiv1alpha1.PriorityLow}
+	return field.ErrorList{field.NotSupported(field.NewPath("metadata", "annotations").Key(apiv1alpha1.PriorityAnnotation), priority, classes)}
+}
+
+// getOperandConfig returns the OperandConfig of the registry when an operand selects a size profile or sets the replicas.
+// It is nil when the OperandConfig isn't found or not needed.
+func (v *OperandRequestValidator) getOperandConfig(ctx context.Context, operands []apiv1alpha1.Operand, registryKey types.NamespacedName) (*apiv1alpha1.OperandConfig, error) {
+	for _, operand := range operands {
+		if operand.SizeProfile == "" && operand.Replicas == nil {
+			continue
+		}
+		configInstance := &apiv1alpha1.OperandConfig{}
+		if err := v.Client.Get(ctx, registryKey, configInstance); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return configInstance, nil
+	}
+	return nil, nil
+}
+
+// validateSizeProfile checks the size profile of the operand is one of the profiles of its service in the OperandConfig
+func validateSizeProfile(operand apiv1alpha1.Operand, configInstance *apiv1alpha1.OperandConfig, registryKey types.NamespacedName, operandPath *field.Path) field.ErrorList {
+	if operand.SizeProfile == "" {
+		return nil
+	}
+	profilePath := operandPath.Child("sizeProfile")
+	if operand.Kind != "" {
+		return field.ErrorList{field.Invalid(profilePath, operand.SizeProfile, "the size profiles apply to the custom resources of the OperandConfig, set the size in the spec of the operand instead")}
+	}
+	var service *apiv1alpha1.ConfigService
+	if configInstance != nil {
+		service = configInstance.GetService(operand.Name)
+	}
+	if service != nil && service.GetSizeProfile(operand.SizeProfile) != nil {
+		return nil
+	}
+	var names []string
+	if service != nil {
+		for _, p := range service.SizeProfiles {
+			names = append(names, p.Name)
+		}
+	}
+	detail := "the service has no size profile in the OperandConfig " + registryKey.String()
+	if len(names) > 0 {
+		detail = "the size profile isn't in the OperandConfig " + registryKey.String() + ", the available profiles are " + strings.Join(names, ", ")
+	}
+	return field.ErrorList{&field.Error{
+		Type:     field.ErrorTypeNotFound,
+		Field:    profilePath.String(),
+		BadValue: operand.SizeProfile,
+		Detail:   detail,
+	}}
+}
+
+// validateReplicas checks the service of the operand declares its scaling in the OperandConfig, and the replicas are in its range
+func validateReplicas(operand apiv1alpha1.Operand, configInstance *apiv1alpha1.OperandConfig, registryKey types.NamespacedName, operandPath *field.Path) field.ErrorList {
+	if operand.Replicas == nil {
+		return nil
+	}
+	replicasPath := operandPath.Child("replicas")
+	if operand.Kind != "" {
+		return field.ErrorList{field.Invalid(replicasPath, *operand.Replicas, "the replicas apply to the custom resources of the OperandConfig, set them in the spec of the operand instead")}
+	}
+	var service *apiv1alpha1.ConfigService
+	if configInstance != nil {
+		service = configInstance.GetService(operand.Name)
+	}
+	if service == nil || service.Scaling == nil {
+		return field.ErrorList{field.Invalid(replicasPath, *operand.Replicas, "the service declares no scaling in the OperandConfig "+registryKey.String())}
+	}
+	if err := scaling.Validate(service.Scaling, *operand.Replicas); err != nil {
+		return field.ErrorList{field.Invalid(replicasPath, *operand.Replicas, err.Error())}
+	}
+	return nil
+}
+
+// validatePatches checks the patches of the operand are valid JSON6902 operations on the spec of its custom resource
+func validatePatches(operand apiv1alpha1.Operand, operandPath *field.Path) field.ErrorList {
+	if len(operand.Patches) == 0 {
+		return nil
+	}
+	patchesPath := operandPath.Child("patches")
+	if operand.Kind == "" {
+		return field.ErrorList{field.Invalid(patchesPath, len(operand.Patches), "the patches apply to the custom resource of the operand, set its kind or patch the OperandConfig instead")}
+	}
+	if err := patch.Validate(operand.Patches); err != nil {
+		return field.ErrorList{field.Invalid(patchesPath, len(operand.Patches), err.Error())}
+	}
+	return nil
+}
+
+// validatePolicies checks the operands of the requests against the ClusterOperandPolicies.
+// The channel downgrades are denied in reconcile, the OperandRequest doesn't set the channels.
+func (v *OperandRequestValidator) validatePolicies(ctx context.Context, requestInstance *apiv1alpha1.OperandRequest) (field.ErrorList, error) {
+	policies, err := policy.List(ctx, v.Client)
+	if err != nil || len(policies) == 0 {
+		return nil, err
+	}
+	ns := &corev1.Namespace{}
+	if err := v.Reader.Get(ctx, types.NamespacedName{Name: requestInstance.Namespace}, ns); err != nil {
+		return nil, errors.Wrapf(err, "failed to get namespace %s", requestInstance.Namespace)
+	}
+	requestList := &apiv1alpha1.OperandRequestList{}
+	if err := v.Client.List(ctx, requestList, client.InNamespace(requestInstance.Namespace)); err != nil {
+		return nil, errors.Wrapf(err, "failed to list the OperandRequests in namespace %s", requestInstance.Namespace)
+	}
+	exceeded := policy.CheckQuota(policies, requestInstance, requestList.Items)
+
+	var allErrs field.ErrorList
+	for i, req := range requestInstance.Spec.Requests {
+		for j, operand := range req.Operands {
+			namePath := field.NewPath("spec", "requests").Index(i).Child("operands").Index(j).Child("name")
+			violation, err := policy.CheckOperator(policies, operand.Name, ns.Name, ns.Labels)
+			if err != nil {
+				return nil, err
+			}
+			if violation == nil {
+				violation = exceeded[operand.Name]
+			}
+			if violation != nil {
+				allErrs = append(allErrs, field.Forbidden(namePath, violation.Message))
+			}
+		}
+	}
+	return allErrs, nil
+}
+
+// InjectDecoder injects the decoder of the webhook server
+func (v *OperandRequestValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// operatorNames returns the names of the operators in the OperandRegistry
+func operatorNames(registryInstance *apiv1alpha1.OperandRegistry) []string {
+	names := make([]string, 0, len(registryInstance.Spec.Operators))
+	for _, o := range registryInstance.Spec.Operators {
+		names = append(names, o.Name)
+	}
+	return names
+}
+
+// denied returns the response rejecting the object with the status of the error,
+// kubectl shows the field paths and the details of the causes
+func denied(err *apierrors.StatusError) admission.Response {
+	resp := admission.Denied(err.ErrStatus.Message)
+	status := err.Status()
+	resp.Result = &status
+	return resp
+}
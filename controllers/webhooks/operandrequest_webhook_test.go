@@ -0,0 +1,336 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apiv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	odlmutil "github.com/IBM/operand-deployment-lifecycle-manager/pkg/util/v1"
+)
+
+var _ = Describe("OperandRequest webhook", func() {
+
+	var (
+		validator *OperandRequestValidator
+		request   *apiv1alpha1.OperandRequest
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(apiv1alpha1.AddToScheme(scheme))
+		utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+		utilruntime.Must(corev1.AddToScheme(scheme))
+
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ibm-common-services", Labels: map[string]string{"tier": "platform"}}}
+		registry := &apiv1alpha1.OperandRegistry{
+			ObjectMeta: metav1.ObjectMeta{Name: "common-service", Namespace: "ibm-common-services"},
+			Spec: apiv1alpha1.OperandRegistrySpec{
+				Operators: []apiv1alpha1.Operator{
+					{Name: "ibm-etcd-operator", PackageName: "ibm-etcd-operator-app", Channel: "v3"},
+					{Name: "ibm-mongodb-operator", PackageName: "ibm-mongodb-operator-app", Channel: "stable v1"},
+				},
+			},
+		}
+		crd := &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "etcdclusters.etcd.ibm.com"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "etcd.ibm.com",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{
+						Name: "v1",
+						Schema: &apiextensionsv1.CustomResourceValidation{
+							OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"spec": {
+										Type:     "object",
+										Required: []string{"size"},
+										Properties: map[string]apiextensionsv1.JSONSchemaProps{
+											"size": {Type: "integer"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		dc := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+		dc.Resources = []*metav1.APIResourceList{
+			{
+				GroupVersion: "etcd.ibm.com/v1",
+				APIResources: []metav1.APIResource{
+					{Name: "etcdclusters", Kind: "EtcdCluster", Namespaced: true},
+				},
+			},
+		}
+
+		maxReplicas := int32(5)
+		config := &apiv1alpha1.OperandConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "common-service", Namespace: "ibm-common-services"},
+			Spec: apiv1alpha1.OperandConfigSpec{
+				Services: []apiv1alpha1.ConfigService{
+					{
+						Name:         "ibm-etcd-operator",
+						SizeProfiles: []apiv1alpha1.SizeProfile{{Name: "small"}, {Name: "large"}},
+						Scaling: &apiv1alpha1.ScalingHints{
+							MaxReplicas:     &maxReplicas,
+							CustomResources: []apiv1alpha1.ScalingResource{{Kind: "etcdCluster", ReplicasPath: "size"}},
+						},
+					},
+				},
+			},
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(namespace, registry, config, crd).Build()
+		validator = &OperandRequestValidator{
+			ODLMOperator: &deploy.ODLMOperator{
+				Client:    c,
+				Reader:    c,
+				Scheme:    scheme,
+				Discovery: odlmutil.NewCachedDiscovery(dc, time.Minute),
+			},
+		}
+		decoder, err := admission.NewDecoder(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(validator.InjectDecoder(decoder)).To(Succeed())
+
+		request = &apiv1alpha1.OperandRequest{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "operator.ibm.com/v1alpha1", Kind: "OperandRequest"},
+			ObjectMeta: metav1.ObjectMeta{Name: "common-service", Namespace: "ibm-common-services"},
+			Spec: apiv1alpha1.OperandRequestSpec{
+				Requests: []apiv1alpha1.Request{
+					{
+						Registry: "common-service",
+						Operands: []apiv1alpha1.Operand{{Name: "ibm-etcd-operator"}},
+					},
+				},
+			},
+		}
+	})
+
+	admissionRequest := func(operation admissionv1.Operation, obj, old *apiv1alpha1.OperandRequest) admission.Request {
+		req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: operation}}
+		raw, err := json.Marshal(obj)
+		Expect(err).NotTo(HaveOccurred())
+		req.Object = runtime.RawExtension{Raw: raw}
+		if old != nil {
+			raw, err := json.Marshal(old)
+			Expect(err).NotTo(HaveOccurred())
+			req.OldObject = runtime.RawExtension{Raw: raw}
+		}
+		return req
+	}
+
+	It("Should allow the request of the existing operands", func() {
+		resp := validator.Handle(context.TODO(), admissionRequest(admissionv1.Create, request, nil))
+		Expect(resp.Allowed).To(BeTrue())
+	})
+
+	It("Should reject the request of a missing registry", func() {
+		request.Spec.Requests[0].Registry = "missing"
+		resp := validator.Handle(context.TODO(), admissionRequest(admissionv1.Create, request, nil))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Details.Causes).To(HaveLen(1))
+		Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.requests[0].registry"))
+	})
+
+	It("Should reject the request of a missing operator and list the available ones", func() {
+		request.Spec.Requests[0].Operands = append(request.Spec.Requests[0].Operands, apiv1alpha1.Operand{Name: "ibm-etcd"})
+		resp := validator.Handle(context.TODO(), admissionRequest(admissionv1.Create, request, nil))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Details.Causes).To(HaveLen(1))
+		Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.requests[0].operands[1].name"))
+		Expect(resp.Result.Details.Causes[0].Message).To(ContainSubstring("ibm-etcd-operator, ibm-mongodb-operator"))
+	})
+
+	It("Should reject the request of an operator with a malformed channel", func() {
+		request.Spec.Requests[0].Operands[0].Name = "ibm-mongodb-operator"
+		resp := validator.Handle(context.TODO(), admissionRequest(admissionv1.Create, request, nil))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Message).To(ContainSubstring("stable v1"))
+	})
+
+	It("Should validate the spec against the schema of the CRD", func() {
+		request.Spec.Requests[0].Operands[0].Kind = "EtcdCluster"
+		request.Spec.Requests[0].Operands[0].APIVersion = "etcd.ibm.com/v1"
+		request.Spec.Requests[0].Operands[0].Spec = &runtime.RawExtension{Raw: []byte(`{"size":"three"}`)}
+		resp := validator.Handle(context.TODO(), admissionRequest(admissionv1.Create, request, nil))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.requests[0].operands[0].spec.size"))
+
+		request.Spec.Requests[0].Operands[0].Spec = &runtime.RawExtension{Raw: []byte(`{"size":3}`)}
+		resp = validator.Handle(context.TODO(), admissionRequest(admissionv1.Create, request, nil))
+		Expect(resp.Allowed).To(BeTrue())
+	})
+
+	It("Should validate the size profile against the OperandConfig", func() {
+		request.Spec.Requests[0].Operands[0].SizeProfile = "large"
+		resp := validator.Handle(context.TODO(), admissionRequest(admissionv1.Create, request, nil))
+		Expect(resp.Allowed).To(BeTrue())
+
+		request.Spec.Requests[0].Operands[0].SizeProfile = "medium"
+		resp = validator.Handle(context.TODO(), admissionRequest(admissionv1.Create, request, nil))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Details.Causes).To(HaveLen(1))
+		Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.requests[0].operands[0].sizeProfile"))
+		Expect(resp.Result.Details.Causes[0].Message).To(ContainSubstring("small, large"))
+
+		request.Spec.Requests[0].Operands[0].SizeProfile = "large"
+		request.Spec.Requests[0].Operands[0].Kind = "EtcdBackup"
+		request.Spec.Requests[0].Operands[0].APIVersion = "etcd.ibm.com/v1"
+		resp = validator.Handle(context.TODO(), admissionRequest(admissionv1.Create, request, nil))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.requests[0].operands[0].sizeProfile"))
+	})
+
+	It("Should validate the replicas against the scaling of the service", func() {
+		replicas := int32(3)
+		request.Spec.Requests[0].Operands[0].Replicas = &replicas
+		resp := validator.Handle(context.TODO(), admissionRequest(admissionv1.Create, request, nil))
+		Expect(resp.Allowed).To(BeTrue())
+
+		replicas = 7
+		resp = validator.Handle(context.TODO(), admissionRequest(admissionv1.Create, request, nil))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Details.Causes).To(HaveLen(1))
+		Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.requests[0].operands[0].replicas"))
+		Expect(resp.Result.Details.Causes[0].Message).To(ContainSubstring("maximum 5"))
+
+		replicas = 3
+		request.Spec.Requests[0].Operands = append(request.Spec.Requests[0].Operands, apiv1alpha1.Operand{Name: "ibm-mongodb-operator", Replicas: &replicas})
+		resp = validator.Handle(context.TODO(), admissionRequest(admissionv1.Create, request, nil))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Message).To(ContainSubstring("spec.requests[0].operands[1].replicas"))
+		Expect(resp.Result.Message).To(ContainSubstring("declares no scaling"))
+	})
+
+	It("Should skip the schema validation before the CRD is installed", func() {
+		request.Spec.Requests[0].Operands[0].Kind = "EtcdBackup"
+		request.Spec.Requests[0].Operands[0].APIVersion = "etcd.ibm.com/v1"
+		request.Spec.Requests[0].Operands[0].Spec = &runtime.RawExtension{Raw: []byte(`{"size":"three"}`)}
+		resp := validator.Handle(context.TODO(), admissionRequest(admissionv1.Create, request, nil))
+		Expect(resp.Allowed).To(BeTrue())
+	})
+
+	It("Should validate the patches of the operands", func() {
+		request.Spec.Requests[0].Operands[0].Patches = []apiv1alpha1.PatchOperation{{Op: "remove", Path: "/spec/backup"}}
+		resp := validator.Handle(context.TODO(), admissionRequest(admissionv1.Create, request, nil))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.requests[0].operands[0].patches"))
+		Expect(resp.Result.Details.Causes[0].Message).To(ContainSubstring("set its kind"))
+
+		request.Spec.Requests[0].Operands[0].Kind = "EtcdBackup"
+		request.Spec.Requests[0].Operands[0].APIVersion = "etcd.ibm.com/v1"
+		resp = validator.Handle(context.TODO(), admissionRequest(admissionv1.Create, request, nil))
+		Expect(resp.Allowed).To(BeTrue())
+
+		request.Spec.Requests[0].Operands[0].Patches = []apiv1alpha1.PatchOperation{{Op: "remove", Path: "/metadata/labels"}}
+		resp = validator.Handle(context.TODO(), admissionRequest(admissionv1.Create, request, nil))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Details.Causes[0].Message).To(ContainSubstring("doesn't start with /spec"))
+	})
+
+	It("Should allow the updates keeping the spec and the deletion", func() {
+		request.Spec.Requests[0].Registry = "missing"
+		updated := request.DeepCopy()
+		updated.Finalizers = []string{"finalizer.request.ibm.com"}
+		resp := validator.Handle(context.TODO(), admissionRequest(admissionv1.Update, updated, request))
+		Expect(resp.Allowed).To(BeTrue())
+
+		updated.Spec.Requests[0].Operands = nil
+		now := metav1.Now()
+		updated.DeletionTimestamp = &now
+		resp = validator.Handle(context.TODO(), admissionRequest(admissionv1.Update, updated, request))
+		Expect(resp.Allowed).To(BeTrue())
+	})
+
+	It("Should reject the unknown priority classes", func() {
+		request.Annotations = map[string]string{apiv1alpha1.PriorityAnnotation: "urgent"}
+		resp := validator.Handle(context.TODO(), admissionRequest(admissionv1.Create, request, nil))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Message).To(ContainSubstring(`supported values: "critical", "high", "normal", "low"`))
+
+		// The priority is validated when only the annotation changes
+		updated := request.DeepCopy()
+		request.Annotations = nil
+		resp = validator.Handle(context.TODO(), admissionRequest(admissionv1.Update, updated, request))
+		Expect(resp.Allowed).To(BeFalse())
+		updated.Annotations[apiv1alpha1.PriorityAnnotation] = apiv1alpha1.PriorityCritical
+		resp = validator.Handle(context.TODO(), admissionRequest(admissionv1.Update, updated, request))
+		Expect(resp.Allowed).To(BeTrue())
+	})
+
+	It("Should reject the operators not allowed in the namespace by a ClusterOperandPolicy", func() {
+		policy := &apiv1alpha1.ClusterOperandPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "restricted"},
+			Spec: apiv1alpha1.ClusterOperandPolicySpec{
+				OperatorRules: []apiv1alpha1.OperatorRule{
+					{Operators: []string{"ibm-etcd-operator"}, NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "platform"}}},
+				},
+			},
+		}
+		Expect(validator.Client.Create(context.TODO(), policy)).To(Succeed())
+		resp := validator.Handle(context.TODO(), admissionRequest(admissionv1.Create, request, nil))
+		Expect(resp.Allowed).To(BeTrue())
+
+		policy.Spec.OperatorRules[0].NamespaceSelector.MatchLabels["tier"] = "tenant"
+		Expect(validator.Client.Update(context.TODO(), policy)).To(Succeed())
+		resp = validator.Handle(context.TODO(), admissionRequest(admissionv1.Create, request, nil))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Details.Causes).To(HaveLen(1))
+		Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.requests[0].operands[0].name"))
+		Expect(resp.Result.Details.Causes[0].Message).To(ContainSubstring("ClusterOperandPolicy restricted"))
+	})
+
+	It("Should reject the operands beyond the quota of the namespace", func() {
+		max := int32(1)
+		policy := &apiv1alpha1.ClusterOperandPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "quota"},
+			Spec:       apiv1alpha1.ClusterOperandPolicySpec{MaxOperandsPerNamespace: &max},
+		}
+		Expect(validator.Client.Create(context.TODO(), policy)).To(Succeed())
+		existing := request.DeepCopy()
+		existing.Name = "existing"
+		existing.CreationTimestamp = metav1.Now()
+		existing.Spec.Requests[0].Operands[0].Name = "ibm-mongodb-operator"
+		Expect(validator.Client.Create(context.TODO(), existing)).To(Succeed())
+
+		resp := validator.Handle(context.TODO(), admissionRequest(admissionv1.Create, request, nil))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Details.Causes).To(HaveLen(1))
+		Expect(resp.Result.Details.Causes[0].Message).To(ContainSubstring("allows 1 operands in the namespace ibm-common-services"))
+	})
+})
@@ -0,0 +1,47 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package webhooks contains the admission and conversion webhooks of the ODLM resources
+package webhooks
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+)
+
+// SetupWithManager registers the admission and conversion webhooks to the webhook server of the manager.
+// The server requires the serving certificate in the CertDir of the manager.
+func SetupWithManager(mgr manager.Manager) error {
+	server := mgr.GetWebhookServer()
+	server.Register("/validate-operator-ibm-com-v1alpha1-operandrequest", &webhook.Admission{Handler: &OperandRequestValidator{
+		ODLMOperator: deploy.NewODLMOperator(mgr, "OperandRequestValidator"),
+	}})
+	server.Register("/mutate-operator-ibm-com-v1alpha1-operandrequest", &webhook.Admission{Handler: &OperandRequestDefaulter{
+		ODLMOperator: deploy.NewODLMOperator(mgr, "OperandRequestDefaulter"),
+	}})
+	server.Register("/validate-operator-ibm-com-v1alpha1-operandconfig", &webhook.Admission{Handler: &OperandConfigValidator{
+		ODLMOperator: deploy.NewODLMOperator(mgr, "OperandConfigValidator"),
+	}})
+	server.Register("/validate-operator-ibm-com-v1alpha1-operandregistry", &webhook.Admission{Handler: &OperandRegistryValidator{
+		ODLMOperator: deploy.NewODLMOperator(mgr, "OperandRegistryValidator"),
+	}})
+	// The OperandConfigs are converted between v1alpha1, the storage version, and v1beta2
+	server.Register("/convert", &conversion.Webhook{})
+	return nil
+}
@@ -0,0 +1,215 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package zone renders the zone-aware settings of the operands from the zones of the cluster.
+package zone
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+const (
+	// TopologyKey is the node label of the zone
+	TopologyKey = corev1.LabelTopologyZone
+	// legacyTopologyKey is the deprecated node label of the zone, it is used when a node doesn't have TopologyKey
+	legacyTopologyKey = corev1.LabelFailureDomainBetaZone
+
+	defaultClassAnnotation     = "storageclass.kubernetes.io/is-default-class"
+	betaDefaultClassAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+)
+
+// workloadKinds are the kinds of k8s resources whose pod template is spread across the zones
+var workloadKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"ReplicaSet":  true,
+}
+
+// Detect returns the sorted zones of the nodes
+func Detect(nodes []corev1.Node) []string {
+	found := make(map[string]bool)
+	for _, node := range nodes {
+		labels := node.GetLabels()
+		zone := labels[TopologyKey]
+		if zone == "" {
+			zone = labels[legacyTopologyKey]
+		}
+		if zone != "" {
+			found[zone] = true
+		}
+	}
+	zones := make([]string, 0, len(found))
+	for zone := range found {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	return zones
+}
+
+// SpreadConstraints returns the topologySpreadConstraints spreading the pods with the labels across the zones
+func SpreadConstraints(za *operatorv1alpha1.ZoneAwareness, podLabels map[string]string) []corev1.TopologySpreadConstraint {
+	return []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           za.GetMaxSkew(),
+			TopologyKey:       TopologyKey,
+			WhenUnsatisfiable: za.GetWhenUnsatisfiable(),
+			LabelSelector:     &metav1.LabelSelector{MatchLabels: podLabels},
+		},
+	}
+}
+
+// StorageClassName returns the name of the copy of the storage class pinned to the zone
+func StorageClassName(storageClass, zone string) string {
+	return storageClass + "-" + zone
+}
+
+// StorageClasses returns the copies of the storage class pinned to each zone
+func StorageClasses(base *storagev1.StorageClass, zones []string) []storagev1.StorageClass {
+	waitForFirstConsumer := storagev1.VolumeBindingWaitForFirstConsumer
+	var classes []storagev1.StorageClass
+	for _, zone := range zones {
+		sc := storagev1.StorageClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        StorageClassName(base.Name, zone),
+				Labels:      base.DeepCopy().Labels,
+				Annotations: map[string]string{},
+			},
+			Provisioner:          base.Provisioner,
+			Parameters:           base.DeepCopy().Parameters,
+			ReclaimPolicy:        base.ReclaimPolicy,
+			MountOptions:         base.DeepCopy().MountOptions,
+			AllowVolumeExpansion: base.AllowVolumeExpansion,
+			VolumeBindingMode:    &waitForFirstConsumer,
+			AllowedTopologies: []corev1.TopologySelectorTerm{
+				{
+					MatchLabelExpressions: []corev1.TopologySelectorLabelRequirement{
+						{Key: TopologyKey, Values: []string{zone}},
+					},
+				},
+			},
+		}
+		// The copy must not become the default storage class of the cluster
+		for k, v := range base.GetAnnotations() {
+			if k != defaultClassAnnotation && k != betaDefaultClassAnnotation {
+				sc.Annotations[k] = v
+			}
+		}
+		classes = append(classes, sc)
+	}
+	return classes
+}
+
+// RenderResource renders the topologySpreadConstraints into the pod template of a Deployment, StatefulSet or ReplicaSet.
+// The data of other kinds, and the pod templates which already have topologySpreadConstraints, are returned unchanged.
+func RenderResource(kind string, data []byte, za *operatorv1alpha1.ZoneAwareness) ([]byte, error) {
+	if !workloadKinds[kind] || len(data) == 0 {
+		return data, nil
+	}
+	obj := make(map[string]interface{})
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode the data of the %s", kind)
+	}
+	podLabels, _, err := unstructured.NestedStringMap(obj, "spec", "selector", "matchLabels")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the selector of the %s", kind)
+	}
+	if len(podLabels) == 0 {
+		podLabels, _, err = unstructured.NestedStringMap(obj, "spec", "template", "metadata", "labels")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the pod labels of the %s", kind)
+		}
+	}
+	if len(podLabels) == 0 {
+		return data, nil
+	}
+	changed, err := setSpreadConstraints(obj, []string{"spec", "template", "spec", "topologySpreadConstraints"}, za, podLabels)
+	if err != nil || !changed {
+		return data, err
+	}
+	return json.Marshal(obj)
+}
+
+// RenderCustomResource renders the topologySpreadConstraints and the zone-pinned storage classes into the spec of a custom resource.
+// The fields already set in the spec are not overwritten.
+func RenderCustomResource(kind string, spec []byte, za *operatorv1alpha1.ZoneAwareness, zones []string) ([]byte, error) {
+	var changed bool
+	obj := make(map[string]interface{})
+	if len(spec) != 0 {
+		if err := json.Unmarshal(spec, &obj); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode the spec of the %s", kind)
+		}
+	}
+	for _, res := range za.CustomResources {
+		if !strings.EqualFold(res.Kind, kind) {
+			continue
+		}
+		if res.TopologySpreadConstraintsPath != "" && len(res.PodLabels) != 0 {
+			set, err := setSpreadConstraints(obj, strings.Split(res.TopologySpreadConstraintsPath, "."), za, res.PodLabels)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to render the topologySpreadConstraints of the %s", kind)
+			}
+			changed = changed || set
+		}
+		if res.StorageClassesPath != "" && za.StorageClass != "" {
+			path := strings.Split(res.StorageClassesPath, ".")
+			if _, found, _ := unstructured.NestedFieldNoCopy(obj, path...); !found {
+				var classes []interface{}
+				for _, zone := range zones {
+					classes = append(classes, StorageClassName(za.StorageClass, zone))
+				}
+				if err := unstructured.SetNestedSlice(obj, classes, path...); err != nil {
+					return nil, errors.Wrapf(err, "failed to render the storage classes of the %s", kind)
+				}
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return spec, nil
+	}
+	return json.Marshal(obj)
+}
+
+// setSpreadConstraints sets the topologySpreadConstraints at the path of the object, if they are not set yet
+func setSpreadConstraints(obj map[string]interface{}, path []string, za *operatorv1alpha1.ZoneAwareness, podLabels map[string]string) (bool, error) {
+	if _, found, _ := unstructured.NestedFieldNoCopy(obj, path...); found {
+		return false, nil
+	}
+	var constraints []interface{}
+	for _, c := range SpreadConstraints(za, podLabels) {
+		c := c
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&c)
+		if err != nil {
+			return false, err
+		}
+		constraints = append(constraints, u)
+	}
+	if err := unstructured.SetNestedSlice(obj, constraints, path...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
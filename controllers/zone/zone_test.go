@@ -0,0 +1,148 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package zone
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+func node(labels map[string]string) corev1.Node {
+	return corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: labels}}
+}
+
+func decode(data []byte) map[string]interface{} {
+	obj := make(map[string]interface{})
+	Expect(json.Unmarshal(data, &obj)).Should(Succeed())
+	return obj
+}
+
+var _ = Describe("Zone", func() {
+
+	Context("Detecting the zones", func() {
+		It("Should return the sorted zones of the nodes", func() {
+			zones := Detect([]corev1.Node{
+				node(map[string]string{TopologyKey: "zone-b"}),
+				node(map[string]string{TopologyKey: "zone-a"}),
+				node(map[string]string{legacyTopologyKey: "zone-c"}),
+				node(map[string]string{TopologyKey: "zone-a"}),
+				node(nil),
+			})
+			Expect(zones).Should(Equal([]string{"zone-a", "zone-b", "zone-c"}))
+		})
+	})
+
+	Context("Rendering the k8s resources", func() {
+		za := &operatorv1alpha1.ZoneAwareness{}
+
+		It("Should spread the pods of a Deployment across the zones", func() {
+			data := []byte(`{"spec":{"selector":{"matchLabels":{"app":"foo"}},"template":{"spec":{"containers":[]}}}}`)
+			rendered, err := RenderResource("Deployment", data, za)
+			Expect(err).NotTo(HaveOccurred())
+
+			obj := decode(rendered)
+			constraints := obj["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["topologySpreadConstraints"].([]interface{})
+			Expect(constraints).Should(HaveLen(1))
+			constraint := constraints[0].(map[string]interface{})
+			Expect(constraint["topologyKey"]).Should(Equal(TopologyKey))
+			Expect(constraint["maxSkew"]).Should(BeNumerically("==", 1))
+			Expect(constraint["whenUnsatisfiable"]).Should(Equal(string(corev1.ScheduleAnyway)))
+			Expect(constraint["labelSelector"]).Should(Equal(map[string]interface{}{"matchLabels": map[string]interface{}{"app": "foo"}}))
+		})
+
+		It("Should keep the topologySpreadConstraints already set", func() {
+			data := []byte(`{"spec":{"selector":{"matchLabels":{"app":"foo"}},"template":{"spec":{"topologySpreadConstraints":[]}}}}`)
+			rendered, err := RenderResource("StatefulSet", data, za)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rendered).Should(Equal(data))
+		})
+
+		It("Should not change the other kinds", func() {
+			data := []byte(`{"data":{"foo":"bar"}}`)
+			rendered, err := RenderResource("ConfigMap", data, za)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rendered).Should(Equal(data))
+		})
+	})
+
+	Context("Rendering the custom resources", func() {
+		za := &operatorv1alpha1.ZoneAwareness{
+			MaxSkew:           2,
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+			StorageClass:      "block",
+			CustomResources: []operatorv1alpha1.ZoneAwareResource{
+				{
+					Kind:                          "Database",
+					TopologySpreadConstraintsPath: "pod.topologySpreadConstraints",
+					PodLabels:                     map[string]string{"app": "db"},
+					StorageClassesPath:            "storage.classes",
+				},
+			},
+		}
+
+		It("Should render the spread constraint and the zone-pinned storage classes", func() {
+			rendered, err := RenderCustomResource("database", []byte(`{"replicas":3}`), za, []string{"zone-a", "zone-b"})
+			Expect(err).NotTo(HaveOccurred())
+
+			obj := decode(rendered)
+			Expect(obj["replicas"]).Should(BeNumerically("==", 3))
+			constraint := obj["pod"].(map[string]interface{})["topologySpreadConstraints"].([]interface{})[0].(map[string]interface{})
+			Expect(constraint["maxSkew"]).Should(BeNumerically("==", 2))
+			Expect(constraint["whenUnsatisfiable"]).Should(Equal(string(corev1.DoNotSchedule)))
+			Expect(obj["storage"].(map[string]interface{})["classes"]).Should(Equal([]interface{}{"block-zone-a", "block-zone-b"}))
+		})
+
+		It("Should not change the custom resources of the other kinds", func() {
+			spec := []byte(`{"replicas":3}`)
+			rendered, err := RenderCustomResource("Cache", spec, za, []string{"zone-a", "zone-b"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rendered).Should(Equal(spec))
+		})
+	})
+
+	Context("Pinning the storage classes", func() {
+		It("Should copy the storage class for each zone", func() {
+			reclaim := corev1.PersistentVolumeReclaimRetain
+			base := &storagev1.StorageClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "block",
+					Annotations: map[string]string{defaultClassAnnotation: "true", "foo": "bar"},
+				},
+				Provisioner:   "ebs.csi.aws.com",
+				Parameters:    map[string]string{"type": "gp3"},
+				ReclaimPolicy: &reclaim,
+			}
+
+			classes := StorageClasses(base, []string{"zone-a", "zone-b"})
+			Expect(classes).Should(HaveLen(2))
+			Expect(classes[1].Name).Should(Equal("block-zone-b"))
+			Expect(classes[1].Provisioner).Should(Equal("ebs.csi.aws.com"))
+			Expect(classes[1].Parameters).Should(Equal(map[string]string{"type": "gp3"}))
+			Expect(*classes[1].ReclaimPolicy).Should(Equal(reclaim))
+			Expect(*classes[1].VolumeBindingMode).Should(Equal(storagev1.VolumeBindingWaitForFirstConsumer))
+			Expect(classes[1].Annotations).Should(Equal(map[string]string{"foo": "bar"}))
+			Expect(classes[1].AllowedTopologies[0].MatchLabelExpressions[0].Values).Should(Equal([]string{"zone-b"}))
+		})
+	})
+})
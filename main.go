@@ -17,7 +17,9 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"os"
 	"strings"
 
@@ -26,34 +28,51 @@ import (
 	operatorsv1 "github.com/operator-framework/operator-lifecycle-manager/pkg/package-server/apis/operators/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
-	"k8s.io/klog"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 
 	cache "github.com/IBM/controller-filtered-cache/filteredcache"
 	nssv1 "github.com/IBM/ibm-namespace-scope-operator/api/v1"
 
 	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	operatorv1beta2 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1beta2"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/bundle"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/constant"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/garbagecollector"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/k8sutil"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/namespacescope"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/operandbindinfo"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/operandconfig"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/operandfleetstatus"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/operandregistry"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/operandrequest"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/operandrequestset"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/operandsnapshot"
 	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/operatorchecker"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/operatorconfig"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/shard"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/tenantrequest"
 	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/util"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/webhooks"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/leader"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/throughput"
+
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/tracing"
 	// +kubebuilder:scaffold:imports
 )
 
 var (
-	scheme = runtime.NewScheme()
+	scheme   = runtime.NewScheme()
+	setupLog = logging.Logger("setup")
 )
 
 func init() {
@@ -63,25 +82,59 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(operatorv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(operatorv1beta2.AddToScheme(scheme))
 	utilruntime.Must(operatorsv1.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
 func main() {
-	klog.InitFlags(nil)
-	defer klog.Flush()
+	var logOptions logging.Options
+	logOptions.BindFlags(flag.CommandLine)
+	var throughputOptions throughput.Options
+	throughputOptions.BindFlags(flag.CommandLine)
+	var checkerOptions operatorchecker.Options
+	checkerOptions.BindFlags(flag.CommandLine)
+	var leaderOptions leader.Options
+	leaderOptions.BindFlags(flag.CommandLine)
+	var shardOptions shard.Options
+	shardOptions.BindFlags(flag.CommandLine)
 	var metricsAddr string
 	var probeAddr string
-	var enableLeaderElection bool
+	var enableWebhooks bool
+	var enableTenantRequests bool
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
-	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
-		"Enable leader election for controller manager. "+
-			"Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"Enable the admission webhooks, the serving certificate must be mounted in the certificate directory of the webhook server.")
+	flag.BoolVar(&enableTenantRequests, "enable-tenant-requests", false,
+		"Convert the ConfigMaps labeled with "+constant.TenantRequestLabel+" into OperandRequests, for the tenants not allowed to create them.")
 	var stepSize = flag.Int("batch-chunk-size", 1, "batch-chunk-size is used to control at most how many subscriptions will be created concurrently")
+	var unpackBundleDir = flag.String("unpack-bundle-dir", "", "unpack-bundle-dir is used by the bundle unpack Job to print the manifests in the directory and exit")
 
 	flag.Parse()
 
+	if err := logging.Setup(logOptions, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to set up the logger: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := leaderOptions.Validate(); err != nil {
+		setupLog.Error(err, "invalid leader election options")
+		os.Exit(1)
+	}
+	throughput.Setup(throughputOptions)
+	operatorchecker.Setup(checkerOptions)
+	shard.Setup(shardOptions)
+
+	if *unpackBundleDir != "" {
+		if err := bundle.Unpack(*unpackBundleDir, os.Stdout); err != nil {
+			setupLog.Error(err, "unable to unpack the bundle")
+			os.Exit(1)
+		}
+		return
+	}
+
 	gvkLabelMap := map[schema.GroupVersionKind]cache.Selector{
 		corev1.SchemeGroupVersion.WithKind("Secret"): {
 			LabelSelector: constant.OpbiTypeLabel,
@@ -105,43 +158,107 @@ func main() {
 		MetricsBindAddress:     metricsAddr,
 		HealthProbeBindAddress: probeAddr,
 		Port:                   9443,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "ab89bbb1.ibm.com",
+	}
+	leaderOptions.Configure(&options, util.GetOperatorNamespace())
+
+	restConfig := ctrl.GetConfigOrDie()
+
+	// Export the spans of the reconciles when an OTLP endpoint is set, and propagate them to the API calls
+	shutdownTracing, err := tracing.Setup(context.TODO())
+	if err != nil {
+		setupLog.Error(err, "unable to set up the tracing")
+		os.Exit(1)
+	}
+	if tracing.Enabled() {
+		restConfig.Wrap(tracing.WrapTransport)
+	}
+
+	// The OperatorConfig takes precedence over the environment variables, it is created from them at the first start
+	if operatorNs := util.GetOperatorNamespace(); operatorNs != "" {
+		configClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+		if err != nil {
+			setupLog.Error(err, "unable to create the client to load the OperatorConfig")
+			os.Exit(1)
+		}
+		if err := operatorconfig.Load(context.TODO(), configClient, operatorNs); err != nil {
+			setupLog.Info("unable to load the OperatorConfig, use the environment variables", "error", err.Error())
+		}
+	}
+
+	// The client of the manager and the controllers run with the throughput of the flags or the OperatorConfig
+	throughput.ConfigureClient(restConfig)
+
+	ctx := ctrl.SetupSignalHandler()
+	// ODLM claims its OperandShard before the controllers start, they skip the namespaces of the other shards
+	if shard.Get() != "" {
+		shardClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+		if err != nil {
+			setupLog.Error(err, "unable to create the client to claim the OperandShard")
+			os.Exit(1)
+		}
+		if err := shard.Claim(ctx, shardClient, restConfig, util.GetOperatorNamespace()); err != nil {
+			setupLog.Error(err, "unable to claim the OperandShard", "shard", shard.Get())
+			os.Exit(1)
+		}
 	}
 
 	watchNamespace := util.GetWatchNamespace()
 	isolatedModeEnable := util.GetIsolatedMode()
-	operatorCheckerDisable := util.GetoperatorCheckerMode()
-	options.NewCache = k8sutil.NewODLMCache(isolatedModeEnable, strings.Split(watchNamespace, ","), gvkLabelMap)
+	namespaceSelector, err := k8sutil.NamespaceSelector(util.GetWatchNamespaceSelector())
+	if err != nil {
+		setupLog.Error(err, "unable to parse the label selector of the watched namespaces")
+		os.Exit(1)
+	}
+	options.NewCache = k8sutil.NewODLMCache(isolatedModeEnable, strings.Split(watchNamespace, ","), namespaceSelector, gvkLabelMap)
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
+	mgr, err := ctrl.NewManager(restConfig, options)
 	if err != nil {
-		klog.Errorf("unable to start manager: %v", err)
+		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
+	if shard.Get() != "" {
+		if err = mgr.Add(shard.NewRenewer(mgr.GetClient(), mgr.GetAPIReader(), util.GetOperatorNamespace())); err != nil {
+			setupLog.Error(err, "unable to renew the claim of the OperandShard")
+			os.Exit(1)
+		}
+	}
 	if err = (&operandrequest.Reconciler{
 		ODLMOperator: deploy.NewODLMOperator(mgr, "OperandRequest"),
 		StepSize:     *stepSize,
+		// Without the admission webhooks, the rendered custom resources are validated before they are applied
+		ValidateSchemas: !enableWebhooks,
 	}).SetupWithManager(mgr); err != nil {
-		klog.Errorf("unable to create controller OperandRequest: %v", err)
+		setupLog.Error(err, "unable to create controller OperandRequest")
 		os.Exit(1)
 	}
 	if err = (&operandconfig.Reconciler{
 		ODLMOperator: deploy.NewODLMOperator(mgr, "OperandConfig"),
 	}).SetupWithManager(mgr); err != nil {
-		klog.Errorf("unable to create controller OperandConfig: %v", err)
+		setupLog.Error(err, "unable to create controller OperandConfig")
 		os.Exit(1)
 	}
 	if err = (&operandbindinfo.Reconciler{
 		ODLMOperator: deploy.NewODLMOperator(mgr, "OperandBindInfo"),
 	}).SetupWithManager(mgr); err != nil {
-		klog.Errorf("unable to create controller OperandBindInfo: %v", err)
+		setupLog.Error(err, "unable to create controller OperandBindInfo")
 		os.Exit(1)
 	}
 	if err = (&operandregistry.Reconciler{
 		ODLMOperator: deploy.NewODLMOperator(mgr, "OperandRegistry"),
 	}).SetupWithManager(mgr); err != nil {
-		klog.Errorf("unable to create controller OperandRegistry: %v", err)
+		setupLog.Error(err, "unable to create controller OperandRegistry")
+		os.Exit(1)
+	}
+	if err = (&operandsnapshot.Reconciler{
+		ODLMOperator: deploy.NewODLMOperator(mgr, "OperandSnapshot"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller OperandSnapshot")
+		os.Exit(1)
+	}
+	if err = (&operatorconfig.Reconciler{
+		ODLMOperator: deploy.NewODLMOperator(mgr, "OperatorConfig"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller OperatorConfig")
 		os.Exit(1)
 	}
 	// Single instance case, disable it on SaaS or on-prem multi instances case
@@ -149,34 +266,75 @@ func main() {
 		if err = (&namespacescope.Reconciler{
 			ODLMOperator: deploy.NewODLMOperator(mgr, "NamespaceScope"),
 		}).SetupWithManager(mgr); err != nil {
-			klog.Errorf("unable to create controller NamespaceScope: %v", err)
+			setupLog.Error(err, "unable to create controller NamespaceScope")
 			os.Exit(1)
 		}
-	}
-	if false {
-		if !operatorCheckerDisable {
-			if err = (&operatorchecker.Reconciler{
-				ODLMOperator: deploy.NewODLMOperator(mgr, "OperatorChecker"),
-			}).SetupWithManager(mgr); err != nil {
-				klog.Errorf("unable to create controller OperatorChecker: %v", err)
+		// The OperandFleetStatus is cluster scoped, it rolls up the ODLM resources of all the namespaces
+		if err = (&operandfleetstatus.Reconciler{
+			ODLMOperator: deploy.NewODLMOperator(mgr, "OperandFleetStatus"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller OperandFleetStatus")
+			os.Exit(1)
+		}
+		// The OperandRequestSet is cluster scoped, it creates OperandRequests in the namespaces it selects
+		if err = (&operandrequestset.Reconciler{
+			ODLMOperator: deploy.NewODLMOperator(mgr, "OperandRequestSet"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller OperandRequestSet")
+			os.Exit(1)
+		}
+		// The orphaned Subscriptions are found from the OperandRequests of all the namespaces,
+		// they are collected by the shard reconciling the cluster scoped resources
+		if shard.Primary() {
+			if err = mgr.Add(garbagecollector.NewCollector(deploy.NewODLMOperator(mgr, "GarbageCollector"))); err != nil {
+				setupLog.Error(err, "unable to create the garbage collector")
 				os.Exit(1)
 			}
 		}
 	}
+	// The operator checker skips the Subscriptions while it is disabled by the OperatorConfig
+	if err = (&operatorchecker.Reconciler{
+		ODLMOperator: deploy.NewODLMOperator(mgr, "OperatorChecker"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller OperatorChecker")
+		os.Exit(1)
+	}
+	if enableTenantRequests {
+		if err = (&tenantrequest.Reconciler{
+			ODLMOperator: deploy.NewODLMOperator(mgr, "TenantRequest"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller TenantRequest")
+			os.Exit(1)
+		}
+	}
+	if enableWebhooks {
+		if err = webhooks.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create the admission webhooks")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("health", healthz.Ping); err != nil {
-		klog.Errorf("unable to set up health check: %v", err)
+		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
 	if err := mgr.AddReadyzCheck("check", healthz.Ping); err != nil {
-		klog.Errorf("unable to set up ready check: %v", err)
+		setupLog.Error(err, "unable to set up ready check")
+		os.Exit(1)
+	}
+	// A replica is ready when its caches are synced, and it is the leader or follows a live leader
+	if err := leader.AddChecks(mgr, leader.NewReadiness(mgr, util.GetOperatorNamespace())); err != nil {
+		setupLog.Error(err, "unable to set up the leader ready checks")
 		os.Exit(1)
 	}
 
-	klog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
-		klog.Errorf("problem running manager: %v", err)
+	setupLog.Info("starting manager")
+	if err := mgr.Start(ctx); err != nil {
+		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+	if err := shutdownTracing(context.TODO()); err != nil {
+		setupLog.Error(err, "unable to flush the spans")
+	}
 }
@@ -0,0 +1,278 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package benchmark measures the throughput of the ODLM controllers under a synthetic load.
+//
+// It creates OperandRegistries, OperandConfigs and OperandRequests against envtest or a cluster,
+// and measures how fast the OperandRequests are reconciled and how many API calls the controllers make,
+// so the performance regressions are caught before a release.
+package benchmark
+
+import (
+	"context"
+	"flag"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+var log = logging.Logger("benchmark")
+
+// Options are the options of a benchmark run set by the flags of the command
+type Options struct {
+	// Namespace is the namespace of the synthetic resources
+	Namespace string
+	// Prefix is the name prefix of the synthetic resources
+	Prefix string
+	// Registries is the number of the OperandRegistries and OperandConfigs
+	Registries int
+	// Operators is the number of the operators in each OperandRegistry
+	Operators int
+	// Requests is the number of the OperandRequests, spread across the OperandRegistries
+	Requests int
+	// Workers is the number of the resources created in parallel
+	Workers int
+	// Phase is the phase an OperandRequest is reconciled in, any phase when it is empty
+	Phase string
+	// Timeout is how long the OperandRequests are waited for
+	Timeout time.Duration
+	// Cleanup deletes the synthetic resources at the end of the run
+	Cleanup bool
+}
+
+// BindFlags binds the options to the flags
+func (o *Options) BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Namespace, "namespace", "odlm-benchmark", "The namespace of the synthetic resources.")
+	fs.StringVar(&o.Prefix, "prefix", "bench", "The name prefix of the synthetic resources.")
+	fs.IntVar(&o.Registries, "registries", 1, "The number of the OperandRegistries and OperandConfigs.")
+	fs.IntVar(&o.Operators, "operators", 2, "The number of the operators in each OperandRegistry.")
+	fs.IntVar(&o.Requests, "requests", 100, "The number of the OperandRequests.")
+	fs.IntVar(&o.Workers, "workers", 10, "The number of the resources created in parallel.")
+	fs.StringVar(&o.Phase, "phase", "", "The phase an OperandRequest is reconciled in, any phase when it is empty.")
+	fs.DurationVar(&o.Timeout, "timeout", 10*time.Minute, "How long the OperandRequests are waited for.")
+	fs.BoolVar(&o.Cleanup, "cleanup", true, "Delete the synthetic resources at the end of the run.")
+}
+
+// Validate checks the options
+func (o Options) Validate() error {
+	if o.Namespace == "" || o.Prefix == "" {
+		return errors.New("the namespace and the prefix of the synthetic resources are required")
+	}
+	if o.Registries < 1 || o.Operators < 1 || o.Requests < 1 || o.Workers < 1 {
+		return errors.New("the numbers of registries, operators, requests and workers must be positive")
+	}
+	return nil
+}
+
+// Run creates the synthetic load, waits for the OperandRequests to be reconciled by the controllers,
+// and returns the measures. The API calls are counted by the counter, if any, wrapping the client of the controllers.
+// The OperandRequests not reconciled before the timeout are reported, they don't fail the run.
+func Run(ctx context.Context, cfg *rest.Config, scheme *runtime.Scheme, opts Options, counter *APICounter) (*Result, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the client of the benchmark")
+	}
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: opts.Namespace}}
+	if err := c.Create(ctx, ns); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, errors.Wrapf(err, "failed to create the namespace %s", opts.Namespace)
+	}
+
+	registries, configs, requests := Load(opts)
+	if opts.Cleanup {
+		defer cleanup(c, opts, registries, configs, requests)
+	}
+
+	tracker, err := newTracker(ctx, cfg, scheme, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// The OperandRegistries and OperandConfigs are created before the measure starts
+	if err := createAll(ctx, c, opts.Workers, registries, configs); err != nil {
+		return nil, err
+	}
+
+	counter.Reset()
+	start := time.Now()
+	if err := createAll(ctx, c, opts.Workers, requests, nil, tracker.created); err != nil {
+		return nil, err
+	}
+	log.Info("Created the synthetic OperandRequests", "requests", len(requests))
+
+	err = wait.PollImmediate(time.Second, opts.Timeout, func() (bool, error) {
+		return tracker.reconciledCount() == len(requests), nil
+	})
+	if err != nil && err != wait.ErrWaitTimeout {
+		return nil, err
+	}
+	// The run lasts until the last OperandRequest is reconciled, or until the timeout
+	end := tracker.lastReconciled()
+	if tracker.reconciledCount() < len(requests) {
+		end = time.Now()
+	}
+	return newResult(end.Sub(start), tracker.latencies(), len(requests), counter.Counts()), nil
+}
+
+// createAll creates the objects with the workers, and calls the callbacks with each created object
+func createAll(ctx context.Context, c client.Client, workers int, objects, more []client.Object, callbacks ...func(client.Object)) error {
+	objects = append(append([]client.Object{}, objects...), more...)
+	queue := make(chan client.Object)
+	errs := make(chan error, len(objects))
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for obj := range queue {
+				for _, callback := range callbacks {
+					callback(obj)
+				}
+				if err := c.Create(ctx, obj); err != nil && !apierrors.IsAlreadyExists(err) {
+					errs <- errors.Wrapf(err, "failed to create the synthetic resource %s/%s", obj.GetNamespace(), obj.GetName())
+				}
+			}
+		}()
+	}
+	for _, obj := range objects {
+		queue <- obj
+	}
+	close(queue)
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
+
+// cleanup deletes the synthetic resources, the OperandRequests first so that ODLM can clean up after them
+func cleanup(c client.Client, opts Options, groups ...[]client.Object) {
+	ctx := context.Background()
+	for i := len(groups) - 1; i >= 0; i-- {
+		for _, obj := range groups[i] {
+			if err := c.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+				log.Error(err, "Failed to delete the synthetic resource", "name", opts.Namespace+"/"+obj.GetName())
+			}
+		}
+	}
+}
+
+// tracker records when the OperandRequests are created and first reconciled
+type tracker struct {
+	phase string
+
+	mu           sync.Mutex
+	createdAt    map[types.NamespacedName]time.Time
+	reconciledAt map[types.NamespacedName]time.Time
+}
+
+// newTracker starts an informer on the OperandRequests of the namespace to observe their phases
+func newTracker(ctx context.Context, cfg *rest.Config, scheme *runtime.Scheme, opts Options) (*tracker, error) {
+	t := &tracker{
+		phase:        opts.Phase,
+		createdAt:    make(map[types.NamespacedName]time.Time),
+		reconciledAt: make(map[types.NamespacedName]time.Time),
+	}
+	c, err := cache.New(cfg, cache.Options{Scheme: scheme, Namespace: opts.Namespace})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the cache of the benchmark")
+	}
+	informer, err := c.GetInformer(ctx, &operatorv1alpha1.OperandRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to watch the OperandRequests")
+	}
+	informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    t.observe,
+		UpdateFunc: func(_, obj interface{}) { t.observe(obj) },
+	})
+	go func() {
+		if err := c.Start(ctx); err != nil {
+			log.Error(err, "Failed to start the cache of the benchmark")
+		}
+	}()
+	if !c.WaitForCacheSync(ctx) {
+		return nil, errors.New("failed to sync the cache of the benchmark")
+	}
+	return t, nil
+}
+
+// created records the creation time of an OperandRequest
+func (t *tracker) created(obj client.Object) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.createdAt[client.ObjectKeyFromObject(obj)] = time.Now()
+}
+
+// observe records when an OperandRequest is first seen in the expected phase
+func (t *tracker) observe(obj interface{}) {
+	req, ok := obj.(*operatorv1alpha1.OperandRequest)
+	if !ok || req.Status.Phase == "" || (t.phase != "" && string(req.Status.Phase) != t.phase) {
+		return
+	}
+	key := client.ObjectKeyFromObject(req)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, created := t.createdAt[key]
+	if _, done := t.reconciledAt[key]; !created || done {
+		return
+	}
+	t.reconciledAt[key] = time.Now()
+}
+
+func (t *tracker) reconciledCount() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.reconciledAt)
+}
+
+// lastReconciled returns when the last OperandRequest was reconciled
+func (t *tracker) lastReconciled() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var last time.Time
+	for _, reconciledAt := range t.reconciledAt {
+		if reconciledAt.After(last) {
+			last = reconciledAt
+		}
+	}
+	return last
+}
+
+// latencies returns the time from the creation to the reconcile of each reconciled OperandRequest
+func (t *tracker) latencies() []time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	latencies := make([]time.Duration, 0, len(t.reconciledAt))
+	for key, reconciledAt := range t.reconciledAt {
+		latencies = append(latencies, reconciledAt.Sub(t.createdAt[key]))
+	}
+	return latencies
+}
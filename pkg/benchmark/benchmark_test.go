@@ -0,0 +1,126 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package benchmark
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Benchmark", func() {
+
+	Context("Generate the synthetic load", func() {
+		It("Should spread the OperandRequests across the OperandRegistries", func() {
+			opts := Options{Namespace: "bench-ns", Prefix: "bench", Registries: 2, Operators: 3, Requests: 5, Workers: 1}
+			Expect(opts.Validate()).To(Succeed())
+
+			registries, configs, requests := Load(opts)
+			Expect(registries).To(HaveLen(2))
+			Expect(configs).To(HaveLen(2))
+			Expect(requests).To(HaveLen(5))
+
+			registry := registries[1].(*operatorv1alpha1.OperandRegistry)
+			Expect(registry.Name).To(Equal("bench-1"))
+			Expect(registry.Labels).To(HaveKeyWithValue(RunLabel, "bench"))
+			Expect(registry.Spec.Operators).To(HaveLen(3))
+			Expect(configs[1].(*operatorv1alpha1.OperandConfig).Spec.Services).To(HaveLen(3))
+
+			request := requests[3].(*operatorv1alpha1.OperandRequest)
+			Expect(request.Name).To(Equal("bench-request-3"))
+			Expect(request.Spec.Requests).To(HaveLen(1))
+			Expect(request.Spec.Requests[0].Registry).To(Equal("bench-1"))
+			Expect(request.Spec.Requests[0].Operands).To(HaveLen(3))
+		})
+
+		It("Should reject the options without load", func() {
+			Expect(Options{Namespace: "bench-ns", Prefix: "bench", Registries: 1, Operators: 1, Workers: 1}.Validate()).NotTo(Succeed())
+		})
+	})
+
+	Context("Count the API calls", func() {
+		It("Should count the requests by verb and resource", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+			defer server.Close()
+
+			counter := NewAPICounter()
+			c := &http.Client{Transport: counter.Wrap(http.DefaultTransport)}
+			for _, call := range []struct{ method, path string }{
+				{http.MethodGet, "/apis/operator.ibm.com/v1alpha1/namespaces/ns/operandrequests"},
+				{http.MethodGet, "/apis/operator.ibm.com/v1alpha1/namespaces/ns/operandrequests?watch=true"},
+				{http.MethodGet, "/apis/operator.ibm.com/v1alpha1/namespaces/ns/operandrequests/example"},
+				{http.MethodPut, "/apis/operator.ibm.com/v1alpha1/namespaces/ns/operandrequests/example/status"},
+				{http.MethodPost, "/apis/operators.coreos.com/v1alpha1/namespaces/ns/subscriptions"},
+				{http.MethodPatch, "/api/v1/namespaces/ns/configmaps/example"},
+				{http.MethodGet, "/api/v1/namespaces/ns"},
+				{http.MethodGet, "/apis/operator.ibm.com/v1alpha1/operandrequests"},
+			} {
+				req, err := http.NewRequest(call.method, server.URL+call.path, nil)
+				Expect(err).NotTo(HaveOccurred())
+				resp, err := c.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+				resp.Body.Close()
+			}
+
+			Expect(counter.Counts()).To(Equal(map[string]int{
+				"list operandrequests":          2,
+				"watch operandrequests":         1,
+				"get operandrequests":           1,
+				"update operandrequests/status": 1,
+				"create subscriptions":          1,
+				"patch configmaps":              1,
+				"get namespaces":                1,
+			}))
+
+			counter.Reset()
+			Expect(counter.Counts()).To(BeEmpty())
+		})
+	})
+
+	Context("Report the measures", func() {
+		latencies := func(seconds ...int) []time.Duration {
+			var durations []time.Duration
+			for _, s := range seconds {
+				durations = append(durations, time.Duration(s)*time.Second)
+			}
+			return durations
+		}
+
+		It("Should compute the throughput and the latency percentiles", func() {
+			result := newResult(10*time.Second, latencies(10, 1, 9, 2, 8, 3, 7, 4, 6, 5), 10, map[string]int{"list operandrequests": 5, "get operandrequests": 15})
+
+			Expect(result.Reconciled).To(Equal(10))
+			Expect(result.Throughput).To(Equal(1.0))
+			Expect(result.Latency).To(Equal(Latency{P50: 5, P90: 9, P99: 10, Max: 10}))
+			Expect(result.TotalAPICalls()).To(Equal(20))
+		})
+
+		It("Should report the regressions from the baseline", func() {
+			baseline := newResult(10*time.Second, latencies(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), 10, map[string]int{"get operandrequests": 20})
+
+			Expect(newResult(11*time.Second, latencies(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), 10, map[string]int{"get operandrequests": 22}).Compare(baseline, 0.2)).To(BeEmpty())
+
+			regressions := newResult(20*time.Second, latencies(2, 4, 6, 8, 10, 12, 14, 16, 18), 10, map[string]int{"get operandrequests": 40}).Compare(baseline, 0.2)
+			Expect(regressions).To(HaveLen(4))
+		})
+	})
+})
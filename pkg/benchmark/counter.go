@@ -0,0 +1,126 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package benchmark
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// APICounter counts the requests sent to the API server by verb and resource, like "list operandrequests"
+type APICounter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// NewAPICounter returns an empty API counter
+func NewAPICounter() *APICounter {
+	return &APICounter{counts: make(map[string]int)}
+}
+
+// Wrap wraps the transport of a client to count its requests, it is used with rest.Config.Wrap
+func (c *APICounter) Wrap(rt http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		c.add(requestKey(req))
+		return rt.RoundTrip(req)
+	})
+}
+
+// Reset clears the counts
+func (c *APICounter) Reset() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts = make(map[string]int)
+}
+
+// Counts returns a copy of the counts
+func (c *APICounter) Counts() map[string]int {
+	counts := make(map[string]int)
+	if c == nil {
+		return counts
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, count := range c.counts {
+		counts[key] = count
+	}
+	return counts
+}
+
+func (c *APICounter) add(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[key]++
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// requestKey returns the verb and the resource of a request to the API server,
+// the paths are /api/<version>/... or /apis/<group>/<version>/..., optionally followed by namespaces/<namespace>,
+// then <resource>[/<name>[/<subresource>]]
+func requestKey(req *http.Request) string {
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	switch {
+	case len(parts) > 1 && parts[0] == "api":
+		parts = parts[2:]
+	case len(parts) > 2 && parts[0] == "apis":
+		parts = parts[3:]
+	default:
+		return strings.ToLower(req.Method) + " " + req.URL.Path
+	}
+	if len(parts) > 2 && parts[0] == "namespaces" {
+		parts = parts[2:]
+	}
+	if len(parts) == 0 {
+		return strings.ToLower(req.Method) + " " + req.URL.Path
+	}
+
+	resource, named := parts[0], len(parts) > 1
+	if len(parts) > 2 {
+		resource += "/" + parts[2]
+	}
+
+	verb := strings.ToLower(req.Method)
+	switch req.Method {
+	case http.MethodGet:
+		switch {
+		case req.URL.Query().Get("watch") == "true":
+			verb = "watch"
+		case named:
+			verb = "get"
+		default:
+			verb = "list"
+		}
+	case http.MethodPost:
+		verb = "create"
+	case http.MethodPut:
+		verb = "update"
+	case http.MethodDelete:
+		if !named {
+			verb = "deletecollection"
+		}
+	}
+	return verb + " " + resource
+}
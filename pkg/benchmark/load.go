@@ -0,0 +1,88 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package benchmark
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+// RunLabel is the label of the synthetic resources created by the benchmark
+const RunLabel = "operator.ibm.com/odlm-benchmark"
+
+// Load returns the synthetic OperandRegistries, OperandConfigs and OperandRequests of the options.
+// Each OperandRegistry has the same number of operators, and the OperandRequests are spread across them
+// and request all their operators.
+func Load(opts Options) (registries, configs, requests []client.Object) {
+	for i := 0; i < opts.Registries; i++ {
+		name := fmt.Sprintf("%s-%d", opts.Prefix, i)
+		registry := &operatorv1alpha1.OperandRegistry{ObjectMeta: objectMeta(name, opts)}
+		config := &operatorv1alpha1.OperandConfig{ObjectMeta: objectMeta(name, opts)}
+		for j := 0; j < opts.Operators; j++ {
+			operator := operatorName(opts, j)
+			registry.Spec.Operators = append(registry.Spec.Operators, operatorv1alpha1.Operator{
+				Name:            operator,
+				Namespace:       opts.Namespace,
+				SourceName:      opts.Prefix + "-catalog",
+				SourceNamespace: opts.Namespace,
+				PackageName:     operator,
+				Channel:         "v1",
+			})
+			config.Spec.Services = append(config.Spec.Services, operatorv1alpha1.ConfigService{
+				Name: operator,
+				Spec: map[string]runtime.RawExtension{
+					"benchmark": {Raw: []byte(fmt.Sprintf(`{"replicas":%d}`, j+1))},
+				},
+			})
+		}
+		registries = append(registries, registry)
+		configs = append(configs, config)
+	}
+
+	for i := 0; i < opts.Requests; i++ {
+		registry := fmt.Sprintf("%s-%d", opts.Prefix, i%opts.Registries)
+		request := &operatorv1alpha1.OperandRequest{ObjectMeta: objectMeta(fmt.Sprintf("%s-request-%d", opts.Prefix, i), opts)}
+		operands := make([]operatorv1alpha1.Operand, 0, opts.Operators)
+		for j := 0; j < opts.Operators; j++ {
+			operands = append(operands, operatorv1alpha1.Operand{Name: operatorName(opts, j)})
+		}
+		request.Spec.Requests = []operatorv1alpha1.Request{{
+			Registry:          registry,
+			RegistryNamespace: opts.Namespace,
+			Operands:          operands,
+		}}
+		requests = append(requests, request)
+	}
+	return registries, configs, requests
+}
+
+func objectMeta(name string, opts Options) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: opts.Namespace,
+		Labels:    map[string]string{RunLabel: opts.Prefix},
+	}
+}
+
+func operatorName(opts Options, index int) string {
+	return fmt.Sprintf("%s-operator-%d", opts.Prefix, index)
+}
@@ -0,0 +1,132 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package benchmark
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Latency are the percentiles of the time, in seconds, from the creation of the OperandRequests to their reconcile
+type Latency struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+// Result are the measures of a benchmark run
+type Result struct {
+	// Requests is the number of the OperandRequests created
+	Requests int `json:"requests"`
+	// Reconciled is the number of the OperandRequests reconciled before the timeout
+	Reconciled int `json:"reconciled"`
+	// Duration is the time, in seconds, until the last OperandRequest was reconciled
+	Duration float64 `json:"duration"`
+	// Throughput is the number of the OperandRequests reconciled per second
+	Throughput float64 `json:"throughput"`
+	// Latency are the percentiles of the reconcile latency
+	Latency Latency `json:"latency"`
+	// APICalls are the API calls of the controllers by verb and resource
+	APICalls map[string]int `json:"apiCalls"`
+}
+
+// newResult computes the measures from the latencies of the reconciled OperandRequests and the duration of the run
+func newResult(duration time.Duration, latencies []time.Duration, requests int, apiCalls map[string]int) *Result {
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	result := &Result{
+		Requests:   requests,
+		Reconciled: len(latencies),
+		Duration:   duration.Seconds(),
+		APICalls:   apiCalls,
+	}
+	if len(latencies) == 0 || duration <= 0 {
+		return result
+	}
+	result.Throughput = float64(result.Reconciled) / result.Duration
+	result.Latency = Latency{
+		P50: percentile(latencies, 50).Seconds(),
+		P90: percentile(latencies, 90).Seconds(),
+		P99: percentile(latencies, 99).Seconds(),
+		Max: latencies[len(latencies)-1].Seconds(),
+	}
+	return result
+}
+
+// percentile returns the nearest-rank percentile of the sorted durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// TotalAPICalls returns the number of the API calls of the controllers
+func (r *Result) TotalAPICalls() int {
+	total := 0
+	for _, count := range r.APICalls {
+		total += count
+	}
+	return total
+}
+
+// Print writes the measures for humans
+func (r *Result) Print(w io.Writer) {
+	fmt.Fprintf(w, "OperandRequests reconciled: %d/%d\n", r.Reconciled, r.Requests)
+	fmt.Fprintf(w, "Duration:                   %.2fs\n", r.Duration)
+	fmt.Fprintf(w, "Throughput:                 %.2f OperandRequests/s\n", r.Throughput)
+	fmt.Fprintf(w, "Latency:                    p50 %.2fs, p90 %.2fs, p99 %.2fs, max %.2fs\n", r.Latency.P50, r.Latency.P90, r.Latency.P99, r.Latency.Max)
+	fmt.Fprintf(w, "API calls:                  %d\n", r.TotalAPICalls())
+	keys := make([]string, 0, len(r.APICalls))
+	for key := range r.APICalls {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "  %-50s %d\n", key, r.APICalls[key])
+	}
+}
+
+// Compare returns the regressions of the result from a baseline result, when the throughput is lower,
+// or the p90 latency or the API calls per OperandRequest are higher, by more than the tolerance, like 0.2 for 20%
+func (r *Result) Compare(baseline *Result, tolerance float64) []string {
+	var regressions []string
+	if r.Reconciled < r.Requests {
+		regressions = append(regressions, fmt.Sprintf("only %d of the %d OperandRequests were reconciled", r.Reconciled, r.Requests))
+	}
+	if baseline.Throughput > 0 && r.Throughput < baseline.Throughput*(1-tolerance) {
+		regressions = append(regressions, fmt.Sprintf("the throughput dropped from %.2f to %.2f OperandRequests/s", baseline.Throughput, r.Throughput))
+	}
+	if baseline.Latency.P90 > 0 && r.Latency.P90 > baseline.Latency.P90*(1+tolerance) {
+		regressions = append(regressions, fmt.Sprintf("the p90 latency rose from %.2fs to %.2fs", baseline.Latency.P90, r.Latency.P90))
+	}
+	baselineCalls, calls := perRequest(baseline.TotalAPICalls(), baseline.Requests), perRequest(r.TotalAPICalls(), r.Requests)
+	if baselineCalls > 0 && calls > baselineCalls*(1+tolerance) {
+		regressions = append(regressions, fmt.Sprintf("the API calls per OperandRequest rose from %.1f to %.1f", baselineCalls, calls))
+	}
+	return regressions
+}
+
+func perRequest(count, requests int) float64 {
+	if requests == 0 {
+		return 0
+	}
+	return float64(count) / float64(requests)
+}
@@ -0,0 +1,163 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package inspect reads an OperandRequest and the resources ODLM created for it, to troubleshoot ODLM
+// without its logs. The operands are resolved the same way as the OperandRequest controller resolves them,
+// and nothing is changed in the cluster.
+package inspect
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+)
+
+// Inspector writes the reports on the OperandRequests to Out
+type Inspector struct {
+	*deploy.ODLMOperator
+	Out io.Writer
+}
+
+// New returns an Inspector reading the cluster with the client
+func New(c client.Client, scheme *runtime.Scheme, out io.Writer) *Inspector {
+	return &Inspector{
+		ODLMOperator: &deploy.ODLMOperator{Client: c, Reader: c, Scheme: scheme},
+		Out:          out,
+	}
+}
+
+// Status writes the phase of the OperandRequest, the phases of its members, its conditions,
+// the fields drifted from the configuration and the last actions of ODLM
+func (i *Inspector) Status(ctx context.Context, key types.NamespacedName) error {
+	req, err := i.getRequest(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(i.Out, "OperandRequest:\t%s\n", key)
+	fmt.Fprintf(i.Out, "Phase:\t%s\n", phaseOf(string(req.Status.Phase)))
+	fmt.Fprintf(i.Out, "Prune policy:\t%s\n", req.GetPrunePolicy())
+
+	fmt.Fprintln(i.Out)
+	w := tabwriter.NewWriter(i.Out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "MEMBER\tOPERATOR\tOPERAND\tCUSTOM RESOURCES")
+	for _, m := range req.Status.Members {
+		crs := make([]string, 0, len(m.OperandCRList))
+		for _, cr := range m.OperandCRList {
+			crs = append(crs, cr.Kind+"/"+cr.Name)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", m.Name, phaseOf(string(m.Phase.OperatorPhase)), phaseOf(string(m.Phase.OperandPhase)), strings.Join(crs, ","))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(req.Status.Conditions) != 0 {
+		fmt.Fprintln(i.Out, "\nConditions:")
+		w = tabwriter.NewWriter(i.Out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "  TYPE\tSTATUS\tREASON\tMESSAGE")
+		for _, c := range req.Status.Conditions {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", c.Type, c.Status, c.Reason, c.Message)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(req.Status.DriftDiff) != 0 {
+		fmt.Fprintln(i.Out, "\nDrifted fields:")
+		for _, res := range req.Status.DriftDiff {
+			fmt.Fprintf(i.Out, "  %s %s/%s (%s)\n", res.Kind, res.Namespace, res.Name, res.Operand)
+			for _, f := range res.Fields {
+				fmt.Fprintf(i.Out, "    %s: desired %s, live %s\n", f.Path, valueOf(f.Desired), valueOf(f.Live))
+			}
+		}
+	}
+
+	if len(req.Status.History) != 0 {
+		fmt.Fprintln(i.Out, "\nHistory:")
+		w = tabwriter.NewWriter(i.Out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "  TIME\tACTION\tOPERATOR\tRESOURCE\tCHANGE\tMESSAGE")
+		for _, h := range req.Status.History {
+			change := ""
+			if h.From != "" || h.To != "" {
+				change = valueOf(h.From) + " -> " + valueOf(h.To)
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t%s\n", h.Time, h.Action, h.Operator, h.Resource, change, h.Message)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getRequest gets the OperandRequest with the defaults of the controller
+func (i *Inspector) getRequest(ctx context.Context, key types.NamespacedName) (*operatorv1alpha1.OperandRequest, error) {
+	req, err := i.GetOperandRequest(ctx, key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the OperandRequest %s", key)
+	}
+	return req, nil
+}
+
+// getRegistry gets the OperandRegistry as it is, the catalog sources of its operators aren't resolved.
+// An empty OperandRegistry is returned when it doesn't exist.
+func (i *Inspector) getRegistry(ctx context.Context, key types.NamespacedName) (*operatorv1alpha1.OperandRegistry, bool, error) {
+	registry := &operatorv1alpha1.OperandRegistry{}
+	if err := i.Client.Get(ctx, key, registry); err != nil {
+		if apierrors.IsNotFound(err) {
+			return &operatorv1alpha1.OperandRegistry{}, false, nil
+		}
+		return nil, false, errors.Wrapf(err, "failed to get the OperandRegistry %s", key)
+	}
+	return registry, true, nil
+}
+
+// getMember returns the status of the member of the OperandRequest, nil when it has no status
+func getMember(req *operatorv1alpha1.OperandRequest, name string) *operatorv1alpha1.MemberStatus {
+	for i := range req.Status.Members {
+		if req.Status.Members[i].Name == name {
+			return &req.Status.Members[i]
+		}
+	}
+	return nil
+}
+
+func phaseOf(phase string) string {
+	if phase == "" {
+		return "-"
+	}
+	return phase
+}
+
+func valueOf(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
@@ -0,0 +1,266 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package inspect
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/ledger"
+)
+
+const (
+	registryNs = "ibm-common-services"
+	requestNs  = "team-a"
+)
+
+func registry() *operatorv1alpha1.OperandRegistry {
+	return &operatorv1alpha1.OperandRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "common-service", Namespace: registryNs},
+		Spec: operatorv1alpha1.OperandRegistrySpec{
+			Operators: []operatorv1alpha1.Operator{
+				{Name: "etcd", Namespace: registryNs, PackageName: "etcd", Channel: "stable", SourceName: "community-operators", SourceNamespace: "olm"},
+				{Name: "jenkins", Namespace: registryNs, PackageName: "jenkins", Channel: "alpha", SourceName: "community-operators", SourceNamespace: "olm"},
+			},
+		},
+	}
+}
+
+func config() *operatorv1alpha1.OperandConfig {
+	return &operatorv1alpha1.OperandConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "common-service", Namespace: registryNs},
+		Spec: operatorv1alpha1.OperandConfigSpec{
+			Services: []operatorv1alpha1.ConfigService{
+				{Name: "etcd", Spec: map[string]runtime.RawExtension{"etcdCluster": {Raw: []byte(`{"size":3}`)}}},
+			},
+		},
+	}
+}
+
+func request() *operatorv1alpha1.OperandRequest {
+	return &operatorv1alpha1.OperandRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-request", Namespace: requestNs},
+		Spec: operatorv1alpha1.OperandRequestSpec{
+			Requests: []operatorv1alpha1.Request{
+				{Registry: "common-service", RegistryNamespace: registryNs, Operands: []operatorv1alpha1.Operand{{Name: "etcd"}}},
+			},
+		},
+		Status: operatorv1alpha1.OperandRequestStatus{
+			Phase: operatorv1alpha1.ClusterPhaseRunning,
+			Members: []operatorv1alpha1.MemberStatus{
+				{
+					Name:          "etcd",
+					Phase:         operatorv1alpha1.MemberPhase{OperatorPhase: operatorv1alpha1.OperatorRunning, OperandPhase: operatorv1alpha1.ServiceRunning},
+					OperandCRList: []operatorv1alpha1.OperandCRMember{{Name: "example", Kind: "EtcdCluster", APIVersion: "etcd.database.coreos.com/v1beta2"}},
+				},
+				{Name: "jenkins", Phase: operatorv1alpha1.MemberPhase{OperatorPhase: operatorv1alpha1.OperatorRunning}},
+			},
+			DriftDiff: []operatorv1alpha1.DriftedResource{
+				{
+					Operand: "etcd", APIVersion: "etcd.database.coreos.com/v1beta2", Kind: "EtcdCluster", Namespace: registryNs, Name: "example",
+					Fields: []operatorv1alpha1.DriftedField{{Path: "spec.size", Desired: "3", Live: "1"}},
+				},
+			},
+			History: []operatorv1alpha1.HistoryEntry{
+				{Time: "2022-06-01T10:00:00Z", Action: operatorv1alpha1.HistoryChannelChanged, Operator: "etcd", From: "beta", To: "stable"},
+			},
+		},
+	}
+}
+
+func objects() []client.Object {
+	cr := &unstructured.Unstructured{}
+	cr.SetAPIVersion("etcd.database.coreos.com/v1beta2")
+	cr.SetKind("EtcdCluster")
+	cr.SetName("example")
+	cr.SetNamespace(registryNs)
+
+	return []client.Object{
+		registry(),
+		config(),
+		request(),
+		&olmv1alpha1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{Name: "etcd", Namespace: registryNs},
+			Spec:       &olmv1alpha1.SubscriptionSpec{Package: "etcd", Channel: "stable"},
+			Status:     olmv1alpha1.SubscriptionStatus{InstalledCSV: "etcdoperator.v0.9.4", State: olmv1alpha1.SubscriptionStateAtLatest},
+		},
+		&olmv1alpha1.ClusterServiceVersion{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "etcdoperator.v0.9.4",
+				Namespace: registryNs,
+				Annotations: map[string]string{
+					"alm-examples": `[{"apiVersion":"etcd.database.coreos.com/v1beta2","kind":"EtcdCluster","metadata":{"name":"example"},"spec":{"size":1,"version":"3.2.13"}},` +
+						`{"apiVersion":"etcd.database.coreos.com/v1beta2","kind":"EtcdBackup","metadata":{"name":"example"},"spec":{"storageType":"S3"}}]`,
+				},
+			},
+			Status: olmv1alpha1.ClusterServiceVersionStatus{Phase: olmv1alpha1.CSVPhaseSucceeded},
+		},
+		cr,
+		&operatorv1alpha1.OperandBindInfo{
+			ObjectMeta: metav1.ObjectMeta{Name: "etcd-bindinfo", Namespace: registryNs},
+			Spec:       operatorv1alpha1.OperandBindInfoSpec{Operand: "etcd", Registry: "common-service"},
+		},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      "etcd-secret",
+			Namespace: requestNs,
+			Labels:    map[string]string{registryNs + ".etcd-bindinfo/bindinfo": "true"},
+		}},
+	}
+}
+
+var _ = Describe("Inspecting an OperandRequest", func() {
+
+	var (
+		ctx       = context.Background()
+		key       = types.NamespacedName{Namespace: requestNs, Name: "my-request"}
+		scheme    *runtime.Scheme
+		out       *bytes.Buffer
+		inspector *Inspector
+	)
+
+	newInspector := func(objs ...client.Object) *Inspector {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+		return New(c, scheme, out)
+	}
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		utilruntime.Must(operatorv1alpha1.AddToScheme(scheme))
+		utilruntime.Must(olmv1alpha1.AddToScheme(scheme))
+		utilruntime.Must(corev1.AddToScheme(scheme))
+		out = &bytes.Buffer{}
+		inspector = newInspector(objects()...)
+	})
+
+	It("Should show the status of the OperandRequest", func() {
+		Expect(inspector.Status(ctx, key)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("team-a/my-request"))
+		Expect(out.String()).To(MatchRegexp(`etcd\s+Running\s+Running\s+EtcdCluster/example`))
+		Expect(out.String()).To(ContainSubstring("spec.size: desired 3, live 1"))
+		Expect(out.String()).To(MatchRegexp(`ChannelChanged\s+etcd\s+beta -> stable`))
+	})
+
+	It("Should fail for a missing OperandRequest", func() {
+		Expect(inspector.Status(ctx, types.NamespacedName{Namespace: requestNs, Name: "missing"})).NotTo(Succeed())
+	})
+
+	It("Should show the resources of the OperandRequest as a tree", func() {
+		Expect(inspector.Tree(ctx, key)).To(Succeed())
+		Expect(out.String()).To(Equal(`OperandRequest team-a/my-request (Running)
+└── OperandRegistry ibm-common-services/common-service
+    └── Operator etcd (package etcd, channel stable)
+        ├── Subscription ibm-common-services/etcd (AtLatestKnown)
+        │   └── ClusterServiceVersion ibm-common-services/etcdoperator.v0.9.4 (Succeeded)
+        ├── EtcdCluster ibm-common-services/example
+        └── OperandBindInfo ibm-common-services/etcd-bindinfo
+            └── Secret team-a/etcd-secret
+`))
+	})
+
+	It("Should render the custom resources merged with the OperandConfig", func() {
+		Expect(inspector.Render(ctx, key)).To(Succeed())
+		Expect(out.String()).To(Equal(`---
+# Operand etcd
+apiVersion: etcd.database.coreos.com/v1beta2
+kind: EtcdCluster
+metadata:
+  annotations:
+    operator.ibm.com/odlm-field-provenance: '{"size":"OperandConfig","version":"alm-example"}'
+    operator.ibm.com/odlm-last-applied-config: '{"size":3}'
+    team-a.my-request/request: "true"
+  labels:
+    operator.ibm.com/opreq-control: "true"
+    team-a.my-request/request: "true"
+  name: example
+  namespace: ibm-common-services
+spec:
+  size: 3
+  version: 3.2.13
+`))
+	})
+
+	It("Should render the custom resources of the operands with a kind", func() {
+		req := request()
+		req.Spec.Requests[0].Operands[0].Kind = "EtcdCluster"
+		req.Spec.Requests[0].Operands[0].APIVersion = "etcd.database.coreos.com/v1beta2"
+		req.Spec.Requests[0].Operands[0].InstanceName = "my-etcd"
+		req.Spec.Requests[0].Operands[0].Spec = &runtime.RawExtension{Raw: []byte(`{"size":5}`)}
+		inspector = newInspector(registry(), req)
+
+		Expect(inspector.Render(ctx, key)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("name: my-etcd\n  namespace: team-a\nspec:\n  size: 5\n"))
+	})
+
+	It("Should list the operands removed from the OperandRequest", func() {
+		candidates, err := inspector.PruneCandidates(ctx, key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(candidates).To(Equal([]PruneCandidate{
+			{Operand: "jenkins", Registry: types.NamespacedName{Namespace: registryNs, Name: "common-service"}, Action: PruneUninstall},
+		}))
+
+		Expect(inspector.Prune(ctx, key)).To(Succeed())
+		Expect(out.String()).To(Equal("jenkins: the operator and its custom resources are uninstalled\n"))
+	})
+
+	It("Should keep the operands requested by the other OperandRequests", func() {
+		other := request()
+		other.Namespace = "team-b"
+		other.Spec.Requests[0].Operands = []operatorv1alpha1.Operand{{Name: "jenkins"}}
+		inspector = newInspector(registry(), request(), other)
+
+		candidates, err := inspector.PruneCandidates(ctx, key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(candidates).To(BeEmpty())
+	})
+
+	It("Should unbind the operators referenced by the other OperandRequests", func() {
+		cm := ledger.New(types.NamespacedName{Namespace: registryNs, Name: "common-service"})
+		ledger.Bind(cm, "jenkins", key)
+		ledger.Bind(cm, "jenkins", types.NamespacedName{Namespace: "team-b", Name: "my-request"})
+		inspector = newInspector(registry(), request(), cm)
+
+		candidates, err := inspector.PruneCandidates(ctx, key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(candidates).To(HaveLen(1))
+		Expect(candidates[0].Action).To(Equal(PruneUnbind))
+		Expect(candidates[0].ReferencedBy).To(Equal([]types.NamespacedName{{Namespace: "team-b", Name: "my-request"}}))
+	})
+
+	It("Should orphan the removed operands with the Orphan prune policy", func() {
+		req := request()
+		req.Spec.PrunePolicy = operatorv1alpha1.PrunePolicyOrphan
+		inspector = newInspector(registry(), req)
+
+		candidates, err := inspector.PruneCandidates(ctx, key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(candidates).To(HaveLen(1))
+		Expect(candidates[0].Action).To(Equal(PruneOrphan))
+	})
+})
@@ -0,0 +1,165 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package inspect
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/ledger"
+)
+
+// PruneAction is what ODLM does with an operand removed from an OperandRequest
+type PruneAction string
+
+const (
+	// PruneUninstall means the operator and its custom resources are uninstalled.
+	PruneUninstall PruneAction = "Uninstall"
+	// PruneUnbind means the operator is kept for the other OperandRequests referencing it.
+	PruneUnbind PruneAction = "Unbind"
+	// PruneOrphan means the operator and its custom resources are left running by the Orphan prune policy.
+	PruneOrphan PruneAction = "Orphan"
+	// PruneKeep means the operand is paused, it is kept until its reconcile is resumed.
+	PruneKeep PruneAction = "Keep"
+)
+
+// PruneCandidate is a member of an OperandRequest no longer requested
+type PruneCandidate struct {
+	Operand  string
+	Registry types.NamespacedName
+	Action   PruneAction
+	// ReferencedBy are the other OperandRequests referencing the operator in the ledger of the OperandRegistry
+	ReferencedBy []types.NamespacedName
+}
+
+// PruneCandidates returns the members of the OperandRequest ODLM removes on its next reconcile:
+// the members no longer requested by any OperandRequest of the same OperandRegistries.
+func (i *Inspector) PruneCandidates(ctx context.Context, key types.NamespacedName) ([]PruneCandidate, error) {
+	req, err := i.getRequest(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	requested := make(map[string]bool)
+	for _, r := range req.Spec.Requests {
+		registryKey := req.GetRegistryKey(r)
+		registry, _, err := i.getRegistry(ctx, registryKey)
+		if err != nil {
+			return nil, err
+		}
+		requestList, err := i.ListOperandRequestsByRegistry(ctx, registryKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list the OperandRequests of the OperandRegistry %s", registryKey)
+		}
+		for _, item := range requestList {
+			if !item.DeletionTimestamp.IsZero() {
+				continue
+			}
+			for _, existingReq := range item.Spec.Requests {
+				if item.GetRegistryKey(existingReq) != registryKey {
+					continue
+				}
+				for _, operand := range registry.WithRequiredOperands(existingReq.Operands) {
+					requested[operand.Name] = true
+				}
+			}
+		}
+	}
+
+	var candidates []PruneCandidate
+	for _, m := range req.Status.Members {
+		if requested[m.Name] {
+			continue
+		}
+		candidate := PruneCandidate{Operand: m.Name, Action: PruneUninstall}
+		switch {
+		case req.IsPaused(m.Name):
+			candidate.Action = PruneKeep
+		case req.GetPrunePolicy() == operatorv1alpha1.PrunePolicyOrphan:
+			candidate.Action = PruneOrphan
+		}
+		if candidate.Registry, candidate.ReferencedBy, err = i.getReferences(ctx, req, m.Name); err != nil {
+			return nil, err
+		}
+		if candidate.Action == PruneUninstall && len(candidate.ReferencedBy) != 0 {
+			candidate.Action = PruneUnbind
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, nil
+}
+
+// getReferences returns the OperandRegistry of the operator and the other OperandRequests referencing it in its ledger
+func (i *Inspector) getReferences(ctx context.Context, req *operatorv1alpha1.OperandRequest, operator string) (types.NamespacedName, []types.NamespacedName, error) {
+	requestKey := types.NamespacedName{Namespace: req.Namespace, Name: req.Name}
+	for _, r := range req.Spec.Requests {
+		registryKey := req.GetRegistryKey(r)
+		registry, _, err := i.getRegistry(ctx, registryKey)
+		if err != nil {
+			return registryKey, nil, err
+		}
+		if registry.GetOperator(operator) == nil {
+			continue
+		}
+		cm := &corev1.ConfigMap{}
+		if err := i.Client.Get(ctx, types.NamespacedName{Namespace: registryKey.Namespace, Name: ledger.Name(registryKey.Name)}, cm); err != nil {
+			if apierrors.IsNotFound(err) {
+				return registryKey, nil, nil
+			}
+			return registryKey, nil, errors.Wrapf(err, "failed to get the ledger of the OperandRegistry %s", registryKey)
+		}
+		var others []types.NamespacedName
+		for _, ref := range ledger.References(cm, operator) {
+			if ref != requestKey {
+				others = append(others, ref)
+			}
+		}
+		return registryKey, others, nil
+	}
+	return types.NamespacedName{}, nil, nil
+}
+
+// Prune writes the members of the OperandRequest ODLM removes on its next reconcile, and what is done with them
+func (i *Inspector) Prune(ctx context.Context, key types.NamespacedName) error {
+	candidates, err := i.PruneCandidates(ctx, key)
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		fmt.Fprintf(i.Out, "Nothing to prune for the OperandRequest %s\n", key)
+		return nil
+	}
+	for _, c := range candidates {
+		switch c.Action {
+		case PruneUninstall:
+			fmt.Fprintf(i.Out, "%s: the operator and its custom resources are uninstalled\n", c.Operand)
+		case PruneUnbind:
+			fmt.Fprintf(i.Out, "%s: the operator is kept, it is still referenced by %v\n", c.Operand, c.ReferencedBy)
+		case PruneOrphan:
+			fmt.Fprintf(i.Out, "%s: the operator and its custom resources are left running by the Orphan prune policy\n", c.Operand)
+		case PruneKeep:
+			fmt.Fprintf(i.Out, "%s: the operand is paused, it is kept until its reconcile is resumed\n", c.Operand)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package inspect
+
+import (
+	"context"
+	"fmt"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/render"
+)
+
+// Render writes the custom resources ODLM creates for the operands of the OperandRequest as YAML documents,
+// without applying them. The services of the OperandConfig are merged into the alm-examples of the installed
+// ClusterServiceVersions, and the specs of the operands with a kind are rendered as they are requested,
+// with the render package. The size profiles, the replicas and the placement rendered from the cluster are not included.
+func (i *Inspector) Render(ctx context.Context, key types.NamespacedName) error {
+	req, err := i.getRequest(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range req.Spec.Requests {
+		registryKey := req.GetRegistryKey(r)
+		registry, found, err := i.getRegistry(ctx, registryKey)
+		if err != nil {
+			return err
+		}
+		if !found {
+			fmt.Fprintf(i.Out, "# The OperandRegistry %s is not found\n", registryKey)
+			continue
+		}
+		for index, operand := range registry.WithRequiredOperands(r.Operands) {
+			var crs []unstructured.Unstructured
+			if operand.Kind != "" {
+				cr, err := render.RequestedCustomResource(req, operand, index)
+				if err != nil {
+					return err
+				}
+				crs = []unstructured.Unstructured{cr}
+			} else {
+				op := registry.GetOperator(operand.Name)
+				if op == nil {
+					fmt.Fprintf(i.Out, "# The operand %s is not found in the OperandRegistry %s\n", operand.Name, registryKey)
+					continue
+				}
+				if crs, err = i.configuredCustomResources(ctx, req, registryKey, op); err != nil {
+					return err
+				}
+			}
+			for _, cr := range crs {
+				out, err := yaml.Marshal(cr.Object)
+				if err != nil {
+					return errors.Wrapf(err, "failed to render the custom resource %s %s/%s", cr.GetKind(), cr.GetNamespace(), cr.GetName())
+				}
+				fmt.Fprintf(i.Out, "---\n# Operand %s\n%s", operand.Name, out)
+			}
+		}
+	}
+	return nil
+}
+
+// configuredCustomResources renders the custom resources of the OperandConfig service of the operator
+// in the namespace of the operator, from the alm-examples of its installed ClusterServiceVersion
+func (i *Inspector) configuredCustomResources(ctx context.Context, req *operatorv1alpha1.OperandRequest, registryKey types.NamespacedName, op *operatorv1alpha1.Operator) ([]unstructured.Unstructured, error) {
+	config, err := i.GetOperandConfig(ctx, registryKey)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the OperandConfig %s", registryKey)
+	}
+	service := config.GetService(op.Name)
+	if service == nil {
+		return nil, nil
+	}
+
+	namespace := i.GetOperatorNamespace(op.InstallMode, op.Namespace)
+	sub, err := i.GetSubscription(ctx, op.Name, namespace, op.PackageName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, errors.Wrapf(err, "failed to get the Subscription %s/%s", namespace, op.Name)
+	}
+	if sub == nil {
+		fmt.Fprintf(i.Out, "# The Subscription of the operand %s is not found, its alm-examples are unknown\n", op.Name)
+		return nil, nil
+	}
+	csv, err := i.GetClusterServiceVersion(ctx, sub)
+	if err != nil {
+		return nil, err
+	}
+	if csv == nil {
+		fmt.Fprintf(i.Out, "# The ClusterServiceVersion of the operand %s is not installed, its alm-examples are unknown\n", op.Name)
+		return nil, nil
+	}
+
+	crs, _, err := render.ConfiguredCustomResources(req, service, op.Namespace, []olmv1alpha1.ClusterServiceVersion{*csv})
+	return crs, err
+}
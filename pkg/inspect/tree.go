@@ -0,0 +1,204 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package inspect
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+// node is a resource of the tree of an OperandRequest
+type node struct {
+	label    string
+	children []*node
+}
+
+func (n *node) add(format string, args ...interface{}) *node {
+	child := &node{label: fmt.Sprintf(format, args...)}
+	n.children = append(n.children, child)
+	return child
+}
+
+// write writes the children of the node, indented by the prefix
+func (n *node) write(w io.Writer, prefix string) {
+	for i, child := range n.children {
+		branch, indent := "├── ", "│   "
+		if i == len(n.children)-1 {
+			branch, indent = "└── ", "    "
+		}
+		fmt.Fprintln(w, prefix+branch+child.label)
+		child.write(w, prefix+indent)
+	}
+}
+
+// Tree writes the resources of the OperandRequest as a tree: the OperandRegistries it requests,
+// their operators with the Subscriptions and the ClusterServiceVersions, the custom resources created
+// for the operands and the copies of the bindings shared by the OperandBindInfos.
+func (i *Inspector) Tree(ctx context.Context, key types.NamespacedName) error {
+	req, err := i.getRequest(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	root := &node{label: fmt.Sprintf("OperandRequest %s (%s)", key, phaseOf(string(req.Status.Phase)))}
+	for _, r := range req.Spec.Requests {
+		registryKey := req.GetRegistryKey(r)
+		registry, found, err := i.getRegistry(ctx, registryKey)
+		if err != nil {
+			return err
+		}
+		if !found {
+			root.add("OperandRegistry %s (not found)", registryKey)
+			continue
+		}
+		registryNode := root.add("OperandRegistry %s", registryKey)
+		bindInfos, err := i.listBindInfos(ctx, registryKey)
+		if err != nil {
+			return err
+		}
+		for _, operand := range registry.WithRequiredOperands(r.Operands) {
+			op := registry.GetOperator(operand.Name)
+			if op == nil {
+				registryNode.add("Operator %s (not found in the OperandRegistry)", operand.Name)
+				continue
+			}
+			opNode := registryNode.add("Operator %s (package %s, channel %s)", op.Name, op.PackageName, op.Channel)
+			if op.GetType() == operatorv1alpha1.OperatorTypeOLM {
+				if err := i.addSubscription(ctx, opNode, op); err != nil {
+					return err
+				}
+			} else {
+				opNode.add("installed by %s", op.GetType())
+			}
+
+			namespace := op.Namespace
+			if operand.Kind != "" {
+				namespace = req.Namespace
+			}
+			if member := getMember(req, operand.Name); member != nil {
+				for _, cr := range member.OperandCRList {
+					if err := i.addCustomResource(ctx, opNode, cr, namespace); err != nil {
+						return err
+					}
+				}
+			}
+			for _, bindInfo := range bindInfos {
+				if bindInfo.Spec.Operand != operand.Name {
+					continue
+				}
+				if err := i.addBindInfo(ctx, opNode, bindInfo, req.Namespace); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	fmt.Fprintln(i.Out, root.label)
+	root.write(i.Out, "")
+	return nil
+}
+
+// addSubscription adds the Subscription of the operator and its installed ClusterServiceVersion
+func (i *Inspector) addSubscription(ctx context.Context, opNode *node, op *operatorv1alpha1.Operator) error {
+	namespace := i.GetOperatorNamespace(op.InstallMode, op.Namespace)
+	sub, err := i.GetSubscription(ctx, op.Name, namespace, op.PackageName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to get the Subscription %s/%s", namespace, op.Name)
+	}
+	if sub == nil {
+		opNode.add("Subscription %s/%s (not found)", namespace, op.Name)
+		return nil
+	}
+	subNode := opNode.add("Subscription %s/%s (%s)", sub.Namespace, sub.Name, phaseOf(string(sub.Status.State)))
+	csv, err := i.GetClusterServiceVersion(ctx, sub)
+	if err != nil {
+		return err
+	}
+	if csv == nil {
+		subNode.add("ClusterServiceVersion (not installed)")
+		return nil
+	}
+	subNode.add("ClusterServiceVersion %s/%s (%s)", csv.Namespace, csv.Name, phaseOf(string(csv.Status.Phase)))
+	return nil
+}
+
+// addCustomResource adds a custom resource of the operand, it is checked in the cluster
+func (i *Inspector) addCustomResource(ctx context.Context, opNode *node, member operatorv1alpha1.OperandCRMember, namespace string) error {
+	cr := &unstructured.Unstructured{}
+	cr.SetAPIVersion(member.APIVersion)
+	cr.SetKind(member.Kind)
+	if err := i.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: member.Name}, cr); err != nil {
+		if apierrors.IsNotFound(err) {
+			opNode.add("%s %s/%s (not found)", member.Kind, namespace, member.Name)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get the custom resource %s %s/%s", member.Kind, namespace, member.Name)
+	}
+	opNode.add("%s %s/%s", member.Kind, namespace, member.Name)
+	return nil
+}
+
+// listBindInfos lists the OperandBindInfos of the OperandRegistry
+func (i *Inspector) listBindInfos(ctx context.Context, registryKey types.NamespacedName) ([]operatorv1alpha1.OperandBindInfo, error) {
+	bindInfoList := &operatorv1alpha1.OperandBindInfoList{}
+	if err := i.Client.List(ctx, bindInfoList, client.InNamespace(registryKey.Namespace)); err != nil {
+		return nil, errors.Wrapf(err, "failed to list the OperandBindInfos in the namespace %s", registryKey.Namespace)
+	}
+	var bindInfos []operatorv1alpha1.OperandBindInfo
+	for _, bindInfo := range bindInfoList.Items {
+		registryNamespace := bindInfo.Spec.RegistryNamespace
+		if registryNamespace == "" {
+			registryNamespace = bindInfo.Namespace
+		}
+		if bindInfo.Spec.Registry == registryKey.Name && registryNamespace == registryKey.Namespace {
+			bindInfos = append(bindInfos, bindInfo)
+		}
+	}
+	return bindInfos, nil
+}
+
+// addBindInfo adds the OperandBindInfo with the copies of its Secrets and ConfigMaps in the namespace of the OperandRequest
+func (i *Inspector) addBindInfo(ctx context.Context, opNode *node, bindInfo operatorv1alpha1.OperandBindInfo, namespace string) error {
+	bindInfoNode := opNode.add("OperandBindInfo %s/%s", bindInfo.Namespace, bindInfo.Name)
+	copyLabel := client.MatchingLabels{bindInfo.Namespace + "." + bindInfo.Name + "/bindinfo": "true"}
+
+	secretList := &corev1.SecretList{}
+	if err := i.Client.List(ctx, secretList, client.InNamespace(namespace), copyLabel); err != nil {
+		return errors.Wrapf(err, "failed to list the Secrets of the OperandBindInfo %s/%s", bindInfo.Namespace, bindInfo.Name)
+	}
+	for _, secret := range secretList.Items {
+		bindInfoNode.add("Secret %s/%s", secret.Namespace, secret.Name)
+	}
+	cmList := &corev1.ConfigMapList{}
+	if err := i.Client.List(ctx, cmList, client.InNamespace(namespace), copyLabel); err != nil {
+		return errors.Wrapf(err, "failed to list the ConfigMaps of the OperandBindInfo %s/%s", bindInfo.Namespace, bindInfo.Name)
+	}
+	for _, cm := range cmList.Items {
+		bindInfoNode.add("ConfigMap %s/%s", cm.Namespace, cm.Name)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package leader runs ODLM in active-passive replicas: the replicas elect a leader, which runs the controllers,
+// the others keep their caches synced to take over as soon as the leader is gone.
+//
+// A replica is ready when its caches are synced and it is the leader or follows a leader renewing its lease,
+// so the Deployment rolls out a replica after the other while the leader keeps running the controllers.
+package leader
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// ElectionID is the name of the lock of the leader election
+	ElectionID = "ab89bbb1.ibm.com"
+	// jitterFactor is the jitter client-go adds to the retry period, the renew deadline must be longer
+	jitterFactor = 1.2
+)
+
+// Options are the leader election options set by the flags of the operator
+type Options struct {
+	// Enabled elects a leader among the replicas, only the leader runs the controllers
+	Enabled bool
+	// LeaseDuration is how long the replicas wait before taking over from a leader which stopped renewing its lease
+	LeaseDuration time.Duration
+	// RenewDeadline is how long the leader retries to renew its lease before it stops leading
+	RenewDeadline time.Duration
+	// RetryPeriod is the time between two tries to acquire or renew the lease
+	RetryPeriod time.Duration
+}
+
+// BindFlags binds the leader election options to the flags, the defaults are the ones of controller-runtime
+func (o *Options) BindFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&o.Enabled, "enable-leader-election", false,
+		"Enable leader election for controller manager. "+
+			"Enabling this will ensure there is only one active controller manager.")
+	fs.DurationVar(&o.LeaseDuration, "leader-election-lease-duration", 15*time.Second, "How long the replicas wait before taking over from a leader which stopped renewing its lease.")
+	fs.DurationVar(&o.RenewDeadline, "leader-election-renew-deadline", 10*time.Second, "How long the leader retries to renew its lease before it stops leading.")
+	fs.DurationVar(&o.RetryPeriod, "leader-election-retry-period", 2*time.Second, "The time between two tries to acquire or renew the lease.")
+}
+
+// Validate checks the durations of the leader election, a leader must renew its lease before the others take over
+func (o Options) Validate() error {
+	if o.RetryPeriod <= 0 {
+		return fmt.Errorf("the leader election retry period %s is not positive", o.RetryPeriod)
+	}
+	if o.RenewDeadline <= time.Duration(jitterFactor*float64(o.RetryPeriod)) {
+		return fmt.Errorf("the leader election renew deadline %s is not longer than %v times the retry period %s", o.RenewDeadline, jitterFactor, o.RetryPeriod)
+	}
+	if o.LeaseDuration <= o.RenewDeadline {
+		return fmt.Errorf("the leader election lease duration %s is not longer than the renew deadline %s", o.LeaseDuration, o.RenewDeadline)
+	}
+	return nil
+}
+
+// Configure sets the leader election of the manager options. The leader releases its lease when it stops,
+// so a replica takes over without waiting for the lease to expire during the rollouts.
+func (o Options) Configure(opts *manager.Options, namespace string) {
+	leaseDuration, renewDeadline, retryPeriod := o.LeaseDuration, o.RenewDeadline, o.RetryPeriod
+	opts.LeaderElection = o.Enabled
+	opts.LeaderElectionID = ElectionID
+	opts.LeaderElectionNamespace = namespace
+	opts.LeaderElectionReleaseOnCancel = true
+	opts.LeaseDuration = &leaseDuration
+	opts.RenewDeadline = &renewDeadline
+	opts.RetryPeriod = &retryPeriod
+}
+
+// Readiness reports if a replica is ready to take over or keep running the controllers
+type Readiness struct {
+	reader  client.Reader
+	lease   types.NamespacedName
+	elected <-chan struct{}
+	synced  int32
+	now     func() time.Time
+}
+
+// NewReadiness returns the readiness of the replica of the manager, the lease is in the namespace of the leader election
+func NewReadiness(mgr manager.Manager, namespace string) *Readiness {
+	return &Readiness{
+		reader:  mgr.GetAPIReader(),
+		lease:   types.NamespacedName{Namespace: namespace, Name: ElectionID},
+		elected: mgr.Elected(),
+		now:     time.Now,
+	}
+}
+
+// Start marks the caches synced, the manager starts the runnables without leader election once its caches are synced
+func (r *Readiness) Start(ctx context.Context) error {
+	atomic.StoreInt32(&r.synced, 1)
+	<-ctx.Done()
+	return nil
+}
+
+// NeedLeaderElection returns false, every replica syncs its caches
+func (r *Readiness) NeedLeaderElection() bool {
+	return false
+}
+
+// CacheSynced returns an error until the caches of the replica are synced
+func (r *Readiness) CacheSynced(_ *http.Request) error {
+	if atomic.LoadInt32(&r.synced) == 0 {
+		return fmt.Errorf("the caches are not synced")
+	}
+	return nil
+}
+
+// Leading returns an error when the replica is neither the leader nor following a leader renewing its lease.
+// The manager elects itself at once when the leader election is disabled.
+func (r *Readiness) Leading(req *http.Request) error {
+	select {
+	case <-r.elected:
+		return nil
+	default:
+	}
+	lease := &coordinationv1.Lease{}
+	if err := r.reader.Get(req.Context(), r.lease, lease); err != nil {
+		return fmt.Errorf("failed to get the lease %s of the leader: %v", r.lease, err)
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == "" {
+		return fmt.Errorf("no replica holds the lease %s", r.lease)
+	}
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return fmt.Errorf("the leader %s never renewed the lease %s", *lease.Spec.HolderIdentity, r.lease)
+	}
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	if r.now().After(expiry) {
+		return fmt.Errorf("the leader %s stopped renewing the lease %s at %s", *lease.Spec.HolderIdentity, r.lease, lease.Spec.RenewTime.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// AddChecks adds the readiness of the replica to the ready checks of the manager
+func AddChecks(mgr manager.Manager, r *Readiness) error {
+	if err := mgr.Add(r); err != nil {
+		return err
+	}
+	if err := mgr.AddReadyzCheck("cache-sync", r.CacheSynced); err != nil {
+		return err
+	}
+	return mgr.AddReadyzCheck("leader", r.Leading)
+}
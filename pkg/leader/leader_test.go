@@ -0,0 +1,136 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package leader
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+var _ = Describe("Leader election", func() {
+
+	defaults := Options{
+		Enabled:       true,
+		LeaseDuration: 15 * time.Second,
+		RenewDeadline: 10 * time.Second,
+		RetryPeriod:   2 * time.Second,
+	}
+
+	It("Should check the durations of the leader election", func() {
+		Expect(defaults.Validate()).Should(Succeed())
+
+		o := defaults
+		o.LeaseDuration = 10 * time.Second
+		Expect(o.Validate()).ShouldNot(Succeed())
+
+		o = defaults
+		o.RetryPeriod = 9 * time.Second
+		Expect(o.Validate()).ShouldNot(Succeed())
+
+		o = defaults
+		o.RetryPeriod = 0
+		Expect(o.Validate()).ShouldNot(Succeed())
+	})
+
+	It("Should configure the leader election of the manager", func() {
+		opts := manager.Options{}
+		defaults.Configure(&opts, "ibm-common-services")
+		Expect(opts.LeaderElection).Should(BeTrue())
+		Expect(opts.LeaderElectionID).Should(Equal(ElectionID))
+		Expect(opts.LeaderElectionNamespace).Should(Equal("ibm-common-services"))
+		Expect(opts.LeaderElectionReleaseOnCancel).Should(BeTrue())
+		Expect(*opts.LeaseDuration).Should(Equal(15 * time.Second))
+		Expect(*opts.RenewDeadline).Should(Equal(10 * time.Second))
+		Expect(*opts.RetryPeriod).Should(Equal(2 * time.Second))
+	})
+
+	Context("Readiness of a replica", func() {
+
+		var (
+			now      time.Time
+			elected  chan struct{}
+			lease    *coordinationv1.Lease
+			req      *http.Request
+			newReady func() *Readiness
+		)
+
+		BeforeEach(func() {
+			now = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+			elected = make(chan struct{})
+			holder := "odlm-7d9f8-abcde"
+			duration := int32(15)
+			lease = &coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{Name: ElectionID, Namespace: "ibm-common-services"},
+				Spec: coordinationv1.LeaseSpec{
+					HolderIdentity:       &holder,
+					LeaseDurationSeconds: &duration,
+					RenewTime:            &metav1.MicroTime{Time: now.Add(-10 * time.Second)},
+				},
+			}
+			req, _ = http.NewRequestWithContext(context.Background(), http.MethodGet, "/readyz", nil)
+			newReady = func() *Readiness {
+				return &Readiness{
+					reader:  fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(lease).Build(),
+					lease:   types.NamespacedName{Namespace: "ibm-common-services", Name: ElectionID},
+					elected: elected,
+					now:     func() time.Time { return now },
+				}
+			}
+		})
+
+		It("Should be ready once the caches are synced", func() {
+			r := newReady()
+			Expect(r.CacheSynced(req)).ShouldNot(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				Expect(r.Start(ctx)).Should(Succeed())
+			}()
+			Eventually(func() error { return r.CacheSynced(req) }).Should(Succeed())
+			cancel()
+			<-done
+		})
+
+		It("Should be ready when it follows a leader renewing its lease", func() {
+			Expect(newReady().Leading(req)).Should(Succeed())
+
+			now = now.Add(10 * time.Second)
+			Expect(newReady().Leading(req)).ShouldNot(Succeed())
+
+			// The leader is always ready
+			close(elected)
+			Expect(newReady().Leading(req)).Should(Succeed())
+		})
+
+		It("Should not be ready when no replica holds the lease", func() {
+			lease.Spec.HolderIdentity = nil
+			Expect(newReady().Leading(req)).ShouldNot(Succeed())
+		})
+	})
+})
@@ -0,0 +1,256 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package logging provides the structured logger of ODLM.
+//
+// The lines are written as JSON by default, and tagged with the name of the controller, the reconcile ID
+// and the reconciled objects. The verbosity is set for all the controllers and can be raised or lowered
+// for each one, at runtime.
+//
+// The levels of the lines follow a policy, so that raising the verbosity adds a kind of lines at a time:
+// the problems and the decisions are always written, then the flow of the reconciles, the changes to the
+// objects, the steps of the reconciles and at last the rendered objects. The sensitive fields are redacted.
+package logging
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	klogv2 "k8s.io/klog/v2"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// FormatJSON writes a JSON object per line
+	FormatJSON = "json"
+	// FormatText writes the lines for humans
+	FormatText = "text"
+)
+
+// The verbosity levels of the lines, the lines at level 0 are the problems and the decisions worth attention
+const (
+	// LevelFlow is the level of the flow of the reconciles, like their start and end and what they wait for
+	LevelFlow = 1
+	// LevelChange is the level of the objects created, updated and deleted
+	LevelChange = 2
+	// LevelDebug is the level of the steps of the reconciles, like the objects looked up and the ones skipped
+	LevelDebug = 3
+	// LevelRender is the level of the rendered objects, like the merged specs of the custom resources
+	LevelRender = 4
+)
+
+// Options are the logging options set by the flags of the operator
+type Options struct {
+	// Verbosity is the verbosity of all the controllers
+	Verbosity int
+	// Controllers are the verbosities of the controllers, in the name=verbosity,... format
+	Controllers string
+	// Format is one of json and text
+	Format string
+}
+
+// BindFlags binds the logging options to the flags
+func (o *Options) BindFlags(fs *flag.FlagSet) {
+	fs.IntVar(&o.Verbosity, "v", 0, "The verbosity of the logs of all the controllers.")
+	fs.StringVar(&o.Controllers, "log-verbosity", "", "The verbosity of the logs of each controller, like operandrequest=3,operandregistry=2, it overrides the -v flag.")
+	fs.StringVar(&o.Format, "log-format", FormatJSON, "The format of the logs, one of json and text.")
+}
+
+// verbosity holds the verbosity of all the controllers and the ones set for each controller
+var verbosity struct {
+	sync.RWMutex
+	all                int
+	controllers        map[string]int
+	defaultAll         int
+	defaultControllers map[string]int
+}
+
+// Setup sets the logger of ODLM, controller-runtime and klog, writing to the output with the options
+func Setup(o Options, output io.Writer) error {
+	controllers, err := ParseVerbosity(o.Controllers)
+	if err != nil {
+		return err
+	}
+	var encoder zapcore.Encoder
+	switch o.Format {
+	case FormatJSON, "":
+		encoderConfig := zap.NewProductionEncoderConfig()
+		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	case FormatText:
+		encoderConfig := zap.NewDevelopmentEncoderConfig()
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	default:
+		return fmt.Errorf("the log format %s is not one of %s and %s", o.Format, FormatJSON, FormatText)
+	}
+
+	verbosity.Lock()
+	verbosity.defaultAll = o.Verbosity
+	verbosity.defaultControllers = controllers
+	verbosity.Unlock()
+	SetVerbosity(o.Verbosity, controllers)
+
+	sink := zapcore.Lock(zapcore.AddSync(output))
+	core := &leveledCore{Core: zapcore.NewCore(encoder, sink, zap.LevelEnablerFunc(func(zapcore.Level) bool { return true }))}
+	logger := zapr.NewLogger(zap.New(core, zap.ErrorOutput(zapcore.Lock(os.Stderr))))
+	ctrllog.SetLogger(logger)
+	klogv2.SetLogger(logger.WithName("klog"))
+	return nil
+}
+
+// GetDefaultVerbosity returns the verbosity of all the controllers and the ones of each controller set by the flags
+func GetDefaultVerbosity() (int, map[string]int) {
+	verbosity.RLock()
+	defer verbosity.RUnlock()
+	return verbosity.defaultAll, copyVerbosity(verbosity.defaultControllers)
+}
+
+// SetVerbosity sets the verbosity of all the controllers and the ones of the listed controllers at runtime
+func SetVerbosity(all int, controllers map[string]int) {
+	verbosity.Lock()
+	defer verbosity.Unlock()
+	verbosity.all = all
+	verbosity.controllers = controllers
+}
+
+// GetVerbosity returns the verbosity of all the controllers and the ones set for each controller
+func GetVerbosity() (int, map[string]int) {
+	verbosity.RLock()
+	defer verbosity.RUnlock()
+	return verbosity.all, copyVerbosity(verbosity.controllers)
+}
+
+func copyVerbosity(controllers map[string]int) map[string]int {
+	copied := make(map[string]int, len(controllers))
+	for name, v := range controllers {
+		copied[name] = v
+	}
+	return copied
+}
+
+// VerbosityOf returns the verbosity of the named logger, the first part of its name is the controller
+func VerbosityOf(name string) int {
+	verbosity.RLock()
+	defer verbosity.RUnlock()
+	if v, ok := verbosity.controllers[strings.SplitN(name, ".", 2)[0]]; ok {
+		return v
+	}
+	return verbosity.all
+}
+
+// maxVerbosity returns the highest verbosity of the controllers
+func maxVerbosity() int {
+	verbosity.RLock()
+	defer verbosity.RUnlock()
+	max := verbosity.all
+	for _, v := range verbosity.controllers {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+// ParseVerbosity parses the verbosities of the controllers in the name=verbosity,... format
+func ParseVerbosity(s string) (map[string]int, error) {
+	controllers := make(map[string]int)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("the log verbosity %s is not in the name=verbosity format", pair)
+		}
+		v, err := strconv.Atoi(parts[1])
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("the log verbosity %s of %s is not a non-negative integer", parts[1], parts[0])
+		}
+		controllers[parts[0]] = v
+	}
+	return controllers, nil
+}
+
+// FormatVerbosity formats the verbosities of the controllers in the name=verbosity,... format
+func FormatVerbosity(controllers map[string]int) string {
+	var pairs []string
+	for name, v := range controllers {
+		pairs = append(pairs, name+"="+strconv.Itoa(v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// leveledCore drops the entries above the verbosity of their logger and redacts the sensitive fields
+type leveledCore struct {
+	zapcore.Core
+}
+
+// Enabled returns true if the level is enabled for any logger, Check filters the entries by logger
+func (c *leveledCore) Enabled(lvl zapcore.Level) bool {
+	return lvl >= zapcore.Level(-maxVerbosity())
+}
+
+func (c *leveledCore) With(fields []zapcore.Field) zapcore.Core {
+	return &leveledCore{Core: c.Core.With(redactFields(fields))}
+}
+
+func (c *leveledCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level < zapcore.Level(-VerbosityOf(ent.LoggerName)) {
+		return ce
+	}
+	return ce.AddCore(ent, c)
+}
+
+func (c *leveledCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, redactFields(fields))
+}
+
+// Logger returns the logger of the controller
+func Logger(controller string) logr.Logger {
+	return ctrllog.Log.WithName(controller)
+}
+
+// Reconcile returns the logger of a reconcile of the controller and the context carrying it.
+// The lines are tagged with a new reconcile ID and the key-values, like the reconciled object.
+func Reconcile(ctx context.Context, controller string, keysAndValues ...interface{}) (context.Context, logr.Logger) {
+	log := Logger(controller).WithValues(append([]interface{}{"reconcileID", string(uuid.NewUUID())}, keysAndValues...)...)
+	return ctrllog.IntoContext(ctx, log), log
+}
+
+// FromContext returns the logger carried by the context, tagged with the key-values
+func FromContext(ctx context.Context, keysAndValues ...interface{}) logr.Logger {
+	return ctrllog.FromContext(ctx, keysAndValues...)
+}
+
+// WithValues returns a context carrying the logger of the context tagged with the key-values, and the logger
+func WithValues(ctx context.Context, keysAndValues ...interface{}) (context.Context, logr.Logger) {
+	log := ctrllog.FromContext(ctx, keysAndValues...)
+	return ctrllog.IntoContext(ctx, log), log
+}
@@ -0,0 +1,134 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Logging", func() {
+
+	BeforeEach(func() {
+		output.Reset()
+	})
+
+	It("Should parse and format the verbosities of the controllers", func() {
+		controllers, err := ParseVerbosity(" operandrequest=3, operandregistry=0,")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(controllers).Should(Equal(map[string]int{"operandrequest": 3, "operandregistry": 0}))
+		Expect(FormatVerbosity(controllers)).Should(Equal("operandregistry=0,operandrequest=3"))
+
+		_, err = ParseVerbosity("operandrequest")
+		Expect(err).To(HaveOccurred())
+		_, err = ParseVerbosity("operandrequest=-1")
+		Expect(err).To(HaveOccurred())
+		_, err = ParseVerbosity("=1")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should write the lines as JSON tagged with the reconcile", func() {
+		all, controllers := GetDefaultVerbosity()
+		Expect(all).Should(Equal(1))
+		Expect(controllers).Should(Equal(map[string]int{"operandrequest": 3}))
+		Expect(VerbosityOf("operandrequest.sub")).Should(Equal(3))
+		Expect(VerbosityOf("operandregistry")).Should(Equal(1))
+
+		ctx, log := Reconcile(context.Background(), "operandrequest", "request", "ibm-common-services/common-service")
+		_, log = WithValues(ctx, "operator", "ibm-etcd-operator")
+		log.V(3).Info("Reconciling the operator")
+		Logger("operandregistry").V(3).Info("Dropped")
+		Logger("operandregistry").V(1).Info("Reconciling OperandRegistry")
+
+		lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+		Expect(lines).Should(HaveLen(2))
+		entry := map[string]interface{}{}
+		Expect(json.Unmarshal([]byte(lines[0]), &entry)).To(Succeed())
+		Expect(entry).Should(HaveKeyWithValue("logger", "operandrequest"))
+		Expect(entry).Should(HaveKeyWithValue("msg", "Reconciling the operator"))
+		Expect(entry).Should(HaveKeyWithValue("request", "ibm-common-services/common-service"))
+		Expect(entry).Should(HaveKeyWithValue("operator", "ibm-etcd-operator"))
+		Expect(entry).Should(HaveKey("reconcileID"))
+		Expect(lines[1]).Should(ContainSubstring("Reconciling OperandRegistry"))
+	})
+
+	It("Should change the verbosity at runtime", func() {
+		defer SetVerbosity(GetDefaultVerbosity())
+
+		SetVerbosity(0, map[string]int{"operandregistry": 2})
+		Logger("operandrequest").V(1).Info("Dropped")
+		Expect(output.String()).Should(BeEmpty())
+
+		SetVerbosity(0, map[string]int{"operandrequest": 2})
+		Logger("operandrequest").V(2).Info("Raised")
+		Logger("operandregistry").V(2).Info("Dropped")
+		Expect(output.String()).Should(ContainSubstring("Raised"))
+		Expect(output.String()).ShouldNot(ContainSubstring("Dropped"))
+
+		all, controllers := GetVerbosity()
+		Expect(all).Should(Equal(0))
+		Expect(controllers).Should(Equal(map[string]int{"operandrequest": 2}))
+
+		Expect(Setup(Options{Format: "yaml"}, &output)).NotTo(Succeed())
+	})
+
+	It("Should redact the sensitive fields", func() {
+		Expect(IsSensitive("adminPassword")).Should(BeTrue())
+		Expect(IsSensitive("tokenSecretName")).Should(BeFalse())
+		Expect(IsSensitive("secret")).Should(BeFalse())
+
+		secret := map[string]interface{}{
+			"kind":     "Secret",
+			"metadata": map[string]interface{}{"name": "db"},
+			"data":     map[string]interface{}{"user": "YWRtaW4="},
+		}
+		spec := map[string]interface{}{
+			"database": map[string]interface{}{"password": "passw0rd", "passwordSecretRef": "db"},
+			"users":    []interface{}{map[string]interface{}{"name": "admin", "apiKey": "key"}},
+		}
+		Expect(Redact(secret)).Should(HaveKeyWithValue("data", Redacted))
+		Expect(Redact(spec)).Should(Equal(map[string]interface{}{
+			"database": map[string]interface{}{"password": Redacted, "passwordSecretRef": "db"},
+			"users":    []interface{}{map[string]interface{}{"name": "admin", "apiKey": Redacted}},
+		}))
+		Expect(spec["database"]).Should(HaveKeyWithValue("password", "passw0rd"))
+
+		log := Logger("operandrequest").WithValues("token", "t0ken")
+		log.V(LevelDebug).Info("Reconciling the operator", "password", "passw0rd", "spec", spec)
+		Expect(output.String()).Should(ContainSubstring("Reconciling the operator"))
+		Expect(output.String()).ShouldNot(ContainSubstring("t0ken"))
+		Expect(output.String()).ShouldNot(ContainSubstring("passw0rd"))
+	})
+
+	It("Should write the rendered objects at the render level", func() {
+		defer SetVerbosity(GetDefaultVerbosity())
+
+		spec := map[string]interface{}{"password": "passw0rd", "replicas": 3}
+		Render(Logger("operandrequest"), "Rendered the custom resource", spec, "kind", "EtcdCluster")
+		Expect(output.String()).Should(BeEmpty())
+
+		SetVerbosity(0, map[string]int{"operandrequest": LevelRender})
+		Render(Logger("operandrequest"), "Rendered the custom resource", spec, "kind", "EtcdCluster")
+		Expect(output.String()).Should(ContainSubstring("Rendered the custom resource"))
+		Expect(output.String()).Should(ContainSubstring(`"replicas":3`))
+		Expect(output.String()).ShouldNot(ContainSubstring("passw0rd"))
+	})
+})
@@ -0,0 +1,111 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logging
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Redacted replaces the values of the sensitive fields
+const Redacted = "<redacted>"
+
+// sensitiveKey matches the keys of the fields holding credentials, the keys of the references to them are not sensitive
+var sensitiveKey = regexp.MustCompile(`(?i)(password|passwd|passphrase|token|apikey|api_key|credential|clientsecret|client_secret|privatekey|private_key)`)
+
+// IsSensitive returns true if the values of the key are redacted
+func IsSensitive(key string) bool {
+	lower := strings.ToLower(key)
+	for _, suffix := range []string{"name", "namespace", "ref", "refs", "path", "file"} {
+		if strings.HasSuffix(lower, suffix) {
+			return false
+		}
+	}
+	return sensitiveKey.MatchString(key)
+}
+
+// Redact returns a copy of the object with the values of the sensitive keys and the data of the Secrets redacted.
+// The objects other than the maps and the slices are returned as they are.
+func Redact(obj interface{}) interface{} {
+	switch o := obj.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(o))
+		secret := o["kind"] == "Secret"
+		for key, value := range o {
+			if IsSensitive(key) || (secret && (key == "data" || key == "stringData")) {
+				redacted[key] = Redacted
+				continue
+			}
+			redacted[key] = Redact(value)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(o))
+		for i, value := range o {
+			redacted[i] = Redact(value)
+		}
+		return redacted
+	default:
+		return obj
+	}
+}
+
+// Render writes the rendered object at the render level with its sensitive fields redacted, it is skipped
+// when no logger writes the render level
+func Render(log logr.Logger, msg string, obj interface{}, keysAndValues ...interface{}) {
+	if !log.V(LevelRender).Enabled() {
+		return
+	}
+	log.V(LevelRender).Info(msg, append(keysAndValues, "rendered", Redact(obj))...)
+}
+
+// redactFields redacts the values of the sensitive fields and the sensitive keys of the maps
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	var redacted []zapcore.Field
+	for i, field := range fields {
+		var replaced zapcore.Field
+		switch {
+		case IsSensitive(field.Key):
+			replaced = zap.String(field.Key, Redacted)
+		case field.Type == zapcore.ReflectType && isRedactable(field.Interface):
+			replaced = zap.Any(field.Key, Redact(field.Interface))
+		default:
+			continue
+		}
+		if redacted == nil {
+			redacted = append([]zapcore.Field{}, fields...)
+		}
+		redacted[i] = replaced
+	}
+	if redacted == nil {
+		return fields
+	}
+	return redacted
+}
+
+func isRedactable(obj interface{}) bool {
+	switch obj.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,162 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DefaultMaxLabelValues is the default number of the values of a guarded label of a metric
+	DefaultMaxLabelValues = 500
+	// OverflowValue is the value of a guarded label beyond its number of values
+	OverflowValue = "_overflow_"
+
+	metricLabel = "metric"
+	labelLabel  = "label"
+)
+
+// maxLabelValues is the number of the values of each guarded label of a metric, 0 disables the guard
+var maxLabelValues int64 = DefaultMaxLabelValues
+
+// LabelOverflows counts the series recorded with the overflow value of a guarded label
+var LabelOverflows = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "odlm_metric_label_overflow_total",
+	Help: "The number of the series recorded with the overflow value, because the label of the metric reached its maximum number of values.",
+}, []string{metricLabel, labelLabel})
+
+// SetMaxLabelValues sets the number of the values of each namespace, name, request and operator label of a metric.
+// The values already recorded are kept when it is lowered. 0 disables the guard.
+func SetMaxLabelValues(max int) {
+	atomic.StoreInt64(&maxLabelValues, int64(max))
+}
+
+// GetMaxLabelValues returns the number of the values of each guarded label of a metric
+func GetMaxLabelValues() int {
+	return int(atomic.LoadInt64(&maxLabelValues))
+}
+
+// labelGuard bounds the number of the values of a label of a metric.
+// The values beyond the maximum are replaced by the overflow value, so the number of the series stays bounded
+// on the clusters with many namespaces, OperandRequests and operators.
+type labelGuard struct {
+	sync.Mutex
+	metric string
+	label  string
+	// values are the admitted values with the number of their references.
+	// The values of the counters and the histograms are never released, their series are never removed.
+	values map[string]int
+}
+
+// newLabelGuards returns the guards of the labels of the metric, nil for the labels which aren't guarded
+func newLabelGuards(metric string, labels ...string) []*labelGuard {
+	guards := make([]*labelGuard, len(labels))
+	for i, label := range labels {
+		switch label {
+		case namespaceLabel, nameLabel, requestLabel, operatorLabel:
+			guards[i] = &labelGuard{metric: metric, label: label, values: make(map[string]int)}
+		}
+	}
+	return guards
+}
+
+// acquire returns the value, or the overflow value when the label has reached its maximum number of values
+func (g *labelGuard) acquire(value string) string {
+	g.Lock()
+	defer g.Unlock()
+	max := GetMaxLabelValues()
+	if _, ok := g.values[value]; ok || max <= 0 || len(g.values) < max {
+		g.values[value]++
+		return value
+	}
+	LabelOverflows.WithLabelValues(g.metric, g.label).Inc()
+	return OverflowValue
+}
+
+// release releases a value returned by acquire, once the series using it is removed
+func (g *labelGuard) release(value string) {
+	g.Lock()
+	defer g.Unlock()
+	if _, ok := g.values[value]; !ok {
+		return
+	}
+	if g.values[value]--; g.values[value] <= 0 {
+		delete(g.values, value)
+	}
+}
+
+// guardedLabels is a metric with the guards of its labels
+type guardedLabels struct {
+	sync.Mutex
+	guards []*labelGuard
+	// seen are the label values the series of the counters and the histograms are recorded with
+	seen map[string][]string
+}
+
+func newGuardedLabels(metric string, labels ...string) *guardedLabels {
+	return &guardedLabels{guards: newLabelGuards(metric, labels...), seen: make(map[string][]string)}
+}
+
+// values returns the label values of the series of the counter or the histogram.
+// The values are admitted once, the later observations of the same labels use the same series.
+func (m *guardedLabels) values(labels ...string) []string {
+	key := joinLabels(labels)
+	m.Lock()
+	defer m.Unlock()
+	if guarded, ok := m.seen[key]; ok {
+		return guarded
+	}
+	guarded := acquireLabels(m.guards, labels)
+	m.seen[key] = guarded
+	return guarded
+}
+
+// acquireLabels returns the label values with the guarded ones acquired
+func acquireLabels(guards []*labelGuard, labels []string) []string {
+	guarded := make([]string, len(labels))
+	for i, value := range labels {
+		guarded[i] = value
+		if i < len(guards) && guards[i] != nil {
+			guarded[i] = guards[i].acquire(value)
+		}
+	}
+	return guarded
+}
+
+// releaseLabels releases the guarded label values returned by acquireLabels
+func releaseLabels(guards []*labelGuard, guarded []string) {
+	for i, value := range guarded {
+		if i < len(guards) && guards[i] != nil && value != OverflowValue {
+			guards[i].release(value)
+		}
+	}
+}
+
+// joinLabels returns the key of the label values
+func joinLabels(labels []string) string {
+	key := ""
+	for i, l := range labels {
+		if i > 0 {
+			key += "\x00"
+		}
+		key += l
+	}
+	return key
+}
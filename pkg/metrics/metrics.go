@@ -0,0 +1,329 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package metrics provides the Prometheus metrics of ODLM.
+//
+// The metrics are registered on the controller-runtime metrics registry,
+// and exported on the metrics endpoint of the manager.
+// The namespace, name, request and operator labels are bounded by a cardinality guard,
+// their values beyond the maximum are recorded with an overflow value.
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	namespaceLabel  = "namespace"
+	registryLabel   = "registry"
+	operatorLabel   = "operator"
+	requestLabel    = "request"
+	nameLabel       = "name"
+	phaseLabel      = "phase"
+	controllerLabel = "controller"
+	kindLabel       = "kind"
+)
+
+var (
+	// OperandRequestPhase is 1 for the current phase of each OperandRequest
+	OperandRequestPhase = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "odlm_operandrequest_phase",
+		Help: "The current phase of the OperandRequest, the value is 1 for the current phase, or the number of the OperandRequests in the phase for the overflow series.",
+	}, []string{namespaceLabel, nameLabel, phaseLabel})
+
+	// OperandPhase is 1 for the current phase of each operand of an OperandRequest
+	OperandPhase = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "odlm_operand_phase",
+		Help: "The current phase of the operand requested by the OperandRequest, the value is 1 for the current phase, or the number of the operands in the phase for the overflow series.",
+	}, []string{namespaceLabel, requestLabel, operatorLabel, phaseLabel})
+
+	// OperandDrift is the number of the drifted fields in the custom resources of each operand of an OperandRequest
+	OperandDrift = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "odlm_operand_drift",
+		Help: "The number of the fields of the custom resources of the operand drifted from the configuration, under the Detect remediation policy.",
+	}, []string{namespaceLabel, requestLabel, operatorLabel})
+
+	// SubscriptionInstallDuration is the time from the creation of the Subscription to the CSV succeeded
+	SubscriptionInstallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "odlm_subscription_install_duration_seconds",
+		Help:    "The time from the creation of the Subscription to the ClusterServiceVersion succeeded.",
+		Buckets: prometheus.ExponentialBuckets(10, 2, 10),
+	}, []string{registryLabel, operatorLabel, namespaceLabel})
+
+	// OperandCRUpdates counts the updates of the operand custom resources
+	OperandCRUpdates = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "odlm_operand_cr_update_total",
+		Help: "The number of the updates of the custom resources created by ODLM.",
+	}, []string{registryLabel, operatorLabel, namespaceLabel})
+
+	// MergeConflicts counts the merged custom resources rejected because they were changed in the meantime
+	MergeConflicts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "odlm_merge_conflicts_total",
+		Help: "The number of the merged custom resources rejected by a conflict, because they were changed during the merge.",
+	}, []string{registryLabel, operatorLabel, namespaceLabel})
+
+	// QueueWaitDuration is the time a request waits for its turn in the fair queue of a controller
+	QueueWaitDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "odlm_workqueue_namespace_wait_seconds",
+		Help:    "The time a request waits for its turn in the fair queue of the controller, by the namespace of the request.",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 14),
+	}, []string{controllerLabel, namespaceLabel})
+
+	// OrphanedObjects is the number of the orphaned objects found by the last garbage collection
+	OrphanedObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "odlm_orphaned_objects",
+		Help: "The number of the Subscriptions and ClusterServiceVersions created by ODLM and no longer requested, found by the last garbage collection.",
+	}, []string{kindLabel})
+
+	// GarbageCollected counts the orphaned objects deleted by the garbage collection
+	GarbageCollected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "odlm_garbage_collected_total",
+		Help: "The number of the orphaned Subscriptions and ClusterServiceVersions deleted by the garbage collection.",
+	}, []string{kindLabel})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		OperandRequestPhase,
+		OperandPhase,
+		OperandDrift,
+		SubscriptionInstallDuration,
+		OperandCRUpdates,
+		MergeConflicts,
+		QueueWaitDuration,
+		OrphanedObjects,
+		GarbageCollected,
+		LabelOverflows,
+	)
+}
+
+// phaseRecorder keeps the current phase of each object,
+// to remove the series of its previous phase from the gauge.
+// The value of a series is the number of the objects in the phase, several objects share
+// the series of the overflow value of a guarded label.
+type phaseRecorder struct {
+	sync.Mutex
+	gauge  *prometheus.GaugeVec
+	guards []*labelGuard
+	phases map[string]phaseSeries
+	counts map[string]int
+}
+
+type phaseSeries struct {
+	owner string
+	// labels are the labels of the object, without the phase
+	labels []string
+	// guarded are the labels of the series, with the phase
+	guarded []string
+}
+
+func newPhaseRecorder(gauge *prometheus.GaugeVec, metric string, labels ...string) *phaseRecorder {
+	return &phaseRecorder{gauge: gauge, guards: newLabelGuards(metric, labels...), phases: make(map[string]phaseSeries), counts: make(map[string]int)}
+}
+
+var (
+	requestPhases = newPhaseRecorder(OperandRequestPhase, "odlm_operandrequest_phase", namespaceLabel, nameLabel, phaseLabel)
+	operandPhases = newPhaseRecorder(OperandPhase, "odlm_operand_phase", namespaceLabel, requestLabel, operatorLabel, phaseLabel)
+
+	subscriptionInstalls = newGuardedLabels("odlm_subscription_install_duration_seconds", registryLabel, operatorLabel, namespaceLabel)
+	operandCRUpdates     = newGuardedLabels("odlm_operand_cr_update_total", registryLabel, operatorLabel, namespaceLabel)
+	mergeConflicts       = newGuardedLabels("odlm_merge_conflicts_total", registryLabel, operatorLabel, namespaceLabel)
+	queueWaits           = newGuardedLabels("odlm_workqueue_namespace_wait_seconds", controllerLabel, namespaceLabel)
+)
+
+// set records the object in the series of the phase and removes it from the series of the previous phase.
+// The labels are the labels of the gauge without the phase.
+func (p *phaseRecorder) set(owner, phase string, labels ...string) {
+	p.Lock()
+	defer p.Unlock()
+	key := owner
+	for _, l := range labels {
+		key += "/" + l
+	}
+	if previous, ok := p.phases[key]; ok {
+		if previous.guarded[len(previous.guarded)-1] == phase {
+			return
+		}
+		p.remove(key, previous)
+	}
+	series := phaseSeries{owner: owner, labels: labels, guarded: acquireLabels(p.guards, append(append([]string{}, labels...), phase))}
+	p.phases[key] = series
+	seriesKey := joinLabels(series.guarded)
+	p.counts[seriesKey]++
+	p.gauge.WithLabelValues(series.guarded...).Set(float64(p.counts[seriesKey]))
+}
+
+// delete removes the series of the owner.
+// The series of the other objects are removed when keep returns false.
+func (p *phaseRecorder) delete(owner string, keep func(labels []string) bool) {
+	p.Lock()
+	defer p.Unlock()
+	for key, series := range p.phases {
+		if series.owner != owner || (keep != nil && keep(series.labels)) {
+			continue
+		}
+		p.remove(key, series)
+	}
+}
+
+// remove removes the object from its series, the series is deleted when it has no object left
+func (p *phaseRecorder) remove(key string, series phaseSeries) {
+	delete(p.phases, key)
+	releaseLabels(p.guards, series.guarded)
+	seriesKey := joinLabels(series.guarded)
+	if p.counts[seriesKey]--; p.counts[seriesKey] > 0 {
+		p.gauge.WithLabelValues(series.guarded...).Set(float64(p.counts[seriesKey]))
+		return
+	}
+	delete(p.counts, seriesKey)
+	p.gauge.DeleteLabelValues(series.guarded...)
+}
+
+// valueRecorder keeps the value of each object. The value of a series is the sum of the values of its objects,
+// several objects share the series of the overflow value of a guarded label.
+type valueRecorder struct {
+	sync.Mutex
+	gauge  *prometheus.GaugeVec
+	guards []*labelGuard
+	values map[string]valueSeries
+	sums   map[string]float64
+	counts map[string]int
+}
+
+type valueSeries struct {
+	owner   string
+	labels  []string
+	guarded []string
+	value   float64
+}
+
+func newValueRecorder(gauge *prometheus.GaugeVec, metric string, labels ...string) *valueRecorder {
+	return &valueRecorder{gauge: gauge, guards: newLabelGuards(metric, labels...), values: make(map[string]valueSeries), sums: make(map[string]float64), counts: make(map[string]int)}
+}
+
+var operandDrifts = newValueRecorder(OperandDrift, "odlm_operand_drift", namespaceLabel, requestLabel, operatorLabel)
+
+// set records the value of the object
+func (v *valueRecorder) set(owner string, value float64, labels ...string) {
+	v.Lock()
+	defer v.Unlock()
+	key := joinLabels(labels)
+	series, ok := v.values[key]
+	if !ok {
+		series = valueSeries{owner: owner, labels: labels, guarded: acquireLabels(v.guards, labels)}
+		v.counts[joinLabels(series.guarded)]++
+	}
+	seriesKey := joinLabels(series.guarded)
+	v.sums[seriesKey] += value - series.value
+	series.value = value
+	v.values[key] = series
+	v.gauge.WithLabelValues(series.guarded...).Set(v.sums[seriesKey])
+}
+
+// delete removes the values of the owner.
+// The values of the other objects are removed when keep returns false.
+func (v *valueRecorder) delete(owner string, keep func(labels []string) bool) {
+	v.Lock()
+	defer v.Unlock()
+	for key, series := range v.values {
+		if series.owner != owner || (keep != nil && keep(series.labels)) {
+			continue
+		}
+		delete(v.values, key)
+		releaseLabels(v.guards, series.guarded)
+		seriesKey := joinLabels(series.guarded)
+		v.sums[seriesKey] -= series.value
+		if v.counts[seriesKey]--; v.counts[seriesKey] > 0 {
+			v.gauge.WithLabelValues(series.guarded...).Set(v.sums[seriesKey])
+			continue
+		}
+		delete(v.counts, seriesKey)
+		delete(v.sums, seriesKey)
+		v.gauge.DeleteLabelValues(series.guarded...)
+	}
+}
+
+// SetOperandRequestPhase records the phase of the OperandRequest
+func SetOperandRequestPhase(namespace, name, phase string) {
+	requestPhases.set(namespace+"/"+name, phase, namespace, name)
+}
+
+// SetOperandPhases records the phases of the operands of the OperandRequest,
+// keyed by the operator name. The operands not in phases are removed.
+func SetOperandPhases(namespace, request string, phases map[string]string) {
+	owner := namespace + "/" + request
+	operandPhases.delete(owner, func(labels []string) bool {
+		_, ok := phases[labels[2]]
+		return ok
+	})
+	for operator, phase := range phases {
+		operandPhases.set(owner, phase, namespace, request, operator)
+	}
+}
+
+// SetOperandDrifts records the number of the drifted fields of the operands of the OperandRequest,
+// keyed by the operator name. The operands not in drifts are removed.
+func SetOperandDrifts(namespace, request string, drifts map[string]int) {
+	owner := namespace + "/" + request
+	operandDrifts.delete(owner, func(labels []string) bool {
+		_, ok := drifts[labels[2]]
+		return ok
+	})
+	for operator, count := range drifts {
+		operandDrifts.set(owner, float64(count), namespace, request, operator)
+	}
+}
+
+// DeleteOperandRequest removes the phases and the drifts of the OperandRequest and its operands
+func DeleteOperandRequest(namespace, name string) {
+	requestPhases.delete(namespace+"/"+name, nil)
+	operandPhases.delete(namespace+"/"+name, nil)
+	operandDrifts.delete(namespace+"/"+name, nil)
+}
+
+// ObserveSubscriptionInstall records the install duration of the operator
+func ObserveSubscriptionInstall(registry, operator, namespace string, duration time.Duration) {
+	SubscriptionInstallDuration.WithLabelValues(subscriptionInstalls.values(registry, operator, namespace)...).Observe(duration.Seconds())
+}
+
+// IncOperandCRUpdate counts an update of the custom resource of the operator
+func IncOperandCRUpdate(registry, operator, namespace string) {
+	OperandCRUpdates.WithLabelValues(operandCRUpdates.values(registry, operator, namespace)...).Inc()
+}
+
+// IncMergeConflict counts a merged custom resource of the operator rejected by a conflict
+func IncMergeConflict(registry, operator, namespace string) {
+	MergeConflicts.WithLabelValues(mergeConflicts.values(registry, operator, namespace)...).Inc()
+}
+
+// ObserveQueueWait records how long a request of the namespace waited for its turn in the fair queue of the controller
+func ObserveQueueWait(controller, namespace string, wait time.Duration) {
+	QueueWaitDuration.WithLabelValues(queueWaits.values(controller, namespace)...).Observe(wait.Seconds())
+}
+
+// SetOrphanedObjects records the number of the orphaned objects of the kind found by the last garbage collection
+func SetOrphanedObjects(kind string, count int) {
+	OrphanedObjects.WithLabelValues(kind).Set(float64(count))
+}
+
+// IncGarbageCollected counts an orphaned object of the kind deleted by the garbage collection
+func IncGarbageCollected(kind string) {
+	GarbageCollected.WithLabelValues(kind).Inc()
+}
@@ -0,0 +1,136 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package metrics
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Describe("Metrics", func() {
+	It("Should keep only the current phase of the OperandRequest", func() {
+		SetOperandRequestPhase("ns", "request", "Installing")
+		Expect(testutil.ToFloat64(OperandRequestPhase.WithLabelValues("ns", "request", "Installing"))).Should(Equal(float64(1)))
+
+		SetOperandRequestPhase("ns", "request", "Running")
+		Expect(testutil.CollectAndCount(OperandRequestPhase)).Should(Equal(1))
+		Expect(testutil.ToFloat64(OperandRequestPhase.WithLabelValues("ns", "request", "Running"))).Should(Equal(float64(1)))
+
+		DeleteOperandRequest("ns", "request")
+		Expect(testutil.CollectAndCount(OperandRequestPhase)).Should(Equal(0))
+	})
+
+	It("Should remove the operands no longer requested", func() {
+		SetOperandPhases("ns", "request", map[string]string{"etcd": "Creating", "jenkins": "Running"})
+		Expect(testutil.CollectAndCount(OperandPhase)).Should(Equal(2))
+
+		SetOperandPhases("ns", "request", map[string]string{"etcd": "Running"})
+		Expect(testutil.CollectAndCount(OperandPhase)).Should(Equal(1))
+		Expect(testutil.ToFloat64(OperandPhase.WithLabelValues("ns", "request", "etcd", "Running"))).Should(Equal(float64(1)))
+
+		DeleteOperandRequest("ns", "request")
+		Expect(testutil.CollectAndCount(OperandPhase)).Should(Equal(0))
+	})
+
+	It("Should record the drifts of the operands", func() {
+		SetOperandDrifts("ns", "request", map[string]int{"etcd": 2, "jenkins": 1})
+		Expect(testutil.CollectAndCount(OperandDrift)).Should(Equal(2))
+		Expect(testutil.ToFloat64(OperandDrift.WithLabelValues("ns", "request", "etcd"))).Should(Equal(float64(2)))
+
+		SetOperandDrifts("ns", "request", map[string]int{"etcd": 3})
+		Expect(testutil.CollectAndCount(OperandDrift)).Should(Equal(1))
+		Expect(testutil.ToFloat64(OperandDrift.WithLabelValues("ns", "request", "etcd"))).Should(Equal(float64(3)))
+
+		DeleteOperandRequest("ns", "request")
+		Expect(testutil.CollectAndCount(OperandDrift)).Should(Equal(0))
+	})
+
+	It("Should sum the drifts sharing the overflow series", func() {
+		SetMaxLabelValues(1)
+		defer SetMaxLabelValues(DefaultMaxLabelValues)
+
+		SetOperandDrifts("tenant-a", "request", map[string]int{"etcd": 1})
+		SetOperandDrifts("tenant-b", "request", map[string]int{"etcd": 2})
+		SetOperandDrifts("tenant-c", "request", map[string]int{"etcd": 4})
+		Expect(testutil.ToFloat64(OperandDrift.WithLabelValues(OverflowValue, "request", "etcd"))).Should(Equal(float64(6)))
+
+		DeleteOperandRequest("tenant-b", "request")
+		Expect(testutil.ToFloat64(OperandDrift.WithLabelValues(OverflowValue, "request", "etcd"))).Should(Equal(float64(4)))
+
+		DeleteOperandRequest("tenant-a", "request")
+		DeleteOperandRequest("tenant-c", "request")
+		Expect(testutil.CollectAndCount(OperandDrift)).Should(Equal(0))
+	})
+
+	It("Should count the updates and the conflicts", func() {
+		IncOperandCRUpdate("ibm-common-services/common-service", "etcd", "ibm-common-services")
+		IncMergeConflict("ibm-common-services/common-service", "etcd", "ibm-common-services")
+		ObserveSubscriptionInstall("ibm-common-services/common-service", "etcd", "ibm-common-services", time.Minute)
+		Expect(testutil.ToFloat64(OperandCRUpdates.WithLabelValues("ibm-common-services/common-service", "etcd", "ibm-common-services"))).Should(Equal(float64(1)))
+		Expect(testutil.ToFloat64(MergeConflicts.WithLabelValues("ibm-common-services/common-service", "etcd", "ibm-common-services"))).Should(Equal(float64(1)))
+		Expect(testutil.CollectAndCount(SubscriptionInstallDuration)).Should(Equal(1))
+	})
+
+	It("Should record the labels beyond the maximum with the overflow value", func() {
+		SetMaxLabelValues(2)
+		defer SetMaxLabelValues(DefaultMaxLabelValues)
+
+		SetOperandRequestPhase("tenant-a", "request", "Running")
+		SetOperandRequestPhase("tenant-b", "request", "Running")
+		SetOperandRequestPhase("tenant-c", "request", "Running")
+		SetOperandRequestPhase("tenant-d", "request", "Running")
+		Expect(testutil.CollectAndCount(OperandRequestPhase)).Should(Equal(3))
+		Expect(testutil.ToFloat64(OperandRequestPhase.WithLabelValues(OverflowValue, "request", "Running"))).Should(Equal(float64(2)))
+		Expect(testutil.ToFloat64(LabelOverflows.WithLabelValues("odlm_operandrequest_phase", namespaceLabel))).Should(Equal(float64(2)))
+
+		// The phase of an object in the overflow series moves to the overflow series of its new phase
+		SetOperandRequestPhase("tenant-c", "request", "Failed")
+		Expect(testutil.ToFloat64(OperandRequestPhase.WithLabelValues(OverflowValue, "request", "Running"))).Should(Equal(float64(1)))
+		Expect(testutil.ToFloat64(OperandRequestPhase.WithLabelValues(OverflowValue, "request", "Failed"))).Should(Equal(float64(1)))
+
+		// The values of the deleted objects are released
+		DeleteOperandRequest("tenant-a", "request")
+		DeleteOperandRequest("tenant-c", "request")
+		SetOperandRequestPhase("tenant-e", "request", "Running")
+		Expect(testutil.ToFloat64(OperandRequestPhase.WithLabelValues("tenant-e", "request", "Running"))).Should(Equal(float64(1)))
+		for _, ns := range []string{"tenant-b", "tenant-d", "tenant-e"} {
+			DeleteOperandRequest(ns, "request")
+		}
+		Expect(testutil.CollectAndCount(OperandRequestPhase)).Should(Equal(0))
+
+		// The counters and the histograms keep the admitted values
+		updates := newGuardedLabels("odlm_operand_cr_update_total", registryLabel, operatorLabel, namespaceLabel)
+		Expect(updates.values("ibm-common-services/common-service", "etcd", "tenant-a")).Should(Equal([]string{"ibm-common-services/common-service", "etcd", "tenant-a"}))
+		Expect(updates.values("ibm-common-services/common-service", "etcd", "tenant-b")).Should(Equal([]string{"ibm-common-services/common-service", "etcd", "tenant-b"}))
+		Expect(updates.values("ibm-common-services/common-service", "etcd", "tenant-c")).Should(Equal([]string{"ibm-common-services/common-service", "etcd", OverflowValue}))
+		Expect(updates.values("ibm-common-services/common-service", "etcd", "tenant-a")).Should(Equal([]string{"ibm-common-services/common-service", "etcd", "tenant-a"}))
+	})
+
+	It("Should not bound the labels when the guard is disabled", func() {
+		SetMaxLabelValues(0)
+		defer SetMaxLabelValues(DefaultMaxLabelValues)
+
+		for _, ns := range []string{"ns-1", "ns-2", "ns-3"} {
+			ObserveQueueWait("operandrequest", ns, time.Second)
+		}
+		Expect(testutil.CollectAndCount(QueueWaitDuration)).Should(Equal(3))
+	})
+})
@@ -0,0 +1,123 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package render
+
+import (
+	"fmt"
+	"io"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	operatorv1beta2 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1beta2"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/bundle"
+)
+
+// Load decodes the OperandRegistries, the OperandConfigs, the OperandRequests and the ClusterServiceVersions
+// of the multi-document YAML or JSON files into the input. The objects without a namespace are in the namespace,
+// like with kubectl apply. The other kinds of objects are ignored, so the files of a GitOps repository can be
+// loaded as they are.
+func Load(namespace string, files ...[]byte) (*Input, error) {
+	in := &Input{}
+	for _, data := range files {
+		objs, err := bundle.Parse(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objs {
+			if obj.GetNamespace() == "" {
+				obj.SetNamespace(namespace)
+			}
+			if err := in.add(obj); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return in, nil
+}
+
+// add converts the object to its type and adds it to the input
+func (in *Input) add(obj unstructured.Unstructured) error {
+	gvk := obj.GroupVersionKind()
+	var into interface{}
+	switch {
+	case gvk.GroupVersion() == operatorv1alpha1.GroupVersion && gvk.Kind == "OperandRegistry":
+		in.Registries = append(in.Registries, operatorv1alpha1.OperandRegistry{})
+		into = &in.Registries[len(in.Registries)-1]
+	case gvk.GroupVersion() == operatorv1alpha1.GroupVersion && gvk.Kind == "OperandConfig":
+		in.Configs = append(in.Configs, operatorv1alpha1.OperandConfig{})
+		into = &in.Configs[len(in.Configs)-1]
+	case gvk.GroupVersion() == operatorv1alpha1.GroupVersion && gvk.Kind == "OperandRequest":
+		in.Requests = append(in.Requests, operatorv1alpha1.OperandRequest{})
+		into = &in.Requests[len(in.Requests)-1]
+	case gvk.GroupVersion() == olmv1alpha1.SchemeGroupVersion && gvk.Kind == olmv1alpha1.ClusterServiceVersionKind:
+		in.ClusterServiceVersions = append(in.ClusterServiceVersions, olmv1alpha1.ClusterServiceVersion{})
+		into = &in.ClusterServiceVersions[len(in.ClusterServiceVersions)-1]
+	case gvk.GroupVersion() == operatorv1beta2.GroupVersion && gvk.Kind == "OperandConfig":
+		// The v1beta2 OperandConfig is converted to the v1alpha1 one the controllers read
+		config := &operatorv1beta2.OperandConfig{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, config); err != nil {
+			return errors.Wrapf(err, "failed to decode the %s %s/%s", gvk.Kind, obj.GetNamespace(), obj.GetName())
+		}
+		hub := operatorv1alpha1.OperandConfig{}
+		if err := config.ConvertTo(&hub); err != nil {
+			return errors.Wrapf(err, "failed to convert the %s %s/%s", gvk.Kind, obj.GetNamespace(), obj.GetName())
+		}
+		in.Configs = append(in.Configs, hub)
+		return nil
+	default:
+		return nil
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, into); err != nil {
+		return errors.Wrapf(err, "failed to decode the %s %s/%s", gvk.Kind, obj.GetNamespace(), obj.GetName())
+	}
+	return nil
+}
+
+// Write writes the rendered objects to w as YAML documents, the Subscriptions first.
+// The skipped operands are written as comments ahead of them.
+func Write(w io.Writer, out *Output) error {
+	for _, skipped := range out.Skipped {
+		fmt.Fprintf(w, "# %s\n", skipped)
+	}
+	objs := make([]map[string]interface{}, 0, len(out.Subscriptions)+len(out.CustomResources))
+	for i := range out.Subscriptions {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&out.Subscriptions[i])
+		if err != nil {
+			return errors.Wrapf(err, "failed to convert the Subscription %s/%s", out.Subscriptions[i].Namespace, out.Subscriptions[i].Name)
+		}
+		// The Subscriptions are created without a status
+		delete(obj, "status")
+		unstructured.RemoveNestedField(obj, "metadata", "creationTimestamp")
+		objs = append(objs, obj)
+	}
+	for _, cr := range out.CustomResources {
+		objs = append(objs, cr.Object)
+	}
+	for _, obj := range objs {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return errors.Wrap(err, "failed to write the rendered objects")
+		}
+		fmt.Fprintf(w, "---\n%s", data)
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package render renders offline the Subscriptions and the custom resources ODLM creates for the OperandRequests,
+// from the OperandRegistries, the OperandConfigs and the OperandRequests. The pipelines can diff and scan them
+// before anything is applied to the cluster. The operands are resolved and merged with the code of the controllers.
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/operandrequest"
+	deploy "github.com/IBM/operand-deployment-lifecycle-manager/controllers/operator"
+	"github.com/IBM/operand-deployment-lifecycle-manager/controllers/provenance"
+)
+
+// Input holds the resources the OperandRequests are rendered from
+type Input struct {
+	Registries []operatorv1alpha1.OperandRegistry
+	Configs    []operatorv1alpha1.OperandConfig
+	Requests   []operatorv1alpha1.OperandRequest
+	// ClusterServiceVersions provide the alm-examples the services of the OperandConfigs are merged into
+	ClusterServiceVersions []olmv1alpha1.ClusterServiceVersion
+}
+
+// Output holds the resources ODLM creates for the OperandRequests
+type Output struct {
+	Subscriptions   []olmv1alpha1.Subscription
+	CustomResources []unstructured.Unstructured
+	// Skipped tells why some operands, or some of their custom resources, are not rendered
+	Skipped []string
+}
+
+func (o *Output) skip(format string, args ...interface{}) {
+	o.Skipped = append(o.Skipped, fmt.Sprintf(format, args...))
+}
+
+// addSubscription adds the Subscription, the annotations of the OperandRequests sharing it are merged
+func (o *Output) addSubscription(sub *olmv1alpha1.Subscription) {
+	for i := range o.Subscriptions {
+		existing := &o.Subscriptions[i]
+		if existing.Namespace == sub.Namespace && existing.Name == sub.Name {
+			for k, v := range sub.Annotations {
+				existing.Annotations[k] = v
+			}
+			return
+		}
+	}
+	o.Subscriptions = append(o.Subscriptions, *sub)
+}
+
+// Render renders the Subscriptions and the custom resources of the OperandRequests of the input.
+// The operands which can't be rendered offline are reported in the Skipped of the output: the operands
+// missing from the OperandRegistry, the private operators requested from other namespaces, the operators
+// not installed by OLM, and the custom resources without an alm-example in the ClusterServiceVersions.
+// The size profiles, the replicas and the placement rendered from the cluster are not applied.
+func Render(in *Input) (*Output, error) {
+	out := &Output{}
+	for i := range in.Requests {
+		req := &in.Requests[i]
+		requestKey := types.NamespacedName{Namespace: req.Namespace, Name: req.Name}
+		for _, r := range req.Spec.Requests {
+			registryKey := req.GetRegistryKey(r)
+			registry := in.getRegistry(registryKey)
+			if registry == nil {
+				out.skip("The OperandRegistry %s requested by the OperandRequest %s is not found", registryKey, requestKey)
+				continue
+			}
+			config := in.getConfig(registryKey)
+
+			// The implicit operands follow the requested ones, the index of a requested operand is unchanged
+			for index, operand := range registry.WithRequiredOperands(r.Operands) {
+				op := registry.GetOperator(operand.Name)
+				if op == nil {
+					out.skip("The operand %s of the OperandRequest %s is not found in the OperandRegistry %s", operand.Name, requestKey, registryKey)
+					continue
+				}
+				op = operandrequest.PinnedOperator(op, operand)
+				if op.Scope == operatorv1alpha1.ScopePrivate && req.Namespace != registry.Namespace {
+					out.skip("The operator %s is private, it can't be requested from the namespace %s", op.Name, req.Namespace)
+					continue
+				}
+				if op.GetType() == operatorv1alpha1.OperatorTypeOLM {
+					out.addSubscription(operandrequest.NewSubscription(op, deploy.OperatorNamespace(op.InstallMode, op.Namespace), registryKey, requestKey))
+				} else {
+					out.skip("The operator %s is installed by %s, only the Subscriptions of OLM are rendered", op.Name, op.GetType())
+				}
+
+				if operand.Kind != "" {
+					cr, err := RequestedCustomResource(req, operand, index)
+					if err != nil {
+						return nil, err
+					}
+					out.CustomResources = append(out.CustomResources, cr)
+					continue
+				}
+				if config == nil {
+					continue
+				}
+				service := config.GetService(operand.Name)
+				if service == nil {
+					continue
+				}
+				crs, missing, err := ConfiguredCustomResources(req, service, op.Namespace, in.ClusterServiceVersions)
+				if err != nil {
+					return nil, err
+				}
+				out.CustomResources = append(out.CustomResources, crs...)
+				for _, kind := range missing {
+					out.skip("The custom resource %s of the operand %s has no alm-example in the ClusterServiceVersions", kind, operand.Name)
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+// getRegistry returns the OperandRegistry with the defaults of its operators, nil when it isn't in the input
+func (in *Input) getRegistry(key types.NamespacedName) *operatorv1alpha1.OperandRegistry {
+	for i := range in.Registries {
+		if in.Registries[i].Namespace == key.Namespace && in.Registries[i].Name == key.Name {
+			registry := in.Registries[i].DeepCopy()
+			for j := range registry.Spec.Operators {
+				deploy.SetOperatorDefaults(&registry.Spec.Operators[j])
+			}
+			return registry
+		}
+	}
+	return nil
+}
+
+// getConfig returns the OperandConfig of the OperandRegistry, nil when it isn't in the input
+func (in *Input) getConfig(key types.NamespacedName) *operatorv1alpha1.OperandConfig {
+	for i := range in.Configs {
+		if in.Configs[i].Namespace == key.Namespace && in.Configs[i].Name == key.Name {
+			return &in.Configs[i]
+		}
+	}
+	return nil
+}
+
+// RequestedCustomResource renders the custom resource of an operand with a kind, in the namespace of the OperandRequest,
+// with the JSON6902 patches of the operand
+func RequestedCustomResource(req *operatorv1alpha1.OperandRequest, operand operatorv1alpha1.Operand, index int) (unstructured.Unstructured, error) {
+	var crTemplate unstructured.Unstructured
+	crTemplate.SetAPIVersion(operand.APIVersion)
+	crTemplate.SetKind(operand.Kind)
+	crTemplate.SetName(operandrequest.OperandInstanceName(req.Name, operand, index))
+	var crConfig []byte
+	if operand.Spec != nil {
+		crConfig = operand.Spec.Raw
+	}
+	cr := operandrequest.RenderCustomResource(req, crTemplate, req.Namespace, crConfig, nil)
+	merged, _ := cr.Object["spec"].(map[string]interface{})
+	if err := operandrequest.PatchCustomResource(&cr, operand.Patches); err != nil {
+		return unstructured.Unstructured{}, err
+	}
+	operandrequest.AnnotateProvenance(&cr, crTemplate, crConfig, provenance.SourceOperandRequest, merged)
+	return cr, nil
+}
+
+// ConfiguredCustomResources renders the custom resources of the service of the OperandConfig in the namespace,
+// the configuration of each kind is merged into the alm-examples of the kind in the ClusterServiceVersions.
+// It returns the kinds of the service without an alm-example too.
+func ConfiguredCustomResources(req *operatorv1alpha1.OperandRequest, service *operatorv1alpha1.ConfigService, namespace string, csvs []olmv1alpha1.ClusterServiceVersion) ([]unstructured.Unstructured, []string, error) {
+	kinds := make([]string, 0, len(service.Spec))
+	for kind := range service.Spec {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	found := make(map[string]bool)
+	var crs []unstructured.Unstructured
+	for _, csv := range csvs {
+		almExamples := csv.GetAnnotations()["alm-examples"]
+		if almExamples == "" {
+			continue
+		}
+		var almExampleList []map[string]interface{}
+		if err := json.Unmarshal([]byte(almExamples), &almExampleList); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to convert alm-examples in the ClusterServiceVersion %s to slice", csv.Name)
+		}
+		for _, almExample := range almExampleList {
+			crFromALM := unstructured.Unstructured{Object: almExample}
+			if crFromALM.Object["spec"] == nil {
+				continue
+			}
+			for _, kind := range kinds {
+				if strings.EqualFold(crFromALM.GetKind(), kind) {
+					found[kind] = true
+					cr := operandrequest.RenderCustomResource(req, crFromALM, namespace, service.Spec[kind].Raw, service.Backup)
+					operandrequest.AnnotateProvenance(&cr, crFromALM, service.Spec[kind].Raw, provenance.SourceOperandConfig, nil)
+					crs = append(crs, cr)
+				}
+			}
+		}
+	}
+
+	var missing []string
+	for _, kind := range kinds {
+		if !found[kind] {
+			missing = append(missing, kind)
+		}
+	}
+	return crs, missing, nil
+}
@@ -0,0 +1,246 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package render
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	olmv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+)
+
+const registryYAML = `
+apiVersion: operator.ibm.com/v1alpha1
+kind: OperandRegistry
+metadata:
+  name: common-service
+spec:
+  operators:
+  - name: etcd
+    namespace: ibm-common-services
+    packageName: etcd
+    channel: stable
+    sourceName: community-operators
+    sourceNamespace: olm
+  - name: jenkins
+    namespace: ibm-common-services
+    packageName: jenkins
+    channel: alpha
+    sourceName: community-operators
+    sourceNamespace: olm
+    installMode: cluster
+  - name: private
+    namespace: ibm-common-services
+    packageName: private
+    channel: stable
+    scope: private
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ignored
+`
+
+const configYAML = `
+apiVersion: operator.ibm.com/v1alpha1
+kind: OperandConfig
+metadata:
+  name: common-service
+spec:
+  services:
+  - name: etcd
+    spec:
+      etcdCluster:
+        size: 3
+      etcdRestore:
+        backupName: daily
+`
+
+const requestYAML = `
+apiVersion: operator.ibm.com/v1alpha1
+kind: OperandRequest
+metadata:
+  name: my-request
+spec:
+  requests:
+  - registry: common-service
+    operands:
+    - name: etcd
+    - name: jenkins
+      apiVersion: jenkins.io/v1alpha2
+      kind: Jenkins
+      instanceName: my-jenkins
+      spec:
+        master:
+          replicas: 2
+`
+
+const csvYAML = `
+apiVersion: operators.coreos.com/v1alpha1
+kind: ClusterServiceVersion
+metadata:
+  name: etcdoperator.v0.9.4
+  annotations:
+    alm-examples: '[{"apiVersion":"etcd.database.coreos.com/v1beta2","kind":"EtcdCluster","metadata":{"name":"example"},"spec":{"size":1,"version":"3.2.13"}}]'
+`
+
+var _ = Describe("Rendering offline", func() {
+
+	load := func(files ...string) *Input {
+		data := make([][]byte, 0, len(files))
+		for _, f := range files {
+			data = append(data, []byte(f))
+		}
+		in, err := Load("ibm-common-services", data...)
+		Expect(err).NotTo(HaveOccurred())
+		return in
+	}
+
+	It("Should load the resources of the files by kind", func() {
+		in := load(registryYAML, configYAML, requestYAML, csvYAML)
+		Expect(in.Registries).To(HaveLen(1))
+		Expect(in.Registries[0].Namespace).To(Equal("ibm-common-services"))
+		Expect(in.Configs).To(HaveLen(1))
+		Expect(in.Requests).To(HaveLen(1))
+		Expect(in.ClusterServiceVersions).To(HaveLen(1))
+	})
+
+	It("Should load the v1beta2 OperandConfig", func() {
+		in := load(`
+apiVersion: operator.ibm.com/v1beta2
+kind: OperandConfig
+metadata:
+  name: common-service
+spec:
+  services:
+  - name: etcd
+    customResources:
+    - kind: etcdCluster
+      spec:
+        size: 3
+`)
+		Expect(in.Configs).To(HaveLen(1))
+		Expect(in.Configs[0].GetService("etcd").Spec).To(HaveKey("etcdCluster"))
+	})
+
+	It("Should fail on the invalid files", func() {
+		_, err := Load("default", []byte("kind: [OperandRequest"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should render the Subscriptions and the custom resources of the OperandRequest", func() {
+		out, err := Render(load(registryYAML, configYAML, requestYAML, csvYAML))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(out.Subscriptions).To(HaveLen(2))
+		etcd := out.Subscriptions[0]
+		Expect(etcd.Namespace).To(Equal("ibm-common-services"))
+		Expect(etcd.Labels).To(HaveKeyWithValue("operator.ibm.com/opreq-control", "true"))
+		Expect(etcd.Annotations).To(HaveKeyWithValue("ibm-common-services.my-request/request", "true"))
+		Expect(etcd.Spec.Channel).To(Equal("stable"))
+		Expect(etcd.Spec.InstallPlanApproval).To(Equal(olmv1alpha1.ApprovalAutomatic))
+		Expect(out.Subscriptions[1].Namespace).To(Equal("openshift-operators"))
+
+		Expect(out.CustomResources).To(HaveLen(2))
+		cluster := out.CustomResources[0]
+		Expect(cluster.GetKind()).To(Equal("EtcdCluster"))
+		Expect(cluster.GetNamespace()).To(Equal("ibm-common-services"))
+		Expect(cluster.Object["spec"]).To(Equal(map[string]interface{}{"size": float64(3), "version": "3.2.13"}))
+		jenkins := out.CustomResources[1]
+		Expect(jenkins.GetName()).To(Equal("my-jenkins"))
+		Expect(jenkins.Object["spec"]).To(Equal(map[string]interface{}{"master": map[string]interface{}{"replicas": float64(2)}}))
+
+		Expect(out.Skipped).To(ConsistOf("The custom resource etcdRestore of the operand etcd has no alm-example in the ClusterServiceVersions"))
+	})
+
+	It("Should merge the Subscriptions shared by the OperandRequests", func() {
+		in := load(registryYAML, requestYAML)
+		other := in.Requests[0].DeepCopy()
+		other.Name = "other"
+		in.Requests = append(in.Requests, *other)
+
+		out, err := Render(in)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.Subscriptions).To(HaveLen(2))
+		Expect(out.Subscriptions[0].Annotations).To(HaveKey("ibm-common-services.my-request/request"))
+		Expect(out.Subscriptions[0].Annotations).To(HaveKey("ibm-common-services.other/request"))
+	})
+
+	It("Should skip the operands which can't be rendered", func() {
+		in := load(registryYAML, requestYAML)
+		in.Requests[0].Namespace = "team-a"
+		in.Requests[0].Spec.Requests[0].RegistryNamespace = "ibm-common-services"
+		in.Requests[0].Spec.Requests[0].Operands = in.Requests[0].Spec.Requests[0].Operands[:1]
+		in.Requests[0].Spec.Requests[0].Operands[0].Name = "private"
+		in.Requests[0].Spec.Requests = append(in.Requests[0].Spec.Requests, in.Requests[0].Spec.Requests[0])
+		in.Requests[0].Spec.Requests[1].Registry = "missing"
+
+		out, err := Render(in)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.Skipped).To(ConsistOf(
+			"The operator private is private, it can't be requested from the namespace team-a",
+			"The OperandRegistry ibm-common-services/missing requested by the OperandRequest team-a/my-request is not found",
+		))
+	})
+
+	It("Should write the rendered objects as YAML documents", func() {
+		out, err := Render(load(registryYAML, requestYAML))
+		Expect(err).NotTo(HaveOccurred())
+		out.Subscriptions = out.Subscriptions[:1]
+		out.Skipped = []string{"skipped"}
+
+		buf := &bytes.Buffer{}
+		Expect(Write(buf, out)).To(Succeed())
+		Expect(buf.String()).To(Equal(`# skipped
+---
+apiVersion: operators.coreos.com/v1alpha1
+kind: Subscription
+metadata:
+  annotations:
+    ibm-common-services.common-service/config: "true"
+    ibm-common-services.common-service/registry: "true"
+    ibm-common-services.my-request/request: "true"
+  labels:
+    operator.ibm.com/opreq-control: "true"
+  name: etcd
+  namespace: ibm-common-services
+spec:
+  channel: stable
+  installPlanApproval: Automatic
+  name: etcd
+  source: community-operators
+  sourceNamespace: olm
+---
+apiVersion: jenkins.io/v1alpha2
+kind: Jenkins
+metadata:
+  annotations:
+    ibm-common-services.my-request/request: "true"
+    operator.ibm.com/odlm-field-provenance: '{"master":"OperandRequest"}'
+    operator.ibm.com/odlm-last-applied-config: '{"master":{"replicas":2}}'
+  labels:
+    ibm-common-services.my-request/request: "true"
+    operator.ibm.com/opreq-control: "true"
+  name: my-jenkins
+  namespace: ibm-common-services
+spec:
+  master:
+    replicas: 2
+`))
+	})
+})
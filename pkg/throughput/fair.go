@@ -0,0 +1,236 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package throughput
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/metrics"
+)
+
+// FairQueue dispatches the reconciles of a controller round robin across the namespaces,
+// so a namespace with a burst of changes doesn't starve the reconciles of the other namespaces.
+//
+// The work queue of a controller can't be replaced in controller-runtime, so the FairQueue wraps its reconciler.
+// A request popped from the work queue isn't reconciled, it is added to the lane of its namespace. The FairQueue
+// takes the requests from the lanes in turn, and sends them back to the work queue through its source, no more
+// than the concurrent reconciles of the controller at once. Only the requests sent back are reconciled.
+// The requests requeued after a failure or a delay go through the lanes too, once the work queue pops them.
+//
+// The work queue forgets the failures of the requests set aside in the lanes, they are reconciled without error,
+// so the FairQueue keeps the failures itself. A failed request is added back to its lane after its backoff,
+// with the rate limiter of the controller, and the work queue retrying it sooner is ignored.
+//
+// The requests may have a priority, the lanes of the higher priorities take their turns first, and the lanes
+// of a lower priority wait until the higher ones are empty.
+type FairQueue struct {
+	name     string
+	enabled  bool
+	window   int
+	events   chan event.GenericEvent
+	priority PriorityFunc
+
+	mu sync.Mutex
+	// lanes are the requests waiting for their turn, by priority and namespace
+	lanes map[lane][]reconcile.Request
+	// turns are the namespaces with waiting requests by priority, in the order of their turns
+	turns map[int][]string
+	// arrived is when the waiting requests were added to their lane
+	arrived map[reconcile.Request]time.Time
+	// dispatched are the requests sent back to the work queue and not reconciled yet, with when they arrived
+	dispatched map[reconcile.Request]time.Time
+	// backoff counts the failures of the requests and returns the delays of their retries
+	backoff workqueue.RateLimiter
+	// retrying are the failed requests waiting for their backoff before they are added back to their lane
+	retrying map[reconcile.Request]bool
+}
+
+// PriorityFunc returns the priority of a request, the higher first
+type PriorityFunc func(req reconcile.Request) int
+
+// lane holds the waiting requests of a namespace with the same priority
+type lane struct {
+	priority  int
+	namespace string
+}
+
+// NewFairQueue returns the fair queue of the named controller, with the throughput ODLM is running with
+func NewFairQueue(name string) *FairQueue {
+	t := Get()
+	c := t.ForController(name)
+	window := c.MaxConcurrentReconciles
+	if window < 1 {
+		window = 1
+	}
+	rateLimiter := operatorv1alpha1.RateLimiter{}
+	if c.RateLimiter != nil {
+		rateLimiter = *c.RateLimiter
+	}
+	return &FairQueue{
+		name:       name,
+		enabled:    c.FairQueue == nil || *c.FairQueue,
+		window:     window,
+		events:     make(chan event.GenericEvent, 1024),
+		lanes:      make(map[lane][]reconcile.Request),
+		turns:      make(map[int][]string),
+		arrived:    make(map[reconcile.Request]time.Time),
+		dispatched: make(map[reconcile.Request]time.Time),
+		backoff:    NewRateLimiter(rateLimiter),
+		retrying:   make(map[reconcile.Request]bool),
+	}
+}
+
+// WithPriority sets the priority of the requests, they all have the same priority without it
+func (f *FairQueue) WithPriority(priority PriorityFunc) *FairQueue {
+	f.priority = priority
+	return f
+}
+
+// Source returns the source of the requests dispatched to the work queue,
+// the controller watches it with the handler.EnqueueRequestForObject
+func (f *FairQueue) Source() source.Source {
+	return &source.Channel{Source: f.events}
+}
+
+// Reconciler wraps the reconciler of the controller, it only reconciles the requests when their turn comes.
+// The reconciler is returned as it is when the fair queue is disabled.
+func (f *FairQueue) Reconciler(r reconcile.Reconciler) reconcile.Reconciler {
+	if !f.enabled {
+		return r
+	}
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (result reconcile.Result, err error) {
+		if !f.start(req) {
+			return reconcile.Result{}, nil
+		}
+		defer func() {
+			// The work queue rate limits the requests requeued without a delay like the failed ones
+			f.finish(req, err != nil || (result.Requeue && result.RequeueAfter <= 0))
+		}()
+		return r.Reconcile(ctx, req)
+	})
+}
+
+// start returns true if the request is dispatched, and records how long it waited for its turn.
+// Otherwise, the request is added to the lane of its namespace and priority, unless it waits for its backoff.
+func (f *FairQueue) start(req reconcile.Request) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if arrived, ok := f.dispatched[req]; ok {
+		metrics.ObserveQueueWait(f.name, req.Namespace, time.Since(arrived))
+		return true
+	}
+	if !f.retrying[req] {
+		f.add(req)
+	}
+	f.dispatch()
+	return false
+}
+
+// add adds the request to the lane of its namespace and priority, unless it is already waiting there
+func (f *FairQueue) add(req reconcile.Request) {
+	if _, ok := f.arrived[req]; !ok {
+		f.arrived[req] = time.Now()
+		l := lane{namespace: req.Namespace}
+		if f.priority != nil {
+			l.priority = f.priority(req)
+		}
+		if len(f.lanes[l]) == 0 {
+			f.turns[l.priority] = append(f.turns[l.priority], l.namespace)
+		}
+		f.lanes[l] = append(f.lanes[l], req)
+	}
+}
+
+// finish frees the place of the reconciled request, and dispatches the next requests.
+// A failed request is added back to its lane after its backoff, the failures of the others are forgotten.
+func (f *FairQueue) finish(req reconcile.Request, failed bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.dispatched, req)
+	if failed {
+		f.retrying[req] = true
+		time.AfterFunc(f.backoff.When(req), func() {
+			f.retry(req)
+		})
+	} else {
+		f.backoff.Forget(req)
+	}
+	f.dispatch()
+}
+
+// retry adds the failed request back to its lane once its backoff is over
+func (f *FairQueue) retry(req reconcile.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.retrying, req)
+	f.add(req)
+	f.dispatch()
+}
+
+// dispatch sends the first request of each lane of the highest priority in turn to the work queue,
+// while there is room for them
+func (f *FairQueue) dispatch() {
+	for len(f.dispatched) < f.window && len(f.turns) > 0 {
+		priority := f.highest()
+		turns := f.turns[priority]
+		l := lane{priority: priority, namespace: turns[0]}
+		turns = turns[1:]
+		requests := f.lanes[l]
+		req := requests[0]
+		if len(requests) > 1 {
+			f.lanes[l] = requests[1:]
+			turns = append(turns, l.namespace)
+		} else {
+			delete(f.lanes, l)
+		}
+		if len(turns) > 0 {
+			f.turns[priority] = turns
+		} else {
+			delete(f.turns, priority)
+		}
+		f.dispatched[req] = f.arrived[req]
+		delete(f.arrived, req)
+
+		obj := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: req.Namespace}}
+		// The source may be busy, don't block the reconciles on it
+		go func() {
+			f.events <- event.GenericEvent{Object: obj}
+		}()
+	}
+}
+
+// highest returns the highest priority with waiting requests
+func (f *FairQueue) highest() int {
+	first := true
+	var highest int
+	for priority := range f.turns {
+		if first || priority > highest {
+			highest = priority
+			first = false
+		}
+	}
+	return highest
+}
@@ -0,0 +1,169 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package throughput
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("FairQueue", func() {
+
+	request := func(namespace, name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}
+	}
+
+	It("Should reconcile the namespaces in turn", func() {
+		Setup(Options{MaxConcurrentReconciles: 1, FairQueue: true})
+		defer Setup(Options{})
+
+		var reconciled []reconcile.Request
+		f := NewFairQueue("operandrequest")
+		r := f.Reconciler(reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+			reconciled = append(reconciled, req)
+			return reconcile.Result{}, nil
+		}))
+		next := func() reconcile.Request {
+			var e event.GenericEvent
+			Eventually(f.events).Should(Receive(&e))
+			return request(e.Object.GetNamespace(), e.Object.GetName())
+		}
+
+		// A burst of requests in the namespace a, then a request in the namespace b
+		burst := []reconcile.Request{request("a", "1"), request("a", "2"), request("a", "3"), request("a", "4"), request("b", "1")}
+		for _, req := range burst {
+			_, err := r.Reconcile(context.TODO(), req)
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+		Expect(reconciled).Should(BeEmpty())
+
+		// The requests are only reconciled when they are dispatched, one at a time
+		for range burst {
+			_, err := r.Reconcile(context.TODO(), next())
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+		Expect(reconciled).Should(Equal([]reconcile.Request{request("a", "1"), request("a", "2"), request("b", "1"), request("a", "3"), request("a", "4")}))
+		Consistently(f.events).ShouldNot(Receive())
+	})
+
+	It("Should merge the requests waiting in the lane", func() {
+		Setup(Options{MaxConcurrentReconciles: 1, FairQueue: true})
+		defer Setup(Options{})
+
+		f := NewFairQueue("operandrequest")
+		r := f.Reconciler(reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+			return reconcile.Result{}, nil
+		}))
+		for _, req := range []reconcile.Request{request("a", "1"), request("a", "2"), request("a", "2")} {
+			_, err := r.Reconcile(context.TODO(), req)
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+		Expect(f.lanes[lane{namespace: "a"}]).Should(Equal([]reconcile.Request{request("a", "2")}))
+	})
+
+	It("Should reconcile the requests of the higher priorities first", func() {
+		Setup(Options{MaxConcurrentReconciles: 1, FairQueue: true})
+		defer Setup(Options{})
+
+		var reconciled []reconcile.Request
+		priorities := map[reconcile.Request]int{request("platform", "1"): 2, request("b", "1"): 0}
+		f := NewFairQueue("operandrequest").WithPriority(func(req reconcile.Request) int {
+			if priority, ok := priorities[req]; ok {
+				return priority
+			}
+			return 1
+		})
+		r := f.Reconciler(reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+			reconciled = append(reconciled, req)
+			return reconcile.Result{}, nil
+		}))
+		next := func() reconcile.Request {
+			var e event.GenericEvent
+			Eventually(f.events).Should(Receive(&e))
+			return request(e.Object.GetNamespace(), e.Object.GetName())
+		}
+
+		// The first request is dispatched at once, the others wait for their turn
+		burst := []reconcile.Request{request("a", "1"), request("b", "1"), request("a", "2"), request("c", "1"), request("platform", "1")}
+		for _, req := range burst {
+			_, err := r.Reconcile(context.TODO(), req)
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+		for range burst {
+			_, err := r.Reconcile(context.TODO(), next())
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+		Expect(reconciled).Should(Equal([]reconcile.Request{request("a", "1"), request("platform", "1"), request("a", "2"), request("c", "1"), request("b", "1")}))
+		Expect(f.turns).Should(BeEmpty())
+	})
+
+	It("Should retry the failed requests with a growing backoff", func() {
+		Setup(Options{MaxConcurrentReconciles: 1, FairQueue: true, BaseDelay: 20 * time.Millisecond, MaxDelay: time.Minute})
+		defer Setup(Options{})
+
+		f := NewFairQueue("operandrequest")
+		r := f.Reconciler(reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+			return reconcile.Result{}, errors.New("the operator isn't ready")
+		}))
+		next := func() reconcile.Request {
+			var e event.GenericEvent
+			Eventually(f.events, 5*time.Second).Should(Receive(&e))
+			return request(e.Object.GetNamespace(), e.Object.GetName())
+		}
+
+		_, err := r.Reconcile(context.TODO(), request("a", "1"))
+		Expect(err).ShouldNot(HaveOccurred())
+		var delays []time.Duration
+		req := next()
+		for i := 0; i < 4; i++ {
+			_, err := r.Reconcile(context.TODO(), req)
+			Expect(err).Should(HaveOccurred())
+			failed := time.Now()
+
+			// The work queue forgets the failure and retries the request at once, it waits for its backoff
+			_, err = r.Reconcile(context.TODO(), req)
+			Expect(err).ShouldNot(HaveOccurred())
+			req = next()
+			delays = append(delays, time.Since(failed))
+		}
+		for i := 1; i < len(delays); i++ {
+			Expect(delays[i]).Should(BeNumerically(">", delays[i-1]))
+		}
+		Expect(delays[3]).Should(BeNumerically(">=", 160*time.Millisecond))
+	})
+
+	It("Should reconcile the requests directly when it is disabled", func() {
+		Setup(Options{MaxConcurrentReconciles: 1})
+		defer Setup(Options{})
+
+		reconciled := 0
+		r := NewFairQueue("operandrequest").Reconciler(reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+			reconciled++
+			return reconcile.Result{}, nil
+		}))
+		_, err := r.Reconcile(context.TODO(), request("a", "1"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(reconciled).Should(Equal(1))
+	})
+})
@@ -0,0 +1,166 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package throughput tunes the client to the API server and the work queues of the ODLM controllers.
+//
+// The flags of the operator are the defaults, the OperatorConfig overrides them when ODLM starts.
+package throughput
+
+import (
+	"flag"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+// Options are the throughput options set by the flags of the operator
+type Options struct {
+	// QPS is the queries per second of the client to the API server
+	QPS int
+	// Burst is the burst of the queries of the client to the API server
+	Burst int
+	// OperandWorkers is the number of the members of an OperandRequest reconciled in parallel
+	OperandWorkers int
+	// MaxConcurrentReconciles is the number of the reconciles each controller runs in parallel
+	MaxConcurrentReconciles int
+	// BaseDelay is the delay of the first retry of a failed resource
+	BaseDelay time.Duration
+	// MaxDelay is the longest delay of the retries of a failed resource
+	MaxDelay time.Duration
+	// RateLimiterQPS is the number of the resources requeued per second by each controller
+	RateLimiterQPS int
+	// RateLimiterBurst is the burst of the resources requeued by each controller
+	RateLimiterBurst int
+	// FairQueue reconciles the resources of the namespaces in turn
+	FairQueue bool
+}
+
+// BindFlags binds the throughput options to the flags, the defaults are the ones of controller-runtime
+func (o *Options) BindFlags(fs *flag.FlagSet) {
+	fs.IntVar(&o.QPS, "kube-api-qps", 20, "The queries per second of the client to the API server.")
+	fs.IntVar(&o.Burst, "kube-api-burst", 30, "The burst of the queries of the client to the API server.")
+	fs.IntVar(&o.OperandWorkers, "operand-workers", 4, "The number of the members of an OperandRequest reconciled in parallel.")
+	fs.IntVar(&o.MaxConcurrentReconciles, "max-concurrent-reconciles", 1, "The number of the reconciles each controller runs in parallel.")
+	fs.DurationVar(&o.BaseDelay, "rate-limiter-base-delay", 5*time.Millisecond, "The delay of the first retry of a failed resource.")
+	fs.DurationVar(&o.MaxDelay, "rate-limiter-max-delay", 1000*time.Second, "The longest delay of the retries of a failed resource.")
+	fs.IntVar(&o.RateLimiterQPS, "rate-limiter-qps", 10, "The number of the resources requeued per second by each controller.")
+	fs.IntVar(&o.RateLimiterBurst, "rate-limiter-burst", 100, "The burst of the resources requeued by each controller.")
+	fs.BoolVar(&o.FairQueue, "fair-queue", true, "Reconcile the resources of the namespaces in turn, so a namespace with a burst of changes doesn't delay the others.")
+}
+
+// Throughput returns the throughput of the options
+func (o Options) Throughput() operatorv1alpha1.Throughput {
+	fairQueue := o.FairQueue
+	return operatorv1alpha1.Throughput{
+		QPS:            o.QPS,
+		Burst:          o.Burst,
+		OperandWorkers: o.OperandWorkers,
+		ControllerThroughput: operatorv1alpha1.ControllerThroughput{
+			MaxConcurrentReconciles: o.MaxConcurrentReconciles,
+			RateLimiter: &operatorv1alpha1.RateLimiter{
+				BaseDelay: &metav1.Duration{Duration: o.BaseDelay},
+				MaxDelay:  &metav1.Duration{Duration: o.MaxDelay},
+				QPS:       o.RateLimiterQPS,
+				Burst:     o.RateLimiterBurst,
+			},
+			FairQueue: &fairQueue,
+		},
+	}
+}
+
+// throughput holds the throughput set by the flags and the one ODLM is running with
+var throughput struct {
+	sync.RWMutex
+	defaults operatorv1alpha1.Throughput
+	running  operatorv1alpha1.Throughput
+}
+
+// Setup sets the throughput of the flags, ODLM runs with it unless the OperatorConfig overrides it
+func Setup(o Options) {
+	throughput.Lock()
+	defer throughput.Unlock()
+	throughput.defaults = o.Throughput()
+	throughput.running = o.Throughput()
+}
+
+// GetDefaults returns the throughput set by the flags
+func GetDefaults() operatorv1alpha1.Throughput {
+	throughput.RLock()
+	defer throughput.RUnlock()
+	return *throughput.defaults.DeepCopy()
+}
+
+// Get returns the throughput ODLM is running with
+func Get() operatorv1alpha1.Throughput {
+	throughput.RLock()
+	defer throughput.RUnlock()
+	return *throughput.running.DeepCopy()
+}
+
+// Set sets the throughput ODLM runs with, it only takes effect before the client and the controllers are created
+func Set(t operatorv1alpha1.Throughput) {
+	throughput.Lock()
+	defer throughput.Unlock()
+	throughput.running = *t.DeepCopy()
+}
+
+// ConfigureClient sets the QPS and the burst of the client to the API server
+func ConfigureClient(config *rest.Config) {
+	t := Get()
+	config.QPS = float32(t.QPS)
+	config.Burst = t.Burst
+}
+
+// ControllerOptions returns the options of the named controller
+func ControllerOptions(name string) controller.Options {
+	t := Get()
+	c := t.ForController(name)
+	options := controller.Options{
+		MaxConcurrentReconciles: c.MaxConcurrentReconciles,
+	}
+	if c.RateLimiter != nil {
+		options.RateLimiter = NewRateLimiter(*c.RateLimiter)
+	}
+	return options
+}
+
+// NewRateLimiter returns the rate limiter of a work queue, the unset values are the defaults of controller-runtime
+func NewRateLimiter(r operatorv1alpha1.RateLimiter) workqueue.RateLimiter {
+	baseDelay, maxDelay, qps, burst := 5*time.Millisecond, 1000*time.Second, 10, 100
+	if r.BaseDelay != nil {
+		baseDelay = r.BaseDelay.Duration
+	}
+	if r.MaxDelay != nil {
+		maxDelay = r.MaxDelay.Duration
+	}
+	if r.QPS != 0 {
+		qps = r.QPS
+	}
+	if r.Burst != 0 {
+		burst = r.Burst
+	}
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(qps), burst)},
+	)
+}
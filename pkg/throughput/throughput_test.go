@@ -0,0 +1,111 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package throughput
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	operatorv1alpha1 "github.com/IBM/operand-deployment-lifecycle-manager/api/v1alpha1"
+)
+
+var _ = Describe("Throughput", func() {
+
+	defaults := Options{
+		QPS:                     20,
+		Burst:                   30,
+		OperandWorkers:          4,
+		MaxConcurrentReconciles: 1,
+		BaseDelay:               5 * time.Millisecond,
+		MaxDelay:                1000 * time.Second,
+		RateLimiterQPS:          10,
+		RateLimiterBurst:        100,
+	}
+
+	It("Should merge the throughput of the OperatorConfig with the flags", func() {
+		t := &operatorv1alpha1.Throughput{
+			QPS: 100,
+			ControllerThroughput: operatorv1alpha1.ControllerThroughput{
+				MaxConcurrentReconciles: 4,
+			},
+			Controllers: []operatorv1alpha1.NamedControllerThroughput{
+				{
+					Name: "operandrequest",
+					ControllerThroughput: operatorv1alpha1.ControllerThroughput{
+						MaxConcurrentReconciles: 10,
+						RateLimiter:             &operatorv1alpha1.RateLimiter{MaxDelay: &metav1.Duration{Duration: time.Minute}},
+					},
+				},
+			},
+		}
+		merged := t.Merge(defaults.Throughput())
+		Expect(merged.QPS).Should(Equal(100))
+		Expect(merged.Burst).Should(Equal(30))
+		Expect(merged.OperandWorkers).Should(Equal(4))
+
+		request := merged.ForController("operandrequest")
+		Expect(request.MaxConcurrentReconciles).Should(Equal(10))
+		Expect(request.RateLimiter.BaseDelay.Duration).Should(Equal(5 * time.Millisecond))
+		Expect(request.RateLimiter.MaxDelay.Duration).Should(Equal(time.Minute))
+		Expect(request.RateLimiter.QPS).Should(Equal(10))
+
+		registry := merged.ForController("operandregistry")
+		Expect(registry.MaxConcurrentReconciles).Should(Equal(4))
+		Expect(registry.RateLimiter.MaxDelay.Duration).Should(Equal(1000 * time.Second))
+	})
+
+	It("Should run the client and the controllers with the throughput", func() {
+		Setup(defaults)
+		defer Setup(defaults)
+		Expect(Get()).Should(Equal(GetDefaults()))
+
+		t := defaults.Throughput()
+		t.QPS = 50
+		t.Controllers = []operatorv1alpha1.NamedControllerThroughput{
+			{Name: "operandbindinfo", ControllerThroughput: operatorv1alpha1.ControllerThroughput{MaxConcurrentReconciles: 3}},
+		}
+		Set(t)
+
+		config := &rest.Config{}
+		ConfigureClient(config)
+		Expect(config.QPS).Should(Equal(float32(50)))
+		Expect(config.Burst).Should(Equal(30))
+
+		options := ControllerOptions("operandbindinfo")
+		Expect(options.MaxConcurrentReconciles).Should(Equal(3))
+		Expect(options.RateLimiter).ShouldNot(BeNil())
+		Expect(ControllerOptions("operandrequest").MaxConcurrentReconciles).Should(Equal(1))
+		Expect(GetDefaults().QPS).Should(Equal(20))
+	})
+
+	It("Should requeue the failed resources with an exponential delay", func() {
+		limiter := NewRateLimiter(operatorv1alpha1.RateLimiter{
+			BaseDelay: &metav1.Duration{Duration: time.Second},
+			MaxDelay:  &metav1.Duration{Duration: 3 * time.Second},
+		})
+		Expect(limiter.When("item")).Should(Equal(time.Second))
+		Expect(limiter.When("item")).Should(Equal(2 * time.Second))
+		Expect(limiter.When("item")).Should(Equal(3 * time.Second))
+		Expect(limiter.NumRequeues("item")).Should(Equal(3))
+		limiter.Forget("item")
+		Expect(limiter.When("item")).Should(Equal(time.Second))
+	})
+})
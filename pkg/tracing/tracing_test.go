@@ -0,0 +1,107 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var _ = Describe("Tracing", func() {
+	var (
+		recorder *tracetest.SpanRecorder
+		server   *httptest.Server
+		headers  http.Header
+	)
+
+	BeforeEach(func() {
+		recorder = tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			headers = r.Header.Clone()
+			if r.URL.Path == "/missing" {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Should be disabled without an OTLP endpoint", func() {
+		Expect(Enabled()).Should(BeFalse())
+		shutdown, err := Setup(context.TODO())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shutdown(context.TODO())).To(Succeed())
+	})
+
+	It("Should propagate the span of the reconcile to the API calls", func() {
+		ctx, span := Start(context.TODO(), "OperandRequest.Reconcile")
+		httpClient := &http.Client{Transport: WrapTransport(http.DefaultTransport)}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/namespaces", nil)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := httpClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		End(span, nil)
+
+		Expect(headers.Get("traceparent")).Should(ContainSubstring(span.SpanContext().TraceID().String()))
+		spans := recorder.Ended()
+		Expect(spans).Should(HaveLen(2))
+		Expect(spans[0].Name()).Should(Equal("HTTP GET"))
+		Expect(spans[0].Parent().SpanID()).Should(Equal(span.SpanContext().SpanID()))
+		Expect(spans[1].Name()).Should(Equal("OperandRequest.Reconcile"))
+	})
+
+	It("Should record the errors on the spans", func() {
+		ctx, span := Start(context.TODO(), "InstallOperator")
+		httpClient := &http.Client{Transport: WrapTransport(http.DefaultTransport)}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/missing", nil)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := httpClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		End(span, errors.New("failed to create the Subscription"))
+
+		spans := recorder.Ended()
+		Expect(spans).Should(HaveLen(2))
+		Expect(spans[0].Status().Code).Should(Equal(codes.Error))
+		Expect(spans[1].Status().Code).Should(Equal(codes.Error))
+		Expect(spans[1].Status().Description).Should(Equal("failed to create the Subscription"))
+	})
+
+	It("Should not trace the watches", func() {
+		httpClient := &http.Client{Transport: WrapTransport(http.DefaultTransport)}
+		resp, err := httpClient.Get(server.URL + "/api/v1/namespaces?watch=true")
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(recorder.Ended()).Should(BeEmpty())
+		Expect(headers.Get("traceparent")).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,140 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1
+
+import (
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+)
+
+// CachedDiscovery caches the server groups and resources of the DiscoveryInterface for a TTL,
+// so the bulk reconciles don't discover the API server for each resource
+type CachedDiscovery struct {
+	discovery.DiscoveryInterface
+
+	ttl time.Duration
+	now func() time.Time
+
+	mu        sync.RWMutex
+	groups    []*metav1.APIGroup
+	resources []*metav1.APIResourceList
+	expiry    time.Time
+	// generation is increased by each invalidation, the discoveries started before it are not cached
+	generation int
+}
+
+// NewCachedDiscovery returns a CachedDiscovery of the DiscoveryInterface keeping the results for the TTL
+func NewCachedDiscovery(dc discovery.DiscoveryInterface, ttl time.Duration) *CachedDiscovery {
+	return &CachedDiscovery{
+		DiscoveryInterface: dc,
+		ttl:                ttl,
+		now:                time.Now,
+	}
+}
+
+// ServerGroupsAndResources returns the cached server groups and resources, and discovers them again when the cache expires.
+// The failed discoveries are not cached.
+func (d *CachedDiscovery) ServerGroupsAndResources() ([]*metav1.APIGroup, []*metav1.APIResourceList, error) {
+	d.mu.RLock()
+	if d.resources != nil && d.now().Before(d.expiry) {
+		defer d.mu.RUnlock()
+		return d.groups, d.resources, nil
+	}
+	generation := d.generation
+	d.mu.RUnlock()
+
+	groups, resources, err := d.DiscoveryInterface.ServerGroupsAndResources()
+	if err != nil {
+		return groups, resources, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if generation != d.generation {
+		return groups, resources, nil
+	}
+	d.groups, d.resources = groups, resources
+	d.expiry = d.now().Add(d.ttl)
+	return groups, resources, nil
+}
+
+// Invalidate drops the cached results, the next call discovers the API server again
+func (d *CachedDiscovery) Invalidate() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.groups, d.resources = nil, nil
+	d.generation++
+}
+
+// ResourceExists returns true if the given resource kind exists
+// in the given api groupversion
+func ResourceExists(dc discovery.DiscoveryInterface, apiGroupVersion, kind string) (bool, error) {
+	_, apiLists, err := dc.ServerGroupsAndResources()
+	if err != nil {
+		return false, err
+	}
+	for _, apiList := range apiLists {
+		if apiList.GroupVersion == apiGroupVersion {
+			for _, r := range apiList.APIResources {
+				if r.Kind == kind {
+					return true, nil
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+// ResourceNamespaced returns true if the given resource is namespaced
+func ResourceNamespaced(dc discovery.DiscoveryInterface, apiGroupVersion, kind string) (bool, error) {
+	_, apiLists, err := dc.ServerGroupsAndResources()
+	if err != nil {
+		return false, err
+	}
+	for _, apiList := range apiLists {
+		if apiList.GroupVersion == apiGroupVersion {
+			for _, r := range apiList.APIResources {
+				if r.Kind == kind {
+					return r.Namespaced, nil
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+// ResourcePlural returns the plural name of the given resource kind
+// in the given api groupversion, it is empty if the kind doesn't exist
+func ResourcePlural(dc discovery.DiscoveryInterface, apiGroupVersion, kind string) (string, error) {
+	_, apiLists, err := dc.ServerGroupsAndResources()
+	if err != nil {
+		return "", err
+	}
+	for _, apiList := range apiLists {
+		if apiList.GroupVersion == apiGroupVersion {
+			for _, r := range apiList.APIResources {
+				if r.Kind == kind {
+					return r.Name, nil
+				}
+			}
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,145 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// failingDiscovery fails to discover the server resources
+type failingDiscovery struct {
+	*fakediscovery.FakeDiscovery
+}
+
+func (d *failingDiscovery) ServerGroupsAndResources() ([]*metav1.APIGroup, []*metav1.APIResourceList, error) {
+	return nil, nil, errors.New("discovery failed")
+}
+
+var _ = Describe("Discovery", func() {
+
+	var dc *fakediscovery.FakeDiscovery
+
+	BeforeEach(func() {
+		dc = &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+		dc.Resources = []*metav1.APIResourceList{
+			{
+				GroupVersion: "operator.ibm.com/v1alpha1",
+				APIResources: []metav1.APIResource{
+					{Name: "operandrequests", Kind: "OperandRequest", Namespaced: true},
+				},
+			},
+			{
+				GroupVersion: "apiextensions.k8s.io/v1",
+				APIResources: []metav1.APIResource{
+					{Name: "customresourcedefinitions", Kind: "CustomResourceDefinition", Namespaced: false},
+				},
+			},
+		}
+	})
+
+	Context("Check the resource exists", func() {
+		It("Should find the kind in the group version", func() {
+			Expect(ResourceExists(dc, "operator.ibm.com/v1alpha1", "OperandRequest")).Should(BeTrue())
+		})
+
+		It("Should not find the kind in another group version", func() {
+			Expect(ResourceExists(dc, "operator.ibm.com/v1", "OperandRequest")).Should(BeFalse())
+			Expect(ResourceExists(dc, "operator.ibm.com/v1alpha1", "OperandConfig")).Should(BeFalse())
+		})
+
+		It("Should fail when the discovery fails", func() {
+			_, err := ResourceExists(&failingDiscovery{dc}, "operator.ibm.com/v1alpha1", "OperandRequest")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Check the resource is namespaced", func() {
+		It("Should return the scope of the kind", func() {
+			Expect(ResourceNamespaced(dc, "operator.ibm.com/v1alpha1", "OperandRequest")).Should(BeTrue())
+			Expect(ResourceNamespaced(dc, "apiextensions.k8s.io/v1", "CustomResourceDefinition")).Should(BeFalse())
+		})
+
+		It("Should return false for an unknown kind", func() {
+			Expect(ResourceNamespaced(dc, "apps/v1", "Deployment")).Should(BeFalse())
+		})
+	})
+
+	Context("Get the plural of the resource", func() {
+		It("Should return the name of the kind", func() {
+			Expect(ResourcePlural(dc, "operator.ibm.com/v1alpha1", "OperandRequest")).Should(Equal("operandrequests"))
+		})
+
+		It("Should return empty for an unknown kind", func() {
+			Expect(ResourcePlural(dc, "operator.ibm.com/v1", "OperandRequest")).Should(BeEmpty())
+		})
+	})
+
+	Context("Cache the discovery", func() {
+		var (
+			cached *CachedDiscovery
+			now    time.Time
+		)
+
+		BeforeEach(func() {
+			now = time.Now()
+			cached = NewCachedDiscovery(dc, time.Minute)
+			cached.now = func() time.Time { return now }
+		})
+
+		It("Should discover the API server once within the TTL", func() {
+			Expect(ResourceExists(cached, "operator.ibm.com/v1alpha1", "OperandRequest")).Should(BeTrue())
+			Expect(ResourceNamespaced(cached, "operator.ibm.com/v1alpha1", "OperandRequest")).Should(BeTrue())
+			// The fake discovery records the groups and the resources for each discovery
+			Expect(dc.Actions()).Should(HaveLen(2))
+		})
+
+		It("Should discover the API server again when the cache expires", func() {
+			Expect(ResourceExists(cached, "operator.ibm.com/v1alpha1", "OperandRequest")).Should(BeTrue())
+			now = now.Add(2 * time.Minute)
+			Expect(ResourceExists(cached, "operator.ibm.com/v1alpha1", "OperandRequest")).Should(BeTrue())
+			Expect(dc.Actions()).Should(HaveLen(4))
+		})
+
+		It("Should discover the new resources after the invalidation", func() {
+			Expect(ResourceExists(cached, "operator.ibm.com/v1", "NamespaceScope")).Should(BeFalse())
+			dc.Resources = append(dc.Resources, &metav1.APIResourceList{
+				GroupVersion: "operator.ibm.com/v1",
+				APIResources: []metav1.APIResource{
+					{Name: "namespacescopes", Kind: "NamespaceScope", Namespaced: true},
+				},
+			})
+			Expect(ResourceExists(cached, "operator.ibm.com/v1", "NamespaceScope")).Should(BeFalse())
+			cached.Invalidate()
+			Expect(ResourceExists(cached, "operator.ibm.com/v1", "NamespaceScope")).Should(BeTrue())
+		})
+
+		It("Should not cache the failed discovery", func() {
+			failing := NewCachedDiscovery(&failingDiscovery{dc}, time.Minute)
+			_, err := ResourceExists(failing, "operator.ibm.com/v1alpha1", "OperandRequest")
+			Expect(err).To(HaveOccurred())
+			_, err = ResourceExists(failing, "operator.ibm.com/v1alpha1", "OperandRequest")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,23 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package v1 provides the utilities ODLM uses to merge and prune the custom resource specs,
+// to compare the channel versions and to discover the resources served by the cluster.
+//
+// The operators depending on the ODLM semantics can import this package instead of copying the code.
+// The exported API of this package is stable. A change breaking it is made in a new version of the
+// package, like pkg/util/v2, and this version is kept for the existing users.
+package v1
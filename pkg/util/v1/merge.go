@@ -0,0 +1,472 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/IBM/operand-deployment-lifecycle-manager/pkg/logging"
+)
+
+var log = logging.Logger("merge")
+
+// MergeStrategy defines how a changed configuration is merged into a default configuration.
+type MergeStrategy string
+
+const (
+	// MergeStrategyDeep merges the nested maps recursively. The other values of the changed configuration,
+	// including the lists, replace the default ones. It is the strategy of MergeCR.
+	MergeStrategyDeep MergeStrategy = "deep"
+	// MergeStrategyReplace replaces the top level fields of the default configuration with the changed ones.
+	MergeStrategyReplace MergeStrategy = "replace"
+	// MergeStrategyAppendLists merges as MergeStrategyDeep, except that the items of a changed list
+	// are appended to the default list, skipping the items already in it.
+	MergeStrategyAppendLists MergeStrategy = "appendLists"
+	// MergeStrategyMergeListsByName merges as MergeStrategyDeep, except that the map items of the lists are
+	// matched by their name field and merged recursively. The default items without a match are kept.
+	MergeStrategyMergeListsByName MergeStrategy = "mergeListsByName"
+)
+
+// listNameKey is the key MergeStrategyMergeListsByName matches the list items by
+const listNameKey = "name"
+
+// MergeCR deep merge two custom resource spec
+func MergeCR(defaultCR, changedCR []byte) map[string]interface{} {
+	defaultCRDecoded, err := decode(defaultCR)
+	if err != nil {
+		log.Error(err, "failed to unmarshal CR Template")
+	}
+	changedCRDecoded, err := decode(changedCR)
+	if err != nil {
+		log.Error(err, "failed to unmarshal service spec")
+	}
+	return MergeMaps(defaultCRDecoded, changedCRDecoded, MergeStrategyDeep)
+}
+
+// Merge merges two JSON encoded custom resource specs with the strategy.
+// The values in the changed spec take precedence over the default ones.
+func Merge(defaultCR, changedCR []byte, strategy MergeStrategy) (map[string]interface{}, error) {
+	if !strategy.IsValid() {
+		return nil, errors.Errorf("unknown merge strategy %q", strategy)
+	}
+	defaultCRDecoded, err := decode(defaultCR)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal the default spec")
+	}
+	changedCRDecoded, err := decode(changedCR)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal the changed spec")
+	}
+	return MergeMaps(defaultCRDecoded, changedCRDecoded, strategy), nil
+}
+
+// MergeMaps merges the default map into the changed map with the strategy, and returns the changed map.
+// An unknown strategy is handled as MergeStrategyDeep.
+func MergeMaps(defaultMap, changedMap map[string]interface{}, strategy MergeStrategy) map[string]interface{} {
+	if changedMap == nil {
+		changedMap = make(map[string]interface{})
+	}
+	for key := range defaultMap {
+		if strategy == MergeStrategyReplace {
+			if changedMap[key] == nil {
+				changedMap[key] = defaultMap[key]
+			}
+			continue
+		}
+		checkKeyBeforeMerging(key, defaultMap[key], changedMap[key], changedMap, strategy)
+	}
+	return changedMap
+}
+
+// IsValid returns true if the strategy is known
+func (s MergeStrategy) IsValid() bool {
+	switch s {
+	case MergeStrategyDeep, MergeStrategyReplace, MergeStrategyAppendLists, MergeStrategyMergeListsByName:
+		return true
+	}
+	return false
+}
+
+func decode(cr []byte) (map[string]interface{}, error) {
+	decoded := make(map[string]interface{})
+	if len(cr) == 0 {
+		return decoded, nil
+	}
+	if err := json.Unmarshal(cr, &decoded); err != nil {
+		return make(map[string]interface{}), err
+	}
+	return decoded, nil
+}
+
+func checkKeyBeforeMerging(key string, defaultMap interface{}, changedMap interface{}, finalMap map[string]interface{}, strategy MergeStrategy) {
+	if !reflect.DeepEqual(defaultMap, changedMap) {
+		switch defaultMap := defaultMap.(type) {
+		case map[string]interface{}:
+			//Check that the changed map value doesn't contain this map at all and is nil
+			if changedMap == nil {
+				finalMap[key] = defaultMap
+			} else if _, ok := changedMap.(map[string]interface{}); ok { //Check that the changed map value is also a map[string]interface
+				defaultMapRef := defaultMap
+				changedMapRef := changedMap.(map[string]interface{})
+				for newKey := range defaultMapRef {
+					checkKeyBeforeMerging(newKey, defaultMapRef[newKey], changedMapRef[newKey], finalMap[key].(map[string]interface{}), strategy)
+				}
+			}
+		case []interface{}:
+			changedList, ok := changedMap.([]interface{})
+			switch {
+			case changedMap == nil:
+				finalMap[key] = defaultMap
+			case ok && strategy == MergeStrategyAppendLists:
+				finalMap[key] = appendList(defaultMap, changedList)
+			case ok && strategy == MergeStrategyMergeListsByName:
+				finalMap[key] = mergeListByName(defaultMap, changedList, strategy)
+			}
+		default:
+			//Check if the value was set, otherwise set it
+			if changedMap == nil {
+				finalMap[key] = defaultMap
+			}
+		}
+	}
+}
+
+// appendList appends the changed items which are not in the default list
+func appendList(defaultList, changedList []interface{}) []interface{} {
+	merged := append([]interface{}{}, defaultList...)
+	for _, item := range changedList {
+		found := false
+		for _, defaultItem := range defaultList {
+			if reflect.DeepEqual(item, defaultItem) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, item)
+		}
+	}
+	return merged
+}
+
+// mergeListByName merges the default items into the changed items with the same name.
+// The default items without a name, or without a changed item of the same name, are appended.
+func mergeListByName(defaultList, changedList []interface{}, strategy MergeStrategy) []interface{} {
+	merged := append([]interface{}{}, changedList...)
+	for _, defaultItem := range defaultList {
+		name, hasName := itemName(defaultItem)
+		matched := false
+		if hasName {
+			for i, item := range merged {
+				if n, ok := itemName(item); ok && n == name {
+					merged[i] = MergeMaps(defaultItem.(map[string]interface{}), item.(map[string]interface{}), strategy)
+					matched = true
+					break
+				}
+			}
+		}
+		if matched {
+			continue
+		}
+		found := false
+		for _, item := range merged {
+			if reflect.DeepEqual(item, defaultItem) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, defaultItem)
+		}
+	}
+	return merged
+}
+
+func itemName(item interface{}) (string, bool) {
+	m, ok := item.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	name, ok := m[listNameKey].(string)
+	return name, ok
+}
+
+// PruneCR removes the fields from a custom resource spec, which were in the last applied configuration
+// but have been removed from the desired configuration.
+// Nested maps are pruned recursively, other values are pruned as a whole.
+func PruneCR(spec, lastAppliedCR, desiredCR []byte) map[string]interface{} {
+	specDecoded := make(map[string]interface{})
+	if len(spec) != 0 {
+		if err := json.Unmarshal(spec, &specDecoded); err != nil {
+			log.Error(err, "failed to unmarshal custom resource spec")
+			return specDecoded
+		}
+	}
+	if len(lastAppliedCR) == 0 {
+		return specDecoded
+	}
+
+	lastAppliedDecoded := make(map[string]interface{})
+	if err := json.Unmarshal(lastAppliedCR, &lastAppliedDecoded); err != nil {
+		log.Error(err, "failed to unmarshal last applied configuration")
+		return specDecoded
+	}
+	desiredDecoded := make(map[string]interface{})
+	if len(desiredCR) != 0 {
+		if err := json.Unmarshal(desiredCR, &desiredDecoded); err != nil {
+			log.Error(err, "failed to unmarshal desired configuration")
+			return specDecoded
+		}
+	}
+
+	pruneRemovedKeys(specDecoded, lastAppliedDecoded, desiredDecoded)
+	return specDecoded
+}
+
+func pruneRemovedKeys(spec, lastApplied, desired map[string]interface{}) {
+	for key, lastValue := range lastApplied {
+		desiredValue, ok := desired[key]
+		if !ok {
+			delete(spec, key)
+			continue
+		}
+		lastMap, lastIsMap := lastValue.(map[string]interface{})
+		desiredMap, desiredIsMap := desiredValue.(map[string]interface{})
+		specMap, specIsMap := spec[key].(map[string]interface{})
+		if lastIsMap && desiredIsMap && specIsMap {
+			pruneRemovedKeys(specMap, lastMap, desiredMap)
+		}
+	}
+}
+
+// RetainCR returns the desired configuration, with the fields which were in the last applied configuration
+// but have been removed from the desired configuration set to their values in the custom resource spec.
+// It keeps the removed fields in a custom resource when the configuration is server-side applied without pruning.
+// Nested maps are retained recursively, other values are retained as a whole.
+func RetainCR(spec, lastAppliedCR, desiredCR []byte) map[string]interface{} {
+	desiredDecoded, err := decode(desiredCR)
+	if err != nil {
+		log.Error(err, "failed to unmarshal desired configuration")
+		return desiredDecoded
+	}
+	lastAppliedDecoded, err := decode(lastAppliedCR)
+	if err != nil {
+		log.Error(err, "failed to unmarshal last applied configuration")
+		return desiredDecoded
+	}
+	specDecoded, err := decode(spec)
+	if err != nil {
+		log.Error(err, "failed to unmarshal custom resource spec")
+		return desiredDecoded
+	}
+
+	retainRemovedKeys(specDecoded, lastAppliedDecoded, desiredDecoded)
+	return desiredDecoded
+}
+
+func retainRemovedKeys(spec, lastApplied, desired map[string]interface{}) {
+	for key, lastValue := range lastApplied {
+		specValue, inSpec := spec[key]
+		if !inSpec {
+			continue
+		}
+		desiredValue, ok := desired[key]
+		if !ok {
+			desired[key] = specValue
+			continue
+		}
+		lastMap, lastIsMap := lastValue.(map[string]interface{})
+		desiredMap, desiredIsMap := desiredValue.(map[string]interface{})
+		specMap, specIsMap := specValue.(map[string]interface{})
+		if lastIsMap && desiredIsMap && specIsMap {
+			retainRemovedKeys(specMap, lastMap, desiredMap)
+		}
+	}
+}
+
+// UnchangedDefaults returns the fields of a default custom resource spec which are missing from the spec,
+// or still have their default value in it. Applying them doesn't override the values changed by others.
+// Nested maps are compared recursively, other values are compared as a whole.
+func UnchangedDefaults(defaultCR, spec []byte) map[string]interface{} {
+	defaultDecoded, err := decode(defaultCR)
+	if err != nil {
+		log.Error(err, "failed to unmarshal CR Template")
+		return defaultDecoded
+	}
+	specDecoded, err := decode(spec)
+	if err != nil {
+		log.Error(err, "failed to unmarshal custom resource spec")
+		return defaultDecoded
+	}
+	return unchangedKeys(defaultDecoded, specDecoded)
+}
+
+func unchangedKeys(defaults, spec map[string]interface{}) map[string]interface{} {
+	unchanged := make(map[string]interface{})
+	for key, defaultValue := range defaults {
+		specValue, ok := spec[key]
+		if !ok || reflect.DeepEqual(defaultValue, specValue) {
+			unchanged[key] = defaultValue
+			continue
+		}
+		defaultMap, defaultIsMap := defaultValue.(map[string]interface{})
+		specMap, specIsMap := specValue.(map[string]interface{})
+		if defaultIsMap && specIsMap {
+			if nested := unchangedKeys(defaultMap, specMap); len(nested) != 0 {
+				unchanged[key] = nested
+			}
+		}
+	}
+	return unchanged
+}
+
+// Drift compares a custom resource spec with the configuration last applied to it and the desired configuration.
+// The drifted fields have been changed in the spec, while their configuration hasn't changed since it was last applied.
+// It returns the sorted dot-separated paths of the drifted fields, and the desired configuration with the drifted fields
+// set to their values in the spec, or removed when they have been removed from the spec.
+func Drift(spec, lastAppliedCR, desiredCR []byte) ([]string, map[string]interface{}) {
+	desiredDecoded, err := decode(desiredCR)
+	if err != nil {
+		log.Error(err, "failed to unmarshal desired configuration")
+		return nil, desiredDecoded
+	}
+	lastAppliedDecoded, err := decode(lastAppliedCR)
+	if err != nil {
+		log.Error(err, "failed to unmarshal last applied configuration")
+		return nil, desiredDecoded
+	}
+	specDecoded, err := decode(spec)
+	if err != nil {
+		log.Error(err, "failed to unmarshal custom resource spec")
+		return nil, desiredDecoded
+	}
+
+	drifted := driftedKeys("", specDecoded, lastAppliedDecoded, desiredDecoded)
+	sort.Strings(drifted)
+	return drifted, desiredDecoded
+}
+
+func driftedKeys(path string, spec, lastApplied, desired map[string]interface{}) []string {
+	var drifted []string
+	for key, desiredValue := range desired {
+		lastValue, ok := lastApplied[key]
+		if !ok {
+			continue
+		}
+		specValue, inSpec := spec[key]
+		desiredMap, desiredIsMap := desiredValue.(map[string]interface{})
+		lastMap, lastIsMap := lastValue.(map[string]interface{})
+		specMap, specIsMap := specValue.(map[string]interface{})
+		if desiredIsMap && lastIsMap && specIsMap {
+			drifted = append(drifted, driftedKeys(path+key+".", specMap, lastMap, desiredMap)...)
+			continue
+		}
+		if !reflect.DeepEqual(desiredValue, lastValue) || reflect.DeepEqual(desiredValue, specValue) {
+			continue
+		}
+		drifted = append(drifted, path+key)
+		if inSpec {
+			desired[key] = specValue
+		} else {
+			delete(desired, key)
+		}
+	}
+	return drifted
+}
+
+// StripCR removes the fields at the dot-separated paths from a custom resource spec.
+// The paths missing from the spec are skipped, and the nested maps left empty by the removal are removed too.
+func StripCR(spec []byte, paths []string) map[string]interface{} {
+	specDecoded, err := decode(spec)
+	if err != nil {
+		log.Error(err, "failed to unmarshal custom resource spec")
+		return specDecoded
+	}
+	for _, path := range paths {
+		stripKeys(specDecoded, strings.Split(path, "."))
+	}
+	return specDecoded
+}
+
+func stripKeys(spec map[string]interface{}, keys []string) {
+	if len(keys) == 1 {
+		delete(spec, keys[0])
+		return
+	}
+	nested, ok := spec[keys[0]].(map[string]interface{})
+	if !ok {
+		return
+	}
+	stripKeys(nested, keys[1:])
+	if len(nested) == 0 {
+		delete(spec, keys[0])
+	}
+}
+
+// PrunedPaths compares a custom resource spec with the configuration last applied to it and the desired configuration.
+// It returns the sorted dot-separated paths of the fields removed from the configuration since it was last applied,
+// which are still in the spec with their last applied value. The fields changed by others since are skipped.
+// Nested maps are compared recursively, other values are compared as a whole.
+func PrunedPaths(spec, lastAppliedCR, desiredCR []byte) []string {
+	desiredDecoded, err := decode(desiredCR)
+	if err != nil {
+		log.Error(err, "failed to unmarshal desired configuration")
+		return nil
+	}
+	lastAppliedDecoded, err := decode(lastAppliedCR)
+	if err != nil {
+		log.Error(err, "failed to unmarshal last applied configuration")
+		return nil
+	}
+	specDecoded, err := decode(spec)
+	if err != nil {
+		log.Error(err, "failed to unmarshal custom resource spec")
+		return nil
+	}
+
+	pruned := prunedKeys("", specDecoded, lastAppliedDecoded, desiredDecoded)
+	sort.Strings(pruned)
+	return pruned
+}
+
+func prunedKeys(path string, spec, lastApplied, desired map[string]interface{}) []string {
+	var pruned []string
+	for key, lastValue := range lastApplied {
+		specValue, inSpec := spec[key]
+		if !inSpec {
+			continue
+		}
+		desiredValue, inDesired := desired[key]
+		lastMap, lastIsMap := lastValue.(map[string]interface{})
+		specMap, specIsMap := specValue.(map[string]interface{})
+		desiredMap, desiredIsMap := desiredValue.(map[string]interface{})
+		if !inDesired && reflect.DeepEqual(lastValue, specValue) {
+			pruned = append(pruned, path+key)
+			continue
+		}
+		if !lastIsMap || !specIsMap || (inDesired && !desiredIsMap) {
+			continue
+		}
+		pruned = append(pruned, prunedKeys(path+key+".", specMap, lastMap, desiredMap)...)
+	}
+	return pruned
+}
@@ -0,0 +1,287 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeepMerge", func() {
+
+	Context("Deep Merge two JSON files", func() {
+		It("Should two JSON files get deep merged", func() {
+			defaultJSON := `{"greetings":{"first":"hi","second":"hello"},"name":"John"}`
+			changedJSON := `{"greetings":{"first":"hey"},"name":"Jane"}`
+			resultJSON := `{"greetings":{"first":"hey","second":"hello"},"name":"Jane"}`
+
+			changedJSONDecoded := MergeCR([]byte(defaultJSON), []byte(changedJSON))
+
+			mergedJSON, err := json.Marshal(changedJSONDecoded)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(mergedJSON).Should(Equal([]byte(resultJSON)))
+		})
+	})
+
+	Context("Deep Merge two JSON files with list", func() {
+		It("Should two JSON files get deep merged", func() {
+			defaultJSON := `{"age":30,"cars":["Ford","BMW","Fiat"],"bicycle":["Giant"],"name":"John"}`
+			changedJSON := `{"age":13,"cars":["Benz","BMW","Fiat"],"plane":["Boeing"],"name":"Jane"}`
+			resultJSON := `{"age":13,"bicycle":["Giant"],"cars":["Benz","BMW","Fiat"],"name":"Jane","plane":["Boeing"]}`
+
+			changedJSONDecoded := MergeCR([]byte(defaultJSON), []byte(changedJSON))
+
+			mergedJSON, err := json.Marshal(changedJSONDecoded)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(mergedJSON).Should(Equal([]byte(resultJSON)))
+		})
+	})
+
+	Context("Deep Merge with invalid JSON", func() {
+		It("Should ignore the spec failed to unmarshal", func() {
+			mergedJSON, err := json.Marshal(MergeCR([]byte(`{"name":"John"}`), []byte(`{invalid`)))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mergedJSON).Should(Equal([]byte(`{"name":"John"}`)))
+
+			mergedJSON, err = json.Marshal(MergeCR(nil, nil))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mergedJSON).Should(Equal([]byte(`{}`)))
+		})
+
+		It("Should keep the default value when the changed value is null", func() {
+			mergedJSON, err := json.Marshal(MergeCR([]byte(`{"name":"John","greetings":{"first":"hi"}}`), []byte(`{"name":null,"greetings":null}`)))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mergedJSON).Should(Equal([]byte(`{"greetings":{"first":"hi"},"name":"John"}`)))
+		})
+
+		It("Should replace the default map with a changed scalar", func() {
+			mergedJSON, err := json.Marshal(MergeCR([]byte(`{"greetings":{"first":"hi"}}`), []byte(`{"greetings":"hey"}`)))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mergedJSON).Should(Equal([]byte(`{"greetings":"hey"}`)))
+		})
+	})
+
+	Context("Merge with strategies", func() {
+		defaultJSON := `{"greetings":{"first":"hi","second":"hello"},"cars":["Ford","BMW"],"containers":[{"name":"a","image":"a:1","args":["-v"]},{"name":"b","image":"b:1"}],"name":"John"}`
+		changedJSON := `{"greetings":{"first":"hey"},"cars":["BMW","Fiat"],"containers":[{"name":"a","image":"a:2"},{"name":"c","image":"c:1"}]}`
+
+		DescribeTable("Should merge the changed JSON into the default JSON",
+			func(strategy MergeStrategy, resultJSON string) {
+				merged, err := Merge([]byte(defaultJSON), []byte(changedJSON), strategy)
+				Expect(err).NotTo(HaveOccurred())
+				mergedJSON, err := json.Marshal(merged)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(mergedJSON)).Should(Equal(resultJSON))
+			},
+			Entry("deep", MergeStrategyDeep,
+				`{"cars":["BMW","Fiat"],"containers":[{"image":"a:2","name":"a"},{"image":"c:1","name":"c"}],"greetings":{"first":"hey","second":"hello"},"name":"John"}`),
+			Entry("replace", MergeStrategyReplace,
+				`{"cars":["BMW","Fiat"],"containers":[{"image":"a:2","name":"a"},{"image":"c:1","name":"c"}],"greetings":{"first":"hey"},"name":"John"}`),
+			Entry("appendLists", MergeStrategyAppendLists,
+				`{"cars":["Ford","BMW","Fiat"],"containers":[{"args":["-v"],"image":"a:1","name":"a"},{"image":"b:1","name":"b"},{"image":"a:2","name":"a"},{"image":"c:1","name":"c"}],"greetings":{"first":"hey","second":"hello"},"name":"John"}`),
+			Entry("mergeListsByName", MergeStrategyMergeListsByName,
+				`{"cars":["BMW","Fiat","Ford"],"containers":[{"args":["-v"],"image":"a:2","name":"a"},{"image":"c:1","name":"c"},{"image":"b:1","name":"b"}],"greetings":{"first":"hey","second":"hello"},"name":"John"}`),
+		)
+
+		It("Should give the same result as MergeCR with the deep strategy", func() {
+			merged, err := Merge([]byte(defaultJSON), []byte(changedJSON), MergeStrategyDeep)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(merged).Should(Equal(MergeCR([]byte(defaultJSON), []byte(changedJSON))))
+		})
+
+		It("Should merge the nested lists by name", func() {
+			merged, err := Merge([]byte(`{"spec":{"containers":[{"name":"a","env":[{"name":"X","value":"1"}]}]}}`),
+				[]byte(`{"spec":{"containers":[{"name":"a","env":[{"name":"Y","value":"2"}]}]}}`), MergeStrategyMergeListsByName)
+			Expect(err).NotTo(HaveOccurred())
+			mergedJSON, err := json.Marshal(merged)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(mergedJSON)).Should(Equal(`{"spec":{"containers":[{"env":[{"name":"Y","value":"2"},{"name":"X","value":"1"}],"name":"a"}]}}`))
+		})
+
+		It("Should fail with an unknown strategy", func() {
+			Expect(MergeStrategy("unknown").IsValid()).Should(BeFalse())
+			_, err := Merge([]byte(defaultJSON), []byte(changedJSON), "unknown")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should fail with invalid JSON", func() {
+			_, err := Merge([]byte(`{invalid`), []byte(changedJSON), MergeStrategyDeep)
+			Expect(err).To(HaveOccurred())
+			_, err = Merge([]byte(defaultJSON), []byte(`["not","a","map"]`), MergeStrategyDeep)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should merge into a nil map", func() {
+			Expect(MergeMaps(map[string]interface{}{"name": "John"}, nil, MergeStrategyDeep)).Should(Equal(map[string]interface{}{"name": "John"}))
+		})
+	})
+
+	Context("Prune the fields removed from the configuration", func() {
+		It("Should remove the fields only from the last applied configuration", func() {
+			specJSON := `{"greetings":{"first":"hey","second":"hello","third":"hola"},"name":"Jane","age":13}`
+			lastAppliedJSON := `{"greetings":{"first":"hey","third":"hola"},"name":"Jane"}`
+			desiredJSON := `{"greetings":{"first":"hey"}}`
+			resultJSON := `{"age":13,"greetings":{"first":"hey","second":"hello"}}`
+
+			prunedJSONDecoded := PruneCR([]byte(specJSON), []byte(lastAppliedJSON), []byte(desiredJSON))
+
+			prunedJSON, err := json.Marshal(prunedJSONDecoded)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(prunedJSON).Should(Equal([]byte(resultJSON)))
+		})
+
+		It("Should prune the nested maps recursively and the other values as a whole", func() {
+			specJSON := `{"spec":{"replicas":3,"args":["-a","-b"],"resources":{"cpu":"1","memory":"1Gi"}}}`
+			lastAppliedJSON := `{"spec":{"args":["-a"],"resources":{"cpu":"1","memory":"1Gi"}}}`
+			desiredJSON := `{"spec":{"resources":{"memory":"1Gi"}}}`
+			resultJSON := `{"spec":{"replicas":3,"resources":{"memory":"1Gi"}}}`
+
+			prunedJSON, err := json.Marshal(PruneCR([]byte(specJSON), []byte(lastAppliedJSON), []byte(desiredJSON)))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(prunedJSON).Should(Equal([]byte(resultJSON)))
+		})
+
+		It("Should keep the spec when the last applied configuration is invalid", func() {
+			specJSON := `{"name":"Jane"}`
+
+			prunedJSON, err := json.Marshal(PruneCR([]byte(specJSON), []byte(`{invalid`), nil))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(prunedJSON).Should(Equal([]byte(specJSON)))
+		})
+
+		It("Should keep the spec without last applied configuration", func() {
+			specJSON := `{"name":"Jane"}`
+
+			prunedJSONDecoded := PruneCR([]byte(specJSON), nil, []byte(`{}`))
+
+			prunedJSON, err := json.Marshal(prunedJSONDecoded)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(prunedJSON).Should(Equal([]byte(specJSON)))
+		})
+	})
+
+	Context("Retain the fields removed from the configuration", func() {
+		It("Should keep the removed fields with their values in the spec", func() {
+			specJSON := `{"greetings":{"first":"hi","second":"hello","third":"hola"},"name":"Jane","age":13}`
+			lastAppliedJSON := `{"greetings":{"first":"hey","third":"hola"},"name":"Jane"}`
+			desiredJSON := `{"greetings":{"first":"hey"}}`
+			resultJSON := `{"greetings":{"first":"hey","third":"hola"},"name":"Jane"}`
+
+			retainedJSON, err := json.Marshal(RetainCR([]byte(specJSON), []byte(lastAppliedJSON), []byte(desiredJSON)))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(retainedJSON).Should(Equal([]byte(resultJSON)))
+		})
+
+		It("Should skip the removed fields missing from the spec", func() {
+			retainedJSON, err := json.Marshal(RetainCR([]byte(`{"age":13}`), []byte(`{"name":"Jane"}`), nil))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(retainedJSON).Should(Equal([]byte(`{}`)))
+		})
+	})
+
+	Context("Select the unchanged default fields", func() {
+		It("Should keep the default fields missing or unchanged in the spec", func() {
+			defaultJSON := `{"spec":{"replicas":1,"args":["-a"],"resources":{"cpu":"1","memory":"1Gi"}},"size":"small"}`
+			specJSON := `{"spec":{"replicas":3,"args":["-a"],"resources":{"cpu":"2"}}}`
+			resultJSON := `{"size":"small","spec":{"args":["-a"],"resources":{"memory":"1Gi"}}}`
+
+			unchangedJSON, err := json.Marshal(UnchangedDefaults([]byte(defaultJSON), []byte(specJSON)))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(unchangedJSON).Should(Equal([]byte(resultJSON)))
+		})
+
+		It("Should drop the nested maps without unchanged fields", func() {
+			unchangedJSON, err := json.Marshal(UnchangedDefaults([]byte(`{"resources":{"cpu":"1"}}`), []byte(`{"resources":{"cpu":"2"}}`)))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(unchangedJSON).Should(Equal([]byte(`{}`)))
+		})
+	})
+
+	Context("Find the fields to prune", func() {
+		It("Should find the removed fields still holding their last applied value", func() {
+			specJSON := `{"greetings":{"first":"hey","second":"hello","fourth":"ciao"},"name":"Jane","age":13,"size":"small"}`
+			lastAppliedJSON := `{"greetings":{"first":"hey","second":"hello","third":"hola"},"name":"Jane","age":12,"size":"small"}`
+			desiredJSON := `{"greetings":{"first":"hey"},"size":"large"}`
+
+			pruned := PrunedPaths([]byte(specJSON), []byte(lastAppliedJSON), []byte(desiredJSON))
+			Expect(pruned).Should(Equal([]string{"greetings.second", "name"}))
+		})
+
+		It("Should prune the nested fields of a removed map which weren't added by others", func() {
+			pruned := PrunedPaths([]byte(`{"resources":{"cpu":"1","memory":"1Gi"}}`), []byte(`{"resources":{"cpu":"1"}}`), nil)
+			Expect(pruned).Should(Equal([]string{"resources.cpu"}))
+		})
+
+		It("Should not prune without last applied configuration", func() {
+			Expect(PrunedPaths([]byte(`{"name":"Jane"}`), nil, nil)).Should(BeEmpty())
+		})
+	})
+
+	Context("Strip the protected fields", func() {
+		It("Should remove the fields at the paths and the nested maps left empty", func() {
+			specJSON := `{"replicas":3,"storage":{"size":"10Gi"},"resources":{"cpu":"1","memory":"1Gi"},"name":"Jane"}`
+			resultJSON := `{"name":"Jane","resources":{"cpu":"1"}}`
+
+			strippedJSON, err := json.Marshal(StripCR([]byte(specJSON), []string{"replicas", "storage.size", "resources.memory"}))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(strippedJSON).Should(Equal([]byte(resultJSON)))
+		})
+
+		It("Should skip the paths missing from the spec", func() {
+			strippedJSON, err := json.Marshal(StripCR([]byte(`{"name":"Jane","age":13}`), []string{"greetings.first", "name.first"}))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(strippedJSON).Should(Equal([]byte(`{"age":13,"name":"Jane"}`)))
+		})
+	})
+
+	Context("Detect the drifted fields", func() {
+		It("Should keep the changes of the fields whose configuration is unchanged", func() {
+			specJSON := `{"greetings":{"first":"hi","second":"hello"},"name":"John","age":13}`
+			lastAppliedJSON := `{"greetings":{"first":"hey","second":"hello","third":"hola"},"name":"Jane","age":12}`
+			desiredJSON := `{"greetings":{"first":"hey","second":"hello","third":"hola"},"name":"Jim","age":12}`
+			resultJSON := `{"age":13,"greetings":{"first":"hi","second":"hello"},"name":"Jim"}`
+
+			drifted, kept := Drift([]byte(specJSON), []byte(lastAppliedJSON), []byte(desiredJSON))
+			Expect(drifted).Should(Equal([]string{"age", "greetings.first", "greetings.third"}))
+
+			keptJSON, err := json.Marshal(kept)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keptJSON).Should(Equal([]byte(resultJSON)))
+		})
+
+		It("Should not detect a drift without last applied configuration", func() {
+			drifted, kept := Drift([]byte(`{"name":"John"}`), nil, []byte(`{"name":"Jane"}`))
+			Expect(drifted).Should(BeEmpty())
+			Expect(kept).Should(Equal(map[string]interface{}{"name": "Jane"}))
+		})
+	})
+})
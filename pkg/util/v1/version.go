@@ -0,0 +1,409 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver/v4"
+)
+
+// channelVersion matches the version at the end of a channel name, like 3.23.1 in stable-v3.23.1,
+// with its prerelease and build metadata
+var channelVersion = regexp.MustCompile(`(?:^|[^0-9A-Za-z])v?([0-9]+(?:\.[0-9]+)*)(?:-([0-9A-Za-z.-]+))?(?:\+([0-9A-Za-z.-]+))?$`)
+
+// ParseVersion returns the semantic version of a channel or a version, like v3, v3.20, stable-v3.23.1 or 4.0.0-beta.1+build.5.
+// The missing minor and patch versions are 0. It fails when there is no version, or a version with more than 3 segments.
+func ParseVersion(name string) (semver.Version, error) {
+	m := channelVersion.FindStringSubmatch(name)
+	if m == nil {
+		return semver.Version{}, fmt.Errorf("%q has no version", name)
+	}
+	segments := strings.Split(m[1], ".")
+	if len(segments) > 3 {
+		return semver.Version{}, fmt.Errorf("the version of %q has more than 3 segments", name)
+	}
+	var core [3]uint64
+	for i, segment := range segments {
+		n, err := strconv.ParseUint(segment, 10, 64)
+		if err != nil {
+			return semver.Version{}, fmt.Errorf("the version of %q is invalid: %v", name, err)
+		}
+		core[i] = n
+	}
+	v := semver.Version{Major: core[0], Minor: core[1], Patch: core[2]}
+	if m[2] != "" {
+		for _, id := range strings.Split(m[2], ".") {
+			pre, err := semver.NewPRVersion(id)
+			if err != nil {
+				return semver.Version{}, fmt.Errorf("the prerelease of %q is invalid: %v", name, err)
+			}
+			v.Pre = append(v.Pre, pre)
+		}
+	}
+	if m[3] != "" {
+		for _, id := range strings.Split(m[3], ".") {
+			build, err := semver.NewBuildVersion(id)
+			if err != nil {
+				return semver.Version{}, fmt.Errorf("the build metadata of %q is invalid: %v", name, err)
+			}
+			v.Build = append(v.Build, build)
+		}
+	}
+	return v, nil
+}
+
+// Compare returns -1, 0 or 1 when the version of the channel or the version v1 is lower than, equal to or higher
+// than the one of v2. A prerelease is lower than its release, and the build metadata is ignored.
+func Compare(v1, v2 string) (int, error) {
+	version1, err := ParseVersion(v1)
+	if err != nil {
+		return 0, err
+	}
+	version2, err := ParseVersion(v2)
+	if err != nil {
+		return 0, err
+	}
+	return version1.Compare(version2), nil
+}
+
+// CompareChannelVersion returns true if the channel v1 has a larger version than the channel v2.
+//
+// Deprecated: use Compare, which also returns when the versions are equal.
+func CompareChannelVersion(v1, v2 string) (v1IsLarger bool, err error) {
+	c, err := Compare(v1, v2)
+	return c > 0, err
+}
+
+// Max returns the channel or the version with the highest version, the first one when several have it.
+// It is empty when there is none.
+func Max(versions ...string) (string, error) {
+	var highest string
+	var highestVersion semver.Version
+	for _, name := range versions {
+		v, err := ParseVersion(name)
+		if err != nil {
+			return "", err
+		}
+		if highest == "" || v.GT(highestVersion) {
+			highest, highestVersion = name, v
+		}
+	}
+	return highest, nil
+}
+
+// Satisfies returns true if the version of the channel or the version is in the range, like >=3.20.0 <4.0.0.
+func Satisfies(version, constraint string) (bool, error) {
+	v, err := ParseVersion(version)
+	if err != nil {
+		return false, err
+	}
+	r, err := semver.ParseRange(constraint)
+	if err != nil {
+		return false, fmt.Errorf("the range %q is invalid: %v", constraint, err)
+	}
+	return r(v), nil
+}
+
+// The named schemes of the channels.
+const (
+	// ChannelSchemeSemver is the scheme of the channels ending with their version, like v3.20, stable-v3.23.1,
+	// candidate-4.14 or 2023.4. It is the default scheme.
+	ChannelSchemeSemver = "semver"
+	// ChannelSchemeOpenShift is the scheme of the channels named by a risk level and a version, like candidate-4.14,
+	// fast-4.14, stable-4.14 or eus-4.14. They are ordered by version, then from candidate to eus.
+	ChannelSchemeOpenShift = "openshift"
+)
+
+// openShiftChannel matches the channels of the openshift scheme
+var openShiftChannel = regexp.MustCompile(`^(candidate|fast|stable|eus)-(v?[0-9]+(?:\.[0-9]+)*)$`)
+
+// openShiftRisks are the ranks of the risk levels of the openshift scheme
+var openShiftRisks = map[string]int{"candidate": 0, "fast": 1, "stable": 2, "eus": 3}
+
+// ChannelScheme reads the versions of the channels of a package, to order the channels whose names don't follow
+// the vX.Y scheme. A nil ChannelScheme is the semver scheme.
+type ChannelScheme struct {
+	name    string
+	pattern *regexp.Regexp
+	order   map[string]int
+}
+
+// NewChannelScheme returns the scheme of the channels. The name is a named scheme, semver when it is empty.
+// The pattern is a regular expression whose first capture group is the version of the channel, like
+// ^release-([0-9.]+)-lts$, it overrides the version of the named scheme. The order lists the channels
+// from the lowest to the highest, it orders the channels without a version, like alpha, beta and stable,
+// and the channels with the same version.
+func NewChannelScheme(name, pattern string, order []string) (*ChannelScheme, error) {
+	s := &ChannelScheme{name: name, order: make(map[string]int, len(order))}
+	switch name {
+	case "", ChannelSchemeSemver, ChannelSchemeOpenShift:
+	default:
+		return nil, fmt.Errorf("the channel scheme %q is unknown, the schemes are %s and %s", name, ChannelSchemeSemver, ChannelSchemeOpenShift)
+	}
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("the channel pattern %q is invalid: %v", pattern, err)
+		}
+		if re.NumSubexp() < 1 {
+			return nil, fmt.Errorf("the channel pattern %q has no capture group for the version", pattern)
+		}
+		s.pattern = re
+	}
+	for i, channel := range order {
+		s.order[channel] = i
+	}
+	return s, nil
+}
+
+// channelRank is the version and the rank of a channel, the version is nil and hasRank is false when they are missing
+type channelRank struct {
+	version *semver.Version
+	rank    int
+	hasRank bool
+}
+
+// parse returns the version and the rank of the channel
+func (s *ChannelScheme) parse(channel string) channelRank {
+	var c channelRank
+	versionOf := channel
+	if s != nil {
+		if rank, ok := s.order[channel]; ok {
+			c.rank, c.hasRank = rank, true
+		}
+		if s.name == ChannelSchemeOpenShift {
+			m := openShiftChannel.FindStringSubmatch(channel)
+			if m == nil {
+				versionOf = ""
+			} else {
+				versionOf = m[2]
+				if !c.hasRank {
+					c.rank, c.hasRank = openShiftRisks[m[1]], true
+				}
+			}
+		}
+		if s.pattern != nil {
+			m := s.pattern.FindStringSubmatch(channel)
+			if m == nil {
+				versionOf = ""
+			} else {
+				versionOf = m[1]
+			}
+		}
+	}
+	if versionOf != "" {
+		if v, err := ParseVersion(versionOf); err == nil {
+			c.version = &v
+		}
+	}
+	return c
+}
+
+// Version returns the version of the channel read by the scheme, false when the channel has none
+func (s *ChannelScheme) Version(channel string) (semver.Version, bool) {
+	c := s.parse(channel)
+	if c.version == nil {
+		return semver.Version{}, false
+	}
+	return *c.version, true
+}
+
+// Compare returns -1, 0 or 1 when the channel c1 is lower than, equal to or higher than the channel c2.
+// The channels with a version are ordered by version, then by their order, and the channels without a version
+// by their order. It fails when the channels can't be ordered.
+func (s *ChannelScheme) Compare(c1, c2 string) (int, error) {
+	if c1 == c2 {
+		return 0, nil
+	}
+	rank1, rank2 := s.parse(c1), s.parse(c2)
+	if rank1.version != nil && rank2.version != nil {
+		if c := rank1.version.Compare(*rank2.version); c != 0 || !rank1.hasRank || !rank2.hasRank {
+			return c, nil
+		}
+	} else if rank1.version != nil || rank2.version != nil || !rank1.hasRank || !rank2.hasRank {
+		return 0, fmt.Errorf("the channels %q and %q can't be ordered", c1, c2)
+	}
+	switch {
+	case rank1.rank < rank2.rank:
+		return -1, nil
+	case rank1.rank > rank2.rank:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Max returns the highest channel, the first one when several are the highest. It is empty when there is none.
+func (s *ChannelScheme) Max(channels ...string) (string, error) {
+	var highest string
+	for i, channel := range channels {
+		if i == 0 {
+			highest = channel
+			continue
+		}
+		c, err := s.Compare(channel, highest)
+		if err != nil {
+			return "", err
+		}
+		if c > 0 {
+			highest = channel
+		}
+	}
+	return highest, nil
+}
+
+// ChannelHead is the CSV at the head of a channel of a package.
+type ChannelHead struct {
+	Channel string
+	CSV     string
+	Version semver.Version
+}
+
+// IntermediateChannels returns the channels whose head is newer than the installed version and older than
+// the target version, sorted by version. They are the channels to upgrade through when the replacement chain
+// of the target channel skips the installed version. A version at the head of several channels is returned once.
+func IntermediateChannels(heads []ChannelHead, installed, target semver.Version) []ChannelHead {
+	var intermediates []ChannelHead
+	for _, head := range heads {
+		if head.Version.GT(installed) && head.Version.LT(target) {
+			intermediates = append(intermediates, head)
+		}
+	}
+	sort.SliceStable(intermediates, func(i, j int) bool {
+		if c := intermediates[i].Version.Compare(intermediates[j].Version); c != 0 {
+			return c < 0
+		}
+		return intermediates[i].Channel < intermediates[j].Channel
+	})
+
+	var unique []ChannelHead
+	for _, head := range intermediates {
+		if len(unique) != 0 && unique[len(unique)-1].Version.EQ(head.Version) {
+			continue
+		}
+		unique = append(unique, head)
+	}
+	return unique
+}
+
+// MissingUpgradeEdge returns the oldest CSV of a channel when no CSV of the channel upgrades from the installed CSV,
+// so the edge from the installed CSV to it is missing, or an empty string when the channel upgrades from the installed CSV.
+// The entries are the CSVs of the channel, and the olm.skipRange of its head lets it upgrade from any version in the range.
+// ok is false when the channel has no entries, the catalogs of the older OLM versions don't list them.
+func MissingUpgradeEdge(installed string, entries []string, headSkipRange string) (oldest string, ok bool) {
+	if InSkipRange(headSkipRange, installed) {
+		return "", true
+	}
+	if len(entries) == 0 {
+		return "", false
+	}
+	oldest = entries[len(entries)-1]
+	oldestVersion, hasVersion := CSVVersion(oldest)
+	for _, entry := range entries {
+		if entry == installed {
+			return "", true
+		}
+		if v, ok := CSVVersion(entry); ok && (!hasVersion || v.LT(oldestVersion)) {
+			oldest, oldestVersion, hasVersion = entry, v, true
+		}
+	}
+	return oldest, true
+}
+
+// The paths OLM takes to upgrade an operator from a CSV to the next one.
+const (
+	// UpgradePathReplaces is the upgrade to the CSV replacing the installed one.
+	UpgradePathReplaces = "replaces"
+	// UpgradePathSkips is the upgrade to a CSV listing the installed one in its skips.
+	UpgradePathSkips = "skips"
+	// UpgradePathSkipRange is the upgrade to a CSV whose olm.skipRange annotation includes the installed version.
+	UpgradePathSkipRange = "skipRange"
+)
+
+// SkipRangeAnnotation is the annotation of the CSVs with the range of versions they can upgrade from.
+const SkipRangeAnnotation = "olm.skipRange"
+
+// CSVVersion returns the version in the name of a CSV, like 3.19.4 in ibm-iam-operator.v3.19.4.
+func CSVVersion(name string) (semver.Version, bool) {
+	i := strings.Index(name, ".v")
+	if i < 0 {
+		return semver.Version{}, false
+	}
+	v, err := semver.ParseTolerant(name[i+2:])
+	if err != nil {
+		return semver.Version{}, false
+	}
+	return v, true
+}
+
+// InSkipRange returns true if the version of the CSV is in the olm.skipRange.
+func InSkipRange(skipRange, csv string) bool {
+	if skipRange == "" {
+		return false
+	}
+	v, ok := CSVVersion(csv)
+	if !ok {
+		return false
+	}
+	r, err := semver.ParseRange(skipRange)
+	if err != nil {
+		return false
+	}
+	return r(v)
+}
+
+// GetUpgradePath returns the path from the previous CSV to a CSV with the replaces, skips and olm.skipRange.
+// The replaces is preferred, like OLM does. It is empty when the CSV doesn't upgrade from the previous one.
+func GetUpgradePath(replaces string, skips []string, skipRange, previous string) string {
+	if replaces == previous {
+		return UpgradePathReplaces
+	}
+	for _, skip := range skips {
+		if skip == previous {
+			return UpgradePathSkips
+		}
+	}
+	if InSkipRange(skipRange, previous) {
+		return UpgradePathSkipRange
+	}
+	return ""
+}
+
+// PinnedCSV returns the CSV the operator is pinned to, or an empty string when it is pinned to a semver.
+func PinnedCSV(pin string) string {
+	if _, err := semver.ParseTolerant(pin); err == nil {
+		return ""
+	}
+	return pin
+}
+
+// MatchesPin returns true if the CSV is the pinned version, the pin is either the name of the CSV or its semver.
+func MatchesPin(pin, csv string) bool {
+	if pin == csv {
+		return true
+	}
+	p, err := semver.ParseTolerant(pin)
+	if err != nil {
+		return false
+	}
+	v, ok := CSVVersion(csv)
+	return ok && v.Equals(p)
+}
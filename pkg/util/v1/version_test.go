@@ -0,0 +1,230 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1
+
+import (
+	"github.com/blang/semver/v4"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Compare", func() {
+
+	DescribeTable("Should compare the versions of the channels",
+		func(v1, v2 string, result int) {
+			c, err := Compare(v1, v2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c).Should(Equal(result))
+		},
+		Entry("larger major version", "v4.0", "v3.23", 1),
+		Entry("smaller major version", "v3.23", "v4.0", -1),
+		Entry("larger minor version", "v3.23", "v3.22", 1),
+		Entry("multi-digit minor version", "v3.10", "v3.9", 1),
+		Entry("same version", "v3.20", "v3.20", 0),
+		Entry("major version only", "v4", "v3.20", 1),
+		Entry("both major versions only", "v3", "v4", -1),
+		Entry("stable channel", "stable-v1.1", "stable-v1.0", 1),
+		Entry("patch version", "stable-v3.23.1", "v3.23", 1),
+		Entry("version without the v", "3.20", "v3.19", 1),
+		Entry("channel without the v", "v3.20", "release-3.21", -1),
+		Entry("prerelease", "v4.0.0-beta.1", "v4.0.0", -1),
+		Entry("prereleases", "v4.0.0-beta.2", "v4.0.0-beta.10", -1),
+		Entry("build metadata", "v4.0.0+build.5", "v4.0.0", 0),
+	)
+
+	It("Should fail when there is no valid version", func() {
+		for _, v := range []string{"v3.x", "v3.beta", "stable", "", "v1.2.3.4", "v3.2-beta..1"} {
+			_, err := Compare(v, "v3.20")
+			Expect(err).To(HaveOccurred(), v)
+			_, err = Compare("v3.20", v)
+			Expect(err).To(HaveOccurred(), v)
+		}
+	})
+
+	It("Should return the channel with the highest version", func() {
+		Expect(Max("v3.20", "stable-v3.23", "v3.5", "v3.23")).Should(Equal("stable-v3.23"))
+		Expect(Max()).Should(BeEmpty())
+		_, err := Max("v3.20", "stable")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should check the version of the channel is in the range", func() {
+		Expect(Satisfies("v3.23", ">=3.20.0 <4.0.0")).Should(BeTrue())
+		Expect(Satisfies("v4", ">=3.20.0 <4.0.0")).Should(BeFalse())
+		_, err := Satisfies("v3.23", ">=three")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("CompareChannelVersion", func() {
+
+	DescribeTable("Should compare the channel versions",
+		func(v1, v2 string, v1IsLarger bool) {
+			result, err := CompareChannelVersion(v1, v2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).Should(Equal(v1IsLarger))
+		},
+		Entry("larger major version", "v4.0", "v3.23", true),
+		Entry("smaller major version", "v3.23", "v4.0", false),
+		Entry("larger minor version", "v3.23", "v3.22", true),
+		Entry("multi-digit minor version", "v3.10", "v3.9", true),
+		Entry("same version", "v3.20", "v3.20", false),
+		Entry("major version only", "v4", "v3.20", true),
+		Entry("both major versions only", "v3", "v4", false),
+		Entry("stable channel", "stable-v1.1", "stable-v1.0", true),
+	)
+
+	It("Should fail when the version isn't a number", func() {
+		_, err := CompareChannelVersion("v3.x", "v3.20")
+		Expect(err).To(HaveOccurred())
+		_, err = CompareChannelVersion("v3.20", "v3.beta")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ChannelScheme", func() {
+
+	It("Should order the channels by their versions without a scheme", func() {
+		var scheme *ChannelScheme
+		Expect(scheme.Compare("candidate-4.14", "2023.4")).Should(Equal(-1))
+		Expect(scheme.Max("v3.20", "stable-v3.23.1")).Should(Equal("stable-v3.23.1"))
+		_, err := scheme.Compare("stable", "v3.20")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should order the channels of the openshift scheme by version, then by risk", func() {
+		scheme, err := NewChannelScheme(ChannelSchemeOpenShift, "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scheme.Compare("stable-4.14", "candidate-4.15")).Should(Equal(-1))
+		Expect(scheme.Compare("stable-4.14", "fast-4.14")).Should(Equal(1))
+		Expect(scheme.Max("candidate-4.14", "eus-4.14", "fast-4.14")).Should(Equal("eus-4.14"))
+		_, err = scheme.Compare("preview-4.14", "stable-4.14")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should read the versions with the pattern and order the other channels", func() {
+		scheme, err := NewChannelScheme("", `^release-([0-9.]+)-lts$`, []string{"alpha", "beta", "stable"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scheme.Compare("release-2.10-lts", "release-2.9-lts")).Should(Equal(1))
+		Expect(scheme.Compare("beta", "stable")).Should(Equal(-1))
+		Expect(scheme.Max("beta", "stable", "alpha")).Should(Equal("stable"))
+		// The channels not matching the pattern have no version
+		_, err = scheme.Compare("v3.20", "release-2.9-lts")
+		Expect(err).To(HaveOccurred())
+		_, err = scheme.Compare("stable", "release-2.9-lts")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should reject the invalid schemes", func() {
+		_, err := NewChannelScheme("calendar", "", nil)
+		Expect(err).To(HaveOccurred())
+		_, err = NewChannelScheme("", "^release-[0-9.]+$", nil)
+		Expect(err).To(HaveOccurred())
+		_, err = NewChannelScheme("", "^release-([0-9.]+$", nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("IntermediateChannels", func() {
+
+	head := func(channel, version string) ChannelHead {
+		return ChannelHead{Channel: channel, CSV: "ibm-iam-operator.v" + version, Version: semver.MustParse(version)}
+	}
+
+	It("Should return the channels between the installed and the target versions by version", func() {
+		heads := []ChannelHead{
+			head("v4.0", "4.0.2"),
+			head("v3.23", "3.23.5"),
+			head("stable-v3", "3.23.5"),
+			head("v3.5", "3.5.1"),
+			head("v3.20", "3.20.1"),
+			head("v3.3", "3.3.0"),
+		}
+		intermediates := IntermediateChannels(heads, semver.MustParse("3.5.1"), semver.MustParse("4.0.2"))
+		Expect(intermediates).To(Equal([]ChannelHead{
+			head("v3.20", "3.20.1"),
+			head("stable-v3", "3.23.5"),
+		}))
+	})
+
+	It("Should return no channel when the versions are adjacent", func() {
+		heads := []ChannelHead{head("v3.5", "3.5.1"), head("v4.0", "4.0.2")}
+		Expect(IntermediateChannels(heads, semver.MustParse("3.5.1"), semver.MustParse("4.0.2"))).To(BeEmpty())
+	})
+})
+
+var _ = Describe("MissingUpgradeEdge", func() {
+
+	entries := []string{"ibm-iam-operator.v3.23.5", "ibm-iam-operator.v3.23.0", "ibm-iam-operator.v3.22.1"}
+
+	DescribeTable("Should return the oldest CSV of the channel when it doesn't upgrade from the installed CSV",
+		func(installed string, entries []string, skipRange, oldest string, ok bool) {
+			missing, verified := MissingUpgradeEdge(installed, entries, skipRange)
+			Expect(missing).To(Equal(oldest))
+			Expect(verified).To(Equal(ok))
+		},
+		Entry("installed CSV in the channel", "ibm-iam-operator.v3.23.0", entries, "", "", true),
+		Entry("installed CSV in the skipRange", "ibm-iam-operator.v3.5.1", entries, ">=3.0.0 <3.23.5", "", true),
+		Entry("installed CSV out of the channel", "ibm-iam-operator.v3.5.1", entries, "", "ibm-iam-operator.v3.22.1", true),
+		Entry("unsorted entries", "ibm-iam-operator.v3.5.1", []string{"ibm-iam-operator.v3.22.1", "ibm-iam-operator.v3.23.5"}, "", "ibm-iam-operator.v3.22.1", true),
+		Entry("no entries", "ibm-iam-operator.v3.5.1", nil, "", "", false),
+	)
+})
+
+var _ = Describe("GetUpgradePath", func() {
+
+	It("Should parse the version in the name of a CSV", func() {
+		v, ok := CSVVersion("ibm-iam-operator.v3.19.4")
+		Expect(ok).To(BeTrue())
+		Expect(v.String()).To(Equal("3.19.4"))
+
+		_, ok = CSVVersion("ibm-iam-operator")
+		Expect(ok).To(BeFalse())
+	})
+
+	DescribeTable("Should return the path from the previous CSV",
+		func(replaces string, skips []string, skipRange, previous, path string) {
+			Expect(GetUpgradePath(replaces, skips, skipRange, previous)).To(Equal(path))
+		},
+		Entry("replaces", "etcd.v3.19.4", nil, ">=3.0.0 <3.20.0", "etcd.v3.19.4", UpgradePathReplaces),
+		Entry("skips", "etcd.v3.19.5", []string{"etcd.v3.19.4"}, "", "etcd.v3.19.4", UpgradePathSkips),
+		Entry("skipRange", "etcd.v3.19.5", nil, ">=3.0.0 <3.20.0", "etcd.v3.18.0", UpgradePathSkipRange),
+		Entry("out of the skipRange", "etcd.v3.19.5", nil, ">=3.19.0 <3.20.0", "etcd.v3.18.0", ""),
+		Entry("malformed skipRange", "etcd.v3.19.5", nil, "3.x.y", "etcd.v3.18.0", ""),
+	)
+})
+
+var _ = Describe("MatchesPin", func() {
+
+	It("Should return the pinned CSV", func() {
+		Expect(PinnedCSV("ibm-etcd-operator.v0.9.4")).To(Equal("ibm-etcd-operator.v0.9.4"))
+		Expect(PinnedCSV("v0.9.4")).To(BeEmpty())
+	})
+
+	DescribeTable("Should match the CSV with the pin",
+		func(pin, csv string, matches bool) {
+			Expect(MatchesPin(pin, csv)).To(Equal(matches))
+		},
+		Entry("CSV", "ibm-etcd-operator.v0.9.4", "ibm-etcd-operator.v0.9.4", true),
+		Entry("other CSV", "ibm-etcd-operator.v0.9.4", "ibm-etcd-operator.v0.9.5", false),
+		Entry("semver", "0.9.4", "ibm-etcd-operator.v0.9.4", true),
+		Entry("semver with v", "v0.9.4", "ibm-etcd-operator.v0.9.4", true),
+		Entry("other semver", "0.9.4", "ibm-etcd-operator.v0.10.0", false),
+		Entry("CSV without version", "0.9.4", "ibm-etcd-operator", false),
+	)
+})